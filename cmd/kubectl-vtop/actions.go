@@ -0,0 +1,349 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlupgrade"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/shardaction"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+var (
+	reparentTo       string
+	rolloutComponent string
+)
+
+func init() {
+	commands["reparent"] = &command{
+		usage: "reparent <cluster> <keyspace>/<shard>",
+		help:  "Move the primary of a shard to another tablet with a planned reparent.",
+		run:   runReparent,
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&reparentTo, "to", "", "alias of the tablet to make primary (by default, the operator picks one)")
+		},
+	}
+	commands["backup"] = &command{
+		usage: "backup <cluster> <keyspace>/<shard> [<tablet>]",
+		help:  "Take a backup of a shard now, optionally from a specific tablet.",
+		run:   runBackup,
+	}
+	commands["debug"] = &command{
+		usage: "debug <cluster> <keyspace>/<shard> <tablet>",
+		help:  "Add an ephemeral debug container to a tablet's Pod.",
+		run:   runDebug,
+	}
+	commands["rollout"] = &command{
+		usage: "rollout release|pause|resume|hold <cluster> [<keyspace>/<shard>]",
+		help:  "Release scheduled changes, or pause and resume rolling restarts of tablets.",
+		run:   runRollout,
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&rolloutComponent, "component", "", "with release or hold, keep releasing changes to only this component (vtgate, vttablet, or etcd) until it's held again")
+		},
+	}
+	commands["mysql-upgrade"] = &command{
+		usage: "mysql-upgrade abort|resume <cluster> <keyspace>/<shard>",
+		help:  "Abort a MySQL major version upgrade of a shard, rolling back upgraded replicas, or resume it.",
+		run:   runMysqlUpgrade,
+	}
+}
+
+// runReparent requests a drain of the current primary tablet Pod, which the
+// operator handles by performing a planned reparent away from it. With -to,
+// it instead asks the operator to reparent to a specific tablet.
+func runReparent(ctx context.Context, opts *options, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected arguments: <cluster> <keyspace>/<shard>")
+	}
+	vts, err := getShard(ctx, opts, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if reparentTo != "" {
+		return requestAction(ctx, opts, vts, shardaction.ReparentToAnnotation, reparentTo)
+	}
+	if vts.Status.MasterAlias == "" {
+		return fmt.Errorf("shard %v has no primary according to its status (hasMaster: %v)", args[1], vts.Status.HasMaster)
+	}
+	tabletAlias, err := topoproto.ParseTabletAlias(vts.Status.MasterAlias)
+	if err != nil {
+		return err
+	}
+
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: opts.namespace, Name: vttablet.PodName(args[0], tabletAlias)}
+	if err := opts.client.Get(ctx, key, pod); err != nil {
+		return err
+	}
+	if drain.Started(pod) {
+		fmt.Printf("Drain of primary tablet %v (Pod %v) was already requested.\n", vts.Status.MasterAlias, pod.Name)
+		return nil
+	}
+
+	if err := patchAnnotations(ctx, opts, pod, func() { drain.Start(pod, "requested by kubectl vtop reparent") }); err != nil {
+		return err
+	}
+	fmt.Printf("Requested planned reparent away from primary tablet %v (Pod %v).\n", vts.Status.MasterAlias, pod.Name)
+	return nil
+}
+
+func runBackup(ctx context.Context, opts *options, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("expected arguments: <cluster> <keyspace>/<shard> [<tablet>]")
+	}
+	vts, err := getShard(ctx, opts, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	tablet := ""
+	if len(args) == 3 {
+		tablet = args[2]
+	}
+	return requestAction(ctx, opts, vts, shardaction.BackupNowAnnotation, tablet)
+}
+
+func runDebug(ctx context.Context, opts *options, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("expected arguments: <cluster> <keyspace>/<shard> <tablet>")
+	}
+	vts, err := getShard(ctx, opts, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return requestAction(ctx, opts, vts, shardaction.DebugTabletAnnotation, args[2])
+}
+
+// requestAction adds a shard action annotation. The operator reports the
+// outcome in the ActionSucceeded condition, which "status" shows.
+func requestAction(ctx context.Context, opts *options, vts *planetscalev2.VitessShard, annotation, value string) error {
+	if err := patchAnnotations(ctx, opts, vts, func() { shardaction.Request(vts, annotation, value) }); err != nil {
+		return err
+	}
+	fmt.Printf("Requested %v=%q on VitessShard %v. Run 'kubectl vtop status' to see the outcome.\n", annotation, value, vts.Name)
+	return nil
+}
+
+func runRollout(ctx context.Context, opts *options, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("expected arguments: release|pause|resume <cluster> [<keyspace>/<shard>]")
+	}
+	action, clusterName := args[0], args[1]
+	if rolloutComponent != "" {
+		if len(args) != 2 {
+			return fmt.Errorf("-component applies to the whole cluster; don't specify a shard")
+		}
+		return runRolloutComponent(ctx, opts, action, clusterName, rolloutComponent)
+	}
+
+	var shards []planetscalev2.VitessShard
+	if len(args) == 3 {
+		vts, err := getShard(ctx, opts, clusterName, args[2])
+		if err != nil {
+			return err
+		}
+		shards = append(shards, *vts)
+	} else {
+		var err error
+		if shards, err = listShards(ctx, opts, clusterName, "", ""); err != nil {
+			return err
+		}
+	}
+
+	switch action {
+	case "release":
+		if len(args) == 2 {
+			if err := releaseParents(ctx, opts, clusterName); err != nil {
+				return err
+			}
+		}
+		for i := range shards {
+			vts := &shards[i]
+			if err := releaseObject(ctx, opts, vts, "VitessShard "+vts.Name); err != nil {
+				return err
+			}
+			if err := cascadeShard(ctx, opts, vts); err != nil {
+				return err
+			}
+		}
+	case "resume":
+		for i := range shards {
+			if err := cascadeShard(ctx, opts, &shards[i]); err != nil {
+				return err
+			}
+		}
+	case "pause":
+		// Removing the cascade annotation stops the shard controller from
+		// releasing any more tablet Pods. A Pod that was already released
+		// will still finish its restart.
+		for i := range shards {
+			vts := &shards[i]
+			if !rollout.Cascading(vts) {
+				continue
+			}
+			if err := patchAnnotations(ctx, opts, vts, func() { rollout.Uncascade(vts) }); err != nil {
+				return err
+			}
+			fmt.Printf("Paused rolling restart of VitessShard %v.\n", vts.Name)
+		}
+	default:
+		return fmt.Errorf("unknown rollout action %q; expected release, pause, or resume (or hold, with -component)", action)
+	}
+	return nil
+}
+
+func runMysqlUpgrade(ctx context.Context, opts *options, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("expected arguments: abort|resume <cluster> <keyspace>/<shard>")
+	}
+	vts, err := getShard(ctx, opts, args[1], args[2])
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "abort":
+		if vts.Status.MysqlUpgrade == nil {
+			return fmt.Errorf("VitessShard %v has no MySQL upgrade in progress", vts.Name)
+		}
+		if err := patchAnnotations(ctx, opts, vts, func() { mysqlupgrade.Abort(vts, "requested by kubectl vtop mysql-upgrade abort") }); err != nil {
+			return err
+		}
+		fmt.Printf("Requested abort of the MySQL upgrade of VitessShard %v. Run 'kubectl vtop status' to follow the rollback.\n", vts.Name)
+	case "resume":
+		if !mysqlupgrade.AbortRequested(vts) {
+			fmt.Printf("MySQL upgrade of VitessShard %v was not aborted.\n", vts.Name)
+			return nil
+		}
+		if err := patchAnnotations(ctx, opts, vts, func() { mysqlupgrade.Resume(vts) }); err != nil {
+			return err
+		}
+		fmt.Printf("Resumed the MySQL upgrade of VitessShard %v.\n", vts.Name)
+	default:
+		return fmt.Errorf("unknown mysql-upgrade action %q; expected abort or resume", args[0])
+	}
+	return nil
+}
+
+// runRolloutComponent releases or holds changes to one component throughout
+// the cluster, by updating the released components annotation.
+func runRolloutComponent(ctx context.Context, opts *options, action, clusterName, component string) error {
+	switch component {
+	case planetscalev2.VtgateComponentName, planetscalev2.VttabletComponentName, planetscalev2.EtcdComponentName:
+	default:
+		return fmt.Errorf("unknown component %q; expected %v, %v, or %v", component,
+			planetscalev2.VtgateComponentName, planetscalev2.VttabletComponentName, planetscalev2.EtcdComponentName)
+	}
+
+	vt := &planetscalev2.VitessCluster{}
+	if err := opts.client.Get(ctx, client.ObjectKey{Namespace: opts.namespace, Name: clusterName}, vt); err != nil {
+		return err
+	}
+	switch action {
+	case "release":
+		if err := patchAnnotations(ctx, opts, vt, func() { rollout.ReleaseComponent(vt, component) }); err != nil {
+			return err
+		}
+		fmt.Printf("Released changes to %v in VitessCluster %v. Run 'kubectl vtop rollout hold %v -component %v' to stop releasing them.\n", component, vt.Name, vt.Name, component)
+	case "hold":
+		if err := patchAnnotations(ctx, opts, vt, func() { rollout.HoldComponent(vt, component) }); err != nil {
+			return err
+		}
+		fmt.Printf("Holding new changes to %v in VitessCluster %v.\n", component, vt.Name)
+	default:
+		return fmt.Errorf("-component only applies to release and hold")
+	}
+	return nil
+}
+
+// releaseParents releases scheduled changes to the cells and keyspaces
+// of a cluster.
+func releaseParents(ctx context.Context, opts *options, clusterName string) error {
+	labels := client.MatchingLabels{planetscalev2.ClusterLabel: clusterName}
+
+	cells := &planetscalev2.VitessCellList{}
+	if err := opts.client.List(ctx, cells, client.InNamespace(opts.namespace), labels); err != nil {
+		return err
+	}
+	for i := range cells.Items {
+		if err := releaseObject(ctx, opts, &cells.Items[i], "VitessCell "+cells.Items[i].Name); err != nil {
+			return err
+		}
+	}
+
+	keyspaces := &planetscalev2.VitessKeyspaceList{}
+	if err := opts.client.List(ctx, keyspaces, client.InNamespace(opts.namespace), labels); err != nil {
+		return err
+	}
+	for i := range keyspaces.Items {
+		if err := releaseObject(ctx, opts, &keyspaces.Items[i], "VitessKeyspace "+keyspaces.Items[i].Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseObject marks an object as released if it has scheduled changes.
+func releaseObject(ctx context.Context, opts *options, obj client.Object, desc string) error {
+	if !rollout.Scheduled(obj) || rollout.Released(obj) {
+		return nil
+	}
+	if err := patchAnnotations(ctx, opts, obj, func() { rollout.Release(obj) }); err != nil {
+		return err
+	}
+	fmt.Printf("Released scheduled changes to %v.\n", desc)
+	return nil
+}
+
+// cascadeShard starts a rolling restart of the shard's tablets if any of them
+// have pending changes.
+func cascadeShard(ctx context.Context, opts *options, vts *planetscalev2.VitessShard) error {
+	if rollout.Cascading(vts) {
+		return nil
+	}
+	pending := false
+	for _, tablet := range vts.Status.Tablets {
+		if tablet.PendingChanges != "" {
+			pending = true
+			break
+		}
+	}
+	if !pending {
+		return nil
+	}
+	if err := patchAnnotations(ctx, opts, vts, func() { rollout.Cascade(vts) }); err != nil {
+		return err
+	}
+	fmt.Printf("Started rolling restart of VitessShard %v.\n", vts.Name)
+	return nil
+}
+
+// patchAnnotations sends the annotation changes made by mutate as a merge
+// patch, so it doesn't conflict with the operator's concurrent updates.
+func patchAnnotations(ctx context.Context, opts *options, obj client.Object, mutate func()) error {
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	mutate()
+	return opts.client.Patch(ctx, obj, patch)
+}
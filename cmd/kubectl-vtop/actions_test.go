@@ -0,0 +1,74 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/shardaction"
+)
+
+func TestRequestAction(t *testing.T) {
+	opts := testOptions(t, testShard("example", "commerce", "-", "", ""))
+	ctx := context.Background()
+
+	if err := runBackup(ctx, opts, []string{"example", "commerce/-", "zone1-0000000101"}); err != nil {
+		t.Fatalf("runBackup() error: %v", err)
+	}
+
+	vts := &planetscalev2.VitessShard{}
+	if err := opts.client.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "example-commerce--"}, vts); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if got, want := vts.Annotations[shardaction.BackupNowAnnotation], "zone1-0000000101"; got != want {
+		t.Errorf("%v = %q; want %q", shardaction.BackupNowAnnotation, got, want)
+	}
+}
+
+func TestCascadeShard(t *testing.T) {
+	vts := testShard("example", "commerce", "-", "", "")
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-0000000100": {},
+	}
+	opts := testOptions(t, vts)
+	ctx := context.Background()
+
+	// Nothing to restart without pending changes.
+	if err := cascadeShard(ctx, opts, vts); err != nil {
+		t.Fatalf("cascadeShard() error: %v", err)
+	}
+	if rollout.Cascading(vts) {
+		t.Errorf("cascadeShard() started a rolling restart without pending changes")
+	}
+
+	vts.Status.Tablets["zone1-0000000100"] = planetscalev2.VitessTabletStatus{PendingChanges: "image"}
+	if err := cascadeShard(ctx, opts, vts); err != nil {
+		t.Fatalf("cascadeShard() error: %v", err)
+	}
+	got := &planetscalev2.VitessShard{}
+	if err := opts.client.Get(ctx, client.ObjectKeyFromObject(vts), got); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if !rollout.Cascading(got) {
+		t.Errorf("cascadeShard() didn't start a rolling restart with pending changes")
+	}
+}
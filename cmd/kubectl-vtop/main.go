@@ -0,0 +1,168 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Command kubectl-vtop performs day-2 operations on Vitess clusters managed by
+the operator, by reading and annotating the objects the operator already
+watches.
+
+When installed on the PATH, it can be invoked as a kubectl plugin:
+
+	kubectl vtop topology my-cluster
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscaleapis "planetscale.dev/vitess-operator/pkg/apis"
+)
+
+// command is a vtop subcommand.
+type command struct {
+	usage string
+	help  string
+	run   func(ctx context.Context, opts *options, args []string) error
+	// flags registers any flags specific to the subcommand.
+	flags func(fs *flag.FlagSet)
+}
+
+var commands = map[string]*command{}
+
+// options are the flags shared by all subcommands.
+type options struct {
+	namespace  string
+	kubeconfig string
+	context    string
+
+	client client.Client
+}
+
+func (o *options) addFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.namespace, "n", "", "namespace of the VitessCluster (defaults to the kubeconfig context namespace)")
+	fs.StringVar(&o.namespace, "namespace", "", "namespace of the VitessCluster (defaults to the kubeconfig context namespace)")
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config)")
+	fs.StringVar(&o.context, "context", "", "name of the kubeconfig context to use")
+}
+
+// connect fills in the client and the default namespace.
+func (o *options) connect() error {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: o.context})
+
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("can't load kubeconfig: %v", err)
+	}
+	if o.namespace == "" {
+		o.namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return fmt.Errorf("can't determine namespace: %v", err)
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := kubernetesscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := planetscaleapis.AddToScheme(scheme); err != nil {
+		return err
+	}
+	o.client, err = client.New(cfg, client.Options{Scheme: scheme})
+	return err
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: kubectl vtop <command> [flags] [args]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(os.Stderr, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", commands[name].usage, commands[name].help)
+	}
+	w.Flush()
+	fmt.Fprintf(os.Stderr, "\nRun 'kubectl vtop <command> -h' for the flags of a command.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	cmd, ok := commands[name]
+	if !ok {
+		if name != "-h" && name != "--help" && name != "help" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		}
+		usage()
+		os.Exit(2)
+	}
+
+	opts := &options{}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: kubectl vtop %s\n\n%s\n\nFlags:\n", cmd.usage, cmd.help)
+		fs.PrintDefaults()
+	}
+	opts.addFlags(fs)
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	args := parseInterleaved(fs, os.Args[2:])
+
+	if err := opts.connect(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := cmd.run(context.Background(), opts, args); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseInterleaved parses flags that may appear before, between, or after
+// positional arguments, as kubectl users expect (e.g. "status my-cluster -w").
+// It returns the positional arguments.
+func parseInterleaved(fs *flag.FlagSet, arguments []string) []string {
+	var args []string
+	for {
+		fs.Parse(arguments)
+		arguments = fs.Args()
+		if len(arguments) == 0 {
+			return args
+		}
+		args = append(args, arguments[0])
+		arguments = arguments[1:]
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+var (
+	showTablets   bool
+	watchStatus   bool
+	watchInterval time.Duration
+)
+
+func init() {
+	commands["topology"] = &command{
+		usage: "topology <cluster>",
+		help:  "Show the cells, keyspaces, shards, and tablets of a cluster.",
+		run:   runTopology,
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&showTablets, "tablets", false, "also list every tablet")
+		},
+	}
+	commands["status"] = &command{
+		usage: "status <cluster> [<keyspace>[/<shard>]]",
+		help:  "Show the status of each shard and its tablets.",
+		run:   runStatus,
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&watchStatus, "w", false, "keep printing the status whenever it changes")
+			fs.DurationVar(&watchInterval, "interval", 5*time.Second, "how often to poll for changes with -w")
+		},
+	}
+}
+
+func runTopology(ctx context.Context, opts *options, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly one argument: <cluster>")
+	}
+	vt := &planetscalev2.VitessCluster{}
+	if err := opts.client.Get(ctx, client.ObjectKey{Namespace: opts.namespace, Name: args[0]}, vt); err != nil {
+		return err
+	}
+	shards, err := listShards(ctx, opts, vt.Name, "", "")
+	if err != nil {
+		return err
+	}
+
+	summary := &vt.Status.Summary
+	fmt.Printf("Cluster %s/%s\n", vt.Namespace, vt.Name)
+	fmt.Printf("  cells serving: %v/%v, shards with primary: %v/%v, tablets ready: %v/%v, pending changes: %v\n",
+		summary.ServingCells, summary.DesiredCells,
+		summary.ShardsWithPrimary, summary.ShardsWithPrimary+summary.ShardsWithoutPrimary,
+		summary.ReadyTablets, summary.DesiredTablets,
+		summary.PendingChanges)
+	if vt.Status.GatewayServiceName != "" {
+		fmt.Printf("  gateway service: %s\n", vt.Status.GatewayServiceName)
+	}
+	for _, component := range sortedKeys(vt.Status.Rollout) {
+		ro := vt.Status.Rollout[component]
+		fmt.Printf("  %s rollout: %v objects pending, released: %v\n", component, ro.PendingObjects, ro.Released)
+	}
+	if upgrade := vt.Status.Upgrade; upgrade != nil && upgrade.Stage != "" {
+		fmt.Printf("  upgrading %s: %s\n", upgrade.Stage, upgrade.Message)
+	}
+	for _, skew := range vt.Status.VersionSkew {
+		name := skew.Keyspace
+		if skew.Shard != "" {
+			name += "/" + skew.Shard
+		}
+		fmt.Printf("  version skew: %s runs %s\n", name, describeImages(&skew.Images))
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CELL\tGATEWAY AVAILABLE\tPENDING CHANGES")
+	for _, name := range sortedKeys(vt.Status.Cells) {
+		cell := vt.Status.Cells[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, cell.GatewayAvailable, cell.PendingChanges)
+	}
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KEYSPACE\tSHARDS READY\tTABLETS READY\tPENDING CHANGES")
+	for _, name := range sortedKeys(vt.Status.Keyspaces) {
+		ks := vt.Status.Keyspaces[name]
+		fmt.Fprintf(w, "%s\t%v/%v\t%v/%v\t%s\n", name, ks.ReadyShards, ks.DesiredShards, ks.ReadyTablets, ks.DesiredTablets, ks.PendingChanges)
+	}
+	w.Flush()
+	fmt.Println()
+
+	writeShards(os.Stdout, shards, showTablets)
+	return nil
+}
+
+func runStatus(ctx context.Context, opts *options, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("expected arguments: <cluster> [<keyspace>[/<shard>]]")
+	}
+	var keyspace, shard string
+	if len(args) == 2 {
+		parts := strings.SplitN(args[1], "/", 2)
+		keyspace = parts[0]
+		if len(parts) == 2 {
+			shard = parts[1]
+		}
+	}
+
+	var last []byte
+	for {
+		shards, err := listShards(ctx, opts, args[0], keyspace, shard)
+		if err != nil {
+			return err
+		}
+		if len(shards) == 0 {
+			return fmt.Errorf("no shards found for cluster %q matching %q", args[0], strings.Join(args[1:], ""))
+		}
+		buf := &bytes.Buffer{}
+		writeShards(buf, shards, true)
+
+		// When watching, only print again once something has changed.
+		if !bytes.Equal(buf.Bytes(), last) {
+			if watchStatus {
+				fmt.Printf("--- %s\n", time.Now().Format(time.RFC3339))
+			}
+			os.Stdout.Write(buf.Bytes())
+			last = buf.Bytes()
+		}
+		if !watchStatus {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// listShards returns the VitessShards of a cluster, optionally filtered by
+// keyspace and shard name, sorted by keyspace and then by shard.
+func listShards(ctx context.Context, opts *options, clusterName, keyspace, shard string) ([]planetscalev2.VitessShard, error) {
+	labels := client.MatchingLabels{planetscalev2.ClusterLabel: clusterName}
+	if keyspace != "" {
+		labels[planetscalev2.KeyspaceLabel] = keyspace
+	}
+	list := &planetscalev2.VitessShardList{}
+	if err := opts.client.List(ctx, list, client.InNamespace(opts.namespace), labels); err != nil {
+		return nil, err
+	}
+
+	shards := make([]planetscalev2.VitessShard, 0, len(list.Items))
+	for i := range list.Items {
+		if shard != "" && list.Items[i].Spec.Name != shard {
+			continue
+		}
+		shards = append(shards, list.Items[i])
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		ki, kj := shards[i].Labels[planetscalev2.KeyspaceLabel], shards[j].Labels[planetscalev2.KeyspaceLabel]
+		if ki != kj {
+			return ki < kj
+		}
+		return shards[i].Spec.KeyRange.String() < shards[j].Spec.KeyRange.String()
+	})
+	return shards, nil
+}
+
+// getShard returns the single VitessShard for <keyspace>/<shard>.
+func getShard(ctx context.Context, opts *options, clusterName, keyspaceShard string) (*planetscalev2.VitessShard, error) {
+	parts := strings.SplitN(keyspaceShard, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid shard %q; expected <keyspace>/<shard>", keyspaceShard)
+	}
+	shards, err := listShards(ctx, opts, clusterName, parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) != 1 {
+		return nil, fmt.Errorf("found %v shards for %q in cluster %q; expected 1", len(shards), keyspaceShard, clusterName)
+	}
+	return &shards[0], nil
+}
+
+func writeShards(out io.Writer, shards []planetscalev2.VitessShard, tablets bool) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KEYSPACE\tSHARD\tPRIMARY\tSERVING WRITES\tTABLETS READY\tLATEST BACKUP\tPENDING CHANGES")
+	for i := range shards {
+		vts := &shards[i]
+		ready, pending := 0, 0
+		for _, tablet := range vts.Status.Tablets {
+			if tablet.Ready == corev1.ConditionTrue {
+				ready++
+			}
+			if tablet.PendingChanges != "" {
+				pending++
+			}
+		}
+		primary := vts.Status.MasterAlias
+		if primary == "" {
+			primary = "<none>"
+		}
+		backup := "<none>"
+		if t := latestBackupTime(vts); !t.IsZero() {
+			backup = t.Format(time.RFC3339)
+		}
+		changes := ""
+		if pending > 0 {
+			changes = fmt.Sprintf("%v tablets", pending)
+		}
+		if rollout.Scheduled(vts) {
+			changes = strings.TrimSpace("scheduled " + changes)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v/%v\t%s\t%s\n",
+			vts.Labels[planetscalev2.KeyspaceLabel], vts.Spec.Name, primary, vts.Status.ServingWrites,
+			ready, len(vts.Status.Tablets), backup, changes)
+	}
+	w.Flush()
+
+	for i := range shards {
+		vts := &shards[i]
+		if upgrade := vts.Status.MysqlUpgrade; upgrade != nil {
+			fmt.Fprintf(out, "\n%s/%s MySQL upgrade to %s (%s, %v/%v tablets): %s\n",
+				vts.Labels[planetscalev2.KeyspaceLabel], vts.Spec.Name, upgrade.ToImage.Image(), upgrade.Phase,
+				len(upgrade.UpgradedTablets), len(vts.Status.Tablets), upgrade.Message)
+		}
+		if reparent := vts.Status.Reparent; reparent != nil {
+			fmt.Fprintf(out, "\n%s/%s planned reparent from %s to %s in progress since %s\n",
+				vts.Labels[planetscalev2.KeyspaceLabel], vts.Spec.Name, reparent.FromTablet, reparent.ToTablet,
+				reparent.StartTime.Format(time.RFC3339))
+		}
+		if reason := vts.Status.RolloutBlocked; reason != "" {
+			fmt.Fprintf(out, "\n%s/%s rolling restart blocked: %s\n",
+				vts.Labels[planetscalev2.KeyspaceLabel], vts.Spec.Name, reason)
+		}
+		if throttler := vts.Status.Throttler; throttler != nil && throttler.Throttled != corev1.ConditionFalse {
+			fmt.Fprintf(out, "\n%s/%s throttler (throttled: %s, value: %s, threshold: %s): %s\n",
+				vts.Labels[planetscalev2.KeyspaceLabel], vts.Spec.Name, throttler.Throttled,
+				throttler.Value, throttler.Threshold, throttler.Message)
+		}
+		if cond, ok := vts.Status.Conditions[planetscalev2.VitessShardActionSucceeded]; ok {
+			fmt.Fprintf(out, "\n%s/%s last action %s: %s (succeeded: %s)\n",
+				vts.Labels[planetscalev2.KeyspaceLabel], vts.Spec.Name, cond.Reason, cond.Message, cond.Status)
+		}
+	}
+
+	if !tablets {
+		return
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KEYSPACE\tSHARD\tTABLET\tPOOL\tTYPE\tRUNNING\tREADY\tPENDING CHANGES")
+	for i := range shards {
+		vts := &shards[i]
+		for _, alias := range vts.Status.TabletAliases() {
+			tablet := vts.Status.Tablets[alias]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				vts.Labels[planetscalev2.KeyspaceLabel], vts.Spec.Name, alias,
+				tablet.PoolType, tablet.Type, tablet.Running, tablet.Ready, tablet.PendingChanges)
+		}
+	}
+	w.Flush()
+}
+
+// latestBackupTime returns the time of the newest complete backup of the
+// shard in any backup location.
+func latestBackupTime(vts *planetscalev2.VitessShard) time.Time {
+	var latest time.Time
+	for _, location := range vts.Status.BackupLocations {
+		if location == nil || location.LatestCompleteBackupTime == nil {
+			continue
+		}
+		if location.LatestCompleteBackupTime.After(latest) {
+			latest = location.LatestCompleteBackupTime.Time
+		}
+	}
+	return latest
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// describeImages lists the images that are set, for version skew reports.
+func describeImages(images *planetscalev2.VitessKeyspaceImages) string {
+	var parts []string
+	for _, image := range []struct{ name, value string }{
+		{"vttablet", images.Vttablet},
+		{"vtorc", images.Vtorc},
+		{"vtbackup", images.Vtbackup},
+		{"mysqld-exporter", images.MysqldExporter},
+		{"init", images.Init},
+		{"debug", images.Debug},
+	} {
+		if image.value != "" {
+			parts = append(parts, image.name+"="+image.value)
+		}
+	}
+	if images.Mysqld != nil {
+		parts = append(parts, "mysqld="+images.Mysqld.Image())
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscaleapis "planetscale.dev/vitess-operator/pkg/apis"
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func testOptions(t *testing.T, objs ...client.Object) *options {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := planetscaleapis.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	return &options{
+		namespace: "ns",
+		client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+	}
+}
+
+func testShard(cluster, keyspace, shard, start, end string) *planetscalev2.VitessShard {
+	return &planetscalev2.VitessShard{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      cluster + "-" + keyspace + "-" + shard,
+			Labels: map[string]string{
+				planetscalev2.ClusterLabel:  cluster,
+				planetscalev2.KeyspaceLabel: keyspace,
+			},
+		},
+		Spec: planetscalev2.VitessShardSpec{
+			Name:     shard,
+			KeyRange: planetscalev2.VitessKeyRange{Start: start, End: end},
+		},
+	}
+}
+
+func TestLatestBackupTime(t *testing.T) {
+	older := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	table := []struct {
+		name      string
+		locations []*planetscalev2.ShardBackupLocationStatus
+		want      time.Time
+	}{
+		{
+			name: "no locations",
+		},
+		{
+			name: "nil location",
+			locations: []*planetscalev2.ShardBackupLocationStatus{
+				nil,
+				{Name: "b", LatestCompleteBackupTime: &older},
+			},
+			want: older.Time,
+		},
+		{
+			name: "no complete backup",
+			locations: []*planetscalev2.ShardBackupLocationStatus{
+				{Name: "a", IncompleteBackups: 1},
+			},
+		},
+		{
+			name: "newest of several",
+			locations: []*planetscalev2.ShardBackupLocationStatus{
+				{Name: "a", LatestCompleteBackupTime: &newer},
+				{Name: "b", LatestCompleteBackupTime: &older},
+			},
+			want: newer.Time,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vts := &planetscalev2.VitessShard{}
+			vts.Status.BackupLocations = test.locations
+			if got := latestBackupTime(vts); !got.Equal(test.want) {
+				t.Errorf("latestBackupTime() = %v; want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestListShards(t *testing.T) {
+	opts := testOptions(t,
+		testShard("example", "commerce", "80-", "80", ""),
+		testShard("example", "commerce", "-80", "", "80"),
+		testShard("example", "customer", "-", "", ""),
+		testShard("other", "commerce", "-", "", ""),
+	)
+	ctx := context.Background()
+
+	shards, err := listShards(ctx, opts, "example", "", "")
+	if err != nil {
+		t.Fatalf("listShards() error: %v", err)
+	}
+	var got []string
+	for i := range shards {
+		got = append(got, shards[i].Labels[planetscalev2.KeyspaceLabel]+"/"+shards[i].Spec.Name)
+	}
+	if want := "commerce/-80 commerce/80- customer/-"; strings.Join(got, " ") != want {
+		t.Errorf("listShards() = %v; want %v", got, want)
+	}
+
+	vts, err := getShard(ctx, opts, "example", "commerce/80-")
+	if err != nil {
+		t.Fatalf("getShard() error: %v", err)
+	}
+	if vts.Name != "example-commerce-80-" {
+		t.Errorf("getShard() = %v; want example-commerce-80-", vts.Name)
+	}
+	if _, err := getShard(ctx, opts, "example", "commerce"); err == nil {
+		t.Errorf("getShard() without a shard name: want error")
+	}
+	if _, err := getShard(ctx, opts, "example", "commerce/c0-"); err == nil {
+		t.Errorf("getShard() of a missing shard: want error")
+	}
+}
+
+func TestWriteShards(t *testing.T) {
+	backupTime := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	vts := testShard("example", "commerce", "-", "", "")
+	vts.Status.MasterAlias = "zone1-0000000100"
+	vts.Status.ServingWrites = corev1.ConditionTrue
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-0000000100": {PoolType: "replica", Type: "master", Running: corev1.ConditionTrue, Ready: corev1.ConditionTrue},
+		"zone1-0000000101": {PoolType: "replica", Type: "replica", Running: corev1.ConditionTrue, Ready: corev1.ConditionFalse, PendingChanges: "image"},
+	}
+	vts.Status.BackupLocations = []*planetscalev2.ShardBackupLocationStatus{
+		nil,
+		{LatestCompleteBackupTime: &backupTime},
+	}
+
+	buf := &bytes.Buffer{}
+	writeShards(buf, []planetscalev2.VitessShard{*vts}, true)
+	out := buf.String()
+
+	for _, want := range []string{
+		"zone1-0000000100",
+		"1/2",
+		backupTime.Format(time.RFC3339),
+		"1 tablets",
+		"zone1-0000000101",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("writeShards() output doesn't contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -32,13 +32,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	"github.com/planetscale/operator-sdk-libs/pkg/k8sutil"
 	"github.com/planetscale/operator-sdk-libs/pkg/leader"
 
 	"planetscale.dev/vitess-operator/pkg/operator/controllermanager"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
 	"planetscale.dev/vitess-operator/pkg/operator/fork"
+	"planetscale.dev/vitess-operator/pkg/operator/health"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 	"planetscale.dev/vitess-operator/version"
 )
 
@@ -53,6 +59,10 @@ var (
 )
 var log = logf.Log.WithName("manager")
 
+// shutdownMargin is how much longer than --shutdown_timeout the manager waits
+// for controllers to stop, so reconciles cancelled at the timeout can return.
+const shutdownMargin = 5 * time.Second
+
 func printVersion() {
 	log.Info(fmt.Sprintf("Operator Version: %s", version.Version))
 	log.Info(fmt.Sprintf("Go Version: %s", goruntime.Version()))
@@ -85,7 +95,8 @@ func main() {
 
 	// Become the leader before proceeding if this is the root process.
 	// Child processes use deterministic Pod names instead of leader election.
-	if forkPath == "" {
+	leaderElection := environment.LeaderElectionConfig()
+	if forkPath == "" && leaderElection.Mode == environment.LeaderForLifeMode {
 		err = leader.Become(ctx, "vitess-operator-lock")
 		if err != nil {
 			log.Error(err, "")
@@ -93,10 +104,37 @@ func main() {
 		}
 	}
 
+	gracefulShutdownTimeout := environment.ShutdownTimeout() + shutdownMargin
 	options := manager.Options{
-		Namespace:          namespace,
-		SyncPeriod:         cacheInvalidateInterval,
-		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Namespace:               namespace,
+		SyncPeriod:              cacheInvalidateInterval,
+		MetricsBindAddress:      fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
+	}
+	if port := environment.HealthProbePort(); port > 0 {
+		options.HealthProbeBindAddress = fmt.Sprintf("%s:%d", metricsHost, port)
+	}
+	if forkPath == "" {
+		switch leaderElection.Mode {
+		case environment.LeaderForLifeMode:
+			// We already became the leader above.
+			if leaderElection.FollowerStatusUpdates {
+				log.Error(fmt.Errorf("--leader_election_follower_status_updates requires --leader_election_mode=%q", environment.LeaseMode), "")
+				os.Exit(1)
+			}
+		case environment.LeaseMode:
+			// The manager only starts controllers once it holds the Lease,
+			// and exits as soon as it loses it.
+			options.LeaderElection = true
+			options.LeaderElectionID = "vitess-operator-leader"
+			options.LeaseDuration = &leaderElection.LeaseDuration
+			options.RenewDeadline = &leaderElection.RenewDeadline
+			options.RetryPeriod = &leaderElection.RetryPeriod
+			options.LeaderElectionReleaseOnCancel = leaderElection.ReleaseOnShutdown
+		default:
+			log.Error(fmt.Errorf("invalid --leader_election_mode %q; expected %q or %q", leaderElection.Mode, environment.LeaderForLifeMode, environment.LeaseMode), "")
+			os.Exit(1)
+		}
 	}
 	// Add support for MultiNamespace set in WATCH_NAMESPACE (e.g ns1,ns2)
 	// Note that this is not intended to be used for excluding namespaces, this is better done via a Predicate
@@ -112,10 +150,66 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Update managed objects with server-side apply, if it was enabled with
+	// --server_side_apply.
+	if environment.ServerSideApply() {
+		if err := reconciler.EnableServerSideApply(cfg); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
+	// Resize tablet Pods in place, unless it was disabled with
+	// --in_place_pod_resize=false or Kubernetes doesn't support it.
+	if environment.InPlacePodResize() {
+		if supported, err := reconciler.EnablePodResize(cfg); err != nil {
+			log.Error(err, "Failed to check for in-place Pod resize, so tablet Pods will be recreated to change their resources")
+		} else if !supported {
+			log.Info("The API server has no resize subresource for Pods, so tablet Pods will be recreated to change their resources")
+		}
+	}
+
+	// Serve the log level, and allow it to be changed without restarting the
+	// operator if --log_level_admin_address is set.
+	if err := logging.AddLevelHandlers(mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Fail the health probes if controllers stop making progress, and serve
+	// the full health report next to the metrics.
+	if err := health.Add(mgr, environment.WedgedThreshold()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Serve the fault injection configuration, if it was compiled in for
+	// end-to-end tests.
+	if err := faults.Register(mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Export traces if an endpoint was set with --otlp_traces_endpoint. Any
+	// buffered spans are flushed when the manager exits.
+	shutdownTracing, err := tracing.Start(ctx, environment.TracingConfig())
+	if err != nil {
+		log.Error(err, "Failed to start tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error(err, "Failed to flush traces")
+		}
+	}()
+
 	log.Info("Starting the manager.")
 
-	// Start the manager
-	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+	// Start the manager. On SIGTERM, it stops starting new reconciles, while
+	// in-flight ones get until --shutdown_timeout to finish.
+	if err := mgr.Start(shutdown.SetupSignalHandler(environment.ShutdownTimeout())); err != nil {
 		log.Error(err, "Manager exited non-zero")
 		os.Exit(1)
 	}
@@ -50,6 +50,10 @@ Before we can add planetscale.com/v3, we'll need to do the following:
 * Write a CRD conversion webhook to translate objects back and forth between v2 and v3.
 */
 
+import (
+	"time"
+)
+
 const (
 	// Mi is the scale factor for Mebi (2**20)
 	Mi = 1 << 20
@@ -79,11 +83,28 @@ const (
 	defaultVtgateCPUMillis   = 500
 	defaultVtgateMemoryBytes = 1 * Gi
 
+	defaultFailoverBufferEnabled                 = true
+	defaultFailoverBufferSize                    = 1000
+	defaultFailoverBufferMaxFailoverDuration     = 10 * time.Second
+	defaultFailoverBufferMinTimeBetweenFailovers = 20 * time.Second
+
 	defaultBackupIntervalHours     = 24
 	defaultBackupMinRetentionHours = 72
 	defaultBackupMinRetentionCount = 1
 	defaultBackupEngine            = VitessBackupEngineBuiltIn
 
+	defaultStandbyRestoreInterval = time.Hour
+
+	defaultResourceRecommendationsWindow          = 7 * 24 * time.Hour
+	defaultResourceRecommendationsHeadroomPercent = 20
+
+	defaultRolloutReadinessTimeout = 10 * time.Minute
+
+	defaultLocalStorageNodeLossTimeout = 5 * time.Minute
+
+	defaultRebuildBrokenFor     = 30 * time.Minute
+	defaultRebuildMaxConcurrent = 1
+
 	// DefaultWebPort is the port for debug status pages and dashboard UIs.
 	DefaultWebPort = 15000
 	// DefaultAPIPort is the port for API endpoint.
@@ -102,10 +123,25 @@ const (
 	// DefaultMysqlPortName is the name for the MySQL port.
 	DefaultMysqlPortName = "mysql"
 
+	// defaultOperatorAppLabel is the "app" label of the operator's Pods in
+	// the standard deployment.
+	defaultOperatorAppLabel = "vitess-operator"
+
 	defaultVitessLiteImage = "vitess/lite:latest"
 
 	DefaultInitCPURequestMillis   = 100
 	DefaultInitMemoryRequestBytes = 32 * (1 << 20) // 32 MiB
+
+	// DefaultQueryServerPoolSize is the default size of the vttablet query pool.
+	DefaultQueryServerPoolSize = 96
+	// DefaultQueryServerStreamPoolSize is the default size of the vttablet stream pool.
+	DefaultQueryServerStreamPoolSize = 96
+	// DefaultQueryServerTransactionCap is the default size of the vttablet transaction pool.
+	DefaultQueryServerTransactionCap = 300
+	// DefaultQueryServerMaxResultSize is the default maximum number of rows returned by a query.
+	DefaultQueryServerMaxResultSize = 100000
+	// DefaultQueryServerQueryTimeout is the default time after which vttablet kills a query.
+	DefaultQueryServerQueryTimeout = 15 * time.Minute
 )
 
 // DefaultImages are a set of images to use when the CRD doesn't specify.
@@ -57,6 +57,9 @@ type EtcdLockserverSpec struct {
 	// label on the Kubernetes Nodes in that AZ.
 	// If the Kubernetes Nodes don't have such a label, leave this empty.
 	Zone string `json:"zone,omitempty"`
+
+	// ResourceDefaults is inherited from the VitessClusterSpec, if any.
+	ResourceDefaults *ResourceDefaultsSpec `json:"resourceDefaults,omitempty"`
 }
 
 // EtcdLockserverTemplate defines the user-configurable settings for an etcd
@@ -207,6 +210,9 @@ type EtcdLockserverStatus struct {
 	Available corev1.ConditionStatus `json:"available,omitempty"`
 	// ClientServiceName is the name of the Service for etcd client connections.
 	ClientServiceName string `json:"clientServiceName,omitempty"`
+	// PendingMembers is the number of members whose Pods have changes
+	// waiting to be rolled out.
+	PendingMembers int32 `json:"pendingMembers,omitempty"`
 }
 
 // NewEtcdLockserverStatus returns a new status with default values.
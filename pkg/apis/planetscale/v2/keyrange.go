@@ -17,6 +17,8 @@ limitations under the License.
 package v2
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
 	"sort"
 )
@@ -35,7 +37,8 @@ func (kr *VitessKeyRange) String() string {
 // Vitess by an empty string).
 //
 // WARNING: DO NOT change the behavior of this function, as that may
-//          cause shards to be deleted.
+//
+//	cause shards to be deleted.
 func (kr *VitessKeyRange) SafeName() string {
 	start, end := kr.Start, kr.End
 	if start == "" {
@@ -73,3 +76,85 @@ func SortKeyRanges(krs []VitessKeyRange) {
 		return false
 	})
 }
+
+// keyRangeBounds are the bounds of a key range as bytes, without trailing
+// zero bytes, since Vitess considers keys that only differ by those equal.
+// For example, "80" and "8000" are the same bound.
+type keyRangeBounds struct {
+	kr         *VitessKeyRange
+	start, end []byte
+}
+
+func newKeyRangeBounds(kr *VitessKeyRange) (*keyRangeBounds, error) {
+	start, err := hex.DecodeString(kr.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := hex.DecodeString(kr.End)
+	if err != nil {
+		return nil, err
+	}
+	return &keyRangeBounds{
+		kr:    kr,
+		start: bytes.TrimRight(start, "\x00"),
+		end:   bytes.TrimRight(end, "\x00"),
+	}, nil
+}
+
+// unbounded returns whether the key range extends to the end of the keyspace.
+func (b *keyRangeBounds) unbounded() bool {
+	return b.kr.End == ""
+}
+
+// KeyRangesProblem returns a description of why a set of key ranges doesn't
+// exactly cover the keyspace, with no gaps or overlaps, or an empty string if
+// it does. Bounds are compared the way Vitess compares them, so "-80" and
+// "8000-" are contiguous.
+func KeyRangesProblem(krs []VitessKeyRange) string {
+	if len(krs) == 0 {
+		return "there are no shards"
+	}
+	bounds := make([]*keyRangeBounds, 0, len(krs))
+	for i := range krs {
+		b, err := newKeyRangeBounds(&krs[i])
+		if err != nil {
+			return fmt.Sprintf("key range %v is invalid: %v", krs[i].String(), err)
+		}
+		bounds = append(bounds, b)
+	}
+	sort.SliceStable(bounds, func(i, j int) bool {
+		if c := bytes.Compare(bounds[i].start, bounds[j].start); c != 0 {
+			return c < 0
+		}
+		// Sort an unbounded end after all others.
+		if bounds[i].unbounded() != bounds[j].unbounded() {
+			return !bounds[i].unbounded()
+		}
+		return bytes.Compare(bounds[i].end, bounds[j].end) < 0
+	})
+
+	var prev *keyRangeBounds
+	for _, b := range bounds {
+		if !b.unbounded() && bytes.Compare(b.start, b.end) >= 0 {
+			return fmt.Sprintf("key range %v is empty", b.kr.String())
+		}
+		if prev == nil {
+			if len(b.start) != 0 {
+				return fmt.Sprintf("no shard covers key range -%v", b.kr.Start)
+			}
+			prev = b
+			continue
+		}
+		if prev.unbounded() || bytes.Compare(b.start, prev.end) < 0 {
+			return fmt.Sprintf("key ranges %v and %v overlap", prev.kr.String(), b.kr.String())
+		}
+		if !bytes.Equal(b.start, prev.end) {
+			return fmt.Sprintf("no shard covers key range %v-%v", prev.kr.End, b.kr.Start)
+		}
+		prev = b
+	}
+	if !prev.unbounded() {
+		return fmt.Sprintf("no shard covers key range %v-", prev.kr.End)
+	}
+	return ""
+}
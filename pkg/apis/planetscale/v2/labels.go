@@ -25,6 +25,8 @@ const (
 	ComponentLabel = LabelPrefix + "/" + "component"
 	// ClusterLabel is the key for identifying the VitessCluster instance to which an object belongs.
 	ClusterLabel = LabelPrefix + "/" + "cluster"
+	// FederationLabel is the key for identifying the VitessClusterFederation from which a VitessCluster was created.
+	FederationLabel = LabelPrefix + "/" + "federation"
 	// CellLabel is the key for identifying the Vitess cell to which an object belongs.
 	CellLabel = LabelPrefix + "/" + "cell"
 	// KeyspaceLabel is the key for identifying the Vitess keyspace to which an object belongs.
@@ -35,6 +37,9 @@ const (
 	TabletUidLabel = LabelPrefix + "/" + "tablet-uid"
 	// TabletTypeLabel is the key for identifying the Vitess target tablet type for a Pod.
 	TabletTypeLabel = LabelPrefix + "/" + "tablet-type"
+	// TabletPoolLabel is the key for identifying the named tablet pool to which a Pod belongs.
+	// Pods in unnamed pools don't have it, since their pools are identified by TabletTypeLabel.
+	TabletPoolLabel = LabelPrefix + "/" + "tablet-pool"
 	// TabletIndexLabel is the key for identifying the index of a Vitess tablet within its pool.
 	TabletIndexLabel = LabelPrefix + "/" + "tablet-index"
 
@@ -54,6 +59,8 @@ const (
 	EtcdComponentName = "etcd"
 	// VBSSubcontrollerComponentName is the ComponentLabel value for the vitessbackupstorage subcontroller.
 	VBSSubcontrollerComponentName = "vbs-subcontroller"
+	// BackupVolumeComponentName is the ComponentLabel value for backup volume claims.
+	BackupVolumeComponentName = "backup-volume"
 
 	// ReplicaTabletPoolName is the TabletPoolLabel value for REPLICA tablets.
 	ReplicaTabletPoolName = "replica"
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+// VitessMySQLUser declares a MySQL user that the operator manages.
+type VitessMySQLUser struct {
+	// Name is the MySQL user name.
+	Name string `json:"name"`
+
+	// Host is the host pattern the user may connect from.
+	//
+	// Default: %
+	// +kubebuilder:default="%"
+	Host string `json:"host,omitempty"`
+
+	// PasswordSecret specifies the user's password. It must be a Kubernetes
+	// Secret, specified with 'name' and 'key'.
+	PasswordSecret SecretSource `json:"passwordSecret"`
+
+	// Grants lists the privileges to grant to the user.
+	Grants []VitessMySQLGrant `json:"grants,omitempty"`
+}
+
+// VitessMySQLGrant grants privileges on some database objects.
+type VitessMySQLGrant struct {
+	// Privileges lists the privileges to grant, like "SELECT" or
+	// "ALL PRIVILEGES".
+	// +kubebuilder:validation:MinItems=1
+	Privileges []string `json:"privileges"`
+
+	// On is the level at which the privileges are granted, like "*.*",
+	// "mydb.*" or "mydb.mytable".
+	//
+	// Default: *.*
+	On string `json:"on,omitempty"`
+}
+
+// HostPattern returns the host pattern of the user, with the default applied.
+func (u *VitessMySQLUser) HostPattern() string {
+	if u.Host == "" {
+		return "%"
+	}
+	return u.Host
+}
+
+// Level returns the level at which the privileges are granted, with the
+// default applied.
+func (g *VitessMySQLGrant) Level() string {
+	if g.On == "" {
+		return "*.*"
+	}
+	return g.On
+}
+
+// VitessInternalCredentialsSpec configures the passwords of the MySQL
+// accounts that Vitess uses internally.
+//
+// Changing a password in the Secret rotates it without downtime: the operator
+// sets the new password on each shard's primary while retaining the old one
+// as a MySQL 8 secondary password, restarts the shard's tablets to pick up the
+// new one, and discards the old one once they're all back.
+type VitessInternalCredentialsSpec struct {
+	// SecretName is the name of a Secret with the passwords, under keys named
+	// after the accounts: "vt_app", "vt_repl" and "vt_filtered". Accounts
+	// without a key keep having no password. The Secret must be in the same
+	// namespace as the VitessCluster.
+	SecretName string `json:"secretName"`
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+//
+// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessAuditRecord records a change that vtop made, such as recreating a
+// Pod, resizing a PVC, reparenting a shard or starting a backup.
+//
+// Records are only written if the operator runs with --audit_log. Unlike
+// Events, which the API server expires after an hour by default, they're
+// kept for --audit_log_retention, so they can be used for compliance and
+// postmortems. Each record carries the cluster, cell, keyspace and shard
+// labels of the object it's about, so records can be listed by those.
+// +kubebuilder:resource:path=vitessauditrecords,shortName=vtaudit
+// +kubebuilder:printcolumn:name="Time",type=date,JSONPath=`.spec.time`
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.object.kind`
+// +kubebuilder:printcolumn:name="Object",type=string,JSONPath=`.spec.object.name`
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.spec.message`,priority=1
+type VitessAuditRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VitessAuditRecordSpec `json:"spec,omitempty"`
+}
+
+// VitessAuditRecordSpec describes a change that vtop made.
+type VitessAuditRecordSpec struct {
+	// Time is when the change was made.
+	Time metav1.Time `json:"time"`
+	// Controller is the name of the vtop controller that made the change.
+	Controller string `json:"controller,omitempty"`
+	// Object is the object on whose behalf the change was made, such as the
+	// VitessShard whose Pod was recreated.
+	Object VitessAuditObjectReference `json:"object"`
+	// Action is a short, machine-readable description of the change, which
+	// matches the reason of the Event reported for it, such as "Deleted" or
+	// "PlannedReparent".
+	Action string `json:"action"`
+	// Message is a human-readable description of the change.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessAuditObjectReference identifies the object an audit record is about.
+type VitessAuditObjectReference struct {
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the object.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	// UID is the UID of the object, which tells apart objects that were
+	// deleted and recreated with the same name.
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessAuditRecordList contains a list of VitessAuditRecords.
+type VitessAuditRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VitessAuditRecord `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VitessAuditRecord{}, &VitessAuditRecordList{})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v2
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -60,6 +61,10 @@ type VitessBackupStatus struct {
 	Position string `json:"position,omitempty"`
 	// Engine is the Vitess backup engine implementation that was used.
 	Engine string `json:"engine,omitempty"`
+	// Size is the total size of the backup files, which is only known for
+	// volume-backed backup locations.
+	// This is only available after the backup is complete.
+	Size *resource.Quantity `json:"size,omitempty"`
 	// StorageDirectory is the name of the parent directory in storage that
 	// contains this backup.
 	StorageDirectory string `json:"storageDirectory,omitempty"`
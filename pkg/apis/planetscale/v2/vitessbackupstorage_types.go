@@ -18,6 +18,7 @@ package v2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,6 +52,10 @@ type VitessBackupStorageSpec struct {
 	Location VitessBackupLocation `json:"location"`
 	// Subcontroller specifies any parameters needed for launching the VitessBackupStorage subcontroller pod.
 	Subcontroller *VitessBackupSubcontrollerSpec `json:"subcontroller,omitempty"`
+	// StorageClusterName is the cluster name under which backups are listed
+	// in the storage location, if it's not the name of the parent cluster.
+	// It's set while the parent cluster is a standby of another cluster.
+	StorageClusterName string `json:"storageClusterName,omitempty"`
 }
 
 type VitessBackupSubcontrollerSpec struct {
@@ -74,6 +79,18 @@ type VitessBackupLocation struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Pattern=^[A-Za-z0-9]([A-Za-z0-9-_.]*[A-Za-z0-9])?$
 	Name string `json:"name,omitempty"`
+	// Cells makes this the location for tablet pools in the given cells
+	// that don't set a backupLocationName, so tablets back up to and restore
+	// from storage close to them, such as a bucket in the same region.
+	// Pools in other cells keep using the location whose name is empty.
+	//
+	// Backups aren't copied between locations, and the initial backup only
+	// goes to the location of the shard's first tablet pool, so tablets in
+	// other cells need a backup taken in their own location to restore from.
+	//
+	// Each cell should be listed in at most one location. If several list
+	// the same cell, the first one is used.
+	Cells []string `json:"cells,omitempty"`
 	// GCS specifies a backup location in Google Cloud Storage.
 	GCS *GCSBackupLocation `json:"gcs,omitempty"`
 	// S3 specifies a backup location in Amazon S3.
@@ -88,14 +105,57 @@ type VitessBackupLocation struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Volume *corev1.VolumeSource `json:"volume,omitempty"`
+	// VolumeClaim specifies a backup location on a PersistentVolumeClaim
+	// that the operator creates and manages, for environments without
+	// object storage. The claim must be mountable by all tablets at once,
+	// so its storage class must support the ReadWriteMany access mode, as
+	// NFS or CephFS do.
+	VolumeClaim *VolumeClaimBackupLocation `json:"volumeClaim,omitempty"`
 	// VolumeSubPath gives the subpath in the volume to mount to the backups target.
-	// Only used for Volume-backed backup storage, ignored otherwise.
+	// Only used for Volume-backed and VolumeClaim-backed backup storage,
+	// ignored otherwise.
 	VolumeSubPath string `json:"volumeSubPath,omitempty"`
+	// Retention can optionally be set to have the operator delete old
+	// backups from this location. It's most useful for volume-backed
+	// locations, which don't have lifecycle rules like object storage.
+	// Default: Backups are kept until they're deleted by other means.
+	Retention *VitessBackupRetention `json:"retention,omitempty"`
 	// Annotations can optionally be used to attach custom annotations to Pods
 	// that need access to this backup storage location.
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// VolumeClaimBackupLocation specifies a backup location on a managed
+// PersistentVolumeClaim.
+type VolumeClaimBackupLocation struct {
+	// ClaimName is the name of the PersistentVolumeClaim.
+	// Default: The name of the VitessCluster, followed by "-backups" and
+	// the name of the backup location, if any.
+	ClaimName string `json:"claimName,omitempty"`
+	// Spec is the spec of the PersistentVolumeClaim. The storage request
+	// may be increased later to expand the volume, if the storage class
+	// allows it. Other changes only apply if the claim is recreated.
+	//
+	// The claim is deleted along with the VitessCluster, but not when the
+	// backup location is removed from the VitessCluster, so backups aren't
+	// lost by accident. Delete the claim by hand once it's no longer needed.
+	//
+	// Default: The access modes default to ReadWriteMany.
+	Spec corev1.PersistentVolumeClaimSpec `json:"spec"`
+}
+
+// VitessBackupRetention specifies which backups to keep in a location.
+type VitessBackupRetention struct {
+	// MaxAge is how long to keep each backup, based on when it started.
+	MaxAge metav1.Duration `json:"maxAge"`
+	// MinCount is the number of most recent complete backups of each shard
+	// that are kept even if they're older than MaxAge, so there's always a
+	// backup to restore from.
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	MinCount int32 `json:"minCount,omitempty"`
+}
+
 // GCSBackupLocation specifies a backup location in Google Cloud Storage.
 type GCSBackupLocation struct {
 	// Bucket is the name of the GCS bucket to use.
@@ -181,6 +241,24 @@ type VitessBackupStorageStatus struct {
 	// TotalBackupCount is the total number of backups found in this storage
 	// location, across all keyspaces and shards.
 	TotalBackupCount int32 `json:"totalBackupCount,omitempty"`
+
+	// DeletedBackupCount is the number of backups that were deleted by the
+	// retention policy when the location was last checked.
+	DeletedBackupCount int32 `json:"deletedBackupCount,omitempty"`
+
+	// Volume reports the space used on the backup volume, for volume-backed
+	// locations.
+	Volume *VitessBackupVolumeStatus `json:"volume,omitempty"`
+}
+
+// VitessBackupVolumeStatus reports the space used on a backup volume.
+type VitessBackupVolumeStatus struct {
+	// Capacity is the size of the file system that holds the backups.
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+	// Used is how much of the file system is in use.
+	Used *resource.Quantity `json:"used,omitempty"`
+	// UsedPercent is Used as a percentage of Capacity.
+	UsedPercent int32 `json:"usedPercent,omitempty"`
 }
 
 // NewVitessBackupStorageStatus creates a new status with default values.
@@ -27,6 +27,7 @@ func DefaultVitessCell(vtc *VitessCell) {
 	DefaultLocalLockserver(&vtc.Spec.Lockserver)
 	DefaultVitessGateway(&vtc.Spec.Gateway)
 	DefaultTopoReconcileConfig(&vtc.Spec.TopologyReconciliation)
+	DefaultFailoverBuffer(&vtc.Spec.FailoverBuffer)
 }
 
 func DefaultLocalLockserver(ls *LockserverSpec) {
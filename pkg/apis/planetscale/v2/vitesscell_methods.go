@@ -47,3 +47,40 @@ func (s *VitessCellGatewaySpec) ReloadSecretNames() sets.String {
 
 	return secretNames
 }
+
+// GatewayNamespace returns the namespace to deploy vtgate in.
+func (c *VitessCell) GatewayNamespace() string {
+	if c.Spec.Gateway.Namespace != "" {
+		return c.Spec.Gateway.Namespace
+	}
+	return c.Namespace
+}
+
+// GatewayFailoverBuffer returns the failover buffer settings for the vtgates
+// in this cell, which are those of the gateway spec, if any, with the rest
+// inherited from the VitessCluster.
+func (s *VitessCellSpec) GatewayFailoverBuffer() *FailoverBufferSpec {
+	override := s.Gateway.FailoverBuffer
+	if override == nil {
+		return s.FailoverBuffer
+	}
+	buffer := override.DeepCopy()
+	if inherited := s.FailoverBuffer; inherited != nil {
+		if buffer.Enabled == nil {
+			buffer.Enabled = inherited.Enabled
+		}
+		if buffer.Size == nil {
+			buffer.Size = inherited.Size
+		}
+		if buffer.Window == nil {
+			buffer.Window = inherited.Window
+		}
+		if buffer.MaxFailoverDuration == nil {
+			buffer.MaxFailoverDuration = inherited.MaxFailoverDuration
+		}
+		if buffer.MinTimeBetweenFailovers == nil {
+			buffer.MinTimeBetweenFailovers = inherited.MinTimeBetweenFailovers
+		}
+	}
+	return buffer
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGatewayFailoverBuffer(t *testing.T) {
+	inherited := &FailoverBufferSpec{
+		Enabled: pointer.BoolPtr(true),
+		Size:    pointer.Int32Ptr(10),
+		Window:  &metav1.Duration{Duration: 10 * time.Second},
+	}
+	spec := &VitessCellSpec{FailoverBuffer: inherited}
+
+	if got := spec.GatewayFailoverBuffer(); got != inherited {
+		t.Errorf("GatewayFailoverBuffer() without override = %+v; want %+v", got, inherited)
+	}
+
+	spec.Gateway.FailoverBuffer = &FailoverBufferSpec{Size: pointer.Int32Ptr(100)}
+	got := spec.GatewayFailoverBuffer()
+	if got.Enabled == nil || !*got.Enabled {
+		t.Errorf("GatewayFailoverBuffer().Enabled = %v; want inherited true", got.Enabled)
+	}
+	if got.Size == nil || *got.Size != 100 {
+		t.Errorf("GatewayFailoverBuffer().Size = %v; want 100", got.Size)
+	}
+	if got.Window == nil || got.Window.Duration != 10*time.Second {
+		t.Errorf("GatewayFailoverBuffer().Window = %v; want inherited 10s", got.Window)
+	}
+	if *inherited.Size != 10 || *spec.Gateway.FailoverBuffer.Size != 100 {
+		t.Errorf("GatewayFailoverBuffer() modified the specs it merged")
+	}
+}
@@ -75,8 +75,32 @@ type VitessCellSpec struct {
 	// ExtraVitessFlags is inherited from the parent's VitessClusterSpec.
 	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
 
+	// ComponentVitessFlags is inherited from the parent's VitessClusterSpec.
+	ComponentVitessFlags *ComponentVitessFlags `json:"componentVitessFlags,omitempty"`
+
 	// TopologyReconciliation is inherited from the parent's VitessClusterSpec.
 	TopologyReconciliation *TopoReconcileConfig `json:"topologyReconciliation,omitempty"`
+
+	// Networking is inherited from the parent's VitessClusterSpec.
+	Networking *NetworkingSpec `json:"networking,omitempty"`
+
+	// ResourceDefaults is inherited from the parent's VitessClusterSpec.
+	ResourceDefaults *ResourceDefaultsSpec `json:"resourceDefaults,omitempty"`
+
+	// FailoverBuffer is inherited from the parent's VitessClusterSpec.
+	FailoverBuffer *FailoverBufferSpec `json:"failoverBuffer,omitempty"`
+
+	// ExternalDNS is inherited from the parent's VitessClusterSpec.
+	ExternalDNS *ExternalDNSConfig `json:"externalDNS,omitempty"`
+
+	// Paused is inherited from the parent's VitessClusterSpec.
+	Paused bool `json:"paused,omitempty"`
+
+	// AdoptExisting is inherited from the parent's VitessClusterSpec.
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// DryRun is inherited from the parent's VitessClusterSpec.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // VitessCellTemplate contains only the user-specified parts of a VitessCell object.
@@ -95,6 +119,12 @@ type VitessCellTemplate struct {
 	// If the Kubernetes Nodes don't have such a label, leave this empty.
 	Zone string `json:"zone,omitempty"`
 
+	// ZoneDiscovery derives the zone of this cell from the topology labels
+	// of Kubernetes Nodes, instead of setting Zone by hand. It's ignored if
+	// Zone is set.
+	// Default: Don't discover the zone.
+	ZoneDiscovery *VitessCellZoneDiscovery `json:"zoneDiscovery,omitempty"`
+
 	// Lockserver specifies either a deployed or external lockserver
 	// to be used as the Vitess cell-local topology store.
 	// Default: Put this cell's topology data in the global lockserver instead of its own lockserver.
@@ -104,6 +134,22 @@ type VitessCellTemplate struct {
 	Gateway VitessCellGatewaySpec `json:"gateway,omitempty"`
 }
 
+// VitessCellZoneDiscovery configures how the zone of a cell is discovered.
+//
+// The zone is read from the "topology.kubernetes.io/zone" label of the Nodes,
+// or from the older "failure-domain.beta.kubernetes.io/zone" label. All the
+// Nodes must be in the same zone; otherwise, the zone that was discovered
+// before is kept, and the ZonesDiscovered condition of the VitessCluster
+// reports the problem. The operator needs permission to list Nodes, which are
+// cluster-scoped, as granted by the vitess-operator ClusterRole.
+type VitessCellZoneDiscovery struct {
+	// NodeSelector selects the Nodes to read the zone from. It's required,
+	// since the cell's Pods are only scheduled in the right zone once the
+	// zone is known, so the Nodes they run on can't tell.
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+}
+
 // VitessCellImages specifies container images to use for this cell.
 type VitessCellImages struct {
 	/*
@@ -123,6 +169,17 @@ type VitessCellGatewaySpec struct {
 	// +kubebuilder:validation:Minimum=0
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Namespace is the namespace to deploy vtgate in, if it's not the
+	// namespace of the VitessCluster. Any Secrets that vtgate refers to must
+	// exist in that namespace, and the operator must watch it.
+	//
+	// Kubernetes doesn't garbage collect objects whose owner is in another
+	// namespace, so the operator deletes them itself when they're no longer
+	// wanted, and holds a finalizer on the VitessCell until they're gone.
+	//
+	// Default: The namespace of the VitessCluster.
+	Namespace string `json:"namespace,omitempty"`
+
 	// Resources determines the compute resources reserved for each vtgate replica.
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
@@ -208,8 +265,40 @@ type VitessCellGatewaySpec struct {
 	// TerminationGracePeriodSeconds can optionally be used to customize
 	// terminationGracePeriodSeconds of the vtgate pod.
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// CellsToWatch lists the cells whose tablets the vtgates in this cell
+	// discover and route queries to, like a disaster recovery cell. This
+	// cell is always watched, whether or not it's listed.
+	//
+	// Default: All cells in the VitessCluster, including ones added later.
+	CellsToWatch []string `json:"cellsToWatch,omitempty"`
+
+	// AllowedTabletTypes lists the types of tablets that the vtgates in
+	// this cell route queries to. Queries for other tablet types fail.
+	//
+	// Default: All tablet types.
+	AllowedTabletTypes []VitessGatewayTabletType `json:"allowedTabletTypes,omitempty"`
+
+	// FailoverBuffer overrides the VitessCluster's failover buffer settings
+	// for the vtgates in this cell. Fields that aren't set are inherited.
+	// CoordinateReparents applies to the whole cluster, so it's ignored here.
+	FailoverBuffer *FailoverBufferSpec `json:"failoverBuffer,omitempty"`
 }
 
+// VitessGatewayTabletType is a type of tablet that vtgate can route queries to.
+// +kubebuilder:validation:Enum=primary;replica;rdonly
+type VitessGatewayTabletType string
+
+const (
+	// GatewayPrimaryTabletType is the tablet type of shard primaries.
+	GatewayPrimaryTabletType VitessGatewayTabletType = "primary"
+	// GatewayReplicaTabletType is the tablet type of replicas that can be
+	// promoted to primary.
+	GatewayReplicaTabletType VitessGatewayTabletType = "replica"
+	// GatewayRdonlyTabletType is the tablet type of read-only replicas.
+	GatewayRdonlyTabletType VitessGatewayTabletType = "rdonly"
+)
+
 // VitessGatewayAuthentication configures authentication for vtgate in this cell.
 type VitessGatewayAuthentication struct {
 	// Static configures vtgate to use a static file containing usernames and passwords.
@@ -258,6 +347,9 @@ type VitessCellGatewayStatus struct {
 type VitessCellStatus struct {
 	// The generation observed by the controller.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// DryRunChanges lists the changes the operator would make to the cell's
+	// objects, if spec.dryRun is set.
+	DryRunChanges []string `json:"dryRunChanges,omitempty"`
 	// Lockserver is a summary of the status of the cell-local lockserver.
 	Lockserver LockserverStatus `json:"lockserver,omitempty"`
 	// Gateway is a summary of the status of vtgate in this cell.
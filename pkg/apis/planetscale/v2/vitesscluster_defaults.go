@@ -19,21 +19,66 @@ package v2
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
 
 // DefaultVitessCluster fills in default values for unspecified fields.
 func DefaultVitessCluster(vt *VitessCluster) {
 	defaultGlobalLockserver(vt)
-	DefaultVitessImages(&vt.Spec.Images, DefaultImages)
+	defaultCellLockserverImages(vt)
+	DefaultVitessImages(&vt.Spec.Images, DefaultImages.WithRegistry(vt.Spec.Images.RegistryOverride))
 	DefaultVitessDashboard(&vt.Spec.VitessDashboard)
 	DefaultVtAdmin(&vt.Spec.VtAdmin)
 	DefaultVitessKeyspaceTemplates(vt.Spec.Keyspaces)
-	defaultClusterBackup(vt.Spec.Backup)
+	defaultClusterBackup(vt.Name, vt.Spec.Backup)
 	DefaultTopoReconcileConfig(&vt.Spec.TopologyReconciliation)
 	DefaultUpdateStrategy(&vt.Spec.UpdateStrategy)
 	DefaultServiceOverrides(&vt.Spec.GatewayService)
 	DefaultServiceOverrides(&vt.Spec.TabletService)
+	defaultUpgrade(vt.Spec.Upgrade)
+	defaultNetworkPolicy(vt.Spec.NetworkPolicy)
+	defaultStandby(vt.Spec.Standby)
+	defaultResourceRecommendations(vt.Spec.ResourceRecommendations)
+}
+
+func defaultResourceRecommendations(recommendations *ResourceRecommendationsSpec) {
+	if recommendations == nil {
+		return
+	}
+	if recommendations.Window == nil {
+		recommendations.Window = &metav1.Duration{Duration: defaultResourceRecommendationsWindow}
+	}
+	if recommendations.HeadroomPercent == nil {
+		recommendations.HeadroomPercent = pointer.Int32Ptr(defaultResourceRecommendationsHeadroomPercent)
+	}
+}
+
+func defaultStandby(standby *VitessClusterStandbySpec) {
+	if standby == nil {
+		return
+	}
+	if standby.RestoreInterval == nil {
+		standby.RestoreInterval = &metav1.Duration{Duration: defaultStandbyRestoreInterval}
+	}
+}
+
+func defaultNetworkPolicy(networkPolicy *NetworkPolicySpec) {
+	if networkPolicy == nil {
+		return
+	}
+	if len(networkPolicy.OperatorPodLabels) == 0 {
+		networkPolicy.OperatorPodLabels = map[string]string{"app": defaultOperatorAppLabel}
+	}
+}
+
+func defaultUpgrade(upgrade *VitessClusterUpgradeSpec) {
+	if upgrade == nil {
+		return
+	}
+	if len(upgrade.Order) == 0 {
+		upgrade.Order = []VitessUpgradeStage{VtctldUpgradeStage, VtgateUpgradeStage, VttabletUpgradeStage}
+	}
 }
 
 func defaultGlobalLockserver(vt *VitessCluster) {
@@ -47,9 +92,27 @@ func defaultGlobalLockserver(vt *VitessCluster) {
 		// By default, deploy our own etcd cluster with default settings.
 		gls.Etcd = &EtcdLockserverTemplate{}
 	}
+	if gls.Etcd.Image == "" {
+		gls.Etcd.Image = ImageWithRegistry(DefaultEtcdImage, vt.Spec.Images.RegistryOverride)
+	}
 	DefaultEtcdLockserverTemplate(gls.Etcd)
 }
 
+// defaultCellLockserverImages fills in the etcd image of cell-local
+// lockservers if the cluster overrides the registry of default images.
+// Otherwise, the image is left for the VitessCell to default.
+func defaultCellLockserverImages(vt *VitessCluster) {
+	registry := vt.Spec.Images.RegistryOverride
+	if registry == "" {
+		return
+	}
+	for i := range vt.Spec.Cells {
+		if etcd := vt.Spec.Cells[i].Lockserver.Etcd; etcd != nil && etcd.Image == "" {
+			etcd.Image = ImageWithRegistry(DefaultEtcdImage, registry)
+		}
+	}
+}
+
 // DefaultVitessImages copies images from src to dst to fill any
 // unspecified values in dst.
 func DefaultVitessImages(dst *VitessImages, src *VitessImages) {
@@ -77,6 +140,12 @@ func DefaultVitessImages(dst *VitessImages, src *VitessImages) {
 	if dst.MysqldExporter == "" {
 		dst.MysqldExporter = src.MysqldExporter
 	}
+	if dst.Init == "" {
+		dst.Init = src.Init
+	}
+	if dst.Debug == "" {
+		dst.Debug = src.Debug
+	}
 }
 
 func DefaultVitessDashboard(dashboard **VitessDashboardSpec) {
@@ -156,6 +225,7 @@ func defaultCustomPartitioning(customPartition *VitessKeyspaceCustomPartitioning
 		return
 	}
 
+	customPartition.ExpandEqualShards()
 	for i := range customPartition.Shards {
 		DefaultVitessShardTemplate(&customPartition.Shards[i].VitessShardTemplate)
 	}
@@ -169,13 +239,30 @@ func defaultEqualPartitioning(equalPartition *VitessKeyspaceEqualPartitioning) {
 	DefaultVitessShardTemplate(&equalPartition.ShardTemplate)
 }
 
-func defaultClusterBackup(backup *ClusterBackupSpec) {
+func defaultClusterBackup(clusterName string, backup *ClusterBackupSpec) {
 	if backup == nil {
 		return
 	}
 	if backup.Engine == "" {
 		backup.Engine = defaultBackupEngine
 	}
+	for i := range backup.Locations {
+		location := &backup.Locations[i]
+		if claim := location.VolumeClaim; claim != nil {
+			if claim.ClaimName == "" {
+				claim.ClaimName = clusterName + "-backups"
+				if location.Name != "" {
+					claim.ClaimName += "-" + location.Name
+				}
+			}
+			if len(claim.Spec.AccessModes) == 0 {
+				claim.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+			}
+		}
+		if retention := location.Retention; retention != nil && retention.MinCount < 1 {
+			retention.MinCount = defaultBackupMinRetentionCount
+		}
+	}
 }
 
 func DefaultTopoReconcileConfig(confPtr **TopoReconcileConfig) {
@@ -211,6 +298,16 @@ func DefaultTopoReconcileConfig(confPtr **TopoReconcileConfig) {
 	if conf.PruneSrvKeyspaces == nil {
 		conf.PruneSrvKeyspaces = pointer.BoolPtr(true)
 	}
+
+	// Defaulting rebuilding code.
+	if conf.RebuildServingGraph == nil {
+		conf.RebuildServingGraph = pointer.BoolPtr(true)
+	}
+
+	// Defaulting shared shard actions.
+	if conf.ManageShards == nil {
+		conf.ManageShards = pointer.BoolPtr(true)
+	}
 }
 
 func DefaultUpdateStrategy(updateStratPtr **VitessClusterUpdateStrategy) {
@@ -231,6 +328,33 @@ func DefaultUpdateStrategy(updateStratPtr **VitessClusterUpdateStrategy) {
 			updateStrat.External = &ExternalVitessClusterUpdateStrategyOptions{}
 		}
 	}
+
+	if updateStrat.MysqlUpgrade != nil && updateStrat.MysqlUpgrade.Method == "" {
+		updateStrat.MysqlUpgrade.Method = InPlaceMysqlUpgradeMethod
+	}
+
+	if coordinator := updateStrat.Coordinator; coordinator != nil {
+		if len(coordinator.Order) == 0 {
+			coordinator.Order = []RolloutCoordinatorStage{CellsRolloutCoordinatorStage, KeyspacesRolloutCoordinatorStage}
+		}
+		if coordinator.MaxConcurrentCells == nil {
+			coordinator.MaxConcurrentCells = pointer.Int32Ptr(1)
+		}
+		if coordinator.MaxConcurrentKeyspaces == nil {
+			coordinator.MaxConcurrentKeyspaces = pointer.Int32Ptr(1)
+		}
+		if autoRollback := coordinator.AutoRollback; autoRollback != nil {
+			if autoRollback.MaxUnhealthyTablets == nil {
+				autoRollback.MaxUnhealthyTablets = pointer.Int32Ptr(1)
+			}
+			if autoRollback.ReadinessTimeout == nil {
+				autoRollback.ReadinessTimeout = &metav1.Duration{Duration: defaultRolloutReadinessTimeout}
+			}
+			if autoRollback.CrashLoopRestarts == nil {
+				autoRollback.CrashLoopRestarts = pointer.Int32Ptr(3)
+			}
+		}
+	}
 }
 
 // DefaultServiceOverrides applies defaults to a ServiceOverrides field.
@@ -239,3 +363,24 @@ func DefaultServiceOverrides(so **ServiceOverrides) {
 		*so = &ServiceOverrides{}
 	}
 }
+
+// DefaultFailoverBuffer fills in the vtgate buffer settings that the
+// operator has always used, so vtgates keep the same flags.
+func DefaultFailoverBuffer(dst **FailoverBufferSpec) {
+	if *dst == nil {
+		*dst = &FailoverBufferSpec{}
+	}
+	buffer := *dst
+	if buffer.Enabled == nil {
+		buffer.Enabled = pointer.BoolPtr(defaultFailoverBufferEnabled)
+	}
+	if buffer.Size == nil {
+		buffer.Size = pointer.Int32Ptr(defaultFailoverBufferSize)
+	}
+	if buffer.MaxFailoverDuration == nil {
+		buffer.MaxFailoverDuration = &metav1.Duration{Duration: defaultFailoverBufferMaxFailoverDuration}
+	}
+	if buffer.MinTimeBetweenFailovers == nil {
+		buffer.MinTimeBetweenFailovers = &metav1.Duration{Duration: defaultFailoverBufferMinTimeBetweenFailovers}
+	}
+}
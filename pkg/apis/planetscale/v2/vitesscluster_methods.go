@@ -17,7 +17,12 @@ limitations under the License.
 package v2
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Cell looks up an item in the Cells list by name.
@@ -31,6 +36,21 @@ func (s *VitessClusterSpec) Cell(cellName string) *VitessCellTemplate {
 	return nil
 }
 
+// AllCellNames returns the names of all the cells in the cluster, including
+// external cells, without duplicates.
+func (s *VitessClusterSpec) AllCellNames() []string {
+	names := make([]string, 0, len(s.Cells)+len(s.ExternalCells))
+	for i := range s.Cells {
+		names = append(names, s.Cells[i].Name)
+	}
+	for i := range s.ExternalCells {
+		if s.Cell(s.ExternalCells[i].Name) == nil {
+			names = append(names, s.ExternalCells[i].Name)
+		}
+	}
+	return names
+}
+
 // ZoneMap returns a map from cell names to zone names.
 func (s *VitessClusterSpec) ZoneMap() map[string]string {
 	zones := make(map[string]string, len(s.Cells))
@@ -74,6 +94,77 @@ func (image *MysqldImage) Flavor() string {
 	}
 }
 
+// NewMysqldImage returns a MysqldImage with the image set for the given
+// Vitess flavor setting value, which is the reverse of Flavor().
+// It returns nil if the flavor is unknown.
+func NewMysqldImage(flavor, image string) *MysqldImage {
+	switch flavor {
+	case "MySQL56":
+		return &MysqldImage{Mysql56Compatible: image}
+	case "MySQL80":
+		return &MysqldImage{Mysql80Compatible: image}
+	case "MariaDB":
+		return &MysqldImage{MariadbCompatible: image}
+	case "MariaDB103":
+		return &MysqldImage{Mariadb103Compatible: image}
+	default:
+		return nil
+	}
+}
+
+// WithRegistry returns a copy of the images with the registry of each one
+// replaced by the given registry, which may include a path prefix. If
+// registry is empty, the images are returned as-is.
+func (images *VitessImages) WithRegistry(registry string) *VitessImages {
+	if registry == "" {
+		return images
+	}
+	out := images.DeepCopy()
+	for _, image := range []*string{
+		&out.Vtctld,
+		&out.Vtadmin,
+		&out.Vtorc,
+		&out.Vtgate,
+		&out.Vttablet,
+		&out.Vtbackup,
+		&out.MysqldExporter,
+		&out.Init,
+		&out.Debug,
+	} {
+		*image = ImageWithRegistry(*image, registry)
+	}
+	if out.Mysqld != nil {
+		for _, image := range []*string{
+			&out.Mysqld.Mysql56Compatible,
+			&out.Mysqld.Mysql80Compatible,
+			&out.Mysqld.MariadbCompatible,
+			&out.Mysqld.Mariadb103Compatible,
+		} {
+			*image = ImageWithRegistry(*image, registry)
+		}
+	}
+	return out
+}
+
+// ImageWithRegistry replaces the registry of an image reference with the
+// given registry, which may include a path prefix. References without a
+// registry, like "vitess/lite:v16.0.0", are treated as Docker Hub images, so
+// the registry is prepended to them. Empty images and registries are left
+// as-is.
+func ImageWithRegistry(image, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if image == "" || registry == "" {
+		return image
+	}
+	// The first path component is a registry domain only if it looks like one.
+	if i := strings.IndexByte(image, '/'); i >= 0 {
+		if first := image[:i]; strings.ContainsAny(first, ".:") || first == "localhost" {
+			image = image[i+1:]
+		}
+	}
+	return registry + "/" + image
+}
+
 func (externalOptions *ExternalVitessClusterUpdateStrategyOptions) ResourceChangesAllowed(resource corev1.ResourceName) bool {
 	for _, resourceOption := range externalOptions.AllowResourceChanges {
 		if resourceOption == resource {
@@ -83,3 +174,273 @@ func (externalOptions *ExternalVitessClusterUpdateStrategyOptions) ResourceChang
 
 	return false
 }
+
+// TabletHostnames returns whether tablets should advertise DNS names rather
+// than Pod IPs.
+func (n *NetworkingSpec) TabletHostnames() bool {
+	return n != nil && n.TabletAddress == HostnameTabletAddress
+}
+
+// Mesh returns the service mesh that injects proxy sidecars into Pods.
+func (n *NetworkingSpec) Mesh() ServiceMeshType {
+	if n == nil || n.ServiceMesh == "" {
+		return NoServiceMesh
+	}
+	return n.ServiceMesh
+}
+
+// VtgateFlags returns the flags for vtgate, or nil if none are set.
+func (f *ComponentVitessFlags) VtgateFlags() map[string]string {
+	if f == nil {
+		return nil
+	}
+	return f.Vtgate
+}
+
+// VttabletFlags returns the flags for vttablet, or nil if none are set.
+func (f *ComponentVitessFlags) VttabletFlags() map[string]string {
+	if f == nil {
+		return nil
+	}
+	return f.Vttablet
+}
+
+// VtctldFlags returns the flags for vtctld, or nil if none are set.
+func (f *ComponentVitessFlags) VtctldFlags() map[string]string {
+	if f == nil {
+		return nil
+	}
+	return f.Vtctld
+}
+
+// VtorcFlags returns the flags for vtorc, or nil if none are set.
+func (f *ComponentVitessFlags) VtorcFlags() map[string]string {
+	if f == nil {
+		return nil
+	}
+	return f.Vtorc
+}
+
+// ManagedFlagOverrides returns the operator-managed flags that extra flags
+// may set anyway, or nil if there are none.
+func (f *ComponentVitessFlags) ManagedFlagOverrides() []string {
+	if f == nil {
+		return nil
+	}
+	return f.AllowManagedFlags
+}
+
+// Evacuating returns whether the given cell is being evacuated.
+func (e *EvacuationSpec) Evacuating(cell string) bool {
+	if e == nil {
+		return false
+	}
+	for _, name := range e.Cells {
+		if name == cell {
+			return true
+		}
+	}
+	return false
+}
+
+// AddShards counts the shards of an observed keyspace into the summary.
+func (s *VitessClusterSummary) AddShards(shards map[string]VitessKeyspaceShardStatus) {
+	for name := range shards {
+		shard := shards[name]
+		if shard.HasMaster == corev1.ConditionTrue {
+			s.ShardsWithPrimary++
+		} else {
+			s.ShardsWithoutPrimary++
+		}
+		if shard.PendingChanges != "" {
+			s.PendingChanges++
+		}
+		if shard.LatestBackupTime == nil {
+			s.ShardsWithoutBackup++
+			continue
+		}
+		if s.OldestLatestBackupTime == nil || shard.LatestBackupTime.Before(s.OldestLatestBackupTime) {
+			s.OldestLatestBackupTime = shard.LatestBackupTime.DeepCopy()
+		}
+	}
+}
+
+// CompleteSummary fills in the parts of the summary that come from the
+// cell and keyspace statuses. It should be called once those are final,
+// after any calls to Summary.AddShards().
+func (s *VitessClusterStatus) CompleteSummary() {
+	summary := &s.Summary
+
+	summary.DesiredCells = int32(len(s.Cells))
+	summary.ServingCells = 0
+	for name := range s.Cells {
+		cell := s.Cells[name]
+		if cell.GatewayAvailable == corev1.ConditionTrue {
+			summary.ServingCells++
+		}
+		if cell.PendingChanges != "" {
+			summary.PendingChanges++
+		}
+	}
+
+	summary.DesiredTablets = 0
+	summary.ReadyTablets = 0
+	for name := range s.Keyspaces {
+		keyspace := s.Keyspaces[name]
+		summary.DesiredTablets += keyspace.DesiredTablets
+		summary.ReadyTablets += keyspace.ReadyTablets
+		if keyspace.PendingChanges != "" {
+			summary.PendingChanges++
+		}
+	}
+	summary.TabletsNotReady = summary.DesiredTablets - summary.ReadyTablets
+	if summary.TabletsNotReady < 0 {
+		// We may briefly see more ready tablets than desired during scale-down.
+		summary.TabletsNotReady = 0
+	}
+
+	summary.Cells = fmt.Sprintf("%d/%d", summary.ServingCells, summary.DesiredCells)
+	summary.Primaries = fmt.Sprintf("%d/%d", summary.ShardsWithPrimary, summary.ShardsWithPrimary+summary.ShardsWithoutPrimary)
+	summary.Tablets = fmt.Sprintf("%d/%d", summary.ReadyTablets, summary.DesiredTablets)
+}
+
+// AddPendingRollout counts objects of a component that have pending changes
+// into the rollout status.
+func (s *VitessClusterStatus) AddPendingRollout(component string, objects int32) {
+	if objects == 0 {
+		return
+	}
+	if s.Rollout == nil {
+		s.Rollout = make(map[string]VitessClusterRolloutStatus)
+	}
+	status := s.Rollout[component]
+	status.PendingObjects += objects
+	s.Rollout[component] = status
+}
+
+// SetRolloutReleased records in the rollout status that a component's
+// pending changes have been released.
+func (s *VitessClusterStatus) SetRolloutReleased(component string) {
+	if s.Rollout == nil {
+		s.Rollout = make(map[string]VitessClusterRolloutStatus)
+	}
+	status := s.Rollout[component]
+	status.Released = true
+	s.Rollout[component] = status
+}
+
+// SetRolloutPods records in the rollout status how many Pods of a component
+// have been updated so far. Components with nothing pending that haven't
+// been released are still omitted.
+func (s *VitessClusterStatus) SetRolloutPods(component string, pods, updatedPods int32) {
+	status, ok := s.Rollout[component]
+	if !ok && updatedPods >= pods {
+		return
+	}
+	if s.Rollout == nil {
+		s.Rollout = make(map[string]VitessClusterRolloutStatus)
+	}
+	status.Pods = pods
+	status.UpdatedPods = updatedPods
+	status.Progress = fmt.Sprintf("%d/%d", updatedPods, pods)
+	s.Rollout[component] = status
+}
+
+// CellRollingOut returns whether the rollout coordinator has released the
+// changes to the given cell.
+func (s *VitessClusterRolloutCoordinatorStatus) CellRollingOut(name string) bool {
+	if s == nil {
+		return false
+	}
+	for _, cell := range s.Cells {
+		if cell == name {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyspaceRollingOut returns whether the rollout coordinator has released
+// the changes to the given keyspace.
+func (s *VitessClusterRolloutCoordinatorStatus) KeyspaceRollingOut(name string) bool {
+	if s == nil {
+		return false
+	}
+	for _, keyspace := range s.Keyspaces {
+		if keyspace == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CommonMetadata returns the labels and annotations to add to every object
+// created for the cluster, and the keys among them that are part of the
+// identity of Pods.
+func (vt *VitessCluster) CommonMetadata() (labels, annotations map[string]string, identity []string) {
+	if vt.Spec.CommonLabelPolicy != nil {
+		identity = vt.Spec.CommonLabelPolicy.Identity
+	}
+	return vt.Spec.CommonLabels, vt.Spec.CommonAnnotations, identity
+}
+
+// SetConditionStatus sets the status, reason and message of a condition,
+// adding it if needed. LastTransitionTime is only updated if the status
+// changed.
+func (s *VitessClusterStatus) SetConditionStatus(condType VitessClusterConditionType, newStatus corev1.ConditionStatus, reason, message string) {
+	for i := range s.Conditions {
+		cond := &s.Conditions[i]
+		if cond.Type != condType {
+			continue
+		}
+		cond.Reason = reason
+		cond.Message = message
+		if cond.Status != newStatus {
+			now := metav1.NewTime(time.Now())
+			cond.Status = newStatus
+			cond.LastTransitionTime = &now
+		}
+		return
+	}
+
+	now := metav1.NewTime(time.Now())
+	s.Conditions = append(s.Conditions, VitessClusterCondition{
+		Type:               condType,
+		Status:             newStatus,
+		LastTransitionTime: &now,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// RemoveCondition removes a condition that no longer applies.
+func (s *VitessClusterStatus) RemoveCondition(condType VitessClusterConditionType) {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == condType {
+			s.Conditions = append(s.Conditions[:i], s.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetCondition returns a copy of a condition, if it exists.
+func (s *VitessClusterStatus) GetCondition(condType VitessClusterConditionType) (VitessClusterCondition, bool) {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == condType {
+			return *s.Conditions[i].DeepCopy(), true
+		}
+	}
+	return VitessClusterCondition{}, false
+}
+
+// DeepCopyConditions deep copies the conditions list for VitessClusterStatus.
+func (s *VitessClusterStatus) DeepCopyConditions() []VitessClusterCondition {
+	if len(s.Conditions) == 0 {
+		return nil
+	}
+	out := make([]VitessClusterCondition, 0, len(s.Conditions))
+	for i := range s.Conditions {
+		out = append(out, *s.Conditions[i].DeepCopy())
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterSummary(t *testing.T) {
+	older := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	status := NewVitessClusterStatus()
+	status.Cells["a"] = VitessClusterCellStatus{GatewayAvailable: corev1.ConditionTrue}
+	status.Cells["b"] = VitessClusterCellStatus{GatewayAvailable: corev1.ConditionFalse, PendingChanges: "vtgate image"}
+	status.Keyspaces["commerce"] = VitessClusterKeyspaceStatus{DesiredTablets: 6, ReadyTablets: 4}
+
+	status.Summary.AddShards(map[string]VitessKeyspaceShardStatus{
+		"-80": {HasMaster: corev1.ConditionTrue, LatestBackupTime: &newer},
+		"80-": {HasMaster: corev1.ConditionUnknown, LatestBackupTime: &older, PendingChanges: "mysqld resources"},
+		"x":   {HasMaster: corev1.ConditionTrue},
+	})
+	status.CompleteSummary()
+
+	want := VitessClusterSummary{
+		Cells:                  "1/2",
+		ServingCells:           1,
+		DesiredCells:           2,
+		Primaries:              "2/3",
+		ShardsWithPrimary:      2,
+		ShardsWithoutPrimary:   1,
+		Tablets:                "4/6",
+		DesiredTablets:         6,
+		ReadyTablets:           4,
+		TabletsNotReady:        2,
+		PendingChanges:         2,
+		ShardsWithoutBackup:    1,
+		OldestLatestBackupTime: &older,
+	}
+	if got := status.Summary; !got.OldestLatestBackupTime.Equal(want.OldestLatestBackupTime) {
+		t.Errorf("OldestLatestBackupTime = %v; want %v", got.OldestLatestBackupTime, want.OldestLatestBackupTime)
+	}
+	status.Summary.OldestLatestBackupTime = nil
+	want.OldestLatestBackupTime = nil
+	if got := status.Summary; got != want {
+		t.Errorf("Summary = %#v; want %#v", got, want)
+	}
+}
+
+func TestImageWithRegistry(t *testing.T) {
+	table := []struct {
+		image, registry, want string
+	}{
+		{"vitess/lite:v16.0.0", "registry.example.com", "registry.example.com/vitess/lite:v16.0.0"},
+		{"vitess/lite:v16.0.0", "registry.example.com/mirror/", "registry.example.com/mirror/vitess/lite:v16.0.0"},
+		{"mysql:8.0", "registry.example.com", "registry.example.com/mysql:8.0"},
+		{"quay.io/coreos/etcd:v3.3.13", "registry.example.com/mirror", "registry.example.com/mirror/coreos/etcd:v3.3.13"},
+		{"localhost:5000/vitess/lite@sha256:abc", "registry.example.com", "registry.example.com/vitess/lite@sha256:abc"},
+		{"localhost/vitess/lite", "registry.example.com", "registry.example.com/vitess/lite"},
+		{"vitess/lite:v16.0.0", "", "vitess/lite:v16.0.0"},
+		{"", "registry.example.com", ""},
+	}
+	for _, test := range table {
+		if got := ImageWithRegistry(test.image, test.registry); got != test.want {
+			t.Errorf("ImageWithRegistry(%q, %q) = %q; want %q", test.image, test.registry, got, test.want)
+		}
+	}
+}
+
+func TestVitessClusterRegistryOverride(t *testing.T) {
+	vt := &VitessCluster{
+		Spec: VitessClusterSpec{
+			Images: VitessImages{
+				Vtgate:           "example.com/custom/vtgate:v1",
+				RegistryOverride: "registry.example.com/mirror",
+			},
+			Cells: []VitessCellTemplate{
+				{Name: "a", Lockserver: LockserverSpec{Etcd: &EtcdLockserverTemplate{}}},
+				{Name: "b"},
+			},
+		},
+	}
+	DefaultVitessCluster(vt)
+
+	images := &vt.Spec.Images
+	if got, want := images.Vtgate, "example.com/custom/vtgate:v1"; got != want {
+		t.Errorf("vtgate image = %q; want %q", got, want)
+	}
+	if got, want := images.Vttablet, ImageWithRegistry(DefaultImages.Vttablet, "registry.example.com/mirror"); got != want {
+		t.Errorf("vttablet image = %q; want %q", got, want)
+	}
+	if got, want := images.MysqldExporter, ImageWithRegistry(DefaultImages.MysqldExporter, "registry.example.com/mirror"); got != want {
+		t.Errorf("mysqld-exporter image = %q; want %q", got, want)
+	}
+	if got, want := images.Mysqld.Image(), ImageWithRegistry(DefaultImages.Mysqld.Image(), "registry.example.com/mirror"); got != want {
+		t.Errorf("mysqld image = %q; want %q", got, want)
+	}
+	etcdImage := ImageWithRegistry(DefaultEtcdImage, "registry.example.com/mirror")
+	if got := vt.Spec.GlobalLockserver.Etcd.Image; got != etcdImage {
+		t.Errorf("global etcd image = %q; want %q", got, etcdImage)
+	}
+	if got := vt.Spec.Cells[0].Lockserver.Etcd.Image; got != etcdImage {
+		t.Errorf("cell etcd image = %q; want %q", got, etcdImage)
+	}
+	if DefaultImages.Vttablet == images.Vttablet {
+		t.Errorf("DefaultImages was modified")
+	}
+}
+
+func TestAllCellNames(t *testing.T) {
+	spec := &VitessClusterSpec{
+		Cells:         []VitessCellTemplate{{Name: "a"}, {Name: "b"}},
+		ExternalCells: []VitessExternalCell{{Name: "dc1"}, {Name: "a"}},
+	}
+	want := []string{"a", "b", "dc1"}
+	if got := spec.AllCellNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllCellNames() = %v; want %v", got, want)
+	}
+}
@@ -38,7 +38,14 @@ import (
 // VitessCluster status, but any configuration changes should only be made in
 // the VitessCluster object.
 // +kubebuilder:resource:path=vitessclusters,shortName=vt
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cells",type=string,JSONPath=`.status.summary.cells`
+// +kubebuilder:printcolumn:name="Primaries",type=string,JSONPath=`.status.summary.primaries`
+// +kubebuilder:printcolumn:name="Tablets",type=string,JSONPath=`.status.summary.tablets`
+// +kubebuilder:printcolumn:name="Pending",type=integer,JSONPath=`.status.summary.pendingChanges`
+// +kubebuilder:printcolumn:name="Oldest-Backup",type=date,JSONPath=`.status.summary.oldestLatestBackupTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type VitessCluster struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -92,6 +99,18 @@ type VitessClusterSpec struct {
 	// +patchStrategy=merge
 	Cells []VitessCellTemplate `json:"cells" patchStrategy:"merge" patchMergeKey:"name"`
 
+	// ExternalCells lists the Vitess cells whose tablets and vtgates are
+	// deployed outside of Kubernetes, like on bare metal, and aren't managed
+	// by the operator.
+	//
+	// External cells are kept in the global lockserver and its cells aliases,
+	// and vtgates watch their tablets, unless a cell's gateway says otherwise.
+	// Their tablets are never removed from the topology, and a keyspace that
+	// still has tablets in them isn't turned down.
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	ExternalCells []VitessExternalCell `json:"externalCells,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
 	// Keyspaces defines the logical databases to deploy.
 	//
 	// A VitessKeyspace can deploy to multiple VitessCells.
@@ -111,8 +130,22 @@ type VitessClusterSpec struct {
 	// All entries must be key-value string pairs of the form "flag": "value". The flag name should
 	// not have any prefix (just "flag", not "-flag"). To set a boolean flag,
 	// set the string value to either "true" or "false".
+	//
+	// Flags that the operator manages itself, such as ports, the cell, the
+	// global topology server, and a tablet's alias, keyspace, shard, and
+	// type, can't be overridden here or in any component's extraFlags,
+	// unless they're listed in componentVitessFlags.allowManagedFlags.
+	// Such entries are ignored, and reported in the ManagedFlagsIgnored
+	// condition of the VitessCluster (for vtctld and vtgate) or of the
+	// VitessShard (for vttablet and vtorc).
 	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
 
+	// ComponentVitessFlags can optionally be used to pass flags to only one
+	// kind of Vitess component, across the whole cluster. These take
+	// precedence over ExtraVitessFlags, while a component's own extraFlags
+	// (for example, those of a tablet pool) take precedence over these.
+	ComponentVitessFlags *ComponentVitessFlags `json:"componentVitessFlags,omitempty"`
+
 	// TopologyReconciliation can be used to enable or disable registration or pruning of various vitess components to and from topo records.
 	TopologyReconciliation *TopoReconcileConfig `json:"topologyReconciliation,omitempty"`
 
@@ -120,6 +153,109 @@ type VitessClusterSpec struct {
 	// when a revision is made to the VitessCluster spec.
 	UpdateStrategy *VitessClusterUpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// Paused stops the operator from making any changes to the cluster.
+	// While paused, the operator won't create, update, or delete any of the
+	// cluster's objects, won't change topology records, and won't perform
+	// reparents, drains, or backups, but it keeps updating status.
+	//
+	// Individual shards can override this with the 'paused' field in their
+	// shard template, to either pause just those shards in an otherwise
+	// unpaused cluster, or keep reconciling them while the rest of the
+	// cluster is paused.
+	// Default: false
+	Paused bool `json:"paused,omitempty"`
+
+	// AdoptExisting lets the operator take ownership of objects, such as
+	// Pods and Services, that already exist under the names the operator
+	// would give them, for example because they were deployed by hand or
+	// with Helm. Without it, such objects are reported as name collisions.
+	//
+	// Adopted objects get the operator's labels, an owner reference, and
+	// the annotation planetscale.com/adopted, but are otherwise left as is
+	// at first. Changes that would require recreating them, like most
+	// changes to a Pod, are then applied as rolling updates according to
+	// the update strategy, rather than right away.
+	//
+	// Topology records with the expected names are always reused. While
+	// this is set, the operator doesn't prune tablet records, since tablets
+	// that haven't been adopted yet may still be serving.
+	// Default: false
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// DryRun makes the operator report the changes it would make to the
+	// cluster, without making any. Each object reports the changes it would
+	// make to its own children in status.dryRunChanges and in events, and
+	// logs the full diffs. Apart from that, it works like 'paused'.
+	//
+	// Since child objects aren't updated during a dry run, changes are
+	// reported one level at a time. For example, a change to a tablet pool
+	// shows up as a change to a VitessKeyspace, and only after the dry run
+	// is turned off as a change to the tablet Pods.
+	// Default: false
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// CommonLabels are added to every object the operator creates for this
+	// cluster, like Pods, Services, PersistentVolumeClaims and the child
+	// VitessCells, VitessKeyspaces and VitessShards, as well as to the Pods
+	// of the Deployments it creates, like vtgate. Use it for labels that all
+	// of the cluster's objects need, like those for cost allocation, instead
+	// of repeating them in every pool's extraLabels.
+	//
+	// Labels that the operator sets itself, or that an object already has
+	// for another reason, like a pool's extraLabels, aren't overridden.
+	// Labels removed from here are removed from the objects too.
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations are added to every object the operator creates for
+	// this cluster, just like commonLabels.
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+
+	// CommonLabelPolicy decides how changes to commonLabels and
+	// commonAnnotations are applied to existing objects.
+	CommonLabelPolicy *CommonLabelPolicy `json:"commonLabelPolicy,omitempty"`
+
+	// Upgrade can optionally be set to have the operator roll out changes to
+	// the 'images' field one component at a time, in a safe order, instead
+	// of changing the images of all components at once.
+	//
+	// When a new image is set for several components, the operator changes
+	// the images of the first stage in the upgrade order, waits until all
+	// of that stage's Pods are updated and healthy, and only then moves on
+	// to the next stage. Progress is reported in status.upgrade.
+	//
+	// Staging starts from the images in use when this field is first set,
+	// so set it before changing any images.
+	Upgrade *VitessClusterUpgradeSpec `json:"upgrade,omitempty"`
+
+	// Standby can optionally be set to run this cluster as a disaster
+	// recovery standby of another VitessCluster, typically in another
+	// region, that writes its backups to the same backup storage.
+	//
+	// While in standby, the tablets restore the source cluster's backups
+	// instead of this cluster's, the operator periodically recreates them
+	// one at a time so they pick up newer backups, and no primary is
+	// elected. The shards report how far behind they are in status.standby.
+	//
+	// To fail over, set 'promoted'. See VitessClusterStandbySpec.
+	Standby *VitessClusterStandbySpec `json:"standby,omitempty"`
+
+	// Users optionally declares MySQL users that the operator creates, and
+	// keeps up to date, on the primary of every shard, from which they
+	// replicate to the other tablets. Changing a user's password Secret or
+	// grants is applied without restarting anything, and removing a user
+	// from the list drops it.
+	// +listType=map
+	// +listMapKey=name
+	// +listMapKey=host
+	Users []VitessMySQLUser `json:"users,omitempty"`
+
+	// InternalCredentials can optionally be set to give the MySQL accounts
+	// that Vitess uses internally passwords, and to rotate them. See
+	// VitessInternalCredentialsSpec.
+	//
+	// Default: The internal accounts have no passwords.
+	InternalCredentials *VitessInternalCredentialsSpec `json:"internalCredentials,omitempty"`
+
 	// GatewayService can optionally be used to customize the global vtgate Service.
 	// Note that per-cell vtgate Services can be customized within each cell
 	// definition.
@@ -127,6 +263,398 @@ type VitessClusterSpec struct {
 
 	// TabletService can optionally be used to customize the global, headless vttablet Service.
 	TabletService *ServiceOverrides `json:"tabletService,omitempty"`
+
+	// Observability can optionally be used to integrate the cluster with
+	// external monitoring systems.
+	Observability *ObservabilitySpec `json:"observability,omitempty"`
+
+	// ExternalDNS can optionally be used to publish stable DNS names for the
+	// vtgate and vtctld Services through external-dns.
+	ExternalDNS *ExternalDNSConfig `json:"externalDNS,omitempty"`
+
+	// Networking can optionally be used to configure the cluster for
+	// IPv6-only or dual-stack Kubernetes networking.
+	Networking *NetworkingSpec `json:"networking,omitempty"`
+
+	// NetworkPolicy can optionally be used to restrict incoming traffic to
+	// the cluster's Pods to the flows that Vitess needs.
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Evacuation can optionally be used to move primaries out of cells that
+	// are degraded, for example because their zone is failing.
+	Evacuation *EvacuationSpec `json:"evacuation,omitempty"`
+
+	// ResourceDefaults can optionally be used to fill in and bound the
+	// resource requests and limits of every container the operator deploys,
+	// including init and sidecar containers. This works like a LimitRange
+	// for the cluster's Pods, except that it changes the Pods instead of
+	// rejecting them.
+	//
+	// Changing this restarts every component whose containers are affected.
+	ResourceDefaults *ResourceDefaultsSpec `json:"resourceDefaults,omitempty"`
+
+	// FailoverBuffer configures how vtgates hold on to queries for a shard
+	// primary while it's being replaced, so applications don't see errors
+	// from short failovers.
+	//
+	// Changing this restarts vtgates.
+	FailoverBuffer *FailoverBufferSpec `json:"failoverBuffer,omitempty"`
+
+	// ResourceRecommendations can optionally be set to compare the resources
+	// of each tablet pool with their actual usage, and report recommended
+	// requests in status.resourceRecommendations. Nothing is changed based on
+	// the recommendations.
+	ResourceRecommendations *ResourceRecommendationsSpec `json:"resourceRecommendations,omitempty"`
+}
+
+// ResourceRecommendationsSpec configures the resource recommendations for
+// tablet pools.
+//
+// Usage is read from the Kubernetes resource metrics API (metrics.k8s.io),
+// which is served by metrics-server, or by prometheus-adapter for usage
+// recorded in Prometheus.
+type ResourceRecommendationsSpec struct {
+	// Window is how long peak usage is tracked before it starts over, so the
+	// recommendations follow lasting changes in load.
+	// Default: 168h (one week)
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// HeadroomPercent is how much to add to the peak usage of a container
+	// to get its recommended requests.
+	// Default: 20
+	// +kubebuilder:validation:Minimum=0
+	HeadroomPercent *int32 `json:"headroomPercent,omitempty"`
+}
+
+// FailoverBufferSpec configures vtgate query buffering during failovers.
+type FailoverBufferSpec struct {
+	// Enabled turns buffering on or off.
+	// Default: true.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Size is the maximum number of queries each vtgate buffers at once,
+	// across all shards. Queries beyond that fail immediately.
+	// Default: 1000.
+	// +kubebuilder:validation:Minimum=1
+	Size *int32 `json:"size,omitempty"`
+
+	// Window is the longest time that a single query may stay buffered.
+	// Default: vtgate's own default (10s).
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// MaxFailoverDuration is the longest time that vtgate buffers for a
+	// single failover. After that, buffered queries are sent on even if the
+	// new primary isn't serving yet.
+	// Default: 10s.
+	MaxFailoverDuration *metav1.Duration `json:"maxFailoverDuration,omitempty"`
+
+	// MinTimeBetweenFailovers is how long after a failover vtgate refuses
+	// to buffer for another one in the same shard.
+	// Default: 20s.
+	MinTimeBetweenFailovers *metav1.Duration `json:"minTimeBetweenFailovers,omitempty"`
+
+	// CoordinateReparents makes the operator fit its own planned reparents
+	// into the buffer. Each reparent waits at most MaxFailoverDuration for
+	// replicas to catch up before giving up and leaving the old primary in
+	// place. Drains and evacuations also wait until MinTimeBetweenFailovers
+	// has passed since the shard's last reparent, since vtgate wouldn't
+	// buffer for them before that. Reparents requested through actions
+	// aren't delayed.
+	// This has no effect if buffering is disabled.
+	// Default: false.
+	CoordinateReparents bool `json:"coordinateReparents,omitempty"`
+}
+
+// ComponentVitessFlags holds extra flags for each kind of Vitess component.
+// All entries must be key-value string pairs of the form "flag": "value".
+type ComponentVitessFlags struct {
+	// Vtgate flags are passed to vtgate in every cell.
+	Vtgate map[string]string `json:"vtgate,omitempty"`
+	// Vttablet flags are passed to vttablet in every tablet pool.
+	Vttablet map[string]string `json:"vttablet,omitempty"`
+	// Vtctld flags are passed to vtctld.
+	Vtctld map[string]string `json:"vtctld,omitempty"`
+	// Vtorc flags are passed to vtorc in every keyspace and shard.
+	Vtorc map[string]string `json:"vtorc,omitempty"`
+	// AllowManagedFlags lists flags that the operator manages, which extra
+	// flags at any level may set anyway, for example "port". The value
+	// from the extra flags then replaces the one the operator derives.
+	// Only use this if you know the operator doesn't rely on that value,
+	// since it may break routing or topology registration otherwise.
+	AllowManagedFlags []string `json:"allowManagedFlags,omitempty"`
+}
+
+// ResourceDefaultsSpec configures resource requests and limits that apply to
+// all containers, whether or not their own resources were set.
+type ResourceDefaultsSpec struct {
+	// DefaultRequests are requested by containers that don't request the
+	// same resource themselves, unless they limit it, in which case
+	// Kubernetes requests the limit.
+	DefaultRequests corev1.ResourceList `json:"defaultRequests,omitempty"`
+
+	// DefaultLimits are set for containers that don't limit the same
+	// resource themselves. A default limit below what the container
+	// requests is raised to the request.
+	DefaultLimits corev1.ResourceList `json:"defaultLimits,omitempty"`
+
+	// MinRequests raises lower requests, including those that were left
+	// unset. Limits below the minimum are raised along with them.
+	MinRequests corev1.ResourceList `json:"minRequests,omitempty"`
+
+	// MaxLimits lowers higher limits, including those that were left unset.
+	// Requests above the maximum are lowered along with them, so MaxLimits
+	// wins if it conflicts with MinRequests.
+	MaxLimits corev1.ResourceList `json:"maxLimits,omitempty"`
+}
+
+// EvacuationSpec lists cells that should no longer host primary tablets.
+type EvacuationSpec struct {
+	// Cells is a list of cell names to evacuate.
+	//
+	// In every shard whose primary is in one of these cells, a planned
+	// reparent is done to a healthy replica in a cell that isn't evacuated,
+	// if there is one. Unlike drains, this doesn't wait for a maintenance
+	// window or for every tablet of the shard to be healthy, since tablets
+	// in the evacuated cells are often unavailable already.
+	//
+	// Tablets in evacuated cells are left running, so they can serve again
+	// as soon as the cell is removed from this list.
+	// +listType=set
+	Cells []string `json:"cells,omitempty"`
+
+	// ScaleUpReplicas makes the operator add replica-type tablets in cells
+	// that aren't evacuated, to make up for the replica-type tablets in
+	// evacuated cells. The extra tablets are spread evenly across the
+	// remaining cells that have a replica pool, and are removed again once
+	// the evacuation ends.
+	// Default: false
+	ScaleUpReplicas bool `json:"scaleUpReplicas,omitempty"`
+}
+
+// NetworkPolicySpec configures the NetworkPolicies generated for the cluster.
+type NetworkPolicySpec struct {
+	// Enabled makes the operator create a NetworkPolicy for each component
+	// that only lets in the traffic Vitess needs: vtgate, vtctld, vtorc and
+	// the operator to vttablet, vttablet to vttablet and vtbackup to mysqld
+	// for replication, vtadmin and the operator to vtgate and vtctld, and all
+	// components to the etcd lockserver. All other incoming traffic is denied.
+	//
+	// Outgoing traffic isn't restricted, since tablets need to reach backup
+	// storage and other services outside the cluster.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AllowedCIDRs are IP blocks that may connect to the client-facing ports
+	// of vtgate, vtctld and vtadmin, and to the web and metrics ports of all
+	// Vitess components. To let applications and Prometheus inside the
+	// Kubernetes cluster connect, include the Pod network here.
+	//
+	// If this is empty, vtgate's MySQL port is open to any client, so
+	// applications can still reach the database. Other client-facing ports
+	// are only open to the Vitess components that need them.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// OperatorPodLabels are the labels of the operator's Pods, in any
+	// namespace, which need to reach tablets and the lockserver.
+	// Default: app=vitess-operator
+	OperatorPodLabels map[string]string `json:"operatorPodLabels,omitempty"`
+
+	// PeerNamespaceSelector can optionally select other namespaces whose Pods
+	// with this cluster's labels are let in like the cluster's own Pods.
+	// Namespaces that cells deploy vtgate to are always let in. Set this if
+	// the cluster is a member of a VitessClusterFederation, and the network
+	// plugin enforces policies across the member Kubernetes clusters.
+	PeerNamespaceSelector *metav1.LabelSelector `json:"peerNamespaceSelector,omitempty"`
+}
+
+// NetworkingSpec configures the IP families used by the cluster.
+type NetworkingSpec struct {
+	// IPFamilyPolicy is set on the vtgate, vtctld, vtadmin, and vttablet
+	// Services.
+	// This is only applied when a Service is created, so changes made
+	// afterwards only take effect if you manually delete the Service.
+	// Default: Let Kubernetes decide, which is SingleStack.
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	// IPFamilies is set on the vtgate, vtctld, vtadmin, and vttablet
+	// Services, in order of preference.
+	// This is only applied when a Service is created, so changes made
+	// afterwards only take effect if you manually delete the Service.
+	// Default: Let Kubernetes decide, which is the cluster's primary family.
+	// +kubebuilder:validation:MaxItems=2
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// TabletAddress is the kind of address that tablets advertise in the
+	// topology, which other tablets use to replicate from them, and which
+	// vtgate and vtctld use to reach them.
+	//
+	// With "PodIP", tablets advertise the Pod IP, which is only ever of the
+	// cluster's primary IP family. With "Hostname", tablets advertise a DNS
+	// name in the vttablet Service, which resolves to every Pod IP, so each
+	// client can connect with the family it supports.
+	//
+	// Changing this restarts all tablets.
+	// Default: PodIP
+	// +kubebuilder:validation:Enum=PodIP;Hostname
+	TabletAddress TabletAddressType `json:"tabletAddress,omitempty"`
+
+	// ServiceMesh is the service mesh, if any, that injects proxy sidecars
+	// into the cluster's Pods. The operator doesn't turn on injection, but
+	// makes tablet, vtbackup and vtgate Pods work with the injected proxies:
+	//
+	// MySQL connections, including replication between tablets, bypass the
+	// proxy, since the mesh can't tell the MySQL protocol apart from others
+	// when the server speaks first. The proxy runs as a native sidecar, so
+	// it starts before any init containers that restore data, keeps serving
+	// while a deleted tablet drains, and exits when a vtbackup Pod is done.
+	//
+	// Native sidecars need Kubernetes 1.29 or later, as well as Istio 1.20 or
+	// Linkerd 2.15 or later. Changing this restarts all tablets.
+	// Default: None
+	// +kubebuilder:validation:Enum=None;Istio;Linkerd
+	ServiceMesh ServiceMeshType `json:"serviceMesh,omitempty"`
+}
+
+// ServiceMeshType is a kind of service mesh.
+type ServiceMeshType string
+
+const (
+	// NoServiceMesh means the Pods don't get proxy sidecars.
+	NoServiceMesh ServiceMeshType = "None"
+	// IstioServiceMesh is the Istio service mesh.
+	IstioServiceMesh ServiceMeshType = "Istio"
+	// LinkerdServiceMesh is the Linkerd service mesh.
+	LinkerdServiceMesh ServiceMeshType = "Linkerd"
+)
+
+// TabletAddressType is a kind of address that tablets advertise.
+type TabletAddressType string
+
+const (
+	// PodIPTabletAddress makes tablets advertise their Pod IP.
+	PodIPTabletAddress TabletAddressType = "PodIP"
+	// HostnameTabletAddress makes tablets advertise their DNS name in the
+	// vttablet Service.
+	HostnameTabletAddress TabletAddressType = "Hostname"
+)
+
+// ExternalDNSConfig configures the hostnames that external-dns publishes
+// for the cluster's Services.
+//
+// While this is set, the operator manages the external-dns hostname and TTL
+// annotations of the vtgate and vtctld Services, overriding any set through
+// Service overrides, and removes them from Services that have no hostname.
+// Note that external-dns only publishes ClusterIP Services if it runs with
+// --publish-internal-services.
+//
+// Hostnames may contain the placeholders "{cluster}", which is replaced by
+// the VitessCluster name, and "{namespace}", which is replaced by its
+// namespace.
+type ExternalDNSConfig struct {
+	// VtgateHostname is the hostname for the global vtgate Service.
+	VtgateHostname string `json:"vtgateHostname,omitempty"`
+
+	// VtgateCellHostname is the hostname template for each cell's vtgate
+	// Service. It may also contain the placeholder "{cell}", which is
+	// replaced by the cell name, for example "vtgate-{cell}.example.com".
+	VtgateCellHostname string `json:"vtgateCellHostname,omitempty"`
+
+	// VtctldHostname is the hostname for the vtctld Service.
+	VtctldHostname string `json:"vtctldHostname,omitempty"`
+
+	// TTL is the TTL of the DNS records, in seconds.
+	// Default: Let external-dns decide.
+	// +kubebuilder:validation:Minimum=1
+	TTL *int32 `json:"ttl,omitempty"`
+}
+
+// ObservabilitySpec configures integration with external monitoring systems.
+type ObservabilitySpec struct {
+	// PrometheusOperator controls whether the operator creates PodMonitor
+	// objects (monitoring.coreos.com/v1) that scrape vtgate, vttablet,
+	// mysqld-exporter, vtctld, vtorc, and etcd in this cluster.
+	//
+	// Default: PodMonitors are created if the Prometheus Operator CRDs are
+	// installed in the Kubernetes cluster. Set this to false to opt out.
+	PrometheusOperator *bool `json:"prometheusOperator,omitempty"`
+
+	// ScrapeInterval can optionally be used to set how often Prometheus
+	// should scrape each target, such as "30s". If unset, the Prometheus
+	// default is used.
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+
+	// MonitorLabels specifies extra labels to add to the generated
+	// PodMonitor objects, such as labels matched by the podMonitorSelector
+	// of your Prometheus instance.
+	MonitorLabels map[string]string `json:"monitorLabels,omitempty"`
+}
+
+// VitessUpgradeStage is a group of components whose images are upgraded together.
+// +kubebuilder:validation:Enum=vtctld;vtgate;vttablet
+type VitessUpgradeStage string
+
+const (
+	// VtctldUpgradeStage upgrades the vtctld and vtadmin images.
+	VtctldUpgradeStage VitessUpgradeStage = "vtctld"
+	// VtgateUpgradeStage upgrades the vtgate image.
+	VtgateUpgradeStage VitessUpgradeStage = "vtgate"
+	// VttabletUpgradeStage upgrades the images of tablet Pods (vttablet,
+	// mysqld, and mysqld-exporter), as well as vtorc and vtbackup.
+	VttabletUpgradeStage VitessUpgradeStage = "vttablet"
+)
+
+// VitessClusterStandbySpec configures a cluster as a standby that keeps
+// restoring the backups of another cluster.
+type VitessClusterStandbySpec struct {
+	// SourceClusterName is the name of the VitessCluster whose backups this
+	// cluster restores. Both clusters must define the same backup locations,
+	// keyspaces and shards.
+	// +kubebuilder:validation:MinLength=1
+	SourceClusterName string `json:"sourceClusterName"`
+
+	// RestoreInterval is how often each tablet is recreated to restore the
+	// latest backup of the source cluster. Tablets are recreated one at a
+	// time, only while the update strategy allows disruptive changes.
+	// Default: 1h
+	RestoreInterval *metav1.Duration `json:"restoreInterval,omitempty"`
+
+	// Promoted turns the standby into a regular cluster. The operator stops
+	// recreating tablets, elects a primary in each shard from the restored
+	// tablets, and starts taking and listing backups of this cluster itself.
+	// Since that changes the backup flags of the tablets, promotion also
+	// schedules a rolling restart of all tablets, which keeps their data.
+	//
+	// Make sure the source cluster no longer takes writes before promoting,
+	// since anything written after its latest backup is lost.
+	// Default: false
+	Promoted bool `json:"promoted,omitempty"`
+}
+
+// CommonLabelPolicy decides how changes to common labels and annotations are
+// applied to existing objects.
+type CommonLabelPolicy struct {
+	// Identity lists keys of commonLabels and commonAnnotations that are part
+	// of the identity of a Pod, because they only take effect when the Pod is
+	// created, like those that control sidecar injection. Changes to them are
+	// rolled out by recreating Pods according to the update strategy.
+	//
+	// Changes to all other keys are applied to existing objects in place.
+	Identity []string `json:"identity,omitempty"`
+}
+
+// VitessClusterUpgradeSpec configures staged upgrades of component images.
+type VitessClusterUpgradeSpec struct {
+	// Order is the order in which to upgrade each stage. Every stage must be
+	// listed exactly once.
+	//
+	// The default is the order recommended by Vitess for most upgrades:
+	// first vtctld, then vtgate, and finally vttablet. If the release notes
+	// for the version you're upgrading to recommend a different order, you
+	// can set it here.
+	//
+	// Default: [vtctld, vtgate, vttablet]
+	// +kubebuilder:validation:MinItems=3
+	// +kubebuilder:validation:MaxItems=3
+	Order []VitessUpgradeStage `json:"order,omitempty"`
 }
 
 // VitessClusterUpdateStrategy indicates the strategy that the operator
@@ -152,6 +680,210 @@ type VitessClusterUpdateStrategy struct {
 	// External can optionally be used to enable the user to customize their external update strategy
 	// to allow certain updates to pass through immediately without using an external tool.
 	External *ExternalVitessClusterUpdateStrategyOptions `json:"external,omitempty"`
+
+	// MaintenanceWindows restricts when disruptive changes may be applied.
+	// Restarting tablet Pods for a rolling update, resizing tablet PVCs, and
+	// planned reparents triggered by drains will wait until one of the
+	// windows is open. Changes that can be applied without disruption, such
+	// as updates to labels or replica counts, are not affected. Each
+	// VitessShard's MaintenanceWindowOpen condition reports whether a window
+	// is open, and when the next one opens if not.
+	//
+	// Actions requested explicitly with shard action annotations, such as
+	// 'planetscale.com/reparent-to', are not restricted by maintenance windows.
+	//
+	// Default: Disruptive changes may be applied at any time.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// MaxConcurrentShardRollouts is how many shards of each keyspace may
+	// roll out changes to their tablets at once. Within a shard, tablets
+	// are still restarted one at a time. Other shards with pending changes
+	// wait for their turn.
+	//
+	// Default: Every shard with pending changes rolls them out at once.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentShardRollouts *int32 `json:"maxConcurrentShardRollouts,omitempty"`
+
+	// Coordinator can optionally be set to have the operator release changes
+	// scheduled by the External strategy on its own, a few cells and
+	// keyspaces at a time, instead of waiting for an external tool.
+	// Progress is reported in status.rolloutCoordinator, and the pods of
+	// each component updated so far are counted in status.rollout.
+	//
+	// This has no effect unless type is External.
+	//
+	// Default: Scheduled changes wait for an external tool to release them.
+	Coordinator *RolloutCoordinatorSpec `json:"coordinator,omitempty"`
+
+	// MysqlUpgrade can optionally be set to orchestrate upgrades to a new
+	// MySQL major version, such as from 5.7 to 8.0, which the operator
+	// detects as a change to a different mysqld image flavor (for example
+	// from mysql56Compatible to mysql80Compatible).
+	//
+	// In each shard, the replicas are upgraded one at a time, waiting for
+	// each one to be healthy and replicating before moving on. Once all
+	// replicas are upgraded, the primary is reparented to one of them, and
+	// then upgraded as well. Progress is reported in the mysqlUpgrade field
+	// of the VitessShard status.
+	//
+	// Until the primary is reached, the upgrade of a shard can be aborted
+	// with the 'planetscale.com/abort-mysql-upgrade' annotation on the
+	// VitessShard. Upgraded replicas are then restored from a backup with
+	// the old mysqld image, so backups must be configured to roll back.
+	//
+	// Default: The new mysqld image is rolled out like any other change.
+	MysqlUpgrade *MysqlUpgradeStrategy `json:"mysqlUpgrade,omitempty"`
+}
+
+// RolloutCoordinatorStage is a group of objects whose scheduled changes the
+// rollout coordinator releases together.
+// +kubebuilder:validation:Enum=cells;keyspaces
+type RolloutCoordinatorStage string
+
+const (
+	// CellsRolloutCoordinatorStage releases changes to VitessCells, which
+	// rolls out vtgate.
+	CellsRolloutCoordinatorStage RolloutCoordinatorStage = "cells"
+	// KeyspacesRolloutCoordinatorStage releases changes to VitessKeyspaces,
+	// along with their shards and tablet Pods.
+	KeyspacesRolloutCoordinatorStage RolloutCoordinatorStage = "keyspaces"
+)
+
+// RolloutCoordinatorSpec configures how the operator sequences the release
+// of scheduled changes across the cluster.
+type RolloutCoordinatorSpec struct {
+	// Order is the order in which to roll out each stage. Every stage must
+	// be listed exactly once. A stage doesn't start until every cell or
+	// keyspace of the stages before it has no pending changes.
+	//
+	// Default: [cells, keyspaces]
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	Order []RolloutCoordinatorStage `json:"order,omitempty"`
+
+	// MaxConcurrentCells is how many cells may roll out changes to vtgate
+	// at once. A cell is done once its vtgate Pods are all updated and
+	// available.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentCells *int32 `json:"maxConcurrentCells,omitempty"`
+
+	// MaxConcurrentKeyspaces is how many keyspaces may roll out changes to
+	// their shards and tablets at once. Within each keyspace, the number of
+	// shards rolling out at once is still limited by maxConcurrentShardRollouts.
+	// A keyspace is done once its tablet Pods are all updated and ready.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentKeyspaces *int32 `json:"maxConcurrentKeyspaces,omitempty"`
+
+	// Paused stops the coordinator from releasing changes to any more cells,
+	// keyspaces or shards. Shards that have already started restarting their
+	// tablets finish doing so. Set it back to false to resume the rollout.
+	//
+	// Default: false
+	Paused bool `json:"paused,omitempty"`
+
+	// Abort stops the rollout as soon as possible. Like paused, it stops
+	// releasing changes, but it also stops shards from restarting any more
+	// of their tablets. Changes that haven't been rolled out stay scheduled.
+	// Set it back to false to resume the rollout where it stopped.
+	//
+	// Components listed in the 'rollout.planetscale.com/released-components'
+	// annotation on the VitessCluster keep being rolled out regardless.
+	//
+	// Default: false
+	Abort bool `json:"abort,omitempty"`
+
+	// AutoRollback can optionally be set to halt the rollout when tablets
+	// that were just updated crash-loop or fail to become ready. A halted
+	// rollout is reported in status.rolloutCoordinator, and stays halted
+	// until the VitessCluster spec changes again, for example to fix or
+	// revert the change that caused it.
+	//
+	// Default: The rollout continues regardless of the health of updated tablets.
+	AutoRollback *RolloutAutoRollbackSpec `json:"autoRollback,omitempty"`
+}
+
+// RolloutAutoRollbackSpec configures when the rollout coordinator halts a
+// rollout because of unhealthy tablets, and what it does about them.
+type RolloutAutoRollbackSpec struct {
+	// MaxUnhealthyTablets is how many updated tablets across the cluster
+	// must be unhealthy at once for the rollout to be halted.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	MaxUnhealthyTablets *int32 `json:"maxUnhealthyTablets,omitempty"`
+
+	// ReadinessTimeout is how long an updated tablet Pod may take to become
+	// ready before the tablet is considered unhealthy.
+	//
+	// Default: 10m
+	ReadinessTimeout *metav1.Duration `json:"readinessTimeout,omitempty"`
+
+	// CrashLoopRestarts is how many times a container of an updated tablet
+	// Pod may restart before the tablet is considered unhealthy.
+	//
+	// Default: 3
+	// +kubebuilder:validation:Minimum=1
+	CrashLoopRestarts *int32 `json:"crashLoopRestarts,omitempty"`
+
+	// RevertPods also recreates the unhealthy tablets with the images and
+	// flags they last ran successfully, as recorded in the lastKnownGood
+	// status field of their VitessShard. Reverted tablets are updated again
+	// once the halt is over.
+	//
+	// Default: false
+	RevertPods bool `json:"revertPods,omitempty"`
+}
+
+// MysqlUpgradeMethod is how tablets are moved to a new MySQL major version.
+// +kubebuilder:validation:Enum=InPlace;RestoreFromBackup
+type MysqlUpgradeMethod string
+
+const (
+	// InPlaceMysqlUpgradeMethod restarts each tablet with the new mysqld
+	// image, keeping its data volume. The new mysqld version upgrades the
+	// data directory when it starts.
+	InPlaceMysqlUpgradeMethod MysqlUpgradeMethod = "InPlace"
+	// RestoreFromBackupMysqlUpgradeMethod recreates each tablet with an empty
+	// data volume, so it restores the latest backup into the new mysqld
+	// version. This requires backups to be configured.
+	RestoreFromBackupMysqlUpgradeMethod MysqlUpgradeMethod = "RestoreFromBackup"
+)
+
+// MysqlUpgradeStrategy configures orchestrated MySQL major version upgrades.
+type MysqlUpgradeStrategy struct {
+	// Method selects how each tablet is upgraded.
+	//
+	// Supported options are:
+	//
+	// - InPlace: Restart the tablet with the new image, and let the new
+	//   mysqld version upgrade the existing data directory.
+	// - RestoreFromBackup: Recreate the tablet with an empty data volume,
+	//   and restore the latest backup into the new mysqld version.
+	//
+	// Default: InPlace
+	Method MysqlUpgradeMethod `json:"method,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time during which disruptive
+// changes may be applied.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression for when the window opens, with the
+	// five standard fields: minute, hour, day of month, month, and day of
+	// week. For example, "0 2 * * 6" opens the window every Saturday at 02:00.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open each time it opens, such as "4h".
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the name of the IANA time zone in which to interpret the
+	// schedule, such as "America/Los_Angeles".
+	// Default: UTC
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 // VitessClusterUpdateStrategyType is a string enumeration type that enumerates
@@ -210,6 +942,24 @@ type TopoReconcileConfig struct {
 	// PruneTablets can be used to enable or disable pruning of extraneous tablets from topo records.
 	// Default: true
 	PruneTablets *bool `json:"pruneTablets,omitempty"`
+
+	// RebuildServingGraph can be used to enable or disable rebuilding the
+	// serving VSchema and serving keyspace records of each cell when they're
+	// missing or out of date, like after a cell is added or a shard starts
+	// serving, which otherwise takes a manual RebuildVSchemaGraph or
+	// RebuildKeyspaceGraph before the cell serves traffic.
+	// Default: true
+	RebuildServingGraph *bool `json:"rebuildServingGraph,omitempty"`
+
+	// ManageShards can be used to enable or disable the actions that change
+	// the shared state of keyspaces and shards, rather than of this cluster's
+	// own tablets: electing a shard's first primary, moving the primary out
+	// of evacuated cells, managing MySQL users and passwords, writing the
+	// keyspace record and tablet controls, and driving Reshard, Materialize
+	// and lookup vindex workflows. In a VitessClusterFederation, it's only
+	// enabled for the topologyOwner member.
+	// Default: true
+	ManageShards *bool `json:"manageShards,omitempty"`
 }
 
 // VitessImages specifies container images to use for Vitess components.
@@ -220,6 +970,7 @@ type VitessImages struct {
 		Make sure to keep the following up to date if you add fields here:
 		  * defaultVitessImages in defaults.go
 		  * DefaultVitessImages() in vitesscluster_defaults.go
+		  * copyUpgradeStageImages() in the vitesscluster controller
 	*/
 
 	// Vtctld is the container image (including version tag) to use for Vitess Dashboard instances.
@@ -242,6 +993,48 @@ type VitessImages struct {
 	Mysqld *MysqldImage `json:"mysqld,omitempty"`
 	// MysqldExporter specifies the container image to use for mysqld-exporter.
 	MysqldExporter string `json:"mysqldExporter,omitempty"`
+	// Init is the container image (including version tag) to use for the
+	// init containers that the operator adds to vttablet and vtbackup Pods
+	// to copy Vitess binaries and config files into the mysqld container.
+	// It must contain the same Vitess release as the vttablet and vtbackup
+	// images, in the same locations.
+	// Default: The vttablet image for tablets, and the vtbackup image for vtbackup.
+	Init string `json:"init,omitempty"`
+	// Debug is the container image (including version tag) to run in the
+	// ephemeral debug containers that can be added to vttablet Pods with the
+	// planetscale.com/debug-tablet annotation.
+	// Default: The vttablet image.
+	Debug string `json:"debug,omitempty"`
+
+	// RegistryOverride is a registry host, optionally followed by a path
+	// prefix, that replaces the registry of every image the operator fills
+	// in by default, including helper images like mysqld-exporter and etcd.
+	// Images that are set explicitly are used as-is.
+	//
+	// For example, with "registry.example.com/mirror", the default image
+	// "vitess/lite:v16.0.0" becomes
+	// "registry.example.com/mirror/vitess/lite:v16.0.0", and
+	// "quay.io/coreos/etcd:v3.3.13" becomes
+	// "registry.example.com/mirror/coreos/etcd:v3.3.13".
+	RegistryOverride string `json:"registryOverride,omitempty"`
+
+	// ResolveTagsToDigests can be set to true to have the operator look up
+	// the digest that each image tag points to, and pin all Pods to that
+	// digest. This ensures that all Pods of a component run exactly the
+	// same image, even if a tag is later pushed again.
+	//
+	// Each tag is only resolved once. To pick up a tag that was pushed
+	// again, change the image in the spec, for example to a different tag
+	// or to a digest. Images that already include a digest are left as-is.
+	//
+	// Credentials for private registries are taken from imagePullSecrets.
+	// If a tag can't be resolved, the operator keeps retrying in the
+	// background. Meanwhile, an image that was pinned before keeps its last
+	// digest, and if there's an image that was never pinned, the operator
+	// holds back changes to the cluster's cells, keyspaces, vtctld and
+	// vtadmin, so no Pod runs the mutable tag.
+	// Default: false
+	ResolveTagsToDigests bool `json:"resolveTagsToDigests,omitempty"`
 }
 
 // MysqldImage specifies the container image to use for mysqld,
@@ -306,8 +1099,105 @@ type ClusterBackupSpec struct {
 	// Default: builtin
 	// +kubebuilder:validation:Enum=builtin;xtrabackup
 	Engine VitessBackupEngine `json:"engine,omitempty"`
+	// Compression can optionally be used to choose how backups are
+	// compressed, with either engine.
+	// Default: Backups are compressed with pgzip.
+	Compression *BackupCompressionSpec `json:"compression,omitempty"`
+	// Xtrabackup can optionally be used to tune the xtrabackup engine.
+	// It has no effect unless engine is xtrabackup.
+	Xtrabackup *XtrabackupSpec `json:"xtrabackup,omitempty"`
 	// Subcontroller specifies any parameters needed for launching the VitessBackupStorage subcontroller pod.
 	Subcontroller *VitessBackupSubcontrollerSpec `json:"subcontroller,omitempty"`
+	// StaleThreshold can optionally be set to have each shard report a
+	// BackupStale condition, which is True while the shard's latest complete
+	// backup started longer ago than this. It can be used for alerts, or to
+	// check that a recent backup exists before a risky operation.
+	// Default: The BackupStale condition isn't reported.
+	StaleThreshold *metav1.Duration `json:"staleThreshold,omitempty"`
+	// DisruptionPolicy can optionally be set to hold back operations that
+	// could lose data unless the affected shards have a recent backup.
+	// Default: Such operations don't check for backups.
+	DisruptionPolicy *VitessBackupDisruptionPolicy `json:"disruptionPolicy,omitempty"`
+	// MaxConcurrentRestores can optionally be set to limit how many tablets
+	// in the cluster may be starting up at once, since a new tablet restores
+	// the latest backup of its shard. This protects the backup storage and
+	// the network when many tablets are recreated at once, like after the
+	// loss of a zone. Tablet Pods beyond the limit are only created once
+	// others become Ready, and each shard reports how many are queued.
+	//
+	// Tablets count against the limit while they aren't Ready, whether or
+	// not they're restoring, and only shards that have a backup wait for
+	// the limit. Shards check the limit independently, so it may briefly be
+	// exceeded by a few tablets.
+	// Default: Tablet Pods are created without waiting.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentRestores *int32 `json:"maxConcurrentRestores,omitempty"`
+	// Vtbackup can optionally be used to tune the Pods that run vtbackup to
+	// take backups without a serving tablet.
+	// Default: vtbackup Pods are shaped like tablets of the shard's first
+	// tablet pool.
+	Vtbackup *VtbackupSpec `json:"vtbackup,omitempty"`
+}
+
+// VtbackupSpec tunes the Pods that run vtbackup. Since vtbackup restores the
+// latest backup, catches up on replication and takes a new backup, large
+// shards may need more resources than a tablet, or dedicated Nodes.
+type VtbackupSpec struct {
+	// Resources specify the compute resources of the vtbackup container.
+	// Default: The resources of the mysqld container of a tablet in the
+	// shard's first tablet pool.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector can optionally be used to schedule vtbackup Pods only on
+	// Nodes with these labels.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity allows you to set rules that constrain the scheduling of
+	// vtbackup Pods.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations allow you to schedule vtbackup Pods onto Nodes with
+	// matching taints.
+	// Default: The tolerations of the shard's first tablet pool.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Concurrency is how many files vtbackup backs up or restores at once
+	// with the builtin backup engine.
+	// Default: 10
+	// +kubebuilder:validation:Minimum=1
+	Concurrency *int32 `json:"concurrency,omitempty"`
+
+	// XtrabackupStripes is how many stripes the xtrabackup engine splits
+	// each backup into, so they can be uploaded and downloaded in parallel.
+	// Default: The stripes set in xtrabackup, or 8.
+	// +kubebuilder:validation:Minimum=1
+	XtrabackupStripes *int32 `json:"xtrabackupStripes,omitempty"`
+
+	// XtrabackupParallelism is how many threads xtrabackup uses to copy
+	// data files, both when taking a backup and when restoring one.
+	// Default: The number of CPUs requested for the vtbackup container.
+	// +kubebuilder:validation:Minimum=1
+	XtrabackupParallelism *int32 `json:"xtrabackupParallelism,omitempty"`
+
+	// Timeout is how long vtbackup may run before it gives up on a backup.
+	// Default: The vtbackup default of 2 hours.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// VitessBackupDisruptionPolicy holds back disruptive operations on a shard
+// until it has a recent backup. It applies to deleting tablet pools, turning
+// down shards, and MySQL major version upgrades.
+//
+// A shard can be exempted by annotating its VitessShard with
+// planetscale.com/skip-backup-gate.
+type VitessBackupDisruptionPolicy struct {
+	// MaxAge is how long ago the latest complete backup of a shard may have
+	// started for disruptive operations to go ahead.
+	MaxAge metav1.Duration `json:"maxAge"`
 }
 
 // VitessBackupEngine is the backup implementation to use.
@@ -320,6 +1210,91 @@ const (
 	VitessBackupEngineXtraBackup VitessBackupEngine = "xtrabackup"
 )
 
+// XtrabackupSpec tunes the xtrabackup backup engine.
+type XtrabackupSpec struct {
+	// Stripes is how many stripes each backup is split into, so they can be
+	// uploaded and downloaded in parallel. Changing it only affects new
+	// backups; restores read the stripe count from each backup's manifest.
+	// Default: 8
+	// +kubebuilder:validation:Minimum=1
+	Stripes *int32 `json:"stripes,omitempty"`
+
+	// StripeBlockSize is the size in bytes of the blocks that are
+	// distributed among stripes.
+	// Default: The Vitess default of 102400.
+	// +kubebuilder:validation:Minimum=1024
+	StripeBlockSize *int32 `json:"stripeBlockSize,omitempty"`
+
+	// BackupThreads is how many threads xtrabackup uses when a serving
+	// tablet takes a backup. vtbackup Pods aren't serving, so they use all
+	// their CPUs instead.
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	BackupThreads *int32 `json:"backupThreads,omitempty"`
+
+	// Compression chooses how backups are compressed with the xtrabackup
+	// engine, instead of the compression set for all engines.
+	// Default: The compression set for all engines.
+	Compression *BackupCompressionSpec `json:"compression,omitempty"`
+}
+
+// BackupCompressionSpec chooses how backups are compressed.
+type BackupCompressionSpec struct {
+	// Engine is the compressor to use. Engines other than pgzip need Vitess
+	// v15 or later. With "external", the compressor and decompressor
+	// commands must be available in the mysqld image.
+	// Default: pgzip
+	// +kubebuilder:validation:Enum=pgzip;pargzip;zstd;lz4;external
+	Engine BackupCompressionEngine `json:"engine,omitempty"`
+
+	// Level is the compression level, whose meaning depends on the engine.
+	// Default: The engine's default level.
+	Level *int32 `json:"level,omitempty"`
+
+	// ExternalCompressor is the command that compresses standard input to
+	// standard output, when the engine is external. For example: "zstd -T0".
+	ExternalCompressor string `json:"externalCompressor,omitempty"`
+
+	// ExternalDecompressor is the command that decompresses standard input
+	// to standard output, when the engine is external. For example: "zstd -d".
+	ExternalDecompressor string `json:"externalDecompressor,omitempty"`
+
+	// ExternalExtension is the file extension of compressed files, when the
+	// engine is external. For example: ".zst".
+	ExternalExtension string `json:"externalExtension,omitempty"`
+}
+
+// BackupCompressionEngine is a compressor for backups.
+type BackupCompressionEngine string
+
+const (
+	// PgzipBackupCompression compresses with parallel gzip.
+	PgzipBackupCompression BackupCompressionEngine = "pgzip"
+	// PargzipBackupCompression compresses with another parallel gzip
+	// implementation.
+	PargzipBackupCompression BackupCompressionEngine = "pargzip"
+	// ZstdBackupCompression compresses with zstd.
+	ZstdBackupCompression BackupCompressionEngine = "zstd"
+	// Lz4BackupCompression compresses with lz4.
+	Lz4BackupCompression BackupCompressionEngine = "lz4"
+	// ExternalBackupCompression compresses with external commands.
+	ExternalBackupCompression BackupCompressionEngine = "external"
+)
+
+// VitessExternalCell is a Vitess cell that's deployed outside of Kubernetes.
+type VitessExternalCell struct {
+	// Name is the cell name as it's known to Vitess.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=^[A-Za-z0-9]([_.A-Za-z0-9]*[A-Za-z0-9])?$
+	Name string `json:"name"`
+
+	// Lockserver is the cell-local lockserver. If it's set, the operator
+	// registers the cell in the global lockserver. Otherwise, the cell must be
+	// registered by whatever deploys it.
+	Lockserver *VitessLockserverParams `json:"lockserver,omitempty"`
+}
+
 // LockserverSpec specifies either a deployed or external lockserver,
 // which can be either global or local.
 type LockserverSpec struct {
@@ -346,12 +1321,61 @@ type LockserverStatus struct {
 // to connect to a given lockserver.
 type VitessLockserverParams struct {
 	// Implementation specifies which Vitess "topo" plugin to use.
+	// Supported values are "etcd2", "zk2" (ZooKeeper), "consul", and "k8s".
 	Implementation string `json:"implementation"`
-	// Address is the host:port of the lockserver client endpoint.
+	// Address is the lockserver client endpoint. Its format depends on the
+	// implementation:
+	//
+	// etcd2: A comma-separated list of host:port etcd client endpoints.
+	//
+	// zk2: A comma-separated list of host:port ZooKeeper servers.
+	//
+	// consul: The host:port of a Consul agent.
 	Address string `json:"address"`
 	// RootPath is a path prefix for all lockserver data belonging to a given Vitess cluster.
 	// Multiple Vitess clusters can share a lockserver as long as they have unique root paths.
+	//
+	// For zk2, this is a ZooKeeper path, such as "/vitess/global".
+	// For consul, this is a key prefix without a leading slash, such as "vitess/global".
 	RootPath string `json:"rootPath"`
+	// TLS configures Vitess components to connect to the lockserver over
+	// TLS with a client certificate. This is supported for etcd2 and zk2.
+	//
+	// Vitess only supports one TLS configuration per process, so the same
+	// settings are also used to connect to any cell-local lockservers.
+	//
+	// For etcd2, the operator also loads the files from the referenced
+	// Secrets for its own connections, so each SecretSource must set 'name'
+	// rather than 'volumeName'. For zk2, the operator uses the settings from
+	// its own --topo_zk_tls_* flags.
+	TLS *VitessLockserverTLSSpec `json:"tls,omitempty"`
+	// AuthSecret loads lockserver credentials for Vitess components from a
+	// given key in a given Secret. The format depends on the implementation:
+	//
+	// zk2: A "<scheme>:<auth>" string, such as "digest:user:password".
+	//
+	// consul: A JSON object that maps each cell name (including "global")
+	// to an object with an "acl_token" field.
+	//
+	// The etcd2 plugin doesn't support username/password authentication,
+	// so use TLS client certificates instead.
+	//
+	// The operator itself authenticates with the file given in its own
+	// --topo_zk_auth_file or --consul_auth_static_file flag, since Vitess
+	// only reads these settings process-wide.
+	AuthSecret *SecretSource `json:"authSecret,omitempty"`
+}
+
+// VitessLockserverTLSSpec configures TLS for connections to a lockserver.
+type VitessLockserverTLSSpec struct {
+	// ClientCertSecret loads the TLS client certificate PEM file from a given key in a given Secret.
+	ClientCertSecret SecretSource `json:"clientCertSecret"`
+	// ClientKeySecret loads the TLS client key PEM file from a given key in a given Secret.
+	ClientKeySecret SecretSource `json:"clientKeySecret"`
+	// CACertSecret loads the certificate authority PEM file used to verify
+	// the lockserver's certificate from a given key in a given Secret.
+	// Default: Use the system's trusted certificate authorities.
+	CACertSecret *SecretSource `json:"caCertSecret,omitempty"`
 }
 
 // VitessDashboardSpec specifies deployment parameters for vtctld.
@@ -362,6 +1386,11 @@ type VitessDashboardSpec struct {
 	Cells []string `json:"cells,omitempty"`
 
 	// Replicas is the number of vtctld instances to deploy in each cell.
+	//
+	// Any vtctld can serve any request, since they coordinate through locks
+	// in the topology service, so no leader election is needed. If there are
+	// two or more vtctlds in total, a PodDisruptionBudget keeps at least one
+	// of them available during voluntary disruptions.
 	Replicas *int32 `json:"replicas,omitempty"`
 
 	// Resources determines the compute resources reserved for each vtctld replica.
@@ -425,12 +1454,43 @@ type VitessDashboardSpec struct {
 	// Service can optionally be used to customize the vtctld Service.
 	Service *ServiceOverrides `json:"service,omitempty"`
 
+	// GRPC can optionally be used to deploy a dedicated Service for the
+	// vtctld gRPC API, and to secure that API with TLS.
+	GRPC *VtctldGRPCSpec `json:"grpc,omitempty"`
+
 	// Tolerations allow you to schedule pods onto nodes with matching taints.
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
+// VtctldGRPCSpec configures the vtctld gRPC API.
+type VtctldGRPCSpec struct {
+	// Service can optionally be used to customize the vtctld gRPC Service.
+	// The Service only exposes the gRPC port, and only sends traffic to
+	// vtctlds that are ready, so clients like vtctldclient can use it as a
+	// stable address.
+	Service *ServiceOverrides `json:"service,omitempty"`
+
+	// TLS can optionally be used to make vtctld serve gRPC over TLS.
+	//
+	// This applies to every gRPC client of vtctld, including vtadmin, which
+	// doesn't support connecting to vtctld over TLS.
+	TLS *VtctldGRPCTLSSpec `json:"tls,omitempty"`
+}
+
+// VtctldGRPCTLSSpec configures TLS for the vtctld gRPC API.
+type VtctldGRPCTLSSpec struct {
+	// CertSecret configures vtctld to load the TLS cert PEM file from a given key in a given Secret.
+	CertSecret SecretSource `json:"certSecret"`
+	// KeySecret configures vtctld to load the TLS key PEM file from a given key in a given Secret.
+	KeySecret SecretSource `json:"keySecret"`
+	// ClientCACertSecret configures vtctld to load the TLS certificate authority PEM file from a given key in a given Secret.
+	// If specified, vtctld requires clients to present certificates signed by this CA.
+	// Optional.
+	ClientCACertSecret *SecretSource `json:"clientCACertSecret,omitempty"`
+}
+
 // VtAdminSpec specifies deployment parameters for vtadmin.
 type VtAdminSpec struct {
 	// Rbac contains the rbac config file for vtadmin.
@@ -548,6 +1608,9 @@ type VitessDashboardStatus struct {
 	Available corev1.ConditionStatus `json:"available,omitempty"`
 	// ServiceName is the name of the Service for this cluster's vtctld.
 	ServiceName string `json:"serviceName,omitempty"`
+	// GRPCServiceName is the name of the dedicated Service for the vtctld
+	// gRPC API, if spec.vitessDashboard.grpc is set.
+	GRPCServiceName string `json:"grpcServiceName,omitempty"`
 }
 
 // VtadminStatus is a summary of the status of the vtadmin deployment.
@@ -563,6 +1626,10 @@ type VitessClusterStatus struct {
 	// The generation observed by the controller.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// DryRunChanges lists the changes the operator would make to the
+	// cluster's objects, if spec.dryRun is set.
+	DryRunChanges []string `json:"dryRunChanges,omitempty"`
+
 	// GlobalLockserver is the status of the global lockserver.
 	GlobalLockserver LockserverStatus `json:"globalLockserver,omitempty"`
 
@@ -584,6 +1651,290 @@ type VitessClusterStatus struct {
 	OrphanedCells map[string]OrphanStatus `json:"orphanedCells,omitempty"`
 	// OrphanedKeyspaces is a list of unwanted keyspaces that could not be turned down.
 	OrphanedKeyspaces map[string]OrphanStatus `json:"orphanedKeyspaces,omitempty"`
+
+	// Summary rolls up the status of all cells, keyspaces and shards in the
+	// cluster, so overall health can be seen at a glance.
+	Summary VitessClusterSummary `json:"summary,omitempty"`
+
+	// Rollout summarizes the changes that are waiting to be rolled out,
+	// grouped by component ("vtgate", "vttablet", or "etcd").
+	// Components with nothing pending that haven't been released are omitted.
+	Rollout map[string]VitessClusterRolloutStatus `json:"rollout,omitempty"`
+
+	// RolloutCoordinator reports what the rollout coordinator is doing, if
+	// spec.updateStrategy.coordinator is set.
+	RolloutCoordinator *VitessClusterRolloutCoordinatorStatus `json:"rolloutCoordinator,omitempty"`
+
+	// PendingChanges lists each object in the cluster that has changes
+	// waiting to be rolled out, so you can review what releasing them will
+	// do. At most 100 objects are listed.
+	PendingChanges []VitessClusterPendingChange `json:"pendingChanges,omitempty"`
+
+	// ResolvedImages maps each image in spec.images to the digest it has
+	// been pinned to, if spec.images.resolveTagsToDigests is set.
+	ResolvedImages map[string]string `json:"resolvedImages,omitempty"`
+
+	// PinnedImages maps each image field of the spec that's pinned to a
+	// digest, by its path, like "images.vttablet" or
+	// "keyspaces[commerce].imageOverrides.vttablet", to the image it was last
+	// pinned to. If a new tag can't be resolved, the field keeps that image.
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
+
+	// VersionSkew lists the keyspaces and shards that run different images
+	// than the rest of the cluster because of their imageOverrides.
+	VersionSkew []VitessVersionSkew `json:"versionSkew,omitempty"`
+
+	// Upgrade reports the progress of a staged upgrade, if spec.upgrade is set.
+	Upgrade *VitessClusterUpgradeStatus `json:"upgrade,omitempty"`
+
+	// DiscoveredZones maps the name of each cell with zoneDiscovery to the
+	// zone it was found to be in.
+	DiscoveredZones map[string]string `json:"discoveredZones,omitempty"`
+
+	// ResourceRecommendations reports recommended resources for each tablet
+	// pool, if spec.resourceRecommendations is set.
+	ResourceRecommendations *VitessClusterResourceRecommendations `json:"resourceRecommendations,omitempty"`
+
+	// Conditions contains details for the current condition of this VitessCluster.
+	Conditions []VitessClusterCondition `json:"conditions,omitempty"`
+}
+
+// VitessClusterCondition contains details for the current condition of this VitessCluster.
+type VitessClusterCondition struct {
+	// Type is the type of the condition.
+	Type VitessClusterConditionType `json:"type"`
+	// Status is the status of the condition.
+	// Can be True, False, Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	// Optional.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Unique, one-word, PascalCase reason for the condition's last transition.
+	// Optional.
+	Reason string `json:"reason,omitempty"`
+	// Human-readable message indicating details about last transition.
+	// Optional.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessClusterConditionType is a valid value for the Type of a VitessClusterCondition.
+type VitessClusterConditionType string
+
+// These are valid conditions of VitessCluster.
+const (
+	// VitessClusterZonesDiscovered indicates whether the zone of every cell with zoneDiscovery was discovered this time.
+	VitessClusterZonesDiscovered VitessClusterConditionType = "ZonesDiscovered"
+	// VitessClusterManagedFlagsIgnored is True if extra vtctld or vtgate flags
+	// are left out because the operator manages them, and lists them. It's
+	// only reported while there are such flags. Ignored vttablet and vtorc
+	// flags are reported by the same condition of each VitessShard.
+	VitessClusterManagedFlagsIgnored VitessClusterConditionType = "ManagedFlagsIgnored"
+)
+
+// VitessClusterResourceRecommendations reports how the resources of tablet
+// pools compare with their usage.
+type VitessClusterResourceRecommendations struct {
+	// ObservedSince is when tracking of the current peak usage started.
+	ObservedSince *metav1.Time `json:"observedSince,omitempty"`
+	// LastSampleTime is when usage was last read.
+	LastSampleTime *metav1.Time `json:"lastSampleTime,omitempty"`
+	// Message explains why usage couldn't be read the last time, if it
+	// couldn't.
+	Message string `json:"message,omitempty"`
+	// Pools lists the recommendations for each tablet pool.
+	Pools []VitessTabletPoolRecommendation `json:"pools,omitempty"`
+}
+
+// VitessTabletPoolRecommendation is the resource recommendation for the
+// tablets of one pool, across all shards of the keyspace.
+type VitessTabletPoolRecommendation struct {
+	// Keyspace is the name of the keyspace.
+	Keyspace string `json:"keyspace"`
+	// Cell is the name of the cell.
+	Cell string `json:"cell"`
+	// Type is the tablet type of the pool.
+	Type VitessTabletPoolType `json:"type"`
+	// Name is the name of the pool, if it has one.
+	Name string `json:"name,omitempty"`
+	// Containers lists the recommendation for each container of the tablets.
+	Containers []VitessContainerRecommendation `json:"containers,omitempty"`
+}
+
+// VitessContainerRecommendation compares the resources of a container with
+// its usage.
+type VitessContainerRecommendation struct {
+	// Name is the name of the container, like "vttablet" or "mysqld".
+	Name string `json:"name"`
+	// Requests are the largest CPU and memory requests of the container
+	// among the tablets of the pool.
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// Limits are the largest CPU and memory limits of the container among
+	// the tablets of the pool.
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+	// PeakUsage is the highest CPU and memory usage of the container seen in
+	// any tablet of the pool since observedSince.
+	PeakUsage corev1.ResourceList `json:"peakUsage,omitempty"`
+	// RecommendedRequests are the peak usage plus headroom.
+	RecommendedRequests corev1.ResourceList `json:"recommendedRequests,omitempty"`
+}
+
+// VitessVersionSkew describes a keyspace or shard whose images differ from
+// the cluster-level images.
+type VitessVersionSkew struct {
+	// Keyspace is the name of the keyspace.
+	Keyspace string `json:"keyspace"`
+	// Shard is the name of the shard, if only that shard has overrides
+	// that differ from the rest of its keyspace.
+	Shard string `json:"shard,omitempty"`
+	// Images lists only the images that differ from the cluster-level images.
+	Images VitessKeyspaceImages `json:"images"`
+}
+
+// VitessClusterUpgradeStatus is the progress of a staged upgrade.
+type VitessClusterUpgradeStatus struct {
+	// Images are the images the operator has rolled out so far. Once the
+	// upgrade is complete, they're the same as spec.images.
+	Images VitessImages `json:"images,omitempty"`
+	// Stage is the stage currently being upgraded, or empty if there's no
+	// upgrade in progress.
+	Stage VitessUpgradeStage `json:"stage,omitempty"`
+	// Message explains what the upgrade is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessClusterRolloutStatus summarizes the changes to one component of a
+// VitessCluster that are waiting to be rolled out.
+type VitessClusterRolloutStatus struct {
+	// PendingObjects is the number of objects belonging to the component that
+	// have pending changes: VitessCells for vtgate; VitessKeyspaces,
+	// VitessShards and tablet Pods for vttablet; and member Pods for etcd.
+	PendingObjects int32 `json:"pendingObjects,omitempty"`
+	// Released indicates that the component is listed in the
+	// 'rollout.planetscale.com/released-components' annotation on the
+	// VitessCluster, so its pending changes are rolled out without waiting
+	// for an external tool.
+	Released bool `json:"released,omitempty"`
+	// Pods is the number of Pods belonging to the component: vtgate Pods for
+	// vtgate, and tablet Pods for vttablet. It's only reported for those two
+	// components.
+	Pods int32 `json:"pods,omitempty"`
+	// UpdatedPods is the number of those Pods that have no changes waiting
+	// to be rolled out.
+	UpdatedPods int32 `json:"updatedPods,omitempty"`
+	// Progress is a human-readable summary of how many Pods are updated,
+	// such as "3/10".
+	Progress string `json:"progress,omitempty"`
+}
+
+// RolloutCoordinatorPhase is the state of the rollout coordinator.
+type RolloutCoordinatorPhase string
+
+const (
+	// IdleRolloutCoordinatorPhase means there are no changes to roll out.
+	IdleRolloutCoordinatorPhase RolloutCoordinatorPhase = "Idle"
+	// ProgressingRolloutCoordinatorPhase means changes are being rolled out.
+	ProgressingRolloutCoordinatorPhase RolloutCoordinatorPhase = "Progressing"
+	// PausedRolloutCoordinatorPhase means there are changes to roll out,
+	// but spec.updateStrategy.coordinator.paused is set.
+	PausedRolloutCoordinatorPhase RolloutCoordinatorPhase = "Paused"
+	// AbortedRolloutCoordinatorPhase means there are changes to roll out,
+	// but spec.updateStrategy.coordinator.abort is set.
+	AbortedRolloutCoordinatorPhase RolloutCoordinatorPhase = "Aborted"
+	// HaltedRolloutCoordinatorPhase means the rollout was stopped because
+	// too many updated tablets are unhealthy.
+	HaltedRolloutCoordinatorPhase RolloutCoordinatorPhase = "Halted"
+)
+
+// VitessClusterRolloutCoordinatorStatus is the progress of the rollout
+// coordinator.
+type VitessClusterRolloutCoordinatorStatus struct {
+	// Phase is the overall state of the rollout.
+	Phase RolloutCoordinatorPhase `json:"phase,omitempty"`
+	// Stage is the stage currently being rolled out, or empty if there's
+	// nothing to roll out.
+	Stage RolloutCoordinatorStage `json:"stage,omitempty"`
+	// Cells lists the cells whose changes are being rolled out right now.
+	Cells []string `json:"cells,omitempty"`
+	// Keyspaces lists the keyspaces whose changes are being rolled out
+	// right now.
+	Keyspaces []string `json:"keyspaces,omitempty"`
+	// CompletedObjects is the number of cells or keyspaces in the current
+	// stage that have no changes left to roll out.
+	CompletedObjects int32 `json:"completedObjects,omitempty"`
+	// TotalObjects is the number of cells or keyspaces in the current stage.
+	TotalObjects int32 `json:"totalObjects,omitempty"`
+	// Message explains what the rollout is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+	// HaltedGeneration is the generation of the VitessCluster whose rollout
+	// was halted because of unhealthy tablets. The rollout stays halted
+	// until the VitessCluster spec changes again.
+	HaltedGeneration int64 `json:"haltedGeneration,omitempty"`
+	// UnhealthyTablets lists the updated tablets that were unhealthy when
+	// the rollout was halted.
+	UnhealthyTablets []string `json:"unhealthyTablets,omitempty"`
+}
+
+// VitessClusterPendingChange describes the changes waiting to be rolled out
+// to one object.
+type VitessClusterPendingChange struct {
+	// Kind is the kind of the object, such as Pod or VitessShard.
+	Kind string `json:"kind"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	// Fields are the paths of the fields that will change.
+	Fields []string `json:"fields,omitempty"`
+	// Recreate indicates that the object will be deleted and recreated to
+	// apply the changes, rather than updated in place.
+	Recreate bool `json:"recreate,omitempty"`
+	// RecreateReason explains why the object needs to be recreated.
+	RecreateReason string `json:"recreateReason,omitempty"`
+	// Released indicates that the changes have been released, and will be
+	// applied as soon as it's safe to do so.
+	Released bool `json:"released,omitempty"`
+}
+
+// VitessClusterSummary is a roll-up of the status of everything in a cluster.
+type VitessClusterSummary struct {
+	// Cells is a human-readable count of cells serving traffic, in the form
+	// "<serving>/<desired>". A cell is serving if its vtgate is available.
+	Cells string `json:"cells,omitempty"`
+	// ServingCells is the number of cells whose vtgate is available.
+	ServingCells int32 `json:"servingCells"`
+	// DesiredCells is the number of desired cells.
+	DesiredCells int32 `json:"desiredCells"`
+
+	// Primaries is a human-readable count of shards with a primary tablet,
+	// in the form "<with primary>/<shards>".
+	Primaries string `json:"primaries,omitempty"`
+	// ShardsWithPrimary is the number of observed shards that have a primary.
+	ShardsWithPrimary int32 `json:"shardsWithPrimary"`
+	// ShardsWithoutPrimary is the number of observed shards that are known
+	// not to have a primary, or for which it's unknown.
+	ShardsWithoutPrimary int32 `json:"shardsWithoutPrimary"`
+
+	// Tablets is a human-readable count of ready tablets, in the form
+	// "<ready>/<desired>".
+	Tablets string `json:"tablets,omitempty"`
+	// DesiredTablets is the total number of desired tablets.
+	DesiredTablets int32 `json:"desiredTablets"`
+	// ReadyTablets is the total number of desired tablets that are Ready.
+	ReadyTablets int32 `json:"readyTablets"`
+	// TabletsNotReady is the number of desired tablets that are not Ready,
+	// including those that don't exist yet.
+	TabletsNotReady int32 `json:"tabletsNotReady"`
+
+	// PendingChanges is the number of cells, keyspaces and shards that have
+	// changes waiting for a rolling update.
+	PendingChanges int32 `json:"pendingChanges"`
+
+	// ShardsWithoutBackup is the number of observed shards for which no
+	// complete backup was found.
+	ShardsWithoutBackup int32 `json:"shardsWithoutBackup"`
+	// OldestLatestBackupTime is the oldest among the latest complete backup
+	// times of each shard that has any backups. In other words, every shard
+	// with backups has at least one that is no older than this.
+	OldestLatestBackupTime *metav1.Time `json:"oldestLatestBackupTime,omitempty"`
 }
 
 // NewVitessClusterStatus creates a new status object with default values.
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+//
+// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessClusterFederation defines one Vitess cluster that spans several
+// Kubernetes clusters, like one per region.
+//
+// The same VitessClusterFederation is created in every Kubernetes cluster.
+// The operator in each one is a member of the federation, named by its
+// --federation_member flag, and deploys only the cells assigned to that
+// member, in a VitessCluster with the same name as the VitessClusterFederation.
+// All the members share the global lockserver, and with it the keyspaces and
+// shards defined in the cluster template.
+// +kubebuilder:resource:path=vitessclusterfederations,shortName=vtf
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Member",type=string,JSONPath=`.status.member`
+// +kubebuilder:printcolumn:name="Cells",type=string,JSONPath=`.status.cells`
+type VitessClusterFederation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VitessClusterFederationSpec   `json:"spec,omitempty"`
+	Status VitessClusterFederationStatus `json:"status,omitempty"`
+}
+
+// VitessClusterFederationSpec defines the desired state of a VitessClusterFederation.
+type VitessClusterFederationSpec struct {
+	// GlobalLockserver is the lockserver that all members share. It must be
+	// reachable from every member's Kubernetes cluster, so it's never
+	// deployed by the operator. The globalLockserver of the cluster template
+	// is ignored.
+	GlobalLockserver VitessLockserverParams `json:"globalLockserver"`
+
+	// Members lists the operator instances that deploy the cluster, and
+	// which cells each of them deploys. Each cell may belong to only one
+	// member. Cells of the cluster template that don't belong to any member
+	// aren't deployed.
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	// +kubebuilder:validation:MinItems=1
+	Members []VitessClusterFederationMember `json:"members" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// TopologyOwner is the name of the member that performs the actions
+	// which change the shared state of keyspaces and shards in the global
+	// lockserver, like electing a shard's first primary, reparenting out of
+	// evacuated cells, managing MySQL users, and resharding. The other
+	// members leave those to it, so they don't race each other. See
+	// manageShards in topologyReconciliation.
+	// Default: The first member listed.
+	TopologyOwner string `json:"topologyOwner,omitempty"`
+
+	// Cluster is the template for the VitessCluster of each member. Its cells
+	// and keyspaces span all the members: each member's VitessCluster gets
+	// only that member's cells, and only the tablet pools in those cells.
+	//
+	// The members take care of the cells aliases and the pruning of cells in
+	// the global lockserver together, so the registerCellsAliases and
+	// pruneCells settings of topologyReconciliation apply to the federation
+	// as a whole. Unless it's set, the cellsToWatch of each cell's gateway
+	// lists all the cells of all the members.
+	Cluster VitessClusterSpec `json:"cluster"`
+}
+
+// VitessClusterFederationMember is an operator instance that deploys some of
+// the cells of a VitessClusterFederation.
+type VitessClusterFederationMember struct {
+	// Name identifies the member. It must match the --federation_member flag
+	// of the operator that deploys this member's cells.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Cells lists the names of the cells in the cluster template that this
+	// member deploys.
+	// +kubebuilder:validation:MinItems=1
+	Cells []string `json:"cells"`
+}
+
+// VitessClusterFederationStatus describes the observed state of a
+// VitessClusterFederation, as seen by the member in this Kubernetes cluster.
+type VitessClusterFederationStatus struct {
+	// The generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Member is the name of the member in this Kubernetes cluster.
+	Member string `json:"member,omitempty"`
+	// Cells lists the cells that this member deploys.
+	Cells []string `json:"cells,omitempty"`
+	// Message explains why this member doesn't deploy anything, if it doesn't.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessClusterFederationList contains a list of VitessClusterFederations.
+type VitessClusterFederationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VitessClusterFederation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VitessClusterFederation{}, &VitessClusterFederationList{})
+}
@@ -68,4 +68,36 @@ func DefaultVitessKeyspaceImages(dst *VitessKeyspaceImages, clusterDefaults *Vit
 	if dst.MysqldExporter == "" {
 		dst.MysqldExporter = clusterDefaults.MysqldExporter
 	}
+	if dst.Init == "" {
+		dst.Init = clusterDefaults.Init
+	}
+	if dst.Debug == "" {
+		dst.Debug = clusterDefaults.Debug
+	}
+}
+
+// DefaultVitessShardImages fills in unspecified shard-level images from
+// the images in effect for the keyspace.
+func DefaultVitessShardImages(dst *VitessKeyspaceImages, keyspaceImages *VitessKeyspaceImages) {
+	if dst.Vttablet == "" {
+		dst.Vttablet = keyspaceImages.Vttablet
+	}
+	if dst.Vtorc == "" {
+		dst.Vtorc = keyspaceImages.Vtorc
+	}
+	if dst.Vtbackup == "" {
+		dst.Vtbackup = keyspaceImages.Vtbackup
+	}
+	if dst.Mysqld == nil {
+		dst.Mysqld = keyspaceImages.Mysqld
+	}
+	if dst.MysqldExporter == "" {
+		dst.MysqldExporter = keyspaceImages.MysqldExporter
+	}
+	if dst.Init == "" {
+		dst.Init = keyspaceImages.Init
+	}
+	if dst.Debug == "" {
+		dst.Debug = keyspaceImages.Debug
+	}
 }
@@ -32,11 +32,50 @@ import (
 
 // KeyRanges returns the set of ranges for an equal partitioning.
 func (p *VitessKeyspaceEqualPartitioning) KeyRanges() []VitessKeyRange {
+	return EqualKeyRanges(p.Parts)
+}
+
+// KeyRangeProblem returns a description of why the key ranges of the shards in
+// a custom partitioning don't exactly cover the keyspace, or an empty string
+// if they do.
+func (p *VitessKeyspaceCustomPartitioning) KeyRangeProblem() string {
+	keyRanges := make([]VitessKeyRange, 0, len(p.Shards))
+	for i := range p.Shards {
+		keyRanges = append(keyRanges, p.Shards[i].KeyRange)
+	}
+	return KeyRangesProblem(keyRanges)
+}
+
+// ExpandEqualShards adds a shard configured with ShardTemplate to the Shards
+// list for each key range generated by EqualShards that's not listed yet.
+func (p *VitessKeyspaceCustomPartitioning) ExpandEqualShards() {
+	if p.EqualShards < 1 {
+		return
+	}
+	listed := make(map[VitessKeyRange]bool, len(p.Shards))
+	for i := range p.Shards {
+		listed[p.Shards[i].KeyRange] = true
+	}
+	for _, keyRange := range EqualKeyRanges(p.EqualShards) {
+		if listed[keyRange] {
+			continue
+		}
+		shard := VitessKeyspaceKeyRangeShard{KeyRange: keyRange}
+		if p.ShardTemplate != nil {
+			shard.VitessShardTemplate = *p.ShardTemplate.DeepCopy()
+		}
+		p.Shards = append(p.Shards, shard)
+	}
+}
+
+// EqualKeyRanges returns the key ranges of a given number of roughly
+// equal-sized shards that together cover the whole keyspace.
+func EqualKeyRanges(parts int32) []VitessKeyRange {
 	// Invariant: number of parts must be between 1-65536. This is enforced via
 	// the CRD.
-	ranges, err := key.GenerateShardRanges(int(p.Parts))
+	ranges, err := key.GenerateShardRanges(int(parts))
 	if err != nil {
-		panic(fmt.Sprintf("could not generate shard range with %d parts: %v", p.Parts, err))
+		panic(fmt.Sprintf("could not generate shard range with %d parts: %v", parts, err))
 	}
 
 	out := make([]VitessKeyRange, 0, len(ranges))
@@ -64,6 +103,35 @@ func (v *VitessKeyRange) FillFromByteKeyRange(kr topodatapb.KeyRange) {
 	v.End = hex.EncodeToString(kr.End)
 }
 
+// DurabilityPolicyProblem returns a description of why the tablet pools of
+// some shard can't satisfy the keyspace's durability policy, or an empty
+// string if they all can.
+func (spec *VitessKeyspaceTemplate) DurabilityPolicyProblem() string {
+	for _, shard := range spec.ShardTemplates() {
+		cells := sets.NewString()
+		masterEligible := int32(0)
+		for i := range shard.TabletPools {
+			pool := &shard.TabletPools[i]
+			if pool.Replicas > 0 && (pool.Type == ReplicaPoolType || pool.Type == ExternalMasterPoolType) {
+				cells.Insert(pool.Cell)
+				masterEligible += pool.Replicas
+			}
+		}
+
+		switch spec.DurabilityPolicy {
+		case SemiSyncDurabilityPolicy:
+			if masterEligible < 2 {
+				return fmt.Sprintf("shard %v needs at least 2 master-eligible tablets for durability policy %v", shard.KeyRange.String(), spec.DurabilityPolicy)
+			}
+		case CrossCellDurabilityPolicy:
+			if cells.Len() < 2 {
+				return fmt.Sprintf("shard %v needs master-eligible tablets in at least 2 cells for durability policy %v", shard.KeyRange.String(), spec.DurabilityPolicy)
+			}
+		}
+	}
+	return ""
+}
+
 // ShardTemplates returns a list of shards to satisfy all partitionings defined in the keyspace.
 // The list is returned in sorted order for determinism.
 func (spec *VitessKeyspaceTemplate) ShardTemplates() []*VitessKeyspaceKeyRangeShard {
@@ -291,3 +359,12 @@ func (s *VitessKeyspaceStatus) setCondition(newCondition *VitessKeyspaceConditio
 	// We got here so we didn't return early by finding the condition already existing. We'll just append to the end.
 	s.Conditions = append(s.Conditions, *newCondition)
 }
+
+// InitImage returns the image to use for the init containers of a Pod whose
+// main Vitess container runs mainImage.
+func (images *VitessKeyspaceImages) InitImage(mainImage string) string {
+	if images.Init != "" {
+		return images.Init
+	}
+	return mainImage
+}
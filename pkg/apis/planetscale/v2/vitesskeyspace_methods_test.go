@@ -132,3 +132,109 @@ func TestVitessKeyspacePartitioningTotalReplicas(t *testing.T) {
 		t.Errorf("customPartitioning.TotalReplicas() = %v; want 6", got)
 	}
 }
+
+func TestDurabilityPolicyProblem(t *testing.T) {
+	pools := func(pools ...VitessShardTabletPool) VitessKeyspaceTemplate {
+		return VitessKeyspaceTemplate{
+			Partitionings: []VitessKeyspacePartitioning{
+				{
+					Equal: &VitessKeyspaceEqualPartitioning{
+						Parts:         2,
+						ShardTemplate: VitessShardTemplate{TabletPools: pools},
+					},
+				},
+			},
+		}
+	}
+	oneCell := pools(
+		VitessShardTabletPool{Cell: "cell1", Type: ReplicaPoolType, Replicas: 2},
+		VitessShardTabletPool{Cell: "cell2", Type: RdonlyPoolType, Replicas: 2},
+	)
+	twoCells := pools(
+		VitessShardTabletPool{Cell: "cell1", Type: ReplicaPoolType, Replicas: 1},
+		VitessShardTabletPool{Cell: "cell2", Type: ReplicaPoolType, Replicas: 1},
+	)
+	lone := pools(
+		VitessShardTabletPool{Cell: "cell1", Type: ReplicaPoolType, Replicas: 1},
+		VitessShardTabletPool{Cell: "cell1", Type: RdonlyPoolType, Replicas: 3},
+	)
+
+	table := []struct {
+		name    string
+		spec    VitessKeyspaceTemplate
+		policy  string
+		problem bool
+	}{
+		{"unset", lone, "", false},
+		{"none", lone, NoneDurabilityPolicy, false},
+		{"semi_sync with lone master", lone, SemiSyncDurabilityPolicy, true},
+		{"semi_sync in one cell", oneCell, SemiSyncDurabilityPolicy, false},
+		{"cross_cell in one cell", oneCell, CrossCellDurabilityPolicy, true},
+		{"cross_cell in two cells", twoCells, CrossCellDurabilityPolicy, false},
+	}
+
+	for _, test := range table {
+		test.spec.DurabilityPolicy = test.policy
+		if got := test.spec.DurabilityPolicyProblem(); (got != "") != test.problem {
+			t.Errorf("%s: DurabilityPolicyProblem() = %q; want problem: %v", test.name, got, test.problem)
+		}
+	}
+}
+
+func TestKeyRangesProblem(t *testing.T) {
+	table := []struct {
+		keyRanges []VitessKeyRange
+		problem   bool
+	}{
+		{EqualKeyRanges(1), false},
+		{EqualKeyRanges(3), false},
+		{[]VitessKeyRange{{Start: "80"}, {End: "80"}}, false},
+		{nil, true},
+		{[]VitessKeyRange{{End: "40"}, {Start: "80"}}, true},
+		{[]VitessKeyRange{{End: "80"}, {Start: "40"}}, true},
+		{[]VitessKeyRange{{End: "80"}, {Start: "80", End: "c0"}}, true},
+		{[]VitessKeyRange{{End: "80"}, {Start: "80", End: "80"}, {Start: "80"}}, true},
+		{[]VitessKeyRange{{}, {Start: "80"}}, true},
+		// Bounds that only differ by trailing zero bytes are equal.
+		{[]VitessKeyRange{{End: "80"}, {Start: "8000"}}, false},
+		{[]VitessKeyRange{{End: "8000"}, {Start: "80", End: "c0"}, {Start: "c000"}}, false},
+		{[]VitessKeyRange{{Start: "00", End: "80"}, {Start: "80"}}, false},
+		{[]VitessKeyRange{{End: "80"}, {Start: "8001"}}, true},
+		{[]VitessKeyRange{{End: "8000"}, {Start: "80", End: "80"}, {Start: "80"}}, true},
+		{[]VitessKeyRange{{End: "0z"}, {Start: "80"}}, true},
+	}
+
+	for _, test := range table {
+		if got := KeyRangesProblem(test.keyRanges); (got != "") != test.problem {
+			t.Errorf("KeyRangesProblem(%v) = %q; want problem: %v", test.keyRanges, got, test.problem)
+		}
+	}
+}
+
+func TestExpandEqualShards(t *testing.T) {
+	p := &VitessKeyspaceCustomPartitioning{
+		Shards: []VitessKeyspaceKeyRangeShard{
+			{KeyRange: VitessKeyRange{End: "80"}, VitessShardTemplate: VitessShardTemplate{DatabaseInitScriptSecret: SecretSource{Name: "custom"}}},
+		},
+		EqualShards:   2,
+		ShardTemplate: &VitessShardTemplate{DatabaseInitScriptSecret: SecretSource{Name: "template"}},
+	}
+	p.ExpandEqualShards()
+	p.ExpandEqualShards()
+
+	if got, want := len(p.Shards), 2; got != want {
+		t.Fatalf("len(Shards) = %v; want %v", got, want)
+	}
+	if got, want := p.Shards[0].DatabaseInitScriptSecret.Name, "custom"; got != want {
+		t.Errorf("listed shard's init script secret = %q; want %q", got, want)
+	}
+	if got, want := p.Shards[1].KeyRange, (VitessKeyRange{Start: "80"}); got != want {
+		t.Errorf("generated shard's key range = %v; want %v", got, want)
+	}
+	if got, want := p.Shards[1].DatabaseInitScriptSecret.Name, "template"; got != want {
+		t.Errorf("generated shard's init script secret = %q; want %q", got, want)
+	}
+	if problem := p.KeyRangeProblem(); problem != "" {
+		t.Errorf("KeyRangeProblem() = %q; want none", problem)
+	}
+}
@@ -51,10 +51,10 @@ type VitessKeyspaceSpec struct {
 	// GlobalLockserver are the params to connect to the global lockserver.
 	GlobalLockserver VitessLockserverParams `json:"globalLockserver"`
 
-	// Images are not customizable by users at the keyspace level because version
-	// skew across the cluster is discouraged except during rolling updates,
-	// in which case this field is automatically managed by the VitessCluster
-	// controller that owns this VitessKeyspace.
+	// Images are the images in effect for this keyspace. They're filled in by
+	// the VitessCluster controller from the cluster-level images, with any
+	// imageOverrides of the keyspace applied. During rolling updates, they're
+	// managed by the controller to roll out new images safely.
 	Images VitessKeyspaceImages `json:"images,omitempty"`
 
 	// ImagePullPolicies are inherited from the VitessCluster spec.
@@ -73,14 +73,69 @@ type VitessKeyspaceSpec struct {
 	// BackupEngine specifies the Vitess backup engine to use, either "builtin" or "xtrabackup".
 	BackupEngine VitessBackupEngine `json:"backupEngine,omitempty"`
 
+	// BackupCompression chooses how backups are compressed, as set in the
+	// VitessCluster.
+	BackupCompression *BackupCompressionSpec `json:"backupCompression,omitempty"`
+
+	// BackupXtrabackup tunes the xtrabackup engine, as set in the VitessCluster.
+	BackupXtrabackup *XtrabackupSpec `json:"backupXtrabackup,omitempty"`
+
+	// BackupStaleThreshold is the age after which the latest backup of a
+	// shard is reported as stale, as set in the VitessCluster.
+	BackupStaleThreshold *metav1.Duration `json:"backupStaleThreshold,omitempty"`
+
+	// BackupDisruptionPolicy holds back disruptive operations until a shard
+	// has a recent backup, as set in the VitessCluster.
+	BackupDisruptionPolicy *VitessBackupDisruptionPolicy `json:"backupDisruptionPolicy,omitempty"`
+
+	// MaxConcurrentRestores limits how many tablets in the cluster may be
+	// starting up at once, as set in the VitessCluster.
+	MaxConcurrentRestores *int32 `json:"maxConcurrentRestores,omitempty"`
+
+	// Vtbackup tunes the Pods that run vtbackup, as set in the VitessCluster.
+	Vtbackup *VtbackupSpec `json:"vtbackup,omitempty"`
+
 	// ExtraVitessFlags is inherited from the parent's VitessClusterSpec.
 	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
 
+	// ComponentVitessFlags is inherited from the parent's VitessClusterSpec.
+	ComponentVitessFlags *ComponentVitessFlags `json:"componentVitessFlags,omitempty"`
+
 	// TopologyReconciliation is inherited from the parent's VitessClusterSpec.
 	TopologyReconciliation *TopoReconcileConfig `json:"topologyReconciliation,omitempty"`
 
+	// Networking is inherited from the parent's VitessClusterSpec.
+	Networking *NetworkingSpec `json:"networking,omitempty"`
+
+	// ResourceDefaults is inherited from the parent's VitessClusterSpec.
+	ResourceDefaults *ResourceDefaultsSpec `json:"resourceDefaults,omitempty"`
+
+	// FailoverBuffer is inherited from the parent's VitessClusterSpec.
+	FailoverBuffer *FailoverBufferSpec `json:"failoverBuffer,omitempty"`
+
+	// Evacuation is inherited from the parent's VitessClusterSpec.
+	Evacuation *EvacuationSpec `json:"evacuation,omitempty"`
+
 	// UpdateStrategy is inherited from the parent's VitessClusterSpec.
 	UpdateStrategy *VitessClusterUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// Paused is inherited from the parent's VitessClusterSpec.
+	Paused bool `json:"paused,omitempty"`
+
+	// AdoptExisting is inherited from the parent's VitessClusterSpec.
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// Standby is inherited from the parent's VitessClusterSpec.
+	Standby *VitessClusterStandbySpec `json:"standby,omitempty"`
+
+	// Users is inherited from the parent's VitessClusterSpec.
+	Users []VitessMySQLUser `json:"users,omitempty"`
+
+	// InternalCredentials is inherited from the parent's VitessClusterSpec.
+	InternalCredentials *VitessInternalCredentialsSpec `json:"internalCredentials,omitempty"`
+
+	// DryRun is inherited from the parent's VitessClusterSpec.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // VitessKeyspaceTemplate contains only the user-specified parts of a VitessKeyspace object.
@@ -113,10 +168,71 @@ type VitessKeyspaceTemplate struct {
 	// Default: Add a "vt_" prefix to the keyspace name.
 	DatabaseName string `json:"databaseName,omitempty"`
 
-	// DurabilityPolicy is the name of the durability policy to use for the keyspace.
+	// DurabilityPolicy is the name of the durability policy to use for the
+	// keyspace, which determines which tablets must acknowledge writes with
+	// semi-sync replication before the primary commits them.
+	//
+	// With "semi_sync", each shard needs at least two master-eligible
+	// tablets. With "cross_cell", each shard needs master-eligible tablets in
+	// at least two cells. The operator doesn't apply a policy that the tablet
+	// pools can't satisfy, since the primary would then block all writes.
+	//
+	// Tablets only read the policy when they start up, so changing it rolls
+	// out to tablet Pods like any other change, according to the update
+	// strategy.
+	//
 	// If unspecified, vtop will not set the durability policy.
+	// +kubebuilder:validation:Enum=none;semi_sync;cross_cell
 	DurabilityPolicy string `json:"durabilityPolicy,omitempty"`
 
+	// QueryServing lets you stop certain tablet types from serving queries in
+	// certain cells, for example to drain traffic from a cell before taking it
+	// down for maintenance.
+	//
+	// Removing it restores query service for every tablet type in every
+	// cell. After that, and if it was never specified, vtop leaves the tablet
+	// controls of the keyspace alone, so they can be managed with
+	// `vtctlclient SetShardTabletControl`.
+	QueryServing *VitessKeyspaceQueryServing `json:"queryServing,omitempty"`
+
+	// Reshard lets vtop run a Reshard workflow from one set of shards to
+	// another, for example to merge over-provisioned shards "-40" and
+	// "40-80" into "-80", or to split shards.
+	//
+	// Both sets of shards must be defined by partitionings, so the target
+	// shards are deployed before the workflow starts. Once the complete step
+	// deleted the source shards from topology, vtop turns down their tablets
+	// even though their partitioning is still listed, as long as this field
+	// names the completed workflow. Remove the partitioning before removing
+	// this field, or the source shards are deployed again.
+	//
+	// If unspecified, vtop only reports on resharding workflows that are
+	// run with vtctlclient.
+	Reshard *VitessKeyspaceReshardSpec `json:"reshard,omitempty"`
+
+	// Materialize lets vtop keep tables of this keyspace up to date with
+	// queries on another keyspace through a Materialize workflow, to
+	// maintain copies or rollups of another keyspace's data without
+	// external ETL.
+	//
+	// The workflow is created once every shard of this keyspace has a
+	// primary, and vtop never deletes it. Changing the tables of an existing
+	// workflow has no effect; use a new workflow name instead.
+	//
+	// If unspecified, vtop doesn't manage Materialize workflows.
+	Materialize *VitessKeyspaceMaterializeSpec `json:"materialize,omitempty"`
+
+	// LookupVindexes are lookup vindexes that vtop creates on tables of
+	// this keyspace. For each one, vtop runs the CreateLookupVindex
+	// workflow to backfill the lookup table, then externalizes the vindex
+	// once the backfill is done, so the vindex starts being used for
+	// queries.
+	//
+	// Vindexes that already exist in the VSchema, and aren't write-only,
+	// are left alone. vtop never deletes a lookup vindex or its table, so
+	// removing an entry only stops vtop from reporting on it.
+	LookupVindexes []VitessLookupVindexSpec `json:"lookupVindexes,omitempty"`
+
 	// VitessOrchestrator deploys a set of Vitess Orchestrator (vtorc) servers for the Keyspace.
 	// It is highly recommended that you set disable_active_reparents=true
 	// for the vttablets if enabling vtorc.
@@ -172,12 +288,28 @@ type VitessKeyspaceTemplate struct {
 	// spec is only ever edited by automation whose edits you trust to be safe,
 	// you can set the policy to Immediate to skip these checks.
 	//
+	// Even with the Immediate policy, a keyspace that still has tablets is
+	// only turned down if it has no tables, if every shard has a complete
+	// backup from the last 24 hours, or if the VitessKeyspace object has the
+	// annotation planetscale.com/allow-data-loss set to "true".
+	//
 	// Default: RequireIdle
 	// +kubebuilder:validation:Enum=RequireIdle;Immediate
 	TurndownPolicy VitessKeyspaceTurndownPolicy `json:"turndownPolicy,omitempty"`
 
 	// Annotations can optionally be used to attach custom annotations to the VitessKeyspace object.
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ImageOverrides can optionally be used to run this keyspace with
+	// different images than the rest of the cluster, for example to try a
+	// newer version of vttablet or mysqld on one keyspace before upgrading
+	// the whole cluster. Any image that's not set here is inherited from
+	// the cluster-level images.
+	//
+	// Version skew across the cluster should only be temporary. Keyspaces
+	// and shards with overrides are listed in the VitessCluster status.
+	// Overridden images are not subject to spec.upgrade staging.
+	ImageOverrides *VitessKeyspaceImages `json:"imageOverrides,omitempty"`
 }
 
 // VitessOrchestratorSpec specifies deployment parameters for vtorc.
@@ -249,6 +381,210 @@ type VitessOrchestratorSpec struct {
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
+// VitessKeyspaceQueryServing declares which tablet types serve queries in
+// which cells.
+type VitessKeyspaceQueryServing struct {
+	// Disabled lists the tablet types whose query service should be disabled,
+	// and the cells in which to disable it. Every tablet type serves queries in
+	// every cell that isn't listed here, so removing an entry moves traffic
+	// back to the cell.
+	//
+	// Changes aren't applied while the keyspace is being resharded, since the
+	// resharding workflow manages tablet controls itself.
+	// +patchMergeKey=tabletType
+	// +patchStrategy=merge
+	Disabled []VitessKeyspaceQueryServiceControl `json:"disabled,omitempty" patchStrategy:"merge" patchMergeKey:"tabletType"`
+}
+
+// VitessKeyspaceReshardSpec declares a Reshard workflow and how far to take it.
+type VitessKeyspaceReshardSpec struct {
+	// Workflow is the name of the Reshard workflow.
+	// +kubebuilder:validation:MinLength=1
+	Workflow string `json:"workflow"`
+
+	// SourceShards are the shards to copy data from, such as ["-40", "40-80"].
+	// +kubebuilder:validation:MinItems=1
+	SourceShards []string `json:"sourceShards"`
+
+	// TargetShards are the shards to copy data to, such as ["-80"]. They
+	// must cover the same range of keyspace IDs as the source shards.
+	// +kubebuilder:validation:MinItems=1
+	TargetShards []string `json:"targetShards"`
+
+	// SwitchTraffic moves rdonly, replica, and then primary traffic to the
+	// target shards once the workflow has caught up. Replication is reversed,
+	// so the source shards stay up to date until the workflow is completed.
+	// Traffic is only switched inside maintenance windows, if any are set.
+	// Default: false.
+	SwitchTraffic bool `json:"switchTraffic,omitempty"`
+
+	// Complete cleans up the workflow once all traffic was switched, and
+	// deletes the source shards and their data from topology. vtop then turns
+	// down the source shards' tablets, subject to the same backup policy as
+	// any other shard that's removed. This can't be undone.
+	// Default: false.
+	Complete bool `json:"complete,omitempty"`
+
+	// Guardrails hold back the traffic switch until it looks safe, and can
+	// switch traffic back if queries start failing afterwards.
+	// Default: Traffic is switched as soon as the workflow has caught up.
+	Guardrails *VitessReshardGuardrails `json:"guardrails,omitempty"`
+}
+
+// VitessReshardGuardrails are checks around switching the traffic of a
+// Reshard workflow.
+type VitessReshardGuardrails struct {
+	// MaxReplicationLag is how far every stream of the workflow must be
+	// caught up for traffic to be switched. It's also the most lag that
+	// the switch of primary traffic waits out.
+	// Default: 10s to start switching, and 30s while switching.
+	MaxReplicationLag *metav1.Duration `json:"maxReplicationLag,omitempty"`
+
+	// MaxCopyPhase is how long the copy phase may take. A copy that ran
+	// for longer often means the target shards are undersized, so traffic
+	// is only switched after it once the switch is approved, as with
+	// requireApproval.
+	// Default: No limit.
+	MaxCopyPhase *metav1.Duration `json:"maxCopyPhase,omitempty"`
+
+	// RequireApproval makes vtop wait, once every other guardrail passes,
+	// until the VitessKeyspace is annotated with
+	// planetscale.com/approve-switch-traffic set to the workflow name.
+	// Default: false.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// Rollback switches traffic back to the source shards if too many
+	// queries to the keyspace fail through vtgate soon after the switch.
+	// Once traffic was switched back, vtop doesn't switch it again until
+	// switchTraffic is set to false and back to true.
+	// Default: Traffic is never switched back automatically.
+	Rollback *VitessReshardRollbackSpec `json:"rollback,omitempty"`
+}
+
+// VitessReshardRollbackSpec specifies when to switch traffic back after a
+// Reshard workflow switched it.
+type VitessReshardRollbackSpec struct {
+	// MaxErrorRate is the fraction of queries to the keyspace, as a decimal
+	// such as "0.01", that may fail during the window without rolling back.
+	// +kubebuilder:validation:Pattern=^(0([.][0-9]+)?|1)$
+	MaxErrorRate string `json:"maxErrorRate"`
+
+	// Window is how long after the switch the error rate is watched.
+	// Completing the workflow waits until the window has passed.
+	// Default: 10m
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// MinQueries is how many queries to the keyspace must have been made
+	// since the switch before the error rate is judged.
+	// Default: 100
+	// +kubebuilder:validation:Minimum=1
+	MinQueries int64 `json:"minQueries,omitempty"`
+}
+
+// VitessKeyspaceMaterializeSpec declares a Materialize workflow that copies
+// data from another keyspace into this one and keeps it up to date.
+type VitessKeyspaceMaterializeSpec struct {
+	// Workflow is the name of the Materialize workflow.
+	// +kubebuilder:validation:MinLength=1
+	Workflow string `json:"workflow"`
+
+	// SourceKeyspace is the keyspace to copy data from. It must be a
+	// different keyspace in the same cluster.
+	// +kubebuilder:validation:MinLength=1
+	SourceKeyspace string `json:"sourceKeyspace"`
+
+	// Tables are the tables of this keyspace to keep up to date.
+	// +kubebuilder:validation:MinItems=1
+	Tables []VitessMaterializedTable `json:"tables"`
+
+	// Cells are the cells whose tablets the workflow may copy from.
+	// Default: The cells of this keyspace's shards.
+	Cells []string `json:"cells,omitempty"`
+
+	// OnDDL is what the workflow does when it sees a DDL statement on the
+	// source keyspace.
+	// Default: IGNORE
+	// +kubebuilder:validation:Enum=IGNORE;STOP;EXEC;EXEC_IGNORE
+	OnDDL string `json:"onDDL,omitempty"`
+}
+
+// VitessMaterializedTable is a table that a Materialize workflow keeps up
+// to date.
+type VitessMaterializedTable struct {
+	// TargetTable is the name of the table in this keyspace.
+	// +kubebuilder:validation:MinLength=1
+	TargetTable string `json:"targetTable"`
+
+	// SourceExpression is the query on the source keyspace whose results the
+	// table holds, such as "select id, count(*) as n from orders group by id".
+	// Default: "select * from <targetTable>", which copies the table.
+	SourceExpression string `json:"sourceExpression,omitempty"`
+
+	// CreateDDL is the statement that creates the table if it doesn't exist,
+	// or "copy" to copy the schema of the source table with the same name.
+	// Default: "copy" if sourceExpression is unset. Otherwise, the table
+	// must already exist.
+	CreateDDL string `json:"createDDL,omitempty"`
+}
+
+// VitessLookupVindexSpec declares a lookup vindex on a table of this
+// keyspace.
+type VitessLookupVindexSpec struct {
+	// Name is the name of the vindex in the VSchema of this keyspace.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type is the type of lookup vindex.
+	// +kubebuilder:validation:Enum=lookup;lookup_unique;consistent_lookup;consistent_lookup_unique
+	Type string `json:"type"`
+
+	// Table is the table of this keyspace that the vindex indexes.
+	// +kubebuilder:validation:MinLength=1
+	Table string `json:"table"`
+
+	// Columns are the columns of the table that map to the vindex.
+	// +kubebuilder:validation:MinItems=1
+	Columns []string `json:"columns"`
+
+	// LookupTable is the table that holds the lookup data, in the form
+	// "<keyspace>.<table>". It's created if it doesn't exist.
+	// +kubebuilder:validation:Pattern=^[^.]+[.][^.]+$
+	LookupTable string `json:"lookupTable"`
+
+	// LookupColumns are the columns of the lookup table that hold the
+	// values of columns.
+	// Default: The same names as columns.
+	LookupColumns []string `json:"lookupColumns,omitempty"`
+
+	// KeyspaceIDColumn is the column of the lookup table that holds the
+	// keyspace ID.
+	// Default: keyspace_id
+	KeyspaceIDColumn string `json:"keyspaceIDColumn,omitempty"`
+
+	// Owned makes the table the owner of the vindex, so that Vitess
+	// updates the lookup table when rows of the table change. The backfill
+	// then stops after copying, instead of replicating changes until the
+	// vindex is externalized.
+	Owned bool `json:"owned,omitempty"`
+
+	// Cells are the cells whose tablets the backfill may copy from.
+	// Default: The cells of the lookup table's shards.
+	Cells []string `json:"cells,omitempty"`
+}
+
+// VitessKeyspaceQueryServiceControl disables query service for one tablet
+// type in some cells.
+type VitessKeyspaceQueryServiceControl struct {
+	// TabletType is the type of tablet that stops serving queries.
+	// +kubebuilder:validation:Enum=primary;replica;rdonly
+	TabletType string `json:"tabletType"`
+
+	// Cells is the list of cells in which tablets of this type stop serving
+	// queries.
+	// +kubebuilder:validation:MinItems=1
+	Cells []string `json:"cells"`
+}
+
 // VitessKeyspaceTurndownPolicy is the policy for turning down a keyspace.
 type VitessKeyspaceTurndownPolicy string
 
@@ -263,6 +599,15 @@ const (
 	VitessKeyspaceTurndownPolicyImmediate VitessKeyspaceTurndownPolicy = "Immediate"
 )
 
+// AllowDataLossAnnotation, when set to "true" on a VitessKeyspace object,
+// lets the keyspace be turned down even if that would delete its data.
+const AllowDataLossAnnotation = LabelPrefix + "/" + "allow-data-loss"
+
+// ApproveSwitchTrafficAnnotation, when set on a VitessKeyspace object to
+// the name of its Reshard workflow, approves switching that workflow's
+// traffic if reshard.guardrails require approval.
+const ApproveSwitchTrafficAnnotation = LabelPrefix + "/" + "approve-switch-traffic"
+
 // VitessKeyspaceImages specifies container images to use for this keyspace.
 type VitessKeyspaceImages struct {
 	/*
@@ -270,6 +615,8 @@ type VitessKeyspaceImages struct {
 
 		Make sure to keep the following up to date if you add fields here:
 		  * DefaultVitessKeyspaceImages() in vitesskeyspace_defaults.go
+		  * DefaultVitessShardImages() in vitesskeyspace_defaults.go
+		  * diffKeyspaceImages() in the vitesscluster controller
 	*/
 
 	// Vttablet is the container image (including version tag) to use for Vitess Tablet instances.
@@ -285,6 +632,18 @@ type VitessKeyspaceImages struct {
 	Mysqld *MysqldImage `json:"mysqld,omitempty"`
 	// MysqldExporter specifies the container image for mysqld-exporter.
 	MysqldExporter string `json:"mysqldExporter,omitempty"`
+	// Init is the container image (including version tag) to use for the
+	// init containers that the operator adds to vttablet and vtbackup Pods
+	// to copy Vitess binaries and config files into the mysqld container.
+	// It must contain the same Vitess release as the vttablet and vtbackup
+	// images, in the same locations.
+	// Default: The vttablet image for tablets, and the vtbackup image for vtbackup.
+	Init string `json:"init,omitempty"`
+	// Debug is the container image (including version tag) to run in the
+	// ephemeral debug containers that can be added to vttablet Pods with the
+	// planetscale.com/debug-tablet annotation.
+	// Default: The vttablet image.
+	Debug string `json:"debug,omitempty"`
 }
 
 // VitessKeyspacePartitioning defines a set of shards by dividing the keyspace into key ranges.
@@ -325,11 +684,31 @@ type VitessKeyspaceEqualPartitioning struct {
 }
 
 // VitessKeyspaceCustomPartitioning lets you explicitly specify the key range of every shard.
+//
+// The key ranges of the shards must exactly cover the keyspace, with no gaps
+// or overlaps. Any problem with that is reported in the keyspace's status.
 type VitessKeyspaceCustomPartitioning struct {
 	// Shards is a list of explicit shard specifications.
 	// +patchMergeKey=keyRange
 	// +patchStrategy=merge
-	Shards []VitessKeyspaceKeyRangeShard `json:"shards" patchStrategy:"merge" patchMergeKey:"keyRange"`
+	Shards []VitessKeyspaceKeyRangeShard `json:"shards,omitempty" patchStrategy:"merge" patchMergeKey:"keyRange"`
+
+	// EqualShards can optionally be set to generate the key ranges of this
+	// many roughly equal-sized shards, configured with ShardTemplate, so you
+	// don't have to list them all. The operator adds a shard to the Shards
+	// list for every generated key range that's not already there, so
+	// listing a shard with one of those key ranges overrides its
+	// configuration.
+	//
+	// WARNING: DO NOT change EqualShards after deploying, for the same reasons
+	//          you shouldn't change the parts of an equal partitioning.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65536
+	EqualShards int32 `json:"equalShards,omitempty"`
+
+	// ShardTemplate is the configuration used for the shards generated by
+	// EqualShards.
+	ShardTemplate *VitessShardTemplate `json:"shardTemplate,omitempty"`
 }
 
 // VitessKeyspaceKeyRangeShard defines a shard based on a key range.
@@ -367,6 +746,9 @@ type VitessKeyRange struct {
 type VitessKeyspaceStatus struct {
 	// The generation observed by the controller.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// DryRunChanges lists the changes the operator would make to the
+	// keyspace's objects, if spec.dryRun is set.
+	DryRunChanges []string `json:"dryRunChanges,omitempty"`
 	// Shards is a summary of the status of all desired shards.
 	Shards map[string]VitessKeyspaceShardStatus `json:"shards,omitempty"`
 	// Partitionings is an aggregation of status for all shards in each partitioning.
@@ -374,13 +756,34 @@ type VitessKeyspaceStatus struct {
 	// OrphanedShards is a list of unwanted shards that could not be turned down.
 	OrphanedShards map[string]OrphanStatus `json:"orphanedShards,omitempty"`
 	// Idle is a condition indicating whether the keyspace can be turned down.
-	// If Idle is True, the keyspace is not deployed in any cells, so it should
-	// be safe to turn down the keyspace.
+	// If Idle is True, the keyspace is not deployed in any cells, and has no
+	// tablets in cells outside of the VitessCluster, so it should be safe to
+	// turn down the keyspace.
 	Idle corev1.ConditionStatus `json:"idle,omitempty"`
+	// HasTables is a condition indicating whether any shard of the keyspace
+	// has tables. It's only checked for keyspaces with the Immediate turndown
+	// policy that aren't idle, since only those can be turned down while
+	// their tablets still hold data.
+	HasTables corev1.ConditionStatus `json:"hasTables,omitempty"`
 	// ReshardingStatus provides information about an active resharding operation, if any.
 	// This field is only present if the ReshardingActive condition is True. If that condition is Unknown,
 	// it means the operator was unable to query resharding status from Vitess.
 	Resharding *ReshardingStatus `json:"resharding,omitempty"`
+	// Materialize reports on the workflow requested in spec.materialize, if
+	// it exists.
+	Materialize *MaterializeStatus `json:"materialize,omitempty"`
+	// QueuedRollouts is the number of shards whose tablets have pending
+	// changes, but wait for other shards to finish rolling out theirs,
+	// because of updateStrategy.maxConcurrentShardRollouts.
+	QueuedRollouts int32 `json:"queuedRollouts,omitempty"`
+	// Cutover records the progress of the workflow requested in
+	// spec.reshard towards switching traffic, for its guardrails.
+	Cutover *ReshardCutoverStatus `json:"cutover,omitempty"`
+	// LookupVindexes reports on the vindexes requested in spec.lookupVindexes.
+	LookupVindexes []LookupVindexStatus `json:"lookupVindexes,omitempty"`
+	// DurabilityPolicy is the durability policy in the keyspace record,
+	// as last seen by the operator.
+	DurabilityPolicy string `json:"durabilityPolicy,omitempty"`
 	// Conditions is a list of all VitessKeyspace specific conditions we want to set and monitor.
 	// It's ok for multiple controllers to add conditions here, and those conditions will be preserved.
 	Conditions []VitessKeyspaceCondition `json:"conditions,omitempty"`
@@ -401,6 +804,102 @@ type ReshardingStatus struct {
 	// are still within the copy phase.
 	// If we can not compute the copy progress in a timely fashion, we will report -1 to indicate the progress is unknown.
 	CopyProgress int `json:"copyProgress,omitempty"`
+	// Streams reports on each stream of the workflow.
+	Streams []VReplicationStreamStatus `json:"streams,omitempty"`
+}
+
+// ReshardCutoverStatus records when a Reshard workflow copied data and
+// switched traffic.
+type ReshardCutoverStatus struct {
+	// Workflow is the name of the workflow.
+	Workflow string `json:"workflow"`
+	// CopyStartTime is when the copy phase was first seen.
+	CopyStartTime *metav1.Time `json:"copyStartTime,omitempty"`
+	// CopyEndTime is when the copy phase was first seen to be done.
+	CopyEndTime *metav1.Time `json:"copyEndTime,omitempty"`
+	// SwitchTime is when vtop switched traffic to the target shards.
+	SwitchTime *metav1.Time `json:"switchTime,omitempty"`
+	// BaselineQueries and BaselineErrors are the counts of queries to the
+	// keyspace, and of those that failed, summed over vtgates when the
+	// error rate started being watched.
+	BaselineQueries int64 `json:"baselineQueries,omitempty"`
+	BaselineErrors  int64 `json:"baselineErrors,omitempty"`
+	// ErrorRate is the fraction of queries to the keyspace that failed
+	// since the baseline, while the rollback window is open.
+	ErrorRate string `json:"errorRate,omitempty"`
+	// RollbackTime is when vtop switched traffic back to the source shards.
+	RollbackTime *metav1.Time `json:"rollbackTime,omitempty"`
+	// RollbackReason explains why traffic was switched back.
+	RollbackReason string `json:"rollbackReason,omitempty"`
+	// CompleteTime is when the workflow was seen completed. From then on,
+	// vtop turns down the source shards.
+	CompleteTime *metav1.Time `json:"completeTime,omitempty"`
+}
+
+// LookupVindexStatus reports on the creation of a lookup vindex.
+type LookupVindexStatus struct {
+	// Name is the name of the vindex.
+	Name string `json:"name"`
+	// Workflow is the name of the workflow that backfills the lookup table.
+	Workflow string `json:"workflow,omitempty"`
+	// State is how far along the creation of the vindex is.
+	State LookupVindexState `json:"state"`
+	// Message explains the state, such as an error that holds up creation.
+	Message string `json:"message,omitempty"`
+	// Streams reports on each stream of the backfill workflow, while it
+	// exists.
+	Streams []VReplicationStreamStatus `json:"streams,omitempty"`
+}
+
+// LookupVindexState is a step in the creation of a lookup vindex.
+type LookupVindexState string
+
+const (
+	// LookupVindexPending means the backfill workflow hasn't been created yet.
+	LookupVindexPending LookupVindexState = "Pending"
+	// LookupVindexBackfilling means the vindex is write-only while its
+	// lookup table is backfilled.
+	LookupVindexBackfilling LookupVindexState = "Backfilling"
+	// LookupVindexExternalized means the vindex is in use for queries.
+	LookupVindexExternalized LookupVindexState = "Externalized"
+	// LookupVindexError means creation is stuck until someone intervenes.
+	LookupVindexError LookupVindexState = "Error"
+)
+
+// MaterializeStatus reports on a Materialize workflow.
+type MaterializeStatus struct {
+	// Workflow is the name of the workflow.
+	Workflow string `json:"workflow"`
+	// State is either 'Running', 'Copying', 'Error' or 'Unknown'.
+	State WorkflowState `json:"state"`
+	// MaxVReplicationLagSeconds is how far behind the source keyspace the
+	// most lagging stream of the workflow is.
+	MaxVReplicationLagSeconds int64 `json:"maxVReplicationLagSeconds,omitempty"`
+	// Streams reports on each stream of the workflow.
+	Streams []VReplicationStreamStatus `json:"streams,omitempty"`
+}
+
+// VReplicationStreamStatus reports on one stream of a VReplication
+// workflow, which copies data from one source shard into one target shard.
+type VReplicationStreamStatus struct {
+	// TargetShard is the shard that the stream writes into.
+	TargetShard string `json:"targetShard"`
+	// ID is the id of the stream in the _vt.vreplication table of the
+	// target shard's primary.
+	ID int32 `json:"id"`
+	// SourceShard is the shard that the stream reads from.
+	SourceShard string `json:"sourceShard,omitempty"`
+	// State is the state of the stream, such as 'Copying', 'Running',
+	// 'Lagging', 'Stopped' or 'Error'.
+	State string `json:"state"`
+	// LagSeconds is how long ago the stream last processed an event from
+	// its source, counting heartbeats.
+	LagSeconds int64 `json:"lagSeconds"`
+	// RowsCopied is how many rows the stream has copied, while it's in
+	// the copy phase.
+	RowsCopied int64 `json:"rowsCopied,omitempty"`
+	// Message is the last message of the stream, such as an error.
+	Message string `json:"message,omitempty"`
 }
 
 // WorkflowState represents the current state for the given Workflow.
@@ -424,6 +923,7 @@ func NewVitessKeyspaceStatus() VitessKeyspaceStatus {
 		Shards:         make(map[string]VitessKeyspaceShardStatus),
 		OrphanedShards: make(map[string]OrphanStatus),
 		Idle:           corev1.ConditionUnknown,
+		HasTables:      corev1.ConditionUnknown,
 	}
 }
 
@@ -455,6 +955,15 @@ type VitessKeyspaceShardStatus struct {
 	PendingChanges string `json:"pendingChanges,omitempty"`
 	// Cells is a list of cells in which any tablets for this shard are deployed.
 	Cells []string `json:"cells,omitempty"`
+	// ExternalTablets is the number of tablets of this shard in cells outside
+	// of the VitessCluster.
+	ExternalTablets int32 `json:"externalTablets,omitempty"`
+	// LatestBackupTime is the time of the most recent complete backup of this
+	// shard in any backup location, if any complete backup was observed.
+	LatestBackupTime *metav1.Time `json:"latestBackupTime,omitempty"`
+	// QueuedRestores is the number of tablets whose Pods are waiting to be
+	// created, because too many tablets in the cluster are already starting.
+	QueuedRestores int32 `json:"queuedRestores,omitempty"`
 }
 
 // NewVitessKeyspaceShardStatus creates a new status object with default values.
@@ -502,18 +1011,25 @@ type VitessKeyspacePartitioningStatus struct {
 	DesiredShards int32 `json:"desiredShards,omitempty"`
 	// ReadyShards is the number of desired shards that are Ready.
 	ReadyShards int32 `json:"readyShards,omitempty"`
+	// KeyRangeProblem describes why the key ranges of the shards in this
+	// partitioning don't exactly cover the keyspace, if they don't.
+	KeyRangeProblem string `json:"keyRangeProblem,omitempty"`
 }
 
 // NewVitessKeyspacePartitioningStatus creates a new status object with default values.
 func NewVitessKeyspacePartitioningStatus(partitioning *VitessKeyspacePartitioning) VitessKeyspacePartitioningStatus {
 	shards := partitioning.ShardNameSet()
 
-	return VitessKeyspacePartitioningStatus{
+	status := VitessKeyspacePartitioningStatus{
 		ShardNames:     shards.List(),
 		ServingWrites:  corev1.ConditionUnknown,
 		DesiredTablets: partitioning.TotalReplicas(),
 		DesiredShards:  int32(shards.Len()),
 	}
+	if partitioning.Custom != nil {
+		status.KeyRangeProblem = partitioning.Custom.KeyRangeProblem()
+	}
+	return status
 }
 
 // VitessKeyspaceCondition contains details for the current condition of this VitessKeyspace.
@@ -548,6 +1064,29 @@ const (
 	VitessKeyspaceReshardingInSync VitessKeyspaceConditionType = "ReshardingInSync"
 	// VitessKeyspaceReady indicates whether the tablet Pods of the keyspace's serving partitioning are all Ready.
 	VitessKeyspaceReady VitessKeyspaceConditionType = "Ready"
+	// VitessKeyspaceDurabilityPolicyApplied indicates whether the keyspace record has the requested durability policy.
+	VitessKeyspaceDurabilityPolicyApplied VitessKeyspaceConditionType = "DurabilityPolicyApplied"
+	// VitessKeyspaceQueryServingApplied indicates whether the serving graph has the tablet controls requested in queryServing.
+	VitessKeyspaceQueryServingApplied VitessKeyspaceConditionType = "QueryServingApplied"
+	// VitessKeyspaceReshardProgressing indicates whether the workflow requested in reshard is moving to the next step.
+	VitessKeyspaceReshardProgressing VitessKeyspaceConditionType = "ReshardProgressing"
+	// VitessKeyspaceMaterializeInSync indicates whether the workflow requested in materialize is caught up with its source keyspace.
+	VitessKeyspaceMaterializeInSync VitessKeyspaceConditionType = "MaterializeInSync"
+	// VitessKeyspaceLookupVindexesReady indicates whether every vindex requested in lookupVindexes has been externalized.
+	VitessKeyspaceLookupVindexesReady VitessKeyspaceConditionType = "LookupVindexesReady"
+	// VitessKeyspacePartitioningsValid indicates whether the shards of every custom partitioning cover the keyspace with no gaps or overlaps.
+	VitessKeyspacePartitioningsValid VitessKeyspaceConditionType = "PartitioningsValid"
+)
+
+// These are the durability policies built into Vitess.
+const (
+	// NoneDurabilityPolicy doesn't use semi-sync replication.
+	NoneDurabilityPolicy = "none"
+	// SemiSyncDurabilityPolicy requires one replica to acknowledge each write.
+	SemiSyncDurabilityPolicy = "semi_sync"
+	// CrossCellDurabilityPolicy requires one replica in another cell than
+	// the primary to acknowledge each write.
+	CrossCellDurabilityPolicy = "cross_cell"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+//
+// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessMaterialize maintains tables in one keyspace of a VitessCluster
+// as the results of queries on another keyspace, through a Vitess
+// Materialize workflow. It's a declarative way to keep derived tables,
+// such as rollups or materialized views, up to date.
+//
+// Unlike the materialize field of a keyspace, which manages a single
+// workflow, any number of VitessMaterialize objects can target the same
+// keyspace, as long as their workflow names differ.
+// +kubebuilder:resource:path=vitessmaterializes,shortName=vtm
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetKeyspace`
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.sourceKeyspace`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Lag",type=integer,JSONPath=`.status.maxVReplicationLagSeconds`
+type VitessMaterialize struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VitessMaterializeSpec   `json:"spec,omitempty"`
+	Status VitessMaterializeStatus `json:"status,omitempty"`
+}
+
+// VitessMaterializeSpec defines the desired state of a VitessMaterialize.
+//
+// The workflow is created once every shard of the target keyspace has a
+// primary, and vtop never deletes it, not even when the VitessMaterialize
+// is deleted. Changing the tables of an existing workflow has no effect;
+// use a new workflow name instead.
+type VitessMaterializeSpec struct {
+	// ClusterName is the name of the VitessCluster, in the same namespace,
+	// whose keyspaces the workflow copies between.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// TargetKeyspace is the keyspace that holds the materialized tables.
+	// +kubebuilder:validation:MinLength=1
+	TargetKeyspace string `json:"targetKeyspace"`
+
+	// VitessKeyspaceMaterializeSpec gives the workflow name, the source
+	// keyspace and the tables to maintain.
+	VitessKeyspaceMaterializeSpec `json:",inline"`
+}
+
+// VitessMaterializeStatus describes the observed state of a VitessMaterialize.
+type VitessMaterializeStatus struct {
+	// The generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// State is either 'Running', 'Copying', 'Error' or 'Unknown'.
+	// It's empty until the workflow has been created.
+	State WorkflowState `json:"state,omitempty"`
+	// MaxVReplicationLagSeconds is how far behind the source keyspace the
+	// most lagging stream of the workflow is.
+	MaxVReplicationLagSeconds int64 `json:"maxVReplicationLagSeconds,omitempty"`
+	// Streams reports on each stream of the workflow.
+	Streams []VReplicationStreamStatus `json:"streams,omitempty"`
+	// Message explains why the workflow isn't running, such as an error
+	// reported by VReplication, or what vtop is waiting for to create it.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessMaterializeList contains a list of VitessMaterializes.
+type VitessMaterializeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VitessMaterialize `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VitessMaterialize{}, &VitessMaterializeList{})
+}
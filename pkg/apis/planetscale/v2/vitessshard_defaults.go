@@ -17,6 +17,7 @@ limitations under the License.
 package v2
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
 
@@ -27,6 +28,7 @@ import (
 func DefaultVitessShard(dst *VitessShard) {
 	DefaultUpdateStrategy(&dst.Spec.UpdateStrategy)
 	DefaultTopoReconcileConfig(&dst.Spec.TopologyReconciliation)
+	DefaultFailoverBuffer(&dst.Spec.FailoverBuffer)
 	DefaultVitessShardTemplate(&dst.Spec.VitessShardTemplate)
 }
 
@@ -36,6 +38,48 @@ func DefaultVitessShardTemplate(shardTemplate *VitessShardTemplate) {
 	}
 
 	DefaultVitessReplicationSpec(&shardTemplate.Replication)
+	for i := range shardTemplate.TabletPools {
+		DefaultVttabletQueryServerSpec(&shardTemplate.TabletPools[i].Vttablet)
+		DefaultVitessTabletPoolLocalStorage(shardTemplate.TabletPools[i].LocalStorage)
+	}
+}
+
+// DefaultVitessTabletPoolLocalStorage fills in defaults for pools on local
+// PersistentVolumes.
+func DefaultVitessTabletPoolLocalStorage(localStorage *VitessTabletPoolLocalStorage) {
+	if localStorage == nil {
+		return
+	}
+	if localStorage.NodeLossTimeout == nil {
+		localStorage.NodeLossTimeout = &metav1.Duration{Duration: defaultLocalStorageNodeLossTimeout}
+	}
+}
+
+// DefaultVttabletQueryServerSpec fills in the query server settings that
+// the operator has always set, so tablets keep the same flags.
+func DefaultVttabletQueryServerSpec(vttablet *VttabletSpec) {
+	if vttablet.QueryServer == nil {
+		vttablet.QueryServer = &VttabletQueryServerSpec{}
+	}
+	qs := vttablet.QueryServer
+	if qs.PoolSize == nil {
+		qs.PoolSize = pointer.Int32Ptr(DefaultQueryServerPoolSize)
+	}
+	if qs.StreamPoolSize == nil {
+		qs.StreamPoolSize = pointer.Int32Ptr(DefaultQueryServerStreamPoolSize)
+	}
+	if qs.TransactionCap == nil {
+		qs.TransactionCap = pointer.Int32Ptr(DefaultQueryServerTransactionCap)
+	}
+	if qs.MaxResultSize == nil {
+		qs.MaxResultSize = pointer.Int32Ptr(DefaultQueryServerMaxResultSize)
+	}
+	if qs.QueryTimeout == nil {
+		qs.QueryTimeout = &metav1.Duration{Duration: DefaultQueryServerQueryTimeout}
+	}
+	if qs.HotRowProtection != nil && qs.HotRowProtection.Mode == "" {
+		qs.HotRowProtection.Mode = DisabledHotRowProtectionMode
+	}
 }
 
 func DefaultVitessReplicationSpec(replicationSpec *VitessReplicationSpec) {
@@ -53,4 +97,17 @@ func DefaultVitessReplicationSpec(replicationSpec *VitessReplicationSpec) {
 	if replicationSpec.RecoverRestartedMaster == nil {
 		replicationSpec.RecoverRestartedMaster = pointer.BoolPtr(true)
 	}
+
+	if errant := replicationSpec.ErrantGTIDs; errant != nil && errant.Action == "" {
+		errant.Action = QuarantineErrantGTIDAction
+	}
+
+	if rebuild := replicationSpec.RebuildBrokenReplicas; rebuild != nil {
+		if rebuild.BrokenFor == nil {
+			rebuild.BrokenFor = &metav1.Duration{Duration: defaultRebuildBrokenFor}
+		}
+		if rebuild.MaxConcurrent == nil {
+			rebuild.MaxConcurrent = pointer.Int32Ptr(defaultRebuildMaxConcurrent)
+		}
+	}
 }
@@ -30,6 +30,14 @@ func (t *VitessTabletStatus) IsExternalMaster() bool {
 	return t.PoolType == ExternalMasterTabletPoolName
 }
 
+// PoolID returns the ID (see VitessShardTabletPool.ID) of the tablet's pool.
+func (t *VitessTabletStatus) PoolID() string {
+	if t.Pool != "" {
+		return t.Pool
+	}
+	return t.PoolType
+}
+
 // IsRunning indicates whether the tablet is known to be Running.
 func (t *VitessTabletStatus) IsRunning() bool {
 	return t.Running == corev1.ConditionTrue
@@ -58,9 +66,58 @@ func (t *VitessTabletPoolType) InitTabletType() string {
 	}
 }
 
-// IsMatch indicates whether a tablet pool matches another tablet pool's type and cell.
+// IsMatch indicates whether a tablet pool matches another tablet pool's type, cell and ID.
 func (t *VitessShardTabletPool) IsMatch(inputPool *VitessShardTabletPool) bool {
-	return t.Type == inputPool.Type && t.Cell == inputPool.Cell
+	return t.Type == inputPool.Type && t.Cell == inputPool.Cell && t.ID() == inputPool.ID()
+}
+
+// ID returns the identity of the pool within its cell, from which the
+// identities of its tablets are derived. That's the pool's name, if it's
+// named, or else its type.
+func (t *VitessShardTabletPool) ID() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return string(t.Type)
+}
+
+// VerticalAutoscalingMode returns what to do with the recommendations for
+// the pool's tablets.
+func (t *VitessShardTabletPool) VerticalAutoscalingMode() VerticalAutoscalingMode {
+	if t.VerticalAutoscaling == nil {
+		return VerticalAutoscalingOff
+	}
+	if t.VerticalAutoscaling.Mode == "" {
+		return VerticalAutoscalingRecommend
+	}
+	return t.VerticalAutoscaling.Mode
+}
+
+// IsScratch indicates whether the pool keeps its data on ephemeral storage.
+// Only rdonly pools can do so.
+func (t *VitessShardTabletPool) IsScratch() bool {
+	return t.Scratch != nil && t.Type == RdonlyPoolType
+}
+
+// IsPaused returns whether reconciliation of the shard is paused.
+// A dry run counts as paused, since it mustn't change anything either.
+func (s *VitessShardSpec) IsPaused() bool {
+	return s.DryRun || (s.Paused != nil && *s.Paused)
+}
+
+// IsStandby returns whether the shard is part of a standby cluster that
+// hasn't been promoted yet.
+func (s *VitessShardSpec) IsStandby() bool {
+	return s.Standby != nil && !s.Standby.Promoted
+}
+
+// BackupClusterName returns the name of the cluster whose backups the shard
+// uses, which is the source cluster while the shard is a standby.
+func (s *VitessShardSpec) BackupClusterName(clusterName string) string {
+	if s.IsStandby() {
+		return s.Standby.SourceClusterName
+	}
+	return clusterName
 }
 
 // UsingExternalDatastore indicates whether the VitessShard Spec is using
@@ -76,6 +133,13 @@ func (s *VitessShardSpec) UsingExternalDatastore() bool {
 	return false
 }
 
+// ManagesInternalCredentials returns whether the operator manages the
+// passwords of the internal MySQL accounts of the shard. That's only the case
+// if all of its tablets run their own MySQL.
+func (s *VitessShardSpec) ManagesInternalCredentials() bool {
+	return s.InternalCredentials != nil && !s.UsingExternalDatastore()
+}
+
 // AllPoolsUsingMysqld returns a boolean indicating whether the VitessShard Spec is using
 // local MySQL for all of it's pools by checking the Mysqld field of all tablet pools.
 func (s *VitessShardSpec) AllPoolsUsingMysqld() bool {
@@ -89,6 +153,21 @@ func (s *VitessShardSpec) AllPoolsUsingMysqld() bool {
 	return true
 }
 
+// RunsMysqldExporter returns whether any tablet of the shard runs the
+// mysqld-exporter sidecar.
+func (s *VitessShardSpec) RunsMysqldExporter() bool {
+	if s.Images.MysqldExporter == "" {
+		return false
+	}
+	for i := range s.TabletPools {
+		p := &s.TabletPools[i]
+		if p.Mysqld != nil && (p.MysqldExporter == nil || !p.MysqldExporter.Disabled) {
+			return true
+		}
+	}
+	return false
+}
+
 // MasterEligibleTabletCount returns the total number of master-eligible tablets in the shard.
 func (s *VitessShardSpec) MasterEligibleTabletCount() int32 {
 	count := int32(0)
@@ -101,6 +180,42 @@ func (s *VitessShardSpec) MasterEligibleTabletCount() int32 {
 	return count
 }
 
+// PoolReplicas returns the number of tablets to deploy in the given pool,
+// which includes tablets added to make up for evacuated cells.
+func (s *VitessShardSpec) PoolReplicas(pool *VitessShardTabletPool) int32 {
+	if s.Evacuation == nil || !s.Evacuation.ScaleUpReplicas || pool.Type != ReplicaPoolType || s.Evacuation.Evacuating(pool.Cell) {
+		return pool.Replicas
+	}
+
+	evacuated, remainingPools := int32(0), int32(0)
+	for i := range s.TabletPools {
+		p := &s.TabletPools[i]
+		if p.Type != ReplicaPoolType {
+			continue
+		}
+		if s.Evacuation.Evacuating(p.Cell) {
+			evacuated += p.Replicas
+		} else {
+			remainingPools++
+		}
+	}
+	// Round up, so the shard never ends up with fewer replicas than before.
+	return pool.Replicas + (evacuated+remainingPools-1)/remainingPools
+}
+
+// TabletPool looks up the tablet pool with the given ID (see
+// VitessShardTabletPool.ID) in the given cell.
+// It returns nil if there's no such pool.
+func (s *VitessShardSpec) TabletPool(cell, poolID string) *VitessShardTabletPool {
+	for i := range s.TabletPools {
+		pool := &s.TabletPools[i]
+		if pool.Cell == cell && pool.ID() == poolID {
+			return pool
+		}
+	}
+	return nil
+}
+
 // BackupLocation looks up a backup location in the list by name.
 // It returns nil if no location by that name exists.
 func (s *VitessShardSpec) BackupLocation(name string) *VitessBackupLocation {
@@ -119,18 +234,28 @@ func (s *VitessShardSpec) BackupLocation(name string) *VitessBackupLocation {
 	return nil
 }
 
+// PoolBackupLocation returns the backup location used by a tablet pool,
+// or nil if it has none.
+func (s *VitessShardSpec) PoolBackupLocation(pool *VitessShardTabletPool) *VitessBackupLocation {
+	if pool.BackupLocationName != "" {
+		return s.BackupLocation(pool.BackupLocationName)
+	}
+	// Prefer a location in the pool's own cell over the default one.
+	for i := range s.BackupLocations {
+		for _, cell := range s.BackupLocations[i].Cells {
+			if cell == pool.Cell {
+				return &s.BackupLocations[i]
+			}
+		}
+	}
+	return s.BackupLocation("")
+}
+
 // BackupsEnabled returns whether at least one tablet pool in the shard has a
 // backup location set.
 func (s *VitessShardSpec) BackupsEnabled() bool {
 	for i := range s.TabletPools {
-		pool := &s.TabletPools[i]
-		// If any explicit value is set, backups are enabled.
-		if pool.BackupLocationName != "" {
-			return true
-		}
-		// If the pool uses the default location, backups are enabled if a
-		// default location exists.
-		if s.BackupLocation("") != nil {
+		if s.PoolBackupLocation(&s.TabletPools[i]) != nil {
 			return true
 		}
 	}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+)
+
+func TestPoolReplicas(t *testing.T) {
+	spec := &VitessShardSpec{
+		VitessShardTemplate: VitessShardTemplate{
+			TabletPools: []VitessShardTabletPool{
+				{Cell: "a", Type: ReplicaPoolType, Replicas: 3},
+				{Cell: "a", Type: RdonlyPoolType, Replicas: 1},
+				{Cell: "b", Type: ReplicaPoolType, Replicas: 3},
+				{Cell: "c", Type: ReplicaPoolType, Replicas: 3},
+				{Cell: "c", Type: RdonlyPoolType, Replicas: 1},
+			},
+		},
+	}
+	table := []struct {
+		evacuation *EvacuationSpec
+		want       []int32
+	}{
+		{nil, []int32{3, 1, 3, 3, 1}},
+		{&EvacuationSpec{Cells: []string{"a"}}, []int32{3, 1, 3, 3, 1}},
+		{&EvacuationSpec{Cells: []string{"a"}, ScaleUpReplicas: true}, []int32{3, 1, 5, 5, 1}},
+		{&EvacuationSpec{Cells: []string{"a", "b"}, ScaleUpReplicas: true}, []int32{3, 1, 3, 9, 1}},
+		{&EvacuationSpec{Cells: []string{"a", "b", "c"}, ScaleUpReplicas: true}, []int32{3, 1, 3, 3, 1}},
+	}
+
+	for _, test := range table {
+		spec.Evacuation = test.evacuation
+		for i := range spec.TabletPools {
+			pool := &spec.TabletPools[i]
+			if got := spec.PoolReplicas(pool); got != test.want[i] {
+				t.Errorf("PoolReplicas(%v/%v) with evacuation %+v = %v; want %v", pool.Cell, pool.Type, test.evacuation, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestPoolBackupLocation(t *testing.T) {
+	spec := &VitessShardSpec{
+		BackupLocations: []VitessBackupLocation{
+			{Name: ""},
+			{Name: "east", Cells: []string{"us-east-1a", "us-east-1b"}},
+			{Name: "west", Cells: []string{"us-west-2a"}},
+		},
+	}
+	table := []struct {
+		pool VitessShardTabletPool
+		want string
+	}{
+		{VitessShardTabletPool{Cell: "us-east-1b"}, "east"},
+		{VitessShardTabletPool{Cell: "us-west-2a"}, "west"},
+		{VitessShardTabletPool{Cell: "eu-west-1a"}, ""},
+		{VitessShardTabletPool{Cell: "us-east-1a", VitessShardTabletPoolTemplate: VitessShardTabletPoolTemplate{BackupLocationName: "west"}}, "west"},
+	}
+
+	for _, test := range table {
+		got := spec.PoolBackupLocation(&test.pool)
+		if got == nil || got.Name != test.want {
+			t.Errorf("PoolBackupLocation(%+v) = %+v; want location %q", test.pool, got, test.want)
+		}
+	}
+
+	spec.BackupLocations = spec.BackupLocations[1:]
+	if got := spec.PoolBackupLocation(&VitessShardTabletPool{Cell: "eu-west-1a"}); got != nil {
+		t.Errorf("PoolBackupLocation() without a default location = %+v; want nil", got)
+	}
+}
+
+func TestTabletPool(t *testing.T) {
+	spec := &VitessShardSpec{
+		VitessShardTemplate: VitessShardTemplate{
+			TabletPools: []VitessShardTabletPool{
+				{Cell: "a", Type: ReplicaPoolType, Replicas: 3},
+				{Name: "canary", Cell: "a", Type: ReplicaPoolType, Replicas: 1},
+				{Name: "rdonly", Cell: "b", Type: RdonlyPoolType, Replicas: 1},
+			},
+		},
+	}
+	table := []struct {
+		cell, poolID string
+		want         *VitessShardTabletPool
+	}{
+		{"a", "replica", &spec.TabletPools[0]},
+		{"a", "canary", &spec.TabletPools[1]},
+		{"b", "rdonly", &spec.TabletPools[2]},
+		{"b", "replica", nil},
+	}
+
+	for _, test := range table {
+		if got := spec.TabletPool(test.cell, test.poolID); got != test.want {
+			t.Errorf("TabletPool(%q, %q) = %+v; want %+v", test.cell, test.poolID, got, test.want)
+		}
+	}
+}
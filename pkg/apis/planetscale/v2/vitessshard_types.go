@@ -18,6 +18,7 @@ package v2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -59,14 +60,18 @@ type VitessShardSpec struct {
 	// the keyspace level.
 	DatabaseName string `json:"databaseName,omitempty"`
 
+	// DurabilityPolicy is inherited from the parent keyspace. It's only used
+	// to restart tablets when it changes, since Vitess itself reads the policy
+	// from the keyspace record.
+	DurabilityPolicy string `json:"durabilityPolicy,omitempty"`
+
 	// ZoneMap is a map from Vitess cell name to zone (failure domain) name
 	// for all cells defined in the VitessCluster.
 	ZoneMap map[string]string `json:"zoneMap"`
 
-	// Images are not customizable by users at the shard level because version
-	// skew across the shard is discouraged except during rolling updates,
-	// in which case this field is automatically managed by the VitessKeyspace
-	// controller that owns this VitessShard.
+	// Images are the images in effect for this shard. They're filled in by
+	// the VitessKeyspace controller from the keyspace-level images, with any
+	// imageOverrides of the shard applied.
 	Images VitessKeyspaceImages `json:"images"`
 
 	// ImagePullPolicies are inherited from the VitessCluster spec.
@@ -90,14 +95,66 @@ type VitessShardSpec struct {
 	// BackupEngine specifies the Vitess backup engine to use, either "builtin" or "xtrabackup".
 	BackupEngine VitessBackupEngine `json:"backupEngine,omitempty"`
 
+	// BackupCompression chooses how backups are compressed, as set in the
+	// VitessCluster.
+	BackupCompression *BackupCompressionSpec `json:"backupCompression,omitempty"`
+
+	// BackupXtrabackup tunes the xtrabackup engine, as set in the VitessCluster.
+	BackupXtrabackup *XtrabackupSpec `json:"backupXtrabackup,omitempty"`
+
+	// BackupStaleThreshold is the age after which the latest backup of a
+	// shard is reported as stale, as set in the VitessCluster.
+	BackupStaleThreshold *metav1.Duration `json:"backupStaleThreshold,omitempty"`
+
+	// BackupDisruptionPolicy holds back disruptive operations until a shard
+	// has a recent backup, as set in the VitessCluster.
+	BackupDisruptionPolicy *VitessBackupDisruptionPolicy `json:"backupDisruptionPolicy,omitempty"`
+
+	// MaxConcurrentRestores limits how many tablets in the cluster may be
+	// starting up at once, as set in the VitessCluster.
+	MaxConcurrentRestores *int32 `json:"maxConcurrentRestores,omitempty"`
+
+	// Vtbackup tunes the Pods that run vtbackup, as set in the VitessCluster.
+	Vtbackup *VtbackupSpec `json:"vtbackup,omitempty"`
+
 	// ExtraVitessFlags is inherited from the parent's VitessClusterSpec.
 	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
 
+	// ComponentVitessFlags is inherited from the parent's VitessClusterSpec.
+	ComponentVitessFlags *ComponentVitessFlags `json:"componentVitessFlags,omitempty"`
+
 	// TopologyReconciliation is inherited from the parent's VitessClusterSpec.
 	TopologyReconciliation *TopoReconcileConfig `json:"topologyReconciliation,omitempty"`
 
+	// Networking is inherited from the parent's VitessClusterSpec.
+	Networking *NetworkingSpec `json:"networking,omitempty"`
+
+	// ResourceDefaults is inherited from the parent's VitessClusterSpec.
+	ResourceDefaults *ResourceDefaultsSpec `json:"resourceDefaults,omitempty"`
+
+	// FailoverBuffer is inherited from the parent's VitessClusterSpec.
+	FailoverBuffer *FailoverBufferSpec `json:"failoverBuffer,omitempty"`
+
+	// Evacuation is inherited from the parent's VitessClusterSpec.
+	Evacuation *EvacuationSpec `json:"evacuation,omitempty"`
+
 	// UpdateStrategy is inherited from the parent's VitessClusterSpec.
 	UpdateStrategy *VitessClusterUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// AdoptExisting is inherited from the parent's VitessClusterSpec.
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// Standby is inherited from the parent's VitessClusterSpec.
+	Standby *VitessClusterStandbySpec `json:"standby,omitempty"`
+
+	// Users is inherited from the parent's VitessClusterSpec.
+	Users []VitessMySQLUser `json:"users,omitempty"`
+
+	// InternalCredentials is inherited from the parent's VitessClusterSpec.
+	InternalCredentials *VitessInternalCredentialsSpec `json:"internalCredentials,omitempty"`
+
+	// DryRun is inherited from the parent's VitessClusterSpec.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // VitessShardTemplate contains only the user-specified parts of a VitessShard object.
@@ -105,7 +162,9 @@ type VitessShardTemplate struct {
 	// TabletPools specify groups of tablets in a given cell with a certain
 	// tablet type and a shared configuration template.
 	//
-	// There must be at most one pool in this list for each (cell,type) pair.
+	// There must be at most one pool in this list for each (cell,type,name)
+	// tuple, and unnamed pools are identified by their type, so there may be
+	// several pools of the same type in a cell only if they're named.
 	// Each shard must have at least one "replica" pool (in at least one cell)
 	// in order to be able to serve.
 	// +patchMergeKey=type
@@ -113,18 +172,50 @@ type VitessShardTemplate struct {
 	// +listType=map
 	// +listMapKey=type
 	// +listMapKey=cell
+	// +listMapKey=name
 	TabletPools []VitessShardTabletPool `json:"tabletPools,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 
-	// DatabaseInitScriptSecret specifies the init_db.sql script file to use for this shard.
+	// DatabaseInitScriptSecret optionally specifies the init_db.sql script file to use for this shard.
 	// This SQL script file is executed immediately after bootstrapping an empty database
 	// to set up initial tables and other MySQL-level entities needed by Vitess.
-	DatabaseInitScriptSecret SecretSource `json:"databaseInitScriptSecret"`
+	//
+	// Default: The operator provides the init_db.sql script of the Vitess
+	// version it's built against, which saves having to keep a copy up to date
+	// across Vitess upgrades.
+	DatabaseInitScriptSecret SecretSource `json:"databaseInitScriptSecret,omitempty"`
+
+	// DatabaseInitScriptFragments optionally lists SQL scripts to append, in
+	// order, to the init_db.sql script, for example to create extra users and
+	// grants. Fragments must be in Kubernetes Secrets, specified with 'name'
+	// and 'key', and so must DatabaseInitScriptSecret if it's set along with
+	// fragments. The operator renders the combined script into a Secret of
+	// its own.
+	DatabaseInitScriptFragments []SecretSource `json:"databaseInitScriptFragments,omitempty"`
 
 	// Replication configures Vitess replication settings for the shard.
 	Replication VitessReplicationSpec `json:"replication,omitempty"`
 
 	// Annotations can optionally be used to attach custom annotations to the VitessShard object.
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Paused overrides the 'paused' field of the VitessCluster for this shard.
+	// In the VitessShard object itself, it's always filled in by the parent
+	// controller with the value that's in effect for the shard.
+	// Default: Inherit from the VitessCluster.
+	Paused *bool `json:"paused,omitempty"`
+
+	// ImageOverrides can optionally be used to run this shard with different
+	// images than the rest of its keyspace. Any image that's not set here is
+	// inherited from the keyspace.
+	ImageOverrides *VitessKeyspaceImages `json:"imageOverrides,omitempty"`
+
+	// RevertToRevision can be set to the name of one of the revisions listed
+	// in the VitessShard's status.revisions to roll the shard's tablets back
+	// to the images and flags they ran in that revision, regardless of those
+	// in the spec. Clear it to roll forward to the spec again.
+	// While it's set, no new revisions are recorded for the shard.
+	// Default: Tablets run the images and flags in the spec.
+	RevertToRevision string `json:"revertToRevision,omitempty"`
 }
 
 // VitessReplicationSpec specifies how Vitess will set up MySQL replication.
@@ -148,10 +239,92 @@ type VitessReplicationSpec struct {
 	//
 	// Default: true.
 	RecoverRestartedMaster *bool `json:"recoverRestartedMaster,omitempty"`
+
+	// ErrantGTIDs can optionally be set to have the operator check replicas
+	// for errant GTIDs, which are transactions a replica executed that the
+	// primary never did. A replica with errant GTIDs can break replication
+	// if it's ever promoted, so the operator takes it out of rotation as
+	// configured here, and records what it found and did as events.
+	//
+	// Default: Replicas aren't checked for errant GTIDs.
+	ErrantGTIDs *VitessReplicationErrantGTIDSpec `json:"errantGTIDs,omitempty"`
+
+	// RebuildBrokenReplicas can optionally be set to have the operator
+	// rebuild replicas whose MySQL has been unreachable, or whose replication
+	// has been failing, for too long. A broken replica is rebuilt by deleting
+	// its Pod and data volume, so the tablet is recreated and restores the
+	// latest backup. The primary is never rebuilt, nor are tablets of pools
+	// without a backup location. Replicas aren't checked while the primary
+	// is unreachable, since none of them can replicate then.
+	//
+	// Default: Broken replicas are left for a human to repair.
+	RebuildBrokenReplicas *VitessReplicationRebuildSpec `json:"rebuildBrokenReplicas,omitempty"`
 }
 
+// VitessReplicationRebuildSpec configures when the operator rebuilds broken
+// replicas.
+type VitessReplicationRebuildSpec struct {
+	// BrokenFor is how long a replica must have been broken before it's
+	// rebuilt.
+	//
+	// Default: 30m
+	BrokenFor *metav1.Duration `json:"brokenFor,omitempty"`
+
+	// MaxConcurrent is the maximum number of replicas of a shard that may be
+	// rebuilding at once. Tablets that are starting or restoring for any
+	// other reason count as rebuilding too.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+}
+
+// VitessReplicationErrantGTIDSpec configures how the operator remediates
+// replicas with errant GTIDs.
+type VitessReplicationErrantGTIDSpec struct {
+	// Action is what the operator does with a replica with errant GTIDs.
+	//
+	// "Quarantine" changes the tablet's type to DRAINED, so it stops serving
+	// and is never promoted, and leaves it for a human to investigate. The
+	// tablet Pod is annotated with the errant GTIDs.
+	//
+	// "Rebuild" deletes the tablet's Pod and data volume, so the tablet is
+	// recreated and restores the latest backup. Only one tablet of a shard is
+	// rebuilt at a time, and only if the shard has a complete backup.
+	// Tablets of pools without a backup location are quarantined instead.
+	//
+	// Default: Quarantine
+	// +kubebuilder:validation:Enum=Quarantine;Rebuild
+	Action ErrantGTIDAction `json:"action,omitempty"`
+}
+
+// ErrantGTIDAction is a way to remediate a replica with errant GTIDs.
+type ErrantGTIDAction string
+
+const (
+	// QuarantineErrantGTIDAction stops a replica with errant GTIDs from
+	// serving.
+	QuarantineErrantGTIDAction ErrantGTIDAction = "Quarantine"
+	// RebuildErrantGTIDAction rebuilds a replica with errant GTIDs from
+	// backup.
+	RebuildErrantGTIDAction ErrantGTIDAction = "Rebuild"
+)
+
 // VitessShardTabletPool defines a pool of tablets with a similar purpose.
 type VitessShardTabletPool struct {
+	// Name optionally identifies this pool among the pools in its cell.
+	//
+	// A tablet's identity (and therefore its UID and Pod name) is derived
+	// from its cell, its pool's name and its index within the pool, so pools
+	// can be reordered, or added to the list, without any tablets being
+	// recreated. A pool without a name is identified by its type, which is
+	// also how tablets were identified before pools could be named, so
+	// naming a pool after its type doesn't change the identity of its tablets.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=^([a-z0-9]([-a-z0-9]*[a-z0-9])?)?$
+	// +kubebuilder:default=""
+	Name string `json:"name,omitempty"`
+
 	// Cell is the name of the Vitess cell in which to deploy this pool.
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=63
@@ -176,6 +349,13 @@ type VitessShardTabletPool struct {
 	// +kubebuilder:validation:Minimum=0
 	Replicas int32 `json:"replicas"`
 
+	// VitessShardTabletPoolTemplate configures the tablets in the pool.
+	VitessShardTabletPoolTemplate `json:",inline"`
+}
+
+// VitessShardTabletPoolTemplate contains the parts of a tablet pool that
+// configure its tablets, regardless of which cell they're deployed in.
+type VitessShardTabletPoolTemplate struct {
 	// DataVolumeClaimTemplate configures the PersistentVolumeClaims that will be created
 	// for each tablet to store its database files.
 	// This field is required for local MySQL, but should be omitted in the case of externally
@@ -186,10 +366,26 @@ type VitessShardTabletPool struct {
 	// is set on the StorageClass specified in the storageClassName field here.
 	DataVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"dataVolumeClaimTemplate,omitempty"`
 
+	// Scratch can optionally be set on an "rdonly" pool to keep the tablets'
+	// database files on ephemeral storage instead of PersistentVolumeClaims.
+	// Scratch tablets restore the latest backup every time they start, which
+	// makes them cheap to throw away, for example to run heavy analytical
+	// queries. DataVolumeClaimTemplate is ignored for scratch pools, and the
+	// pool must have a backup location to restore from.
+	Scratch *VitessTabletPoolScratch `json:"scratch,omitempty"`
+
+	// LocalStorage can optionally be set if DataVolumeClaimTemplate requests
+	// local PersistentVolumes, which pin each tablet to the Node its volume
+	// is on. If that Node is removed, the operator deletes the tablet's PVC
+	// and Pod, so the tablet is recreated on another Node and restores the
+	// latest backup, instead of staying Pending forever.
+	LocalStorage *VitessTabletPoolLocalStorage `json:"localStorage,omitempty"`
+
 	// BackupLocationName is the name of the backup location to use for this
 	// tablet pool. It must match the name of one of the backup locations
 	// defined in the VitessCluster.
-	// Default: Use the backup location whose name is empty.
+	// Default: Use the backup location that lists the pool's cell in its
+	// 'cells' field, if any, or else the backup location whose name is empty.
 	BackupLocationName string `json:"backupLocationName,omitempty"`
 
 	// Vttablet configures the vttablet server within each tablet.
@@ -202,6 +398,17 @@ type VitessShardTabletPool struct {
 	// You must specify either Mysqld or ExternalDatastore, but not both.
 	ExternalDatastore *ExternalDatastore `json:"externalDatastore,omitempty"`
 
+	// MysqldExporter can optionally be used to configure the mysqld-exporter
+	// sidecar that the operator runs next to a local MySQL in each tablet Pod.
+	// It has no effect when ExternalDatastore is used.
+	MysqldExporter *MysqldExporterSpec `json:"mysqldExporter,omitempty"`
+
+	// VerticalAutoscaling can optionally be set to size the vttablet and
+	// mysqld containers of the pool's tablets from their actual usage, with
+	// a VerticalPodAutoscaler that the operator creates for the pool. The
+	// Vertical Pod Autoscaler must be installed in the Kubernetes cluster.
+	VerticalAutoscaling *VitessTabletPoolVerticalAutoscaling `json:"verticalAutoscaling,omitempty"`
+
 	// Affinity allows you to set rules that constrain the scheduling of
 	// your vttablet pods. Affinity rules will affect all underlying
 	// tablets in the specified tablet pool the same way. WARNING: These affinity rules
@@ -211,6 +418,44 @@ type VitessShardTabletPool struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 
+	// SpreadPolicy is a simpler alternative to affinity for choosing how the
+	// tablets of the pool are spread out, without having to refer to the
+	// labels that the operator sets on tablet Pods.
+	//
+	// With "Node", a tablet is never scheduled on a Node that already has a
+	// tablet of the same shard. With "Zone", tablets of the same shard and
+	// type, like the master-eligible replicas, are also spread evenly across
+	// zones, and a tablet isn't scheduled if that would make the spread
+	// uneven. With "ReplicaNode", a master-eligible replica is never
+	// scheduled on a Node that already has a master-eligible replica of the
+	// same shard, in any cell, so losing one Node can't take out more than one
+	// of them, while other tablets are spread out as by default. With "None",
+	// tablets are scheduled wherever they fit.
+	//
+	// Node anti-affinity is only applied if affinity isn't set, while zone
+	// spreading is added to any topologySpreadConstraints.
+	// Default: Tablets prefer Nodes with no other tablet of the same shard,
+	// but are still scheduled if there's none.
+	// +kubebuilder:validation:Enum=None;ReplicaNode;Node;Zone
+	SpreadPolicy SpreadPolicy `json:"spreadPolicy,omitempty"`
+
+	// NodeSelector can optionally be used to schedule the pool's tablets,
+	// and the vtbackup Pods that back them up, only on Nodes with matching
+	// labels, like the NodePool of a node autoscaler (for example,
+	// karpenter.sh/nodepool for Karpenter). Changing this restarts the
+	// pool's tablets.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// ProtectPrimary keeps node autoscalers, like Karpenter and the cluster
+	// autoscaler, from removing the Node that runs the primary tablet of
+	// the shard, if it's in this pool. The operator annotates the primary's
+	// Pod with karpenter.sh/do-not-disrupt and
+	// cluster-autoscaler.kubernetes.io/safe-to-evict: "false", and moves the
+	// annotations along with the primary after a reparent. Other tablets
+	// can still be moved off Nodes that autoscalers want to remove.
+	// Default: false
+	ProtectPrimary bool `json:"protectPrimary,omitempty"`
+
 	// Annotations can optionally be used to attach custom annotations to Pods
 	// created for this component.
 	Annotations map[string]string `json:"annotations,omitempty"`
@@ -230,10 +475,20 @@ type VitessShardTabletPool struct {
 	// volumeMount to specify where in each container's filesystem the volume
 	// should be mounted.
 	// These volumes are available to be mounted by both vttablet and mysqld.
+	// The volumeClaimTemplate of a generic ephemeral volume that doesn't
+	// request any storage requests as much as dataVolumeClaimTemplate.
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
 
+	// ExtraVolumeClaimTemplates can optionally be used to give each tablet
+	// PersistentVolumeClaims besides its data volume, which the operator
+	// creates and deletes along with the tablet, for example for a tmpdir.
+	// Each claim is available as a Pod volume with the template's name, to
+	// be mounted through extraVolumeMounts. A template that doesn't request
+	// any storage requests as much as dataVolumeClaimTemplate.
+	ExtraVolumeClaimTemplates []VolumeClaimTemplate `json:"extraVolumeClaimTemplates,omitempty"`
+
 	// ExtraVolumeMounts can optionally be used to override default Pod
 	// volumeMounts defined by the operator, or specify additional mounts.
 	// Typically, these are used to mount volumes defined through extraVolumes.
@@ -262,8 +517,85 @@ type VitessShardTabletPool struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// HostNetwork runs the tablet Pods in the network namespace of their
+	// node, for environments where the Pod network's MTU or latency to
+	// storage is a problem.
+	//
+	// Tablets then listen on the node's ports, which are declared as host
+	// ports so the scheduler never puts two host-network tablets on the same
+	// node. Make sure your nodes have enough capacity for that, and that no
+	// other processes on them use the tablet ports (3306, 9104, 15000 and
+	// 15999). NetworkPolicies don't apply to Pods in host network mode.
+	//
+	// Changing this restarts all tablets in the pool.
+	HostNetwork bool `json:"hostNetwork,omitempty"`
+
+	// DNSPolicy is the DNS policy of the tablet Pods.
+	// Default: ClusterFirstWithHostNet if hostNetwork is set,
+	// ClusterFirst otherwise.
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNSConfig can optionally be used to add nameservers, search domains
+	// and resolver options to the tablet Pods' DNS configuration.
+	// It's required if dnsPolicy is None.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+}
+
+// VitessTabletPoolScratch configures a pool of tablets on ephemeral storage.
+type VitessTabletPoolScratch struct {
+	// SizeLimit caps the local storage that each tablet's database files may
+	// use. A tablet that exceeds it is evicted and recreated.
+	// Default: No limit, other than the Node's available ephemeral storage.
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// RestoreInterval is how old a scratch tablet may get before the
+	// operator recreates it to restore a more recent backup. Only one tablet
+	// of the pool is recreated at a time, once every tablet in the pool is
+	// Ready, and only within maintenance windows if any are set.
+	// Default: Tablets are only recreated when they fail or are deleted.
+	RestoreInterval *metav1.Duration `json:"restoreInterval,omitempty"`
+}
+
+// VolumeClaimTemplate configures a PersistentVolumeClaim for a Pod volume.
+type VolumeClaimTemplate struct {
+	// Name is the name of the Pod volume for the claim.
+	Name string `json:"name"`
+
+	// Spec is the spec of the claim.
+	Spec corev1.PersistentVolumeClaimSpec `json:"spec"`
 }
 
+// VitessTabletPoolLocalStorage configures how the operator handles tablets
+// whose local PersistentVolume is lost along with its Node.
+type VitessTabletPoolLocalStorage struct {
+	// NodeLossTimeout is how long a tablet Pod must have been unschedulable
+	// because of the node affinity of its volume before the operator gives up
+	// on the volume. The tablet is only recreated elsewhere if it isn't the
+	// primary, and the shard has a complete backup to restore.
+	// Default: 5m
+	NodeLossTimeout *metav1.Duration `json:"nodeLossTimeout,omitempty"`
+}
+
+// SpreadPolicy is a preset for how to spread tablets out across Nodes and
+// zones.
+type SpreadPolicy string
+
+const (
+	// NoSpreadPolicy doesn't spread tablets out.
+	NoSpreadPolicy SpreadPolicy = "None"
+	// ReplicaNodeSpreadPolicy puts master-eligible replicas of the same shard
+	// on different Nodes, and prefers to put other tablets of the same shard
+	// on different Nodes too.
+	ReplicaNodeSpreadPolicy SpreadPolicy = "ReplicaNode"
+	// NodeSpreadPolicy puts tablets of the same shard on different Nodes.
+	NodeSpreadPolicy SpreadPolicy = "Node"
+	// ZoneSpreadPolicy puts tablets of the same shard on different Nodes, and
+	// spreads tablets of the same shard and type evenly across zones.
+	ZoneSpreadPolicy SpreadPolicy = "Zone"
+)
+
 // VttabletSpec configures the vttablet server within a tablet.
 type VttabletSpec struct {
 	// Resources specify the compute resources to allocate for just the vttablet
@@ -283,6 +615,157 @@ type VttabletSpec struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Lifecycle corev1.Lifecycle `json:"lifecycle,omitempty"`
+
+	// ReadinessProbe can optionally be used to tune or replace the readiness
+	// probe the operator sets on the vttablet container.
+	ReadinessProbe *ProbeOverrides `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe can optionally be used to tune or replace the liveness
+	// probe the operator sets on the vttablet container.
+	LivenessProbe *ProbeOverrides `json:"livenessProbe,omitempty"`
+
+	// QueryServer tunes the connection pools and limits of the vttablet
+	// query server. Flags set in extraFlags take precedence over these
+	// settings.
+	QueryServer *VttabletQueryServerSpec `json:"queryServer,omitempty"`
+
+	// Throttler configures the tablet throttler, which online DDL,
+	// VReplication and other clients check to back off when replicas lag.
+	// The throttler on the primary serves these checks, so it should be
+	// configured the same way in every master-eligible pool of a shard.
+	Throttler *VttabletThrottlerSpec `json:"throttler,omitempty"`
+
+	// TransactionThrottler configures the transaction throttler, which
+	// slows down transactions on the primary when replicas lag.
+	TransactionThrottler *VttabletTransactionThrottlerSpec `json:"transactionThrottler,omitempty"`
+}
+
+// VttabletQueryServerSpec tunes the vttablet query server.
+type VttabletQueryServerSpec struct {
+	// PoolSize is the number of MySQL connections for queries outside of
+	// transactions.
+	// Default: 96
+	// +kubebuilder:validation:Minimum=1
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// StreamPoolSize is the number of MySQL connections for streaming
+	// queries.
+	// Default: 96
+	// +kubebuilder:validation:Minimum=1
+	StreamPoolSize *int32 `json:"streamPoolSize,omitempty"`
+
+	// TransactionCap is the number of MySQL connections for transactions,
+	// which limits how many transactions can be open at once.
+	// Default: 300
+	// +kubebuilder:validation:Minimum=1
+	TransactionCap *int32 `json:"transactionCap,omitempty"`
+
+	// MaxResultSize is the maximum number of rows that a non-streaming
+	// query may return.
+	// Default: 100000
+	// +kubebuilder:validation:Minimum=1
+	MaxResultSize *int32 `json:"maxResultSize,omitempty"`
+
+	// QueryTimeout is how long a query may run before it's killed.
+	// Default: 15m
+	QueryTimeout *metav1.Duration `json:"queryTimeout,omitempty"`
+
+	// TransactionTimeout is how long a transaction may stay open before
+	// it's killed. If unset, the vttablet default is used.
+	TransactionTimeout *metav1.Duration `json:"transactionTimeout,omitempty"`
+
+	// HotRowProtection queues transactions that update the same row, so
+	// they can't take up the whole transaction pool.
+	HotRowProtection *VttabletHotRowProtectionSpec `json:"hotRowProtection,omitempty"`
+}
+
+// VttabletHotRowProtectionSpec configures hot row protection.
+// Fields that are left unset use the vttablet defaults.
+type VttabletHotRowProtectionSpec struct {
+	// Mode is either Disabled, DryRun (only log transactions that would
+	// have been queued), or Enabled.
+	// Default: Disabled
+	Mode HotRowProtectionMode `json:"mode,omitempty"`
+
+	// MaxQueueSize is how many transactions may be queued for the same row.
+	// +kubebuilder:validation:Minimum=1
+	MaxQueueSize *int32 `json:"maxQueueSize,omitempty"`
+
+	// MaxGlobalQueueSize is how many transactions may be queued across all
+	// rows. It must not be lower than maxQueueSize.
+	// +kubebuilder:validation:Minimum=1
+	MaxGlobalQueueSize *int32 `json:"maxGlobalQueueSize,omitempty"`
+
+	// ConcurrentTransactions is how many transactions for the same row are
+	// let through to MySQL at once.
+	// +kubebuilder:validation:Minimum=1
+	ConcurrentTransactions *int32 `json:"concurrentTransactions,omitempty"`
+}
+
+// HotRowProtectionMode is the mode of hot row protection.
+// +kubebuilder:validation:Enum=Disabled;DryRun;Enabled
+type HotRowProtectionMode string
+
+const (
+	// DisabledHotRowProtectionMode turns hot row protection off.
+	DisabledHotRowProtectionMode HotRowProtectionMode = "Disabled"
+	// DryRunHotRowProtectionMode only logs transactions that would have been queued.
+	DryRunHotRowProtectionMode HotRowProtectionMode = "DryRun"
+	// EnabledHotRowProtectionMode queues transactions for hot rows.
+	EnabledHotRowProtectionMode HotRowProtectionMode = "Enabled"
+)
+
+// VttabletThrottlerSpec configures the tablet throttler.
+// Flags set in extraFlags take precedence over these settings.
+type VttabletThrottlerSpec struct {
+	// Enabled turns on the tablet throttler.
+	Enabled bool `json:"enabled"`
+
+	// Threshold is the replication lag above which clients are throttled.
+	// It's ignored if customQuery is set.
+	// Default: 1s
+	Threshold *metav1.Duration `json:"threshold,omitempty"`
+
+	// CustomQuery replaces replication lag with another metric. It must be
+	// either a SELECT that returns a single value, or a
+	// "SHOW GLOBAL STATUS LIKE" or "SHOW GLOBAL VARIABLES LIKE" query.
+	// If set, customQueryThreshold must also be set.
+	CustomQuery string `json:"customQuery,omitempty"`
+
+	// CustomQueryThreshold is the value of customQuery above which clients
+	// are throttled, as a decimal number such as "100" or "0.5".
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	CustomQueryThreshold string `json:"customQueryThreshold,omitempty"`
+
+	// TabletTypes are the types of tablets whose lag is checked, in addition
+	// to replica tablets, which are always checked.
+	TabletTypes []VitessTabletPoolType `json:"tabletTypes,omitempty"`
+
+	// CheckAsCheckSelf makes checks only consider the primary itself,
+	// rather than its replicas.
+	CheckAsCheckSelf bool `json:"checkAsCheckSelf,omitempty"`
+}
+
+// VttabletTransactionThrottlerSpec configures the transaction throttler.
+// Flags set in extraFlags take precedence over these settings.
+type VttabletTransactionThrottlerSpec struct {
+	// Enabled turns on the transaction throttler.
+	Enabled bool `json:"enabled"`
+
+	// TargetReplicationLag is the replication lag that the throttler tries
+	// to keep replicas under, in whole seconds.
+	// Default: 2s
+	TargetReplicationLag *metav1.Duration `json:"targetReplicationLag,omitempty"`
+
+	// MaxReplicationLag is the replication lag above which the throttler
+	// sharply reduces the rate of transactions, in whole seconds.
+	// It must not be lower than targetReplicationLag.
+	// Default: 10s
+	MaxReplicationLag *metav1.Duration `json:"maxReplicationLag,omitempty"`
+
+	// HealthCheckCells are the cells whose replicas are checked for lag.
+	// Default: all cells.
+	HealthCheckCells []string `json:"healthCheckCells,omitempty"`
 }
 
 // MysqldSpec configures the local MySQL server within a tablet.
@@ -296,6 +779,123 @@ type MysqldSpec struct {
 	// to override default my.cnf values (included with Vitess) for this
 	// particular MySQL instance.
 	ConfigOverrides string `json:"configOverrides,omitempty"`
+
+	// ReadinessProbe can optionally be used to tune or replace the readiness
+	// probe the operator sets on the mysqld container.
+	ReadinessProbe *ProbeOverrides `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe can optionally be used to add a liveness probe to the
+	// mysqld container. The operator does not set one by default, so only
+	// the 'custom' field has any effect here.
+	LivenessProbe *ProbeOverrides `json:"livenessProbe,omitempty"`
+}
+
+// MysqldExporterSpec configures the mysqld-exporter sidecar within a tablet.
+//
+// mysqld-exporter connects to MySQL as the vt_exporter account, which the
+// operator creates on the primary of each shard with a generated password,
+// and with only the privileges the exporter needs to read metrics.
+type MysqldExporterSpec struct {
+	// Disabled can be set to true to skip deploying mysqld-exporter in this
+	// tablet pool, even if a mysqld-exporter image is set.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Resources can optionally be used to replace the compute resources the
+	// operator allocates to mysqld-exporter by default.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ExtraFlags can optionally be used to override default flags set by the
+	// operator, or pass additional flags to mysqld_exporter. All entries must
+	// be key-value string pairs of the form "flag": "value". The flag name
+	// should not have any prefix (just "flag", not "--flag"). To set a boolean
+	// flag, set the string value to either "true" or "false".
+	ExtraFlags map[string]string `json:"extraFlags,omitempty"`
+}
+
+// VitessTabletPoolVerticalAutoscaling configures vertical autoscaling of
+// the tablets of a pool.
+//
+// The operator creates a VitessTabletPool object that stands for the pool,
+// and whose scale subresource selects the pool's tablet Pods, along with a
+// VerticalPodAutoscaler that targets it. The VerticalPodAutoscaler only
+// makes recommendations. Its update mode is always "Off", so it never
+// evicts or resizes tablet Pods itself.
+type VitessTabletPoolVerticalAutoscaling struct {
+	// Mode is what the operator does with the recommendations.
+	//
+	// With "Recommend", the recommendations are only reported in the
+	// VitessShard status. With "Auto", the operator also sets the CPU and
+	// memory requests of the containers to the recommended targets, and
+	// scales their limits along with them. The change is rolled out like any
+	// other change to the pool: Pods are resized in place where possible, and
+	// otherwise recreated according to the update strategy, with a planned
+	// reparent before the primary is recreated. Requests are only changed
+	// once they fall outside the range that the VerticalPodAutoscaler
+	// recommends, so tablets aren't restarted for every small change in
+	// usage. With "Off", no VerticalPodAutoscaler is created.
+	// Default: Recommend
+	// +kubebuilder:validation:Enum=Off;Recommend;Auto
+	Mode VerticalAutoscalingMode `json:"mode,omitempty"`
+
+	// MinAllowed are the smallest CPU and memory requests to recommend for
+	// each container.
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+
+	// MaxAllowed are the largest CPU and memory requests to recommend for
+	// each container.
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+}
+
+// VerticalAutoscalingMode is what the operator does with the recommendations
+// for a tablet pool.
+type VerticalAutoscalingMode string
+
+const (
+	// VerticalAutoscalingOff means no recommendations are made.
+	VerticalAutoscalingOff VerticalAutoscalingMode = "Off"
+	// VerticalAutoscalingRecommend means recommendations are only reported.
+	VerticalAutoscalingRecommend VerticalAutoscalingMode = "Recommend"
+	// VerticalAutoscalingAuto means recommendations are applied to tablets.
+	VerticalAutoscalingAuto VerticalAutoscalingMode = "Auto"
+)
+
+// ProbeOverrides customizes a container probe that the operator would
+// otherwise generate with built-in settings.
+//
+// Any timing fields that are set replace the corresponding values in the
+// operator's default probe, leaving the check itself (HTTP path, port, etc.)
+// untouched. If Custom is set, it replaces the default probe entirely and the
+// timing fields are ignored.
+type ProbeOverrides struct {
+	// InitialDelaySeconds is the number of seconds after the container has
+	// started before the probe is initiated.
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// TimeoutSeconds is the number of seconds after which the probe times out.
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// PeriodSeconds is how often (in seconds) to perform the probe.
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// SuccessThreshold is the minimum number of consecutive successes for the
+	// probe to be considered successful after having failed.
+	// Liveness probes only accept a value of 1.
+	// +kubebuilder:validation:Minimum=1
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+
+	// FailureThreshold is the number of consecutive failures after which the
+	// probe is considered failed.
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// Custom can optionally be used to replace the operator's probe with a
+	// fully specified Kubernetes Probe.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Custom *corev1.Probe `json:"custom,omitempty"`
 }
 
 // VitessTabletPoolType represents the tablet types for which it makes sense
@@ -352,6 +952,10 @@ type VitessShardStatus struct {
 	// The generation observed by the controller.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// DryRunChanges lists the changes the operator would make to the
+	// shard's objects, if spec.dryRun is set.
+	DryRunChanges []string `json:"dryRunChanges,omitempty"`
+
 	// Tablets is a summary of the status of all desired tablets in the shard.
 	Tablets map[string]VitessTabletStatus `json:"tablets,omitempty"`
 	// OrphanedTablets is a list of unwanted tablets that could not be turned down.
@@ -360,6 +964,12 @@ type VitessShardStatus struct {
 	// Cells is a list of cells in which any tablets for this shard are deployed.
 	Cells []string `json:"cells,omitempty"`
 
+	// ExternalTablets is the number of tablets of this shard in the topology
+	// that are in cells outside of the VitessCluster, like external cells.
+	// They aren't managed by the operator, and are never removed from the
+	// topology by it.
+	ExternalTablets int32 `json:"externalTablets,omitempty"`
+
 	// VitessOrchestrator is a summary of the status of the vtorc deployment.
 	VitessOrchestrator VitessOrchestratorStatus `json:"vitessOrchestrator,omitempty"`
 
@@ -371,6 +981,10 @@ type VitessShardStatus struct {
 	// has been seeded for the shard.
 	HasInitialBackup corev1.ConditionStatus `json:"hasInitialBackup,omitempty"`
 
+	// QueuedRestores is the number of tablets whose Pods are waiting to be
+	// created, because too many tablets in the cluster are already starting.
+	QueuedRestores int32 `json:"queuedRestores,omitempty"`
+
 	// ServingWrites is a condition indicating whether this shard is the one
 	// that serves writes for its key range, according to Vitess topology.
 	// A shard might be deployed without serving writes if, for example, it is
@@ -402,6 +1016,223 @@ type VitessShardStatus struct {
 	// at least as up-to-date as this VitessShard generation. Changes made in
 	// subsequent generations that affect tablets may not be reflected in status yet.
 	LowestPodGeneration int64 `json:"lowestPodGeneration,omitempty"`
+
+	// MysqlUpgrade reports the progress of an orchestrated MySQL major
+	// version upgrade, if one is in progress or was aborted.
+	MysqlUpgrade *VitessShardMysqlUpgradeStatus `json:"mysqlUpgrade,omitempty"`
+
+	// RolloutBlocked explains why a rolling restart of the shard's tablets
+	// to apply their pending changes isn't proceeding. It's empty if no
+	// rolling restart is in progress, or if it's proceeding.
+	RolloutBlocked string `json:"rolloutBlocked,omitempty"`
+
+	// Throttler reports the result of a tablet throttler check on the
+	// primary, if the throttler is enabled for it.
+	Throttler *VitessShardThrottlerStatus `json:"throttler,omitempty"`
+
+	// Backup reports the latest complete backup of the shard, across all
+	// backup locations.
+	Backup *VitessShardBackupStatus `json:"backup,omitempty"`
+
+	// Reparent records a planned reparent that the operator started to
+	// drain the primary tablet, until its outcome is known. If the operator
+	// stops in the middle of it, the next operator Pod resumes the reparent
+	// to the same tablet instead of starting over with a new candidate.
+	Reparent *VitessShardReparentStatus `json:"reparent,omitempty"`
+
+	// Standby reports how far behind the source cluster the shard is, while
+	// the cluster is a standby.
+	Standby *VitessShardStandbyStatus `json:"standby,omitempty"`
+
+	// Users reports the MySQL users the operator last applied to the
+	// shard's primary.
+	Users *VitessShardUsersStatus `json:"users,omitempty"`
+
+	// InternalCredentials reports the rotation of the passwords of the MySQL
+	// accounts that Vitess uses internally.
+	InternalCredentials *VitessShardInternalCredentialsStatus `json:"internalCredentials,omitempty"`
+
+	// MysqldExporterCredentialsHash is a hash of the password of the MySQL
+	// account that mysqld-exporter connects as, as last set on the primary.
+	MysqldExporterCredentialsHash string `json:"mysqldExporterCredentialsHash,omitempty"`
+
+	// VerticalAutoscaling reports the recommendations for each tablet pool
+	// that uses vertical autoscaling.
+	VerticalAutoscaling []VitessTabletPoolAutoscalingStatus `json:"verticalAutoscaling,omitempty"`
+
+	// LastKnownGood records the images and flags of the shard's tablets the
+	// last time they were all updated and ready.
+	LastKnownGood *VitessShardSnapshot `json:"lastKnownGood,omitempty"`
+
+	// Revisions is a bounded history of the distinct snapshots that were
+	// recorded in lastKnownGood, newest first. Any of them can be named in
+	// spec.revertToRevision.
+	Revisions []VitessShardSnapshot `json:"revisions,omitempty"`
+
+	// RevertedToRevision is the revision that the shard's tablets were
+	// rolled back to, if spec.revertToRevision names a known revision.
+	RevertedToRevision string `json:"revertedToRevision,omitempty"`
+
+	// UnhealthyTablets lists the tablets that have been updated since
+	// lastKnownGood was recorded, but are crash-looping or didn't become
+	// ready within the autoRollback readinessTimeout.
+	UnhealthyTablets []string `json:"unhealthyTablets,omitempty"`
+
+	// RevertedTablets lists the tablets that run the images and flags in
+	// lastKnownGood, rather than those in the spec, because they were
+	// reverted after a rollout was halted.
+	RevertedTablets []string `json:"revertedTablets,omitempty"`
+}
+
+// VitessShardSnapshot records the parts of a shard's spec that a rollout of
+// new images or flags changes.
+type VitessShardSnapshot struct {
+	// Revision is a hash of the images and flags in the snapshot, which
+	// names the snapshot in status.revisions.
+	Revision string `json:"revision,omitempty"`
+	// Generation is the generation of the VitessShard that the snapshot was
+	// taken from.
+	Generation int64 `json:"generation,omitempty"`
+	// Images are the images of the shard's tablets.
+	Images VitessKeyspaceImages `json:"images,omitempty"`
+	// ExtraVitessFlags are the flags passed to every Vitess component.
+	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
+	// VttabletFlags are the flags passed to vttablet in every tablet pool.
+	VttabletFlags map[string]string `json:"vttabletFlags,omitempty"`
+	// TabletPools are the flags passed to vttablet in each tablet pool.
+	TabletPools []VitessShardTabletPoolSnapshot `json:"tabletPools,omitempty"`
+}
+
+// VitessShardTabletPoolSnapshot records the vttablet flags of one tablet pool.
+type VitessShardTabletPoolSnapshot struct {
+	// Cell is the cell of the tablet pool.
+	Cell string `json:"cell"`
+	// Type is the type of the tablet pool.
+	Type VitessTabletPoolType `json:"type"`
+	// Name is the name of the tablet pool, if it's named.
+	Name string `json:"name,omitempty"`
+	// ExtraFlags are the extra vttablet flags of the tablet pool.
+	ExtraFlags map[string]string `json:"extraFlags,omitempty"`
+}
+
+// VitessTabletPoolAutoscalingStatus reports the recommendations for the
+// tablets of one pool.
+type VitessTabletPoolAutoscalingStatus struct {
+	// Cell is the cell of the pool.
+	Cell string `json:"cell"`
+	// Pool identifies the pool within its cell, by its name, or by its type
+	// if it has no name.
+	Pool string `json:"pool"`
+	// Mode is the vertical autoscaling mode of the pool.
+	Mode VerticalAutoscalingMode `json:"mode"`
+	// Message explains why there are no recommendations, if there are none.
+	Message string `json:"message,omitempty"`
+	// Containers lists the recommendations for each container.
+	Containers []VitessContainerAutoscalingStatus `json:"containers,omitempty"`
+}
+
+// VitessContainerAutoscalingStatus reports the recommendation for one
+// container of the tablets of a pool.
+type VitessContainerAutoscalingStatus struct {
+	// Name is the name of the container, like "vttablet" or "mysqld".
+	Name string `json:"name"`
+	// Target is the recommended CPU and memory requests.
+	Target corev1.ResourceList `json:"target,omitempty"`
+	// LowerBound is the smallest CPU and memory requests that the
+	// VerticalPodAutoscaler considers enough.
+	LowerBound corev1.ResourceList `json:"lowerBound,omitempty"`
+	// UpperBound is the largest CPU and memory requests that the
+	// VerticalPodAutoscaler considers useful.
+	UpperBound corev1.ResourceList `json:"upperBound,omitempty"`
+	// AppliedRequests are the CPU and memory requests that the operator sets
+	// on the container in "Auto" mode.
+	AppliedRequests corev1.ResourceList `json:"appliedRequests,omitempty"`
+}
+
+// VitessShardBackupStatus describes the latest complete backup of a shard.
+type VitessShardBackupStatus struct {
+	// Location is the name of the backup location that holds the backup.
+	Location string `json:"location,omitempty"`
+	// StartTime is when the backup started. The data in the backup is as
+	// of a point between StartTime and FinishedTime.
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// FinishedTime is when the backup finished, if known.
+	FinishedTime *metav1.Time `json:"finishedTime,omitempty"`
+	// Engine is the backup engine that took the backup.
+	Engine string `json:"engine,omitempty"`
+	// Size is the total size of the backup files. It's only known for
+	// volume-backed backup locations.
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// VitessShardUsersStatus describes the MySQL users applied to a shard.
+type VitessShardUsersStatus struct {
+	// Applied lists the users as they were last applied, so the operator
+	// knows which users and privileges to remove when they're taken out of
+	// the spec.
+	Applied []VitessMySQLUser `json:"applied,omitempty"`
+	// Hash is a hash of the applied users, including their passwords, so the
+	// operator only applies them again when something changed.
+	Hash string `json:"hash,omitempty"`
+	// LastAppliedTime is when the users were last applied.
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+// VitessShardInternalCredentialsStatus describes the passwords of the
+// internal MySQL accounts of a shard.
+type VitessShardInternalCredentialsStatus struct {
+	// Hash is a hash of the passwords that were last set on the primary.
+	// Tablets are restarted to pick up the passwords once they're set.
+	Hash string `json:"hash,omitempty"`
+	// Accounts lists the internal accounts whose passwords were set.
+	Accounts []string `json:"accounts,omitempty"`
+	// OldPasswordsRetainedSince is set while a rotation is in progress, to
+	// when the new passwords were set on the primary. Until all tablets have
+	// restarted with the new passwords, the old ones are retained as MySQL
+	// secondary passwords, and no other rotation starts.
+	OldPasswordsRetainedSince *metav1.Time `json:"oldPasswordsRetainedSince,omitempty"`
+}
+
+// VitessShardReparentStatus describes a planned reparent in progress.
+type VitessShardReparentStatus struct {
+	// FromTablet is the alias of the primary tablet being drained.
+	FromTablet string `json:"fromTablet"`
+	// ToTablet is the alias of the tablet being promoted.
+	ToTablet string `json:"toTablet"`
+	// StartTime is when the reparent started.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// VitessShardStandbyStatus reports the staleness of a standby shard.
+type VitessShardStandbyStatus struct {
+	// RestoredBackupTime is the start time of the backup that the stalest
+	// tablet restored. Since it's inferred from when the tablet was created,
+	// the tablet may have restored an older backup if this one was still in
+	// progress at the time.
+	RestoredBackupTime *metav1.Time `json:"restoredBackupTime,omitempty"`
+	// LatestBackupTime is the start time of the source cluster's latest
+	// complete backup of the shard.
+	LatestBackupTime *metav1.Time `json:"latestBackupTime,omitempty"`
+	// BackupsBehind is the number of complete backups of the source cluster
+	// that are newer than the one in RestoredBackupTime.
+	BackupsBehind int32 `json:"backupsBehind,omitempty"`
+	// LastRestoreTime is when the most recently restored tablet was created.
+	LastRestoreTime *metav1.Time `json:"lastRestoreTime,omitempty"`
+	// RestoringTablet is the alias of the tablet that's restoring, if any.
+	RestoringTablet string `json:"restoringTablet,omitempty"`
+}
+
+// VitessShardThrottlerStatus is the result of a tablet throttler check.
+type VitessShardThrottlerStatus struct {
+	// Throttled is True if the check was rejected, meaning that clients of
+	// the throttler are currently backing off.
+	Throttled corev1.ConditionStatus `json:"throttled,omitempty"`
+	// Value is the throttled metric, such as the replication lag in seconds.
+	Value string `json:"value,omitempty"`
+	// Threshold is the value above which clients are throttled.
+	Threshold string `json:"threshold,omitempty"`
+	// Message explains the result, or why the check failed.
+	Message string `json:"message,omitempty"`
 }
 
 // VitessOrchestratorStatus is a summary of the status of the vtorc deployment.
@@ -416,6 +1247,38 @@ type VitessOrchestratorStatus struct {
 // VitessShardConditionType and the value is a VitessShardCondition.
 type VitessShardConditionType string
 
+// These are valid conditions of VitessShard.
+const (
+	// VitessShardActionSucceeded reports the outcome of the most recent action
+	// requested with an action annotation like 'planetscale.com/reparent-to'.
+	// The Reason is the name of the action. The status is Unknown while a
+	// long-running action like a backup is still in progress.
+	VitessShardActionSucceeded VitessShardConditionType = "ActionSucceeded"
+	// VitessShardBackupStale is True if the shard's latest complete backup
+	// is older than the stale threshold set in the VitessCluster's backup
+	// spec, or if there's no complete backup at all. It's only reported if
+	// a threshold is set.
+	VitessShardBackupStale VitessShardConditionType = "BackupStale"
+	// VitessShardRoutableFromGates is True if, in each cell where the shard
+	// is in the serving graph, at least one vtgate reports a healthy
+	// connection to the shard's primary. Ready tablet Pods alone don't prove
+	// that queries reach the shard. It's only reported for serving shards.
+	VitessShardRoutableFromGates VitessShardConditionType = "RoutableFromGates"
+	// VitessShardManagedFlagsIgnored is True if extra vttablet or vtorc flags
+	// are left out because the operator manages them, and lists them. It's
+	// only reported while there are such flags.
+	VitessShardManagedFlagsIgnored VitessShardConditionType = "ManagedFlagsIgnored"
+	// VitessShardScratchPoolsValid is False if a tablet pool sets scratch but
+	// isn't an rdonly pool, in which case scratch is ignored, or has no backup
+	// location to restore from. It's only reported while a pool sets scratch.
+	VitessShardScratchPoolsValid VitessShardConditionType = "ScratchPoolsValid"
+	// VitessShardMaintenanceWindowOpen is True if one of the maintenance
+	// windows in the update strategy is open, so disruptive changes, including
+	// planned reparents of a drained primary, are applied. If not, the message
+	// says when the next one opens. It's only reported if windows are set.
+	VitessShardMaintenanceWindowOpen VitessShardConditionType = "MaintenanceWindowOpen"
+)
+
 // VitessShardCondition contains details for the current condition of this VitessShard.
 type VitessShardCondition struct {
 	// Status is the status of the condition.
@@ -449,10 +1312,45 @@ func NewVitessShardStatus() VitessShardStatus {
 	}
 }
 
+// VitessShardMysqlUpgradePhase is a step of a MySQL major version upgrade.
+type VitessShardMysqlUpgradePhase string
+
+const (
+	// MysqlUpgradeReplicasPhase means replicas are being upgraded one at a time.
+	MysqlUpgradeReplicasPhase VitessShardMysqlUpgradePhase = "UpgradingReplicas"
+	// MysqlUpgradePrimaryPhase means all replicas were upgraded, and the
+	// primary is being reparented away and upgraded.
+	MysqlUpgradePrimaryPhase VitessShardMysqlUpgradePhase = "UpgradingPrimary"
+	// MysqlUpgradeRollingBackPhase means the upgrade was aborted, and
+	// upgraded replicas are being restored with the old mysqld image.
+	MysqlUpgradeRollingBackPhase VitessShardMysqlUpgradePhase = "RollingBack"
+	// MysqlUpgradeAbortedPhase means the upgrade was aborted, and all tablets
+	// run the old mysqld image again.
+	MysqlUpgradeAbortedPhase VitessShardMysqlUpgradePhase = "Aborted"
+)
+
+// VitessShardMysqlUpgradeStatus is the progress of a MySQL major version
+// upgrade in a shard.
+type VitessShardMysqlUpgradeStatus struct {
+	// FromImage is the mysqld image the tablets ran before the upgrade.
+	FromImage MysqldImage `json:"fromImage"`
+	// ToImage is the mysqld image the tablets are being upgraded to.
+	ToImage MysqldImage `json:"toImage"`
+	// Phase is the current step of the upgrade.
+	Phase VitessShardMysqlUpgradePhase `json:"phase,omitempty"`
+	// UpgradedTablets lists the aliases of tablets that have been switched
+	// to the new image, in the order they were upgraded.
+	UpgradedTablets []string `json:"upgradedTablets,omitempty"`
+	// Message explains what the upgrade is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+}
+
 // VitessTabletStatus is the status of one tablet in a shard.
 type VitessTabletStatus struct {
 	// PoolType is the target tablet type for the tablet pool.
 	PoolType string `json:"poolType,omitempty"`
+	// Pool is the name of the tablet pool, if it's named.
+	Pool string `json:"pool,omitempty"`
 	// Index is the tablet's index within its tablet pool.
 	Index int32 `json:"index,omitempty"`
 	// Running indicates whether the vttablet Pod is running.
@@ -471,6 +1369,18 @@ type VitessTabletStatus struct {
 	// PendingChanges describes changes to the tablet Pod that will be applied
 	// the next time a rolling update allows.
 	PendingChanges string `json:"pendingChanges,omitempty"`
+	// Position is the GTID set that the tablet's MySQL had executed the last
+	// time the operator checked, which is how far the tablet has replicated.
+	Position string `json:"position,omitempty"`
+	// ReplicationLagSeconds is how far a replica was behind its source the
+	// last time the operator checked. It's unset for the primary, and for
+	// replicas whose lag is unknown, for example because replication is
+	// stopped.
+	ReplicationLagSeconds *int32 `json:"replicationLagSeconds,omitempty"`
+	// Hostname is the stable DNS name of the tablet in the vttablet Service,
+	// which resolves to its current Pod IPs. It's only set if tablets
+	// advertise hostnames (see NetworkingSpec.TabletAddress).
+	Hostname string `json:"hostname,omitempty"`
 }
 
 // NewVitessTabletStatus creates a new status object with default values.
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+//
+// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessTabletPool stands for the tablets of one tablet pool of a shard.
+//
+// The VitessShard controller creates one for each tablet pool that uses
+// vertical autoscaling, as the target of the pool's VerticalPodAutoscaler,
+// which finds the Pods to size through the scale subresource. It only mirrors
+// the pool, so any changes to it are overwritten, and scaling it has no
+// effect. Change the pool in the VitessCluster instead.
+// +kubebuilder:resource:path=vitesstabletpools,shortName=vttp
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Cell",type=string,JSONPath=`.spec.cell`
+// +kubebuilder:printcolumn:name="Pool",type=string,JSONPath=`.spec.pool`
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.spec.replicas`
+type VitessTabletPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VitessTabletPoolSpec   `json:"spec,omitempty"`
+	Status VitessTabletPoolStatus `json:"status,omitempty"`
+}
+
+// VitessTabletPoolSpec describes a tablet pool.
+type VitessTabletPoolSpec struct {
+	// Cell is the cell of the pool.
+	Cell string `json:"cell"`
+	// Pool identifies the pool within its cell, by its name, or by its type
+	// if it has no name.
+	Pool string `json:"pool"`
+	// Replicas is the number of tablets in the pool.
+	Replicas int32 `json:"replicas"`
+}
+
+// VitessTabletPoolStatus is what the scale subresource reports for a
+// tablet pool.
+type VitessTabletPoolStatus struct {
+	// Replicas is the number of tablets in the pool.
+	Replicas int32 `json:"replicas"`
+	// Selector is the label selector of the pool's tablet Pods, in string
+	// form.
+	Selector string `json:"selector,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessTabletPoolList contains a list of VitessTabletPools.
+type VitessTabletPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VitessTabletPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VitessTabletPool{}, &VitessTabletPoolList{})
+}
@@ -7,6 +7,7 @@ package v2
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -26,6 +27,26 @@ func (in *AzblobBackupLocation) DeepCopy() *AzblobBackupLocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupCompressionSpec) DeepCopyInto(out *BackupCompressionSpec) {
+	*out = *in
+	if in.Level != nil {
+		in, out := &in.Level, &out.Level
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupCompressionSpec.
+func (in *BackupCompressionSpec) DeepCopy() *BackupCompressionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupCompressionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephBackupLocation) DeepCopyInto(out *CephBackupLocation) {
 	*out = *in
@@ -52,11 +73,41 @@ func (in *ClusterBackupSpec) DeepCopyInto(out *ClusterBackupSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(BackupCompressionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Xtrabackup != nil {
+		in, out := &in.Xtrabackup, &out.Xtrabackup
+		*out = new(XtrabackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Subcontroller != nil {
 		in, out := &in.Subcontroller, &out.Subcontroller
 		*out = new(VitessBackupSubcontrollerSpec)
 		**out = **in
 	}
+	if in.StaleThreshold != nil {
+		in, out := &in.StaleThreshold, &out.StaleThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DisruptionPolicy != nil {
+		in, out := &in.DisruptionPolicy, &out.DisruptionPolicy
+		*out = new(VitessBackupDisruptionPolicy)
+		**out = **in
+	}
+	if in.MaxConcurrentRestores != nil {
+		in, out := &in.MaxConcurrentRestores, &out.MaxConcurrentRestores
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Vtbackup != nil {
+		in, out := &in.Vtbackup, &out.Vtbackup
+		*out = new(VtbackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupSpec.
@@ -69,6 +120,74 @@ func (in *ClusterBackupSpec) DeepCopy() *ClusterBackupSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonLabelPolicy) DeepCopyInto(out *CommonLabelPolicy) {
+	*out = *in
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonLabelPolicy.
+func (in *CommonLabelPolicy) DeepCopy() *CommonLabelPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonLabelPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentVitessFlags) DeepCopyInto(out *ComponentVitessFlags) {
+	*out = *in
+	if in.Vtgate != nil {
+		in, out := &in.Vtgate, &out.Vtgate
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Vttablet != nil {
+		in, out := &in.Vttablet, &out.Vttablet
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Vtctld != nil {
+		in, out := &in.Vtctld, &out.Vtctld
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Vtorc != nil {
+		in, out := &in.Vtorc, &out.Vtorc
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowManagedFlags != nil {
+		in, out := &in.AllowManagedFlags, &out.AllowManagedFlags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentVitessFlags.
+func (in *ComponentVitessFlags) DeepCopy() *ComponentVitessFlags {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentVitessFlags)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdLockserver) DeepCopyInto(out *EtcdLockserver) {
 	*out = *in
@@ -132,6 +251,11 @@ func (in *EtcdLockserverList) DeepCopyObject() runtime.Object {
 func (in *EtcdLockserverSpec) DeepCopyInto(out *EtcdLockserverSpec) {
 	*out = *in
 	in.EtcdLockserverTemplate.DeepCopyInto(&out.EtcdLockserverTemplate)
+	if in.ResourceDefaults != nil {
+		in, out := &in.ResourceDefaults, &out.ResourceDefaults
+		*out = new(ResourceDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdLockserverSpec.
@@ -284,6 +408,46 @@ func (in *EtcdLockserverTemplate) DeepCopy() *EtcdLockserverTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvacuationSpec) DeepCopyInto(out *EvacuationSpec) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvacuationSpec.
+func (in *EvacuationSpec) DeepCopy() *EvacuationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EvacuationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNSConfig) DeepCopyInto(out *ExternalDNSConfig) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSConfig.
+func (in *ExternalDNSConfig) DeepCopy() *ExternalDNSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDatastore) DeepCopyInto(out *ExternalDatastore) {
 	*out = *in
@@ -325,6 +489,46 @@ func (in *ExternalVitessClusterUpdateStrategyOptions) DeepCopy() *ExternalVitess
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverBufferSpec) DeepCopyInto(out *FailoverBufferSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxFailoverDuration != nil {
+		in, out := &in.MaxFailoverDuration, &out.MaxFailoverDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinTimeBetweenFailovers != nil {
+		in, out := &in.MinTimeBetweenFailovers, &out.MinTimeBetweenFailovers
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverBufferSpec.
+func (in *FailoverBufferSpec) DeepCopy() *FailoverBufferSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverBufferSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GCSBackupLocation) DeepCopyInto(out *GCSBackupLocation) {
 	*out = *in
@@ -351,7 +555,7 @@ func (in *LockserverSpec) DeepCopyInto(out *LockserverSpec) {
 	if in.External != nil {
 		in, out := &in.External, &out.External
 		*out = new(VitessLockserverParams)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Etcd != nil {
 		in, out := &in.Etcd, &out.Etcd
@@ -391,669 +595,709 @@ func (in *LockserverStatus) DeepCopy() *LockserverStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MysqldImage) DeepCopyInto(out *MysqldImage) {
+func (in *LookupVindexStatus) DeepCopyInto(out *LookupVindexStatus) {
 	*out = *in
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]VReplicationStreamStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldImage.
-func (in *MysqldImage) DeepCopy() *MysqldImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LookupVindexStatus.
+func (in *LookupVindexStatus) DeepCopy() *LookupVindexStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MysqldImage)
+	out := new(LookupVindexStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MysqldSpec) DeepCopyInto(out *MysqldSpec) {
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
+	out.Duration = in.Duration
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldSpec.
-func (in *MysqldSpec) DeepCopy() *MysqldSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(MysqldSpec)
+	out := new(MaintenanceWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OrphanStatus) DeepCopyInto(out *OrphanStatus) {
+func (in *MaterializeStatus) DeepCopyInto(out *MaterializeStatus) {
 	*out = *in
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]VReplicationStreamStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanStatus.
-func (in *OrphanStatus) DeepCopy() *OrphanStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaterializeStatus.
+func (in *MaterializeStatus) DeepCopy() *MaterializeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OrphanStatus)
+	out := new(MaterializeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReshardingStatus) DeepCopyInto(out *ReshardingStatus) {
+func (in *MysqlUpgradeStrategy) DeepCopyInto(out *MysqlUpgradeStrategy) {
 	*out = *in
-	if in.SourceShards != nil {
-		in, out := &in.SourceShards, &out.SourceShards
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqlUpgradeStrategy.
+func (in *MysqlUpgradeStrategy) DeepCopy() *MysqlUpgradeStrategy {
+	if in == nil {
+		return nil
 	}
-	if in.TargetShards != nil {
-		in, out := &in.TargetShards, &out.TargetShards
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(MysqlUpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MysqldExporterSpec) DeepCopyInto(out *MysqldExporterSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReshardingStatus.
-func (in *ReshardingStatus) DeepCopy() *ReshardingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldExporterSpec.
+func (in *MysqldExporterSpec) DeepCopy() *MysqldExporterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReshardingStatus)
+	out := new(MysqldExporterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3BackupLocation) DeepCopyInto(out *S3BackupLocation) {
+func (in *MysqldImage) DeepCopyInto(out *MysqldImage) {
 	*out = *in
-	if in.AuthSecret != nil {
-		in, out := &in.AuthSecret, &out.AuthSecret
-		*out = new(SecretSource)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupLocation.
-func (in *S3BackupLocation) DeepCopy() *S3BackupLocation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldImage.
+func (in *MysqldImage) DeepCopy() *MysqldImage {
 	if in == nil {
 		return nil
 	}
-	out := new(S3BackupLocation)
+	out := new(MysqldImage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretSource) DeepCopyInto(out *SecretSource) {
+func (in *MysqldSpec) DeepCopyInto(out *MysqldSpec) {
 	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ProbeOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeOverrides)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSource.
-func (in *SecretSource) DeepCopy() *SecretSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldSpec.
+func (in *MysqldSpec) DeepCopy() *MysqldSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretSource)
+	out := new(MysqldSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceOverrides) DeepCopyInto(out *ServiceOverrides) {
+func (in *NetworkPolicySpec) DeepCopyInto(out *NetworkPolicySpec) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OperatorPodLabels != nil {
+		in, out := &in.OperatorPodLabels, &out.OperatorPodLabels
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.PeerNamespaceSelector != nil {
+		in, out := &in.PeerNamespaceSelector, &out.PeerNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOverrides.
-func (in *ServiceOverrides) DeepCopy() *ServiceOverrides {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySpec.
+func (in *NetworkPolicySpec) DeepCopy() *NetworkPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceOverrides)
+	out := new(NetworkPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ShardBackupLocationStatus) DeepCopyInto(out *ShardBackupLocationStatus) {
+func (in *NetworkingSpec) DeepCopyInto(out *NetworkingSpec) {
 	*out = *in
-	if in.LatestCompleteBackupTime != nil {
-		in, out := &in.LatestCompleteBackupTime, &out.LatestCompleteBackupTime
-		*out = (*in).DeepCopy()
+	if in.IPFamilyPolicy != nil {
+		in, out := &in.IPFamilyPolicy, &out.IPFamilyPolicy
+		*out = new(v1.IPFamilyPolicy)
+		**out = **in
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]v1.IPFamily, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardBackupLocationStatus.
-func (in *ShardBackupLocationStatus) DeepCopy() *ShardBackupLocationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkingSpec.
+func (in *NetworkingSpec) DeepCopy() *NetworkingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ShardBackupLocationStatus)
+	out := new(NetworkingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TopoReconcileConfig) DeepCopyInto(out *TopoReconcileConfig) {
+func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
 	*out = *in
-	if in.RegisterCellsAliases != nil {
-		in, out := &in.RegisterCellsAliases, &out.RegisterCellsAliases
-		*out = new(bool)
-		**out = **in
-	}
-	if in.RegisterCells != nil {
-		in, out := &in.RegisterCells, &out.RegisterCells
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneCells != nil {
-		in, out := &in.PruneCells, &out.PruneCells
+	if in.PrometheusOperator != nil {
+		in, out := &in.PrometheusOperator, &out.PrometheusOperator
 		*out = new(bool)
 		**out = **in
 	}
-	if in.PruneKeyspaces != nil {
-		in, out := &in.PruneKeyspaces, &out.PruneKeyspaces
-		*out = new(bool)
-		**out = **in
+	if in.MonitorLabels != nil {
+		in, out := &in.MonitorLabels, &out.MonitorLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.PruneSrvKeyspaces != nil {
-		in, out := &in.PruneSrvKeyspaces, &out.PruneSrvKeyspaces
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
+func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+	if in == nil {
+		return nil
 	}
-	if in.PruneShards != nil {
-		in, out := &in.PruneShards, &out.PruneShards
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneShardCells != nil {
-		in, out := &in.PruneShardCells, &out.PruneShardCells
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneTablets != nil {
-		in, out := &in.PruneTablets, &out.PruneTablets
-		*out = new(bool)
-		**out = **in
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoReconcileConfig.
-func (in *TopoReconcileConfig) DeepCopy() *TopoReconcileConfig {
-	if in == nil {
-		return nil
-	}
-	out := new(TopoReconcileConfig)
+	out := new(ObservabilitySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackup) DeepCopyInto(out *VitessBackup) {
+func (in *OrphanStatus) DeepCopyInto(out *OrphanStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackup.
-func (in *VitessBackup) DeepCopy() *VitessBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanStatus.
+func (in *OrphanStatus) DeepCopy() *OrphanStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackup)
+	out := new(OrphanStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackup) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupList) DeepCopyInto(out *VitessBackupList) {
+func (in *ProbeOverrides) DeepCopyInto(out *ProbeOverrides) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VitessBackup, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupList.
-func (in *VitessBackupList) DeepCopy() *VitessBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeOverrides.
+func (in *ProbeOverrides) DeepCopy() *ProbeOverrides {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupList)
+	out := new(ProbeOverrides)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackupList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupLocation) DeepCopyInto(out *VitessBackupLocation) {
+func (in *ReshardCutoverStatus) DeepCopyInto(out *ReshardCutoverStatus) {
 	*out = *in
-	if in.GCS != nil {
-		in, out := &in.GCS, &out.GCS
-		*out = new(GCSBackupLocation)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.S3 != nil {
-		in, out := &in.S3, &out.S3
-		*out = new(S3BackupLocation)
-		(*in).DeepCopyInto(*out)
+	if in.CopyStartTime != nil {
+		in, out := &in.CopyStartTime, &out.CopyStartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Azblob != nil {
-		in, out := &in.Azblob, &out.Azblob
-		*out = new(AzblobBackupLocation)
-		**out = **in
+	if in.CopyEndTime != nil {
+		in, out := &in.CopyEndTime, &out.CopyEndTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Ceph != nil {
-		in, out := &in.Ceph, &out.Ceph
-		*out = new(CephBackupLocation)
-		**out = **in
+	if in.SwitchTime != nil {
+		in, out := &in.SwitchTime, &out.SwitchTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Volume != nil {
-		in, out := &in.Volume, &out.Volume
-		*out = new(v1.VolumeSource)
-		(*in).DeepCopyInto(*out)
+	if in.RollbackTime != nil {
+		in, out := &in.RollbackTime, &out.RollbackTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.CompleteTime != nil {
+		in, out := &in.CompleteTime, &out.CompleteTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupLocation.
-func (in *VitessBackupLocation) DeepCopy() *VitessBackupLocation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReshardCutoverStatus.
+func (in *ReshardCutoverStatus) DeepCopy() *ReshardCutoverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupLocation)
+	out := new(ReshardCutoverStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupSpec) DeepCopyInto(out *VitessBackupSpec) {
+func (in *ReshardingStatus) DeepCopyInto(out *ReshardingStatus) {
 	*out = *in
+	if in.SourceShards != nil {
+		in, out := &in.SourceShards, &out.SourceShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetShards != nil {
+		in, out := &in.TargetShards, &out.TargetShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]VReplicationStreamStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSpec.
-func (in *VitessBackupSpec) DeepCopy() *VitessBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReshardingStatus.
+func (in *ReshardingStatus) DeepCopy() *ReshardingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupSpec)
+	out := new(ReshardingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStatus) DeepCopyInto(out *VitessBackupStatus) {
+func (in *ResourceDefaultsSpec) DeepCopyInto(out *ResourceDefaultsSpec) {
 	*out = *in
-	in.StartTime.DeepCopyInto(&out.StartTime)
-	if in.FinishedTime != nil {
-		in, out := &in.FinishedTime, &out.FinishedTime
-		*out = (*in).DeepCopy()
+	if in.DefaultRequests != nil {
+		in, out := &in.DefaultRequests, &out.DefaultRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DefaultLimits != nil {
+		in, out := &in.DefaultLimits, &out.DefaultLimits
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MinRequests != nil {
+		in, out := &in.MinRequests, &out.MinRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxLimits != nil {
+		in, out := &in.MaxLimits, &out.MaxLimits
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStatus.
-func (in *VitessBackupStatus) DeepCopy() *VitessBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDefaultsSpec.
+func (in *ResourceDefaultsSpec) DeepCopy() *ResourceDefaultsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStatus)
+	out := new(ResourceDefaultsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorage) DeepCopyInto(out *VitessBackupStorage) {
+func (in *ResourceRecommendationsSpec) DeepCopyInto(out *ResourceRecommendationsSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HeadroomPercent != nil {
+		in, out := &in.HeadroomPercent, &out.HeadroomPercent
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorage.
-func (in *VitessBackupStorage) DeepCopy() *VitessBackupStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendationsSpec.
+func (in *ResourceRecommendationsSpec) DeepCopy() *ResourceRecommendationsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorage)
+	out := new(ResourceRecommendationsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackupStorage) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorageList) DeepCopyInto(out *VitessBackupStorageList) {
+func (in *RolloutAutoRollbackSpec) DeepCopyInto(out *RolloutAutoRollbackSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VitessBackupStorage, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MaxUnhealthyTablets != nil {
+		in, out := &in.MaxUnhealthyTablets, &out.MaxUnhealthyTablets
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadinessTimeout != nil {
+		in, out := &in.ReadinessTimeout, &out.ReadinessTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CrashLoopRestarts != nil {
+		in, out := &in.CrashLoopRestarts, &out.CrashLoopRestarts
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageList.
-func (in *VitessBackupStorageList) DeepCopy() *VitessBackupStorageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutAutoRollbackSpec.
+func (in *RolloutAutoRollbackSpec) DeepCopy() *RolloutAutoRollbackSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorageList)
+	out := new(RolloutAutoRollbackSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackupStorageList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorageSpec) DeepCopyInto(out *VitessBackupStorageSpec) {
+func (in *RolloutCoordinatorSpec) DeepCopyInto(out *RolloutCoordinatorSpec) {
 	*out = *in
-	in.Location.DeepCopyInto(&out.Location)
-	if in.Subcontroller != nil {
-		in, out := &in.Subcontroller, &out.Subcontroller
-		*out = new(VitessBackupSubcontrollerSpec)
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = make([]RolloutCoordinatorStage, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxConcurrentCells != nil {
+		in, out := &in.MaxConcurrentCells, &out.MaxConcurrentCells
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrentKeyspaces != nil {
+		in, out := &in.MaxConcurrentKeyspaces, &out.MaxConcurrentKeyspaces
+		*out = new(int32)
 		**out = **in
 	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(RolloutAutoRollbackSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageSpec.
-func (in *VitessBackupStorageSpec) DeepCopy() *VitessBackupStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutCoordinatorSpec.
+func (in *RolloutCoordinatorSpec) DeepCopy() *RolloutCoordinatorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorageSpec)
+	out := new(RolloutCoordinatorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorageStatus) DeepCopyInto(out *VitessBackupStorageStatus) {
+func (in *S3BackupLocation) DeepCopyInto(out *S3BackupLocation) {
 	*out = *in
+	if in.AuthSecret != nil {
+		in, out := &in.AuthSecret, &out.AuthSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageStatus.
-func (in *VitessBackupStorageStatus) DeepCopy() *VitessBackupStorageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupLocation.
+func (in *S3BackupLocation) DeepCopy() *S3BackupLocation {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorageStatus)
+	out := new(S3BackupLocation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupSubcontrollerSpec) DeepCopyInto(out *VitessBackupSubcontrollerSpec) {
+func (in *SecretSource) DeepCopyInto(out *SecretSource) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSubcontrollerSpec.
-func (in *VitessBackupSubcontrollerSpec) DeepCopy() *VitessBackupSubcontrollerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSource.
+func (in *SecretSource) DeepCopy() *SecretSource {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupSubcontrollerSpec)
+	out := new(SecretSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCell) DeepCopyInto(out *VitessCell) {
+func (in *ServiceOverrides) DeepCopyInto(out *ServiceOverrides) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCell.
-func (in *VitessCell) DeepCopy() *VitessCell {
-	if in == nil {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOverrides.
+func (in *ServiceOverrides) DeepCopy() *ServiceOverrides {
+	if in == nil {
 		return nil
 	}
-	out := new(VitessCell)
+	out := new(ServiceOverrides)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessCell) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardBackupLocationStatus) DeepCopyInto(out *ShardBackupLocationStatus) {
+	*out = *in
+	if in.LatestCompleteBackupTime != nil {
+		in, out := &in.LatestCompleteBackupTime, &out.LatestCompleteBackupTime
+		*out = (*in).DeepCopy()
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardBackupLocationStatus.
+func (in *ShardBackupLocationStatus) DeepCopy() *ShardBackupLocationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardBackupLocationStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellGatewaySpec) DeepCopyInto(out *VitessCellGatewaySpec) {
+func (in *TopoReconcileConfig) DeepCopyInto(out *TopoReconcileConfig) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	if in.RegisterCellsAliases != nil {
+		in, out := &in.RegisterCellsAliases, &out.RegisterCellsAliases
+		*out = new(bool)
 		**out = **in
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	in.Authentication.DeepCopyInto(&out.Authentication)
-	if in.SecureTransport != nil {
-		in, out := &in.SecureTransport, &out.SecureTransport
-		*out = new(VitessGatewaySecureTransport)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ExtraFlags != nil {
-		in, out := &in.ExtraFlags, &out.ExtraFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExtraEnv != nil {
-		in, out := &in.ExtraEnv, &out.ExtraEnv
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumeMounts != nil {
-		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RegisterCells != nil {
+		in, out := &in.RegisterCells, &out.RegisterCells
+		*out = new(bool)
+		**out = **in
 	}
-	if in.SidecarContainers != nil {
-		in, out := &in.SidecarContainers, &out.SidecarContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PruneCells != nil {
+		in, out := &in.PruneCells, &out.PruneCells
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
+	if in.PruneKeyspaces != nil {
+		in, out := &in.PruneKeyspaces, &out.PruneKeyspaces
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.PruneSrvKeyspaces != nil {
+		in, out := &in.PruneSrvKeyspaces, &out.PruneSrvKeyspaces
+		*out = new(bool)
+		**out = **in
 	}
-	if in.ExtraLabels != nil {
-		in, out := &in.ExtraLabels, &out.ExtraLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.PruneShards != nil {
+		in, out := &in.PruneShards, &out.PruneShards
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
+	if in.PruneShardCells != nil {
+		in, out := &in.PruneShardCells, &out.PruneShardCells
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PruneTablets != nil {
+		in, out := &in.PruneTablets, &out.PruneTablets
+		*out = new(bool)
+		**out = **in
 	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]v1.TopologySpreadConstraint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RebuildServingGraph != nil {
+		in, out := &in.RebuildServingGraph, &out.RebuildServingGraph
+		*out = new(bool)
+		**out = **in
 	}
-	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
-	if in.TerminationGracePeriodSeconds != nil {
-		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
-		*out = new(int64)
+	if in.ManageShards != nil {
+		in, out := &in.ManageShards, &out.ManageShards
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewaySpec.
-func (in *VitessCellGatewaySpec) DeepCopy() *VitessCellGatewaySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoReconcileConfig.
+func (in *TopoReconcileConfig) DeepCopy() *TopoReconcileConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellGatewaySpec)
+	out := new(TopoReconcileConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellGatewayStatus) DeepCopyInto(out *VitessCellGatewayStatus) {
+func (in *VReplicationStreamStatus) DeepCopyInto(out *VReplicationStreamStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewayStatus.
-func (in *VitessCellGatewayStatus) DeepCopy() *VitessCellGatewayStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VReplicationStreamStatus.
+func (in *VReplicationStreamStatus) DeepCopy() *VReplicationStreamStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellGatewayStatus)
+	out := new(VReplicationStreamStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellImages) DeepCopyInto(out *VitessCellImages) {
+func (in *VitessAuditObjectReference) DeepCopyInto(out *VitessAuditObjectReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellImages.
-func (in *VitessCellImages) DeepCopy() *VitessCellImages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAuditObjectReference.
+func (in *VitessAuditObjectReference) DeepCopy() *VitessAuditObjectReference {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellImages)
+	out := new(VitessAuditObjectReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellKeyspaceStatus) DeepCopyInto(out *VitessCellKeyspaceStatus) {
+func (in *VitessAuditRecord) DeepCopyInto(out *VitessAuditRecord) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellKeyspaceStatus.
-func (in *VitessCellKeyspaceStatus) DeepCopy() *VitessCellKeyspaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAuditRecord.
+func (in *VitessAuditRecord) DeepCopy() *VitessAuditRecord {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellKeyspaceStatus)
+	out := new(VitessAuditRecord)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessAuditRecord) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellList) DeepCopyInto(out *VitessCellList) {
+func (in *VitessAuditRecordList) DeepCopyInto(out *VitessAuditRecordList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VitessCell, len(*in))
+		*out = make([]VitessAuditRecord, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellList.
-func (in *VitessCellList) DeepCopy() *VitessCellList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAuditRecordList.
+func (in *VitessAuditRecordList) DeepCopy() *VitessAuditRecordList {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellList)
+	out := new(VitessAuditRecordList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessCellList) DeepCopyObject() runtime.Object {
+func (in *VitessAuditRecordList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1061,108 +1305,91 @@ func (in *VitessCellList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellSpec) DeepCopyInto(out *VitessCellSpec) {
+func (in *VitessAuditRecordSpec) DeepCopyInto(out *VitessAuditRecordSpec) {
 	*out = *in
-	in.VitessCellTemplate.DeepCopyInto(&out.VitessCellTemplate)
-	out.GlobalLockserver = in.GlobalLockserver
-	if in.AllCells != nil {
-		in, out := &in.AllCells, &out.AllCells
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	out.Images = in.Images
-	out.ImagePullPolicies = in.ImagePullPolicies
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.ExtraVitessFlags != nil {
-		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.TopologyReconciliation != nil {
-		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
-		*out = new(TopoReconcileConfig)
-		(*in).DeepCopyInto(*out)
-	}
+	in.Time.DeepCopyInto(&out.Time)
+	out.Object = in.Object
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellSpec.
-func (in *VitessCellSpec) DeepCopy() *VitessCellSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAuditRecordSpec.
+func (in *VitessAuditRecordSpec) DeepCopy() *VitessAuditRecordSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellSpec)
+	out := new(VitessAuditRecordSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellStatus) DeepCopyInto(out *VitessCellStatus) {
+func (in *VitessBackup) DeepCopyInto(out *VitessBackup) {
 	*out = *in
-	in.Lockserver.DeepCopyInto(&out.Lockserver)
-	out.Gateway = in.Gateway
-	if in.Keyspaces != nil {
-		in, out := &in.Keyspaces, &out.Keyspaces
-		*out = make(map[string]VitessCellKeyspaceStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellStatus.
-func (in *VitessCellStatus) DeepCopy() *VitessCellStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackup.
+func (in *VitessBackup) DeepCopy() *VitessBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellStatus)
+	out := new(VitessBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellTemplate) DeepCopyInto(out *VitessCellTemplate) {
+func (in *VitessBackupDisruptionPolicy) DeepCopyInto(out *VitessBackupDisruptionPolicy) {
 	*out = *in
-	in.Lockserver.DeepCopyInto(&out.Lockserver)
-	in.Gateway.DeepCopyInto(&out.Gateway)
+	out.MaxAge = in.MaxAge
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellTemplate.
-func (in *VitessCellTemplate) DeepCopy() *VitessCellTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupDisruptionPolicy.
+func (in *VitessBackupDisruptionPolicy) DeepCopy() *VitessBackupDisruptionPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellTemplate)
+	out := new(VitessBackupDisruptionPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCluster) DeepCopyInto(out *VitessCluster) {
+func (in *VitessBackupList) DeepCopyInto(out *VitessBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCluster.
-func (in *VitessCluster) DeepCopy() *VitessCluster {
-	if in == nil {
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupList.
+func (in *VitessBackupList) DeepCopy() *VitessBackupList {
+	if in == nil {
 		return nil
 	}
-	out := new(VitessCluster)
+	out := new(VitessBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessCluster) DeepCopyObject() runtime.Object {
+func (in *VitessBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1170,66 +1397,2465 @@ func (in *VitessCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterCellStatus) DeepCopyInto(out *VitessClusterCellStatus) {
+func (in *VitessBackupLocation) DeepCopyInto(out *VitessBackupLocation) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(GCSBackupLocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupLocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Azblob != nil {
+		in, out := &in.Azblob, &out.Azblob
+		*out = new(AzblobBackupLocation)
+		**out = **in
+	}
+	if in.Ceph != nil {
+		in, out := &in.Ceph, &out.Ceph
+		*out = new(CephBackupLocation)
+		**out = **in
+	}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeClaim != nil {
+		in, out := &in.VolumeClaim, &out.VolumeClaim
+		*out = new(VolumeClaimBackupLocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(VitessBackupRetention)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupLocation.
+func (in *VitessBackupLocation) DeepCopy() *VitessBackupLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupRetention) DeepCopyInto(out *VitessBackupRetention) {
+	*out = *in
+	out.MaxAge = in.MaxAge
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupRetention.
+func (in *VitessBackupRetention) DeepCopy() *VitessBackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupSpec) DeepCopyInto(out *VitessBackupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSpec.
+func (in *VitessBackupSpec) DeepCopy() *VitessBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupStatus) DeepCopyInto(out *VitessBackupStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.FinishedTime != nil {
+		in, out := &in.FinishedTime, &out.FinishedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStatus.
+func (in *VitessBackupStatus) DeepCopy() *VitessBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupStorage) DeepCopyInto(out *VitessBackupStorage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorage.
+func (in *VitessBackupStorage) DeepCopy() *VitessBackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessBackupStorage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupStorageList) DeepCopyInto(out *VitessBackupStorageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessBackupStorage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageList.
+func (in *VitessBackupStorageList) DeepCopy() *VitessBackupStorageList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupStorageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessBackupStorageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupStorageSpec) DeepCopyInto(out *VitessBackupStorageSpec) {
+	*out = *in
+	in.Location.DeepCopyInto(&out.Location)
+	if in.Subcontroller != nil {
+		in, out := &in.Subcontroller, &out.Subcontroller
+		*out = new(VitessBackupSubcontrollerSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageSpec.
+func (in *VitessBackupStorageSpec) DeepCopy() *VitessBackupStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupStorageStatus) DeepCopyInto(out *VitessBackupStorageStatus) {
+	*out = *in
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(VitessBackupVolumeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageStatus.
+func (in *VitessBackupStorageStatus) DeepCopy() *VitessBackupStorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupStorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupSubcontrollerSpec) DeepCopyInto(out *VitessBackupSubcontrollerSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSubcontrollerSpec.
+func (in *VitessBackupSubcontrollerSpec) DeepCopy() *VitessBackupSubcontrollerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupSubcontrollerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupVolumeStatus) DeepCopyInto(out *VitessBackupVolumeStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupVolumeStatus.
+func (in *VitessBackupVolumeStatus) DeepCopy() *VitessBackupVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCell) DeepCopyInto(out *VitessCell) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCell.
+func (in *VitessCell) DeepCopy() *VitessCell {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCell)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessCell) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellGatewaySpec) DeepCopyInto(out *VitessCellGatewaySpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Authentication.DeepCopyInto(&out.Authentication)
+	if in.SecureTransport != nil {
+		in, out := &in.SecureTransport, &out.SecureTransport
+		*out = new(VitessGatewaySecureTransport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CellsToWatch != nil {
+		in, out := &in.CellsToWatch, &out.CellsToWatch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedTabletTypes != nil {
+		in, out := &in.AllowedTabletTypes, &out.AllowedTabletTypes
+		*out = make([]VitessGatewayTabletType, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailoverBuffer != nil {
+		in, out := &in.FailoverBuffer, &out.FailoverBuffer
+		*out = new(FailoverBufferSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewaySpec.
+func (in *VitessCellGatewaySpec) DeepCopy() *VitessCellGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellGatewayStatus) DeepCopyInto(out *VitessCellGatewayStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewayStatus.
+func (in *VitessCellGatewayStatus) DeepCopy() *VitessCellGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellImages) DeepCopyInto(out *VitessCellImages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellImages.
+func (in *VitessCellImages) DeepCopy() *VitessCellImages {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellKeyspaceStatus) DeepCopyInto(out *VitessCellKeyspaceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellKeyspaceStatus.
+func (in *VitessCellKeyspaceStatus) DeepCopy() *VitessCellKeyspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellKeyspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellList) DeepCopyInto(out *VitessCellList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessCell, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellList.
+func (in *VitessCellList) DeepCopy() *VitessCellList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessCellList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellSpec) DeepCopyInto(out *VitessCellSpec) {
+	*out = *in
+	in.VitessCellTemplate.DeepCopyInto(&out.VitessCellTemplate)
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	if in.AllCells != nil {
+		in, out := &in.AllCells, &out.AllCells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Images = in.Images
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ComponentVitessFlags != nil {
+		in, out := &in.ComponentVitessFlags, &out.ComponentVitessFlags
+		*out = new(ComponentVitessFlags)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(NetworkingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceDefaults != nil {
+		in, out := &in.ResourceDefaults, &out.ResourceDefaults
+		*out = new(ResourceDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverBuffer != nil {
+		in, out := &in.FailoverBuffer, &out.FailoverBuffer
+		*out = new(FailoverBufferSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellSpec.
+func (in *VitessCellSpec) DeepCopy() *VitessCellSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellStatus) DeepCopyInto(out *VitessCellStatus) {
+	*out = *in
+	if in.DryRunChanges != nil {
+		in, out := &in.DryRunChanges, &out.DryRunChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Lockserver.DeepCopyInto(&out.Lockserver)
+	out.Gateway = in.Gateway
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make(map[string]VitessCellKeyspaceStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellStatus.
+func (in *VitessCellStatus) DeepCopy() *VitessCellStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellTemplate) DeepCopyInto(out *VitessCellTemplate) {
+	*out = *in
+	if in.ZoneDiscovery != nil {
+		in, out := &in.ZoneDiscovery, &out.ZoneDiscovery
+		*out = new(VitessCellZoneDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Lockserver.DeepCopyInto(&out.Lockserver)
+	in.Gateway.DeepCopyInto(&out.Gateway)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellTemplate.
+func (in *VitessCellTemplate) DeepCopy() *VitessCellTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellZoneDiscovery) DeepCopyInto(out *VitessCellZoneDiscovery) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellZoneDiscovery.
+func (in *VitessCellZoneDiscovery) DeepCopy() *VitessCellZoneDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellZoneDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCluster) DeepCopyInto(out *VitessCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCluster.
+func (in *VitessCluster) DeepCopy() *VitessCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterCellStatus) DeepCopyInto(out *VitessClusterCellStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterCellStatus.
+func (in *VitessClusterCellStatus) DeepCopy() *VitessClusterCellStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterCellStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterCondition) DeepCopyInto(out *VitessClusterCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterCondition.
+func (in *VitessClusterCondition) DeepCopy() *VitessClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterFederation) DeepCopyInto(out *VitessClusterFederation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterFederation.
+func (in *VitessClusterFederation) DeepCopy() *VitessClusterFederation {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterFederation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessClusterFederation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterFederationList) DeepCopyInto(out *VitessClusterFederationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessClusterFederation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterFederationList.
+func (in *VitessClusterFederationList) DeepCopy() *VitessClusterFederationList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterFederationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessClusterFederationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterFederationMember) DeepCopyInto(out *VitessClusterFederationMember) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterFederationMember.
+func (in *VitessClusterFederationMember) DeepCopy() *VitessClusterFederationMember {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterFederationMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterFederationSpec) DeepCopyInto(out *VitessClusterFederationSpec) {
+	*out = *in
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]VitessClusterFederationMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Cluster.DeepCopyInto(&out.Cluster)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterFederationSpec.
+func (in *VitessClusterFederationSpec) DeepCopy() *VitessClusterFederationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterFederationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterFederationStatus) DeepCopyInto(out *VitessClusterFederationStatus) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterFederationStatus.
+func (in *VitessClusterFederationStatus) DeepCopy() *VitessClusterFederationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterFederationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterKeyspaceStatus) DeepCopyInto(out *VitessClusterKeyspaceStatus) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterKeyspaceStatus.
+func (in *VitessClusterKeyspaceStatus) DeepCopy() *VitessClusterKeyspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterKeyspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterList) DeepCopyInto(out *VitessClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterList.
+func (in *VitessClusterList) DeepCopy() *VitessClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterPendingChange) DeepCopyInto(out *VitessClusterPendingChange) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterPendingChange.
+func (in *VitessClusterPendingChange) DeepCopy() *VitessClusterPendingChange {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterPendingChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterResourceRecommendations) DeepCopyInto(out *VitessClusterResourceRecommendations) {
+	*out = *in
+	if in.ObservedSince != nil {
+		in, out := &in.ObservedSince, &out.ObservedSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSampleTime != nil {
+		in, out := &in.LastSampleTime, &out.LastSampleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]VitessTabletPoolRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterResourceRecommendations.
+func (in *VitessClusterResourceRecommendations) DeepCopy() *VitessClusterResourceRecommendations {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterResourceRecommendations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterRolloutCoordinatorStatus) DeepCopyInto(out *VitessClusterRolloutCoordinatorStatus) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnhealthyTablets != nil {
+		in, out := &in.UnhealthyTablets, &out.UnhealthyTablets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterRolloutCoordinatorStatus.
+func (in *VitessClusterRolloutCoordinatorStatus) DeepCopy() *VitessClusterRolloutCoordinatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterRolloutCoordinatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterRolloutStatus) DeepCopyInto(out *VitessClusterRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterRolloutStatus.
+func (in *VitessClusterRolloutStatus) DeepCopy() *VitessClusterRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterSpec) DeepCopyInto(out *VitessClusterSpec) {
+	*out = *in
+	in.Images.DeepCopyInto(&out.Images)
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(ClusterBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	if in.VitessDashboard != nil {
+		in, out := &in.VitessDashboard, &out.VitessDashboard
+		*out = new(VitessDashboardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VtAdmin != nil {
+		in, out := &in.VtAdmin, &out.VtAdmin
+		*out = new(VtAdminSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]VitessCellTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalCells != nil {
+		in, out := &in.ExternalCells, &out.ExternalCells
+		*out = make([]VitessExternalCell, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make([]VitessKeyspaceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ComponentVitessFlags != nil {
+		in, out := &in.ComponentVitessFlags, &out.ComponentVitessFlags
+		*out = new(ComponentVitessFlags)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VitessClusterUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonLabelPolicy != nil {
+		in, out := &in.CommonLabelPolicy, &out.CommonLabelPolicy
+		*out = new(CommonLabelPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(VitessClusterUpgradeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(VitessClusterStandbySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]VitessMySQLUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InternalCredentials != nil {
+		in, out := &in.InternalCredentials, &out.InternalCredentials
+		*out = new(VitessInternalCredentialsSpec)
+		**out = **in
+	}
+	if in.GatewayService != nil {
+		in, out := &in.GatewayService, &out.GatewayService
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TabletService != nil {
+		in, out := &in.TabletService, &out.TabletService
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Observability != nil {
+		in, out := &in.Observability, &out.Observability
+		*out = new(ObservabilitySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(NetworkingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Evacuation != nil {
+		in, out := &in.Evacuation, &out.Evacuation
+		*out = new(EvacuationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceDefaults != nil {
+		in, out := &in.ResourceDefaults, &out.ResourceDefaults
+		*out = new(ResourceDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverBuffer != nil {
+		in, out := &in.FailoverBuffer, &out.FailoverBuffer
+		*out = new(FailoverBufferSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = new(ResourceRecommendationsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterSpec.
+func (in *VitessClusterSpec) DeepCopy() *VitessClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterStandbySpec) DeepCopyInto(out *VitessClusterStandbySpec) {
+	*out = *in
+	if in.RestoreInterval != nil {
+		in, out := &in.RestoreInterval, &out.RestoreInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterStandbySpec.
+func (in *VitessClusterStandbySpec) DeepCopy() *VitessClusterStandbySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterStandbySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterStatus) DeepCopyInto(out *VitessClusterStatus) {
+	*out = *in
+	if in.DryRunChanges != nil {
+		in, out := &in.DryRunChanges, &out.DryRunChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	out.VitessDashboard = in.VitessDashboard
+	out.Vtadmin = in.Vtadmin
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make(map[string]VitessClusterCellStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make(map[string]VitessClusterKeyspaceStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.OrphanedCells != nil {
+		in, out := &in.OrphanedCells, &out.OrphanedCells
+		*out = make(map[string]OrphanStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OrphanedKeyspaces != nil {
+		in, out := &in.OrphanedKeyspaces, &out.OrphanedKeyspaces
+		*out = make(map[string]OrphanStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Summary.DeepCopyInto(&out.Summary)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = make(map[string]VitessClusterRolloutStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RolloutCoordinator != nil {
+		in, out := &in.RolloutCoordinator, &out.RolloutCoordinator
+		*out = new(VitessClusterRolloutCoordinatorStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]VitessClusterPendingChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResolvedImages != nil {
+		in, out := &in.ResolvedImages, &out.ResolvedImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PinnedImages != nil {
+		in, out := &in.PinnedImages, &out.PinnedImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VersionSkew != nil {
+		in, out := &in.VersionSkew, &out.VersionSkew
+		*out = make([]VitessVersionSkew, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(VitessClusterUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiscoveredZones != nil {
+		in, out := &in.DiscoveredZones, &out.DiscoveredZones
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = new(VitessClusterResourceRecommendations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VitessClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterStatus.
+func (in *VitessClusterStatus) DeepCopy() *VitessClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterSummary) DeepCopyInto(out *VitessClusterSummary) {
+	*out = *in
+	if in.OldestLatestBackupTime != nil {
+		in, out := &in.OldestLatestBackupTime, &out.OldestLatestBackupTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterSummary.
+func (in *VitessClusterSummary) DeepCopy() *VitessClusterSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterUpdateStrategy) DeepCopyInto(out *VitessClusterUpdateStrategy) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(VitessClusterUpdateStrategyType)
+		**out = **in
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalVitessClusterUpdateStrategyOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxConcurrentShardRollouts != nil {
+		in, out := &in.MaxConcurrentShardRollouts, &out.MaxConcurrentShardRollouts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Coordinator != nil {
+		in, out := &in.Coordinator, &out.Coordinator
+		*out = new(RolloutCoordinatorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MysqlUpgrade != nil {
+		in, out := &in.MysqlUpgrade, &out.MysqlUpgrade
+		*out = new(MysqlUpgradeStrategy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterUpdateStrategy.
+func (in *VitessClusterUpdateStrategy) DeepCopy() *VitessClusterUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterUpgradeSpec) DeepCopyInto(out *VitessClusterUpgradeSpec) {
+	*out = *in
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = make([]VitessUpgradeStage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterUpgradeSpec.
+func (in *VitessClusterUpgradeSpec) DeepCopy() *VitessClusterUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterUpgradeStatus) DeepCopyInto(out *VitessClusterUpgradeStatus) {
+	*out = *in
+	in.Images.DeepCopyInto(&out.Images)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterUpgradeStatus.
+func (in *VitessClusterUpgradeStatus) DeepCopy() *VitessClusterUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessContainerAutoscalingStatus) DeepCopyInto(out *VitessContainerAutoscalingStatus) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LowerBound != nil {
+		in, out := &in.LowerBound, &out.LowerBound
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.UpperBound != nil {
+		in, out := &in.UpperBound, &out.UpperBound
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.AppliedRequests != nil {
+		in, out := &in.AppliedRequests, &out.AppliedRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessContainerAutoscalingStatus.
+func (in *VitessContainerAutoscalingStatus) DeepCopy() *VitessContainerAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessContainerAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessContainerRecommendation) DeepCopyInto(out *VitessContainerRecommendation) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.PeakUsage != nil {
+		in, out := &in.PeakUsage, &out.PeakUsage
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.RecommendedRequests != nil {
+		in, out := &in.RecommendedRequests, &out.RecommendedRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessContainerRecommendation.
+func (in *VitessContainerRecommendation) DeepCopy() *VitessContainerRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessContainerRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardSpec) DeepCopyInto(out *VitessDashboardSpec) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(VtctldGRPCSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardSpec.
+func (in *VitessDashboardSpec) DeepCopy() *VitessDashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardStatus) DeepCopyInto(out *VitessDashboardStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardStatus.
+func (in *VitessDashboardStatus) DeepCopy() *VitessDashboardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessExternalCell) DeepCopyInto(out *VitessExternalCell) {
+	*out = *in
+	if in.Lockserver != nil {
+		in, out := &in.Lockserver, &out.Lockserver
+		*out = new(VitessLockserverParams)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessExternalCell.
+func (in *VitessExternalCell) DeepCopy() *VitessExternalCell {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessExternalCell)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayAuthentication) DeepCopyInto(out *VitessGatewayAuthentication) {
+	*out = *in
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(VitessGatewayStaticAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayAuthentication.
+func (in *VitessGatewayAuthentication) DeepCopy() *VitessGatewayAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewaySecureTransport) DeepCopyInto(out *VitessGatewaySecureTransport) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(VitessGatewayTLSSecureTransport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewaySecureTransport.
+func (in *VitessGatewaySecureTransport) DeepCopy() *VitessGatewaySecureTransport {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewaySecureTransport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayStaticAuthentication) DeepCopyInto(out *VitessGatewayStaticAuthentication) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayStaticAuthentication.
+func (in *VitessGatewayStaticAuthentication) DeepCopy() *VitessGatewayStaticAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayStaticAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayTLSSecureTransport) DeepCopyInto(out *VitessGatewayTLSSecureTransport) {
+	*out = *in
+	if in.ClientCACertSecret != nil {
+		in, out := &in.ClientCACertSecret, &out.ClientCACertSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.CertSecret != nil {
+		in, out := &in.CertSecret, &out.CertSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.KeySecret != nil {
+		in, out := &in.KeySecret, &out.KeySecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayTLSSecureTransport.
+func (in *VitessGatewayTLSSecureTransport) DeepCopy() *VitessGatewayTLSSecureTransport {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayTLSSecureTransport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessImagePullPolicies) DeepCopyInto(out *VitessImagePullPolicies) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImagePullPolicies.
+func (in *VitessImagePullPolicies) DeepCopy() *VitessImagePullPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessImagePullPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessImages) DeepCopyInto(out *VitessImages) {
+	*out = *in
+	if in.Mysqld != nil {
+		in, out := &in.Mysqld, &out.Mysqld
+		*out = new(MysqldImage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImages.
+func (in *VitessImages) DeepCopy() *VitessImages {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessInternalCredentialsSpec) DeepCopyInto(out *VitessInternalCredentialsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessInternalCredentialsSpec.
+func (in *VitessInternalCredentialsSpec) DeepCopy() *VitessInternalCredentialsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessInternalCredentialsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyRange) DeepCopyInto(out *VitessKeyRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyRange.
+func (in *VitessKeyRange) DeepCopy() *VitessKeyRange {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspace) DeepCopyInto(out *VitessKeyspace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspace.
+func (in *VitessKeyspace) DeepCopy() *VitessKeyspace {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessKeyspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceCondition) DeepCopyInto(out *VitessKeyspaceCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCondition.
+func (in *VitessKeyspaceCondition) DeepCopy() *VitessKeyspaceCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceCustomPartitioning) DeepCopyInto(out *VitessKeyspaceCustomPartitioning) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]VitessKeyspaceKeyRangeShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ShardTemplate != nil {
+		in, out := &in.ShardTemplate, &out.ShardTemplate
+		*out = new(VitessShardTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCustomPartitioning.
+func (in *VitessKeyspaceCustomPartitioning) DeepCopy() *VitessKeyspaceCustomPartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceCustomPartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceEqualPartitioning) DeepCopyInto(out *VitessKeyspaceEqualPartitioning) {
+	*out = *in
+	in.ShardTemplate.DeepCopyInto(&out.ShardTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceEqualPartitioning.
+func (in *VitessKeyspaceEqualPartitioning) DeepCopy() *VitessKeyspaceEqualPartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceEqualPartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceImages) DeepCopyInto(out *VitessKeyspaceImages) {
+	*out = *in
+	if in.Mysqld != nil {
+		in, out := &in.Mysqld, &out.Mysqld
+		*out = new(MysqldImage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceImages.
+func (in *VitessKeyspaceImages) DeepCopy() *VitessKeyspaceImages {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceKeyRangeShard) DeepCopyInto(out *VitessKeyspaceKeyRangeShard) {
+	*out = *in
+	out.KeyRange = in.KeyRange
+	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceKeyRangeShard.
+func (in *VitessKeyspaceKeyRangeShard) DeepCopy() *VitessKeyspaceKeyRangeShard {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceKeyRangeShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceList) DeepCopyInto(out *VitessKeyspaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessKeyspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceList.
+func (in *VitessKeyspaceList) DeepCopy() *VitessKeyspaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessKeyspaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceMaterializeSpec) DeepCopyInto(out *VitessKeyspaceMaterializeSpec) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]VitessMaterializedTable, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceMaterializeSpec.
+func (in *VitessKeyspaceMaterializeSpec) DeepCopy() *VitessKeyspaceMaterializeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceMaterializeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspacePartitioning) DeepCopyInto(out *VitessKeyspacePartitioning) {
+	*out = *in
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = new(VitessKeyspaceEqualPartitioning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(VitessKeyspaceCustomPartitioning)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioning.
+func (in *VitessKeyspacePartitioning) DeepCopy() *VitessKeyspacePartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspacePartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspacePartitioningStatus) DeepCopyInto(out *VitessKeyspacePartitioningStatus) {
+	*out = *in
+	if in.ShardNames != nil {
+		in, out := &in.ShardNames, &out.ShardNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioningStatus.
+func (in *VitessKeyspacePartitioningStatus) DeepCopy() *VitessKeyspacePartitioningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspacePartitioningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceQueryServiceControl) DeepCopyInto(out *VitessKeyspaceQueryServiceControl) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceQueryServiceControl.
+func (in *VitessKeyspaceQueryServiceControl) DeepCopy() *VitessKeyspaceQueryServiceControl {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceQueryServiceControl)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceQueryServing) DeepCopyInto(out *VitessKeyspaceQueryServing) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]VitessKeyspaceQueryServiceControl, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceQueryServing.
+func (in *VitessKeyspaceQueryServing) DeepCopy() *VitessKeyspaceQueryServing {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceQueryServing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceReshardSpec) DeepCopyInto(out *VitessKeyspaceReshardSpec) {
+	*out = *in
+	if in.SourceShards != nil {
+		in, out := &in.SourceShards, &out.SourceShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetShards != nil {
+		in, out := &in.TargetShards, &out.TargetShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = new(VitessReshardGuardrails)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceReshardSpec.
+func (in *VitessKeyspaceReshardSpec) DeepCopy() *VitessKeyspaceReshardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceReshardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceShardStatus) DeepCopyInto(out *VitessKeyspaceShardStatus) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LatestBackupTime != nil {
+		in, out := &in.LatestBackupTime, &out.LatestBackupTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceShardStatus.
+func (in *VitessKeyspaceShardStatus) DeepCopy() *VitessKeyspaceShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceSpec) DeepCopyInto(out *VitessKeyspaceSpec) {
+	*out = *in
+	in.VitessKeyspaceTemplate.DeepCopyInto(&out.VitessKeyspaceTemplate)
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	in.Images.DeepCopyInto(&out.Images)
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ZoneMap != nil {
+		in, out := &in.ZoneMap, &out.ZoneMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BackupLocations != nil {
+		in, out := &in.BackupLocations, &out.BackupLocations
+		*out = make([]VitessBackupLocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackupCompression != nil {
+		in, out := &in.BackupCompression, &out.BackupCompression
+		*out = new(BackupCompressionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupXtrabackup != nil {
+		in, out := &in.BackupXtrabackup, &out.BackupXtrabackup
+		*out = new(XtrabackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupStaleThreshold != nil {
+		in, out := &in.BackupStaleThreshold, &out.BackupStaleThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.BackupDisruptionPolicy != nil {
+		in, out := &in.BackupDisruptionPolicy, &out.BackupDisruptionPolicy
+		*out = new(VitessBackupDisruptionPolicy)
+		**out = **in
+	}
+	if in.MaxConcurrentRestores != nil {
+		in, out := &in.MaxConcurrentRestores, &out.MaxConcurrentRestores
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Vtbackup != nil {
+		in, out := &in.Vtbackup, &out.Vtbackup
+		*out = new(VtbackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ComponentVitessFlags != nil {
+		in, out := &in.ComponentVitessFlags, &out.ComponentVitessFlags
+		*out = new(ComponentVitessFlags)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(NetworkingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceDefaults != nil {
+		in, out := &in.ResourceDefaults, &out.ResourceDefaults
+		*out = new(ResourceDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverBuffer != nil {
+		in, out := &in.FailoverBuffer, &out.FailoverBuffer
+		*out = new(FailoverBufferSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Evacuation != nil {
+		in, out := &in.Evacuation, &out.Evacuation
+		*out = new(EvacuationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VitessClusterUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(VitessClusterStandbySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]VitessMySQLUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InternalCredentials != nil {
+		in, out := &in.InternalCredentials, &out.InternalCredentials
+		*out = new(VitessInternalCredentialsSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceSpec.
+func (in *VitessKeyspaceSpec) DeepCopy() *VitessKeyspaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceStatus) DeepCopyInto(out *VitessKeyspaceStatus) {
+	*out = *in
+	if in.DryRunChanges != nil {
+		in, out := &in.DryRunChanges, &out.DryRunChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make(map[string]VitessKeyspaceShardStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Partitionings != nil {
+		in, out := &in.Partitionings, &out.Partitionings
+		*out = make([]VitessKeyspacePartitioningStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OrphanedShards != nil {
+		in, out := &in.OrphanedShards, &out.OrphanedShards
+		*out = make(map[string]OrphanStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resharding != nil {
+		in, out := &in.Resharding, &out.Resharding
+		*out = new(ReshardingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Materialize != nil {
+		in, out := &in.Materialize, &out.Materialize
+		*out = new(MaterializeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cutover != nil {
+		in, out := &in.Cutover, &out.Cutover
+		*out = new(ReshardCutoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LookupVindexes != nil {
+		in, out := &in.LookupVindexes, &out.LookupVindexes
+		*out = make([]LookupVindexStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VitessKeyspaceCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceStatus.
+func (in *VitessKeyspaceStatus) DeepCopy() *VitessKeyspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceTemplate) DeepCopyInto(out *VitessKeyspaceTemplate) {
+	*out = *in
+	if in.QueryServing != nil {
+		in, out := &in.QueryServing, &out.QueryServing
+		*out = new(VitessKeyspaceQueryServing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reshard != nil {
+		in, out := &in.Reshard, &out.Reshard
+		*out = new(VitessKeyspaceReshardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Materialize != nil {
+		in, out := &in.Materialize, &out.Materialize
+		*out = new(VitessKeyspaceMaterializeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LookupVindexes != nil {
+		in, out := &in.LookupVindexes, &out.LookupVindexes
+		*out = make([]VitessLookupVindexSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VitessOrchestrator != nil {
+		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
+		*out = new(VitessOrchestratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Partitionings != nil {
+		in, out := &in.Partitionings, &out.Partitionings
+		*out = make([]VitessKeyspacePartitioning, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = new(VitessKeyspaceImages)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceTemplate.
+func (in *VitessKeyspaceTemplate) DeepCopy() *VitessKeyspaceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessLockserverParams) DeepCopyInto(out *VitessLockserverParams) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(VitessLockserverTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthSecret != nil {
+		in, out := &in.AuthSecret, &out.AuthSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessLockserverParams.
+func (in *VitessLockserverParams) DeepCopy() *VitessLockserverParams {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessLockserverParams)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessLockserverTLSSpec) DeepCopyInto(out *VitessLockserverTLSSpec) {
+	*out = *in
+	out.ClientCertSecret = in.ClientCertSecret
+	out.ClientKeySecret = in.ClientKeySecret
+	if in.CACertSecret != nil {
+		in, out := &in.CACertSecret, &out.CACertSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessLockserverTLSSpec.
+func (in *VitessLockserverTLSSpec) DeepCopy() *VitessLockserverTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessLockserverTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessLookupVindexSpec) DeepCopyInto(out *VitessLookupVindexSpec) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LookupColumns != nil {
+		in, out := &in.LookupColumns, &out.LookupColumns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterCellStatus.
-func (in *VitessClusterCellStatus) DeepCopy() *VitessClusterCellStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessLookupVindexSpec.
+func (in *VitessLookupVindexSpec) DeepCopy() *VitessLookupVindexSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterCellStatus)
+	out := new(VitessLookupVindexSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterKeyspaceStatus) DeepCopyInto(out *VitessClusterKeyspaceStatus) {
+func (in *VitessMaterialize) DeepCopyInto(out *VitessMaterialize) {
 	*out = *in
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterKeyspaceStatus.
-func (in *VitessClusterKeyspaceStatus) DeepCopy() *VitessClusterKeyspaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMaterialize.
+func (in *VitessMaterialize) DeepCopy() *VitessMaterialize {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterKeyspaceStatus)
+	out := new(VitessMaterialize)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessMaterialize) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterList) DeepCopyInto(out *VitessClusterList) {
+func (in *VitessMaterializeList) DeepCopyInto(out *VitessMaterializeList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VitessCluster, len(*in))
+		*out = make([]VitessMaterialize, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterList.
-func (in *VitessClusterList) DeepCopy() *VitessClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMaterializeList.
+func (in *VitessMaterializeList) DeepCopy() *VitessMaterializeList {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterList)
+	out := new(VitessMaterializeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessClusterList) DeepCopyObject() runtime.Object {
+func (in *VitessMaterializeList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1237,168 +3863,102 @@ func (in *VitessClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterSpec) DeepCopyInto(out *VitessClusterSpec) {
+func (in *VitessMaterializeSpec) DeepCopyInto(out *VitessMaterializeSpec) {
 	*out = *in
-	in.Images.DeepCopyInto(&out.Images)
-	out.ImagePullPolicies = in.ImagePullPolicies
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.Backup != nil {
-		in, out := &in.Backup, &out.Backup
-		*out = new(ClusterBackupSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
-	if in.VitessDashboard != nil {
-		in, out := &in.VitessDashboard, &out.VitessDashboard
-		*out = new(VitessDashboardSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.VtAdmin != nil {
-		in, out := &in.VtAdmin, &out.VtAdmin
-		*out = new(VtAdminSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make([]VitessCellTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Keyspaces != nil {
-		in, out := &in.Keyspaces, &out.Keyspaces
-		*out = make([]VitessKeyspaceTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVitessFlags != nil {
-		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.TopologyReconciliation != nil {
-		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
-		*out = new(TopoReconcileConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.UpdateStrategy != nil {
-		in, out := &in.UpdateStrategy, &out.UpdateStrategy
-		*out = new(VitessClusterUpdateStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.GatewayService != nil {
-		in, out := &in.GatewayService, &out.GatewayService
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TabletService != nil {
-		in, out := &in.TabletService, &out.TabletService
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
-	}
+	in.VitessKeyspaceMaterializeSpec.DeepCopyInto(&out.VitessKeyspaceMaterializeSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterSpec.
-func (in *VitessClusterSpec) DeepCopy() *VitessClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMaterializeSpec.
+func (in *VitessMaterializeSpec) DeepCopy() *VitessMaterializeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterSpec)
+	out := new(VitessMaterializeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterStatus) DeepCopyInto(out *VitessClusterStatus) {
+func (in *VitessMaterializeStatus) DeepCopyInto(out *VitessMaterializeStatus) {
 	*out = *in
-	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
-	out.VitessDashboard = in.VitessDashboard
-	out.Vtadmin = in.Vtadmin
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make(map[string]VitessClusterCellStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Keyspaces != nil {
-		in, out := &in.Keyspaces, &out.Keyspaces
-		*out = make(map[string]VitessClusterKeyspaceStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.OrphanedCells != nil {
-		in, out := &in.OrphanedCells, &out.OrphanedCells
-		*out = make(map[string]OrphanStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.OrphanedKeyspaces != nil {
-		in, out := &in.OrphanedKeyspaces, &out.OrphanedKeyspaces
-		*out = make(map[string]OrphanStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]VReplicationStreamStatus, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterStatus.
-func (in *VitessClusterStatus) DeepCopy() *VitessClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMaterializeStatus.
+func (in *VitessMaterializeStatus) DeepCopy() *VitessMaterializeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterStatus)
+	out := new(VitessMaterializeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterUpdateStrategy) DeepCopyInto(out *VitessClusterUpdateStrategy) {
+func (in *VitessMaterializedTable) DeepCopyInto(out *VitessMaterializedTable) {
 	*out = *in
-	if in.Type != nil {
-		in, out := &in.Type, &out.Type
-		*out = new(VitessClusterUpdateStrategyType)
-		**out = **in
-	}
-	if in.External != nil {
-		in, out := &in.External, &out.External
-		*out = new(ExternalVitessClusterUpdateStrategyOptions)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterUpdateStrategy.
-func (in *VitessClusterUpdateStrategy) DeepCopy() *VitessClusterUpdateStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMaterializedTable.
+func (in *VitessMaterializedTable) DeepCopy() *VitessMaterializedTable {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterUpdateStrategy)
+	out := new(VitessMaterializedTable)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessDashboardSpec) DeepCopyInto(out *VitessDashboardSpec) {
+func (in *VitessMySQLGrant) DeepCopyInto(out *VitessMySQLGrant) {
 	*out = *in
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMySQLGrant.
+func (in *VitessMySQLGrant) DeepCopy() *VitessMySQLGrant {
+	if in == nil {
+		return nil
 	}
+	out := new(VitessMySQLGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMySQLUser) DeepCopyInto(out *VitessMySQLUser) {
+	*out = *in
+	out.PasswordSecret = in.PasswordSecret
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]VitessMySQLGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMySQLUser.
+func (in *VitessMySQLUser) DeepCopy() *VitessMySQLUser {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMySQLUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessOrchestratorSpec) DeepCopyInto(out *VitessOrchestratorSpec) {
+	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.ExtraFlags != nil {
 		in, out := &in.ExtraFlags, &out.ExtraFlags
@@ -1475,173 +4035,163 @@ func (in *VitessDashboardSpec) DeepCopyInto(out *VitessDashboardSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardSpec.
-func (in *VitessDashboardSpec) DeepCopy() *VitessDashboardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorSpec.
+func (in *VitessOrchestratorSpec) DeepCopy() *VitessOrchestratorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessDashboardSpec)
+	out := new(VitessOrchestratorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessDashboardStatus) DeepCopyInto(out *VitessDashboardStatus) {
+func (in *VitessOrchestratorStatus) DeepCopyInto(out *VitessOrchestratorStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardStatus.
-func (in *VitessDashboardStatus) DeepCopy() *VitessDashboardStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorStatus.
+func (in *VitessOrchestratorStatus) DeepCopy() *VitessOrchestratorStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessDashboardStatus)
+	out := new(VitessOrchestratorStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewayAuthentication) DeepCopyInto(out *VitessGatewayAuthentication) {
+func (in *VitessReplicationErrantGTIDSpec) DeepCopyInto(out *VitessReplicationErrantGTIDSpec) {
 	*out = *in
-	if in.Static != nil {
-		in, out := &in.Static, &out.Static
-		*out = new(VitessGatewayStaticAuthentication)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayAuthentication.
-func (in *VitessGatewayAuthentication) DeepCopy() *VitessGatewayAuthentication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReplicationErrantGTIDSpec.
+func (in *VitessReplicationErrantGTIDSpec) DeepCopy() *VitessReplicationErrantGTIDSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessGatewayAuthentication)
+	out := new(VitessReplicationErrantGTIDSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewaySecureTransport) DeepCopyInto(out *VitessGatewaySecureTransport) {
+func (in *VitessReplicationRebuildSpec) DeepCopyInto(out *VitessReplicationRebuildSpec) {
 	*out = *in
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(VitessGatewayTLSSecureTransport)
-		(*in).DeepCopyInto(*out)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewaySecureTransport.
-func (in *VitessGatewaySecureTransport) DeepCopy() *VitessGatewaySecureTransport {
-	if in == nil {
-		return nil
+	if in.BrokenFor != nil {
+		in, out := &in.BrokenFor, &out.BrokenFor
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	out := new(VitessGatewaySecureTransport)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewayStaticAuthentication) DeepCopyInto(out *VitessGatewayStaticAuthentication) {
-	*out = *in
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
-		*out = new(SecretSource)
+	if in.MaxConcurrent != nil {
+		in, out := &in.MaxConcurrent, &out.MaxConcurrent
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayStaticAuthentication.
-func (in *VitessGatewayStaticAuthentication) DeepCopy() *VitessGatewayStaticAuthentication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReplicationRebuildSpec.
+func (in *VitessReplicationRebuildSpec) DeepCopy() *VitessReplicationRebuildSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessGatewayStaticAuthentication)
+	out := new(VitessReplicationRebuildSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewayTLSSecureTransport) DeepCopyInto(out *VitessGatewayTLSSecureTransport) {
+func (in *VitessReplicationSpec) DeepCopyInto(out *VitessReplicationSpec) {
 	*out = *in
-	if in.ClientCACertSecret != nil {
-		in, out := &in.ClientCACertSecret, &out.ClientCACertSecret
-		*out = new(SecretSource)
+	if in.InitializeMaster != nil {
+		in, out := &in.InitializeMaster, &out.InitializeMaster
+		*out = new(bool)
 		**out = **in
 	}
-	if in.CertSecret != nil {
-		in, out := &in.CertSecret, &out.CertSecret
-		*out = new(SecretSource)
+	if in.InitializeBackup != nil {
+		in, out := &in.InitializeBackup, &out.InitializeBackup
+		*out = new(bool)
 		**out = **in
 	}
-	if in.KeySecret != nil {
-		in, out := &in.KeySecret, &out.KeySecret
-		*out = new(SecretSource)
+	if in.RecoverRestartedMaster != nil {
+		in, out := &in.RecoverRestartedMaster, &out.RecoverRestartedMaster
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ErrantGTIDs != nil {
+		in, out := &in.ErrantGTIDs, &out.ErrantGTIDs
+		*out = new(VitessReplicationErrantGTIDSpec)
 		**out = **in
 	}
+	if in.RebuildBrokenReplicas != nil {
+		in, out := &in.RebuildBrokenReplicas, &out.RebuildBrokenReplicas
+		*out = new(VitessReplicationRebuildSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayTLSSecureTransport.
-func (in *VitessGatewayTLSSecureTransport) DeepCopy() *VitessGatewayTLSSecureTransport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReplicationSpec.
+func (in *VitessReplicationSpec) DeepCopy() *VitessReplicationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessGatewayTLSSecureTransport)
+	out := new(VitessReplicationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessImagePullPolicies) DeepCopyInto(out *VitessImagePullPolicies) {
+func (in *VitessReshardGuardrails) DeepCopyInto(out *VitessReshardGuardrails) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImagePullPolicies.
-func (in *VitessImagePullPolicies) DeepCopy() *VitessImagePullPolicies {
-	if in == nil {
-		return nil
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	out := new(VitessImagePullPolicies)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessImages) DeepCopyInto(out *VitessImages) {
-	*out = *in
-	if in.Mysqld != nil {
-		in, out := &in.Mysqld, &out.Mysqld
-		*out = new(MysqldImage)
+	if in.MaxCopyPhase != nil {
+		in, out := &in.MaxCopyPhase, &out.MaxCopyPhase
+		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(VitessReshardRollbackSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImages.
-func (in *VitessImages) DeepCopy() *VitessImages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReshardGuardrails.
+func (in *VitessReshardGuardrails) DeepCopy() *VitessReshardGuardrails {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessImages)
+	out := new(VitessReshardGuardrails)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyRange) DeepCopyInto(out *VitessKeyRange) {
+func (in *VitessReshardRollbackSpec) DeepCopyInto(out *VitessReshardRollbackSpec) {
 	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyRange.
-func (in *VitessKeyRange) DeepCopy() *VitessKeyRange {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReshardRollbackSpec.
+func (in *VitessReshardRollbackSpec) DeepCopy() *VitessReshardRollbackSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyRange)
+	out := new(VitessReshardRollbackSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspace) DeepCopyInto(out *VitessKeyspace) {
+func (in *VitessShard) DeepCopyInto(out *VitessShard) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1649,18 +4199,18 @@ func (in *VitessKeyspace) DeepCopyInto(out *VitessKeyspace) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspace.
-func (in *VitessKeyspace) DeepCopy() *VitessKeyspace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShard.
+func (in *VitessShard) DeepCopy() *VitessShard {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspace)
+	out := new(VitessShard)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessKeyspace) DeepCopyObject() runtime.Object {
+func (in *VitessShard) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1668,354 +4218,560 @@ func (in *VitessKeyspace) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceCondition) DeepCopyInto(out *VitessKeyspaceCondition) {
+func (in *VitessShardBackupStatus) DeepCopyInto(out *VitessShardBackupStatus) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.FinishedTime != nil {
+		in, out := &in.FinishedTime, &out.FinishedTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCondition.
-func (in *VitessKeyspaceCondition) DeepCopy() *VitessKeyspaceCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardBackupStatus.
+func (in *VitessShardBackupStatus) DeepCopy() *VitessShardBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceCondition)
+	out := new(VitessShardBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceCustomPartitioning) DeepCopyInto(out *VitessKeyspaceCustomPartitioning) {
+func (in *VitessShardCondition) DeepCopyInto(out *VitessShardCondition) {
 	*out = *in
-	if in.Shards != nil {
-		in, out := &in.Shards, &out.Shards
-		*out = make([]VitessKeyspaceKeyRangeShard, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCustomPartitioning.
-func (in *VitessKeyspaceCustomPartitioning) DeepCopy() *VitessKeyspaceCustomPartitioning {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardCondition.
+func (in *VitessShardCondition) DeepCopy() *VitessShardCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceCustomPartitioning)
+	out := new(VitessShardCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceEqualPartitioning) DeepCopyInto(out *VitessKeyspaceEqualPartitioning) {
+func (in *VitessShardInternalCredentialsStatus) DeepCopyInto(out *VitessShardInternalCredentialsStatus) {
 	*out = *in
-	in.ShardTemplate.DeepCopyInto(&out.ShardTemplate)
+	if in.Accounts != nil {
+		in, out := &in.Accounts, &out.Accounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OldPasswordsRetainedSince != nil {
+		in, out := &in.OldPasswordsRetainedSince, &out.OldPasswordsRetainedSince
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceEqualPartitioning.
-func (in *VitessKeyspaceEqualPartitioning) DeepCopy() *VitessKeyspaceEqualPartitioning {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardInternalCredentialsStatus.
+func (in *VitessShardInternalCredentialsStatus) DeepCopy() *VitessShardInternalCredentialsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceEqualPartitioning)
+	out := new(VitessShardInternalCredentialsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceImages) DeepCopyInto(out *VitessKeyspaceImages) {
+func (in *VitessShardList) DeepCopyInto(out *VitessShardList) {
 	*out = *in
-	if in.Mysqld != nil {
-		in, out := &in.Mysqld, &out.Mysqld
-		*out = new(MysqldImage)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceImages.
-func (in *VitessKeyspaceImages) DeepCopy() *VitessKeyspaceImages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardList.
+func (in *VitessShardList) DeepCopy() *VitessShardList {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceImages)
+	out := new(VitessShardList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessShardList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceKeyRangeShard) DeepCopyInto(out *VitessKeyspaceKeyRangeShard) {
+func (in *VitessShardMysqlUpgradeStatus) DeepCopyInto(out *VitessShardMysqlUpgradeStatus) {
 	*out = *in
-	out.KeyRange = in.KeyRange
-	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
+	out.FromImage = in.FromImage
+	out.ToImage = in.ToImage
+	if in.UpgradedTablets != nil {
+		in, out := &in.UpgradedTablets, &out.UpgradedTablets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceKeyRangeShard.
-func (in *VitessKeyspaceKeyRangeShard) DeepCopy() *VitessKeyspaceKeyRangeShard {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardMysqlUpgradeStatus.
+func (in *VitessShardMysqlUpgradeStatus) DeepCopy() *VitessShardMysqlUpgradeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceKeyRangeShard)
+	out := new(VitessShardMysqlUpgradeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceList) DeepCopyInto(out *VitessKeyspaceList) {
+func (in *VitessShardReparentStatus) DeepCopyInto(out *VitessShardReparentStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VitessKeyspace, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceList.
-func (in *VitessKeyspaceList) DeepCopy() *VitessKeyspaceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardReparentStatus.
+func (in *VitessShardReparentStatus) DeepCopy() *VitessShardReparentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceList)
+	out := new(VitessShardReparentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessKeyspaceList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspacePartitioning) DeepCopyInto(out *VitessKeyspacePartitioning) {
+func (in *VitessShardSnapshot) DeepCopyInto(out *VitessShardSnapshot) {
 	*out = *in
-	if in.Equal != nil {
-		in, out := &in.Equal, &out.Equal
-		*out = new(VitessKeyspaceEqualPartitioning)
-		(*in).DeepCopyInto(*out)
+	in.Images.DeepCopyInto(&out.Images)
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.Custom != nil {
-		in, out := &in.Custom, &out.Custom
-		*out = new(VitessKeyspaceCustomPartitioning)
-		(*in).DeepCopyInto(*out)
+	if in.VttabletFlags != nil {
+		in, out := &in.VttabletFlags, &out.VttabletFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TabletPools != nil {
+		in, out := &in.TabletPools, &out.TabletPools
+		*out = make([]VitessShardTabletPoolSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioning.
-func (in *VitessKeyspacePartitioning) DeepCopy() *VitessKeyspacePartitioning {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardSnapshot.
+func (in *VitessShardSnapshot) DeepCopy() *VitessShardSnapshot {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspacePartitioning)
+	out := new(VitessShardSnapshot)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspacePartitioningStatus) DeepCopyInto(out *VitessKeyspacePartitioningStatus) {
+func (in *VitessShardSpec) DeepCopyInto(out *VitessShardSpec) {
 	*out = *in
-	if in.ShardNames != nil {
-		in, out := &in.ShardNames, &out.ShardNames
-		*out = make([]string, len(*in))
+	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
+	if in.ZoneMap != nil {
+		in, out := &in.ZoneMap, &out.ZoneMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Images.DeepCopyInto(&out.Images)
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	out.KeyRange = in.KeyRange
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	if in.VitessOrchestrator != nil {
+		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
+		*out = new(VitessOrchestratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupLocations != nil {
+		in, out := &in.BackupLocations, &out.BackupLocations
+		*out = make([]VitessBackupLocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackupCompression != nil {
+		in, out := &in.BackupCompression, &out.BackupCompression
+		*out = new(BackupCompressionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupXtrabackup != nil {
+		in, out := &in.BackupXtrabackup, &out.BackupXtrabackup
+		*out = new(XtrabackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupStaleThreshold != nil {
+		in, out := &in.BackupStaleThreshold, &out.BackupStaleThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.BackupDisruptionPolicy != nil {
+		in, out := &in.BackupDisruptionPolicy, &out.BackupDisruptionPolicy
+		*out = new(VitessBackupDisruptionPolicy)
+		**out = **in
+	}
+	if in.MaxConcurrentRestores != nil {
+		in, out := &in.MaxConcurrentRestores, &out.MaxConcurrentRestores
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Vtbackup != nil {
+		in, out := &in.Vtbackup, &out.Vtbackup
+		*out = new(VtbackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ComponentVitessFlags != nil {
+		in, out := &in.ComponentVitessFlags, &out.ComponentVitessFlags
+		*out = new(ComponentVitessFlags)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(NetworkingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceDefaults != nil {
+		in, out := &in.ResourceDefaults, &out.ResourceDefaults
+		*out = new(ResourceDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverBuffer != nil {
+		in, out := &in.FailoverBuffer, &out.FailoverBuffer
+		*out = new(FailoverBufferSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Evacuation != nil {
+		in, out := &in.Evacuation, &out.Evacuation
+		*out = new(EvacuationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VitessClusterUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(VitessClusterStandbySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]VitessMySQLUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InternalCredentials != nil {
+		in, out := &in.InternalCredentials, &out.InternalCredentials
+		*out = new(VitessInternalCredentialsSpec)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioningStatus.
-func (in *VitessKeyspacePartitioningStatus) DeepCopy() *VitessKeyspacePartitioningStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardSpec.
+func (in *VitessShardSpec) DeepCopy() *VitessShardSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspacePartitioningStatus)
+	out := new(VitessShardSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceShardStatus) DeepCopyInto(out *VitessKeyspaceShardStatus) {
+func (in *VitessShardStandbyStatus) DeepCopyInto(out *VitessShardStandbyStatus) {
 	*out = *in
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.RestoredBackupTime != nil {
+		in, out := &in.RestoredBackupTime, &out.RestoredBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LatestBackupTime != nil {
+		in, out := &in.LatestBackupTime, &out.LatestBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRestoreTime != nil {
+		in, out := &in.LastRestoreTime, &out.LastRestoreTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceShardStatus.
-func (in *VitessKeyspaceShardStatus) DeepCopy() *VitessKeyspaceShardStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardStandbyStatus.
+func (in *VitessShardStandbyStatus) DeepCopy() *VitessShardStandbyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceShardStatus)
+	out := new(VitessShardStandbyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceSpec) DeepCopyInto(out *VitessKeyspaceSpec) {
+func (in *VitessShardStatus) DeepCopyInto(out *VitessShardStatus) {
 	*out = *in
-	in.VitessKeyspaceTemplate.DeepCopyInto(&out.VitessKeyspaceTemplate)
-	out.GlobalLockserver = in.GlobalLockserver
-	in.Images.DeepCopyInto(&out.Images)
-	out.ImagePullPolicies = in.ImagePullPolicies
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
+	if in.DryRunChanges != nil {
+		in, out := &in.DryRunChanges, &out.DryRunChanges
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ZoneMap != nil {
-		in, out := &in.ZoneMap, &out.ZoneMap
-		*out = make(map[string]string, len(*in))
+	if in.Tablets != nil {
+		in, out := &in.Tablets, &out.Tablets
+		*out = make(map[string]VitessTabletStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.OrphanedTablets != nil {
+		in, out := &in.OrphanedTablets, &out.OrphanedTablets
+		*out = make(map[string]OrphanStatus, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.VitessOrchestrator = in.VitessOrchestrator
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(map[VitessShardConditionType]VitessShardCondition, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.BackupLocations != nil {
 		in, out := &in.BackupLocations, &out.BackupLocations
-		*out = make([]VitessBackupLocation, len(*in))
+		*out = make([]*ShardBackupLocationStatus, len(*in))
 		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ShardBackupLocationStatus)
+				(*in).DeepCopyInto(*out)
+			}
 		}
 	}
-	if in.ExtraVitessFlags != nil {
-		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.MysqlUpgrade != nil {
+		in, out := &in.MysqlUpgrade, &out.MysqlUpgrade
+		*out = new(VitessShardMysqlUpgradeStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.TopologyReconciliation != nil {
-		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
-		*out = new(TopoReconcileConfig)
+	if in.Throttler != nil {
+		in, out := &in.Throttler, &out.Throttler
+		*out = new(VitessShardThrottlerStatus)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(VitessShardBackupStatus)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.UpdateStrategy != nil {
-		in, out := &in.UpdateStrategy, &out.UpdateStrategy
-		*out = new(VitessClusterUpdateStrategy)
+	if in.Reparent != nil {
+		in, out := &in.Reparent, &out.Reparent
+		*out = new(VitessShardReparentStatus)
 		(*in).DeepCopyInto(*out)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceSpec.
-func (in *VitessKeyspaceSpec) DeepCopy() *VitessKeyspaceSpec {
-	if in == nil {
-		return nil
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(VitessShardStandbyStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(VitessKeyspaceSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceStatus) DeepCopyInto(out *VitessKeyspaceStatus) {
-	*out = *in
-	if in.Shards != nil {
-		in, out := &in.Shards, &out.Shards
-		*out = make(map[string]VitessKeyspaceShardStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = new(VitessShardUsersStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Partitionings != nil {
-		in, out := &in.Partitionings, &out.Partitionings
-		*out = make([]VitessKeyspacePartitioningStatus, len(*in))
+	if in.InternalCredentials != nil {
+		in, out := &in.InternalCredentials, &out.InternalCredentials
+		*out = new(VitessShardInternalCredentialsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VerticalAutoscaling != nil {
+		in, out := &in.VerticalAutoscaling, &out.VerticalAutoscaling
+		*out = make([]VitessTabletPoolAutoscalingStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.OrphanedShards != nil {
-		in, out := &in.OrphanedShards, &out.OrphanedShards
-		*out = make(map[string]OrphanStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Resharding != nil {
-		in, out := &in.Resharding, &out.Resharding
-		*out = new(ReshardingStatus)
+	if in.LastKnownGood != nil {
+		in, out := &in.LastKnownGood, &out.LastKnownGood
+		*out = new(VitessShardSnapshot)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]VitessKeyspaceCondition, len(*in))
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]VitessShardSnapshot, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UnhealthyTablets != nil {
+		in, out := &in.UnhealthyTablets, &out.UnhealthyTablets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevertedTablets != nil {
+		in, out := &in.RevertedTablets, &out.RevertedTablets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceStatus.
-func (in *VitessKeyspaceStatus) DeepCopy() *VitessKeyspaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardStatus.
+func (in *VitessShardStatus) DeepCopy() *VitessShardStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceStatus)
+	out := new(VitessShardStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceTemplate) DeepCopyInto(out *VitessKeyspaceTemplate) {
+func (in *VitessShardTabletPool) DeepCopyInto(out *VitessShardTabletPool) {
 	*out = *in
-	if in.VitessOrchestrator != nil {
-		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
-		*out = new(VitessOrchestratorSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Partitionings != nil {
-		in, out := &in.Partitionings, &out.Partitionings
-		*out = make([]VitessKeyspacePartitioning, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	in.VitessShardTabletPoolTemplate.DeepCopyInto(&out.VitessShardTabletPoolTemplate)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceTemplate.
-func (in *VitessKeyspaceTemplate) DeepCopy() *VitessKeyspaceTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTabletPool.
+func (in *VitessShardTabletPool) DeepCopy() *VitessShardTabletPool {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceTemplate)
+	out := new(VitessShardTabletPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessLockserverParams) DeepCopyInto(out *VitessLockserverParams) {
+func (in *VitessShardTabletPoolSnapshot) DeepCopyInto(out *VitessShardTabletPoolSnapshot) {
 	*out = *in
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessLockserverParams.
-func (in *VitessLockserverParams) DeepCopy() *VitessLockserverParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTabletPoolSnapshot.
+func (in *VitessShardTabletPoolSnapshot) DeepCopy() *VitessShardTabletPoolSnapshot {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessLockserverParams)
+	out := new(VitessShardTabletPoolSnapshot)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessOrchestratorSpec) DeepCopyInto(out *VitessOrchestratorSpec) {
+func (in *VitessShardTabletPoolTemplate) DeepCopyInto(out *VitessShardTabletPoolTemplate) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.ExtraFlags != nil {
-		in, out := &in.ExtraFlags, &out.ExtraFlags
+	if in.DataVolumeClaimTemplate != nil {
+		in, out := &in.DataVolumeClaimTemplate, &out.DataVolumeClaimTemplate
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scratch != nil {
+		in, out := &in.Scratch, &out.Scratch
+		*out = new(VitessTabletPoolScratch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalStorage != nil {
+		in, out := &in.LocalStorage, &out.LocalStorage
+		*out = new(VitessTabletPoolLocalStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Vttablet.DeepCopyInto(&out.Vttablet)
+	if in.Mysqld != nil {
+		in, out := &in.Mysqld, &out.Mysqld
+		*out = new(MysqldSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDatastore != nil {
+		in, out := &in.ExternalDatastore, &out.ExternalDatastore
+		*out = new(ExternalDatastore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MysqldExporter != nil {
+		in, out := &in.MysqldExporter, &out.MysqldExporter
+		*out = new(MysqldExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VerticalAutoscaling != nil {
+		in, out := &in.VerticalAutoscaling, &out.VerticalAutoscaling
+		*out = new(VitessTabletPoolVerticalAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
@@ -2035,6 +4791,13 @@ func (in *VitessOrchestratorSpec) DeepCopyInto(out *VitessOrchestratorSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExtraVolumeClaimTemplates != nil {
+		in, out := &in.ExtraVolumeClaimTemplates, &out.ExtraVolumeClaimTemplates
+		*out = make([]VolumeClaimTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ExtraVolumeMounts != nil {
 		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
 		*out = make([]v1.VolumeMount, len(*in))
@@ -2056,11 +4819,54 @@ func (in *VitessOrchestratorSpec) DeepCopyInto(out *VitessOrchestratorSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSConfig != nil {
+		in, out := &in.DNSConfig, &out.DNSConfig
+		*out = new(v1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTabletPoolTemplate.
+func (in *VitessShardTabletPoolTemplate) DeepCopy() *VitessShardTabletPoolTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardTabletPoolTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardTemplate) DeepCopyInto(out *VitessShardTemplate) {
+	*out = *in
+	if in.TabletPools != nil {
+		in, out := &in.TabletPools, &out.TabletPools
+		*out = make([]VitessShardTabletPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.DatabaseInitScriptSecret = in.DatabaseInitScriptSecret
+	if in.DatabaseInitScriptFragments != nil {
+		in, out := &in.DatabaseInitScriptFragments, &out.DatabaseInitScriptFragments
+		*out = make([]SecretSource, len(*in))
+		copy(*out, *in)
+	}
+	in.Replication.DeepCopyInto(&out.Replication)
 	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
@@ -2068,103 +4874,90 @@ func (in *VitessOrchestratorSpec) DeepCopyInto(out *VitessOrchestratorSpec) {
 			(*out)[key] = val
 		}
 	}
-	if in.ExtraLabels != nil {
-		in, out := &in.ExtraLabels, &out.ExtraLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceOverrides)
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = new(VitessKeyspaceImages)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorSpec.
-func (in *VitessOrchestratorSpec) DeepCopy() *VitessOrchestratorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTemplate.
+func (in *VitessShardTemplate) DeepCopy() *VitessShardTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessOrchestratorSpec)
+	out := new(VitessShardTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessOrchestratorStatus) DeepCopyInto(out *VitessOrchestratorStatus) {
+func (in *VitessShardThrottlerStatus) DeepCopyInto(out *VitessShardThrottlerStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorStatus.
-func (in *VitessOrchestratorStatus) DeepCopy() *VitessOrchestratorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardThrottlerStatus.
+func (in *VitessShardThrottlerStatus) DeepCopy() *VitessShardThrottlerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessOrchestratorStatus)
+	out := new(VitessShardThrottlerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessReplicationSpec) DeepCopyInto(out *VitessReplicationSpec) {
+func (in *VitessShardUsersStatus) DeepCopyInto(out *VitessShardUsersStatus) {
 	*out = *in
-	if in.InitializeMaster != nil {
-		in, out := &in.InitializeMaster, &out.InitializeMaster
-		*out = new(bool)
-		**out = **in
-	}
-	if in.InitializeBackup != nil {
-		in, out := &in.InitializeBackup, &out.InitializeBackup
-		*out = new(bool)
-		**out = **in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = make([]VitessMySQLUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.RecoverRestartedMaster != nil {
-		in, out := &in.RecoverRestartedMaster, &out.RecoverRestartedMaster
-		*out = new(bool)
-		**out = **in
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReplicationSpec.
-func (in *VitessReplicationSpec) DeepCopy() *VitessReplicationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardUsersStatus.
+func (in *VitessShardUsersStatus) DeepCopy() *VitessShardUsersStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessReplicationSpec)
+	out := new(VitessShardUsersStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShard) DeepCopyInto(out *VitessShard) {
+func (in *VitessTabletPool) DeepCopyInto(out *VitessTabletPool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Spec = in.Spec
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShard.
-func (in *VitessShard) DeepCopy() *VitessShard {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPool.
+func (in *VitessTabletPool) DeepCopy() *VitessTabletPool {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShard)
+	out := new(VitessTabletPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessShard) DeepCopyObject() runtime.Object {
+func (in *VitessTabletPool) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2172,50 +4965,53 @@ func (in *VitessShard) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardCondition) DeepCopyInto(out *VitessShardCondition) {
+func (in *VitessTabletPoolAutoscalingStatus) DeepCopyInto(out *VitessTabletPoolAutoscalingStatus) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]VitessContainerAutoscalingStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardCondition.
-func (in *VitessShardCondition) DeepCopy() *VitessShardCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolAutoscalingStatus.
+func (in *VitessTabletPoolAutoscalingStatus) DeepCopy() *VitessTabletPoolAutoscalingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardCondition)
+	out := new(VitessTabletPoolAutoscalingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardList) DeepCopyInto(out *VitessShardList) {
+func (in *VitessTabletPoolList) DeepCopyInto(out *VitessTabletPoolList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VitessShard, len(*in))
+		*out = make([]VitessTabletPool, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardList.
-func (in *VitessShardList) DeepCopy() *VitessShardList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolList.
+func (in *VitessTabletPoolList) DeepCopy() *VitessTabletPoolList {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardList)
+	out := new(VitessTabletPoolList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessShardList) DeepCopyObject() runtime.Object {
+func (in *VitessTabletPoolList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2223,245 +5019,127 @@ func (in *VitessShardList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardSpec) DeepCopyInto(out *VitessShardSpec) {
+func (in *VitessTabletPoolLocalStorage) DeepCopyInto(out *VitessTabletPoolLocalStorage) {
 	*out = *in
-	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
-	if in.ZoneMap != nil {
-		in, out := &in.ZoneMap, &out.ZoneMap
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Images.DeepCopyInto(&out.Images)
-	out.ImagePullPolicies = in.ImagePullPolicies
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
+	if in.NodeLossTimeout != nil {
+		in, out := &in.NodeLossTimeout, &out.NodeLossTimeout
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	out.KeyRange = in.KeyRange
-	out.GlobalLockserver = in.GlobalLockserver
-	if in.VitessOrchestrator != nil {
-		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
-		*out = new(VitessOrchestratorSpec)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolLocalStorage.
+func (in *VitessTabletPoolLocalStorage) DeepCopy() *VitessTabletPoolLocalStorage {
+	if in == nil {
+		return nil
 	}
-	if in.BackupLocations != nil {
-		in, out := &in.BackupLocations, &out.BackupLocations
-		*out = make([]VitessBackupLocation, len(*in))
+	out := new(VitessTabletPoolLocalStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessTabletPoolRecommendation) DeepCopyInto(out *VitessTabletPoolRecommendation) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]VitessContainerRecommendation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.ExtraVitessFlags != nil {
-		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.TopologyReconciliation != nil {
-		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
-		*out = new(TopoReconcileConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.UpdateStrategy != nil {
-		in, out := &in.UpdateStrategy, &out.UpdateStrategy
-		*out = new(VitessClusterUpdateStrategy)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardSpec.
-func (in *VitessShardSpec) DeepCopy() *VitessShardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolRecommendation.
+func (in *VitessTabletPoolRecommendation) DeepCopy() *VitessTabletPoolRecommendation {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardSpec)
+	out := new(VitessTabletPoolRecommendation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardStatus) DeepCopyInto(out *VitessShardStatus) {
+func (in *VitessTabletPoolScratch) DeepCopyInto(out *VitessTabletPoolScratch) {
 	*out = *in
-	if in.Tablets != nil {
-		in, out := &in.Tablets, &out.Tablets
-		*out = make(map[string]VitessTabletStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.OrphanedTablets != nil {
-		in, out := &in.OrphanedTablets, &out.OrphanedTablets
-		*out = make(map[string]OrphanStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	out.VitessOrchestrator = in.VitessOrchestrator
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(map[VitessShardConditionType]VitessShardCondition, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.BackupLocations != nil {
-		in, out := &in.BackupLocations, &out.BackupLocations
-		*out = make([]*ShardBackupLocationStatus, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ShardBackupLocationStatus)
-				(*in).DeepCopyInto(*out)
-			}
-		}
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.RestoreInterval != nil {
+		in, out := &in.RestoreInterval, &out.RestoreInterval
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardStatus.
-func (in *VitessShardStatus) DeepCopy() *VitessShardStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolScratch.
+func (in *VitessTabletPoolScratch) DeepCopy() *VitessTabletPoolScratch {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardStatus)
+	out := new(VitessTabletPoolScratch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardTabletPool) DeepCopyInto(out *VitessShardTabletPool) {
+func (in *VitessTabletPoolSpec) DeepCopyInto(out *VitessTabletPoolSpec) {
 	*out = *in
-	if in.DataVolumeClaimTemplate != nil {
-		in, out := &in.DataVolumeClaimTemplate, &out.DataVolumeClaimTemplate
-		*out = new(v1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Vttablet.DeepCopyInto(&out.Vttablet)
-	if in.Mysqld != nil {
-		in, out := &in.Mysqld, &out.Mysqld
-		*out = new(MysqldSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ExternalDatastore != nil {
-		in, out := &in.ExternalDatastore, &out.ExternalDatastore
-		*out = new(ExternalDatastore)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExtraLabels != nil {
-		in, out := &in.ExtraLabels, &out.ExtraLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExtraEnv != nil {
-		in, out := &in.ExtraEnv, &out.ExtraEnv
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumeMounts != nil {
-		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.SidecarContainers != nil {
-		in, out := &in.SidecarContainers, &out.SidecarContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]v1.TopologySpreadConstraint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolSpec.
+func (in *VitessTabletPoolSpec) DeepCopy() *VitessTabletPoolSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(VitessTabletPoolSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTabletPool.
-func (in *VitessShardTabletPool) DeepCopy() *VitessShardTabletPool {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessTabletPoolStatus) DeepCopyInto(out *VitessTabletPoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolStatus.
+func (in *VitessTabletPoolStatus) DeepCopy() *VitessTabletPoolStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardTabletPool)
+	out := new(VitessTabletPoolStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardTemplate) DeepCopyInto(out *VitessShardTemplate) {
+func (in *VitessTabletPoolVerticalAutoscaling) DeepCopyInto(out *VitessTabletPoolVerticalAutoscaling) {
 	*out = *in
-	if in.TabletPools != nil {
-		in, out := &in.TabletPools, &out.TabletPools
-		*out = make([]VitessShardTabletPool, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
 		}
 	}
-	out.DatabaseInitScriptSecret = in.DatabaseInitScriptSecret
-	in.Replication.DeepCopyInto(&out.Replication)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(v1.ResourceList, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = val.DeepCopy()
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTemplate.
-func (in *VitessShardTemplate) DeepCopy() *VitessShardTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolVerticalAutoscaling.
+func (in *VitessTabletPoolVerticalAutoscaling) DeepCopy() *VitessTabletPoolVerticalAutoscaling {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardTemplate)
+	out := new(VitessTabletPoolVerticalAutoscaling)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2469,6 +5147,11 @@ func (in *VitessShardTemplate) DeepCopy() *VitessShardTemplate {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VitessTabletStatus) DeepCopyInto(out *VitessTabletStatus) {
 	*out = *in
+	if in.ReplicationLagSeconds != nil {
+		in, out := &in.ReplicationLagSeconds, &out.ReplicationLagSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletStatus.
@@ -2481,6 +5164,54 @@ func (in *VitessTabletStatus) DeepCopy() *VitessTabletStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessVersionSkew) DeepCopyInto(out *VitessVersionSkew) {
+	*out = *in
+	in.Images.DeepCopyInto(&out.Images)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessVersionSkew.
+func (in *VitessVersionSkew) DeepCopy() *VitessVersionSkew {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessVersionSkew)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeClaimBackupLocation) DeepCopyInto(out *VolumeClaimBackupLocation) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimBackupLocation.
+func (in *VolumeClaimBackupLocation) DeepCopy() *VolumeClaimBackupLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeClaimBackupLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeClaimTemplate) DeepCopyInto(out *VolumeClaimTemplate) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTemplate.
+func (in *VolumeClaimTemplate) DeepCopy() *VolumeClaimTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeClaimTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VtAdminSpec) DeepCopyInto(out *VtAdminSpec) {
 	*out = *in
@@ -2611,6 +5342,188 @@ func (in *VtadminStatus) DeepCopy() *VtadminStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VtbackupSpec) DeepCopyInto(out *VtbackupSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.XtrabackupStripes != nil {
+		in, out := &in.XtrabackupStripes, &out.XtrabackupStripes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.XtrabackupParallelism != nil {
+		in, out := &in.XtrabackupParallelism, &out.XtrabackupParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VtbackupSpec.
+func (in *VtbackupSpec) DeepCopy() *VtbackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VtbackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VtctldGRPCSpec) DeepCopyInto(out *VtctldGRPCSpec) {
+	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(VtctldGRPCTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VtctldGRPCSpec.
+func (in *VtctldGRPCSpec) DeepCopy() *VtctldGRPCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VtctldGRPCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VtctldGRPCTLSSpec) DeepCopyInto(out *VtctldGRPCTLSSpec) {
+	*out = *in
+	out.CertSecret = in.CertSecret
+	out.KeySecret = in.KeySecret
+	if in.ClientCACertSecret != nil {
+		in, out := &in.ClientCACertSecret, &out.ClientCACertSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VtctldGRPCTLSSpec.
+func (in *VtctldGRPCTLSSpec) DeepCopy() *VtctldGRPCTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VtctldGRPCTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VttabletHotRowProtectionSpec) DeepCopyInto(out *VttabletHotRowProtectionSpec) {
+	*out = *in
+	if in.MaxQueueSize != nil {
+		in, out := &in.MaxQueueSize, &out.MaxQueueSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxGlobalQueueSize != nil {
+		in, out := &in.MaxGlobalQueueSize, &out.MaxGlobalQueueSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConcurrentTransactions != nil {
+		in, out := &in.ConcurrentTransactions, &out.ConcurrentTransactions
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletHotRowProtectionSpec.
+func (in *VttabletHotRowProtectionSpec) DeepCopy() *VttabletHotRowProtectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VttabletHotRowProtectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VttabletQueryServerSpec) DeepCopyInto(out *VttabletQueryServerSpec) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StreamPoolSize != nil {
+		in, out := &in.StreamPoolSize, &out.StreamPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TransactionCap != nil {
+		in, out := &in.TransactionCap, &out.TransactionCap
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxResultSize != nil {
+		in, out := &in.MaxResultSize, &out.MaxResultSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueryTimeout != nil {
+		in, out := &in.QueryTimeout, &out.QueryTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TransactionTimeout != nil {
+		in, out := &in.TransactionTimeout, &out.TransactionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HotRowProtection != nil {
+		in, out := &in.HotRowProtection, &out.HotRowProtection
+		*out = new(VttabletHotRowProtectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletQueryServerSpec.
+func (in *VttabletQueryServerSpec) DeepCopy() *VttabletQueryServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VttabletQueryServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VttabletSpec) DeepCopyInto(out *VttabletSpec) {
 	*out = *in
@@ -2623,6 +5536,31 @@ func (in *VttabletSpec) DeepCopyInto(out *VttabletSpec) {
 		}
 	}
 	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ProbeOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryServer != nil {
+		in, out := &in.QueryServer, &out.QueryServer
+		*out = new(VttabletQueryServerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Throttler != nil {
+		in, out := &in.Throttler, &out.Throttler
+		*out = new(VttabletThrottlerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TransactionThrottler != nil {
+		in, out := &in.TransactionThrottler, &out.TransactionThrottler
+		*out = new(VttabletTransactionThrottlerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletSpec.
@@ -2634,3 +5572,93 @@ func (in *VttabletSpec) DeepCopy() *VttabletSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VttabletThrottlerSpec) DeepCopyInto(out *VttabletThrottlerSpec) {
+	*out = *in
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TabletTypes != nil {
+		in, out := &in.TabletTypes, &out.TabletTypes
+		*out = make([]VitessTabletPoolType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletThrottlerSpec.
+func (in *VttabletThrottlerSpec) DeepCopy() *VttabletThrottlerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VttabletThrottlerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VttabletTransactionThrottlerSpec) DeepCopyInto(out *VttabletTransactionThrottlerSpec) {
+	*out = *in
+	if in.TargetReplicationLag != nil {
+		in, out := &in.TargetReplicationLag, &out.TargetReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HealthCheckCells != nil {
+		in, out := &in.HealthCheckCells, &out.HealthCheckCells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletTransactionThrottlerSpec.
+func (in *VttabletTransactionThrottlerSpec) DeepCopy() *VttabletTransactionThrottlerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VttabletTransactionThrottlerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XtrabackupSpec) DeepCopyInto(out *XtrabackupSpec) {
+	*out = *in
+	if in.Stripes != nil {
+		in, out := &in.Stripes, &out.Stripes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StripeBlockSize != nil {
+		in, out := &in.StripeBlockSize, &out.StripeBlockSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackupThreads != nil {
+		in, out := &in.BackupThreads, &out.BackupThreads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(BackupCompressionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XtrabackupSpec.
+func (in *XtrabackupSpec) DeepCopy() *XtrabackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(XtrabackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
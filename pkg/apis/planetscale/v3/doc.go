@@ -0,0 +1,23 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v3 contains API Schema definitions for the planetscale.com/v3 API group.
+//
+// Only VitessCluster is defined in v3. VitessClusters are stored as v2, and
+// converted to and from v3 by the operator's conversion webhook.
+// +k8s:deepcopy-gen=package,register
+// +groupName=planetscale.com
+package v3
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// ConvertTo converts this VitessCluster to the hub version (v2).
+func (src *VitessCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*planetscalev2.VitessCluster)
+
+	// Everything other than tablet pools has the same schema in both versions.
+	dst.Spec = planetscalev2.VitessClusterSpec{}
+	if err := convertJSON(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	return forEachShardTemplate(&src.Spec, &dst.Spec, func(key string, srcShard *VitessShardTemplate, dstShard *planetscalev2.VitessShardTemplate) error {
+		pools, err := convertTabletPoolsTo(srcShard.TabletPools)
+		if err != nil {
+			return fmt.Errorf("shard template %v: %v", key, err)
+		}
+		dstShard.TabletPools = pools
+		return nil
+	})
+}
+
+// ConvertFrom converts from the hub version (v2) to this version.
+func (dst *VitessCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*planetscalev2.VitessCluster)
+
+	dst.Spec = VitessClusterSpec{}
+	if err := convertJSON(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	return forEachShardTemplate(&dst.Spec, &src.Spec, func(key string, dstShard *VitessShardTemplate, srcShard *planetscalev2.VitessShardTemplate) error {
+		dstShard.TabletPools = convertTabletPoolsFrom(srcShard.TabletPools)
+		return nil
+	})
+}
+
+// convertJSON copies the fields of in to the fields with the same JSON names
+// in out.
+func convertJSON(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// forEachShardTemplate calls fn for each pair of corresponding shard
+// templates in a v3 spec and a v2 spec converted from or to it.
+func forEachShardTemplate(v3Spec *VitessClusterSpec, v2Spec *planetscalev2.VitessClusterSpec, fn func(key string, v3Shard *VitessShardTemplate, v2Shard *planetscalev2.VitessShardTemplate) error) error {
+	for i := range v3Spec.Keyspaces {
+		v3Keyspace, v2Keyspace := &v3Spec.Keyspaces[i], &v2Spec.Keyspaces[i]
+		for j := range v3Keyspace.Partitionings {
+			v3Partitioning, v2Partitioning := &v3Keyspace.Partitionings[j], &v2Keyspace.Partitionings[j]
+			key := fmt.Sprintf("%v/%v", v3Keyspace.Name, j)
+			if v3Partitioning.Equal != nil {
+				if err := fn(key, &v3Partitioning.Equal.ShardTemplate, &v2Partitioning.Equal.ShardTemplate); err != nil {
+					return err
+				}
+			}
+			if v3Partitioning.Custom != nil {
+				if v3Partitioning.Custom.ShardTemplate != nil {
+					if err := fn(key+"/template", v3Partitioning.Custom.ShardTemplate, v2Partitioning.Custom.ShardTemplate); err != nil {
+						return err
+					}
+				}
+				for k := range v3Partitioning.Custom.Shards {
+					v3Shard, v2Shard := &v3Partitioning.Custom.Shards[k], &v2Partitioning.Custom.Shards[k]
+					shardKey := fmt.Sprintf("%v/%v", key, v3Shard.KeyRange.String())
+					if err := fn(shardKey, &v3Shard.VitessShardTemplate, &v2Shard.VitessShardTemplate); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// convertTabletPoolsTo expands v3 tablet pools into one v2 tablet pool per
+// cell. A v3 pool named after its type becomes an unnamed v2 pool, since
+// that's how v2 pools are identified by default.
+func convertTabletPoolsTo(in []VitessShardTabletPool) ([]planetscalev2.VitessShardTabletPool, error) {
+	var pools []planetscalev2.VitessShardTabletPool
+	seen := map[string]bool{}
+	for i := range in {
+		pool := &in[i]
+		name := pool.Name
+		if name == string(pool.Type) {
+			name = ""
+		}
+		for cellIndex, cell := range pool.Cells {
+			v2Pool := planetscalev2.VitessShardTabletPool{
+				Name:                          name,
+				Cell:                          cell,
+				Type:                          pool.Type,
+				Replicas:                      pool.ReplicasInCell(cellIndex),
+				VitessShardTabletPoolTemplate: *pool.VitessShardTabletPoolTemplate.DeepCopy(),
+			}
+			poolKey := fmt.Sprintf("%v/%v", cell, v2Pool.ID())
+			if seen[poolKey] {
+				return nil, fmt.Errorf("more than one tablet pool is named %q in cell %q", v2Pool.ID(), cell)
+			}
+			seen[poolKey] = true
+			pools = append(pools, v2Pool)
+		}
+	}
+	return pools, nil
+}
+
+/*
+convertTabletPoolsFrom groups v2 tablet pools into v3 tablet pools named
+after their IDs (see VitessShardTabletPool.ID).
+
+The v2 pools with the same ID and type are grouped into one v3 pool if
+they're identical apart from their cell and replica count. Otherwise, each
+distinct configuration becomes a v3 pool of its own, with the same name.
+*/
+func convertTabletPoolsFrom(in []planetscalev2.VitessShardTabletPool) []VitessShardTabletPool {
+	var pools []VitessShardTabletPool
+	var replicas [][]int32
+	for i := range in {
+		pool := &in[i]
+		group := -1
+		for j := range pools {
+			if tabletPoolGroupMatches(&pools[j], pool) {
+				group = j
+				break
+			}
+		}
+		if group >= 0 {
+			pools[group].Cells = append(pools[group].Cells, pool.Cell)
+			replicas[group] = append(replicas[group], pool.Replicas)
+			continue
+		}
+		pools = append(pools, VitessShardTabletPool{
+			Name:                          pool.ID(),
+			Cells:                         []string{pool.Cell},
+			Type:                          pool.Type,
+			VitessShardTabletPoolTemplate: *pool.VitessShardTabletPoolTemplate.DeepCopy(),
+		})
+		replicas = append(replicas, []int32{pool.Replicas})
+	}
+	for i := range pools {
+		setTabletPoolReplicas(&pools[i], replicas[i])
+	}
+	return pools
+}
+
+// tabletPoolGroupMatches returns whether a v2 tablet pool can be merged into
+// a v3 tablet pool as another one of its cells.
+func tabletPoolGroupMatches(group *VitessShardTabletPool, pool *planetscalev2.VitessShardTabletPool) bool {
+	return group.Name == pool.ID() &&
+		group.Type == pool.Type &&
+		apiequality.Semantic.DeepEqual(&group.VitessShardTabletPoolTemplate, &pool.VitessShardTabletPoolTemplate)
+}
+
+// setTabletPoolReplicas sets the replica count fields of a v3 tablet pool to
+// deploy the given number of tablets in each of its cells, preferring
+// Replicas, then TotalReplicas, then overrides in CellReplicas.
+func setTabletPoolReplicas(pool *VitessShardTabletPool, replicas []int32) {
+	var total int32
+	uniform, even := true, true
+	for _, n := range replicas {
+		total += n
+	}
+	for i, n := range replicas {
+		uniform = uniform && n == replicas[0]
+		even = even && n == evenReplicas(total, len(replicas), i)
+	}
+
+	pool.Replicas = replicas[0]
+	switch {
+	case uniform:
+	case even:
+		pool.Replicas = 0
+		pool.TotalReplicas = &total
+	default:
+		for i, n := range replicas {
+			if n == pool.Replicas {
+				continue
+			}
+			if pool.CellReplicas == nil {
+				pool.CellReplicas = map[string]int32{}
+			}
+			pool.CellReplicas[pool.Cells[i]] = n
+		}
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func newTestCluster(pools ...VitessShardTabletPool) *VitessCluster {
+	return &VitessCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: VitessClusterSpec{
+			Keyspaces: []VitessKeyspaceTemplate{
+				{
+					Name: "commerce",
+					Partitionings: []VitessKeyspacePartitioning{
+						{
+							Equal: &VitessKeyspaceEqualPartitioning{
+								Parts:         2,
+								ShardTemplate: VitessShardTemplate{TabletPools: pools},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestVitessClusterConversionRoundTrip(t *testing.T) {
+	src := newTestCluster(
+		VitessShardTabletPool{
+			Name:     "main",
+			Cells:    []string{"zone1", "zone2"},
+			Type:     planetscalev2.ReplicaPoolType,
+			Replicas: 2,
+			VitessShardTabletPoolTemplate: planetscalev2.VitessShardTabletPoolTemplate{
+				Vttablet: planetscalev2.VttabletSpec{ExtraFlags: map[string]string{"queryserver-config-pool-size": "8"}},
+			},
+		},
+		VitessShardTabletPool{
+			Name:     "analytics",
+			Cells:    []string{"zone1"},
+			Type:     planetscalev2.RdonlyPoolType,
+			Replicas: 1,
+		},
+	)
+
+	hub := &planetscalev2.VitessCluster{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() error: %v", err)
+	}
+	pools := hub.Spec.Keyspaces[0].Partitionings[0].Equal.ShardTemplate.TabletPools
+	if got, want := len(pools), 3; got != want {
+		t.Fatalf("len(v2 pools) = %v, want %v", got, want)
+	}
+	for _, pool := range pools {
+		if pool.Type == planetscalev2.ReplicaPoolType && pool.Vttablet.ExtraFlags["queryserver-config-pool-size"] != "8" {
+			t.Errorf("v2 pool %v/%v lost its vttablet flags", pool.Cell, pool.Type)
+		}
+	}
+
+	dst := &VitessCluster{}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error: %v", err)
+	}
+	if !reflect.DeepEqual(dst, src) {
+		t.Errorf("round trip = %#v, want %#v", dst, src)
+	}
+}
+
+func TestVitessClusterConversionOverlappingPools(t *testing.T) {
+	src := newTestCluster(
+		VitessShardTabletPool{Name: "a", Cells: []string{"zone1", "zone2"}, Type: planetscalev2.ReplicaPoolType},
+		VitessShardTabletPool{Name: "a", Cells: []string{"zone2"}, Type: planetscalev2.RdonlyPoolType},
+	)
+	if err := src.ConvertTo(&planetscalev2.VitessCluster{}); err == nil {
+		t.Errorf("ConvertTo() of pools with the same name and cell didn't fail")
+	}
+}
+
+func TestConvertTabletPoolsTo(t *testing.T) {
+	in := []VitessShardTabletPool{
+		{Name: "replica", Cells: []string{"zone1"}, Type: planetscalev2.ReplicaPoolType, Replicas: 2},
+		{Name: "canary", Cells: []string{"zone1"}, Type: planetscalev2.ReplicaPoolType, Replicas: 1},
+	}
+	pools, err := convertTabletPoolsTo(in)
+	if err != nil {
+		t.Fatalf("convertTabletPoolsTo() error: %v", err)
+	}
+	var names []string
+	for _, pool := range pools {
+		names = append(names, pool.Name)
+	}
+	// A pool named after its type is an unnamed v2 pool.
+	if want := []string{"", "canary"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("convertTabletPoolsTo() names = %q, want %q", names, want)
+	}
+}
+
+func TestConvertTabletPoolsFrom(t *testing.T) {
+	in := []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 2},
+		{Cell: "zone1", Type: planetscalev2.RdonlyPoolType, Replicas: 1},
+		{Name: "canary", Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 1},
+		{Cell: "zone2", Type: planetscalev2.RdonlyPoolType, Replicas: 1},
+		{Cell: "zone2", Type: planetscalev2.ReplicaPoolType, Replicas: 3},
+		// The backup location differs, so this isn't grouped with zone2.
+		{
+			Cell:                          "zone3",
+			Type:                          planetscalev2.RdonlyPoolType,
+			Replicas:                      1,
+			VitessShardTabletPoolTemplate: planetscalev2.VitessShardTabletPoolTemplate{BackupLocationName: "west"},
+		},
+	}
+	var got []string
+	for _, pool := range convertTabletPoolsFrom(in) {
+		got = append(got, fmt.Sprintf("%v%v", pool.Name, pool.Cells))
+	}
+	want := []string{"replica[zone1 zone2]", "rdonly[zone1 zone2]", "canary[zone1]", "rdonly[zone3]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertTabletPoolsFrom() = %v, want %v", got, want)
+	}
+}
+
+func TestTabletPoolReplicas(t *testing.T) {
+	table := []struct {
+		replicas []int32
+		want     VitessShardTabletPool
+	}{
+		{[]int32{2, 2, 2}, VitessShardTabletPool{Replicas: 2}},
+		{[]int32{3, 3, 2}, VitessShardTabletPool{TotalReplicas: pointer.Int32(8)}},
+		{[]int32{1, 3, 1}, VitessShardTabletPool{Replicas: 1, CellReplicas: map[string]int32{"b": 3}}},
+	}
+	for _, test := range table {
+		cells := []string{"a", "b", "c"}
+		test.want.Cells = cells
+
+		pool := VitessShardTabletPool{Cells: cells}
+		setTabletPoolReplicas(&pool, test.replicas)
+		if !reflect.DeepEqual(pool, test.want) {
+			t.Errorf("setTabletPoolReplicas(%v) = %+v; want %+v", test.replicas, pool, test.want)
+		}
+		for i, want := range test.replicas {
+			if got := pool.ReplicasInCell(i); got != want {
+				t.Errorf("ReplicasInCell(%v) = %v; want %v", i, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+// ReplicasInCell returns the number of tablets the pool deploys in the cell
+// at the given index in its list of cells.
+func (p *VitessShardTabletPool) ReplicasInCell(cellIndex int) int32 {
+	if replicas, ok := p.CellReplicas[p.Cells[cellIndex]]; ok {
+		return replicas
+	}
+	if p.TotalReplicas == nil {
+		return p.Replicas
+	}
+	return evenReplicas(*p.TotalReplicas, len(p.Cells), cellIndex)
+}
+
+// evenReplicas returns how many of a total number of tablets go to the cell
+// at the given index when they're spread evenly across a number of cells.
+func evenReplicas(total int32, cells, cellIndex int) int32 {
+	replicas := total / int32(cells)
+	if int32(cellIndex) < total%int32(cells) {
+		replicas++
+	}
+	return replicas
+}
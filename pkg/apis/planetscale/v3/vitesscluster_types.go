@@ -0,0 +1,683 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+//
+// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+
+// VitessCluster is the top-level interface for configuring a cluster.
+//
+// The v3 VitessCluster is the same as the v2 one, except that tablet pools
+// are named, and each pool lists the cells it's deployed in. VitessClusters
+// are stored as v2, and the operator's conversion webhook converts them
+// between versions. The v3 version is only served if the conversion
+// webhook is deployed.
+// +kubebuilder:resource:path=vitessclusters,shortName=vt
+// +kubebuilder:unservedversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cells",type=string,JSONPath=`.status.summary.cells`
+// +kubebuilder:printcolumn:name="Primaries",type=string,JSONPath=`.status.summary.primaries`
+// +kubebuilder:printcolumn:name="Tablets",type=string,JSONPath=`.status.summary.tablets`
+// +kubebuilder:printcolumn:name="Pending",type=integer,JSONPath=`.status.summary.pendingChanges`
+// +kubebuilder:printcolumn:name="Oldest-Backup",type=date,JSONPath=`.status.summary.oldestLatestBackupTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type VitessCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VitessClusterSpec                 `json:"spec,omitempty"`
+	Status planetscalev2.VitessClusterStatus `json:"status,omitempty"`
+}
+
+// VitessClusterSpec defines the desired state of VitessCluster.
+type VitessClusterSpec struct {
+	// Images specifies the container images (including version tag) to use
+	// in the cluster.
+	// Default: Let the operator choose.
+	Images planetscalev2.VitessImages `json:"images,omitempty"`
+
+	// ImagePullPolicies specifies the container image pull policies to use for
+	// images defined in the 'images' field.
+	ImagePullPolicies planetscalev2.VitessImagePullPolicies `json:"imagePullPolicies,omitempty"`
+
+	// ImagePullSecrets specifies the image pull secrets to add to all Pods that
+	// use the images defined in the 'images' field.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Backup specifies how to take and store Vitess backups.
+	// This is optional but strongly recommended. In addition to disaster
+	// recovery, Vitess currently depends on backups to support provisioning
+	// of a new tablet in a shard with existing data, as an implementation detail.
+	Backup *planetscalev2.ClusterBackupSpec `json:"backup,omitempty"`
+
+	// GlobalLockserver specifies either a deployed or external lockserver
+	// to be used as the Vitess global topology store.
+	// Default: Deploy an etcd cluster as the global lockserver.
+	GlobalLockserver planetscalev2.LockserverSpec `json:"globalLockserver,omitempty"`
+
+	// Dashboard deploys a set of Vitess Dashboard servers (vtctld) for the Vitess cluster.
+	VitessDashboard *planetscalev2.VitessDashboardSpec `json:"vitessDashboard,omitempty"`
+
+	// VtAdmin deploys a set of Vitess Admin servers for the Vitess cluster.
+	VtAdmin *planetscalev2.VtAdminSpec `json:"vtadmin,omitempty"`
+
+	// Cells is a list of templates for VitessCells to create for this cluster.
+	//
+	// Each VitessCell represents a set of Nodes in a given failure domain,
+	// to which VitessKeyspaces can be deployed. The VitessCell also deploys
+	// cell-local services that any keyspaces deployed there will need.
+	//
+	// This field is required, but it may be set to an empty list: [].
+	// Before removing any cell from this list, you should first ensure
+	// that no keyspaces are set to deploy to this cell.
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Cells []planetscalev2.VitessCellTemplate `json:"cells" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// ExternalCells lists the Vitess cells whose tablets and vtgates are
+	// deployed outside of Kubernetes, like on bare metal, and aren't managed
+	// by the operator.
+	//
+	// External cells are kept in the global lockserver and its cells aliases,
+	// and vtgates watch their tablets, unless a cell's gateway says otherwise.
+	// Their tablets are never removed from the topology, and a keyspace that
+	// still has tablets in them isn't turned down.
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	ExternalCells []planetscalev2.VitessExternalCell `json:"externalCells,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// Keyspaces defines the logical databases to deploy.
+	//
+	// A VitessKeyspace can deploy to multiple VitessCells.
+	//
+	// This field is required, but it may be set to an empty list: [].
+	// Before removing any keyspace from this list, you should first ensure
+	// that it is undeployed from all cells by clearing the keyspace's list
+	// of target cells.
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Keyspaces []VitessKeyspaceTemplate `json:"keyspaces,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// ExtraVitessFlags can optionally be used to pass flags to all Vitess components.
+	// WARNING: Any flags passed here must be flags that can be accepted by vtgate, vtctld, vtorc, and vttablet.
+	// An example use-case would be topo flags.
+	//
+	// All entries must be key-value string pairs of the form "flag": "value". The flag name should
+	// not have any prefix (just "flag", not "-flag"). To set a boolean flag,
+	// set the string value to either "true" or "false".
+	//
+	// Flags that the operator manages itself, such as ports, the cell, the
+	// global topology server, and a tablet's alias, keyspace, shard, and
+	// type, can't be overridden here or in any component's extraFlags.
+	// Such entries are ignored, and a warning event is emitted.
+	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
+
+	// ComponentVitessFlags can optionally be used to pass flags to only one
+	// kind of Vitess component, across the whole cluster. These take
+	// precedence over ExtraVitessFlags, while a component's own extraFlags
+	// (for example, those of a tablet pool) take precedence over these.
+	ComponentVitessFlags *planetscalev2.ComponentVitessFlags `json:"componentVitessFlags,omitempty"`
+
+	// TopologyReconciliation can be used to enable or disable registration or pruning of various vitess components to and from topo records.
+	TopologyReconciliation *planetscalev2.TopoReconcileConfig `json:"topologyReconciliation,omitempty"`
+
+	// UpdateStrategy specifies how components in the Vitess cluster will be updated
+	// when a revision is made to the VitessCluster spec.
+	UpdateStrategy *planetscalev2.VitessClusterUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// Paused stops the operator from making any changes to the cluster.
+	// While paused, the operator won't create, update, or delete any of the
+	// cluster's objects, won't change topology records, and won't perform
+	// reparents, drains, or backups, but it keeps updating status.
+	//
+	// Individual shards can override this with the 'paused' field in their
+	// shard template, to either pause just those shards in an otherwise
+	// unpaused cluster, or keep reconciling them while the rest of the
+	// cluster is paused.
+	// Default: false
+	Paused bool `json:"paused,omitempty"`
+
+	// AdoptExisting lets the operator take ownership of objects, such as
+	// Pods and Services, that already exist under the names the operator
+	// would give them, for example because they were deployed by hand or
+	// with Helm. Without it, such objects are reported as name collisions.
+	//
+	// Adopted objects get the operator's labels, an owner reference, and
+	// the annotation planetscale.com/adopted, but are otherwise left as is
+	// at first. Changes that would require recreating them, like most
+	// changes to a Pod, are then applied as rolling updates according to
+	// the update strategy, rather than right away.
+	//
+	// Topology records with the expected names are always reused. While
+	// this is set, the operator doesn't prune tablet records, since tablets
+	// that haven't been adopted yet may still be serving.
+	// Default: false
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// DryRun makes the operator report the changes it would make to the
+	// cluster, without making any. Each object reports the changes it would
+	// make to its own children in status.dryRunChanges and in events, and
+	// logs the full diffs. Apart from that, it works like 'paused'.
+	//
+	// Since child objects aren't updated during a dry run, changes are
+	// reported one level at a time. For example, a change to a tablet pool
+	// shows up as a change to a VitessKeyspace, and only after the dry run
+	// is turned off as a change to the tablet Pods.
+	// Default: false
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// CommonLabels are added to every object the operator creates for this
+	// cluster, like Pods, Services, PersistentVolumeClaims and the child
+	// VitessCells, VitessKeyspaces and VitessShards, as well as to the Pods
+	// of the Deployments it creates, like vtgate. Use it for labels that all
+	// of the cluster's objects need, like those for cost allocation, instead
+	// of repeating them in every pool's extraLabels.
+	//
+	// Labels that the operator sets itself, or that an object already has
+	// for another reason, like a pool's extraLabels, aren't overridden.
+	// Labels removed from here are removed from the objects too.
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations are added to every object the operator creates for
+	// this cluster, just like commonLabels.
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+
+	// CommonLabelPolicy decides how changes to commonLabels and
+	// commonAnnotations are applied to existing objects.
+	CommonLabelPolicy *planetscalev2.CommonLabelPolicy `json:"commonLabelPolicy,omitempty"`
+
+	// Upgrade can optionally be set to have the operator roll out changes to
+	// the 'images' field one component at a time, in a safe order, instead
+	// of changing the images of all components at once.
+	//
+	// When a new image is set for several components, the operator changes
+	// the images of the first stage in the upgrade order, waits until all
+	// of that stage's Pods are updated and healthy, and only then moves on
+	// to the next stage. Progress is reported in status.upgrade.
+	//
+	// Staging starts from the images in use when this field is first set,
+	// so set it before changing any images.
+	Upgrade *planetscalev2.VitessClusterUpgradeSpec `json:"upgrade,omitempty"`
+
+	// Standby can optionally be set to run this cluster as a disaster
+	// recovery standby of another VitessCluster, typically in another
+	// region, that writes its backups to the same backup storage.
+	//
+	// While in standby, the tablets restore the source cluster's backups
+	// instead of this cluster's, the operator periodically recreates them
+	// one at a time so they pick up newer backups, and no primary is
+	// elected. The shards report how far behind they are in status.standby.
+	//
+	// To fail over, set 'promoted'. See VitessClusterStandbySpec.
+	Standby *planetscalev2.VitessClusterStandbySpec `json:"standby,omitempty"`
+
+	// Users optionally declares MySQL users that the operator creates, and
+	// keeps up to date, on the primary of every shard, from which they
+	// replicate to the other tablets. Changing a user's password Secret or
+	// grants is applied without restarting anything, and removing a user
+	// from the list drops it.
+	// +listType=map
+	// +listMapKey=name
+	// +listMapKey=host
+	Users []planetscalev2.VitessMySQLUser `json:"users,omitempty"`
+
+	// InternalCredentials can optionally be set to give the MySQL accounts
+	// that Vitess uses internally passwords, and to rotate them. See
+	// VitessInternalCredentialsSpec.
+	//
+	// Default: The internal accounts have no passwords.
+	InternalCredentials *planetscalev2.VitessInternalCredentialsSpec `json:"internalCredentials,omitempty"`
+
+	// GatewayService can optionally be used to customize the global vtgate Service.
+	// Note that per-cell vtgate Services can be customized within each cell
+	// definition.
+	GatewayService *planetscalev2.ServiceOverrides `json:"gatewayService,omitempty"`
+
+	// TabletService can optionally be used to customize the global, headless vttablet Service.
+	TabletService *planetscalev2.ServiceOverrides `json:"tabletService,omitempty"`
+
+	// Observability can optionally be used to integrate the cluster with
+	// external monitoring systems.
+	Observability *planetscalev2.ObservabilitySpec `json:"observability,omitempty"`
+
+	// ExternalDNS can optionally be used to publish stable DNS names for the
+	// vtgate and vtctld Services through external-dns.
+	ExternalDNS *planetscalev2.ExternalDNSConfig `json:"externalDNS,omitempty"`
+
+	// Networking can optionally be used to configure the cluster for
+	// IPv6-only or dual-stack Kubernetes networking.
+	Networking *planetscalev2.NetworkingSpec `json:"networking,omitempty"`
+
+	// NetworkPolicy can optionally be used to restrict incoming traffic to
+	// the cluster's Pods to the flows that Vitess needs.
+	NetworkPolicy *planetscalev2.NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Evacuation can optionally be used to move primaries out of cells that
+	// are degraded, for example because their zone is failing.
+	Evacuation *planetscalev2.EvacuationSpec `json:"evacuation,omitempty"`
+
+	// ResourceDefaults can optionally be used to fill in and bound the
+	// resource requests and limits of every container the operator deploys,
+	// including init and sidecar containers. This works like a LimitRange
+	// for the cluster's Pods, except that it changes the Pods instead of
+	// rejecting them.
+	//
+	// Changing this restarts every component whose containers are affected.
+	ResourceDefaults *planetscalev2.ResourceDefaultsSpec `json:"resourceDefaults,omitempty"`
+
+	// FailoverBuffer configures how vtgates hold on to queries for a shard
+	// primary while it's being replaced, so applications don't see errors
+	// from short failovers.
+	//
+	// Changing this restarts vtgates.
+	FailoverBuffer *planetscalev2.FailoverBufferSpec `json:"failoverBuffer,omitempty"`
+
+	// ResourceRecommendations can optionally be set to compare the resources
+	// of each tablet pool with their actual usage, and report recommended
+	// requests in status.resourceRecommendations. Nothing is changed based on
+	// the recommendations.
+	ResourceRecommendations *planetscalev2.ResourceRecommendationsSpec `json:"resourceRecommendations,omitempty"`
+}
+
+// VitessKeyspaceTemplate contains only the user-specified parts of a VitessKeyspace object.
+type VitessKeyspaceTemplate struct {
+	// Name is the keyspace name as it should be provided to Vitess.
+	// Note that this is different from the VitessKeyspace object's
+	// metadata.name, which is generated by the operator.
+	//
+	// WARNING: DO NOT change the name of a keyspace that was already deployed.
+	// Keyspaces cannot be renamed, so this will be interpreted as an
+	// instruction to delete the old keyspace and create a new one.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=^[A-Za-z0-9]([A-Za-z0-9-_.]*[A-Za-z0-9])?$
+	Name string `json:"name"`
+
+	// DatabaseName is the name to use for the underlying, physical MySQL
+	// database created to hold data for the keyspace.
+	//
+	// This name is mostly hidden from Vitess clients, which should see and use
+	// only the keyspace name as a logical database. However, you may want to
+	// set this to control the name used by clients that bypass Vitess and
+	// connect directly to the underlying MySQL, such as certain DBA tools.
+	//
+	// The default, when the field is either left unset or set to empty string,
+	// is to add a "vt_" prefix to the keyspace name since that has historically
+	// been the default in Vitess itself. However, it's often preferable to set
+	// this to be the same as the keyspace name to reduce confusion.
+	//
+	// Default: Add a "vt_" prefix to the keyspace name.
+	DatabaseName string `json:"databaseName,omitempty"`
+
+	// DurabilityPolicy is the name of the durability policy to use for the
+	// keyspace, which determines which tablets must acknowledge writes with
+	// semi-sync replication before the primary commits them.
+	//
+	// With "semi_sync", each shard needs at least two master-eligible
+	// tablets. With "cross_cell", each shard needs master-eligible tablets in
+	// at least two cells. The operator doesn't apply a policy that the tablet
+	// pools can't satisfy, since the primary would then block all writes.
+	//
+	// Tablets only read the policy when they start up, so changing it rolls
+	// out to tablet Pods like any other change, according to the update
+	// strategy.
+	//
+	// If unspecified, vtop will not set the durability policy.
+	// +kubebuilder:validation:Enum=none;semi_sync;cross_cell
+	DurabilityPolicy string `json:"durabilityPolicy,omitempty"`
+
+	// QueryServing lets you stop certain tablet types from serving queries in
+	// certain cells, for example to drain traffic from a cell before taking it
+	// down for maintenance.
+	//
+	// If unspecified, vtop leaves the tablet controls of the keyspace alone,
+	// so they can be managed with `vtctlclient SetShardTabletControl`.
+	QueryServing *planetscalev2.VitessKeyspaceQueryServing `json:"queryServing,omitempty"`
+
+	// Reshard lets vtop run a Reshard workflow from one set of shards to
+	// another, for example to merge over-provisioned shards "-40" and
+	// "40-80" into "-80", or to split shards.
+	//
+	// Both sets of shards must be defined by partitionings, so the target
+	// shards are deployed before the workflow starts. Once the source shards
+	// were deleted from topology by the complete step, remove their
+	// partitioning to turn down their tablets.
+	//
+	// If unspecified, vtop only reports on resharding workflows that are
+	// run with vtctlclient.
+	Reshard *planetscalev2.VitessKeyspaceReshardSpec `json:"reshard,omitempty"`
+
+	// Materialize lets vtop keep tables of this keyspace up to date with
+	// queries on another keyspace through a Materialize workflow, to
+	// maintain copies or rollups of another keyspace's data without
+	// external ETL.
+	//
+	// The workflow is created once every shard of this keyspace has a
+	// primary, and vtop never deletes it. Changing the tables of an existing
+	// workflow has no effect; use a new workflow name instead.
+	//
+	// If unspecified, vtop doesn't manage Materialize workflows.
+	Materialize *planetscalev2.VitessKeyspaceMaterializeSpec `json:"materialize,omitempty"`
+
+	// LookupVindexes are lookup vindexes that vtop creates on tables of
+	// this keyspace. For each one, vtop runs the CreateLookupVindex
+	// workflow to backfill the lookup table, then externalizes the vindex
+	// once the backfill is done, so the vindex starts being used for
+	// queries.
+	//
+	// Vindexes that already exist in the VSchema, and aren't write-only,
+	// are left alone. vtop never deletes a lookup vindex or its table, so
+	// removing an entry only stops vtop from reporting on it.
+	LookupVindexes []planetscalev2.VitessLookupVindexSpec `json:"lookupVindexes,omitempty"`
+
+	// VitessOrchestrator deploys a set of Vitess Orchestrator (vtorc) servers for the Keyspace.
+	// It is highly recommended that you set disable_active_reparents=true
+	// for the vttablets if enabling vtorc.
+	VitessOrchestrator *planetscalev2.VitessOrchestratorSpec `json:"vitessOrchestrator,omitempty"`
+
+	// Partitionings specify how to divide the keyspace up into shards by
+	// defining the range of keyspace IDs that each shard contains.
+	// For example, you might divide the keyspace into N equal-sized key ranges.
+	//
+	// Note that this is distinct from defining how each row maps to a keyspace ID,
+	// which is done in the VSchema. Partitioning is purely an operational concern
+	// (scaling the infrastructure), while VSchema is an application-level concern
+	// (modeling relationships between data). This separation of concerns allows
+	// resharding to occur generically at the infrastructure level without any
+	// knowledge of the data model.
+	//
+	// Each partitioning must define a set of shards that fully covers the
+	// space of all possible keyspace IDs; there can be no gaps between ranges.
+	// There's usually only one partitioning present at a time, but during
+	// resharding, it's necessary to launch the destination shards alongside
+	// the source shards. When the resharding is complete, the old partitioning
+	// can be removed, which will turn down (undeploy) any unneeded shards.
+	//
+	// If only some shards are being split or joined during resharding,
+	// the shards that aren't changing must be specified in both partitionings,
+	// although the common shards will be shared (only deployed once).
+	// If the per-shard configuration differs, the configuration in the latter
+	// partitioning (in the order listed in this field) will be used.
+	// For this reason, it's recommended to add new partitionings at the end,
+	// and only remove partitionings from the beginning.
+	//
+	// This field is required. An unsharded keyspace may be specified as a
+	// partitioning into 1 part.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=2
+	Partitionings []VitessKeyspacePartitioning `json:"partitionings"`
+
+	// TurndownPolicy specifies what should happen if this keyspace is ever
+	// removed from the VitessCluster spec. By default, removing a keyspace
+	// entry from the VitessCluster spec will NOT actually turn down the
+	// deployed resources, unless it can be verified that the keyspace was
+	// previously set to have 0 total desired tablets across all shards.
+	//
+	// With this default policy (RequireIdle), before removing the keyspace
+	// entry from the spec, you must first edit the keyspace entry to remove
+	// all tablet pools from all shards, and wait for that change to roll out.
+	// If a keyspace entry is removed too soon, the keyspace resources will
+	// remain deployed indefinitely, and the keyspace will be listed in the
+	// orphanedKeyspaces field of VitessCluster status.
+	//
+	// This is a safety mechanism to prevent accidental edits to the cluster
+	// object from having immediate, destructive consequences. If the cluster
+	// spec is only ever edited by automation whose edits you trust to be safe,
+	// you can set the policy to Immediate to skip these checks.
+	//
+	// Even with the Immediate policy, a keyspace that still has tablets is
+	// only turned down if it has no tables, if every shard has a complete
+	// backup from the last 24 hours, or if the VitessKeyspace object has the
+	// annotation planetscale.com/allow-data-loss set to "true".
+	//
+	// Default: RequireIdle
+	// +kubebuilder:validation:Enum=RequireIdle;Immediate
+	TurndownPolicy planetscalev2.VitessKeyspaceTurndownPolicy `json:"turndownPolicy,omitempty"`
+
+	// Annotations can optionally be used to attach custom annotations to the VitessKeyspace object.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ImageOverrides can optionally be used to run this keyspace with
+	// different images than the rest of the cluster, for example to try a
+	// newer version of vttablet or mysqld on one keyspace before upgrading
+	// the whole cluster. Any image that's not set here is inherited from
+	// the cluster-level images.
+	//
+	// Version skew across the cluster should only be temporary. Keyspaces
+	// and shards with overrides are listed in the VitessCluster status.
+	// Overridden images are not subject to spec.upgrade staging.
+	ImageOverrides *planetscalev2.VitessKeyspaceImages `json:"imageOverrides,omitempty"`
+}
+
+// VitessKeyspacePartitioning defines a set of shards by dividing the keyspace into key ranges.
+// Each field is a different method of dividing the keyspace. Only one field should be set on
+// a given partitioning.
+type VitessKeyspacePartitioning struct {
+	// Equal partitioning splits the keyspace into some number of equal parts,
+	// assuming that the keyspace IDs are uniformly distributed, for example
+	// because they're generated by a hash vindex.
+	Equal *VitessKeyspaceEqualPartitioning `json:"equal,omitempty"`
+
+	// Custom partitioning lets you explicitly specify the key range of every shard,
+	// in case you don't want them to be divided equally.
+	Custom *VitessKeyspaceCustomPartitioning `json:"custom,omitempty"`
+}
+
+// VitessKeyspaceEqualPartitioning splits the keyspace into some number of equal parts.
+type VitessKeyspaceEqualPartitioning struct {
+	// Parts is the number of equal parts to split the keyspace into.
+	// If you need shards that are not equal-sized, use custom partitioning instead.
+	//
+	// Note that if the number of parts is not a power of 2, the key ranges will
+	// only be roughly equal in size.
+	//
+	// WARNING: DO NOT change the number of parts in a partitioning after deploying.
+	//          That's effectively deleting the old partitioning and adding a new one,
+	//          which can lead to downtime or data loss. Instead, add an additional
+	//          partitioning with the desired number of parts, perform a resharding
+	//          migration, and then remove the old partitioning.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65536
+	Parts int32 `json:"parts"`
+
+	// ShardTemplate is the configuration used for each equal-sized shard.
+	// If you need shards that don't all share the same configuration,
+	// use custom partitioning instead.
+	ShardTemplate VitessShardTemplate `json:"shardTemplate,omitempty"`
+}
+
+// VitessKeyspaceCustomPartitioning lets you explicitly specify the key range of every shard.
+//
+// The key ranges of the shards must exactly cover the keyspace, with no gaps
+// or overlaps. Any problem with that is reported in the keyspace's status.
+type VitessKeyspaceCustomPartitioning struct {
+	// Shards is a list of explicit shard specifications.
+	// +patchMergeKey=keyRange
+	// +patchStrategy=merge
+	Shards []VitessKeyspaceKeyRangeShard `json:"shards,omitempty" patchStrategy:"merge" patchMergeKey:"keyRange"`
+
+	// EqualShards can optionally be set to generate the key ranges of this
+	// many roughly equal-sized shards, configured with ShardTemplate, so you
+	// don't have to list them all. The operator adds a shard to the Shards
+	// list for every generated key range that's not already there, so
+	// listing a shard with one of those key ranges overrides its
+	// configuration.
+	//
+	// WARNING: DO NOT change EqualShards after deploying, for the same reasons
+	//          you shouldn't change the parts of an equal partitioning.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65536
+	EqualShards int32 `json:"equalShards,omitempty"`
+
+	// ShardTemplate is the configuration used for the shards generated by
+	// EqualShards.
+	ShardTemplate *VitessShardTemplate `json:"shardTemplate,omitempty"`
+}
+
+// VitessKeyspaceKeyRangeShard defines a shard based on a key range.
+type VitessKeyspaceKeyRangeShard struct {
+	// KeyRange is the range of keys that this shard serves.
+	//
+	// WARNING: DO NOT change the key range of a shard after deploying.
+	//          That's effectively deleting the old shard and adding a new one,
+	//          which can lead to downtime or data loss. Instead, add an additional
+	//          partitioning with the desired set of shards, perform a resharding
+	//          migration, and then remove the old partitioning.
+	KeyRange planetscalev2.VitessKeyRange `json:"keyRange"`
+
+	// VitessShardTemplate is the configuration for the shard.
+	VitessShardTemplate `json:",inline"`
+}
+
+// VitessShardTemplate contains only the user-specified parts of a VitessShard object.
+type VitessShardTemplate struct {
+	// TabletPools specify named groups of tablets with a certain tablet type
+	// and a shared configuration template, deployed in one or more cells.
+	//
+	// A cell can be listed in at most one pool with a given name. Pools in
+	// different cells may share a name, which is how the same pool can be
+	// configured differently in some of its cells.
+	// Each shard must have at least one "replica" pool (in at least one cell)
+	// in order to be able to serve.
+	// +listType=atomic
+	TabletPools []VitessShardTabletPool `json:"tabletPools,omitempty"`
+
+	// DatabaseInitScriptSecret optionally specifies the init_db.sql script file to use for this shard.
+	// This SQL script file is executed immediately after bootstrapping an empty database
+	// to set up initial tables and other MySQL-level entities needed by Vitess.
+	//
+	// Default: The operator provides the init_db.sql script of the Vitess
+	// version it's built against, which saves having to keep a copy up to date
+	// across Vitess upgrades.
+	DatabaseInitScriptSecret planetscalev2.SecretSource `json:"databaseInitScriptSecret,omitempty"`
+
+	// DatabaseInitScriptFragments optionally lists SQL scripts to append, in
+	// order, to the init_db.sql script, for example to create extra users and
+	// grants. Fragments must be in Kubernetes Secrets, specified with 'name'
+	// and 'key', and so must DatabaseInitScriptSecret if it's set along with
+	// fragments. The operator renders the combined script into a Secret of
+	// its own.
+	DatabaseInitScriptFragments []planetscalev2.SecretSource `json:"databaseInitScriptFragments,omitempty"`
+
+	// Replication configures Vitess replication settings for the shard.
+	Replication planetscalev2.VitessReplicationSpec `json:"replication,omitempty"`
+
+	// Annotations can optionally be used to attach custom annotations to the VitessShard object.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Paused overrides the 'paused' field of the VitessCluster for this shard.
+	// In the VitessShard object itself, it's always filled in by the parent
+	// controller with the value that's in effect for the shard.
+	// Default: Inherit from the VitessCluster.
+	Paused *bool `json:"paused,omitempty"`
+
+	// ImageOverrides can optionally be used to run this shard with different
+	// images than the rest of its keyspace. Any image that's not set here is
+	// inherited from the keyspace.
+	ImageOverrides *planetscalev2.VitessKeyspaceImages `json:"imageOverrides,omitempty"`
+
+	// RevertToRevision can be set to the name of one of the revisions listed
+	// in the VitessShard's status.revisions to roll the shard's tablets back
+	// to the images and flags they ran in that revision, regardless of those
+	// in the spec. Clear it to roll forward to the spec again.
+	// While it's set, no new revisions are recorded for the shard.
+	// Default: Tablets run the images and flags in the spec.
+	RevertToRevision string `json:"revertToRevision,omitempty"`
+}
+
+// VitessShardTabletPool defines a named pool of tablets with a similar
+// purpose, deployed in one or more cells.
+type VitessShardTabletPool struct {
+	// Name identifies the pool within each of its cells.
+	//
+	// A tablet's identity (and therefore its UID and Pod name) is derived
+	// from its cell, its pool's name and its index within the pool, so pools
+	// can be reordered or added without any tablets being recreated.
+	// Tablets in a pool named after its type keep the identities they had
+	// before pools could be named.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	Name string `json:"name"`
+
+	// Cells are the names of the Vitess cells in which to deploy this pool.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Cells []string `json:"cells"`
+
+	// Type is the type of tablet contained in this tablet pool.
+	//
+	// The allowed types are:
+	//
+	//   * replica - master-eligible tablets that serve transactional (OLTP) workloads
+	//   * rdonly - master-ineligible tablets (can never be promoted to master) that serve batch/analytical (OLAP) workloads
+	//   * externalmaster - tablets pointed at an external, read-write MySQL endpoint
+	//   * externalreplica - tablets pointed at an external, read-only MySQL endpoint that serve transactional (OLTP) workloads
+	//   * externalrdonly - tablets pointed at an external, read-only MySQL endpoint that serve batch/analytical (OLAP) workloads
+	// +kubebuilder:validation:Enum=replica;rdonly;externalmaster;externalreplica;externalrdonly
+	Type planetscalev2.VitessTabletPoolType `json:"type"`
+
+	// Replicas is the number of tablets to deploy in each of the pool's cells,
+	// unless TotalReplicas or CellReplicas say otherwise.
+	// Default: 0
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// TotalReplicas can optionally be set to spread a total number of tablets
+	// evenly across the pool's cells, instead of deploying Replicas tablets
+	// in each of them. If the total isn't divisible by the number of cells,
+	// the cells listed first get one more tablet each.
+	// Default: Deploy Replicas tablets in each cell.
+	// +kubebuilder:validation:Minimum=0
+	TotalReplicas *int32 `json:"totalReplicas,omitempty"`
+
+	// CellReplicas can optionally be used to override the number of tablets
+	// to deploy in specific cells of the pool, by cell name. It takes
+	// precedence over both Replicas and TotalReplicas.
+	//
+	// Note that the apiserver stores pools one cell at a time, so a pool read
+	// back from it may express the same per-cell counts with a different
+	// combination of these fields.
+	CellReplicas map[string]int32 `json:"cellReplicas,omitempty"`
+
+	// VitessShardTabletPoolTemplate configures the tablets in the pool.
+	planetscalev2.VitessShardTabletPoolTemplate `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessClusterList contains a list of VitessCluster
+type VitessClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VitessCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VitessCluster{}, &VitessClusterList{})
+}
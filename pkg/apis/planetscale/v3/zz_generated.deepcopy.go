@@ -0,0 +1,468 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v3
+
+import (
+	"k8s.io/api/core/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCluster) DeepCopyInto(out *VitessCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCluster.
+func (in *VitessCluster) DeepCopy() *VitessCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterList) DeepCopyInto(out *VitessClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterList.
+func (in *VitessClusterList) DeepCopy() *VitessClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterSpec) DeepCopyInto(out *VitessClusterSpec) {
+	*out = *in
+	in.Images.DeepCopyInto(&out.Images)
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(v2.ClusterBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	if in.VitessDashboard != nil {
+		in, out := &in.VitessDashboard, &out.VitessDashboard
+		*out = new(v2.VitessDashboardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VtAdmin != nil {
+		in, out := &in.VtAdmin, &out.VtAdmin
+		*out = new(v2.VtAdminSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]v2.VitessCellTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalCells != nil {
+		in, out := &in.ExternalCells, &out.ExternalCells
+		*out = make([]v2.VitessExternalCell, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make([]VitessKeyspaceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ComponentVitessFlags != nil {
+		in, out := &in.ComponentVitessFlags, &out.ComponentVitessFlags
+		*out = new(v2.ComponentVitessFlags)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(v2.TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(v2.VitessClusterUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonLabelPolicy != nil {
+		in, out := &in.CommonLabelPolicy, &out.CommonLabelPolicy
+		*out = new(v2.CommonLabelPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(v2.VitessClusterUpgradeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(v2.VitessClusterStandbySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]v2.VitessMySQLUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InternalCredentials != nil {
+		in, out := &in.InternalCredentials, &out.InternalCredentials
+		*out = new(v2.VitessInternalCredentialsSpec)
+		**out = **in
+	}
+	if in.GatewayService != nil {
+		in, out := &in.GatewayService, &out.GatewayService
+		*out = new(v2.ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TabletService != nil {
+		in, out := &in.TabletService, &out.TabletService
+		*out = new(v2.ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Observability != nil {
+		in, out := &in.Observability, &out.Observability
+		*out = new(v2.ObservabilitySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(v2.ExternalDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(v2.NetworkingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(v2.NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Evacuation != nil {
+		in, out := &in.Evacuation, &out.Evacuation
+		*out = new(v2.EvacuationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceDefaults != nil {
+		in, out := &in.ResourceDefaults, &out.ResourceDefaults
+		*out = new(v2.ResourceDefaultsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverBuffer != nil {
+		in, out := &in.FailoverBuffer, &out.FailoverBuffer
+		*out = new(v2.FailoverBufferSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = new(v2.ResourceRecommendationsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterSpec.
+func (in *VitessClusterSpec) DeepCopy() *VitessClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceCustomPartitioning) DeepCopyInto(out *VitessKeyspaceCustomPartitioning) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]VitessKeyspaceKeyRangeShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ShardTemplate != nil {
+		in, out := &in.ShardTemplate, &out.ShardTemplate
+		*out = new(VitessShardTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCustomPartitioning.
+func (in *VitessKeyspaceCustomPartitioning) DeepCopy() *VitessKeyspaceCustomPartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceCustomPartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceEqualPartitioning) DeepCopyInto(out *VitessKeyspaceEqualPartitioning) {
+	*out = *in
+	in.ShardTemplate.DeepCopyInto(&out.ShardTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceEqualPartitioning.
+func (in *VitessKeyspaceEqualPartitioning) DeepCopy() *VitessKeyspaceEqualPartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceEqualPartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceKeyRangeShard) DeepCopyInto(out *VitessKeyspaceKeyRangeShard) {
+	*out = *in
+	out.KeyRange = in.KeyRange
+	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceKeyRangeShard.
+func (in *VitessKeyspaceKeyRangeShard) DeepCopy() *VitessKeyspaceKeyRangeShard {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceKeyRangeShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspacePartitioning) DeepCopyInto(out *VitessKeyspacePartitioning) {
+	*out = *in
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = new(VitessKeyspaceEqualPartitioning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(VitessKeyspaceCustomPartitioning)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioning.
+func (in *VitessKeyspacePartitioning) DeepCopy() *VitessKeyspacePartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspacePartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceTemplate) DeepCopyInto(out *VitessKeyspaceTemplate) {
+	*out = *in
+	if in.QueryServing != nil {
+		in, out := &in.QueryServing, &out.QueryServing
+		*out = new(v2.VitessKeyspaceQueryServing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reshard != nil {
+		in, out := &in.Reshard, &out.Reshard
+		*out = new(v2.VitessKeyspaceReshardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Materialize != nil {
+		in, out := &in.Materialize, &out.Materialize
+		*out = new(v2.VitessKeyspaceMaterializeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LookupVindexes != nil {
+		in, out := &in.LookupVindexes, &out.LookupVindexes
+		*out = make([]v2.VitessLookupVindexSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VitessOrchestrator != nil {
+		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
+		*out = new(v2.VitessOrchestratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Partitionings != nil {
+		in, out := &in.Partitionings, &out.Partitionings
+		*out = make([]VitessKeyspacePartitioning, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = new(v2.VitessKeyspaceImages)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceTemplate.
+func (in *VitessKeyspaceTemplate) DeepCopy() *VitessKeyspaceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardTabletPool) DeepCopyInto(out *VitessShardTabletPool) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TotalReplicas != nil {
+		in, out := &in.TotalReplicas, &out.TotalReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CellReplicas != nil {
+		in, out := &in.CellReplicas, &out.CellReplicas
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.VitessShardTabletPoolTemplate.DeepCopyInto(&out.VitessShardTabletPoolTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTabletPool.
+func (in *VitessShardTabletPool) DeepCopy() *VitessShardTabletPool {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardTabletPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardTemplate) DeepCopyInto(out *VitessShardTemplate) {
+	*out = *in
+	if in.TabletPools != nil {
+		in, out := &in.TabletPools, &out.TabletPools
+		*out = make([]VitessShardTabletPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.DatabaseInitScriptSecret = in.DatabaseInitScriptSecret
+	if in.DatabaseInitScriptFragments != nil {
+		in, out := &in.DatabaseInitScriptFragments, &out.DatabaseInitScriptFragments
+		*out = make([]v2.SecretSource, len(*in))
+		copy(*out, *in)
+	}
+	in.Replication.DeepCopyInto(&out.Replication)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = new(v2.VitessKeyspaceImages)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTemplate.
+func (in *VitessShardTemplate) DeepCopy() *VitessShardTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -24,7 +24,6 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
-	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,9 +37,15 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/etcd"
+	"planetscale.dev/vitess-operator/pkg/operator/follower"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/statusupdate"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 )
 
 const (
@@ -69,22 +74,23 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	c := mgr.GetClient()
+	c := follower.Client(mgr.GetClient())
 	scheme := mgr.GetScheme()
-	recorder := mgr.GetEventRecorderFor(controllerName)
+	recorder := follower.Recorder(mgr.GetEventRecorderFor(controllerName))
 
 	return &ReconcileEtcdLockserver{
-		client:     c,
-		scheme:     scheme,
-		recorder:   recorder,
-		reconciler: reconciler.New(c, scheme, recorder),
+		client:       c,
+		scheme:       scheme,
+		recorder:     recorder,
+		reconciler:   reconciler.New(c, scheme, recorder),
+		statusWriter: statusupdate.NewWriter(c, "EtcdLockserver", environment.StatusUpdateInterval()),
 	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{
+	c, err := follower.NewController(controllerName, mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: *maxConcurrentReconciles,
 	})
@@ -117,10 +123,11 @@ var _ reconcile.Reconciler = &ReconcileEtcdLockserver{}
 type ReconcileEtcdLockserver struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client     client.Client
-	scheme     *runtime.Scheme
-	recorder   record.EventRecorder
-	reconciler *reconciler.Reconciler
+	client       client.Client
+	scheme       *runtime.Scheme
+	recorder     record.EventRecorder
+	reconciler   *reconciler.Reconciler
+	statusWriter *statusupdate.Writer
 }
 
 // Reconcile reads that state of the cluster for a EtcdLockserver object and makes changes based on the state read
@@ -128,15 +135,20 @@ type ReconcileEtcdLockserver struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileEtcdLockserver) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+func (r *ReconcileEtcdLockserver) Reconcile(cctx context.Context, request reconcile.Request) (_ reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
 	defer cancel()
 
+	ctx, span := tracing.StartReconcile(ctx, "EtcdLockserver", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	resultBuilder := &results.Builder{}
 	log := log.WithFields(logrus.Fields{
-		"namespace":      request.Namespace,
-		"etcdlockserver": request.Name,
+		"namespace":              request.Namespace,
+		"etcdlockserver":         request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	log.Info("Reconciling EtcdLockserver")
 
 	// Fetch the EtcdLockserver instance.
@@ -152,6 +164,8 @@ func (r *ReconcileEtcdLockserver) Reconcile(cctx context.Context, request reconc
 		// Error reading the object - requeue the request.
 		return resultBuilder.Error(err)
 	}
+	log = log.WithFields(logging.ObjectFields(ls))
+	ctx = logging.NewContext(ctx, log)
 
 	// Materialize defaults.
 	planetscalev2.DefaultEtcdLockserver(ls)
@@ -160,6 +174,9 @@ func (r *ReconcileEtcdLockserver) Reconcile(cctx context.Context, request reconc
 	oldStatus := ls.Status
 	ls.Status = *planetscalev2.NewEtcdLockserverStatus()
 
+	// Replicas that aren't the leader only compute status.
+	ctx = follower.NewContext(ctx)
+
 	// Create/update Services.
 	svcResult, err := r.reconcileServices(ctx, ls)
 	resultBuilder.Merge(svcResult, err)
@@ -172,15 +189,24 @@ func (r *ReconcileEtcdLockserver) Reconcile(cctx context.Context, request reconc
 	pdbResult, err := r.reconcilePodDisruptionBudget(ctx, ls)
 	resultBuilder.Merge(pdbResult, err)
 
+	// Report losing quorum, which can trigger notifications. Write status
+	// right away, so it isn't reported again on the next reconcile.
+	quorumLost := oldStatus.Available == corev1.ConditionTrue && ls.Status.Available == corev1.ConditionFalse
+	if quorumLost {
+		r.recorder.Eventf(ls, corev1.EventTypeWarning, "QuorumLost", "fewer than %v etcd members are ready", etcd.QuorumSize)
+	}
+
 	// Update status if needed.
 	ls.Status.ObservedGeneration = ls.Generation
-	if !apiequality.Semantic.DeepEqual(&ls.Status, &oldStatus) {
-		if err := r.client.Status().Update(ctx, ls); err != nil {
-			if !apierrors.IsConflict(err) {
-				r.recorder.Eventf(ls, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
-			}
-			resultBuilder.Error(err)
+	urgent := oldStatus.ObservedGeneration != ls.Generation || quorumLost
+	if delay, err := r.statusWriter.Update(ctx, ls, &ls.Status, &oldStatus, urgent); err != nil {
+		if !apierrors.IsConflict(err) {
+			r.recorder.Eventf(ls, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
 		}
+		resultBuilder.Error(err)
+	} else if delay > 0 {
+		// Write the latest status once the interval has passed.
+		resultBuilder.RequeueAfter(delay)
 	}
 
 	// Update metrics.
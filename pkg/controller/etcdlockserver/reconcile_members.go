@@ -31,6 +31,7 @@ import (
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 )
 
 func (r *ReconcileEtcdLockserver) reconcileMembers(ctx context.Context, ls *planetscalev2.EtcdLockserver) (reconcile.Result, error) {
@@ -76,6 +77,7 @@ func (r *ReconcileEtcdLockserver) reconcileMembers(ctx context.Context, ls *plan
 
 	// Reconcile member Pods.
 	numPodsReady := 0
+	var memberPods []*corev1.Pod
 	err = r.reconciler.ReconcileObjectSet(ctx, ls, keys, labels, reconciler.Strategy{
 		Kind: &corev1.Pod{},
 
@@ -95,6 +97,10 @@ func (r *ReconcileEtcdLockserver) reconcileMembers(ctx context.Context, ls *plan
 			if podutils.IsPodReady(curObj) {
 				numPodsReady++
 			}
+			if rollout.Scheduled(curObj) {
+				ls.Status.PendingMembers++
+			}
+			memberPods = append(memberPods, curObj)
 		},
 	})
 	if err != nil {
@@ -113,6 +119,13 @@ func (r *ReconcileEtcdLockserver) reconcileMembers(ctx context.Context, ls *plan
 		ls.Status.Available = k8s.ConditionStatus(numPodsReady > 0)
 	}
 
+	// Roll out pending changes to members, if they've been released.
+	if rollout.ComponentReleased(ls, planetscalev2.EtcdComponentName) && numPodsReady == len(keys) {
+		if err := r.releaseNextMember(ctx, ls, memberPods); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
 	return resultBuilder.Result()
 }
 
@@ -162,6 +175,7 @@ func memberSpecs(ls *planetscalev2.EtcdLockserver, parentLabels map[string]strin
 			Annotations:       ls.Spec.Annotations,
 			AdvertisePeerURLs: ls.Spec.AdvertisePeerURLs,
 			Tolerations:       ls.Spec.Tolerations,
+			ResourceDefaults:  ls.Spec.ResourceDefaults,
 		})
 	}
 	return members
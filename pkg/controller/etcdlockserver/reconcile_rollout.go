@@ -0,0 +1,54 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdlockserver
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// releaseNextMember releases scheduled changes to one member Pod at a time,
+// so we never restart more than one member and risk losing quorum.
+// The caller must check that all members are Ready first.
+func (r *ReconcileEtcdLockserver) releaseNextMember(ctx context.Context, ls *planetscalev2.EtcdLockserver, pods []*corev1.Pod) error {
+	for _, pod := range pods {
+		if rollout.Released(pod) {
+			// Wait for the member that was already released to finish.
+			return nil
+		}
+	}
+
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	for _, pod := range pods {
+		if !rollout.Scheduled(pod) {
+			continue
+		}
+		rollout.Release(pod)
+		if err := r.client.Update(ctx, pod); err != nil {
+			r.recorder.Eventf(ls, corev1.EventTypeWarning, "RolloutFailed", "failed to release etcd member Pod %v: %v", pod.Name, err)
+			return err
+		}
+		r.recorder.Eventf(ls, corev1.EventTypeNormal, "RolloutReleased", "released pending changes to etcd member Pod %v", pod.Name)
+		return nil
+	}
+	return nil
+}
@@ -194,6 +194,9 @@ func (r *ReconcileVitessBackupStorage) newSubcontrollerPodSpec(ctx context.Conte
 
 	// Add config for this specific backup storage location.
 	clusterName := vbs.Labels[planetscalev2.ClusterLabel]
+	if vbs.Spec.StorageClusterName != "" {
+		clusterName = vbs.Spec.StorageClusterName
+	}
 	backupFlags := vitessbackup.StorageFlags(&vbs.Spec.Location, clusterName)
 	container.Args = append(container.Args, backupFlags.FormatArgs()...)
 	update.VolumeMounts(&container.VolumeMounts, vitessbackup.StorageVolumeMounts(&vbs.Spec.Location))
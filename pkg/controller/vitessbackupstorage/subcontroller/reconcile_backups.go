@@ -19,11 +19,13 @@ package subcontroller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,10 +41,15 @@ import (
 	_ "vitess.io/vitess/go/vt/mysqlctl/s3backupstorage"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
 )
 
+// volumeUsageWarningPercent is how full a backup volume may get before we
+// start warning about it.
+const volumeUsageWarningPercent = 90
+
 func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs *planetscalev2.VitessBackupStorage) (reconcile.Result, error) {
 	resultBuilder := results.Builder{}
 	clusterName := vbs.Labels[planetscalev2.ClusterLabel]
@@ -97,6 +104,11 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 			r.recorder.Eventf(vbs, corev1.EventTypeWarning, "ListFailed", "failed to list backups for shard %v/%v: %v", keyspaceName, shard.Spec.Name, err)
 			return resultBuilder.Error(err)
 		}
+		backups, err = r.pruneBackups(ctx, vbs, backupStorage, backupDir, backups)
+		if err != nil {
+			r.recorder.Eventf(vbs, corev1.EventTypeWarning, "PruneFailed", "failed to delete expired backups of shard %v/%v: %v", keyspaceName, shard.Spec.Name, err)
+			resultBuilder.Error(err)
+		}
 
 		// Copy parent labels and add shard-specific labels.
 		labels := map[string]string{
@@ -148,7 +160,7 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 			// again since backups don't change after they're completed, except
 			// when they're deleted.
 			if backup := backupHandles[key]; backup != nil {
-				updateBackupStatus(ctx, vb, backup)
+				updateBackupStatus(ctx, vbs, vb, backup)
 			}
 			return vb
 		},
@@ -163,7 +175,7 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 
 			// We haven't seen that this backup was completed yet. Check again.
 			if backup := backupHandles[key]; backup != nil {
-				updateBackupStatus(ctx, vb, backup)
+				updateBackupStatus(ctx, vbs, vb, backup)
 			}
 		},
 	})
@@ -174,7 +186,97 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 	return resultBuilder.Result()
 }
 
-func updateBackupStatus(ctx context.Context, vb *planetscalev2.VitessBackup, backup backupstorage.BackupHandle) {
+// pruneBackups deletes the backups of one shard that have expired according
+// to the location's retention policy, and returns the remaining ones.
+func (r *ReconcileVitessBackupStorage) pruneBackups(ctx context.Context, vbs *planetscalev2.VitessBackupStorage, backupStorage backupstorage.BackupStorage, backupDir string, backups []backupstorage.BackupHandle) ([]backupstorage.BackupHandle, error) {
+	retention := vbs.Spec.Location.Retention
+	// A standby lists the backups of another cluster, which aren't ours to delete.
+	if retention == nil || vbs.Spec.StorageClusterName != "" {
+		return backups, nil
+	}
+
+	startTimes := make([]time.Time, 0, len(backups))
+	for _, backup := range backups {
+		backupTime, _, err := vitessbackup.ParseBackupName(backup.Name())
+		if err != nil {
+			// Don't delete anything if we can't tell the order.
+			return backups, fmt.Errorf("invalid backup name %q: %v", backup.Name(), err)
+		}
+		startTimes = append(startTimes, backupTime)
+	}
+	sort.Sort(backupsByTime{backups, startTimes})
+
+	expired := vitessbackup.ExpiredBackups(startTimes, retention, time.Now(), func(i int) bool {
+		readCtx, cancel := context.WithTimeout(ctx, *requestTimeout)
+		defer cancel()
+		_, err := mysqlctl.GetBackupManifest(readCtx, backups[i])
+		return err == nil
+	})
+	if len(expired) == 0 {
+		return backups, nil
+	}
+
+	deleted := map[int]bool{}
+	for _, i := range expired {
+		backup := backups[i]
+		if err := backupStorage.RemoveBackup(ctx, backupDir, backup.Name()); err != nil {
+			return remainingBackups(backups, deleted), err
+		}
+		r.recorder.Eventf(vbs, corev1.EventTypeNormal, "BackupExpired", "Deleted backup %v/%v, which was past the retention time", backupDir, backup.Name())
+		deleted[i] = true
+		vbs.Status.DeletedBackupCount++
+	}
+	return remainingBackups(backups, deleted), nil
+}
+
+func remainingBackups(backups []backupstorage.BackupHandle, deleted map[int]bool) []backupstorage.BackupHandle {
+	remaining := make([]backupstorage.BackupHandle, 0, len(backups)-len(deleted))
+	for i, backup := range backups {
+		if !deleted[i] {
+			remaining = append(remaining, backup)
+		}
+	}
+	return remaining
+}
+
+// backupsByTime sorts backup handles along with their start times.
+type backupsByTime struct {
+	backups    []backupstorage.BackupHandle
+	startTimes []time.Time
+}
+
+func (b backupsByTime) Len() int { return len(b.backups) }
+func (b backupsByTime) Less(i, j int) bool {
+	return b.startTimes[i].Before(b.startTimes[j])
+}
+func (b backupsByTime) Swap(i, j int) {
+	b.backups[i], b.backups[j] = b.backups[j], b.backups[i]
+	b.startTimes[i], b.startTimes[j] = b.startTimes[j], b.startTimes[i]
+}
+
+// reportVolumeUsage reports the space used on a volume-backed location, and
+// warns when it's running out.
+func (r *ReconcileVitessBackupStorage) reportVolumeUsage(vbs *planetscalev2.VitessBackupStorage) {
+	if !vitessbackup.IsVolume(&vbs.Spec.Location) {
+		return
+	}
+	capacity, used, err := vitessbackup.VolumeUsage()
+	if err != nil || capacity <= 0 {
+		r.recorder.Eventf(vbs, corev1.EventTypeWarning, "VolumeUsageFailed", "failed to get the space used on the backup volume: %v", err)
+		return
+	}
+	status := &planetscalev2.VitessBackupVolumeStatus{
+		Capacity:    resource.NewQuantity(capacity, resource.BinarySI),
+		Used:        resource.NewQuantity(used, resource.BinarySI),
+		UsedPercent: int32(used * 100 / capacity),
+	}
+	vbs.Status.Volume = status
+	if status.UsedPercent >= volumeUsageWarningPercent {
+		r.recorder.Eventf(vbs, corev1.EventTypeWarning, "BackupVolumeAlmostFull", "The backup volume is %v%% full (%v of %v used).", status.UsedPercent, status.Used, status.Capacity)
+	}
+}
+
+func updateBackupStatus(ctx context.Context, vbs *planetscalev2.VitessBackupStorage, vb *planetscalev2.VitessBackup, backup backupstorage.BackupHandle) {
 	// Check if it's complete by looking for the MANIFEST file.
 	// If any errors are encountered, we assume it's not complete yet.
 	readCtx, cancel := context.WithTimeout(ctx, *requestTimeout)
@@ -182,7 +284,7 @@ func updateBackupStatus(ctx context.Context, vb *planetscalev2.VitessBackup, bac
 
 	manifest, err := mysqlctl.GetBackupManifest(readCtx, backup)
 	if err != nil {
-		log.Warningf("Can't get MANIFEST for %v: %v", backup.Name(), err)
+		logging.FromContext(ctx).Warningf("Can't get MANIFEST for %v: %v", backup.Name(), err)
 		return
 	}
 
@@ -194,6 +296,24 @@ func updateBackupStatus(ctx context.Context, vb *planetscalev2.VitessBackup, bac
 	if finishedTime, err := time.Parse(time.RFC3339, manifest.FinishedTime); err == nil {
 		vb.Status.FinishedTime = &metav1.Time{Time: finishedTime}
 	} else {
-		log.Warningf("Can't parse FinishedTime from MANIFEST of backup %v/%v: %v", backup.Directory(), backup.Name(), err)
+		logging.FromContext(ctx).Warningf("Can't parse FinishedTime from MANIFEST of backup %v/%v: %v", backup.Directory(), backup.Name(), err)
+	}
+
+	// We can only add up the size of backups on a volume we have mounted.
+	if vitessbackup.IsVolume(&vbs.Spec.Location) {
+		if size, err := vitessbackup.VolumeBackupSize(storageClusterName(vbs), backup.Directory(), backup.Name()); err == nil {
+			vb.Status.Size = resource.NewQuantity(size, resource.BinarySI)
+		} else {
+			logging.FromContext(ctx).Warningf("Can't get the size of backup %v/%v: %v", backup.Directory(), backup.Name(), err)
+		}
+	}
+}
+
+// storageClusterName returns the cluster name under which the backups are
+// stored in the location.
+func storageClusterName(vbs *planetscalev2.VitessBackupStorage) string {
+	if vbs.Spec.StorageClusterName != "" {
+		return vbs.Spec.StorageClusterName
 	}
+	return vbs.Labels[planetscalev2.ClusterLabel]
 }
@@ -49,10 +49,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 )
 
 const (
@@ -173,9 +176,12 @@ type ReconcileVitessBackupStorage struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileVitessBackupStorage) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	ctx, cancel := context.WithTimeout(cctx, *reconcileTimeout)
+func (r *ReconcileVitessBackupStorage) Reconcile(cctx context.Context, request reconcile.Request) (_ reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), *reconcileTimeout)
 	defer cancel()
+
+	ctx, span := tracing.StartReconcile(ctx, "VitessBackupStorageSubcontroller", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
 	resultBuilder := &results.Builder{}
 
 	// Ignore everything except the one object we care about.
@@ -184,9 +190,11 @@ func (r *ReconcileVitessBackupStorage) Reconcile(cctx context.Context, request r
 	}
 
 	log := log.WithFields(logrus.Fields{
-		"namespace":           request.Namespace,
-		"vitessbackupstorage": request.Name,
+		"namespace":              request.Namespace,
+		"vitessbackupstorage":    request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	log.Info("Reconciling VitessBackupStorage")
 
 	// Fetch the VitessBackupStorage instance.
@@ -202,12 +210,15 @@ func (r *ReconcileVitessBackupStorage) Reconcile(cctx context.Context, request r
 		// Error reading the object - requeue the request.
 		return resultBuilder.Error(err)
 	}
+	log = log.WithFields(logging.ObjectFields(vbs))
+	ctx = logging.NewContext(ctx, log)
 
 	// Reset status, since that's all out of date info that we will recompute now.
 	oldStatus := vbs.Status
 	vbs.Status = *planetscalev2.NewVitessBackupStorageStatus()
 
 	resultBuilder.Merge(r.reconcileBackups(ctx, vbs))
+	r.reportVolumeUsage(vbs)
 
 	// Update status if needed.
 	vbs.Status.ObservedGeneration = vbs.Generation
@@ -35,9 +35,12 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 )
 
 const (
@@ -126,16 +129,21 @@ type ReconcileVitessBackupStorage struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileVitessBackupStorage) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+func (r *ReconcileVitessBackupStorage) Reconcile(cctx context.Context, request reconcile.Request) (_ reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
 	defer cancel()
 
+	ctx, span := tracing.StartReconcile(ctx, "VitessBackupStorage", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	resultBuilder := &results.Builder{}
 
 	log := log.WithFields(logrus.Fields{
-		"namespace":           request.Namespace,
-		"vitessbackupstorage": request.Name,
+		"namespace":              request.Namespace,
+		"vitessbackupstorage":    request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	log.Info("Reconciling VitessBackupStorage")
 
 	// Fetch the VitessBackupStorage instance.
@@ -151,6 +159,8 @@ func (r *ReconcileVitessBackupStorage) Reconcile(cctx context.Context, request r
 		// Error reading the object - requeue the request.
 		return resultBuilder.Error(err)
 	}
+	log = log.WithFields(logging.ObjectFields(vbs))
+	ctx = logging.NewContext(ctx, log)
 
 	resultBuilder.Merge(r.reconcileSubcontroller(ctx, vbs))
 
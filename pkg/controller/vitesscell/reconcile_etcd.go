@@ -25,6 +25,7 @@ import (
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 )
 
 func (r *ReconcileVitessCell) reconcileLocalEtcd(ctx context.Context, vtc *planetscalev2.VitessCell) error {
@@ -52,11 +53,12 @@ func (r *ReconcileVitessCell) reconcileLocalEtcd(ctx context.Context, vtc *plane
 		Kind: &planetscalev2.EtcdLockserver{},
 
 		New: func(key client.ObjectKey) runtime.Object {
-			return lockserver.NewEtcdLockserver(key, vtc.Spec.Lockserver.Etcd, labels, vtc.Spec.Zone)
+			return lockserver.NewEtcdLockserver(key, vtc.Spec.Lockserver.Etcd, labels, vtc.Spec.Zone, vtc.Spec.ResourceDefaults)
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.EtcdLockserver)
-			lockserver.UpdateEtcdLockserver(newObj, vtc.Spec.Lockserver.Etcd, labels, vtc.Spec.Zone)
+			lockserver.UpdateEtcdLockserver(newObj, vtc.Spec.Lockserver.Etcd, labels, vtc.Spec.Zone, vtc.Spec.ResourceDefaults)
+			rollout.InheritReleasedComponents(newObj, vtc)
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			curObj := obj.(*planetscalev2.EtcdLockserver)
@@ -109,7 +109,7 @@ func (r *ReconcileVitessCell) reconcileKeyspaces(ctx context.Context, vtc *plane
 	// We actually know the address of the local lockserver already,
 	// but for now we'll follow the same rule as all Vitess components,
 	// which is to use the global lockserver to find the local ones.
-	ts, err := toposerver.Open(ctx, vtc.Spec.GlobalLockserver)
+	ts, err := toposerver.Open(ctx, r.client, vtc.Namespace, vtc.Spec.GlobalLockserver)
 	if err != nil {
 		r.recorder.Eventf(vtc, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
 		return resultBuilder.RequeueAfter(topoRequeueDelay)
@@ -21,9 +21,16 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/logutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
 )
@@ -40,11 +47,16 @@ const (
 func (r *ReconcileVitessCell) reconcileTopology(ctx context.Context, vtc *planetscalev2.VitessCell, ts *toposerver.Conn, keyspaces []*planetscalev2.VitessKeyspace) (reconcile.Result, error) {
 	resultBuilder := &results.Builder{}
 
-	if *vtc.Spec.TopologyReconciliation.PruneSrvKeyspaces {
+	if *vtc.Spec.TopologyReconciliation.PruneSrvKeyspaces && !reconciler.IsPaused(ctx) {
 		result, err := r.pruneSrvKeyspaces(ctx, vtc, keyspaces, ts)
 		resultBuilder.Merge(result, err)
 	}
 
+	if *vtc.Spec.TopologyReconciliation.RebuildServingGraph && !reconciler.IsPaused(ctx) {
+		result, err := r.rebuildServingGraph(ctx, vtc, keyspaces, ts)
+		resultBuilder.Merge(result, err)
+	}
+
 	return resultBuilder.Result()
 }
 
@@ -82,3 +94,105 @@ func (r *ReconcileVitessCell) pruneSrvKeyspaces(ctx context.Context, vtc *planet
 
 	return resultBuilder.Result()
 }
+
+// rebuildServingGraph rebuilds the serving VSchema of the cell if it's missing,
+// and the serving keyspace record of each keyspace deployed in the cell if it's
+// missing or doesn't match the shards that serve the keyspace's primary.
+// This is what `vtctl RebuildVSchemaGraph` and `vtctl RebuildKeyspaceGraph`
+// would do for the cell.
+func (r *ReconcileVitessCell) rebuildServingGraph(ctx context.Context, vtc *planetscalev2.VitessCell, keyspaces []*planetscalev2.VitessKeyspace, ts *toposerver.Conn) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	cell := vtc.Spec.Name
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+	defer cancel()
+
+	if _, err := ts.GetSrvVSchema(ctx, cell); topo.IsErrType(err, topo.NoNode) {
+		if err := ts.RebuildSrvVSchema(ctx, []string{cell}); err != nil {
+			r.recorder.Eventf(vtc, corev1.EventTypeWarning, "TopoRebuildFailed", "failed to rebuild serving VSchema in cell-local topology: %v", err)
+			resultBuilder.RequeueAfter(topoRequeueDelay)
+		} else {
+			r.recorder.Event(vtc, corev1.EventTypeNormal, "TopoRebuild", "rebuilt serving VSchema in cell-local topology")
+		}
+	} else if err != nil {
+		r.recorder.Eventf(vtc, corev1.EventTypeWarning, "TopoGetFailed", "failed to get serving VSchema from cell-local topology: %v", err)
+		resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	for _, vtk := range keyspaces {
+		keyspace := vtk.Spec.Name
+		stale, err := srvKeyspaceStale(ctx, ts.Server, cell, keyspace)
+		if err != nil {
+			r.recorder.Eventf(vtc, corev1.EventTypeWarning, "TopoGetFailed", "failed to check serving graph of keyspace %s: %v", keyspace, err)
+			resultBuilder.RequeueAfter(topoRequeueDelay)
+			continue
+		}
+		if !stale {
+			continue
+		}
+		if err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts.Server, keyspace, []string{cell}, false /* allowPartial */); err != nil {
+			r.recorder.Eventf(vtc, corev1.EventTypeWarning, "TopoRebuildFailed", "failed to rebuild serving graph of keyspace %s in cell-local topology: %v", keyspace, err)
+			resultBuilder.RequeueAfter(topoRequeueDelay)
+		} else {
+			r.recorder.Eventf(vtc, corev1.EventTypeNormal, "TopoRebuild", "rebuilt serving graph of keyspace %s in cell-local topology", keyspace)
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// srvKeyspaceStale returns whether the serving keyspace record in the cell
+// should be rebuilt, because it's missing or because its primary partition
+// doesn't list the shards whose primaries are serving.
+//
+// Records with tablet controls that disable query service are left alone,
+// since those belong to a migration in progress, which keeps them up to date.
+func srvKeyspaceStale(ctx context.Context, ts *topo.Server, cell, keyspace string) (bool, error) {
+	names, err := ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			// The keyspace hasn't been created yet.
+			return false, nil
+		}
+		return false, err
+	}
+	serving := sets.New[string]()
+	for _, name := range names {
+		shard, err := ts.GetShard(ctx, keyspace, name)
+		if err != nil {
+			if topo.IsErrType(err, topo.NoNode) {
+				continue
+			}
+			return false, err
+		}
+		if shard.IsPrimaryServing {
+			serving.Insert(name)
+		}
+	}
+	if serving.Len() == 0 {
+		// There's nothing to serve yet.
+		return false, nil
+	}
+
+	srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return true, nil
+		}
+		return false, err
+	}
+	for _, partition := range srvKeyspace.GetPartitions() {
+		for _, control := range partition.GetShardTabletControls() {
+			if control.QueryServiceDisabled {
+				return false, nil
+			}
+		}
+	}
+	partition := topoproto.SrvKeyspaceGetPartition(srvKeyspace, topodatapb.TabletType_PRIMARY)
+	listed := sets.New[string]()
+	for _, ref := range partition.GetShardReferences() {
+		listed.Insert(ref.GetName())
+	}
+	return !listed.Equal(serving), nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"context"
+	"testing"
+
+	"vitess.io/vitess/go/vt/logutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topotools"
+)
+
+func TestSrvKeyspaceStale(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("zone1")
+
+	check := func(want bool) {
+		t.Helper()
+		got, err := srvKeyspaceStale(ctx, ts, "zone1", "commerce")
+		if err != nil {
+			t.Fatalf("srvKeyspaceStale() error: %v", err)
+		}
+		if got != want {
+			t.Errorf("srvKeyspaceStale() = %v; want %v", got, want)
+		}
+	}
+	setServing := func(shard string) {
+		t.Helper()
+		if err := ts.CreateShard(ctx, "commerce", shard); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ts.UpdateShardFields(ctx, "commerce", shard, func(si *topo.ShardInfo) error {
+			si.IsPrimaryServing = true
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing to serve before the keyspace exists.
+	check(false)
+	if err := ts.CreateKeyspace(ctx, "commerce", &topodatapb.Keyspace{}); err != nil {
+		t.Fatal(err)
+	}
+	check(false)
+
+	// A serving shard without a serving keyspace record needs a rebuild.
+	setServing("-80")
+	setServing("80-")
+	check(true)
+	if err := topotools.RebuildKeyspace(ctx, logutil.NewMemoryLogger(), ts, "commerce", []string{"zone1"}, false); err != nil {
+		t.Fatal(err)
+	}
+	check(false)
+
+	// So does a partition that doesn't match the serving shards anymore.
+	if err := ts.UpdateSrvKeyspace(ctx, "zone1", "commerce", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+			ServedType:      topodatapb.TabletType_PRIMARY,
+			ShardReferences: []*topodatapb.ShardReference{{Name: "-80"}},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	check(true)
+
+	// Unless a migration is in progress.
+	if err := ts.UpdateSrvKeyspace(ctx, "zone1", "commerce", &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+			ServedType:      topodatapb.TabletType_PRIMARY,
+			ShardReferences: []*topodatapb.ShardReference{{Name: "-80"}},
+			ShardTabletControls: []*topodatapb.ShardTabletControl{
+				{Name: "-80", QueryServiceDisabled: true},
+			},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	check(false)
+}
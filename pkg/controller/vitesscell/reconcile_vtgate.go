@@ -28,10 +28,13 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+	"planetscale.dev/vitess-operator/pkg/operator/externaldns"
+	"planetscale.dev/vitess-operator/pkg/operator/mesh"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/secrets"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
 )
 
@@ -47,10 +50,8 @@ func (m *secretCellsMapper) Map(obj client.Object) []reconcile.Request {
 
 	cellList := &planetscalev2.VitessCellList{}
 	ctx := context.TODO()
-	opts := &client.ListOptions{
-		Namespace: secret.Namespace,
-	}
-	err := m.client.List(ctx, cellList, opts)
+	// Cells in any namespace may deploy vtgate to the namespace of the Secret.
+	err := m.client.List(ctx, cellList)
 	if err != nil {
 		log.WithError(err).Error("failed to list VitessCells; unable to map Secrets to matching VitessCells")
 		return nil
@@ -60,7 +61,7 @@ func (m *secretCellsMapper) Map(obj client.Object) []reconcile.Request {
 	var requests []reconcile.Request
 	for i := range cellList.Items {
 		cell := &cellList.Items[i]
-		if cell.Spec.Gateway.ReloadSecretNames().Has(secretName) {
+		if cell.GatewayNamespace() == secret.Namespace && cell.Spec.Gateway.ReloadSecretNames().Has(secretName) {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: apitypes.NamespacedName{
 					Namespace: cell.Namespace,
@@ -75,27 +76,33 @@ func (m *secretCellsMapper) Map(obj client.Object) []reconcile.Request {
 func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetscalev2.VitessCell) (reconcile.Result, error) {
 	clusterName := vtc.Labels[planetscalev2.ClusterLabel]
 
-	key := client.ObjectKey{Namespace: vtc.Namespace, Name: vtgate.ServiceName(clusterName, vtc.Spec.Name)}
+	namespace := vtc.GatewayNamespace()
+	serviceKey := client.ObjectKey{Namespace: namespace, Name: vtgate.ServiceName(clusterName, vtc.Spec.Name)}
+	deploymentKey := client.ObjectKey{Namespace: namespace, Name: vtgate.DeploymentName(clusterName, vtc.Spec.Name)}
 	labels := map[string]string{
 		planetscalev2.ClusterLabel:   clusterName,
 		planetscalev2.CellLabel:      vtc.Spec.Name,
 		planetscalev2.ComponentLabel: planetscalev2.VtgateComponentName,
 	}
+	hostname := externaldns.VtgateCellHostname(vtc.Spec.ExternalDNS, clusterName, vtc.Namespace, vtc.Spec.Name)
 	resultBuilder := results.Builder{}
 
 	// Reconcile vtgate Service.
-	err := r.reconciler.ReconcileObject(ctx, vtc, key, labels, true, reconciler.Strategy{
+	err := r.reconciler.ReconcileObject(ctx, vtc, serviceKey, labels, true, reconciler.Strategy{
 		Kind: &corev1.Service{},
 
 		New: func(key client.ObjectKey) runtime.Object {
 			svc := vtgate.NewService(key, labels)
 			update.ServiceOverrides(svc, vtc.Spec.Gateway.Service)
+			update.ServiceNetworking(svc, vtc.Spec.Networking)
+			externaldns.UpdateService(svc, vtc.Spec.ExternalDNS, hostname)
 			return svc
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
 			vtgate.UpdateService(svc, labels)
 			update.InPlaceServiceOverrides(svc, vtc.Spec.Gateway.Service)
+			externaldns.UpdateService(svc, vtc.Spec.ExternalDNS, hostname)
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
@@ -108,7 +115,7 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 	}
 
 	reloadSecretNames := vtc.Spec.Gateway.ReloadSecretNames()
-	gatewaySecrets, err := secrets.GetByNames(ctx, r.client, vtc.Namespace, reloadSecretNames)
+	gatewaySecrets, err := secrets.GetByNames(ctx, r.client, namespace, reloadSecretNames)
 	if err != nil {
 		// Record error and return, to avoid generating a Deployment based on incomplete information.
 		return resultBuilder.Error(err)
@@ -118,15 +125,18 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 		"planetscale.com/secret-hash": secrets.ContentHash(gatewaySecrets...),
 	}
 	update.Annotations(&annotations, vtc.Spec.Gateway.Annotations)
+	update.Annotations(&annotations, mesh.Annotations(vtc.Spec.Networking))
 
 	// Merge ExtraVitessFlags and ExtraFlags together into a new map.
-	extraFlags := make(map[string]string)
-	update.StringMap(&extraFlags, vtc.Spec.ExtraVitessFlags)
-	update.StringMap(&extraFlags, vtc.Spec.Gateway.ExtraFlags)
+	// The VitessCluster's ManagedFlagsIgnored condition reports any flags
+	// that are left out.
+	extraFlags, _ := vitess.MergeExtraFlags(vtc.Spec.ComponentVitessFlags.ManagedFlagOverrides(), vtc.Spec.ExtraVitessFlags, vtc.Spec.ComponentVitessFlags.VtgateFlags(), vtc.Spec.Gateway.ExtraFlags)
 
 	// Reconcile vtgate Deployment.
 	spec := &vtgate.Spec{
 		Cell:                          &vtc.Spec,
+		CellsToWatch:                  vtc.Spec.Gateway.CellsToWatch,
+		AllowedTabletTypes:            vtc.Spec.Gateway.AllowedTabletTypes,
 		Labels:                        labels,
 		Replicas:                      *vtc.Spec.Gateway.Replicas,
 		Resources:                     vtc.Spec.Gateway.Resources,
@@ -146,9 +156,7 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 		Lifecycle:                     vtc.Spec.Gateway.Lifecycle,
 		TerminationGracePeriodSeconds: vtc.Spec.Gateway.TerminationGracePeriodSeconds,
 	}
-	key = client.ObjectKey{Namespace: vtc.Namespace, Name: vtgate.DeploymentName(clusterName, vtc.Spec.Name)}
-
-	err = r.reconciler.ReconcileObject(ctx, vtc, key, labels, true, reconciler.Strategy{
+	err = r.reconciler.ReconcileObject(ctx, vtc, deploymentKey, labels, true, reconciler.Strategy{
 		Kind: &appsv1.Deployment{},
 
 		New: func(key client.ObjectKey) runtime.Object {
@@ -171,5 +179,20 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 		resultBuilder.Error(err)
 	}
 
+	// Clean up vtgate objects left behind in other namespaces when the
+	// gateway namespace changes.
+	if namespace != vtc.Namespace {
+		if err := r.reconciler.ReconcileObject(ctx, vtc, client.ObjectKey{Namespace: vtc.Namespace, Name: serviceKey.Name}, labels, false, reconciler.Strategy{Kind: &corev1.Service{}}); err != nil {
+			resultBuilder.Error(err)
+		}
+		if err := r.reconciler.ReconcileObject(ctx, vtc, client.ObjectKey{Namespace: vtc.Namespace, Name: deploymentKey.Name}, labels, false, reconciler.Strategy{Kind: &appsv1.Deployment{}}); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+	keep := []client.ObjectKey{serviceKey, deploymentKey}
+	if err := r.reconciler.ReconcileCrossNamespace(ctx, vtc, keep, crossNamespaceLists()...); err != nil {
+		resultBuilder.Error(err)
+	}
+
 	return resultBuilder.Result()
 }
@@ -25,7 +25,6 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -38,10 +37,15 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/follower"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/statusupdate"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 )
 
 const (
@@ -63,6 +67,15 @@ var watchResources = []client.Object{
 	&planetscalev2.EtcdLockserver{},
 }
 
+// crossNamespaceLists returns lists of the resource types that this controller
+// may create in other namespaces than the VitessCell.
+func crossNamespaceLists() []client.ObjectList {
+	return []client.ObjectList{
+		&corev1.ServiceList{},
+		&appsv1.DeploymentList{},
+	}
+}
+
 // Add creates a new VitessCell Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
@@ -71,23 +84,24 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) *ReconcileVitessCell {
-	c := mgr.GetClient()
+	c := follower.Client(mgr.GetClient())
 	scheme := mgr.GetScheme()
-	recorder := mgr.GetEventRecorderFor(controllerName)
+	recorder := follower.Recorder(mgr.GetEventRecorderFor(controllerName))
 
 	return &ReconcileVitessCell{
-		client:     c,
-		scheme:     scheme,
-		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
-		recorder:   recorder,
-		reconciler: reconciler.New(c, scheme, recorder),
+		client:       c,
+		scheme:       scheme,
+		resync:       resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder:     recorder,
+		reconciler:   reconciler.New(c, scheme, recorder),
+		statusWriter: statusupdate.NewWriter(c, "VitessCell", environment.StatusUpdateInterval()),
 	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileVitessCell) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{
+	c, err := follower.NewController(controllerName, mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: *maxConcurrentReconciles,
 	})
@@ -112,6 +126,15 @@ func add(mgr manager.Manager, r *ReconcileVitessCell) error {
 		}
 	}
 
+	// Resources in other namespaces can't have owner references, so they
+	// point back to the VitessCell with an annotation instead.
+	for _, resource := range []client.Object{&corev1.Service{}, &appsv1.Deployment{}} {
+		err := c.Watch(&source.Kind{Type: resource}, reconciler.EnqueueRequestForCrossNamespaceOwner("VitessCell"))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Watch for changes in VitessKeyspaces, which we don't own, and requeue associated VitessCells.
 	err = c.Watch(&source.Kind{Type: &planetscalev2.VitessKeyspace{}}, handler.EnqueueRequestsFromMapFunc(keyspaceCellsMapper))
 	if err != nil {
@@ -141,11 +164,12 @@ var _ reconcile.Reconciler = &ReconcileVitessCell{}
 type ReconcileVitessCell struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client     client.Client
-	scheme     *runtime.Scheme
-	resync     *resync.Periodic
-	recorder   record.EventRecorder
-	reconciler *reconciler.Reconciler
+	client       client.Client
+	scheme       *runtime.Scheme
+	resync       *resync.Periodic
+	recorder     record.EventRecorder
+	reconciler   *reconciler.Reconciler
+	statusWriter *statusupdate.Writer
 }
 
 // Reconcile reads that state of the cluster for a VitessCell object and makes changes based on the state read
@@ -153,16 +177,21 @@ type ReconcileVitessCell struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileVitessCell) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+func (r *ReconcileVitessCell) Reconcile(cctx context.Context, request reconcile.Request) (_ reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
 	defer cancel()
 
+	ctx, span := tracing.StartReconcile(ctx, "VitessCell", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	resultBuilder := &results.Builder{}
 
 	log := log.WithFields(logrus.Fields{
-		"namespace":  request.Namespace,
-		"vitesscell": request.Name,
+		"namespace":              request.Namespace,
+		"vitesscell":             request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	log.Info("Reconciling VitessCell")
 
 	// Fetch the VitessCell instance
@@ -178,6 +207,8 @@ func (r *ReconcileVitessCell) Reconcile(cctx context.Context, request reconcile.
 		// Error reading the object - requeue the request.
 		return resultBuilder.Error(err)
 	}
+	log = log.WithFields(logging.ObjectFields(vtc))
+	ctx = logging.NewContext(ctx, log)
 
 	// Reset status so it's all based on the latest observed state.
 	oldStatus := vtc.Status
@@ -187,6 +218,30 @@ func (r *ReconcileVitessCell) Reconcile(cctx context.Context, request reconcile.
 	// TODO(enisoc): Use versioned defaults when operator-sdk supports mutating webhooks.
 	planetscalev2.DefaultVitessCell(vtc)
 
+	// While paused, we only compute status.
+	if vtc.Spec.Paused {
+		log.Info("Reconciliation is paused")
+		ctx = reconciler.NewPausedContext(ctx)
+	}
+	if vtc.Spec.AdoptExisting {
+		ctx = reconciler.NewAdoptingContext(ctx)
+	}
+	// Replicas that aren't the leader only compute status.
+	ctx = follower.NewContext(ctx)
+	// In a dry run, we also report what we would have changed.
+	var dryRun *reconciler.DryRun
+	if vtc.Spec.DryRun {
+		log.Info("Reconciling as a dry run")
+		ctx, dryRun = reconciler.NewDryRunContext(ctx)
+	}
+
+	// Kubernetes doesn't garbage collect vtgates in other namespaces, so we
+	// delete them before letting the VitessCell go.
+	if vtc.DeletionTimestamp != nil {
+		log.Info("Cleaning up objects in other namespaces")
+		return resultBuilder.Error(r.reconciler.ReconcileCrossNamespace(ctx, vtc, nil, crossNamespaceLists()...))
+	}
+
 	// Create/update cell-local etcd, if requested.
 	if err := r.reconcileLocalEtcd(ctx, vtc); err != nil {
 		// Record result but continue.
@@ -201,15 +256,21 @@ func (r *ReconcileVitessCell) Reconcile(cctx context.Context, request reconcile.
 	keyspaceResult, err := r.reconcileKeyspaces(ctx, vtc)
 	resultBuilder.Merge(keyspaceResult, err)
 
+	if dryRun != nil {
+		vtc.Status.DryRunChanges = dryRun.Changes()
+	}
+
 	// Update status if needed.
 	vtc.Status.ObservedGeneration = vtc.Generation
-	if !apiequality.Semantic.DeepEqual(&vtc.Status, &oldStatus) {
-		if err := r.client.Status().Update(ctx, vtc); err != nil {
-			if !apierrors.IsConflict(err) {
-				r.recorder.Eventf(vtc, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
-			}
-			resultBuilder.Error(err)
+	urgent := oldStatus.ObservedGeneration != vtc.Generation
+	if delay, err := r.statusWriter.Update(ctx, vtc, &vtc.Status, &oldStatus, urgent); err != nil {
+		if !apierrors.IsConflict(err) {
+			r.recorder.Eventf(vtc, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
 		}
+		resultBuilder.Error(err)
+	} else if delay > 0 {
+		// Write the latest status once the interval has passed.
+		resultBuilder.RequeueAfter(delay)
 	}
 
 	// Request a periodic resync for the cluster so we can recheck topology even
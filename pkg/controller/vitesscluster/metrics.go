@@ -24,6 +24,9 @@ import (
 
 const (
 	metricsSubsystemName = "cluster"
+
+	kindLabel  = "kind"
+	driftLabel = "drift"
 )
 
 var (
@@ -33,10 +36,17 @@ var (
 		Name:      "reconcile_count",
 		Help:      "Reconciliation attempts for a VitessCluster",
 	}, []string{metrics.ClusterLabel, metrics.ResultLabel})
+	driftedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "drifted_objects",
+		Help:      "Number of objects in a VitessCluster that don't match their desired spec yet",
+	}, []string{metrics.ClusterLabel, kindLabel, driftLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		reconcileCount,
+		driftedObjects,
 	)
 }
@@ -21,6 +21,7 @@ import (
 
 	"planetscale.dev/vitess-operator/pkg/operator/update"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -50,7 +51,7 @@ func (r *ReconcileVitessCluster) reconcileBackupStorage(ctx context.Context, vt
 				Name:      vitessbackup.StorageObjectName(vt.Name, location.Name),
 			}
 			keys = append(keys, key)
-			vbsMap[key] = newVitessBackupStorage(key, labels, location, vt.Spec.Backup.Subcontroller)
+			vbsMap[key] = newVitessBackupStorage(key, labels, location, vt.Spec.Backup.Subcontroller, vt.Spec.Standby)
 		}
 	}
 
@@ -72,7 +73,57 @@ func (r *ReconcileVitessCluster) reconcileBackupStorage(ctx context.Context, vt
 	})
 }
 
-func newVitessBackupStorage(key client.ObjectKey, parentLabels map[string]string, location *planetscalev2.VitessBackupLocation, subcontroller *planetscalev2.VitessBackupSubcontrollerSpec) *planetscalev2.VitessBackupStorage {
+// reconcileBackupVolumeClaims creates the PVCs of VolumeClaim backup locations.
+func (r *ReconcileVitessCluster) reconcileBackupVolumeClaims(ctx context.Context, vt *planetscalev2.VitessCluster) error {
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.BackupVolumeComponentName,
+		planetscalev2.ClusterLabel:   vt.Name,
+	}
+
+	keys := []client.ObjectKey{}
+	locationMap := map[client.ObjectKey]*planetscalev2.VitessBackupLocation{}
+	if vt.Spec.Backup != nil {
+		for i := range vt.Spec.Backup.Locations {
+			location := &vt.Spec.Backup.Locations[i]
+			if location.VolumeClaim == nil {
+				continue
+			}
+			key := client.ObjectKey{Namespace: vt.Namespace, Name: location.VolumeClaim.ClaimName}
+			keys = append(keys, key)
+			locationMap[key] = location
+		}
+	}
+
+	// Copy parent labels and add child-specific labels.
+	claimLabels := func(location *planetscalev2.VitessBackupLocation) map[string]string {
+		l := map[string]string{
+			vitessbackup.LocationLabel: location.Name,
+		}
+		for k, v := range labels {
+			l[k] = v
+		}
+		return l
+	}
+
+	return r.reconciler.ReconcileObjectSet(ctx, vt, keys, labels, reconciler.Strategy{
+		Kind: &corev1.PersistentVolumeClaim{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			location := locationMap[key]
+			return vitessbackup.NewVolumeClaim(key, claimLabels(location), location.VolumeClaim)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			location := locationMap[key]
+			vitessbackup.UpdateVolumeClaimInPlace(obj.(*corev1.PersistentVolumeClaim), claimLabels(location), location.VolumeClaim)
+		},
+		PrepareForTurndown: func(key client.ObjectKey, obj runtime.Object) *planetscalev2.OrphanStatus {
+			// The claim holds backups, so we never delete it on our own.
+			return planetscalev2.NewOrphanStatus("HoldsBackups", "The backup location was removed, but its volume claim may still hold backups. Delete it by hand if they're no longer needed.")
+		},
+	})
+}
+
+func newVitessBackupStorage(key client.ObjectKey, parentLabels map[string]string, location *planetscalev2.VitessBackupLocation, subcontroller *planetscalev2.VitessBackupSubcontrollerSpec, standby *planetscalev2.VitessClusterStandbySpec) *planetscalev2.VitessBackupStorage {
 	// Copy parent labels and add child-specific labels.
 	labels := map[string]string{
 		vitessbackup.LocationLabel: location.Name,
@@ -81,6 +132,12 @@ func newVitessBackupStorage(key client.ObjectKey, parentLabels map[string]string
 		labels[k] = v
 	}
 
+	// A standby lists the backups of its source cluster, which it restores.
+	storageClusterName := ""
+	if standby != nil && !standby.Promoted {
+		storageClusterName = standby.SourceClusterName
+	}
+
 	return &planetscalev2.VitessBackupStorage{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: key.Namespace,
@@ -88,8 +145,9 @@ func newVitessBackupStorage(key client.ObjectKey, parentLabels map[string]string
 			Labels:    labels,
 		},
 		Spec: planetscalev2.VitessBackupStorageSpec{
-			Location:      *location,
-			Subcontroller: subcontroller,
+			Location:           *location,
+			Subcontroller:      subcontroller,
+			StorageClusterName: storageClusterName,
 		},
 	}
 }
@@ -60,11 +60,21 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessCell)
+			rollout.InheritReleasedComponents(newObj, vt)
+			if vt.Status.RolloutCoordinator.CellRollingOut(cellMap[key].Name) {
+				rollout.ReleaseComponent(newObj, planetscalev2.VtgateComponentName)
+			}
 			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
 				updateVitessCell(key, newObj, vt, labels, cellMap[key])
 				return
 			}
 			updateVitessCellInPlace(key, newObj, vt, labels, cellMap[key])
+
+			// The cell controller updates vtgates as soon as the cell itself
+			// is updated, so releasing the cell releases its vtgates.
+			if rollout.ComponentReleased(newObj, planetscalev2.VtgateComponentName) && rollout.Scheduled(newObj) {
+				rollout.Release(newObj)
+			}
 		},
 		UpdateRollingInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessCell)
@@ -74,6 +84,11 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 			}
 			updateVitessCell(key, newObj, vt, labels, cellMap[key])
 		},
+		UpdatePaused: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*planetscalev2.VitessCell)
+			newObj.Spec.Paused = vt.Spec.Paused
+			newObj.Spec.DryRun = vt.Spec.DryRun
+		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			curObj := obj.(*planetscalev2.VitessCell)
 
@@ -81,6 +96,13 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 			status.PendingChanges = curObj.Annotations[rollout.ScheduledAnnotation]
 			status.GatewayAvailable = curObj.Status.Gateway.Available
 			vt.Status.Cells[curObj.Spec.Name] = status
+
+			if status.PendingChanges != "" {
+				vt.Status.AddPendingRollout(planetscalev2.VtgateComponentName, 1)
+			}
+			if etcd := curObj.Status.Lockserver.Etcd; etcd != nil {
+				vt.Status.AddPendingRollout(planetscalev2.EtcdComponentName, etcd.PendingMembers)
+			}
 		},
 		OrphanStatus: func(key client.ObjectKey, obj runtime.Object, orphanStatus *planetscalev2.OrphanStatus) {
 			curObj := obj.(*planetscalev2.VitessCell)
@@ -114,10 +136,7 @@ func newVitessCell(key client.ObjectKey, vt *planetscalev2.VitessCluster, parent
 	planetscalev2.DefaultVitessCellImages(&images, &vt.Spec.Images)
 
 	// Tell each cell what other cells there are.
-	allCells := make([]string, 0, len(vt.Spec.Cells))
-	for i := range vt.Spec.Cells {
-		allCells = append(allCells, vt.Spec.Cells[i].Name)
-	}
+	allCells := vt.Spec.AllCellNames()
 
 	// Copy parent labels map and add cell-specific label.
 	labels := make(map[string]string, len(parentLabels)+1)
@@ -140,7 +159,15 @@ func newVitessCell(key client.ObjectKey, vt *planetscalev2.VitessCluster, parent
 			ImagePullPolicies:      vt.Spec.ImagePullPolicies,
 			ImagePullSecrets:       vt.Spec.ImagePullSecrets,
 			ExtraVitessFlags:       vt.Spec.ExtraVitessFlags,
+			ComponentVitessFlags:   vt.Spec.ComponentVitessFlags,
 			TopologyReconciliation: vt.Spec.TopologyReconciliation,
+			ExternalDNS:            vt.Spec.ExternalDNS,
+			Networking:             vt.Spec.Networking,
+			ResourceDefaults:       vt.Spec.ResourceDefaults,
+			FailoverBuffer:         vt.Spec.FailoverBuffer,
+			Paused:                 vt.Spec.Paused,
+			AdoptExisting:          vt.Spec.AdoptExisting,
+			DryRun:                 vt.Spec.DryRun,
 		},
 	}
 }
@@ -155,6 +182,16 @@ func updateVitessCellInPlace(key client.ObjectKey, vtc *planetscalev2.VitessCell
 	// We allow immediate update of replica counts for stateless workloads,
 	// like Deployment does.
 	vtc.Spec.Gateway.Replicas = newCell.Spec.Gateway.Replicas
+
+	// DNS records don't affect the running vtgates.
+	vtc.Spec.ExternalDNS = newCell.Spec.ExternalDNS
+
+	// Pausing and unpausing should always take effect immediately.
+	vtc.Spec.Paused = newCell.Spec.Paused
+	vtc.Spec.DryRun = newCell.Spec.DryRun
+
+	// Adoption only changes ownership, so it doesn't need to roll out.
+	vtc.Spec.AdoptExisting = newCell.Spec.AdoptExisting
 }
 
 func updateVitessCell(key client.ObjectKey, vtc *planetscalev2.VitessCell, vt *planetscalev2.VitessCluster, parentLabels map[string]string, cell *planetscalev2.VitessCellTemplate) {
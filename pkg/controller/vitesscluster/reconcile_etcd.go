@@ -25,6 +25,7 @@ import (
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 )
 
 func (r *ReconcileVitessCluster) reconcileGlobalEtcd(ctx context.Context, vt *planetscalev2.VitessCluster) error {
@@ -47,11 +48,12 @@ func (r *ReconcileVitessCluster) reconcileGlobalEtcd(ctx context.Context, vt *pl
 		Kind: &planetscalev2.EtcdLockserver{},
 
 		New: func(key client.ObjectKey) runtime.Object {
-			return lockserver.NewEtcdLockserver(key, vt.Spec.GlobalLockserver.Etcd, labels, "")
+			return lockserver.NewEtcdLockserver(key, vt.Spec.GlobalLockserver.Etcd, labels, "", vt.Spec.ResourceDefaults)
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.EtcdLockserver)
-			lockserver.UpdateEtcdLockserver(newObj, vt.Spec.GlobalLockserver.Etcd, labels, "")
+			lockserver.UpdateEtcdLockserver(newObj, vt.Spec.GlobalLockserver.Etcd, labels, "", vt.Spec.ResourceDefaults)
+			rollout.InheritReleasedComponents(newObj, vt)
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			curObj := obj.(*planetscalev2.EtcdLockserver)
@@ -59,6 +61,7 @@ func (r *ReconcileVitessCluster) reconcileGlobalEtcd(ctx context.Context, vt *pl
 			status := curObj.Status
 			status.ObservedGeneration = 0
 			vt.Status.GlobalLockserver.Etcd = &status
+			vt.Status.AddPendingRollout(planetscalev2.EtcdComponentName, status.PendingMembers)
 		},
 		PrepareForTurndown: func(key client.ObjectKey, obj runtime.Object) *planetscalev2.OrphanStatus {
 			// Make sure it's ok to delete this etcd cluster.
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+// updateManagedFlagsCondition reports the extra vtctld and vtgate flags that
+// are left out because the operator manages them. It's a condition rather
+// than an event so it's reported once, rather than on every reconcile.
+func updateManagedFlagsCondition(vt *planetscalev2.VitessCluster) {
+	allowed := vt.Spec.ComponentVitessFlags.ManagedFlagOverrides()
+
+	var problems []string
+	if _, denied := vitess.MergeExtraFlags(allowed, vt.Spec.ExtraVitessFlags, vt.Spec.ComponentVitessFlags.VtctldFlags(), vt.Spec.VitessDashboard.ExtraFlags); len(denied) > 0 {
+		problems = append(problems, fmt.Sprintf("vtctld: %v", strings.Join(denied, ", ")))
+	}
+	for i := range vt.Spec.Cells {
+		cell := &vt.Spec.Cells[i]
+		if _, denied := vitess.MergeExtraFlags(allowed, vt.Spec.ExtraVitessFlags, vt.Spec.ComponentVitessFlags.VtgateFlags(), cell.Gateway.ExtraFlags); len(denied) > 0 {
+			problems = append(problems, fmt.Sprintf("vtgate in cell %v: %v", cell.Name, strings.Join(denied, ", ")))
+		}
+	}
+
+	if len(problems) == 0 {
+		vt.Status.RemoveCondition(planetscalev2.VitessClusterManagedFlagsIgnored)
+		return
+	}
+	vt.Status.SetConditionStatus(planetscalev2.VitessClusterManagedFlagsIgnored, corev1.ConditionTrue, "FlagsIgnored",
+		fmt.Sprintf("Extra flags that the operator manages are left out, unless listed in componentVitessFlags.allowManagedFlags: %v.", strings.Join(problems, "; ")))
+}
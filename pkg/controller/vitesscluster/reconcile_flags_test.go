@@ -0,0 +1,61 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateManagedFlagsCondition(t *testing.T) {
+	vt := &planetscalev2.VitessCluster{
+		Spec: planetscalev2.VitessClusterSpec{
+			ExtraVitessFlags: map[string]string{"topo_global_root": "/other"},
+			VitessDashboard:  &planetscalev2.VitessDashboardSpec{},
+			Cells: []planetscalev2.VitessCellTemplate{
+				{Name: "zone1"},
+				{Name: "zone2", Gateway: planetscalev2.VitessCellGatewaySpec{ExtraFlags: map[string]string{"--mysql_server_port": "3307"}}},
+			},
+		},
+	}
+
+	updateManagedFlagsCondition(vt)
+	cond, ok := vt.Status.GetCondition(planetscalev2.VitessClusterManagedFlagsIgnored)
+	if !ok || cond.Status != corev1.ConditionTrue {
+		t.Fatalf("got condition %v; want status True", cond)
+	}
+	for _, want := range []string{
+		"vtctld: topo_global_root",
+		"vtgate in cell zone1: topo_global_root",
+		"vtgate in cell zone2: mysql_server_port, topo_global_root",
+	} {
+		if !strings.Contains(cond.Message, want) {
+			t.Errorf("condition message %q doesn't contain %q", cond.Message, want)
+		}
+	}
+
+	// Allowing the flags clears the condition.
+	vt.Spec.ComponentVitessFlags = &planetscalev2.ComponentVitessFlags{AllowManagedFlags: []string{"topo_global_root", "mysql-server-port"}}
+	updateManagedFlagsCondition(vt)
+	if cond, ok := vt.Status.GetCondition(planetscalev2.VitessClusterManagedFlagsIgnored); ok {
+		t.Errorf("got condition %v after allowing the flags; want none", cond)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/registry"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// imageResolveRetryInterval is how long to wait before trying again to
+// resolve a tag that we couldn't resolve.
+const imageResolveRetryInterval = time.Minute
+
+// resolveImageDigests replaces image tags in vt.Spec.Images (in memory only)
+// with references pinned to digests, if that was requested.
+//
+// Tags are only resolved the first time we see them. After that, we reuse
+// the digest recorded in status, so a tag that's pushed again doesn't cause
+// some Pods to run a different image than others.
+//
+// If a tag can't be resolved, its field keeps the image it was last pinned
+// to. If it was never pinned, resolveImageDigests returns false, and the
+// caller must hold back changes to children until it's resolved, so no Pod
+// runs the mutable tag.
+func (r *ReconcileVitessCluster) resolveImageDigests(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) (reconcile.Result, bool, error) {
+	resultBuilder := &results.Builder{}
+	images := &vt.Spec.Images
+	if !images.ResolveTagsToDigests {
+		result, err := resultBuilder.Result()
+		return result, true, err
+	}
+
+	fields := []imageField{
+		{"images.vtctld", &images.Vtctld},
+		{"images.vtadmin", &images.Vtadmin},
+		{"images.vtorc", &images.Vtorc},
+		{"images.vtgate", &images.Vtgate},
+		{"images.vttablet", &images.Vttablet},
+		{"images.vtbackup", &images.Vtbackup},
+		{"images.mysqldExporter", &images.MysqldExporter},
+		{"images.init", &images.Init},
+		{"images.debug", &images.Debug},
+	}
+	if images.Mysqld != nil {
+		images.Mysqld = images.Mysqld.DeepCopy()
+		fields = append(fields, mysqldImageFields("images.mysqld", images.Mysqld)...)
+	}
+	for i := range vt.Spec.Keyspaces {
+		keyspace := &vt.Spec.Keyspaces[i]
+		path := fmt.Sprintf("keyspaces[%v]", keyspace.Name)
+		fields = append(fields, keyspaceImageFields(path+".imageOverrides", keyspace.ImageOverrides)...)
+		for j := range keyspace.Partitionings {
+			partitioning := &keyspace.Partitionings[j]
+			path := fmt.Sprintf("%v.partitionings[%d]", path, j)
+			if partitioning.Equal != nil {
+				fields = append(fields, keyspaceImageFields(path+".equal.shardTemplate.imageOverrides", partitioning.Equal.ShardTemplate.ImageOverrides)...)
+			}
+			if partitioning.Custom != nil {
+				for k := range partitioning.Custom.Shards {
+					shard := &partitioning.Custom.Shards[k]
+					fields = append(fields, keyspaceImageFields(fmt.Sprintf("%v.custom.shards[%v].imageOverrides", path, shard.KeyRange.String()), shard.ImageOverrides)...)
+				}
+			}
+		}
+	}
+
+	resolved := true
+	var creds registry.Credentials
+	for _, field := range fields {
+		image := field.image
+		if *image == "" || registry.IsPinned(*image) {
+			continue
+		}
+		pinned := oldStatus.ResolvedImages[*image]
+		if pinned == "" {
+			if creds == nil {
+				creds = r.imagePullCredentials(ctx, vt)
+			}
+			var err error
+			if pinned, err = r.registry.Resolve(ctx, *image, creds); err != nil {
+				resultBuilder.RequeueAfter(imageResolveRetryInterval)
+				last := oldStatus.PinnedImages[field.path]
+				if last == "" {
+					r.recorder.Eventf(vt, corev1.EventTypeWarning, "ImageResolveFailed", "failed to resolve image %v to a digest, holding back changes until it's resolved: %v", *image, err)
+					resolved = false
+					continue
+				}
+				r.recorder.Eventf(vt, corev1.EventTypeWarning, "ImageResolveFailed", "failed to resolve image %v to a digest, keeping %v: %v", *image, last, err)
+				setPinnedImage(vt, field.path, last)
+				*image = last
+				continue
+			}
+			r.recorder.Eventf(vt, corev1.EventTypeNormal, "ImageResolved", "Pinned image %v to %v", *image, pinned)
+		}
+		if vt.Status.ResolvedImages == nil {
+			vt.Status.ResolvedImages = make(map[string]string)
+		}
+		vt.Status.ResolvedImages[*image] = pinned
+		setPinnedImage(vt, field.path, pinned)
+		*image = pinned
+	}
+	result, err := resultBuilder.Result()
+	return result, resolved, err
+}
+
+// imageField is an image in the spec that can be pinned to a digest.
+type imageField struct {
+	// path identifies the field in status.pinnedImages.
+	path  string
+	image *string
+}
+
+func setPinnedImage(vt *planetscalev2.VitessCluster, path, image string) {
+	if vt.Status.PinnedImages == nil {
+		vt.Status.PinnedImages = make(map[string]string)
+	}
+	vt.Status.PinnedImages[path] = image
+}
+
+func keyspaceImageFields(path string, images *planetscalev2.VitessKeyspaceImages) []imageField {
+	if images == nil {
+		return nil
+	}
+	fields := []imageField{
+		{path + ".vttablet", &images.Vttablet},
+		{path + ".vtorc", &images.Vtorc},
+		{path + ".vtbackup", &images.Vtbackup},
+		{path + ".mysqldExporter", &images.MysqldExporter},
+		{path + ".init", &images.Init},
+		{path + ".debug", &images.Debug},
+	}
+	return append(fields, mysqldImageFields(path+".mysqld", images.Mysqld)...)
+}
+
+func mysqldImageFields(path string, image *planetscalev2.MysqldImage) []imageField {
+	if image == nil {
+		return nil
+	}
+	return []imageField{
+		{path + ".mysql56Compatible", &image.Mysql56Compatible},
+		{path + ".mysql80Compatible", &image.Mysql80Compatible},
+		{path + ".mariadbCompatible", &image.MariadbCompatible},
+		{path + ".mariadb103Compatible", &image.Mariadb103Compatible},
+	}
+}
+
+// versionSkew lists the keyspaces and shards whose image overrides make
+// them run different images than the cluster-level ones.
+func versionSkew(vt *planetscalev2.VitessCluster) []planetscalev2.VitessVersionSkew {
+	var skew []planetscalev2.VitessVersionSkew
+
+	clusterImages := planetscalev2.VitessKeyspaceImages{}
+	planetscalev2.DefaultVitessKeyspaceImages(&clusterImages, &vt.Spec.Images)
+
+	for i := range vt.Spec.Keyspaces {
+		keyspace := &vt.Spec.Keyspaces[i]
+		keyspaceImages := planetscalev2.VitessKeyspaceImages{}
+		if keyspace.ImageOverrides != nil {
+			keyspaceImages = *keyspace.ImageOverrides.DeepCopy()
+		}
+		planetscalev2.DefaultVitessKeyspaceImages(&keyspaceImages, &vt.Spec.Images)
+		if diff, skewed := diffKeyspaceImages(&keyspaceImages, &clusterImages); skewed {
+			skew = append(skew, planetscalev2.VitessVersionSkew{Keyspace: keyspace.Name, Images: diff})
+		}
+
+		for _, shard := range keyspace.ShardTemplates() {
+			if shard.ImageOverrides == nil {
+				continue
+			}
+			shardImages := *shard.ImageOverrides.DeepCopy()
+			planetscalev2.DefaultVitessShardImages(&shardImages, &keyspaceImages)
+			if _, skewed := diffKeyspaceImages(&shardImages, &keyspaceImages); !skewed {
+				continue
+			}
+			diff, _ := diffKeyspaceImages(&shardImages, &clusterImages)
+			skew = append(skew, planetscalev2.VitessVersionSkew{Keyspace: keyspace.Name, Shard: shard.KeyRange.String(), Images: diff})
+		}
+	}
+	return skew
+}
+
+// diffKeyspaceImages returns the images in a that differ from those in b,
+// and whether there were any.
+func diffKeyspaceImages(a, b *planetscalev2.VitessKeyspaceImages) (planetscalev2.VitessKeyspaceImages, bool) {
+	diff := planetscalev2.VitessKeyspaceImages{}
+	if a.Vttablet != b.Vttablet {
+		diff.Vttablet = a.Vttablet
+	}
+	if a.Vtorc != b.Vtorc {
+		diff.Vtorc = a.Vtorc
+	}
+	if a.Vtbackup != b.Vtbackup {
+		diff.Vtbackup = a.Vtbackup
+	}
+	if !apiequality.Semantic.DeepEqual(a.Mysqld, b.Mysqld) {
+		diff.Mysqld = a.Mysqld.DeepCopy()
+	}
+	if a.MysqldExporter != b.MysqldExporter {
+		diff.MysqldExporter = a.MysqldExporter
+	}
+	if a.Init != b.Init {
+		diff.Init = a.Init
+	}
+	if a.Debug != b.Debug {
+		diff.Debug = a.Debug
+	}
+	return diff, !apiequality.Semantic.DeepEqual(&diff, &planetscalev2.VitessKeyspaceImages{})
+}
+
+// imagePullCredentials collects registry logins from the cluster's image
+// pull secrets. Secrets that can't be read are skipped, since the image
+// might not need them.
+func (r *ReconcileVitessCluster) imagePullCredentials(ctx context.Context, vt *planetscalev2.VitessCluster) registry.Credentials {
+	creds := registry.Credentials{}
+	for _, ref := range vt.Spec.ImagePullSecrets {
+		secret := &corev1.Secret{}
+		if err := r.client.Get(ctx, client.ObjectKey{Namespace: vt.Namespace, Name: ref.Name}, secret); err != nil {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "ImageResolveFailed", "failed to read image pull secret %v: %v", ref.Name, err)
+			continue
+		}
+		if err := creds.AddSecret(secret); err != nil {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "ImageResolveFailed", "%v", err)
+		}
+	}
+	return creds
+}
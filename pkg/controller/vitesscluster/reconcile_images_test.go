@@ -0,0 +1,169 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/registry"
+)
+
+// unreachableRegistry fails every request, like a registry that's down.
+type unreachableRegistry struct{}
+
+func (unreachableRegistry) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("registry is down")
+}
+
+// TestResolveImageDigests checks which images children get while the
+// registry is down.
+func TestResolveImageDigests(t *testing.T) {
+	const (
+		oldDigest = "vitess/lite@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		newDigest = "vitess/lite@sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+
+	table := []struct {
+		name         string
+		resolved     map[string]string
+		pinned       map[string]string
+		wantVttablet string
+		wantPinned   bool
+		wantRequeue  bool
+	}{
+		{
+			name:         "tag resolved before",
+			resolved:     map[string]string{"vitess/lite:v16": newDigest},
+			pinned:       map[string]string{"images.vttablet": newDigest},
+			wantVttablet: newDigest,
+			wantPinned:   true,
+		},
+		{
+			name:         "new tag keeps the last digest",
+			resolved:     map[string]string{"vitess/lite:v15": oldDigest},
+			pinned:       map[string]string{"images.vttablet": oldDigest},
+			wantVttablet: oldDigest,
+			wantPinned:   true,
+			wantRequeue:  true,
+		},
+		{
+			name:         "never pinned is held back",
+			wantVttablet: "vitess/lite:v16",
+			wantPinned:   false,
+			wantRequeue:  true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vt := &planetscalev2.VitessCluster{
+				Spec: planetscalev2.VitessClusterSpec{
+					Images: planetscalev2.VitessImages{
+						Vttablet:             "vitess/lite:v16",
+						ResolveTagsToDigests: true,
+					},
+				},
+			}
+			oldStatus := &planetscalev2.VitessClusterStatus{
+				ResolvedImages: test.resolved,
+				PinnedImages:   test.pinned,
+			}
+			r := &ReconcileVitessCluster{
+				client:   fake.NewClientBuilder().Build(),
+				recorder: record.NewFakeRecorder(100),
+				registry: registry.NewResolver(&http.Client{Transport: unreachableRegistry{}}),
+			}
+
+			result, pinned, err := r.resolveImageDigests(context.Background(), vt, oldStatus)
+			if err != nil {
+				t.Fatalf("resolveImageDigests() error: %v", err)
+			}
+			if pinned != test.wantPinned {
+				t.Errorf("pinned = %v; want %v", pinned, test.wantPinned)
+			}
+			if got := result.RequeueAfter > 0; got != test.wantRequeue {
+				t.Errorf("requeue = %v; want %v", got, test.wantRequeue)
+			}
+			if got := vt.Spec.Images.Vttablet; got != test.wantVttablet {
+				t.Errorf("vttablet image = %v; want %v", got, test.wantVttablet)
+			}
+			if got, want := vt.Status.PinnedImages["images.vttablet"], test.pinned["images.vttablet"]; got != want {
+				t.Errorf("status.pinnedImages[images.vttablet] = %q; want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestVersionSkew(t *testing.T) {
+	vt := &planetscalev2.VitessCluster{
+		Spec: planetscalev2.VitessClusterSpec{
+			Images: planetscalev2.VitessImages{
+				Vttablet: "vitess/lite:v15",
+				Vtorc:    "vitess/lite:v15",
+			},
+			Keyspaces: []planetscalev2.VitessKeyspaceTemplate{
+				{
+					// Overriding with the same image isn't skew.
+					Name:           "same",
+					ImageOverrides: &planetscalev2.VitessKeyspaceImages{Vttablet: "vitess/lite:v15"},
+				},
+				{
+					Name:           "newer",
+					ImageOverrides: &planetscalev2.VitessKeyspaceImages{Vttablet: "vitess/lite:v16"},
+				},
+				{
+					Name: "canary",
+					Partitionings: []planetscalev2.VitessKeyspacePartitioning{
+						{
+							Custom: &planetscalev2.VitessKeyspaceCustomPartitioning{
+								Shards: []planetscalev2.VitessKeyspaceKeyRangeShard{
+									{
+										KeyRange: planetscalev2.VitessKeyRange{End: "80"},
+										VitessShardTemplate: planetscalev2.VitessShardTemplate{
+											ImageOverrides: &planetscalev2.VitessKeyspaceImages{Vtorc: "vitess/lite:v16"},
+										},
+									},
+									{
+										KeyRange: planetscalev2.VitessKeyRange{Start: "80"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	skew := versionSkew(vt)
+	if len(skew) != 2 {
+		t.Fatalf("versionSkew() = %+v; want 2 entries", skew)
+	}
+	if got := skew[0]; got.Keyspace != "newer" || got.Shard != "" || got.Images.Vttablet != "vitess/lite:v16" || got.Images.Vtorc != "" {
+		t.Errorf("versionSkew()[0] = %+v; want keyspace newer with only vttablet overridden", got)
+	}
+	if got := skew[1]; got.Keyspace != "canary" || got.Shard != "-80" || got.Images.Vtorc != "vitess/lite:v16" || got.Images.Vttablet != "" {
+		t.Errorf("versionSkew()[1] = %+v; want shard canary/-80 with only vtorc overridden", got)
+	}
+}
@@ -18,7 +18,9 @@ package vitesscluster
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +35,10 @@ import (
 	"planetscale.dev/vitess-operator/pkg/operator/vitesskeyspace"
 )
 
+// keyspaceTurndownBackupMaxAge is how recent the backups of every shard must
+// be for a keyspace that still has tables to be turned down.
+const keyspaceTurndownBackupMaxAge = 24 * time.Hour
+
 func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *planetscalev2.VitessCluster) error {
 	labels := map[string]string{
 		planetscalev2.ClusterLabel: vt.Name,
@@ -62,11 +68,22 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessKeyspace)
+			rollout.InheritReleasedComponents(newObj, vt)
+			if vt.Status.RolloutCoordinator.KeyspaceRollingOut(keyspaceMap[key].Name) {
+				rollout.ReleaseComponent(newObj, planetscalev2.VttabletComponentName)
+			}
 			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
 				updateVitessKeyspace(key, newObj, vt, labels, keyspaceMap[key])
 				return
 			}
 			updateVitessKeyspaceInPlace(key, newObj, vt, labels, keyspaceMap[key])
+
+			// The keyspace controller releases changes to shards and tablets
+			// on its own once they're released, but it needs the changes to
+			// the keyspace itself to be released first.
+			if rollout.ComponentReleased(newObj, planetscalev2.VttabletComponentName) && rollout.Scheduled(newObj) {
+				rollout.Release(newObj)
+			}
 		},
 		UpdateRollingInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessKeyspace)
@@ -76,12 +93,17 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 			}
 			updateVitessKeyspace(key, newObj, vt, labels, keyspaceMap[key])
 		},
+		UpdatePaused: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*planetscalev2.VitessKeyspace)
+			updateVitessKeyspacePaused(newObj, newVitessKeyspace(key, vt, labels, keyspaceMap[key]))
+		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			curObj := obj.(*planetscalev2.VitessKeyspace)
 
 			status := vt.Status.Keyspaces[curObj.Spec.Name]
 			status.PendingChanges = curObj.Annotations[rollout.ScheduledAnnotation]
 			status.Shards = int32(len(curObj.Status.Shards))
+			vt.Status.Summary.AddShards(curObj.Status.Shards)
 
 			status.ReadyShards = 0
 			status.UpdatedShards = 0
@@ -110,6 +132,9 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 			}
 			sort.Strings(status.Cells)
 
+			// Count every keyspace, shard and tablet that has pending changes.
+			vt.Status.AddPendingRollout(planetscalev2.VttabletComponentName, keyspacePendingObjects(curObj))
+
 			vt.Status.Keyspaces[curObj.Spec.Name] = status
 		},
 		OrphanStatus: func(key client.ObjectKey, obj runtime.Object, orphanStatus *planetscalev2.OrphanStatus) {
@@ -120,9 +145,10 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 			curObj := obj.(*planetscalev2.VitessKeyspace)
 
 			// Make sure it's ok to delete this keyspace.
-			// The user may specify to skip turndown safety checks.
+			// The user may specify to skip turndown safety checks,
+			// but we still won't silently delete data.
 			if curObj.Spec.TurndownPolicy == planetscalev2.VitessKeyspaceTurndownPolicyImmediate {
-				return nil
+				return keyspaceDataLossRisk(curObj, time.Now())
 			}
 
 			// Otherwise, we err on the safe side since losing a keyspace accidentally is very disruptive.
@@ -132,11 +158,33 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 			}
 			// The keyspace is either not idle (Idle=False),
 			// or we can't be sure whether it's idle (Idle=Unknown).
-			return planetscalev2.NewOrphanStatus("NotIdle", "The keyspace can't be turned down because it's not idle. You must remove all tablet pools before removing the keyspace.")
+			return planetscalev2.NewOrphanStatus("NotIdle", "The keyspace can't be turned down because it's not idle. You must remove all tablet pools, and any tablets in external cells, before removing the keyspace.")
 		},
 	})
 }
 
+// keyspaceDataLossRisk returns an OrphanStatus explaining why turning down a
+// keyspace could lose data, or nil if it can't or the user accepted that.
+func keyspaceDataLossRisk(vtk *planetscalev2.VitessKeyspace, now time.Time) *planetscalev2.OrphanStatus {
+	if vtk.Status.Idle == corev1.ConditionTrue || vtk.Status.HasTables == corev1.ConditionFalse {
+		return nil
+	}
+	if vtk.Annotations[planetscalev2.AllowDataLossAnnotation] == "true" {
+		return nil
+	}
+	backedUp := len(vtk.Status.Shards) > 0
+	for _, shard := range vtk.Status.Shards {
+		if shard.LatestBackupTime == nil || now.Sub(shard.LatestBackupTime.Time) > keyspaceTurndownBackupMaxAge {
+			backedUp = false
+			break
+		}
+	}
+	if backedUp {
+		return nil
+	}
+	return planetscalev2.NewOrphanStatus("DataAtRisk", fmt.Sprintf("The keyspace can't be turned down because it may still have tables, and not every shard has a backup from the last %v. Take a backup, or annotate the VitessKeyspace with %v=true to accept losing its data.", keyspaceTurndownBackupMaxAge, planetscalev2.AllowDataLossAnnotation))
+}
+
 // newVitessKeyspace expands a complete VitessKeyspace from a VitessKeyspaceTemplate.
 //
 // A VitessKeyspace consists of both user-configured parts, which come from VitessKeyspaceTemplate,
@@ -147,6 +195,9 @@ func newVitessKeyspace(key client.ObjectKey, vt *planetscalev2.VitessCluster, pa
 	template := keyspace.DeepCopy()
 
 	images := planetscalev2.VitessKeyspaceImages{}
+	if keyspace.ImageOverrides != nil {
+		images = *keyspace.ImageOverrides.DeepCopy()
+	}
 	planetscalev2.DefaultVitessKeyspaceImages(&images, &vt.Spec.Images)
 
 	// Copy parent labels map and add keyspace-specific label.
@@ -158,9 +209,21 @@ func newVitessKeyspace(key client.ObjectKey, vt *planetscalev2.VitessCluster, pa
 
 	var backupLocations []planetscalev2.VitessBackupLocation
 	var backupEngine planetscalev2.VitessBackupEngine
+	var backupCompression *planetscalev2.BackupCompressionSpec
+	var backupXtrabackup *planetscalev2.XtrabackupSpec
+	var backupStaleThreshold *metav1.Duration
+	var backupDisruptionPolicy *planetscalev2.VitessBackupDisruptionPolicy
+	var maxConcurrentRestores *int32
+	var vtbackup *planetscalev2.VtbackupSpec
 	if vt.Spec.Backup != nil {
 		backupLocations = vt.Spec.Backup.Locations
 		backupEngine = vt.Spec.Backup.Engine
+		backupCompression = vt.Spec.Backup.Compression
+		backupXtrabackup = vt.Spec.Backup.Xtrabackup
+		backupStaleThreshold = vt.Spec.Backup.StaleThreshold
+		backupDisruptionPolicy = vt.Spec.Backup.DisruptionPolicy
+		maxConcurrentRestores = vt.Spec.Backup.MaxConcurrentRestores
+		vtbackup = vt.Spec.Backup.Vtbackup
 	}
 
 	return &planetscalev2.VitessKeyspace{
@@ -179,9 +242,26 @@ func newVitessKeyspace(key client.ObjectKey, vt *planetscalev2.VitessCluster, pa
 			ZoneMap:                vt.Spec.ZoneMap(),
 			BackupLocations:        backupLocations,
 			BackupEngine:           backupEngine,
+			BackupCompression:      backupCompression,
+			BackupXtrabackup:       backupXtrabackup,
+			BackupStaleThreshold:   backupStaleThreshold,
+			BackupDisruptionPolicy: backupDisruptionPolicy,
+			MaxConcurrentRestores:  maxConcurrentRestores,
+			Vtbackup:               vtbackup,
 			ExtraVitessFlags:       vt.Spec.ExtraVitessFlags,
+			ComponentVitessFlags:   vt.Spec.ComponentVitessFlags,
 			TopologyReconciliation: vt.Spec.TopologyReconciliation,
+			Networking:             vt.Spec.Networking,
+			ResourceDefaults:       vt.Spec.ResourceDefaults,
+			FailoverBuffer:         vt.Spec.FailoverBuffer,
+			Evacuation:             vt.Spec.Evacuation,
 			UpdateStrategy:         vt.Spec.UpdateStrategy,
+			Paused:                 vt.Spec.Paused,
+			AdoptExisting:          vt.Spec.AdoptExisting,
+			Standby:                vt.Spec.Standby,
+			Users:                  vt.Spec.Users,
+			InternalCredentials:    vt.Spec.InternalCredentials,
+			DryRun:                 vt.Spec.DryRun,
 		},
 	}
 }
@@ -220,6 +300,37 @@ func updateVitessKeyspaceInPlace(key client.ObjectKey, vtk *planetscalev2.Vitess
 	// partitionings that already exist.
 	update.PartitioningSet(&vtk.Spec.Partitionings, newKeyspace.Spec.Partitionings)
 
+	// Reshard steps are explicit requests that don't restart any Pods, and
+	// the target shards they need are added above, so apply them right away.
+	vtk.Spec.Reshard = newKeyspace.Spec.Reshard
+
+	// Pausing and unpausing should always take effect immediately.
+	updateVitessKeyspacePaused(vtk, newKeyspace)
+
+	// Evacuations respond to outages, so they can't wait for a rollout.
+	vtk.Spec.Evacuation = newKeyspace.Spec.Evacuation
+
+	// Adoption only changes ownership, so it doesn't need to roll out.
+	vtk.Spec.AdoptExisting = newKeyspace.Spec.AdoptExisting
+
+	// The stale backup threshold only affects status.
+	vtk.Spec.BackupStaleThreshold = newKeyspace.Spec.BackupStaleThreshold
+
+	// Users are applied with SQL, so they don't need to roll out.
+	vtk.Spec.Users = newKeyspace.Spec.Users
+
+	// Tablets pick up new internal passwords in a rollout of their own.
+	vtk.Spec.InternalCredentials = newKeyspace.Spec.InternalCredentials
+
+	// The backup disruption policy should apply before anything it guards.
+	vtk.Spec.BackupDisruptionPolicy = newKeyspace.Spec.BackupDisruptionPolicy
+
+	// The restore limit only decides when new Pods are created.
+	vtk.Spec.MaxConcurrentRestores = newKeyspace.Spec.MaxConcurrentRestores
+
+	// Promoting a standby is how a failover starts, so it can't wait either.
+	vtk.Spec.Standby = newKeyspace.Spec.Standby
+
 	// Only update things that are safe to roll out immediately.
 	vtk.Spec.TurndownPolicy = newKeyspace.Spec.TurndownPolicy
 
@@ -227,6 +338,14 @@ func updateVitessKeyspaceInPlace(key client.ObjectKey, vtk *planetscalev2.Vitess
 	updateVitessKeyspaceAnnotations(vtk, newKeyspace)
 }
 
+// updateVitessKeyspacePaused updates only the fields that control whether
+// the keyspace and its shards are paused or in a dry run.
+func updateVitessKeyspacePaused(vtk *planetscalev2.VitessKeyspace, newKeyspace *planetscalev2.VitessKeyspace) {
+	vtk.Spec.Paused = newKeyspace.Spec.Paused
+	vtk.Spec.DryRun = newKeyspace.Spec.DryRun
+	update.KeyspacePaused(&vtk.Spec.VitessKeyspaceTemplate, &newKeyspace.Spec.VitessKeyspaceTemplate)
+}
+
 func updateVitessKeyspaceAnnotations(vtk *planetscalev2.VitessKeyspace, newKeyspace *planetscalev2.VitessKeyspace) {
 	differentAnnotations := differentKeys(vtk.Spec.Annotations, newKeyspace.Spec.Annotations)
 	for _, annotation := range differentAnnotations {
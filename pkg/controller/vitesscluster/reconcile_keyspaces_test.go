@@ -0,0 +1,64 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestKeyspaceDataLossRisk(t *testing.T) {
+	now := time.Now()
+	recent := &metav1.Time{Time: now.Add(-time.Hour)}
+	stale := &metav1.Time{Time: now.Add(-48 * time.Hour)}
+
+	table := []struct {
+		name        string
+		hasTables   corev1.ConditionStatus
+		annotations map[string]string
+		backups     []*metav1.Time
+		blocked     bool
+	}{
+		{"no tables", corev1.ConditionFalse, nil, nil, false},
+		{"unknown tables", corev1.ConditionUnknown, nil, nil, true},
+		{"tables without backups", corev1.ConditionTrue, nil, []*metav1.Time{nil, recent}, true},
+		{"tables with stale backup", corev1.ConditionTrue, nil, []*metav1.Time{recent, stale}, true},
+		{"tables with recent backups", corev1.ConditionTrue, nil, []*metav1.Time{recent, recent}, false},
+		{"data loss allowed", corev1.ConditionTrue, map[string]string{planetscalev2.AllowDataLossAnnotation: "true"}, nil, false},
+		{"data loss not allowed", corev1.ConditionTrue, map[string]string{planetscalev2.AllowDataLossAnnotation: "false"}, nil, true},
+	}
+
+	for _, test := range table {
+		vtk := &planetscalev2.VitessKeyspace{
+			ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+			Status:     planetscalev2.NewVitessKeyspaceStatus(),
+		}
+		vtk.Status.HasTables = test.hasTables
+		vtk.Status.Shards = map[string]planetscalev2.VitessKeyspaceShardStatus{}
+		for i, backup := range test.backups {
+			vtk.Status.Shards[string(rune('a'+i))] = planetscalev2.VitessKeyspaceShardStatus{LatestBackupTime: backup}
+		}
+		if got := keyspaceDataLossRisk(vtk, now); (got != nil) != test.blocked {
+			t.Errorf("%v: keyspaceDataLossRisk() = %v; want blocked: %v", test.name, got, test.blocked)
+		}
+	}
+}
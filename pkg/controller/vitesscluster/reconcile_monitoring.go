@@ -0,0 +1,102 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/etcd"
+	"planetscale.dev/vitess-operator/pkg/operator/monitoring"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// monitoredComponents lists the components we generate PodMonitors for,
+// along with the names of the container ports that serve metrics.
+var monitoredComponents = []struct {
+	name  string
+	ports []string
+}{
+	{name: planetscalev2.VtgateComponentName, ports: []string{planetscalev2.DefaultWebPortName}},
+	{name: planetscalev2.VttabletComponentName, ports: []string{planetscalev2.DefaultWebPortName, vttablet.MysqldExporterPortName}},
+	{name: planetscalev2.VtctldComponentName, ports: []string{planetscalev2.DefaultWebPortName}},
+	{name: planetscalev2.VtorcComponentName, ports: []string{planetscalev2.DefaultWebPortName}},
+	{name: planetscalev2.EtcdComponentName, ports: []string{etcd.ClientPortName}},
+}
+
+func (r *ReconcileVitessCluster) reconcileMonitoring(ctx context.Context, vt *planetscalev2.VitessCluster) error {
+	observability := vt.Spec.Observability
+	// PodMonitors are wanted by default, as long as the CRD is installed.
+	wanted := observability == nil || observability.PrometheusOperator == nil || *observability.PrometheusOperator
+
+	installed, err := monitoring.PodMonitorInstalled(r.client.RESTMapper())
+	if err != nil {
+		return err
+	}
+	if !installed {
+		// If the CRD doesn't exist, there can't be any PodMonitors to clean up.
+		if observability != nil && observability.PrometheusOperator != nil && *observability.PrometheusOperator {
+			r.recorder.Event(vt, corev1.EventTypeWarning, "PodMonitorUnavailable", "spec.observability.prometheusOperator is set, but the PodMonitor CRD is not installed")
+		}
+		return nil
+	}
+
+	for _, component := range monitoredComponents {
+		key := client.ObjectKey{
+			Namespace: vt.Namespace,
+			Name:      monitoring.PodMonitorName(vt.Name, component.name),
+		}
+		labels := map[string]string{
+			planetscalev2.ClusterLabel:   vt.Name,
+			planetscalev2.ComponentLabel: component.name,
+		}
+		spec := &monitoring.PodMonitorSpec{
+			Labels:   map[string]string{},
+			Selector: labels,
+			Ports:    component.ports,
+		}
+		if observability != nil {
+			spec.ScrapeInterval = observability.ScrapeInterval
+			update.Labels(&spec.Labels, observability.MonitorLabels)
+		}
+		// Set our own labels last so they take precedence.
+		update.Labels(&spec.Labels, labels)
+
+		err := r.reconciler.ReconcileObject(ctx, vt, key, labels, wanted, reconciler.Strategy{
+			Kind: monitoring.NewPodMonitorKind(),
+
+			New: func(key client.ObjectKey) runtime.Object {
+				return monitoring.NewPodMonitor(key, spec)
+			},
+			UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+				monitoring.UpdatePodMonitor(obj.(*unstructured.Unstructured), spec)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
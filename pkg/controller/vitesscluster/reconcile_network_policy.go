@@ -0,0 +1,76 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/networkpolicy"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+)
+
+func (r *ReconcileVitessCluster) reconcileNetworkPolicies(ctx context.Context, vt *planetscalev2.VitessCluster) error {
+	wanted := vt.Spec.NetworkPolicy != nil && vt.Spec.NetworkPolicy.Enabled
+
+	// Let in the cluster's vtgates that run in other namespaces.
+	peerNamespaces := sets.NewString()
+	for i := range vt.Spec.Cells {
+		if ns := vt.Spec.Cells[i].Gateway.Namespace; ns != "" && ns != vt.Namespace {
+			peerNamespaces.Insert(ns)
+		}
+	}
+
+	for _, component := range networkpolicy.Components {
+		key := client.ObjectKey{
+			Namespace: vt.Namespace,
+			Name:      networkpolicy.Name(vt.Name, component),
+		}
+		labels := map[string]string{
+			planetscalev2.ClusterLabel:   vt.Name,
+			planetscalev2.ComponentLabel: component,
+		}
+		spec := &networkpolicy.Spec{
+			ClusterName:    vt.Name,
+			ComponentName:  component,
+			Labels:         labels,
+			NetworkPolicy:  vt.Spec.NetworkPolicy,
+			PeerNamespaces: peerNamespaces.List(),
+		}
+
+		err := r.reconciler.ReconcileObject(ctx, vt, key, labels, wanted, reconciler.Strategy{
+			Kind: &networkingv1.NetworkPolicy{},
+
+			New: func(key client.ObjectKey) runtime.Object {
+				return networkpolicy.NewNetworkPolicy(key, spec)
+			},
+			UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+				networkpolicy.UpdateNetworkPolicy(obj.(*networkingv1.NetworkPolicy), spec)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// maxPendingChanges is how many objects we list in status.pendingChanges.
+const maxPendingChanges = 100
+
+// pendingChangeKinds are the kinds of objects in a cluster that get changes
+// scheduled for rollout, listed in the order they're released.
+var pendingChangeKinds = []struct {
+	kind string
+	list func() client.ObjectList
+}{
+	{"VitessCell", func() client.ObjectList { return &planetscalev2.VitessCellList{} }},
+	{"VitessKeyspace", func() client.ObjectList { return &planetscalev2.VitessKeyspaceList{} }},
+	{"VitessShard", func() client.ObjectList { return &planetscalev2.VitessShardList{} }},
+	{"Deployment", func() client.ObjectList { return &appsv1.DeploymentList{} }},
+	{"Pod", func() client.ObjectList { return &corev1.PodList{} }},
+}
+
+// Values of the drift label of the drifted_objects metric. Each object with
+// pending changes is counted under exactly one of them.
+const (
+	// unreleasedDrift is for changes that wait to be released by the
+	// update strategy.
+	unreleasedDrift = "unreleased"
+	// releasedDrift is for changes that were released but not applied yet.
+	releasedDrift = "released"
+	// recreateDrift is for changes to immutable fields, which can only be
+	// applied by recreating the object.
+	recreateDrift = "recreate"
+)
+
+// reconcilePendingChanges lists every object in the cluster with changes
+// scheduled for rollout in status, along with what those changes are, and
+// exports how many there are of each kind in the drifted_objects metric.
+func (r *ReconcileVitessCluster) reconcilePendingChanges(ctx context.Context, vt *planetscalev2.VitessCluster) error {
+	var changes []planetscalev2.VitessClusterPendingChange
+	drift := map[string]map[string]int{}
+	for _, kind := range pendingChangeKinds {
+		list := kind.list()
+		if err := r.client.List(ctx, list, client.InNamespace(vt.Namespace), client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}); err != nil {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "ListFailed", "failed to list %v objects: %v", kind.kind, err)
+			return err
+		}
+		var kindChanges []planetscalev2.VitessClusterPendingChange
+		err := meta.EachListItem(list, func(obj runtime.Object) error {
+			objMeta, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			if !rollout.Scheduled(objMeta) {
+				return nil
+			}
+			reason := rollout.RecreateReason(objMeta)
+			kindChanges = append(kindChanges, planetscalev2.VitessClusterPendingChange{
+				Kind:           kind.kind,
+				Name:           objMeta.GetName(),
+				Fields:         rollout.ChangedFields(objMeta.GetAnnotations()[rollout.ScheduledAnnotation]),
+				Recreate:       reason != "",
+				RecreateReason: reason,
+				Released:       rollout.Released(objMeta),
+			})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		sort.Slice(kindChanges, func(i, j int) bool { return kindChanges[i].Name < kindChanges[j].Name })
+		changes = append(changes, kindChanges...)
+		drift[kind.kind] = countDrift(kindChanges)
+	}
+	reportDriftMetrics(vt.Name, drift)
+
+	if len(changes) > maxPendingChanges {
+		changes = changes[:maxPendingChanges]
+	}
+	vt.Status.PendingChanges = changes
+	return nil
+}
+
+// countDrift counts pending changes by the value of their drift label.
+func countDrift(changes []planetscalev2.VitessClusterPendingChange) map[string]int {
+	counts := map[string]int{}
+	for i := range changes {
+		switch change := &changes[i]; {
+		case change.Recreate:
+			counts[recreateDrift]++
+		case change.Released:
+			counts[releasedDrift]++
+		default:
+			counts[unreleasedDrift]++
+		}
+	}
+	return counts
+}
+
+// reportDriftMetrics exports the number of drifted objects in a cluster by
+// kind, including zeroes so alerts can tell "no drift" from "no data".
+func reportDriftMetrics(clusterName string, drift map[string]map[string]int) {
+	for kind, counts := range drift {
+		for _, value := range []string{unreleasedDrift, releasedDrift, recreateDrift} {
+			driftedObjects.WithLabelValues(clusterName, kind, value).Set(float64(counts[value]))
+		}
+	}
+}
+
+// forgetDriftMetrics stops exporting drift metrics for a cluster, once it's
+// gone.
+func forgetDriftMetrics(clusterName string) {
+	driftedObjects.DeletePartialMatch(prometheus.Labels{metrics.ClusterLabel: clusterName})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestDriftMetrics(t *testing.T) {
+	changes := []planetscalev2.VitessClusterPendingChange{
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "c", Released: true},
+		{Name: "d", Recreate: true, Released: true},
+	}
+	reportDriftMetrics("example", map[string]map[string]int{
+		"Pod":        countDrift(changes),
+		"Deployment": countDrift(nil),
+	})
+
+	for _, test := range []struct {
+		kind, drift string
+		want        float64
+	}{
+		{"Pod", unreleasedDrift, 2},
+		{"Pod", releasedDrift, 1},
+		{"Pod", recreateDrift, 1},
+		{"Deployment", unreleasedDrift, 0},
+	} {
+		if got := testutil.ToFloat64(driftedObjects.WithLabelValues("example", test.kind, test.drift)); got != test.want {
+			t.Errorf("drifted_objects{kind=%q, drift=%q} = %v; want %v", test.kind, test.drift, got, test.want)
+		}
+	}
+
+	forgetDriftMetrics("example")
+	if got := testutil.CollectAndCount(driftedObjects); got != 0 {
+		t.Errorf("drifted_objects series after forgetDriftMetrics = %v; want 0", got)
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// resourceSampleInterval is how often to read the usage of tablet Pods for
+// resource recommendations.
+const resourceSampleInterval = 5 * time.Minute
+
+// podMetricsListGVK is the kind that the resource metrics API lists the
+// usage of Pods with. We read it as unstructured, since we only need a few
+// of its fields.
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// recommendedResources are the resources that recommendations are made for.
+var recommendedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+/*
+reconcileResourceRecommendations samples the usage of tablet Pods, if
+resource recommendations were requested, and reports the peak usage and
+recommended requests of each tablet pool in status.
+
+Peak usage is carried over from one reconcile to the next in status, and
+starts over once the window has passed. Nothing is changed based on the
+recommendations.
+*/
+func (r *ReconcileVitessCluster) reconcileResourceRecommendations(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	spec := vt.Spec.ResourceRecommendations
+	if spec == nil {
+		return resultBuilder.Result()
+	}
+
+	now := time.Now()
+	recommendations := oldStatus.ResourceRecommendations.DeepCopy()
+	if recommendations == nil {
+		recommendations = &planetscalev2.VitessClusterResourceRecommendations{}
+	}
+	vt.Status.ResourceRecommendations = recommendations
+	if recommendations.ObservedSince == nil || now.Sub(recommendations.ObservedSince.Time) >= spec.Window.Duration {
+		// Start tracking peak usage over.
+		recommendations.ObservedSince = &metav1.Time{Time: now}
+		recommendations.LastSampleTime = nil
+		recommendations.Pools = nil
+	}
+	if recommendations.LastSampleTime != nil {
+		if wait := resourceSampleInterval - now.Sub(recommendations.LastSampleTime.Time); wait > 0 {
+			resultBuilder.RequeueAfter(wait)
+			return resultBuilder.Result()
+		}
+	}
+	// Sample again later, even if no Kubernetes events come in.
+	resultBuilder.RequeueAfter(resourceSampleInterval)
+
+	pods := &corev1.PodList{}
+	labels := map[string]string{
+		planetscalev2.ClusterLabel:   vt.Name,
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+	}
+	if err := r.client.List(ctx, pods, client.InNamespace(vt.Namespace), client.MatchingLabels(labels)); err != nil {
+		return resultBuilder.Error(err)
+	}
+	usage, err := r.podUsage(ctx, vt.Namespace, labels)
+	if err != nil {
+		// The metrics API might not be installed. Keep what we had.
+		recommendations.Message = fmt.Sprintf("failed to read resource metrics: %v", err)
+		return resultBuilder.Result()
+	}
+	recommendations.Message = ""
+	recommendations.LastSampleTime = &metav1.Time{Time: now}
+	recommendations.Pools = updatePoolRecommendations(recommendations.Pools, pods.Items, usage, *spec.HeadroomPercent)
+	return resultBuilder.Result()
+}
+
+// podUsage returns the current usage of each container of the selected Pods,
+// by Pod name and then container name, from the resource metrics API.
+func (r *ReconcileVitessCluster) podUsage(ctx context.Context, namespace string, labels map[string]string) (map[string]map[string]corev1.ResourceList, error) {
+	// The metrics API can't be watched, so we read it directly.
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	if err := r.apiReader.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]map[string]corev1.ResourceList, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		containerUsage := make(map[string]corev1.ResourceList, len(containers))
+		for _, container := range containers {
+			fields, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(fields, "name")
+			values, _, _ := unstructured.NestedStringMap(fields, "usage")
+			resources := corev1.ResourceList{}
+			for _, resourceName := range recommendedResources {
+				if quantity, err := resource.ParseQuantity(values[string(resourceName)]); err == nil {
+					resources[resourceName] = quantity
+				}
+			}
+			containerUsage[name] = resources
+		}
+		usage[item.GetName()] = containerUsage
+	}
+	return usage, nil
+}
+
+type poolKey struct {
+	keyspace, cell, tabletType, name string
+}
+
+/*
+updatePoolRecommendations returns the recommendations for the pools of the
+given tablet Pods, given the current usage of their containers and the
+recommendations from before, whose peak usage is carried over.
+
+Pools that no longer have any Pods are dropped.
+*/
+func updatePoolRecommendations(prev []planetscalev2.VitessTabletPoolRecommendation, pods []corev1.Pod, usage map[string]map[string]corev1.ResourceList, headroomPercent int32) []planetscalev2.VitessTabletPoolRecommendation {
+	prevPeaks := make(map[poolKey]map[string]corev1.ResourceList, len(prev))
+	for i := range prev {
+		pool := &prev[i]
+		key := poolKey{keyspace: pool.Keyspace, cell: pool.Cell, tabletType: string(pool.Type), name: pool.Name}
+		peaks := make(map[string]corev1.ResourceList, len(pool.Containers))
+		for j := range pool.Containers {
+			peaks[pool.Containers[j].Name] = pool.Containers[j].PeakUsage
+		}
+		prevPeaks[key] = peaks
+	}
+
+	pools := map[poolKey]map[string]*planetscalev2.VitessContainerRecommendation{}
+	for i := range pods {
+		pod := &pods[i]
+		key := poolKey{
+			keyspace:   pod.Labels[planetscalev2.KeyspaceLabel],
+			cell:       pod.Labels[planetscalev2.CellLabel],
+			tabletType: pod.Labels[planetscalev2.TabletTypeLabel],
+			name:       pod.Labels[planetscalev2.TabletPoolLabel],
+		}
+		containers := pools[key]
+		if containers == nil {
+			containers = map[string]*planetscalev2.VitessContainerRecommendation{}
+			pools[key] = containers
+		}
+		for j := range pod.Spec.Containers {
+			container := &pod.Spec.Containers[j]
+			rec := containers[container.Name]
+			if rec == nil {
+				rec = &planetscalev2.VitessContainerRecommendation{Name: container.Name}
+				maxResources(&rec.PeakUsage, prevPeaks[key][container.Name])
+				containers[container.Name] = rec
+			}
+			maxResources(&rec.Requests, container.Resources.Requests)
+			maxResources(&rec.Limits, container.Resources.Limits)
+			maxResources(&rec.PeakUsage, usage[pod.Name][container.Name])
+		}
+	}
+
+	result := make([]planetscalev2.VitessTabletPoolRecommendation, 0, len(pools))
+	for key, containers := range pools {
+		pool := planetscalev2.VitessTabletPoolRecommendation{
+			Keyspace: key.keyspace,
+			Cell:     key.cell,
+			Type:     planetscalev2.VitessTabletPoolType(key.tabletType),
+			Name:     key.name,
+		}
+		for _, rec := range containers {
+			rec.RecommendedRequests = withHeadroom(rec.PeakUsage, headroomPercent)
+			pool.Containers = append(pool.Containers, *rec)
+		}
+		sort.Slice(pool.Containers, func(i, j int) bool {
+			return pool.Containers[i].Name < pool.Containers[j].Name
+		})
+		result = append(result, pool)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := &result[i], &result[j]
+		if a.Keyspace != b.Keyspace {
+			return a.Keyspace < b.Keyspace
+		}
+		if a.Cell != b.Cell {
+			return a.Cell < b.Cell
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
+
+// maxResources raises the CPU and memory in dst to those in src, where
+// they're higher.
+func maxResources(dst *corev1.ResourceList, src corev1.ResourceList) {
+	for _, resourceName := range recommendedResources {
+		quantity, ok := src[resourceName]
+		if !ok {
+			continue
+		}
+		if cur, ok := (*dst)[resourceName]; ok && cur.Cmp(quantity) >= 0 {
+			continue
+		}
+		if *dst == nil {
+			*dst = corev1.ResourceList{}
+		}
+		(*dst)[resourceName] = quantity.DeepCopy()
+	}
+}
+
+// withHeadroom returns usage plus the given percentage, rounded up to whole
+// millicores of CPU and mebibytes of memory.
+func withHeadroom(usage corev1.ResourceList, headroomPercent int32) corev1.ResourceList {
+	if len(usage) == 0 {
+		return nil
+	}
+	factor := int64(100 + headroomPercent)
+	result := corev1.ResourceList{}
+	if cpu, ok := usage[corev1.ResourceCPU]; ok {
+		millis := (cpu.MilliValue()*factor + 99) / 100
+		result[corev1.ResourceCPU] = *resource.NewMilliQuantity(millis, resource.DecimalSI)
+	}
+	if memory, ok := usage[corev1.ResourceMemory]; ok {
+		const mebibyte = 1 << 20
+		mebibytes := (memory.Value()*factor/100 + mebibyte - 1) / mebibyte
+		result[corev1.ResourceMemory] = *resource.NewQuantity(mebibytes*mebibyte, resource.BinarySI)
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdatePoolRecommendations(t *testing.T) {
+	resources := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+	pod := func(name, shard, cpu string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					planetscalev2.KeyspaceLabel:   "commerce",
+					planetscalev2.ShardLabel:      shard,
+					planetscalev2.CellLabel:       "zone1",
+					planetscalev2.TabletTypeLabel: "replica",
+				},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "mysqld", Resources: corev1.ResourceRequirements{Requests: resources(cpu, "1Gi")}},
+			}},
+		}
+	}
+	pods := []corev1.Pod{pod("a", "-80", "1"), pod("b", "80-", "2")}
+
+	// The peak is the highest usage of any tablet in the pool, including
+	// the peak from before.
+	prev := []planetscalev2.VitessTabletPoolRecommendation{{
+		Keyspace: "commerce",
+		Cell:     "zone1",
+		Type:     planetscalev2.ReplicaPoolType,
+		Containers: []planetscalev2.VitessContainerRecommendation{
+			{Name: "mysqld", PeakUsage: resources("100m", "800Mi")},
+		},
+	}}
+	usage := map[string]map[string]corev1.ResourceList{
+		"a": {"mysqld": resources("500m", "100Mi")},
+		"b": {"mysqld": resources("250m", "200Mi")},
+	}
+
+	got := updatePoolRecommendations(prev, pods, usage, 20)
+	if len(got) != 1 || len(got[0].Containers) != 1 {
+		t.Fatalf("updatePoolRecommendations() = %v; want one pool with one container", got)
+	}
+	rec := got[0].Containers[0]
+	checks := []struct {
+		name string
+		list corev1.ResourceList
+		want corev1.ResourceList
+	}{
+		{"requests", rec.Requests, resources("2", "1Gi")},
+		{"peak usage", rec.PeakUsage, resources("500m", "800Mi")},
+		{"recommended requests", rec.RecommendedRequests, resources("600m", "960Mi")},
+	}
+	for _, check := range checks {
+		for name, want := range check.want {
+			if got := check.list[name]; got.Cmp(want) != 0 {
+				t.Errorf("%v %v = %v; want %v", check.name, name, got.String(), want.String())
+			}
+		}
+	}
+
+	// Pools without Pods are dropped.
+	if got := updatePoolRecommendations(got, nil, usage, 20); len(got) != 0 {
+		t.Errorf("updatePoolRecommendations() without Pods = %v; want none", got)
+	}
+}
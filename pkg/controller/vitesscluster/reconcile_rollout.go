@@ -0,0 +1,450 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// rolloutCoordinatorPollInterval is how often to recheck the progress of a
+// coordinated rollout. Not all of the objects we check trigger a reconcile
+// of the VitessCluster.
+const rolloutCoordinatorPollInterval = 15 * time.Second
+
+// rolloutTarget is a cell or keyspace whose changes the rollout coordinator
+// may release.
+type rolloutTarget struct {
+	name string
+	// pending indicates that the target has changes waiting to be rolled out.
+	pending bool
+	// waiting explains what a target is still waiting for after its changes
+	// have been rolled out, such as Pods becoming ready.
+	waiting string
+}
+
+/*
+reconcileRolloutCoordinator implements spec.updateStrategy.coordinator. It
+decides which cells and keyspaces may roll out their scheduled changes
+right now, and lists them in status.rolloutCoordinator, which
+reconcileCells and reconcileKeyspaces check to release those changes.
+
+The cells and keyspaces that were rolling out are remembered in the old
+status. They keep their turn until they're done, and any free slots go to
+the next cells or keyspaces with pending changes, in order by name.
+
+While reconciliation is paused, including dry runs and replicas of the
+operator that aren't the leader, nothing is released and no VitessShard is
+updated, so status.rolloutCoordinator is kept as it was. Otherwise, a dry run would see every target as done, since none of
+them would have changed.
+*/
+func (r *ReconcileVitessCluster) reconcileRolloutCoordinator(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	spec := vt.Spec.UpdateStrategy.Coordinator
+	if spec == nil || *vt.Spec.UpdateStrategy.Type != planetscalev2.ExternalVitessClusterUpdateStrategyType {
+		return resultBuilder.Result()
+	}
+	if reconciler.IsPaused(ctx) {
+		vt.Status.RolloutCoordinator = oldStatus.RolloutCoordinator.DeepCopy()
+		return resultBuilder.Result()
+	}
+
+	status := &planetscalev2.VitessClusterRolloutCoordinatorStatus{
+		Phase: planetscalev2.IdleRolloutCoordinatorPhase,
+	}
+	vt.Status.RolloutCoordinator = status
+	prev := oldStatus.RolloutCoordinator
+
+	if err := validateRolloutCoordinatorOrder(spec.Order); err != nil {
+		status.Message = err.Error()
+		r.recorder.Eventf(vt, corev1.EventTypeWarning, "InvalidRolloutOrder", "%v", err)
+		return resultBuilder.Result()
+	}
+
+	// Find the first stage that isn't done yet.
+	var targets []rolloutTarget
+	var rollingOut []string
+	for _, stage := range spec.Order {
+		var err error
+		switch stage {
+		case planetscalev2.CellsRolloutCoordinatorStage:
+			targets, err = r.cellRolloutTargets(ctx, vt)
+			if prev != nil {
+				rollingOut = prev.Cells
+			}
+		case planetscalev2.KeyspacesRolloutCoordinatorStage:
+			targets, err = r.keyspaceRolloutTargets(ctx, vt)
+			if prev != nil {
+				rollingOut = prev.Keyspaces
+			}
+		}
+		if err != nil {
+			return resultBuilder.Error(err)
+		}
+
+		status.TotalObjects = int32(len(targets))
+		status.CompletedObjects = status.TotalObjects
+		for i := range targets {
+			if !rolloutTargetDone(&targets[i], rollingOut) {
+				status.CompletedObjects--
+			}
+		}
+		if status.CompletedObjects < status.TotalObjects {
+			status.Stage = stage
+			break
+		}
+	}
+
+	// A halted rollout stays halted until the spec changes again.
+	if prev != nil && prev.HaltedGeneration == vt.Generation {
+		status.HaltedGeneration = prev.HaltedGeneration
+		status.UnhealthyTablets = prev.UnhealthyTablets
+	}
+	if spec.AutoRollback != nil {
+		if err := r.reconcileAutoRollback(ctx, vt, spec.AutoRollback, status); err != nil {
+			return resultBuilder.Error(err)
+		}
+	}
+
+	if status.HaltedGeneration != 0 {
+		status.Phase = planetscalev2.HaltedRolloutCoordinatorPhase
+		status.Message = fmt.Sprintf("the rollout was halted because updated tablets are unhealthy: %v; change the spec to resume", strings.Join(status.UnhealthyTablets, ", "))
+		if err := r.abortShardRollouts(ctx, vt); err != nil {
+			return resultBuilder.Error(err)
+		}
+		return resultBuilder.RequeueAfter(rolloutCoordinatorPollInterval)
+	}
+	if status.Stage == "" {
+		// There's nothing to roll out.
+		status.TotalObjects = 0
+		status.CompletedObjects = 0
+		return resultBuilder.Result()
+	}
+
+	switch {
+	case spec.Abort:
+		status.Phase = planetscalev2.AbortedRolloutCoordinatorPhase
+		status.Message = "the rollout was aborted"
+		if err := r.abortShardRollouts(ctx, vt); err != nil {
+			return resultBuilder.Error(err)
+		}
+		return resultBuilder.RequeueAfter(rolloutCoordinatorPollInterval)
+	case spec.Paused:
+		status.Phase = planetscalev2.PausedRolloutCoordinatorPhase
+		status.Message = "the rollout was paused"
+		return resultBuilder.Result()
+	}
+	status.Phase = planetscalev2.ProgressingRolloutCoordinatorPhase
+
+	maxConcurrent := *spec.MaxConcurrentCells
+	if status.Stage == planetscalev2.KeyspacesRolloutCoordinatorStage {
+		maxConcurrent = *spec.MaxConcurrentKeyspaces
+	}
+
+	// Targets that were already rolling out keep their turn until they're done.
+	var selected []string
+	var waiting []string
+	for i := range targets {
+		target := &targets[i]
+		if rolloutTargetDone(target, rollingOut) || !containsString(rollingOut, target.name) {
+			continue
+		}
+		selected = append(selected, target.name)
+		if !target.pending && target.waiting != "" {
+			waiting = append(waiting, target.waiting)
+		}
+	}
+	// Fill any free slots with the next targets that have pending changes.
+	for i := range targets {
+		target := &targets[i]
+		if int32(len(selected)) >= maxConcurrent {
+			break
+		}
+		if !target.pending || containsString(selected, target.name) {
+			continue
+		}
+		selected = append(selected, target.name)
+		r.recorder.Eventf(vt, corev1.EventTypeNormal, "RolloutStarted", "Started rolling out changes to %v %v", strings.TrimSuffix(string(status.Stage), "s"), target.name)
+	}
+	sort.Strings(selected)
+
+	switch status.Stage {
+	case planetscalev2.CellsRolloutCoordinatorStage:
+		status.Cells = selected
+	case planetscalev2.KeyspacesRolloutCoordinatorStage:
+		status.Keyspaces = selected
+	}
+	if len(waiting) > 0 {
+		status.Message = "waiting for " + strings.Join(waiting, "; ")
+	} else {
+		status.Message = fmt.Sprintf("rolling out changes to %v: %v", status.Stage, strings.Join(selected, ", "))
+	}
+	return resultBuilder.RequeueAfter(rolloutCoordinatorPollInterval)
+}
+
+// rolloutTargetDone returns whether a target has nothing left to roll out.
+// Targets that were rolling out are only done once they're also healthy.
+func rolloutTargetDone(target *rolloutTarget, rollingOut []string) bool {
+	if target.pending {
+		return false
+	}
+	return target.waiting == "" || !containsString(rollingOut, target.name)
+}
+
+// cellRolloutTargets lists the cells of the cluster, in order by name, along
+// with whether their vtgates have been updated.
+func (r *ReconcileVitessCluster) cellRolloutTargets(ctx context.Context, vt *planetscalev2.VitessCluster) ([]rolloutTarget, error) {
+	labels := client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}
+	cells := &planetscalev2.VitessCellList{}
+	if err := r.client.List(ctx, cells, client.InNamespace(vt.Namespace), labels); err != nil {
+		return nil, err
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := r.client.List(ctx, deployments, client.InNamespace(vt.Namespace), labels, client.MatchingLabels{planetscalev2.ComponentLabel: planetscalev2.VtgateComponentName}); err != nil {
+		return nil, err
+	}
+	cellDeployments := make(map[string]*appsv1.Deployment, len(deployments.Items))
+	for i := range deployments.Items {
+		deploy := &deployments.Items[i]
+		cellDeployments[deploy.Labels[planetscalev2.CellLabel]] = deploy
+	}
+
+	targets := make([]rolloutTarget, 0, len(cells.Items))
+	for i := range cells.Items {
+		vtc := &cells.Items[i]
+		target := rolloutTarget{
+			name:    vtc.Spec.Name,
+			pending: rollout.Scheduled(vtc),
+		}
+		if vtc.Status.ObservedGeneration != vtc.Generation {
+			target.waiting = fmt.Sprintf("VitessCell %v to be reconciled", vtc.Name)
+		} else if deploy := cellDeployments[vtc.Spec.Name]; deploy != nil {
+			if msg := deploymentRolledOut(deploy); msg != "" {
+				target.waiting = msg
+			}
+		}
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].name < targets[j].name })
+	return targets, nil
+}
+
+// keyspaceRolloutTargets lists the keyspaces of the cluster, in order by
+// name, along with whether their shards and tablets have been updated.
+func (r *ReconcileVitessCluster) keyspaceRolloutTargets(ctx context.Context, vt *planetscalev2.VitessCluster) ([]rolloutTarget, error) {
+	keyspaces := &planetscalev2.VitessKeyspaceList{}
+	if err := r.client.List(ctx, keyspaces, client.InNamespace(vt.Namespace), client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}); err != nil {
+		return nil, err
+	}
+
+	targets := make([]rolloutTarget, 0, len(keyspaces.Items))
+	for i := range keyspaces.Items {
+		vtk := &keyspaces.Items[i]
+		target := rolloutTarget{
+			name:    vtk.Spec.Name,
+			pending: keyspacePendingObjects(vtk) > 0,
+		}
+		if vtk.Status.ObservedGeneration != vtk.Generation {
+			target.waiting = fmt.Sprintf("VitessKeyspace %v to be reconciled", vtk.Name)
+		} else {
+			for _, shard := range vtk.Status.Shards {
+				if shard.ReadyTablets < shard.DesiredTablets {
+					target.waiting = fmt.Sprintf("tablets of keyspace %v to be ready", vtk.Spec.Name)
+					break
+				}
+			}
+		}
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].name < targets[j].name })
+	return targets, nil
+}
+
+// keyspacePendingObjects counts the keyspace itself, and every shard and
+// tablet in it, that has pending changes.
+func keyspacePendingObjects(vtk *planetscalev2.VitessKeyspace) int32 {
+	var pending int32
+	if rollout.Scheduled(vtk) {
+		pending++
+	}
+	for _, shard := range vtk.Status.Shards {
+		if shard.PendingChanges != "" {
+			pending++
+		}
+		pending += shard.Tablets - shard.UpdatedTablets
+	}
+	return pending
+}
+
+/*
+reconcileAutoRollback halts the rollout once too many updated tablets are
+unhealthy, and asks their shards to revert them if requested.
+
+A halted rollout is remembered in the old status until the generation of
+the VitessCluster changes, so it's not resumed just because reverted
+tablets become healthy again.
+*/
+func (r *ReconcileVitessCluster) reconcileAutoRollback(ctx context.Context, vt *planetscalev2.VitessCluster, autoRollback *planetscalev2.RolloutAutoRollbackSpec, status *planetscalev2.VitessClusterRolloutCoordinatorStatus) error {
+	shards := &planetscalev2.VitessShardList{}
+	if err := r.client.List(ctx, shards, client.InNamespace(vt.Namespace), client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}); err != nil {
+		return err
+	}
+
+	if status.HaltedGeneration == 0 && status.Stage != "" {
+		var unhealthy []string
+		for i := range shards.Items {
+			unhealthy = append(unhealthy, shards.Items[i].Status.UnhealthyTablets...)
+		}
+		if len(unhealthy) > 0 && int32(len(unhealthy)) >= *autoRollback.MaxUnhealthyTablets {
+			sort.Strings(unhealthy)
+			status.HaltedGeneration = vt.Generation
+			status.UnhealthyTablets = unhealthy
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "RolloutHalted", "Halted rollout because %v updated tablets are unhealthy: %v", len(unhealthy), strings.Join(unhealthy, ", "))
+		}
+	}
+
+	// Ask each shard to revert its unhealthy tablets while the rollout is halted.
+	for i := range shards.Items {
+		vts := &shards.Items[i]
+		var revert []string
+		if status.HaltedGeneration != 0 && autoRollback.RevertPods {
+			for _, tablet := range status.UnhealthyTablets {
+				if _, ok := vts.Status.Tablets[tablet]; ok {
+					revert = append(revert, tablet)
+				}
+			}
+		}
+		if strings.Join(revert, ",") == strings.Join(rollout.RevertTablets(vts), ",") {
+			continue
+		}
+		rollout.SetRevertTablets(vts, revert)
+		if err := r.client.Update(ctx, vts); err != nil {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "RevertFailed", "failed to update tablets to revert in VitessShard %v: %v", vts.Name, err)
+			return err
+		}
+		if len(revert) > 0 {
+			r.recorder.Eventf(vt, corev1.EventTypeNormal, "TabletsReverted", "Reverting unhealthy tablets %v to their last known good images and flags", strings.Join(revert, ", "))
+		}
+	}
+	return nil
+}
+
+// abortShardRollouts stops every shard in the cluster from restarting any
+// more of its tablets, unless tablet changes are released by the
+// released-components annotation anyway.
+func (r *ReconcileVitessCluster) abortShardRollouts(ctx context.Context, vt *planetscalev2.VitessCluster) error {
+	if rollout.ComponentReleased(vt, planetscalev2.VttabletComponentName) {
+		return nil
+	}
+	shards := &planetscalev2.VitessShardList{}
+	if err := r.client.List(ctx, shards, client.InNamespace(vt.Namespace), client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}); err != nil {
+		return err
+	}
+	for i := range shards.Items {
+		vts := &shards.Items[i]
+		if !rollout.Cascading(vts) {
+			continue
+		}
+		rollout.Uncascade(vts)
+		if err := r.client.Update(ctx, vts); err != nil {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "RolloutAbortFailed", "failed to stop rollout of VitessShard %v: %v", vts.Name, err)
+			return err
+		}
+		r.recorder.Eventf(vt, corev1.EventTypeNormal, "RolloutAborted", "stopped rollout of VitessShard %v", vts.Name)
+	}
+	return nil
+}
+
+// reconcileRolloutProgress counts how many vtgate and tablet Pods have been
+// updated so far into the rollout status. It must run after cells and
+// keyspaces have reported their status.
+func (r *ReconcileVitessCluster) reconcileRolloutProgress(ctx context.Context, vt *planetscalev2.VitessCluster) error {
+	deployments := &appsv1.DeploymentList{}
+	labels := client.MatchingLabels{
+		planetscalev2.ClusterLabel:   vt.Name,
+		planetscalev2.ComponentLabel: planetscalev2.VtgateComponentName,
+	}
+	if err := r.client.List(ctx, deployments, client.InNamespace(vt.Namespace), labels); err != nil {
+		return err
+	}
+	var pods, updatedPods int32
+	for i := range deployments.Items {
+		deploy := &deployments.Items[i]
+		replicas := deploymentReplicas(deploy)
+		pods += replicas
+		if deploy.Status.ObservedGeneration != deploy.Generation {
+			// The Deployment hasn't seen its latest changes yet.
+			continue
+		}
+		if deploy.Status.UpdatedReplicas < replicas {
+			updatedPods += deploy.Status.UpdatedReplicas
+		} else {
+			updatedPods += replicas
+		}
+	}
+	vt.Status.SetRolloutPods(planetscalev2.VtgateComponentName, pods, updatedPods)
+
+	pods, updatedPods = 0, 0
+	for _, keyspace := range vt.Status.Keyspaces {
+		pods += keyspace.Tablets
+		updatedPods += keyspace.UpdatedTablets
+	}
+	vt.Status.SetRolloutPods(planetscalev2.VttabletComponentName, pods, updatedPods)
+	return nil
+}
+
+func validateRolloutCoordinatorOrder(order []planetscalev2.RolloutCoordinatorStage) error {
+	seen := map[planetscalev2.RolloutCoordinatorStage]bool{}
+	for _, stage := range order {
+		switch stage {
+		case planetscalev2.CellsRolloutCoordinatorStage, planetscalev2.KeyspacesRolloutCoordinatorStage:
+		default:
+			return fmt.Errorf("invalid rollout order: unknown stage %q", stage)
+		}
+		if seen[stage] {
+			return fmt.Errorf("invalid rollout order: stage %q is listed more than once", stage)
+		}
+		seen[stage] = true
+	}
+	if len(seen) != 2 {
+		return fmt.Errorf("invalid rollout order: both %v and %v must be listed",
+			planetscalev2.CellsRolloutCoordinatorStage, planetscalev2.KeyspacesRolloutCoordinatorStage)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
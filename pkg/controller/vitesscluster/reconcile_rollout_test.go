@@ -0,0 +1,174 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+func TestValidateRolloutCoordinatorOrder(t *testing.T) {
+	cells, keyspaces := planetscalev2.CellsRolloutCoordinatorStage, planetscalev2.KeyspacesRolloutCoordinatorStage
+
+	table := []struct {
+		order   []planetscalev2.RolloutCoordinatorStage
+		wantErr bool
+	}{
+		{[]planetscalev2.RolloutCoordinatorStage{cells, keyspaces}, false},
+		{[]planetscalev2.RolloutCoordinatorStage{keyspaces, cells}, false},
+		{[]planetscalev2.RolloutCoordinatorStage{cells}, true},
+		{[]planetscalev2.RolloutCoordinatorStage{cells, cells}, true},
+		{[]planetscalev2.RolloutCoordinatorStage{cells, "shards"}, true},
+	}
+
+	for _, test := range table {
+		if err := validateRolloutCoordinatorOrder(test.order); (err != nil) != test.wantErr {
+			t.Errorf("validateRolloutCoordinatorOrder(%v) = %v; want error: %v", test.order, err, test.wantErr)
+		}
+	}
+}
+
+func TestRolloutTargetDone(t *testing.T) {
+	rollingOut := []string{"zone1"}
+
+	table := []struct {
+		target rolloutTarget
+		want   bool
+	}{
+		{rolloutTarget{name: "zone1", pending: true}, false},
+		{rolloutTarget{name: "zone1", waiting: "vtgate"}, false},
+		{rolloutTarget{name: "zone1"}, true},
+		// Unhealthy targets that weren't rolling out don't hold up the stage.
+		{rolloutTarget{name: "zone2", waiting: "vtgate"}, true},
+		{rolloutTarget{name: "zone2", pending: true}, false},
+	}
+
+	for _, test := range table {
+		if got := rolloutTargetDone(&test.target, rollingOut); got != test.want {
+			t.Errorf("rolloutTargetDone(%+v) = %v; want %v", test.target, got, test.want)
+		}
+	}
+}
+
+func TestReconcileRolloutCoordinatorPaused(t *testing.T) {
+	dryRunCtx, _ := reconciler.NewDryRunContext(context.Background())
+
+	table := []struct {
+		name       string
+		ctx        context.Context
+		wantWrites bool
+	}{
+		{
+			name:       "not paused",
+			ctx:        context.Background(),
+			wantWrites: true,
+		},
+		{
+			name: "paused",
+			ctx:  reconciler.NewPausedContext(context.Background()),
+		},
+		{
+			name: "dry run",
+			ctx:  dryRunCtx,
+		},
+		{
+			name: "read-only",
+			ctx:  reconciler.NewReadOnlyContext(context.Background()),
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			// The rollout was halted by an unhealthy tablet in a shard that
+			// is still restarting tablets, so the coordinator would revert
+			// the tablet and stop the shard.
+			const unhealthy = "zone1-0000000101"
+			vts := &planetscalev2.VitessShard{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Name:      "shard",
+					Labels:    map[string]string{planetscalev2.ClusterLabel: "cluster"},
+				},
+				Status: planetscalev2.VitessShardStatus{
+					Tablets: map[string]planetscalev2.VitessTabletStatus{unhealthy: {}},
+				},
+			}
+			rollout.Cascade(vts)
+			c := newFakeClient(t, vts)
+			if err := c.Get(ctx, client.ObjectKeyFromObject(vts), vts); err != nil {
+				t.Fatal(err)
+			}
+
+			external := planetscalev2.ExternalVitessClusterUpdateStrategyType
+			one := int32(1)
+			vt := &planetscalev2.VitessCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster", Generation: 2},
+				Spec: planetscalev2.VitessClusterSpec{
+					UpdateStrategy: &planetscalev2.VitessClusterUpdateStrategy{
+						Type: &external,
+						Coordinator: &planetscalev2.RolloutCoordinatorSpec{
+							Order: []planetscalev2.RolloutCoordinatorStage{
+								planetscalev2.CellsRolloutCoordinatorStage, planetscalev2.KeyspacesRolloutCoordinatorStage,
+							},
+							MaxConcurrentCells:     &one,
+							MaxConcurrentKeyspaces: &one,
+							AutoRollback: &planetscalev2.RolloutAutoRollbackSpec{
+								MaxUnhealthyTablets: &one,
+								RevertPods:          true,
+							},
+						},
+					},
+				},
+			}
+			oldStatus := &planetscalev2.VitessClusterStatus{
+				RolloutCoordinator: &planetscalev2.VitessClusterRolloutCoordinatorStatus{
+					Phase:            planetscalev2.ProgressingRolloutCoordinatorPhase,
+					Stage:            planetscalev2.KeyspacesRolloutCoordinatorStage,
+					Keyspaces:        []string{"keyspace"},
+					HaltedGeneration: 2,
+					UnhealthyTablets: []string{unhealthy},
+				},
+			}
+			r := &ReconcileVitessCluster{client: c, recorder: record.NewFakeRecorder(10)}
+
+			if _, err := r.reconcileRolloutCoordinator(test.ctx, vt, oldStatus); err != nil {
+				t.Fatalf("reconcileRolloutCoordinator() error: %v", err)
+			}
+
+			got := &planetscalev2.VitessShard{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(vts), got); err != nil {
+				t.Fatal(err)
+			}
+			if written := got.ResourceVersion != vts.ResourceVersion; written != test.wantWrites {
+				t.Errorf("VitessShard updated = %v; want %v", written, test.wantWrites)
+			}
+			if unchanged := apiequality.Semantic.DeepEqual(vt.Status.RolloutCoordinator, oldStatus.RolloutCoordinator); unchanged == test.wantWrites {
+				t.Errorf("status.rolloutCoordinator = %+v; want unchanged = %v", vt.Status.RolloutCoordinator, !test.wantWrites)
+			}
+		})
+	}
+}
@@ -22,13 +22,11 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
-	"planetscale.dev/vitess-operator/pkg/operator/stringsets"
 	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
 	"planetscale.dev/vitess-operator/pkg/operator/vitesstopo"
 )
@@ -52,7 +50,7 @@ func (r *ReconcileVitessCluster) reconcileTopology(ctx context.Context, vt *plan
 		r.recorder.Event(vt, corev1.EventTypeWarning, "TopoInvalid", "no global lockserver is defined")
 		return resultBuilder.Result()
 	}
-	ts, err := toposerver.Open(ctx, *globalParams)
+	ts, err := toposerver.Open(ctx, r.client, vt.Namespace, *globalParams)
 	if err != nil {
 		r.recorder.Eventf(vt, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
 		// Give the lockserver some time to come up.
@@ -73,11 +71,28 @@ func (r *ReconcileVitessCluster) reconcileCellTopology(ctx context.Context, vt *
 	resultBuilder := &results.Builder{}
 
 	// Make a map from cell name (as Vitess calls them) back to the cell's lockserver spec.
-	desiredCells := make(map[string]*planetscalev2.LockserverSpec, len(vt.Spec.Cells))
+	desiredCells := make(map[string]*planetscalev2.LockserverSpec, len(vt.Spec.Cells)+len(vt.Spec.ExternalCells))
 	for i := range vt.Spec.Cells {
 		cell := &vt.Spec.Cells[i]
 		desiredCells[cell.Name] = &cell.Lockserver
 	}
+	// External cells are only registered by us if we know their lockservers,
+	// but they're all desired, so they're never pruned.
+	registeredCells := make(map[string]*planetscalev2.LockserverSpec, len(desiredCells))
+	for name, lockserverSpec := range desiredCells {
+		registeredCells[name] = lockserverSpec
+	}
+	for i := range vt.Spec.ExternalCells {
+		cell := &vt.Spec.ExternalCells[i]
+		if desiredCells[cell.Name] != nil {
+			continue
+		}
+		lockserverSpec := &planetscalev2.LockserverSpec{External: cell.Lockserver}
+		desiredCells[cell.Name] = lockserverSpec
+		if cell.Lockserver != nil {
+			registeredCells[cell.Name] = lockserverSpec
+		}
+	}
 
 	if *vt.Spec.TopologyReconciliation.RegisterCellsAliases {
 		// We need to add an alias for all the cells in each region so that vtgate
@@ -87,7 +102,17 @@ func (r *ReconcileVitessCluster) reconcileCellTopology(ctx context.Context, vt *
 		// We also need to create the aliases before we create the cells because we
 		// don't want any vtgates to start after the cells are created but before
 		// the alias exists.
-		err := r.registerCellsAliases(ctx, vt, ts, desiredCells)
+		//
+		// Don't hold our slot in the reconcile work queue for too long.
+		ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+		defer cancel()
+
+		err := vitesstopo.RegisterCellsAliases(ctx, vitesstopo.RegisterCellsAliasesParams{
+			EventObj:     vt,
+			TopoServer:   ts,
+			Recorder:     r.recorder,
+			DesiredCells: desiredCells,
+		})
 		if err != nil {
 			return resultBuilder.Error(err)
 		}
@@ -105,7 +130,7 @@ func (r *ReconcileVitessCluster) reconcileCellTopology(ctx context.Context, vt *
 			GlobalLockserver: &vt.Spec.GlobalLockserver,
 			ClusterName:      vt.Name,
 			GlobalTopoImpl:   globalTopoImpl,
-			DesiredCells:     desiredCells,
+			DesiredCells:     registeredCells,
 		})
 		resultBuilder.Merge(result, err)
 	}
@@ -128,44 +153,6 @@ func (r *ReconcileVitessCluster) reconcileCellTopology(ctx context.Context, vt *
 	return resultBuilder.Result()
 }
 
-func (r *ReconcileVitessCluster) registerCellsAliases(ctx context.Context, vt *planetscalev2.VitessCluster, ts *topo.Server, desiredCells map[string]*planetscalev2.LockserverSpec) error {
-	// Don't hold our slot in the reconcile work queue for too long.
-	ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
-	defer cancel()
-
-	desiredCellsAliases := buildCellsAliases(desiredCells)
-	currentCellsAliases, err := ts.GetCellsAliases(ctx, true)
-	if err != nil {
-		r.recorder.Eventf(vt, corev1.EventTypeWarning, "TopoCellAlias",
-			"Failed to get current cell aliases: %v", err)
-		return err
-	}
-	for alias, desiredCellsAlias := range desiredCellsAliases {
-		// If this alias already exists and matches what we are trying to update
-		// it to, skip it.
-		if _, ok := currentCellsAliases[alias]; ok {
-			if stringsets.Equal(desiredCellsAlias.Cells, currentCellsAliases[alias].Cells) {
-				continue
-			}
-		}
-		// Create or update cells alias.
-		err = ts.UpdateCellsAlias(ctx, alias, func(ca *topodatapb.CellsAlias) error {
-			// Even if we're creating, 'ca' will already be non-nil.
-			ca.Cells = desiredCellsAlias.Cells
-			return nil
-		})
-		if err != nil {
-			r.recorder.Eventf(vt, corev1.EventTypeWarning, "TopoCellAlias",
-				"Failed to create or update cell alias: %s: %v", alias, err)
-			return err
-		}
-		r.recorder.Eventf(vt, corev1.EventTypeNormal, "TopoCellAlias",
-			"Created or updated cells alias: %s -> %v", alias,
-			desiredCellsAlias.Cells)
-	}
-	return nil
-}
-
 func (r *ReconcileVitessCluster) reconcileKeyspaceTopology(ctx context.Context, vt *planetscalev2.VitessCluster, ts *topo.Server) (reconcile.Result, error) {
 	resultBuilder := &results.Builder{}
 
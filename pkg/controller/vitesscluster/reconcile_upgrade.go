@@ -0,0 +1,283 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// upgradePollInterval is how often to recheck the health of an upgrade stage.
+// Not all of the objects we check trigger a reconcile of the VitessCluster.
+const upgradePollInterval = 15 * time.Second
+
+/*
+reconcileUpgrade implements staged upgrades. It replaces vt.Spec.Images
+(in memory only) with the images that should be rolled out right now, so
+the rest of the reconcile passes them down as usual.
+
+The images rolled out so far are remembered in status.upgrade.images.
+Each time we're called, we find the first stage in the upgrade order whose
+images differ from spec.images. Once every earlier stage is verified to be
+fully rolled out and healthy, we copy that stage's images from the spec.
+
+While reconciliation is paused, including dry runs and replicas of the
+operator that aren't the leader, nothing is rolled out, so status.upgrade
+is kept as it was. Otherwise, a dry run would find every
+stage healthy, since none of them would have changed, and the whole
+upgrade would go out at once when the dry run ends.
+*/
+func (r *ReconcileVitessCluster) reconcileUpgrade(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	if vt.Spec.Upgrade == nil {
+		return resultBuilder.Result()
+	}
+
+	target := vt.Spec.Images.DeepCopy()
+	if reconciler.IsPaused(ctx) {
+		vt.Status.Upgrade = oldStatus.Upgrade.DeepCopy()
+		if vt.Status.Upgrade != nil {
+			vt.Spec.Images = *vt.Status.Upgrade.Images.DeepCopy()
+			vt.Spec.Images.ResolveTagsToDigests = target.ResolveTagsToDigests
+		}
+		return resultBuilder.Result()
+	}
+
+	status := oldStatus.Upgrade.DeepCopy()
+	if status == nil {
+		// This is the first time we've seen spec.upgrade, so assume the
+		// current images are the ones that are already rolled out.
+		status = &planetscalev2.VitessClusterUpgradeStatus{Images: *target.DeepCopy()}
+	}
+	status.Stage = ""
+	status.Message = ""
+	vt.Status.Upgrade = status
+
+	// Whatever happens next, only pass down the images we've rolled out.
+	status.Images.ResolveTagsToDigests = false
+	defer func() {
+		vt.Spec.Images = *status.Images.DeepCopy()
+		vt.Spec.Images.ResolveTagsToDigests = target.ResolveTagsToDigests
+	}()
+
+	order := vt.Spec.Upgrade.Order
+	if err := validateUpgradeOrder(order); err != nil {
+		status.Message = err.Error()
+		r.recorder.Eventf(vt, corev1.EventTypeWarning, "InvalidUpgradeOrder", "%v", err)
+		return resultBuilder.Result()
+	}
+
+	next := -1
+	for i, stage := range order {
+		if !upgradeStageImagesEqual(&status.Images, target, stage) {
+			next = i
+			break
+		}
+	}
+	if next < 0 {
+		// There's no upgrade in progress.
+		return resultBuilder.Result()
+	}
+	status.Stage = order[next]
+
+	// Don't start the next stage until all earlier stages are done.
+	for _, stage := range order[:next] {
+		msg, err := r.upgradeStageHealthy(ctx, vt, stage)
+		if err != nil {
+			return resultBuilder.Error(err)
+		}
+		if msg != "" {
+			status.Message = fmt.Sprintf("waiting for %v upgrade to finish: %v", stage, msg)
+			return resultBuilder.RequeueAfter(upgradePollInterval)
+		}
+	}
+
+	copyUpgradeStageImages(&status.Images, target, order[next])
+	status.Message = fmt.Sprintf("rolling out new %v images", order[next])
+	r.recorder.Eventf(vt, corev1.EventTypeNormal, "UpgradeStageStarted", "Started upgrading %v to new images", order[next])
+	return resultBuilder.RequeueAfter(upgradePollInterval)
+}
+
+// upgradeStageHealthy checks whether all objects of a stage have been
+// updated and are healthy. If not, it returns a description of what it's
+// still waiting for.
+func (r *ReconcileVitessCluster) upgradeStageHealthy(ctx context.Context, vt *planetscalev2.VitessCluster, stage planetscalev2.VitessUpgradeStage) (string, error) {
+	switch stage {
+	case planetscalev2.VtctldUpgradeStage:
+		msg, err := r.deploymentsHealthy(ctx, vt, planetscalev2.VtctldComponentName)
+		if msg != "" || err != nil {
+			return msg, err
+		}
+		return r.deploymentsHealthy(ctx, vt, planetscalev2.VtadminComponentName)
+	case planetscalev2.VtgateUpgradeStage:
+		cells := &planetscalev2.VitessCellList{}
+		if err := r.client.List(ctx, cells, client.InNamespace(vt.Namespace), client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}); err != nil {
+			return "", err
+		}
+		for i := range cells.Items {
+			if msg := childUpToDate(&cells.Items[i], cells.Items[i].Status.ObservedGeneration); msg != "" {
+				return "VitessCell " + msg, nil
+			}
+		}
+		return r.deploymentsHealthy(ctx, vt, planetscalev2.VtgateComponentName)
+	case planetscalev2.VttabletUpgradeStage:
+		keyspaces := &planetscalev2.VitessKeyspaceList{}
+		if err := r.client.List(ctx, keyspaces, client.InNamespace(vt.Namespace), client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}); err != nil {
+			return "", err
+		}
+		for i := range keyspaces.Items {
+			if msg := childUpToDate(&keyspaces.Items[i], keyspaces.Items[i].Status.ObservedGeneration); msg != "" {
+				return "VitessKeyspace " + msg, nil
+			}
+		}
+		shards := &planetscalev2.VitessShardList{}
+		if err := r.client.List(ctx, shards, client.InNamespace(vt.Namespace), client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name}); err != nil {
+			return "", err
+		}
+		for i := range shards.Items {
+			vts := &shards.Items[i]
+			if msg := childUpToDate(vts, vts.Status.ObservedGeneration); msg != "" {
+				return "VitessShard " + msg, nil
+			}
+			for alias, tablet := range vts.Status.Tablets {
+				if tablet.PendingChanges != "" {
+					return fmt.Sprintf("tablet %v has pending changes", alias), nil
+				}
+				if tablet.Ready != corev1.ConditionTrue {
+					return fmt.Sprintf("tablet %v is not ready", alias), nil
+				}
+			}
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown upgrade stage %q", stage)
+}
+
+// childUpToDate checks whether a child object has seen its latest spec and
+// has no changes waiting for release.
+func childUpToDate(obj client.Object, observedGeneration int64) string {
+	if observedGeneration != obj.GetGeneration() {
+		return fmt.Sprintf("%v hasn't been reconciled yet", obj.GetName())
+	}
+	if rollout.Scheduled(obj) {
+		return fmt.Sprintf("%v has changes waiting to be released", obj.GetName())
+	}
+	return ""
+}
+
+// deploymentsHealthy checks whether all Deployments of a component have
+// finished rolling out, and all their replicas are available.
+func (r *ReconcileVitessCluster) deploymentsHealthy(ctx context.Context, vt *planetscalev2.VitessCluster, component string) (string, error) {
+	deployments := &appsv1.DeploymentList{}
+	labels := client.MatchingLabels{
+		planetscalev2.ClusterLabel:   vt.Name,
+		planetscalev2.ComponentLabel: component,
+	}
+	if err := r.client.List(ctx, deployments, client.InNamespace(vt.Namespace), labels); err != nil {
+		return "", err
+	}
+	for i := range deployments.Items {
+		if msg := deploymentRolledOut(&deployments.Items[i]); msg != "" {
+			return msg, nil
+		}
+	}
+	return "", nil
+}
+
+// deploymentRolledOut checks whether a Deployment has finished rolling out,
+// and all its replicas are available. If not, it returns a description of
+// what it's still waiting for.
+func deploymentRolledOut(deploy *appsv1.Deployment) string {
+	replicas := deploymentReplicas(deploy)
+	if deploy.Status.ObservedGeneration != deploy.Generation ||
+		deploy.Status.UpdatedReplicas != replicas ||
+		deploy.Status.Replicas != replicas ||
+		deploy.Status.AvailableReplicas != replicas {
+		return fmt.Sprintf("Deployment %v has %v/%v updated and %v/%v available replicas",
+			deploy.Name, deploy.Status.UpdatedReplicas, replicas, deploy.Status.AvailableReplicas, replicas)
+	}
+	return ""
+}
+
+// deploymentReplicas returns the desired number of replicas of a Deployment.
+func deploymentReplicas(deploy *appsv1.Deployment) int32 {
+	if deploy.Spec.Replicas != nil {
+		return *deploy.Spec.Replicas
+	}
+	return 1
+}
+
+func validateUpgradeOrder(order []planetscalev2.VitessUpgradeStage) error {
+	seen := map[planetscalev2.VitessUpgradeStage]bool{}
+	for _, stage := range order {
+		switch stage {
+		case planetscalev2.VtctldUpgradeStage, planetscalev2.VtgateUpgradeStage, planetscalev2.VttabletUpgradeStage:
+		default:
+			return fmt.Errorf("invalid upgrade order: unknown stage %q", stage)
+		}
+		if seen[stage] {
+			return fmt.Errorf("invalid upgrade order: stage %q is listed more than once", stage)
+		}
+		seen[stage] = true
+	}
+	if len(seen) != 3 {
+		return fmt.Errorf("invalid upgrade order: all of %v, %v, and %v must be listed",
+			planetscalev2.VtctldUpgradeStage, planetscalev2.VtgateUpgradeStage, planetscalev2.VttabletUpgradeStage)
+	}
+	return nil
+}
+
+// upgradeStageImagesEqual returns whether a and b agree on the images
+// that belong to the given stage.
+func upgradeStageImagesEqual(a, b *planetscalev2.VitessImages, stage planetscalev2.VitessUpgradeStage) bool {
+	x, y := &planetscalev2.VitessImages{}, &planetscalev2.VitessImages{}
+	copyUpgradeStageImages(x, a, stage)
+	copyUpgradeStageImages(y, b, stage)
+	return apiequality.Semantic.DeepEqual(x, y)
+}
+
+// copyUpgradeStageImages copies the images that belong to the given stage
+// from src to dst.
+func copyUpgradeStageImages(dst, src *planetscalev2.VitessImages, stage planetscalev2.VitessUpgradeStage) {
+	switch stage {
+	case planetscalev2.VtctldUpgradeStage:
+		dst.Vtctld = src.Vtctld
+		dst.Vtadmin = src.Vtadmin
+	case planetscalev2.VtgateUpgradeStage:
+		dst.Vtgate = src.Vtgate
+	case planetscalev2.VttabletUpgradeStage:
+		dst.Vttablet = src.Vttablet
+		dst.Vtorc = src.Vtorc
+		dst.Vtbackup = src.Vtbackup
+		dst.Mysqld = src.Mysqld.DeepCopy()
+		dst.MysqldExporter = src.MysqldExporter
+		dst.Init = src.Init
+		dst.Debug = src.Debug
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+)
+
+// newFakeClient returns a fake client that knows our types.
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := planetscalev2.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestValidateUpgradeOrder(t *testing.T) {
+	vtctld, vtgate, vttablet := planetscalev2.VtctldUpgradeStage, planetscalev2.VtgateUpgradeStage, planetscalev2.VttabletUpgradeStage
+
+	table := []struct {
+		order   []planetscalev2.VitessUpgradeStage
+		wantErr bool
+	}{
+		{[]planetscalev2.VitessUpgradeStage{vtctld, vtgate, vttablet}, false},
+		{[]planetscalev2.VitessUpgradeStage{vtctld, vttablet, vtgate}, false},
+		{[]planetscalev2.VitessUpgradeStage{vtctld, vtgate}, true},
+		{[]planetscalev2.VitessUpgradeStage{vtctld, vtgate, vtgate}, true},
+		{[]planetscalev2.VitessUpgradeStage{vtctld, vtgate, "vtorc"}, true},
+	}
+
+	for _, test := range table {
+		if err := validateUpgradeOrder(test.order); (err != nil) != test.wantErr {
+			t.Errorf("validateUpgradeOrder(%v) = %v; want error: %v", test.order, err, test.wantErr)
+		}
+	}
+}
+
+func TestUpgradeStageImages(t *testing.T) {
+	old := &planetscalev2.VitessImages{
+		Vtctld:   "vitess/lite:v15",
+		Vtgate:   "vitess/lite:v15",
+		Vttablet: "vitess/lite:v15",
+		Mysqld:   &planetscalev2.MysqldImage{Mysql80Compatible: "vitess/lite:v15"},
+	}
+	target := &planetscalev2.VitessImages{
+		Vtctld:   "vitess/lite:v16",
+		Vtgate:   "vitess/lite:v16",
+		Vttablet: "vitess/lite:v16",
+		Mysqld:   &planetscalev2.MysqldImage{Mysql80Compatible: "vitess/lite:v16"},
+	}
+
+	got := old.DeepCopy()
+	copyUpgradeStageImages(got, target, planetscalev2.VtgateUpgradeStage)
+	if got.Vtgate != target.Vtgate || got.Vtctld != old.Vtctld || got.Vttablet != old.Vttablet {
+		t.Errorf("copyUpgradeStageImages(vtgate) = %+v; want only vtgate changed", got)
+	}
+	if !upgradeStageImagesEqual(got, target, planetscalev2.VtgateUpgradeStage) {
+		t.Errorf("upgradeStageImagesEqual(vtgate) = false; want true")
+	}
+	if upgradeStageImagesEqual(got, target, planetscalev2.VttabletUpgradeStage) {
+		t.Errorf("upgradeStageImagesEqual(vttablet) = true; want false")
+	}
+
+	copyUpgradeStageImages(got, target, planetscalev2.VttabletUpgradeStage)
+	if got.Mysqld.Mysql80Compatible != target.Mysqld.Mysql80Compatible {
+		t.Errorf("copyUpgradeStageImages(vttablet) mysqld = %v; want %v", got.Mysqld.Mysql80Compatible, target.Mysqld.Mysql80Compatible)
+	}
+}
+
+func TestReconcileUpgradePaused(t *testing.T) {
+	dryRunCtx, _ := reconciler.NewDryRunContext(context.Background())
+
+	table := []struct {
+		name       string
+		ctx        context.Context
+		wantVtgate string
+	}{
+		{
+			name:       "not paused",
+			ctx:        context.Background(),
+			wantVtgate: "vtgate:new",
+		},
+		{
+			name:       "paused",
+			ctx:        reconciler.NewPausedContext(context.Background()),
+			wantVtgate: "vtgate:old",
+		},
+		{
+			name:       "dry run",
+			ctx:        dryRunCtx,
+			wantVtgate: "vtgate:old",
+		},
+		{
+			name:       "read-only",
+			ctx:        reconciler.NewReadOnlyContext(context.Background()),
+			wantVtgate: "vtgate:old",
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			// The vtctld stage is done, and its Deployment is rolled out.
+			replicas := int32(1)
+			deploy := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:  "ns",
+					Name:       "vtctld",
+					Generation: 1,
+					Labels: map[string]string{
+						planetscalev2.ClusterLabel:   "cluster",
+						planetscalev2.ComponentLabel: planetscalev2.VtctldComponentName,
+					},
+				},
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			}
+			vt := &planetscalev2.VitessCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"},
+				Spec: planetscalev2.VitessClusterSpec{
+					Images: planetscalev2.VitessImages{Vtctld: "vtctld:new", Vtgate: "vtgate:new", Vttablet: "vttablet:new"},
+					Upgrade: &planetscalev2.VitessClusterUpgradeSpec{
+						Order: []planetscalev2.VitessUpgradeStage{
+							planetscalev2.VtctldUpgradeStage, planetscalev2.VtgateUpgradeStage, planetscalev2.VttabletUpgradeStage,
+						},
+					},
+				},
+			}
+			oldStatus := &planetscalev2.VitessClusterStatus{
+				Upgrade: &planetscalev2.VitessClusterUpgradeStatus{
+					Images:  planetscalev2.VitessImages{Vtctld: "vtctld:new", Vtgate: "vtgate:old", Vttablet: "vttablet:old"},
+					Stage:   planetscalev2.VtctldUpgradeStage,
+					Message: "rolling out new vtctld images",
+				},
+			}
+			r := &ReconcileVitessCluster{client: newFakeClient(t, deploy), recorder: record.NewFakeRecorder(10)}
+
+			if _, err := r.reconcileUpgrade(test.ctx, vt, oldStatus); err != nil {
+				t.Fatalf("reconcileUpgrade() error: %v", err)
+			}
+			if got := vt.Spec.Images.Vtgate; got != test.wantVtgate {
+				t.Errorf("vtgate image = %q; want %q", got, test.wantVtgate)
+			}
+			if got := vt.Status.Upgrade.Images.Vtgate; got != test.wantVtgate {
+				t.Errorf("status.upgrade.images.vtgate = %q; want %q", got, test.wantVtgate)
+			}
+			if reconciler.IsPaused(test.ctx) && !apiequality.Semantic.DeepEqual(vt.Status.Upgrade, oldStatus.Upgrade) {
+				t.Errorf("status.upgrade = %+v; want it unchanged: %+v", vt.Status.Upgrade, oldStatus.Upgrade)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
@@ -48,16 +49,16 @@ func (r *ReconcileVitessCluster) reconcileVtadmin(ctx context.Context, vt *plane
 
 	// Some checks to validate user input
 	if len(vt.Spec.Images.Vtadmin) == 0 {
-		log.Error("Not deploying vtadmin since image is unspecified")
+		logging.FromContext(ctx).Error("Not deploying vtadmin since image is unspecified")
 		return resultBuilder.Result()
 	}
 
 	if len(vt.Spec.VtAdmin.APIAddresses) == 0 {
-		log.Errorf("Not deploying vtadmin since api addresses field is not specified. Atleast 1 value is required")
+		logging.FromContext(ctx).Errorf("Not deploying vtadmin since api addresses field is not specified. Atleast 1 value is required")
 	}
 
 	if len(vt.Spec.VtAdmin.APIAddresses) != 1 && len(vt.Spec.VtAdmin.APIAddresses) != len(vt.Spec.VtAdmin.Cells) {
-		log.Errorf("Not deploying vtadmin since api addresses field doesn't align with cells field")
+		logging.FromContext(ctx).Errorf("Not deploying vtadmin since api addresses field doesn't align with cells field")
 	}
 
 	key := client.ObjectKey{Namespace: vt.Namespace, Name: vtadmin.ServiceName(vt.Name)}
@@ -73,6 +74,7 @@ func (r *ReconcileVitessCluster) reconcileVtadmin(ctx context.Context, vt *plane
 		New: func(key client.ObjectKey) runtime.Object {
 			svc := vtadmin.NewService(key, labels)
 			update.ServiceOverrides(svc, vt.Spec.VtAdmin.Service)
+			update.ServiceNetworking(svc, vt.Spec.Networking)
 			return svc
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
@@ -219,6 +221,7 @@ func (r *ReconcileVitessCluster) vtadminSpecs(ctx context.Context, vt *planetsca
 			ExtraVolumeMounts: vt.Spec.VtAdmin.ExtraVolumeMounts,
 			InitContainers:    vt.Spec.VtAdmin.InitContainers,
 			SidecarContainers: vt.Spec.VtAdmin.SidecarContainers,
+			ResourceDefaults:  vt.Spec.ResourceDefaults,
 			Annotations:       vt.Spec.VtAdmin.Annotations,
 			ExtraLabels:       vt.Spec.VtAdmin.ExtraLabels,
 			Tolerations:       vt.Spec.VtAdmin.Tolerations,
@@ -329,12 +332,12 @@ func (r *ReconcileVitessCluster) createDiscoverySecret(ctx context.Context, vt *
 
 	// return the secret sources, which must align with the secrets we created above
 	return &planetscalev2.SecretSource{
-			Name: discoverySecretName,
-			Key:  discoveryKey,
-		}, &planetscalev2.SecretSource{
-			Name: clusterConfigSecretName,
-			Key:  configKey,
-		}, nil
+		Name: discoverySecretName,
+		Key:  discoveryKey,
+	}, &planetscalev2.SecretSource{
+		Name: clusterConfigSecretName,
+		Key:  configKey,
+	}, nil
 }
 
 func (r *ReconcileVitessCluster) createWebConfigSecret(ctx context.Context, vt *planetscalev2.VitessCluster, cell *planetscalev2.VitessCellTemplate, apiAddress string) (*planetscalev2.SecretSource, error) {
@@ -377,6 +380,9 @@ func convertReadOnlyFieldToString(readOnly *bool) string {
 }
 
 func (r *ReconcileVitessCluster) createOrUpdateSecret(ctx context.Context, vt *planetscalev2.VitessCluster, secretName, discoveryKey, discoveryVal string) error {
+	if reconciler.IsPaused(ctx) {
+		return nil
+	}
 	desiredSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
@@ -23,15 +23,18 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+	"planetscale.dev/vitess-operator/pkg/operator/externaldns"
 	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 	"planetscale.dev/vitess-operator/pkg/operator/vtctld"
 )
 
@@ -42,6 +45,7 @@ func (r *ReconcileVitessCluster) reconcileVtctld(ctx context.Context, vt *planet
 		planetscalev2.ClusterLabel:   vt.Name,
 		planetscalev2.ComponentLabel: planetscalev2.VtctldComponentName,
 	}
+	hostname := externaldns.VtctldHostname(vt.Spec.ExternalDNS, vt.Name, vt.Namespace)
 
 	// Reconcile vtctld Service.
 	err := r.reconciler.ReconcileObject(ctx, vt, key, labels, true, reconciler.Strategy{
@@ -50,12 +54,15 @@ func (r *ReconcileVitessCluster) reconcileVtctld(ctx context.Context, vt *planet
 		New: func(key client.ObjectKey) runtime.Object {
 			svc := vtctld.NewService(key, labels)
 			update.ServiceOverrides(svc, vt.Spec.VitessDashboard.Service)
+			update.ServiceNetworking(svc, vt.Spec.Networking)
+			externaldns.UpdateService(svc, vt.Spec.ExternalDNS, hostname)
 			return svc
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
 			vtctld.UpdateService(svc, labels)
 			update.InPlaceServiceOverrides(svc, vt.Spec.VitessDashboard.Service)
+			externaldns.UpdateService(svc, vt.Spec.ExternalDNS, hostname)
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
@@ -67,9 +74,56 @@ func (r *ReconcileVitessCluster) reconcileVtctld(ctx context.Context, vt *planet
 		resultBuilder.Error(err)
 	}
 
+	// Reconcile the dedicated vtctld gRPC Service, if requested.
+	grpc := vt.Spec.VitessDashboard.GRPC
+	key = client.ObjectKey{Namespace: vt.Namespace, Name: vtctld.GRPCServiceName(vt.Name)}
+	err = r.reconciler.ReconcileObject(ctx, vt, key, labels, grpc != nil, reconciler.Strategy{
+		Kind: &corev1.Service{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			svc := vtctld.NewGRPCService(key, labels)
+			update.ServiceOverrides(svc, grpc.Service)
+			update.ServiceNetworking(svc, vt.Spec.Networking)
+			return svc
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			svc := obj.(*corev1.Service)
+			vtctld.UpdateGRPCService(svc, labels)
+			update.InPlaceServiceOverrides(svc, grpc.Service)
+		},
+		Status: func(key client.ObjectKey, obj runtime.Object) {
+			svc := obj.(*corev1.Service)
+			vt.Status.VitessDashboard.GRPCServiceName = svc.Name
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
 	// Reconcile vtctld Deployments.
 	specs := r.vtctldSpecs(vt, labels)
 
+	// Keep at least one vtctld available during voluntary disruptions,
+	// as long as there's more than one to begin with.
+	var replicas int32
+	for _, spec := range specs {
+		replicas += spec.Replicas
+	}
+	key = client.ObjectKey{Namespace: vt.Namespace, Name: vtctld.PDBName(vt.Name)}
+	err = r.reconciler.ReconcileObject(ctx, vt, key, labels, replicas > 1, reconciler.Strategy{
+		Kind: &policyv1.PodDisruptionBudget{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return vtctld.NewPDB(key, labels)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			vtctld.UpdatePDBInPlace(obj.(*policyv1.PodDisruptionBudget), labels)
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
 	// Generate keys (object names) for all desired vtctld Deployments.
 	// Keep a map back from generated names to the vtctld specs.
 	keys := make([]client.ObjectKey, 0, len(specs))
@@ -154,9 +208,8 @@ func (r *ReconcileVitessCluster) vtctldSpecs(vt *planetscalev2.VitessCluster, pa
 		labels[planetscalev2.CellLabel] = cell.Name
 
 		// Merge ExtraVitessFlags and ExtraFlags into a new map.
-		extraFlags := make(map[string]string)
-		update.StringMap(&extraFlags, vt.Spec.ExtraVitessFlags)
-		update.StringMap(&extraFlags, vt.Spec.VitessDashboard.ExtraFlags)
+		// updateManagedFlagsCondition reports any flags that are left out.
+		extraFlags, _ := vitess.MergeExtraFlags(vt.Spec.ComponentVitessFlags.ManagedFlagOverrides(), vt.Spec.ExtraVitessFlags, vt.Spec.ComponentVitessFlags.VtctldFlags(), vt.Spec.VitessDashboard.ExtraFlags)
 
 		var backupLocation *planetscalev2.VitessBackupLocation
 		var backupEngine planetscalev2.VitessBackupEngine
@@ -170,6 +223,11 @@ func (r *ReconcileVitessCluster) vtctldSpecs(vt *planetscalev2.VitessCluster, pa
 			backupEngine = vt.Spec.Backup.Engine
 		}
 
+		var grpcTLS *planetscalev2.VtctldGRPCTLSSpec
+		if vt.Spec.VitessDashboard.GRPC != nil {
+			grpcTLS = vt.Spec.VitessDashboard.GRPC.TLS
+		}
+
 		specs = append(specs, &vtctld.Spec{
 			GlobalLockserver:  glsParams,
 			Image:             vt.Spec.Images.Vtctld,
@@ -186,11 +244,13 @@ func (r *ReconcileVitessCluster) vtctldSpecs(vt *planetscalev2.VitessCluster, pa
 			ExtraVolumeMounts: vt.Spec.VitessDashboard.ExtraVolumeMounts,
 			InitContainers:    vt.Spec.VitessDashboard.InitContainers,
 			SidecarContainers: vt.Spec.VitessDashboard.SidecarContainers,
+			ResourceDefaults:  vt.Spec.ResourceDefaults,
 			Annotations:       vt.Spec.VitessDashboard.Annotations,
 			ExtraLabels:       vt.Spec.VitessDashboard.ExtraLabels,
 			Tolerations:       vt.Spec.VitessDashboard.Tolerations,
 			BackupEngine:      backupEngine,
 			BackupLocation:    backupLocation,
+			GRPCTLS:           grpcTLS,
 		})
 
 	}
@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/externaldns"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
@@ -37,6 +38,7 @@ func (r *ReconcileVitessCluster) reconcileVtgate(ctx context.Context, vt *planet
 		planetscalev2.ClusterLabel:   vt.Name,
 		planetscalev2.ComponentLabel: planetscalev2.VtgateComponentName,
 	}
+	hostname := externaldns.VtgateHostname(vt.Spec.ExternalDNS, vt.Name, vt.Namespace)
 	resultBuilder := results.Builder{}
 
 	// Reconcile vtgate Service.
@@ -46,12 +48,15 @@ func (r *ReconcileVitessCluster) reconcileVtgate(ctx context.Context, vt *planet
 		New: func(key client.ObjectKey) runtime.Object {
 			svc := vtgate.NewService(key, labels)
 			update.ServiceOverrides(svc, vt.Spec.GatewayService)
+			update.ServiceNetworking(svc, vt.Spec.Networking)
+			externaldns.UpdateService(svc, vt.Spec.ExternalDNS, hostname)
 			return svc
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
 			vtgate.UpdateService(svc, labels)
 			update.InPlaceServiceOverrides(svc, vt.Spec.GatewayService)
+			externaldns.UpdateService(svc, vt.Spec.ExternalDNS, hostname)
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
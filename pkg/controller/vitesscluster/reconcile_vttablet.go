@@ -50,12 +50,17 @@ func (r *ReconcileVitessCluster) reconcileVttablet(ctx context.Context, vt *plan
 		New: func(key client.ObjectKey) runtime.Object {
 			svc := vttablet.NewService(key, labels)
 			update.ServiceOverrides(svc, vt.Spec.TabletService)
+			update.ServiceNetworking(svc, vt.Spec.Networking)
+			svc.Spec.PublishNotReadyAddresses = vt.Spec.Networking.TabletHostnames()
 			return svc
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
 			vttablet.UpdateService(svc, labels)
 			update.InPlaceServiceOverrides(svc, vt.Spec.TabletService)
+			// Tablets that advertise hostnames must be resolvable before
+			// they're Ready, so replicas can start replicating from them.
+			svc.Spec.PublishNotReadyAddresses = vt.Spec.Networking.TabletHostnames()
 		},
 	})
 	if err != nil {
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+)
+
+/*
+discoverZones fills in the zone (in memory only) of each cell that has
+zoneDiscovery set and no explicit zone, and records the zones in status.
+
+The zone then flows into the ZoneMap of keyspaces and the zone affinity of
+each component, just as if it had been set by hand, so those are kept up to
+date as the zone is discovered or changes.
+
+If the zone can't be discovered this time, because no Nodes were found or they
+disagree, we keep using the zone that was discovered before, if any, and
+report the problem in the ZonesDiscovered condition.
+*/
+func (r *ReconcileVitessCluster) discoverZones(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) {
+	discovering := false
+	var problems []string
+	for i := range vt.Spec.Cells {
+		cell := &vt.Spec.Cells[i]
+		if cell.Zone != "" || cell.ZoneDiscovery == nil {
+			continue
+		}
+		discovering = true
+
+		zone, err := r.discoverCellZone(ctx, cell)
+		if err == nil && zone == "" {
+			err = fmt.Errorf("no Nodes match nodeSelector")
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", cell.Name, err))
+			zone = oldStatus.DiscoveredZones[cell.Name]
+		}
+		if zone == "" {
+			continue
+		}
+		cell.Zone = zone
+		if vt.Status.DiscoveredZones == nil {
+			vt.Status.DiscoveredZones = make(map[string]string, len(vt.Spec.Cells))
+		}
+		vt.Status.DiscoveredZones[cell.Name] = zone
+	}
+
+	switch {
+	case !discovering:
+		vt.Status.RemoveCondition(planetscalev2.VitessClusterZonesDiscovered)
+	case len(problems) > 0:
+		vt.Status.SetConditionStatus(planetscalev2.VitessClusterZonesDiscovered, corev1.ConditionFalse, "DiscoveryFailed", fmt.Sprintf("Can't discover the zone of some cells, so the zone discovered before, if any, is kept: %v.", strings.Join(problems, "; ")))
+	default:
+		vt.Status.SetConditionStatus(planetscalev2.VitessClusterZonesDiscovered, corev1.ConditionTrue, "Discovered", "The zone of every cell with zoneDiscovery was discovered.")
+	}
+}
+
+// discoverCellZone returns the zone of the Nodes selected for the cell,
+// or "" if there are no such Nodes.
+//
+// The Nodes must be selected explicitly. The Nodes that the cell's Pods run
+// on can't be used, since those Pods are only scheduled in the right zone
+// once the zone is known.
+func (r *ReconcileVitessCluster) discoverCellZone(ctx context.Context, cell *planetscalev2.VitessCellTemplate) (string, error) {
+	selector := cell.ZoneDiscovery.NodeSelector
+	if len(selector) == 0 {
+		return "", fmt.Errorf("zoneDiscovery.nodeSelector is empty")
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.apiReader.List(ctx, nodes, client.MatchingLabels(selector)); err != nil {
+		return "", err
+	}
+	return nodesZone(nodes.Items)
+}
+
+// nodesZone returns the zone that all the given Nodes are in, according to
+// their topology labels, or "" if there are no Nodes.
+func nodesZone(nodes []corev1.Node) (string, error) {
+	zones := sets.New[string]()
+	for i := range nodes {
+		node := &nodes[i]
+		zone := node.Labels[k8s.TopologyZoneLabel]
+		if zone == "" {
+			zone = node.Labels[k8s.ZoneFailureDomainLabel]
+		}
+		if zone == "" {
+			return "", fmt.Errorf("node %v has no zone label", node.Name)
+		}
+		zones.Insert(zone)
+	}
+	switch zones.Len() {
+	case 0:
+		return "", nil
+	case 1:
+		return sets.List(zones)[0], nil
+	default:
+		return "", fmt.Errorf("nodes are in more than one zone: %v", sets.List(zones))
+	}
+}
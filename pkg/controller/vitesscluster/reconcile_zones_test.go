@@ -0,0 +1,157 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+)
+
+func TestNodesZone(t *testing.T) {
+	node := func(name string, labels map[string]string) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	tests := []struct {
+		name    string
+		nodes   []corev1.Node
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no nodes",
+		},
+		{
+			name: "same zone",
+			nodes: []corev1.Node{
+				node("a", map[string]string{k8s.TopologyZoneLabel: "us-east-1a"}),
+				node("b", map[string]string{k8s.ZoneFailureDomainLabel: "us-east-1a"}),
+			},
+			want: "us-east-1a",
+		},
+		{
+			name: "GA label wins",
+			nodes: []corev1.Node{
+				node("a", map[string]string{k8s.TopologyZoneLabel: "us-east-1b", k8s.ZoneFailureDomainLabel: "us-east-1a"}),
+			},
+			want: "us-east-1b",
+		},
+		{
+			name: "different zones",
+			nodes: []corev1.Node{
+				node("a", map[string]string{k8s.TopologyZoneLabel: "us-east-1a"}),
+				node("b", map[string]string{k8s.TopologyZoneLabel: "us-east-1b"}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unlabeled node",
+			nodes: []corev1.Node{
+				node("a", map[string]string{k8s.TopologyZoneLabel: "us-east-1a"}),
+				node("b", nil),
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := nodesZone(test.nodes)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("nodesZone() error = %v; wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("nodesZone() = %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverZones(t *testing.T) {
+	ctx := context.Background()
+	node := func(name, pool, zone string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"pool": pool, k8s.TopologyZoneLabel: zone},
+		}}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		node("a1", "a", "us-east-1a"),
+		node("a2", "a", "us-east-1a"),
+		node("b1", "b", "us-east-1b"),
+		node("c1", "c", "us-east-1a"),
+		node("c2", "c", "us-east-1c"),
+	).Build()
+	r := &ReconcileVitessCluster{apiReader: c}
+
+	cluster := func(pools ...string) *planetscalev2.VitessCluster {
+		vt := &planetscalev2.VitessCluster{}
+		for _, pool := range pools {
+			vt.Spec.Cells = append(vt.Spec.Cells, planetscalev2.VitessCellTemplate{
+				Name:          "cell-" + pool,
+				ZoneDiscovery: &planetscalev2.VitessCellZoneDiscovery{NodeSelector: map[string]string{"pool": pool}},
+			})
+		}
+		vt.Status = planetscalev2.NewVitessClusterStatus()
+		return vt
+	}
+	condition := func(vt *planetscalev2.VitessCluster) corev1.ConditionStatus {
+		cond, ok := vt.Status.GetCondition(planetscalev2.VitessClusterZonesDiscovered)
+		if !ok {
+			return ""
+		}
+		return cond.Status
+	}
+
+	// All zones are discovered.
+	vt := cluster("a", "b")
+	r.discoverZones(ctx, vt, &planetscalev2.VitessClusterStatus{})
+	if got, want := vt.Spec.Cells[1].Zone, "us-east-1b"; got != want {
+		t.Errorf("zone of cell-b = %q; want %q", got, want)
+	}
+	if got, want := condition(vt), corev1.ConditionTrue; got != want {
+		t.Errorf("ZonesDiscovered = %q; want %q", got, want)
+	}
+
+	// Nodes in different zones, or no Nodes at all, keep the zone that was
+	// discovered before, and set the condition to False.
+	vt = cluster("c", "none")
+	oldStatus := &planetscalev2.VitessClusterStatus{DiscoveredZones: map[string]string{"cell-c": "us-east-1c"}}
+	r.discoverZones(ctx, vt, oldStatus)
+	if got, want := vt.Spec.Cells[0].Zone, "us-east-1c"; got != want {
+		t.Errorf("zone of cell-c = %q; want %q", got, want)
+	}
+	if got := vt.Spec.Cells[1].Zone; got != "" {
+		t.Errorf("zone of cell-none = %q; want none", got)
+	}
+	if got, want := condition(vt), corev1.ConditionFalse; got != want {
+		t.Errorf("ZonesDiscovered = %q; want %q", got, want)
+	}
+
+	// Without zoneDiscovery, there's no condition.
+	vt.Spec.Cells = nil
+	r.discoverZones(ctx, vt, oldStatus)
+	if got := condition(vt); got != "" {
+		t.Errorf("ZonesDiscovered = %q; want no condition", got)
+	}
+}
@@ -25,6 +25,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,10 +40,17 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/follower"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/registry"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/statusupdate"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 )
 
 const (
@@ -59,6 +68,9 @@ var log = logrus.WithField("controller", "VitessCluster")
 var watchResources = []client.Object{
 	&corev1.Service{},
 	&appsv1.Deployment{},
+	&networkingv1.NetworkPolicy{},
+	&policyv1.PodDisruptionBudget{},
+	&corev1.PersistentVolumeClaim{},
 
 	&planetscalev2.VitessCell{},
 	&planetscalev2.VitessKeyspace{},
@@ -74,23 +86,26 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) *ReconcileVitessCluster {
-	c := mgr.GetClient()
+	c := follower.Client(mgr.GetClient())
 	scheme := mgr.GetScheme()
-	recorder := mgr.GetEventRecorderFor(controllerName)
+	recorder := follower.Recorder(mgr.GetEventRecorderFor(controllerName))
 
 	return &ReconcileVitessCluster{
-		client:     c,
-		scheme:     scheme,
-		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
-		recorder:   recorder,
-		reconciler: reconciler.New(c, scheme, recorder),
+		client:       c,
+		apiReader:    mgr.GetAPIReader(),
+		scheme:       scheme,
+		resync:       resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder:     recorder,
+		reconciler:   reconciler.New(c, scheme, recorder),
+		statusWriter: statusupdate.NewWriter(c, "VitessCluster", environment.StatusUpdateInterval()),
+		registry:     registry.NewResolver(nil),
 	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileVitessCluster) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{
+	c, err := follower.NewController(controllerName, mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: *maxConcurrentReconciles,
 	})
@@ -128,11 +143,16 @@ var _ reconcile.Reconciler = &ReconcileVitessCluster{}
 type ReconcileVitessCluster struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client     client.Client
-	scheme     *runtime.Scheme
-	resync     *resync.Periodic
-	recorder   record.EventRecorder
-	reconciler *reconciler.Reconciler
+	client client.Client
+	// apiReader reads Nodes directly from the apiserver, so we don't need
+	// to cache all of them just to discover the zones of a few.
+	apiReader    client.Reader
+	scheme       *runtime.Scheme
+	resync       *resync.Periodic
+	recorder     record.EventRecorder
+	reconciler   *reconciler.Reconciler
+	registry     *registry.Resolver
+	statusWriter *statusupdate.Writer
 }
 
 // Reconcile reads that state of the cluster for a VitessCluster object and makes changes based on the state read
@@ -140,15 +160,20 @@ type ReconcileVitessCluster struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconcile.Request) (_ reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
 	defer cancel()
 
+	ctx, span := tracing.StartReconcile(ctx, "VitessCluster", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	resultBuilder := &results.Builder{}
 	log := log.WithFields(logrus.Fields{
-		"namespace":     request.Namespace,
-		"VitessCluster": request.Name,
+		"namespace":              request.Namespace,
+		"VitessCluster":          request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	log.Info("Reconciling VitessCluster")
 
 	// Fetch the VitessCluster instance.
@@ -159,20 +184,63 @@ func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconci
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			forgetDriftMetrics(request.Name)
 			return resultBuilder.Result()
 		}
 		// Error reading the object - requeue the request.
 		return resultBuilder.Error(err)
 	}
+	log = log.WithFields(logging.ObjectFields(vt))
+	ctx = logging.NewContext(ctx, log)
 
 	// Reset status, since that's all out of date info that we will recompute now.
 	oldStatus := vt.Status
 	vt.Status = planetscalev2.NewVitessClusterStatus()
+	// Conditions keep their transition times, so each one is updated or
+	// removed by whatever sets it.
+	vt.Status.Conditions = oldStatus.DeepCopyConditions()
 
 	// Materialize all hard-coded default values into the object.
 	// TODO(enisoc): Use versioned defaults when operator-sdk supports mutating webhooks.
 	planetscalev2.DefaultVitessCluster(vt)
 
+	// Fill in the zones of cells that discover them from Nodes.
+	r.discoverZones(ctx, vt, &oldStatus)
+
+	// Report extra flags that are left out because the operator manages them.
+	updateManagedFlagsCondition(vt)
+
+	// While paused, we only compute status and propagate the paused state.
+	if vt.Spec.Paused {
+		log.Info("Reconciliation is paused")
+		ctx = reconciler.NewPausedContext(ctx)
+	}
+	if vt.Spec.AdoptExisting {
+		ctx = reconciler.NewAdoptingContext(ctx)
+	}
+	// Replicas that aren't the leader only compute status.
+	ctx = follower.NewContext(ctx)
+	// In a dry run, we also report what we would have changed.
+	var dryRun *reconciler.DryRun
+	if vt.Spec.DryRun {
+		log.Info("Reconciling as a dry run")
+		ctx, dryRun = reconciler.NewDryRunContext(ctx)
+	}
+
+	// Choose which images to roll out if a staged upgrade was requested.
+	upgradeResult, err := r.reconcileUpgrade(ctx, vt, &oldStatus)
+	resultBuilder.Merge(upgradeResult, err)
+
+	// Pin images to digests if requested. Until each image has been pinned
+	// once, children that run them are only read.
+	imagesResult, imagesPinned, err := r.resolveImageDigests(ctx, vt, &oldStatus)
+	resultBuilder.Merge(imagesResult, err)
+	childCtx := ctx
+	if !imagesPinned {
+		childCtx = reconciler.NewReadOnlyContext(ctx)
+	}
+	vt.Status.VersionSkew = versionSkew(vt)
+
 	// Create/update global etcd, if requested.
 	if err := r.reconcileGlobalEtcd(ctx, vt); err != nil {
 		// Record result but continue to reconcile cells.
@@ -184,13 +252,22 @@ func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconci
 		resultBuilder.Error(err)
 	}
 
+	// Create/update PVCs for volume claim backup locations.
+	if err := r.reconcileBackupVolumeClaims(ctx, vt); err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Choose which cells and keyspaces to roll out if the rollout coordinator is enabled.
+	coordinatorResult, err := r.reconcileRolloutCoordinator(ctx, vt, &oldStatus)
+	resultBuilder.Merge(coordinatorResult, err)
+
 	// Create/update desired VitessCells.
-	if err := r.reconcileCells(ctx, vt); err != nil {
+	if err := r.reconcileCells(childCtx, vt); err != nil {
 		resultBuilder.Error(err)
 	}
 
 	// Create/update desired VitessKeyspaces.
-	if err := r.reconcileKeyspaces(ctx, vt); err != nil {
+	if err := r.reconcileKeyspaces(childCtx, vt); err != nil {
 		resultBuilder.Error(err)
 	}
 
@@ -203,26 +280,70 @@ func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconci
 	resultBuilder.Merge(vttabletResult, err)
 
 	// Create/update vtctld deployments.
-	vtctldResult, err := r.reconcileVtctld(ctx, vt)
+	vtctldResult, err := r.reconcileVtctld(childCtx, vt)
 	resultBuilder.Merge(vtctldResult, err)
 
 	// Create/update vtadmin deployments.
-	vtadminResult, err := r.reconcileVtadmin(ctx, vt)
+	vtadminResult, err := r.reconcileVtadmin(childCtx, vt)
 	resultBuilder.Merge(vtadminResult, err)
 
+	// Create/update Prometheus Operator PodMonitors, if requested.
+	if err := r.reconcileMonitoring(ctx, vt); err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Create/update NetworkPolicies, if requested.
+	if err := r.reconcileNetworkPolicies(ctx, vt); err != nil {
+		resultBuilder.Error(err)
+	}
+
 	// Create/update Vitess topology records for cells as needed.
-	topoResult, err := r.reconcileTopology(ctx, vt)
-	resultBuilder.Merge(topoResult, err)
+	if !reconciler.IsPaused(ctx) {
+		topoResult, err := r.reconcileTopology(ctx, vt)
+		resultBuilder.Merge(topoResult, err)
+	} else if !vt.Spec.Paused && !vt.Spec.DryRun {
+		// We're following, so keep the leader's record of what it's pruning.
+		vt.Status.OrphanedCells = oldStatus.OrphanedCells
+		vt.Status.OrphanedKeyspaces = oldStatus.OrphanedKeyspaces
+	}
+
+	// Roll up cell and keyspace status into the cluster summary.
+	vt.Status.CompleteSummary()
+
+	// List the changes waiting to be rolled out, for review.
+	if err := r.reconcilePendingChanges(ctx, vt); err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Record which components have been released for rollout.
+	for _, component := range rollout.ReleasedComponents(vt) {
+		vt.Status.SetRolloutReleased(component)
+	}
+
+	// Count how many Pods have been updated so far.
+	if err := r.reconcileRolloutProgress(ctx, vt); err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Compare the resources of tablet pools with their usage, if requested.
+	recommendationsResult, err := r.reconcileResourceRecommendations(ctx, vt, &oldStatus)
+	resultBuilder.Merge(recommendationsResult, err)
+
+	if dryRun != nil {
+		vt.Status.DryRunChanges = dryRun.Changes()
+	}
 
 	// Update status if needed.
 	vt.Status.ObservedGeneration = vt.Generation
-	if !apiequality.Semantic.DeepEqual(&vt.Status, &oldStatus) {
-		if err := r.client.Status().Update(ctx, vt); err != nil {
-			if !apierrors.IsConflict(err) {
-				r.recorder.Eventf(vt, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
-			}
-			resultBuilder.Error(err)
+	urgent := oldStatus.ObservedGeneration != vt.Generation || decisionsChanged(&oldStatus, &vt.Status)
+	if delay, err := r.statusWriter.Update(ctx, vt, &vt.Status, &oldStatus, urgent); err != nil {
+		if !apierrors.IsConflict(err) {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
 		}
+		resultBuilder.Error(err)
+	} else if delay > 0 {
+		// Write the latest status once the interval has passed.
+		resultBuilder.RequeueAfter(delay)
 	}
 
 	// Request a periodic resync for the cluster so we can recheck topology even
@@ -233,3 +354,16 @@ func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconci
 	reconcileCount.WithLabelValues(vt.Name, metrics.Result(err)).Inc()
 	return result, err
 }
+
+// decisionsChanged returns whether any of the status fields that record the
+// operator's decisions changed. Later reconciles act on those, so they're
+// written right away. For example, a tag is only resolved the first time it's
+// seen, so if the digest weren't written, the next reconcile could resolve
+// the tag again to a different image that was pushed since.
+func decisionsChanged(oldStatus, newStatus *planetscalev2.VitessClusterStatus) bool {
+	return !apiequality.Semantic.DeepEqual(oldStatus.ResolvedImages, newStatus.ResolvedImages) ||
+		!apiequality.Semantic.DeepEqual(oldStatus.PinnedImages, newStatus.PinnedImages) ||
+		!apiequality.Semantic.DeepEqual(oldStatus.Upgrade, newStatus.Upgrade) ||
+		!apiequality.Semantic.DeepEqual(oldStatus.Rollout, newStatus.Rollout) ||
+		!apiequality.Semantic.DeepEqual(oldStatus.RolloutCoordinator, newStatus.RolloutCoordinator)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessclusterfederation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// membersProblem returns a description of what's wrong with the members of
+// a federation, or "" if nothing is.
+func membersProblem(spec *planetscalev2.VitessClusterFederationSpec) string {
+	names := sets.New[string]()
+	cellMembers := make(map[string]string)
+	for i := range spec.Members {
+		member := &spec.Members[i]
+		if names.Has(member.Name) {
+			return fmt.Sprintf("Member %v is listed more than once.", member.Name)
+		}
+		names.Insert(member.Name)
+		for _, cell := range member.Cells {
+			if spec.Cluster.Cell(cell) == nil {
+				return fmt.Sprintf("Cell %v of member %v isn't defined in the cluster template.", cell, member.Name)
+			}
+			if other, ok := cellMembers[cell]; ok && other != member.Name {
+				return fmt.Sprintf("Cell %v belongs to both member %v and member %v.", cell, other, member.Name)
+			}
+			cellMembers[cell] = member.Name
+		}
+	}
+	if spec.TopologyOwner != "" && !names.Has(spec.TopologyOwner) {
+		return fmt.Sprintf("Topology owner %v isn't a member.", spec.TopologyOwner)
+	}
+	return ""
+}
+
+// topologyOwner returns the name of the member that performs the actions on
+// keyspaces and shards that only one member may do.
+func topologyOwner(spec *planetscalev2.VitessClusterFederationSpec) string {
+	if spec.TopologyOwner != "" {
+		return spec.TopologyOwner
+	}
+	if len(spec.Members) == 0 {
+		return ""
+	}
+	return spec.Members[0].Name
+}
+
+// member looks up a member of a federation by name.
+// It returns nil if there's no such member.
+func member(spec *planetscalev2.VitessClusterFederationSpec, name string) *planetscalev2.VitessClusterFederationMember {
+	for i := range spec.Members {
+		if spec.Members[i].Name == name {
+			return &spec.Members[i]
+		}
+	}
+	return nil
+}
+
+// federationCells returns the sorted names of the cells that any member of a
+// federation deploys.
+func federationCells(spec *planetscalev2.VitessClusterFederationSpec) []string {
+	cells := sets.New[string]()
+	for i := range spec.Members {
+		cells.Insert(spec.Members[i].Cells...)
+	}
+	return sets.List(cells)
+}
+
+// memberClusterSpec returns the spec of the VitessCluster that deploys the
+// cells of one member of a federation.
+func memberClusterSpec(spec *planetscalev2.VitessClusterFederationSpec, member *planetscalev2.VitessClusterFederationMember) *planetscalev2.VitessClusterSpec {
+	cluster := spec.Cluster.DeepCopy()
+	cluster.GlobalLockserver = planetscalev2.LockserverSpec{External: spec.GlobalLockserver.DeepCopy()}
+
+	memberCells := sets.New(member.Cells...)
+	allCells := federationCells(spec)
+	for i := range cluster.ExternalCells {
+		if cluster.Cell(cluster.ExternalCells[i].Name) == nil {
+			allCells = append(allCells, cluster.ExternalCells[i].Name)
+		}
+	}
+	cells := make([]planetscalev2.VitessCellTemplate, 0, len(member.Cells))
+	for i := range cluster.Cells {
+		cell := &cluster.Cells[i]
+		if !memberCells.Has(cell.Name) {
+			continue
+		}
+		// Let vtgates reach the tablets of other members, like the primaries
+		// of shards in another region, just as they would in a VitessCluster
+		// that deploys all the cells.
+		if len(cell.Gateway.CellsToWatch) == 0 {
+			cell.Gateway.CellsToWatch = allCells
+		}
+		cells = append(cells, *cell)
+	}
+	cluster.Cells = cells
+
+	// Each member deploys the shards of every keyspace, with only the
+	// tablets in its own cells.
+	for i := range cluster.Keyspaces {
+		for _, shard := range shardTemplates(&cluster.Keyspaces[i]) {
+			shard.TabletPools = memberTabletPools(shard.TabletPools, memberCells)
+		}
+	}
+
+	// The cells aliases and the list of cells in the global lockserver are
+	// shared by all members, so they're managed for the whole federation.
+	planetscalev2.DefaultTopoReconcileConfig(&cluster.TopologyReconciliation)
+	cluster.TopologyReconciliation.RegisterCellsAliases = pointer.BoolPtr(false)
+	cluster.TopologyReconciliation.PruneCells = pointer.BoolPtr(false)
+	// Only one member may act on the keyspaces and shards they all share,
+	// or they'd race to elect primaries, apply users and run workflows.
+	cluster.TopologyReconciliation.ManageShards = pointer.BoolPtr(member.Name == topologyOwner(spec))
+
+	return cluster
+}
+
+// shardTemplates returns pointers to all the shard templates of a keyspace.
+func shardTemplates(keyspace *planetscalev2.VitessKeyspaceTemplate) []*planetscalev2.VitessShardTemplate {
+	var shards []*planetscalev2.VitessShardTemplate
+	for i := range keyspace.Partitionings {
+		partitioning := &keyspace.Partitionings[i]
+		if partitioning.Equal != nil {
+			shards = append(shards, &partitioning.Equal.ShardTemplate)
+		}
+		if partitioning.Custom != nil {
+			for j := range partitioning.Custom.Shards {
+				shards = append(shards, &partitioning.Custom.Shards[j].VitessShardTemplate)
+			}
+			if partitioning.Custom.ShardTemplate != nil {
+				shards = append(shards, partitioning.Custom.ShardTemplate)
+			}
+		}
+	}
+	return shards
+}
+
+// memberTabletPools returns the tablet pools that are in the given cells.
+func memberTabletPools(pools []planetscalev2.VitessShardTabletPool, cells sets.Set[string]) []planetscalev2.VitessShardTabletPool {
+	var kept []planetscalev2.VitessShardTabletPool
+	for i := range pools {
+		if cells.Has(pools[i].Cell) {
+			kept = append(kept, pools[i])
+		}
+	}
+	return kept
+}
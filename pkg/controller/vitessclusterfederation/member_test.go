@@ -0,0 +1,157 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessclusterfederation
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMemberClusterSpec(t *testing.T) {
+	spec := &planetscalev2.VitessClusterFederationSpec{
+		GlobalLockserver: planetscalev2.VitessLockserverParams{Implementation: "etcd2", Address: "global:2379", RootPath: "/vitess/global"},
+		Members: []planetscalev2.VitessClusterFederationMember{
+			{Name: "east", Cells: []string{"east1", "east2"}},
+			{Name: "west", Cells: []string{"west1"}},
+		},
+		Cluster: planetscalev2.VitessClusterSpec{
+			Cells: []planetscalev2.VitessCellTemplate{
+				{Name: "east1"},
+				{Name: "east2", Gateway: planetscalev2.VitessCellGatewaySpec{CellsToWatch: []string{"east2"}}},
+				{Name: "west1"},
+				{Name: "spare"},
+			},
+			Keyspaces: []planetscalev2.VitessKeyspaceTemplate{{
+				Name: "commerce",
+				Partitionings: []planetscalev2.VitessKeyspacePartitioning{{
+					Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{
+						Parts: 2,
+						ShardTemplate: planetscalev2.VitessShardTemplate{
+							TabletPools: []planetscalev2.VitessShardTabletPool{
+								{Cell: "east1", Type: planetscalev2.ReplicaPoolType, Replicas: 2},
+								{Cell: "west1", Type: planetscalev2.ReplicaPoolType, Replicas: 2},
+								{Cell: "west1", Type: planetscalev2.RdonlyPoolType, Replicas: 1},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}
+	if problem := membersProblem(spec); problem != "" {
+		t.Fatalf("membersProblem() = %q; want none", problem)
+	}
+
+	cluster := memberClusterSpec(spec, member(spec, "west"))
+	if cluster.GlobalLockserver.External == nil || *cluster.GlobalLockserver.External != spec.GlobalLockserver {
+		t.Errorf("GlobalLockserver.External = %+v; want %+v", cluster.GlobalLockserver.External, spec.GlobalLockserver)
+	}
+	if len(cluster.Cells) != 1 || cluster.Cells[0].Name != "west1" {
+		t.Fatalf("Cells = %+v; want only west1", cluster.Cells)
+	}
+	if got, want := cluster.Cells[0].Gateway.CellsToWatch, []string{"east1", "east2", "west1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CellsToWatch = %v; want %v", got, want)
+	}
+	pools := cluster.Keyspaces[0].Partitionings[0].Equal.ShardTemplate.TabletPools
+	if len(pools) != 2 || pools[0].Cell != "west1" || pools[1].Cell != "west1" {
+		t.Errorf("TabletPools = %+v; want only the pools in west1", pools)
+	}
+	if *cluster.TopologyReconciliation.RegisterCellsAliases || *cluster.TopologyReconciliation.PruneCells {
+		t.Errorf("TopologyReconciliation = %+v; want cells aliases and pruning left to the federation", cluster.TopologyReconciliation)
+	}
+
+	// The template itself is left alone.
+	if len(spec.Cluster.Keyspaces[0].Partitionings[0].Equal.ShardTemplate.TabletPools) != 3 {
+		t.Errorf("memberClusterSpec() modified the cluster template")
+	}
+
+	cluster = memberClusterSpec(spec, member(spec, "east"))
+	if got, want := cluster.Cells[1].Gateway.CellsToWatch, []string{"east2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CellsToWatch of east2 = %v; want %v", got, want)
+	}
+}
+
+func TestMemberClusterSpecTopologyOwner(t *testing.T) {
+	spec := &planetscalev2.VitessClusterFederationSpec{
+		Members: []planetscalev2.VitessClusterFederationMember{
+			{Name: "east", Cells: []string{"east1"}},
+			{Name: "west", Cells: []string{"west1"}},
+		},
+		Cluster: planetscalev2.VitessClusterSpec{
+			Cells: []planetscalev2.VitessCellTemplate{{Name: "east1"}, {Name: "west1"}},
+		},
+	}
+
+	// managers returns the members whose VitessCluster manages shards.
+	managers := func() []string {
+		var names []string
+		for i := range spec.Members {
+			cluster := memberClusterSpec(spec, &spec.Members[i])
+			if *cluster.TopologyReconciliation.ManageShards {
+				names = append(names, spec.Members[i].Name)
+			}
+		}
+		return names
+	}
+
+	// By default, the first member is the only one that acts on shards.
+	if got, want := managers(), []string{"east"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("members managing shards = %v; want %v", got, want)
+	}
+
+	spec.TopologyOwner = "west"
+	if got, want := managers(), []string{"west"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("members managing shards = %v; want %v", got, want)
+	}
+
+	// Even if the template asks every member to.
+	spec.Cluster.TopologyReconciliation = &planetscalev2.TopoReconcileConfig{ManageShards: pointer.BoolPtr(true)}
+	if got, want := managers(), []string{"west"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("members managing shards = %v; want %v", got, want)
+	}
+
+	spec.TopologyOwner = "north"
+	if problem := membersProblem(spec); problem == "" {
+		t.Errorf("membersProblem() = none; want a problem with unknown topology owner")
+	}
+}
+
+func TestMembersProblem(t *testing.T) {
+	cluster := planetscalev2.VitessClusterSpec{
+		Cells: []planetscalev2.VitessCellTemplate{{Name: "a"}, {Name: "b"}},
+	}
+	table := []struct {
+		members []planetscalev2.VitessClusterFederationMember
+		problem bool
+	}{
+		{[]planetscalev2.VitessClusterFederationMember{{Name: "x", Cells: []string{"a"}}, {Name: "y", Cells: []string{"b"}}}, false},
+		{[]planetscalev2.VitessClusterFederationMember{{Name: "x", Cells: []string{"a"}}, {Name: "x", Cells: []string{"b"}}}, true},
+		{[]planetscalev2.VitessClusterFederationMember{{Name: "x", Cells: []string{"a"}}, {Name: "y", Cells: []string{"a"}}}, true},
+		{[]planetscalev2.VitessClusterFederationMember{{Name: "x", Cells: []string{"c"}}}, true},
+	}
+
+	for _, test := range table {
+		spec := &planetscalev2.VitessClusterFederationSpec{Members: test.members, Cluster: cluster}
+		if got := membersProblem(spec); (got != "") != test.problem {
+			t.Errorf("membersProblem(%+v) = %q; want problem: %v", test.members, got, test.problem)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessclusterfederation
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesstopo"
+)
+
+const (
+	topoReconcileTimeout = 10 * time.Second
+	// topoRequeueDelay is how long to wait before retrying when a topology
+	// server call failed. We typically return success with a requeue delay
+	// instead of returning an error, because it's unlikely that retrying
+	// immediately will be worthwhile.
+	topoRequeueDelay = 5 * time.Second
+)
+
+// reconcileTopology registers the cells aliases for the cells of all members,
+// and prunes cells that no member deploys anymore. Only the topology owner
+// calls it, for the whole federation.
+func (r *ReconcileVitessClusterFederation) reconcileTopology(ctx context.Context, vtf *planetscalev2.VitessClusterFederation) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	if reconciler.IsPaused(ctx) || vtf.Spec.Cluster.DryRun {
+		return resultBuilder.Result()
+	}
+	conf := vtf.Spec.Cluster.TopologyReconciliation.DeepCopy()
+	planetscalev2.DefaultTopoReconcileConfig(&conf)
+	if !*conf.RegisterCellsAliases && !*conf.PruneCells {
+		return resultBuilder.Result()
+	}
+
+	desiredCells := make(map[string]*planetscalev2.LockserverSpec)
+	for _, name := range federationCells(&vtf.Spec) {
+		desiredCells[name] = &vtf.Spec.Cluster.Cell(name).Lockserver
+	}
+	for i := range vtf.Spec.Cluster.ExternalCells {
+		cell := &vtf.Spec.Cluster.ExternalCells[i]
+		if desiredCells[cell.Name] == nil {
+			desiredCells[cell.Name] = &planetscalev2.LockserverSpec{External: cell.Lockserver}
+		}
+	}
+
+	ts, err := toposerver.Open(ctx, r.client, vtf.Namespace, vtf.Spec.GlobalLockserver)
+	if err != nil {
+		r.recorder.Eventf(vtf, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	defer ts.Close()
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+	defer cancel()
+
+	if *conf.RegisterCellsAliases {
+		err := vitesstopo.RegisterCellsAliases(ctx, vitesstopo.RegisterCellsAliasesParams{
+			EventObj:     vtf,
+			TopoServer:   ts.Server,
+			Recorder:     r.recorder,
+			DesiredCells: desiredCells,
+		})
+		if err != nil {
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+	}
+
+	if *conf.PruneCells {
+		// Cells that still have keyspaces deployed in them can't be deleted,
+		// so an owner that hasn't caught up with a new cell yet can't break it.
+		result, err := vitesstopo.PruneCells(ctx, vitesstopo.PruneCellsParams{
+			EventObj:     vtf,
+			TopoServer:   ts.Server,
+			Recorder:     r.recorder,
+			DesiredCells: desiredCells,
+		})
+		resultBuilder.Merge(result, err)
+	}
+
+	return resultBuilder.Result()
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessclusterfederation
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+const (
+	controllerName = "vitessclusterfederation-controller"
+)
+
+var (
+	maxConcurrentReconciles = flag.Int("vitessclusterfederation_concurrent_reconciles", 10, "the maximum number of different vitessclusterfederations to reconcile concurrently")
+	resyncPeriod            = flag.Duration("vitessclusterfederation_resync_period", 30*time.Second, "reconcile vitessclusterfederations with this period even if no Kubernetes events occur")
+)
+
+var log = logrus.WithField("controller", "VitessClusterFederation")
+
+// Add creates a new VitessClusterFederation Controller and adds it to the Manager.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileVitessClusterFederation {
+	c := mgr.GetClient()
+	scheme := mgr.GetScheme()
+	recorder := mgr.GetEventRecorderFor(controllerName)
+
+	return &ReconcileVitessClusterFederation{
+		client:     c,
+		scheme:     scheme,
+		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder:   recorder,
+		reconciler: reconciler.New(c, scheme, recorder),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileVitessClusterFederation) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: *maxConcurrentReconciles,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource VitessClusterFederation
+	if err := c.Watch(&source.Kind{Type: &planetscalev2.VitessClusterFederation{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch for changes in the VitessCluster of this member.
+	if err := c.Watch(&source.Kind{Type: &planetscalev2.VitessCluster{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &planetscalev2.VitessClusterFederation{},
+	}); err != nil {
+		return err
+	}
+
+	// The cells of other members live in the global lockserver rather than in
+	// this Kubernetes cluster, so we periodically resync to keep up with them.
+	if err := c.Watch(r.resync.WatchSource(), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileVitessClusterFederation{}
+
+// ReconcileVitessClusterFederation reconciles a VitessClusterFederation object
+type ReconcileVitessClusterFederation struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client     client.Client
+	scheme     *runtime.Scheme
+	resync     *resync.Periodic
+	recorder   record.EventRecorder
+	reconciler *reconciler.Reconciler
+}
+
+// Reconcile deploys the cells of this operator's member of a
+// VitessClusterFederation in a VitessCluster, and manages the parts of the
+// global lockserver that all members share.
+func (r *ReconcileVitessClusterFederation) Reconcile(cctx context.Context, request reconcile.Request) (finalResult reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
+	defer cancel()
+
+	ctx, span := tracing.StartReconcile(ctx, "VitessClusterFederation", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
+	resultBuilder := &results.Builder{}
+
+	log := log.WithFields(logrus.Fields{
+		"namespace":               request.Namespace,
+		"vitessclusterfederation": request.Name,
+		logging.ReconcileIDField:  logging.NewReconcileID(),
+	})
+	ctx = logging.NewContext(ctx, log)
+	log.Debug("Reconciling VitessClusterFederation")
+
+	// Fetch the VitessClusterFederation instance.
+	vtf := &planetscalev2.VitessClusterFederation{}
+	if err := r.client.Get(ctx, request.NamespacedName, vtf); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Owned objects are automatically garbage collected.
+			return resultBuilder.Result()
+		}
+		// Error reading the object - requeue the request.
+		return resultBuilder.Error(err)
+	}
+
+	oldStatus := vtf.Status.DeepCopy()
+	vtf.Status = planetscalev2.VitessClusterFederationStatus{}
+	defer func() {
+		vtf.Status.ObservedGeneration = vtf.Generation
+		if equality.Semantic.DeepEqual(&vtf.Status, oldStatus) {
+			return
+		}
+		if err := r.client.Status().Update(ctx, vtf); err != nil {
+			if !apierrors.IsConflict(err) {
+				r.recorder.Eventf(vtf, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
+			}
+			finalResult, finalErr = resultBuilder.Error(err)
+		}
+	}()
+
+	if vtf.Spec.Cluster.Paused {
+		log.Info("Reconciliation is paused")
+		ctx = reconciler.NewPausedContext(ctx)
+	}
+
+	resultBuilder.Merge(r.reconcileMember(ctx, vtf))
+
+	// Request a periodic resync so the shared parts of the global lockserver
+	// are kept up to date even if no Kubernetes events occur.
+	r.resync.Enqueue(request.NamespacedName)
+
+	result, err := resultBuilder.Result()
+	reconcileCount.WithLabelValues(vtf.Name, metrics.Result(err)).Inc()
+	return result, err
+}
+
+func (r *ReconcileVitessClusterFederation) reconcileMember(ctx context.Context, vtf *planetscalev2.VitessClusterFederation) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	name := environment.FederationMember()
+	if name == "" {
+		vtf.Status.Message = "This operator isn't a federation member. Set its --federation_member flag to deploy the member's cells."
+		return resultBuilder.Result()
+	}
+	vtf.Status.Member = name
+	if problem := membersProblem(&vtf.Spec); problem != "" {
+		vtf.Status.Message = problem
+		return resultBuilder.Result()
+	}
+	member := member(&vtf.Spec, name)
+	if member == nil {
+		// A VitessCluster that this member deployed before is left alone,
+		// since deleting it would turn down all of its cells at once.
+		vtf.Status.Message = fmt.Sprintf("The federation has no member named %v.", name)
+		return resultBuilder.Result()
+	}
+	vtf.Status.Cells = member.Cells
+
+	if err := r.reconcileCluster(ctx, vtf, member); err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// The cells aliases and the list of cells in the global lockserver are
+	// shared by all members, so only the topology owner writes them.
+	// Otherwise, members that disagree about the cells, like while a change
+	// to the federation is applied in each Kubernetes cluster, would undo
+	// each other's writes, or prune a cell that another member just added.
+	if member.Name == topologyOwner(&vtf.Spec) {
+		topoResult, err := r.reconcileTopology(ctx, vtf)
+		resultBuilder.Merge(topoResult, err)
+	}
+
+	return resultBuilder.Result()
+}
+
+func (r *ReconcileVitessClusterFederation) reconcileCluster(ctx context.Context, vtf *planetscalev2.VitessClusterFederation, member *planetscalev2.VitessClusterFederationMember) error {
+	key := client.ObjectKey{Namespace: vtf.Namespace, Name: vtf.Name}
+	labels := map[string]string{
+		planetscalev2.FederationLabel: vtf.Name,
+	}
+
+	return r.reconciler.ReconcileObject(ctx, vtf, key, labels, true, reconciler.Strategy{
+		Kind: &planetscalev2.VitessCluster{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return newVitessCluster(key, vtf, labels, member)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*planetscalev2.VitessCluster)
+			// The VitessCluster has its own update strategy for rolling out
+			// changes to what it deploys, so its spec can be updated right away.
+			cluster := newVitessCluster(key, vtf, labels, member)
+			update.Labels(&newObj.Labels, cluster.Labels)
+			newObj.Spec = cluster.Spec
+		},
+		UpdatePaused: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*planetscalev2.VitessCluster)
+			newObj.Spec.Paused = vtf.Spec.Cluster.Paused
+		},
+	})
+}
+
+// newVitessCluster returns the VitessCluster that deploys the cells of one
+// member of a federation.
+func newVitessCluster(key client.ObjectKey, vtf *planetscalev2.VitessClusterFederation, parentLabels map[string]string, member *planetscalev2.VitessClusterFederationMember) *planetscalev2.VitessCluster {
+	// Copy the labels of the federation, so the VitessCluster is selected by
+	// the same --cluster_label_selector.
+	labels := make(map[string]string, len(vtf.Labels)+len(parentLabels))
+	for k, v := range vtf.Labels {
+		labels[k] = v
+	}
+	for k, v := range parentLabels {
+		labels[k] = v
+	}
+
+	return &planetscalev2.VitessCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    labels,
+		},
+		Spec: *memberClusterSpec(&vtf.Spec, member),
+	}
+}
@@ -17,8 +17,8 @@ limitations under the License.
 package vitesskeyspace
 
 import (
-	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"github.com/prometheus/client_golang/prometheus"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 )
 
 const (
@@ -18,9 +18,10 @@ package vitesskeyspace
 
 import (
 	"context"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,8 +30,12 @@ import (
 	"vitess.io/vitess/go/vt/wrangler"
 
 	v2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/statusupdate"
 	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/vtctldclient"
+	"planetscale.dev/vitess-operator/pkg/operator/webclient"
 )
 
 // reconcileHandler provides context for this specific reconcile loop,
@@ -39,6 +44,8 @@ type reconcileHandler struct {
 	client              client.Client
 	recorder            record.EventRecorder
 	reconciler          *reconciler.Reconciler
+	statusWriter        *statusupdate.Writer
+	webClient           *webclient.Client
 	vtk                 *v2.VitessKeyspace
 	oldStatus           *v2.VitessKeyspaceStatus
 	untouchedConditions map[v2.VitessKeyspaceConditionType]bool
@@ -48,6 +55,9 @@ type reconcileHandler struct {
 
 	// This field holds a Wrangler. Please don't try to access until you have run tsInit()
 	wr *wrangler.Wrangler
+	// This field holds a vtctld client backed by the Wrangler.
+	// Please don't try to access until you have run tsInit().
+	vtctld *vtctldclient.Client
 	// This field holds a tablet manager client internally for closing upon collection of reconcileHandler.
 	// Please don't try to access until you have run tsInit().
 	tmc tmclient.TabletManagerClient
@@ -61,11 +71,11 @@ func (r *reconcileHandler) tsInit(ctx context.Context) error {
 	}
 
 	// We need to initialize for the first time if we got here.
-	ts, err := toposerver.Open(ctx, r.vtk.Spec.GlobalLockserver)
+	ts, err := toposerver.Open(ctx, r.client, r.vtk.Namespace, r.vtk.Spec.GlobalLockserver)
 	if err != nil {
 		r.recorder.Eventf(r.vtk, v1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
 		// Give the lockserver some time to come up.
-		log.Info("Could not connect to topo at vitesskeyspace controller.")
+		logging.FromContext(ctx).Info("Could not connect to topo at vitesskeyspace controller.")
 		return err
 	}
 	r.ts = ts
@@ -78,6 +88,7 @@ func (r *reconcileHandler) tsInit(ctx context.Context) error {
 	// multi-step Vitess cluster management workflows.
 	wr := wrangler.New(logutil.NewConsoleLogger(), r.ts.Server, r.tmc)
 	r.wr = wr
+	r.vtctld = vtctldclient.NewLocal(wr.VtctldServer())
 
 	return nil
 }
@@ -89,28 +100,33 @@ func (r *reconcileHandler) close() {
 		r.ts.Close()
 	}
 
+	if r.vtctld != nil {
+		r.vtctld.Close()
+	}
+
 	if r.tmc != nil {
 		r.tmc.Close()
 	}
 }
 
-func (r *reconcileHandler) updateStatus(ctx context.Context) error {
+// updateStatus writes the keyspace's status, if it changed. It returns how
+// long to wait before writing it, if it was written too recently.
+func (r *reconcileHandler) updateStatus(ctx context.Context) (time.Duration, error) {
 	// Before updating status, set conditions we haven't touched to unknown.
 	for condition := range r.untouchedConditions {
 		r.setConditionStatus(condition, v1.ConditionUnknown, "ReconcileFailed", "Failed to determine status of the condition.")
 	}
 
 	r.vtk.Status.ObservedGeneration = r.vtk.Generation
-	if !equality.Semantic.DeepEqual(&r.vtk.Status, r.oldStatus) {
-		if err := r.client.Status().Update(ctx, r.vtk); err != nil {
-			if !errors.IsConflict(err) {
-				r.recorder.Eventf(r.vtk, v1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
-			}
-			return err
-		}
+	// Later reconciles act on the state of a reshard cutover, so changes to
+	// it are written right away.
+	urgent := r.oldStatus.ObservedGeneration != r.vtk.Generation ||
+		!apiequality.Semantic.DeepEqual(r.oldStatus.Cutover, r.vtk.Status.Cutover)
+	delay, err := r.statusWriter.Update(ctx, r.vtk, &r.vtk.Status, r.oldStatus, urgent)
+	if err != nil && !errors.IsConflict(err) {
+		r.recorder.Eventf(r.vtk, v1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
 	}
-
-	return nil
+	return delay, err
 }
 
 func (r *reconcileHandler) setConditionStatus(condType v2.VitessKeyspaceConditionType, newStatus v1.ConditionStatus, reason, message string) {
@@ -119,3 +135,21 @@ func (r *reconcileHandler) setConditionStatus(condType v2.VitessKeyspaceConditio
 
 	r.vtk.Status.SetConditionStatus(condType, newStatus, reason, message)
 }
+
+// sharedConditions are the conditions reported by the actions on the shared
+// state of the keyspace, which only the instance that manages shards takes.
+var sharedConditions = []v2.VitessKeyspaceConditionType{
+	v2.VitessKeyspaceDurabilityPolicyApplied,
+	v2.VitessKeyspaceQueryServingApplied,
+	v2.VitessKeyspaceReshardProgressing,
+	v2.VitessKeyspaceMaterializeInSync,
+	v2.VitessKeyspaceLookupVindexesReady,
+}
+
+// skipSharedConditions reports that this instance leaves the actions on the
+// shared state of the keyspace to another one.
+func (r *reconcileHandler) skipSharedConditions() {
+	for _, condition := range sharedConditions {
+		r.setConditionStatus(condition, v1.ConditionUnknown, "NotManaged", "Another operator instance, like the topology owner of the federation, manages the shared state of this keyspace.")
+	}
+}
@@ -26,7 +26,9 @@ import (
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	"vitess.io/vitess/go/vt/topo"
 
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vtctldclient"
 )
 
 func (r *reconcileHandler) reconcileKeyspaceInformation(ctx context.Context) (reconcile.Result, error) {
@@ -43,16 +45,29 @@ func (r *reconcileHandler) reconcileKeyspaceInformation(ctx context.Context) (re
 	topoServer := r.ts.Server
 	keyspaceName := r.vtk.Spec.Name
 	durabilityPolicy := r.vtk.Spec.DurabilityPolicy
+
+	// Don't apply a durability policy that some shard can't satisfy, since
+	// its primary would then wait forever for semi-sync acks.
+	problem := r.vtk.Spec.DurabilityPolicyProblem()
+	if problem != "" {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "DurabilityPolicyInvalid", "not applying durability policy: %v", problem)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceDurabilityPolicyApplied, corev1.ConditionFalse, "InsufficientTablets", problem)
+		durabilityPolicy = ""
+	}
+
 	keyspaceInfo, err := topoServer.GetKeyspace(ctx, keyspaceName)
 	if err != nil {
 		// The keyspace information record does not exist in the topo server.
 		// We should create the record
-		if !topo.IsErrType(err, topo.NoNode) {
+		if topo.IsErrType(err, topo.NoNode) {
 			// Create a normal keyspace with the requested durability policy
-			_, err := r.wr.VtctldServer().CreateKeyspace(ctx, &vtctldatapb.CreateKeyspaceRequest{
-				Name:             keyspaceName,
-				Type:             topodatapb.KeyspaceType_NORMAL,
-				DurabilityPolicy: durabilityPolicy,
+			err := vtctldclient.Call(ctx, func(ctx context.Context) error {
+				_, err := r.vtctld.CreateKeyspace(ctx, &vtctldatapb.CreateKeyspaceRequest{
+					Name:             keyspaceName,
+					Type:             topodatapb.KeyspaceType_NORMAL,
+					DurabilityPolicy: durabilityPolicy,
+				})
+				return err
 			})
 			if err != nil {
 				resultBuilder.Error(err)
@@ -65,17 +80,31 @@ func (r *reconcileHandler) reconcileKeyspaceInformation(ctx context.Context) (re
 		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "GetKeyspace", "failed to get keyspace %v: %v", keyspaceName, err)
 		return resultBuilder.RequeueAfter(topoRequeueDelay)
 	}
+	r.vtk.Status.DurabilityPolicy = keyspaceInfo.DurabilityPolicy
 
-	// DurabilityPolicy doesn't match the one requested by the user
-	// We change the durability policy using the SetKeyspaceDurabilityPolicy rpc
-	if durabilityPolicy != "" && keyspaceInfo.DurabilityPolicy != durabilityPolicy {
-		_, err := r.wr.VtctldServer().SetKeyspaceDurabilityPolicy(ctx, &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
-			Keyspace:         keyspaceName,
-			DurabilityPolicy: durabilityPolicy,
+	switch {
+	case problem != "":
+	case durabilityPolicy == "":
+		r.setConditionStatus(planetscalev2.VitessKeyspaceDurabilityPolicyApplied, corev1.ConditionTrue, "NotRequested", "The keyspace doesn't request a durability policy.")
+	case keyspaceInfo.DurabilityPolicy == durabilityPolicy:
+		r.setConditionStatus(planetscalev2.VitessKeyspaceDurabilityPolicyApplied, corev1.ConditionTrue, "Applied", "")
+	default:
+		// DurabilityPolicy doesn't match the one requested by the user
+		// We change the durability policy using the SetKeyspaceDurabilityPolicy rpc
+		err := vtctldclient.Call(ctx, func(ctx context.Context) error {
+			_, err := r.vtctld.SetKeyspaceDurabilityPolicy(ctx, &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace:         keyspaceName,
+				DurabilityPolicy: durabilityPolicy,
+			})
+			return err
 		})
 		if err != nil {
-			resultBuilder.Error(err)
+			r.setConditionStatus(planetscalev2.VitessKeyspaceDurabilityPolicyApplied, corev1.ConditionFalse, "UpdateFailed", err.Error())
+			return resultBuilder.Error(err)
 		}
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "DurabilityPolicyChanged", "Changed durability policy from %q to %q", keyspaceInfo.DurabilityPolicy, durabilityPolicy)
+		r.vtk.Status.DurabilityPolicy = durabilityPolicy
+		r.setConditionStatus(planetscalev2.VitessKeyspaceDurabilityPolicyApplied, corev1.ConditionTrue, "Applied", "")
 	}
 	return resultBuilder.Result()
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	"vitess.io/vitess/go/vt/topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vreplication"
+)
+
+// reconcileLookupVindexes drives each vindex requested in
+// spec.lookupVindexes through the CreateLookupVindex workflow, from
+// backfilling its lookup table to externalizing it.
+func (r *reconcileHandler) reconcileLookupVindexes(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	specs := r.vtk.Spec.LookupVindexes
+	if len(specs) == 0 {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceLookupVindexesReady, corev1.ConditionTrue, "NotRequested", "The keyspace doesn't manage lookup vindexes.")
+		return resultBuilder.Result()
+	}
+
+	err := r.tsInit(ctx)
+	if err != nil {
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	vschema, err := r.ts.Server.GetVSchema(ctx, r.vtk.Spec.Name)
+	if err != nil {
+		if !topo.IsErrType(err, topo.NoNode) {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "TopoGetFailed", "failed to get VSchema: %v", err)
+			r.setConditionStatus(planetscalev2.VitessKeyspaceLookupVindexesReady, corev1.ConditionUnknown, "GetVSchemaFailed", err.Error())
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		vschema = &vschemapb.Keyspace{}
+	}
+
+	var notReady []string
+	for i := range specs {
+		status := r.reconcileLookupVindex(ctx, vschema, &specs[i])
+		r.vtk.Status.LookupVindexes = append(r.vtk.Status.LookupVindexes, status)
+		if status.State != planetscalev2.LookupVindexExternalized {
+			notReady = append(notReady, fmt.Sprintf("%v is %v", status.Name, status.State))
+		}
+	}
+
+	if len(notReady) > 0 {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceLookupVindexesReady, corev1.ConditionFalse, "NotExternalized", strings.Join(notReady, "; "))
+		return resultBuilder.Result()
+	}
+	r.setConditionStatus(planetscalev2.VitessKeyspaceLookupVindexesReady, corev1.ConditionTrue, "Externalized", "")
+	return resultBuilder.Result()
+}
+
+// reconcileLookupVindex takes one step towards creating a lookup vindex,
+// and reports on where it's at.
+//
+// The VSchema tells us which step we're at: a missing vindex hasn't been
+// created yet, and a write-only one is still being backfilled.
+func (r *reconcileHandler) reconcileLookupVindex(ctx context.Context, vschema *vschemapb.Keyspace, spec *planetscalev2.VitessLookupVindexSpec) planetscalev2.LookupVindexStatus {
+	keyspaceName := r.vtk.Spec.Name
+	// The backfill workflow runs in the keyspace of the lookup table.
+	lookupKeyspace := strings.SplitN(spec.LookupTable, ".", 2)[0]
+	status := planetscalev2.LookupVindexStatus{
+		Name:     spec.Name,
+		Workflow: vreplication.LookupVindexWorkflow(spec),
+	}
+
+	vindex := vschema.GetVindexes()[spec.Name]
+	if vindex == nil {
+		err := r.wr.CreateLookupVindex(ctx, keyspaceName, vreplication.LookupVindexSpecs(spec), strings.Join(spec.Cells, ","), vreplication.SourceTabletTypes, false /* continueAfterCopyWithOwner */)
+		if err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "CreateLookupVindexFailed", "failed to create lookup vindex %v: %v", spec.Name, err)
+			status.State = planetscalev2.LookupVindexPending
+			status.Message = fmt.Sprintf("Failed to create the vindex: %v", err)
+			return status
+		}
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "LookupVindexCreated", "Created lookup vindex %v and started backfilling %v.", spec.Name, spec.LookupTable)
+		status.State = planetscalev2.LookupVindexBackfilling
+		status.Message = "The lookup table is being backfilled."
+		return status
+	}
+	if vindex.GetParams()["write_only"] != "true" {
+		status.State = planetscalev2.LookupVindexExternalized
+		return status
+	}
+
+	status.State = planetscalev2.LookupVindexBackfilling
+	workflow, err := r.wr.ShowWorkflow(ctx, status.Workflow, lookupKeyspace)
+	if err != nil {
+		// Without the workflow, we can't tell whether the backfill is done,
+		// so we must not externalize the vindex.
+		status.Message = fmt.Sprintf("Can't check the backfill workflow: %v", err)
+		return status
+	}
+	status.Streams = vreplication.Streams(workflow, time.Now())
+	vreplication.ReportMetrics(r.vtk.Labels[planetscalev2.ClusterLabel], lookupKeyspace, status.Workflow, status.Streams)
+	state, errorMsgs := vreplication.WorkflowState(workflow)
+	switch state {
+	case planetscalev2.WorkflowError:
+		sort.Strings(errorMsgs)
+		status.State = planetscalev2.LookupVindexError
+		status.Message = fmt.Sprintf("VReplication reported an error: %v", errorMsgs[0])
+		return status
+	case planetscalev2.WorkflowCopying:
+		status.Message = "The lookup table is being backfilled."
+		return status
+	}
+
+	// ExternalizeVindex checks that every stream is done copying, and
+	// deletes the workflow if the vindex is owned.
+	if err := r.wr.ExternalizeVindex(ctx, keyspaceName+"."+spec.Name); err != nil {
+		status.Message = fmt.Sprintf("Not ready to externalize: %v", err)
+		return status
+	}
+	r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "LookupVindexExternalized", "Externalized lookup vindex %v.", spec.Name)
+	status.State = planetscalev2.LookupVindexExternalized
+	status.Message = ""
+	status.Streams = nil
+	vreplication.ForgetMetrics(r.vtk.Labels[planetscalev2.ClusterLabel], lookupKeyspace, status.Workflow)
+	return status
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vreplication"
+)
+
+// reconcileMaterialize creates the Materialize workflow requested in
+// spec.materialize, and reports whether it's caught up.
+func (r *reconcileHandler) reconcileMaterialize(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	clusterName := r.vtk.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := r.vtk.Spec.Name
+	spec := r.vtk.Spec.Materialize
+	if old := r.oldStatus.Materialize; old != nil && (spec == nil || spec.Workflow != old.Workflow) {
+		vreplication.ForgetMetrics(clusterName, keyspaceName, old.Workflow)
+	}
+	if spec == nil {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "NotRequested", "The keyspace doesn't manage a Materialize workflow.")
+		return resultBuilder.Result()
+	}
+	if spec.SourceKeyspace == keyspaceName {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "InvalidSpec", "sourceKeyspace must be a different keyspace.")
+		return resultBuilder.Result()
+	}
+
+	err := r.tsInit(ctx)
+	if err != nil {
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	workflows, err := r.wr.ListAllWorkflows(ctx, keyspaceName, false /* include stopped workflows */)
+	if err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ListAllWorkflowsFailed", "failed to list all workflows: %v", err)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionUnknown, "ListWorkflowsFailed", err.Error())
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	exists := false
+	for _, name := range workflows {
+		if name == spec.Workflow {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return r.startMaterialize(ctx, spec)
+	}
+
+	workflow, err := r.wr.ShowWorkflow(ctx, spec.Workflow, keyspaceName)
+	if err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ShowWorkflowFailed", "failed to show workflow %v: %v", spec.Workflow, err)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionUnknown, "ShowWorkflowFailed", err.Error())
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	state, errorMsgs := vreplication.WorkflowState(workflow)
+	r.vtk.Status.Materialize = &planetscalev2.MaterializeStatus{
+		Workflow:                  spec.Workflow,
+		State:                     state,
+		MaxVReplicationLagSeconds: workflow.MaxVReplicationLag,
+		Streams:                   vreplication.Streams(workflow, time.Now()),
+	}
+	vreplication.ReportMetrics(clusterName, keyspaceName, spec.Workflow, r.vtk.Status.Materialize.Streams)
+
+	switch state {
+	case planetscalev2.WorkflowError:
+		sort.Strings(errorMsgs)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "Error", fmt.Sprintf("VReplication reported an error: %v", errorMsgs[0]))
+	case planetscalev2.WorkflowCopying:
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "Copying", "Existing data from the source keyspace is being copied.")
+	case planetscalev2.WorkflowRunning:
+		if workflow.MaxVReplicationLag < maxSafeVReplicationLag {
+			r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionTrue, "CaughtUp", fmt.Sprintf("The materialized tables are within %v seconds of the source keyspace.", maxSafeVReplicationLag))
+		} else {
+			r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "Lagging", fmt.Sprintf("The materialized tables are %v or more seconds behind the source keyspace.", maxSafeVReplicationLag))
+		}
+	default:
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionUnknown, "UnknownWorkflowState", fmt.Sprintf("VReplication workflow %v is in an unknown state.", spec.Workflow))
+	}
+	return resultBuilder.Result()
+}
+
+// startMaterialize creates the workflow once every shard of the keyspace has
+// a primary.
+func (r *reconcileHandler) startMaterialize(ctx context.Context, spec *planetscalev2.VitessKeyspaceMaterializeSpec) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	topoServer := r.ts.Server
+	keyspaceName := r.vtk.Spec.Name
+
+	shardsCtx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+	defer cancel()
+
+	shards, err := topoServer.GetShardNames(shardsCtx, keyspaceName)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "TopoGetFailed", "failed to list shards: %v", err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	notReady := len(shards) == 0
+	for _, shard := range shards {
+		shardInfo, err := topoServer.GetShard(shardsCtx, keyspaceName, shard)
+		if err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard %v: %v", shard, err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		if !shardInfo.HasPrimary() {
+			notReady = true
+		}
+	}
+	if notReady {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "WaitingForShards", "Waiting for every shard of the keyspace to have a primary.")
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	if err := r.wr.Materialize(ctx, vreplication.MaterializeSettings(keyspaceName, spec)); err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "MaterializeCreateFailed", "failed to create workflow %v: %v", spec.Workflow, err)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "CreateFailed", err.Error())
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "MaterializeCreated", "Created workflow %v from keyspace %v.", spec.Workflow, spec.SourceKeyspace)
+	r.setConditionStatus(planetscalev2.VitessKeyspaceMaterializeInSync, corev1.ConditionFalse, "Created", "The workflow is copying data from the source keyspace.")
+	return resultBuilder.Result()
+}
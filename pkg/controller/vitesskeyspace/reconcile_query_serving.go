@@ -0,0 +1,215 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/key"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vtctldclient"
+)
+
+// queryServiceChange is a change of the query service state of one tablet
+// type of one shard, in some cells.
+type queryServiceChange struct {
+	shard      string
+	tabletType topodatapb.TabletType
+	disable    bool
+	cells      []string
+}
+
+// reconcileQueryServing makes the tablet controls in each cell's serving
+// graph match spec.queryServing. Tablets watch their SrvKeyspace, so they
+// start or stop serving without being restarted.
+//
+// Removing spec.queryServing restores query service everywhere, as if it
+// listed nothing, and only then stops managing the tablet controls.
+func (r *reconcileHandler) reconcileQueryServing(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	queryServing := r.vtk.Spec.QueryServing
+	if queryServing == nil {
+		if !queryServingManaged(r.oldStatus) {
+			r.setConditionStatus(planetscalev2.VitessKeyspaceQueryServingApplied, corev1.ConditionTrue, "NotRequested", "The keyspace doesn't manage query serving.")
+			return resultBuilder.Result()
+		}
+		queryServing = &planetscalev2.VitessKeyspaceQueryServing{}
+	}
+	if r.vtk.Status.Resharding != nil {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceQueryServingApplied, corev1.ConditionFalse, "ReshardingActive", "Tablet controls are managed by the resharding workflow until it completes.")
+		return resultBuilder.Result()
+	}
+
+	err := r.tsInit(ctx)
+	if err != nil {
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	topoServer := r.ts.Server
+	keyspaceName := r.vtk.Spec.Name
+
+	cells, err := topoServer.GetCellInfoNames(ctx)
+	if err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "GetCellInfoNames", "failed to list cells: %v", err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	// disabled maps tablet types to the cells in which they shouldn't serve.
+	disabled := map[topodatapb.TabletType]map[string]bool{}
+	var unknownCells []string
+	for _, control := range queryServing.Disabled {
+		tabletType, err := topoproto.ParseTabletType(control.TabletType)
+		if err != nil {
+			// This should have been caught by CRD validation.
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "InvalidQueryServing", "ignoring invalid tablet type %q: %v", control.TabletType, err)
+			continue
+		}
+		if disabled[tabletType] == nil {
+			disabled[tabletType] = map[string]bool{}
+		}
+		for _, cell := range control.Cells {
+			disabled[tabletType][cell] = true
+			if !topo.InCellList(cell, cells) {
+				unknownCells = append(unknownCells, cell)
+			}
+		}
+	}
+
+	srvKeyspaces := make(map[string]*topodatapb.SrvKeyspace, len(cells))
+	for _, cell := range cells {
+		srvKeyspace, err := topoServer.GetSrvKeyspace(ctx, cell, keyspaceName)
+		if err != nil {
+			if topo.IsErrType(err, topo.NoNode) {
+				// The keyspace isn't served in this cell yet.
+				continue
+			}
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "GetSrvKeyspace", "failed to get serving graph of keyspace %v in cell %v: %v", keyspaceName, cell, err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		srvKeyspaces[cell] = srvKeyspace
+	}
+
+	var failures []string
+	for _, change := range queryServiceChanges(srvKeyspaces, disabled) {
+		err := vtctldclient.Call(ctx, func(ctx context.Context) error {
+			_, err := r.vtctld.SetShardTabletControl(ctx, &vtctldatapb.SetShardTabletControlRequest{
+				Keyspace:            keyspaceName,
+				Shard:               change.shard,
+				TabletType:          change.tabletType,
+				Cells:               change.cells,
+				DisableQueryService: change.disable,
+			})
+			return err
+		})
+		action, done := "enable", "Enabled"
+		if change.disable {
+			action, done = "disable", "Disabled"
+		}
+		tabletType := topoproto.TabletTypeLString(change.tabletType)
+		if err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "SetShardTabletControlFailed", "failed to %v query service for %v tablets of shard %v in cells %v: %v", action, tabletType, change.shard, strings.Join(change.cells, ","), err)
+			failures = append(failures, fmt.Sprintf("%v/%v: %v", change.shard, tabletType, err))
+			continue
+		}
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "QueryServiceChanged", "%v query service for %v tablets of shard %v in cells %v", done, tabletType, change.shard, strings.Join(change.cells, ","))
+	}
+
+	switch {
+	case len(failures) > 0:
+		r.setConditionStatus(planetscalev2.VitessKeyspaceQueryServingApplied, corev1.ConditionFalse, "UpdateFailed", strings.Join(failures, "; "))
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	case len(unknownCells) > 0:
+		r.setConditionStatus(planetscalev2.VitessKeyspaceQueryServingApplied, corev1.ConditionFalse, "UnknownCells", fmt.Sprintf("Cells not found in topology: %v", strings.Join(unknownCells, ",")))
+	case r.vtk.Spec.QueryServing == nil:
+		r.setConditionStatus(planetscalev2.VitessKeyspaceQueryServingApplied, corev1.ConditionTrue, "NotRequested", "Query service was restored everywhere, and the keyspace no longer manages query serving.")
+	default:
+		r.setConditionStatus(planetscalev2.VitessKeyspaceQueryServingApplied, corev1.ConditionTrue, "Applied", "")
+	}
+	return resultBuilder.Result()
+}
+
+// queryServingManaged returns whether the last reconcile applied a
+// spec.queryServing, or hadn't finished restoring query service after it
+// was removed.
+func queryServingManaged(oldStatus *planetscalev2.VitessKeyspaceStatus) bool {
+	cond, ok := oldStatus.GetCondition(planetscalev2.VitessKeyspaceQueryServingApplied)
+	return ok && cond.Reason != "NotRequested" && cond.Reason != "NotManaged"
+}
+
+// queryServiceChanges compares each cell's serving graph against the cells
+// in which each tablet type should have its query service disabled, and
+// groups the changes to make by shard, so the keyspace is locked as few
+// times as possible.
+func queryServiceChanges(srvKeyspaces map[string]*topodatapb.SrvKeyspace, disabled map[topodatapb.TabletType]map[string]bool) []*queryServiceChange {
+	changes := map[string]*queryServiceChange{}
+	cells := make([]string, 0, len(srvKeyspaces))
+	for cell := range srvKeyspaces {
+		cells = append(cells, cell)
+	}
+	sort.Strings(cells)
+	for _, cell := range cells {
+		for _, partition := range srvKeyspaces[cell].GetPartitions() {
+			tabletType := partition.GetServedType()
+			disable := disabled[tabletType][cell]
+			for _, shard := range partition.GetShardReferences() {
+				if queryServiceDisabled(partition, shard.GetKeyRange()) == disable {
+					continue
+				}
+				changeKey := fmt.Sprintf("%v/%v/%v", shard.GetName(), tabletType, disable)
+				change := changes[changeKey]
+				if change == nil {
+					change = &queryServiceChange{shard: shard.GetName(), tabletType: tabletType, disable: disable}
+					changes[changeKey] = change
+				}
+				change.cells = append(change.cells, cell)
+			}
+		}
+	}
+
+	changeKeys := make([]string, 0, len(changes))
+	for changeKey := range changes {
+		changeKeys = append(changeKeys, changeKey)
+	}
+	sort.Strings(changeKeys)
+	sorted := make([]*queryServiceChange, 0, len(changeKeys))
+	for _, changeKey := range changeKeys {
+		sorted = append(sorted, changes[changeKey])
+	}
+	return sorted
+}
+
+// queryServiceDisabled returns whether a serving partition disables query
+// service for the shard with the given key range.
+func queryServiceDisabled(partition *topodatapb.SrvKeyspace_KeyspacePartition, keyRange *topodatapb.KeyRange) bool {
+	for _, tabletControl := range partition.GetShardTabletControls() {
+		if key.KeyRangeEqual(tabletControl.GetKeyRange(), keyRange) {
+			return tabletControl.GetQueryServiceDisabled()
+		}
+	}
+	return false
+}
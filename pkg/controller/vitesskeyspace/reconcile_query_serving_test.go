@@ -0,0 +1,155 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// testSrvKeyspace returns a serving graph with shards -80 and 80- for the
+// primary and replica types, where the query service of the listed tablet
+// types is disabled.
+func testSrvKeyspace(disabled ...topodatapb.TabletType) *topodatapb.SrvKeyspace {
+	shards := []*topodatapb.ShardReference{
+		{Name: "-80", KeyRange: &topodatapb.KeyRange{End: []byte{0x80}}},
+		{Name: "80-", KeyRange: &topodatapb.KeyRange{Start: []byte{0x80}}},
+	}
+	srvKeyspace := &topodatapb.SrvKeyspace{}
+	for _, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA} {
+		partition := &topodatapb.SrvKeyspace_KeyspacePartition{
+			ServedType:      tabletType,
+			ShardReferences: shards,
+		}
+		for _, d := range disabled {
+			if d != tabletType {
+				continue
+			}
+			for _, shard := range shards {
+				partition.ShardTabletControls = append(partition.ShardTabletControls, &topodatapb.ShardTabletControl{
+					Name:                 shard.Name,
+					KeyRange:             shard.KeyRange,
+					QueryServiceDisabled: true,
+				})
+			}
+		}
+		srvKeyspace.Partitions = append(srvKeyspace.Partitions, partition)
+	}
+	return srvKeyspace
+}
+
+func TestQueryServiceChanges(t *testing.T) {
+	replica := topodatapb.TabletType_REPLICA
+
+	table := []struct {
+		name         string
+		srvKeyspaces map[string]*topodatapb.SrvKeyspace
+		disabled     map[topodatapb.TabletType]map[string]bool
+		want         []string
+	}{
+		{
+			name: "nothing to do",
+			srvKeyspaces: map[string]*topodatapb.SrvKeyspace{
+				"cell1": testSrvKeyspace(),
+				"cell2": testSrvKeyspace(replica),
+			},
+			disabled: map[topodatapb.TabletType]map[string]bool{replica: {"cell2": true}},
+		},
+		{
+			name: "disable added",
+			srvKeyspaces: map[string]*topodatapb.SrvKeyspace{
+				"cell1": testSrvKeyspace(),
+				"cell2": testSrvKeyspace(),
+				"cell3": testSrvKeyspace(),
+			},
+			disabled: map[topodatapb.TabletType]map[string]bool{replica: {"cell1": true, "cell3": true}},
+			want: []string{
+				"-80 REPLICA disable [cell1 cell3]",
+				"80- REPLICA disable [cell1 cell3]",
+			},
+		},
+		{
+			name: "disable removed",
+			srvKeyspaces: map[string]*topodatapb.SrvKeyspace{
+				"cell1": testSrvKeyspace(replica),
+				"cell2": testSrvKeyspace(replica),
+			},
+			disabled: map[topodatapb.TabletType]map[string]bool{replica: {"cell2": true}},
+			want: []string{
+				"-80 REPLICA enable [cell1]",
+				"80- REPLICA enable [cell1]",
+			},
+		},
+		{
+			name: "queryServing removed",
+			srvKeyspaces: map[string]*topodatapb.SrvKeyspace{
+				"cell1": testSrvKeyspace(replica, topodatapb.TabletType_PRIMARY),
+				"cell2": testSrvKeyspace(replica),
+			},
+			want: []string{
+				"-80 PRIMARY enable [cell1]",
+				"-80 REPLICA enable [cell1 cell2]",
+				"80- PRIMARY enable [cell1]",
+				"80- REPLICA enable [cell1 cell2]",
+			},
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, change := range queryServiceChanges(test.srvKeyspaces, test.disabled) {
+				action := "enable"
+				if change.disable {
+					action = "disable"
+				}
+				got = append(got, fmt.Sprintf("%v %v %v %v", change.shard, change.tabletType, action, change.cells))
+			}
+			if strings.Join(got, "; ") != strings.Join(test.want, "; ") {
+				t.Errorf("queryServiceChanges() = %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestQueryServingManaged(t *testing.T) {
+	table := []struct {
+		reason string
+		want   bool
+	}{
+		{reason: "", want: false},
+		{reason: "NotRequested", want: false},
+		{reason: "NotManaged", want: false},
+		{reason: "Applied", want: true},
+		{reason: "UpdateFailed", want: true},
+		{reason: "ReshardingActive", want: true},
+	}
+	for _, test := range table {
+		status := &planetscalev2.VitessKeyspaceStatus{}
+		if test.reason != "" {
+			status.SetConditionStatus(planetscalev2.VitessKeyspaceQueryServingApplied, corev1.ConditionTrue, test.reason, "")
+		}
+		if got := queryServingManaged(status); got != test.want {
+			t.Errorf("queryServingManaged() with reason %q = %v; want %v", test.reason, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/key"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+const (
+	// reshardSwitchTimeout is how long to wait for the target shards to
+	// catch up while switching primary traffic. These match the defaults of
+	// `vtctlclient Reshard SwitchTraffic`.
+	reshardSwitchTimeout = 30 * time.Second
+	// reshardSourceTabletTypes are the tablet types that the workflow may
+	// copy from, in order of preference.
+	reshardSourceTabletTypes = "in_order:REPLICA,PRIMARY"
+	// reshardSwitchTabletTypes are the tablet types whose traffic is switched,
+	// in order.
+	reshardSwitchTabletTypes = "in_order:RDONLY,REPLICA,PRIMARY"
+)
+
+// reconcileReshard moves the Reshard workflow requested in spec.reshard to
+// its next step: create it, switch traffic, or complete it.
+func (r *reconcileHandler) reconcileReshard(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	spec := r.vtk.Spec.Reshard
+	if spec == nil {
+		r.vtk.Status.Cutover = nil
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "NotRequested", "The keyspace doesn't manage a Reshard workflow.")
+		return resultBuilder.Result()
+	}
+	if err := validateReshard(spec); err != nil {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "InvalidSpec", err.Error())
+		return resultBuilder.Result()
+	}
+	cutover := r.trackCutover(spec)
+
+	err := r.tsInit(ctx)
+	if err != nil {
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	keyspaceName := r.vtk.Spec.Name
+
+	params := &wrangler.VReplicationWorkflowParams{
+		WorkflowType:   wrangler.ReshardWorkflow,
+		Workflow:       spec.Workflow,
+		TargetKeyspace: keyspaceName,
+	}
+	vrw, err := r.wr.NewVReplicationWorkflow(ctx, wrangler.ReshardWorkflow, params)
+	if err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "GetWorkflowFailed", "failed to get state of workflow %v: %v", spec.Workflow, err)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionUnknown, "GetWorkflowFailed", err.Error())
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	if !vrw.Exists() {
+		return r.startReshard(ctx, spec, cutover, vrw)
+	}
+
+	state := vrw.CachedState()
+	if state == wrangler.WorkflowStateAllSwitched {
+		if r.watchRollback(ctx, spec, cutover, vrw, params) {
+			return resultBuilder.Result()
+		}
+		if !spec.Complete {
+			r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "TrafficSwitched", "All traffic is served by the target shards. Set complete to delete the source shards.")
+			return resultBuilder.Result()
+		}
+		if _, err := vrw.Complete(); err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ReshardCompleteFailed", "failed to complete workflow %v: %v", spec.Workflow, err)
+			r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "CompleteFailed", err.Error())
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		completeTime := metav1.NewTime(time.Now())
+		cutover.CompleteTime = &completeTime
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "ReshardCompleted", "Completed workflow %v and deleted source shards %v.", spec.Workflow, strings.Join(spec.SourceShards, ","))
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "Completed", "The workflow completed. The source shards are turned down once they're idle.")
+		return resultBuilder.Result()
+	}
+
+	if !spec.SwitchTraffic {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "WaitingToSwitch", fmt.Sprintf("Workflow state: %v. Set switchTraffic to move traffic to the target shards.", state))
+		return resultBuilder.Result()
+	}
+	if cutover.RollbackTime != nil {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "RolledBack", fmt.Sprintf("Traffic was switched back: %v. Set switchTraffic to false and back to true to try again.", cutover.RollbackReason))
+		return resultBuilder.Result()
+	}
+	if reason, message := r.switchBlocked(spec, cutover); reason != "" {
+		status := corev1.ConditionFalse
+		if reason == "CatchingUp" {
+			status = corev1.ConditionTrue
+		}
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, status, reason, message)
+		return resultBuilder.Result()
+	}
+	maxSwitchLag := reshardSwitchTimeout
+	if spec.Guardrails != nil && spec.Guardrails.MaxReplicationLag != nil {
+		maxSwitchLag = spec.Guardrails.MaxReplicationLag.Duration
+	}
+
+	// Switching primary traffic briefly blocks writes, so only do it inside a maintenance window.
+	windows, now := r.vtk.Spec.UpdateStrategy.MaintenanceWindows, time.Now()
+	if open, _ := maintenance.Open(windows, now); !open {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "WaitingForMaintenanceWindow", fmt.Sprintf("Not switching traffic: %v.", maintenance.Describe(windows, now)))
+		return resultBuilder.Result()
+	}
+
+	// Take the baseline for the error rate right before switching, so it
+	// only counts queries served after the switch.
+	if spec.Guardrails != nil && spec.Guardrails.Rollback != nil {
+		queries, errors, err := r.vtgateQueryCounts(ctx, 0)
+		if err != nil {
+			r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "VtgateMetricsUnavailable", fmt.Sprintf("Not switching traffic, since the error rate couldn't be watched: %v", err))
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		cutover.BaselineQueries, cutover.BaselineErrors = queries, errors
+	}
+
+	params.TabletTypes = reshardSwitchTabletTypes
+	params.Timeout = reshardSwitchTimeout
+	params.EnableReverseReplication = true
+	params.MaxAllowedTransactionLagSeconds = int64(math.Ceil(maxSwitchLag.Seconds()))
+	if _, err := vrw.SwitchTraffic(workflow.DirectionForward); err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ReshardSwitchTrafficFailed", "failed to switch traffic for workflow %v: %v", spec.Workflow, err)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "SwitchTrafficFailed", err.Error())
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	switchTime := metav1.NewTime(time.Now())
+	cutover.SwitchTime = &switchTime
+	r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "ReshardTrafficSwitched", "Switched traffic for workflow %v to shards %v.", spec.Workflow, strings.Join(spec.TargetShards, ","))
+	r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "TrafficSwitched", "All traffic is served by the target shards.")
+	return resultBuilder.Result()
+}
+
+// startReshard creates the workflow once the target shards are ready, unless
+// it already ran to completion.
+func (r *reconcileHandler) startReshard(ctx context.Context, spec *planetscalev2.VitessKeyspaceReshardSpec, cutover *planetscalev2.ReshardCutoverStatus, vrw *wrangler.VReplicationWorkflow) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	topoServer := r.ts.Server
+	keyspaceName := r.vtk.Spec.Name
+
+	shardsCtx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+	defer cancel()
+
+	// Completing the workflow deletes the source shards along with it.
+	sourcesLeft := false
+	for _, shard := range spec.SourceShards {
+		_, err := topoServer.GetShard(shardsCtx, keyspaceName, shard)
+		if err == nil {
+			sourcesLeft = true
+			continue
+		}
+		if !topo.IsErrType(err, topo.NoNode) {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard %v: %v", shard, err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+	}
+	if !sourcesLeft {
+		if cutover.CompleteTime == nil && cutover.SwitchTime != nil && spec.Complete {
+			// We completed the workflow, but failed to record it. Shards
+			// that never made it into topology don't count, since they may
+			// just not be deployed yet.
+			completeTime := metav1.NewTime(time.Now())
+			cutover.CompleteTime = &completeTime
+		}
+		if cutover.CompleteTime == nil {
+			r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "Completed", "The source shards no longer exist. Remove their partitioning to turn down their tablets.")
+			return resultBuilder.Result()
+		}
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "Completed", "The workflow completed. The source shards are turned down once they're idle.")
+		return resultBuilder.Result()
+	}
+
+	var notReady []string
+	for _, shard := range spec.TargetShards {
+		shardInfo, err := topoServer.GetShard(shardsCtx, keyspaceName, shard)
+		if err != nil && !topo.IsErrType(err, topo.NoNode) {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard %v: %v", shard, err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		if err != nil || !shardInfo.HasPrimary() {
+			notReady = append(notReady, shard)
+		}
+	}
+	if len(notReady) > 0 {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "WaitingForTargetShards", fmt.Sprintf("Waiting for target shards to have a primary: %v. Make sure a partitioning defines them.", strings.Join(notReady, ",")))
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	params := &wrangler.VReplicationWorkflowParams{
+		WorkflowType:   wrangler.ReshardWorkflow,
+		Workflow:       spec.Workflow,
+		TargetKeyspace: keyspaceName,
+		SourceKeyspace: keyspaceName,
+		SourceShards:   spec.SourceShards,
+		TargetShards:   spec.TargetShards,
+		TabletTypes:    reshardSourceTabletTypes,
+		OnDDL:          "IGNORE",
+		AutoStart:      true,
+	}
+	vrw, err := r.wr.NewVReplicationWorkflow(ctx, wrangler.ReshardWorkflow, params)
+	if err == nil {
+		err = vrw.Create(ctx)
+	}
+	if err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ReshardCreateFailed", "failed to create workflow %v: %v", spec.Workflow, err)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "CreateFailed", err.Error())
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "ReshardCreated", "Created workflow %v from shards %v to shards %v.", spec.Workflow, strings.Join(spec.SourceShards, ","), strings.Join(spec.TargetShards, ","))
+	r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "Created", "The workflow is copying data to the target shards.")
+	return resultBuilder.Result()
+}
+
+// completedSourceShards returns the set of source shards of the Reshard
+// workflow in spec.reshard, if vtop completed it. They were deleted from
+// topology, so their tablets should be turned down, even though their
+// partitioning still lists them.
+func completedSourceShards(vtk *planetscalev2.VitessKeyspace) map[string]bool {
+	spec, cutover := vtk.Spec.Reshard, vtk.Status.Cutover
+	if spec == nil || !spec.Complete || cutover == nil || cutover.Workflow != spec.Workflow || cutover.CompleteTime == nil {
+		return nil
+	}
+	shards := make(map[string]bool, len(spec.SourceShards))
+	for _, shard := range spec.SourceShards {
+		shards[shard] = true
+	}
+	return shards
+}
+
+// validateReshard checks that the source and target shards are distinct and
+// cover the same range of keyspace IDs.
+func validateReshard(spec *planetscalev2.VitessKeyspaceReshardSpec) error {
+	sourceRange, err := shardsKeyRange(spec.SourceShards)
+	if err != nil {
+		return fmt.Errorf("invalid sourceShards: %v", err)
+	}
+	targetRange, err := shardsKeyRange(spec.TargetShards)
+	if err != nil {
+		return fmt.Errorf("invalid targetShards: %v", err)
+	}
+	if !key.KeyRangeEqual(sourceRange, targetRange) {
+		return fmt.Errorf("sourceShards cover %v, but targetShards cover %v", key.KeyRangeString(sourceRange), key.KeyRangeString(targetRange))
+	}
+	if spec.Guardrails != nil && spec.Guardrails.Rollback != nil {
+		if _, err := strconv.ParseFloat(spec.Guardrails.Rollback.MaxErrorRate, 64); err != nil {
+			return fmt.Errorf("invalid guardrails.rollback.maxErrorRate: %v", err)
+		}
+	}
+	for _, source := range spec.SourceShards {
+		for _, target := range spec.TargetShards {
+			if source == target {
+				return fmt.Errorf("shard %v can't be both a source and a target", source)
+			}
+		}
+	}
+	return nil
+}
+
+// shardsKeyRange returns the key range covered by a list of contiguous shards.
+func shardsKeyRange(shards []string) (*topodatapb.KeyRange, error) {
+	keyRanges := make([]*topodatapb.KeyRange, 0, len(shards))
+	for _, shard := range shards {
+		_, keyRange, err := topo.ValidateShardName(shard)
+		if err != nil {
+			return nil, err
+		}
+		if keyRange == nil {
+			return nil, fmt.Errorf("shard %v is not a key range", shard)
+		}
+		keyRanges = append(keyRanges, keyRange)
+	}
+	if len(keyRanges) == 0 {
+		return nil, fmt.Errorf("no shards listed")
+	}
+	sort.Slice(keyRanges, func(i, j int) bool {
+		return key.KeyRangeStartSmaller(keyRanges[i], keyRanges[j])
+	})
+	combined := keyRanges[0]
+	for _, keyRange := range keyRanges[1:] {
+		next, ok := key.KeyRangeAdd(combined, keyRange)
+		if !ok {
+			return nil, fmt.Errorf("shards %v aren't contiguous", strings.Join(shards, ","))
+		}
+		combined = next
+	}
+	return combined, nil
+}
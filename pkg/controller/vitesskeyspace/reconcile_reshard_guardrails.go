@@ -0,0 +1,223 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+const (
+	// reshardDefaultRollbackWindow is how long the error rate is watched
+	// after switching traffic, unless the rollback spec says otherwise.
+	reshardDefaultRollbackWindow = 10 * time.Minute
+	// reshardDefaultRollbackMinQueries is how many queries must have been
+	// made before the error rate is judged, unless the rollback spec says
+	// otherwise.
+	reshardDefaultRollbackMinQueries = 100
+	// vtgateVarsMaxAge is how long query counts fetched from a vtgate are
+	// reused while watching the error rate.
+	vtgateVarsMaxAge = 10 * time.Second
+)
+
+// trackCutover returns the record of the workflow's cutover, and notes when
+// the copy phase started and ended based on what reconcileResharding just
+// saw.
+func (r *reconcileHandler) trackCutover(spec *planetscalev2.VitessKeyspaceReshardSpec) *planetscalev2.ReshardCutoverStatus {
+	cutover := r.vtk.Status.Cutover
+	if cutover == nil || cutover.Workflow != spec.Workflow {
+		cutover = &planetscalev2.ReshardCutoverStatus{Workflow: spec.Workflow}
+	}
+	if !spec.SwitchTraffic && cutover.RollbackTime != nil {
+		// Turning switchTraffic off acknowledges the rollback, so the next
+		// switch starts over.
+		cutover.SwitchTime = nil
+		cutover.RollbackTime = nil
+		cutover.RollbackReason = ""
+		cutover.ErrorRate = ""
+	}
+
+	now := metav1.Now()
+	if resharding := r.vtk.Status.Resharding; resharding != nil && resharding.Workflow == spec.Workflow {
+		switch resharding.State {
+		case planetscalev2.WorkflowCopying:
+			if cutover.CopyStartTime == nil {
+				cutover.CopyStartTime = &now
+			}
+		case planetscalev2.WorkflowRunning:
+			if cutover.CopyStartTime != nil && cutover.CopyEndTime == nil {
+				cutover.CopyEndTime = &now
+			}
+		}
+	}
+	r.vtk.Status.Cutover = cutover
+	return cutover
+}
+
+// switchBlocked returns the reason and message of why switching traffic is
+// held back, if it is. Traffic is only switched once this workflow, not just
+// any resharding workflow of the keyspace, has caught up, and then only if
+// the guardrails allow it.
+func (r *reconcileHandler) switchBlocked(spec *planetscalev2.VitessKeyspaceReshardSpec, cutover *planetscalev2.ReshardCutoverStatus) (string, string) {
+	resharding := r.vtk.Status.Resharding
+	if resharding == nil || resharding.Workflow != spec.Workflow || resharding.State != planetscalev2.WorkflowRunning {
+		return "CatchingUp", "Waiting for the workflow to finish copying before switching traffic."
+	}
+	guardrails := spec.Guardrails
+	maxLag := int64(maxSafeVReplicationLag)
+	if guardrails != nil && guardrails.MaxReplicationLag != nil {
+		maxLag = int64(guardrails.MaxReplicationLag.Duration / time.Second)
+	}
+	for _, stream := range resharding.Streams {
+		if stream.LagSeconds > maxLag {
+			return "Lagging", fmt.Sprintf("Stream %v of shard %v is %vs behind, more than the %vs allowed to switch traffic.", stream.ID, stream.TargetShard, stream.LagSeconds, maxLag)
+		}
+	}
+	if guardrails == nil {
+		return "", ""
+	}
+
+	approved := r.vtk.Annotations[planetscalev2.ApproveSwitchTrafficAnnotation] == spec.Workflow
+	approveHint := fmt.Sprintf("Annotate the keyspace with %v=%v to switch traffic.", planetscalev2.ApproveSwitchTrafficAnnotation, spec.Workflow)
+	if guardrails.MaxCopyPhase != nil && cutover.CopyStartTime != nil && cutover.CopyEndTime != nil && !approved {
+		took := cutover.CopyEndTime.Sub(cutover.CopyStartTime.Time)
+		if took > guardrails.MaxCopyPhase.Duration {
+			return "CopyPhaseTooLong", fmt.Sprintf("The copy phase took %v, longer than maxCopyPhase. %v", took.Round(time.Second), approveHint)
+		}
+	}
+	if guardrails.RequireApproval && !approved {
+		return "WaitingForApproval", approveHint
+	}
+	return "", ""
+}
+
+// watchRollback watches the error rate of queries to the keyspace while the
+// rollback window after switching traffic is open, and switches traffic back
+// if it's too high. It returns whether the window is still open, in which
+// case it has set the ReshardProgressing condition.
+func (r *reconcileHandler) watchRollback(ctx context.Context, spec *planetscalev2.VitessKeyspaceReshardSpec, cutover *planetscalev2.ReshardCutoverStatus, vrw *wrangler.VReplicationWorkflow, params *wrangler.VReplicationWorkflowParams) bool {
+	if spec.Guardrails == nil || spec.Guardrails.Rollback == nil || cutover.SwitchTime == nil || cutover.RollbackTime != nil {
+		return false
+	}
+	rollback := spec.Guardrails.Rollback
+	window := reshardDefaultRollbackWindow
+	if rollback.Window != nil {
+		window = rollback.Window.Duration
+	}
+	windowEnd := cutover.SwitchTime.Add(window)
+	if time.Now().After(windowEnd) {
+		return false
+	}
+	minQueries := int64(reshardDefaultRollbackMinQueries)
+	if rollback.MinQueries > 0 {
+		minQueries = rollback.MinQueries
+	}
+
+	queries, errors, err := r.vtgateQueryCounts(ctx, vtgateVarsMaxAge)
+	if err != nil {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "WatchingErrorRate", fmt.Sprintf("Can't get query counts from vtgate: %v", err))
+		return true
+	}
+	if queries < cutover.BaselineQueries || errors < cutover.BaselineErrors {
+		// A vtgate restarted and its counts started over, so start over too.
+		cutover.BaselineQueries, cutover.BaselineErrors = queries, errors
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "WatchingErrorRate", "Query counts were reset by a vtgate restart. Watching the error rate again.")
+		return true
+	}
+	queries -= cutover.BaselineQueries
+	errors -= cutover.BaselineErrors
+	if queries < minQueries {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "WatchingErrorRate", fmt.Sprintf("Only %v queries were made since traffic was switched. Watching the error rate until %v.", queries, windowEnd.UTC().Format(time.RFC3339)))
+		return true
+	}
+	errorRate := float64(errors) / float64(queries)
+	cutover.ErrorRate = strconv.FormatFloat(errorRate, 'f', 4, 64)
+	maxErrorRate, _ := strconv.ParseFloat(rollback.MaxErrorRate, 64)
+	if errorRate <= maxErrorRate {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionTrue, "WatchingErrorRate", fmt.Sprintf("The error rate is %v, within maxErrorRate. Watching it until %v.", cutover.ErrorRate, windowEnd.UTC().Format(time.RFC3339)))
+		return true
+	}
+
+	reason := fmt.Sprintf("%v of %v queries failed since traffic was switched, more than maxErrorRate %v", errors, queries, rollback.MaxErrorRate)
+	params.SourceKeyspace = params.TargetKeyspace
+	params.TabletTypes = reshardSwitchTabletTypes
+	params.Timeout = reshardSwitchTimeout
+	params.EnableReverseReplication = true
+	if _, err := vrw.ReverseTraffic(); err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ReshardRollbackFailed", "failed to switch traffic back for workflow %v: %v", spec.Workflow, err)
+		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "RollbackFailed", fmt.Sprintf("%v, but switching traffic back failed: %v", reason, err))
+		return true
+	}
+	now := metav1.Now()
+	cutover.RollbackTime = &now
+	cutover.RollbackReason = reason
+	r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ReshardRolledBack", "Switched traffic for workflow %v back to shards %v: %v.", spec.Workflow, strings.Join(spec.SourceShards, ","), reason)
+	r.setConditionStatus(planetscalev2.VitessKeyspaceReshardProgressing, corev1.ConditionFalse, "RolledBack", fmt.Sprintf("Traffic was switched back: %v. Set switchTraffic to false and back to true to try again.", reason))
+	return true
+}
+
+// vtgateQueryCounts returns how many queries to the keyspace all running
+// vtgates of the cluster have served, and how many of those failed. Counts
+// fetched less than maxAge ago are reused.
+func (r *reconcileHandler) vtgateQueryCounts(ctx context.Context, maxAge time.Duration) (int64, int64, error) {
+	pods, err := vtgate.RunningPods(ctx, r.client, r.vtk.Labels[planetscalev2.ClusterLabel], "")
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(pods) == 0 {
+		return 0, 0, fmt.Errorf("no running vtgate Pods")
+	}
+
+	var queries, errors int64
+	for _, pod := range pods {
+		vars, err := vtgate.GetVars(ctx, r.webClient, pod, maxAge)
+		if err != nil {
+			return 0, 0, fmt.Errorf("vtgate Pod %v: %v", pod.Name, err)
+		}
+		podQueries, podErrors := keyspaceQueryCounts(vars, r.vtk.Spec.Name)
+		queries += podQueries
+		errors += podErrors
+	}
+	return queries, errors, nil
+}
+
+// keyspaceQueryCounts sums the counts of queries to a keyspace, and of
+// those that failed, over all operations and tablet types.
+func keyspaceQueryCounts(vars *vtgate.Vars, keyspace string) (int64, int64) {
+	var queries, errors int64
+	for key, histogram := range vars.VtgateApi.Histograms {
+		if parts := strings.Split(key, "."); len(parts) > 1 && parts[1] == keyspace {
+			queries += histogram.Count
+		}
+	}
+	for key, count := range vars.VtgateApiErrorCounts {
+		if parts := strings.Split(key, "."); len(parts) > 1 && parts[1] == keyspace {
+			errors += count
+		}
+	}
+	return queries, errors
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+func TestKeyspaceQueryCounts(t *testing.T) {
+	body := `{
+		"VtgateApi": {
+			"Histograms": {
+				"Execute.commerce.primary": {"Count": 10},
+				"StreamExecute.commerce.replica": {"Count": 5},
+				"Execute.customer.primary": {"Count": 100},
+				"Execute.commercial.primary": {"Count": 1000}
+			}
+		},
+		"VtgateApiErrorCounts": {
+			"Execute.commerce.primary.UNAVAILABLE": 2,
+			"Execute.commerce.primary.INTERNAL": 1,
+			"Execute.customer.primary.UNAVAILABLE": 50
+		}
+	}`
+	vars := &vtgate.Vars{}
+	if err := json.Unmarshal([]byte(body), vars); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	queries, errors := keyspaceQueryCounts(vars, "commerce")
+	if queries != 15 || errors != 3 {
+		t.Errorf("keyspaceQueryCounts() = %v, %v; want 15, 3", queries, errors)
+	}
+}
+
+func TestSwitchBlocked(t *testing.T) {
+	running := func(workflow string, lags ...int64) *planetscalev2.ReshardingStatus {
+		status := &planetscalev2.ReshardingStatus{Workflow: workflow, State: planetscalev2.WorkflowRunning}
+		for i, lag := range lags {
+			status.Streams = append(status.Streams, planetscalev2.VReplicationStreamStatus{TargetShard: "-80", ID: int32(i + 1), LagSeconds: lag})
+		}
+		return status
+	}
+	copyStart := metav1.NewTime(time.Now().Add(-3 * time.Hour))
+	copyEnd := metav1.NewTime(time.Now())
+
+	table := []struct {
+		name       string
+		resharding *planetscalev2.ReshardingStatus
+		guardrails *planetscalev2.VitessReshardGuardrails
+		cutover    planetscalev2.ReshardCutoverStatus
+		approved   bool
+		wantReason string
+	}{
+		{
+			name:       "no workflow status",
+			wantReason: "CatchingUp",
+		},
+		{
+			name:       "another workflow is in sync",
+			resharding: running("other"),
+			wantReason: "CatchingUp",
+		},
+		{
+			name:       "copying",
+			resharding: &planetscalev2.ReshardingStatus{Workflow: "split", State: planetscalev2.WorkflowCopying},
+			wantReason: "CatchingUp",
+		},
+		{
+			name:       "caught up",
+			resharding: running("split", 1, 2),
+		},
+		{
+			name:       "lagging by default",
+			resharding: running("split", 1, 60),
+			wantReason: "Lagging",
+		},
+		{
+			name:       "within maxReplicationLag",
+			resharding: running("split", 1, 60),
+			guardrails: &planetscalev2.VitessReshardGuardrails{MaxReplicationLag: &metav1.Duration{Duration: time.Minute}},
+		},
+		{
+			name:       "beyond maxReplicationLag",
+			resharding: running("split", 1, 5),
+			guardrails: &planetscalev2.VitessReshardGuardrails{MaxReplicationLag: &metav1.Duration{Duration: 2 * time.Second}},
+			wantReason: "Lagging",
+		},
+		{
+			name:       "copy phase too long",
+			resharding: running("split"),
+			guardrails: &planetscalev2.VitessReshardGuardrails{MaxCopyPhase: &metav1.Duration{Duration: time.Hour}},
+			cutover:    planetscalev2.ReshardCutoverStatus{CopyStartTime: &copyStart, CopyEndTime: &copyEnd},
+			wantReason: "CopyPhaseTooLong",
+		},
+		{
+			name:       "long copy phase approved",
+			resharding: running("split"),
+			guardrails: &planetscalev2.VitessReshardGuardrails{MaxCopyPhase: &metav1.Duration{Duration: time.Hour}},
+			cutover:    planetscalev2.ReshardCutoverStatus{CopyStartTime: &copyStart, CopyEndTime: &copyEnd},
+			approved:   true,
+		},
+		{
+			name:       "waiting for approval",
+			resharding: running("split"),
+			guardrails: &planetscalev2.VitessReshardGuardrails{RequireApproval: true},
+			wantReason: "WaitingForApproval",
+		},
+		{
+			name:       "approved",
+			resharding: running("split"),
+			guardrails: &planetscalev2.VitessReshardGuardrails{RequireApproval: true},
+			approved:   true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vtk := &planetscalev2.VitessKeyspace{}
+			vtk.Status.Resharding = test.resharding
+			if test.approved {
+				vtk.Annotations = map[string]string{planetscalev2.ApproveSwitchTrafficAnnotation: "split"}
+			}
+			spec := &planetscalev2.VitessKeyspaceReshardSpec{Workflow: "split", Guardrails: test.guardrails}
+			r := &reconcileHandler{vtk: vtk}
+			reason, message := r.switchBlocked(spec, &test.cutover)
+			if reason != test.wantReason {
+				t.Errorf("switchBlocked() = %q (%v); want %q", reason, message, test.wantReason)
+			}
+		})
+	}
+}
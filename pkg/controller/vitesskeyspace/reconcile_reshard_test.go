@@ -0,0 +1,177 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"vitess.io/vitess/go/vt/key"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestShardsKeyRange(t *testing.T) {
+	table := []struct {
+		shards  []string
+		want    string
+		wantErr string
+	}{
+		{shards: []string{"-"}, want: "-"},
+		{shards: []string{"-80", "80-"}, want: "-"},
+		{shards: []string{"80-c0", "-80", "c0-"}, want: "-"},
+		{shards: []string{"40-80", "80-c0"}, want: "40-c0"},
+		{shards: []string{"-40", "80-"}, wantErr: "aren't contiguous"},
+		{shards: []string{"0"}, wantErr: "not a key range"},
+		{shards: []string{"80-40"}, wantErr: "out of order"},
+		{shards: nil, wantErr: "no shards"},
+	}
+	for _, test := range table {
+		got, err := shardsKeyRange(test.shards)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("shardsKeyRange(%v) error = %v; want %q", test.shards, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("shardsKeyRange(%v) error: %v", test.shards, err)
+			continue
+		}
+		if got := key.KeyRangeString(got); got != test.want {
+			t.Errorf("shardsKeyRange(%v) = %v; want %v", test.shards, got, test.want)
+		}
+	}
+}
+
+func TestValidateReshard(t *testing.T) {
+	table := []struct {
+		name    string
+		spec    planetscalev2.VitessKeyspaceReshardSpec
+		wantErr string
+	}{
+		{
+			name: "split",
+			spec: planetscalev2.VitessKeyspaceReshardSpec{SourceShards: []string{"-"}, TargetShards: []string{"-80", "80-"}},
+		},
+		{
+			name: "merge",
+			spec: planetscalev2.VitessKeyspaceReshardSpec{SourceShards: []string{"40-80", "80-c0"}, TargetShards: []string{"40-c0"}},
+		},
+		{
+			name:    "invalid source",
+			spec:    planetscalev2.VitessKeyspaceReshardSpec{SourceShards: []string{"0"}, TargetShards: []string{"-"}},
+			wantErr: "invalid sourceShards",
+		},
+		{
+			name:    "invalid target",
+			spec:    planetscalev2.VitessKeyspaceReshardSpec{SourceShards: []string{"-"}, TargetShards: []string{"-40", "80-"}},
+			wantErr: "invalid targetShards",
+		},
+		{
+			name:    "different ranges",
+			spec:    planetscalev2.VitessKeyspaceReshardSpec{SourceShards: []string{"-80"}, TargetShards: []string{"-40", "40-c0"}},
+			wantErr: "sourceShards cover -80, but targetShards cover -c0",
+		},
+		{
+			name:    "same shard",
+			spec:    planetscalev2.VitessKeyspaceReshardSpec{SourceShards: []string{"-80", "80-"}, TargetShards: []string{"-80", "80-c0", "c0-"}},
+			wantErr: "shard -80 can't be both a source and a target",
+		},
+		{
+			name: "invalid max error rate",
+			spec: planetscalev2.VitessKeyspaceReshardSpec{
+				SourceShards: []string{"-"},
+				TargetShards: []string{"-80", "80-"},
+				Guardrails: &planetscalev2.VitessReshardGuardrails{
+					Rollback: &planetscalev2.VitessReshardRollbackSpec{MaxErrorRate: "1%"},
+				},
+			},
+			wantErr: "invalid guardrails.rollback.maxErrorRate",
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateReshard(&test.spec)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateReshard() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("validateReshard() error = %v; want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompletedSourceShards(t *testing.T) {
+	completeTime := metav1.Now()
+	spec := &planetscalev2.VitessKeyspaceReshardSpec{
+		Workflow:     "merge",
+		SourceShards: []string{"-40", "40-80"},
+		TargetShards: []string{"-80"},
+		Complete:     true,
+	}
+	incomplete := spec.DeepCopy()
+	incomplete.Complete = false
+
+	table := []struct {
+		name    string
+		spec    *planetscalev2.VitessKeyspaceReshardSpec
+		cutover *planetscalev2.ReshardCutoverStatus
+		want    map[string]bool
+	}{
+		{
+			name:    "completed",
+			spec:    spec,
+			cutover: &planetscalev2.ReshardCutoverStatus{Workflow: "merge", CompleteTime: &completeTime},
+			want:    map[string]bool{"-40": true, "40-80": true},
+		},
+		{
+			name:    "not completed yet",
+			spec:    spec,
+			cutover: &planetscalev2.ReshardCutoverStatus{Workflow: "merge"},
+		},
+		{
+			name:    "completed another workflow",
+			spec:    spec,
+			cutover: &planetscalev2.ReshardCutoverStatus{Workflow: "split", CompleteTime: &completeTime},
+		},
+		{
+			name:    "complete unset",
+			spec:    incomplete,
+			cutover: &planetscalev2.ReshardCutoverStatus{Workflow: "merge", CompleteTime: &completeTime},
+		},
+		{
+			name: "no reshard",
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vtk := &planetscalev2.VitessKeyspace{}
+			vtk.Spec.Reshard = test.spec
+			vtk.Status.Cutover = test.cutover
+			if got := completedSourceShards(vtk); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("completedSourceShards() = %v; want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"time"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +30,8 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vreplication"
+	"planetscale.dev/vitess-operator/pkg/operator/vtctldclient"
 )
 
 const (
@@ -81,6 +84,10 @@ func (r *reconcileHandler) reconcileResharding(ctx context.Context) (reconcile.R
 		reshardingWorkflow = workflow
 	}
 
+	clusterName := r.vtk.Labels[planetscalev2.ClusterLabel]
+	if r.oldStatus.Resharding != nil && (reshardingWorkflow == nil || reshardingWorkflow.Workflow != r.oldStatus.Resharding.Workflow) {
+		vreplication.ForgetMetrics(clusterName, r.vtk.Spec.Name, r.oldStatus.Resharding.Workflow)
+	}
 	if reshardingWorkflow == nil {
 		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardingActive, corev1.ConditionFalse, "NoActiveReshardingWorkflow", "No active resharding workflow found.")
 		r.setConditionStatus(planetscalev2.VitessKeyspaceReshardingInSync, corev1.ConditionFalse, "NoActiveReshardingWorkflow", "No active resharding workflow found.")
@@ -97,28 +104,16 @@ func (r *reconcileHandler) reconcileResharding(ctx context.Context) (reconcile.R
 		SourceShards: reshardingWorkflow.SourceLocation.Shards,
 		TargetShards: reshardingWorkflow.TargetLocation.Shards,
 		CopyProgress: -1,
+		Streams:      vreplication.Streams(reshardingWorkflow, time.Now()),
 	}
+	vreplication.ReportMetrics(clusterName, r.vtk.Spec.Name, workflowStatus.Workflow, workflowStatus.Streams)
 
 	// We aggregate status across all the shards for the workflow so we can definitely know if we are in two states:
 	// Copying, or Error. We also do this so we can determine what all of the serving shards are.
 	// At a high level we mostly need to know if we are still in the Copying phase (for any shard whatsoever), or if
 	// we have an error in resharding somewhere that needs to be surfaced.
 	var errorMsgs []string
-	for _, status := range reshardingWorkflow.ShardStatuses {
-		for _, vReplRow := range status.PrimaryReplicationStatuses {
-			if vReplRow.State == "Error" {
-				workflowStatus.State = planetscalev2.WorkflowError
-				errorMsgs = append(errorMsgs, vReplRow.Message)
-				break
-			}
-			if vReplRow.State == "Copying" && workflowStatus.State != planetscalev2.WorkflowError {
-				workflowStatus.State = planetscalev2.WorkflowCopying
-			}
-			if (vReplRow.State == "Running" || vReplRow.State == "Lagging") && workflowStatus.State == planetscalev2.WorkflowUnknown {
-				workflowStatus.State = planetscalev2.WorkflowRunning
-			}
-		}
-	}
+	workflowStatus.State, errorMsgs = vreplication.WorkflowState(reshardingWorkflow)
 
 	progressCtx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
 	defer cancel()
@@ -185,13 +180,17 @@ func (r *reconcileHandler) shardsRowCount(ctx context.Context, shardNames []stri
 		if shardInfo.PrimaryAlias == nil {
 			return 0, fmt.Errorf("could not find primary tablet alias for determining row count of shard %v", shardName)
 		}
-		resp, err := r.wr.VtctldServer().GetSchema(ctx, &vtctldatapb.GetSchemaRequest{
-			TabletAlias:    shardInfo.PrimaryAlias,
-			Tables:         nil,
-			ExcludeTables:  nil,
-			IncludeViews:   false,
-			TableNamesOnly: false,
-			TableSizesOnly: false,
+		var resp *vtctldatapb.GetSchemaResponse
+		err = vtctldclient.Call(ctx, func(ctx context.Context) (err error) {
+			resp, err = r.vtctld.GetSchema(ctx, &vtctldatapb.GetSchemaRequest{
+				TabletAlias:    shardInfo.PrimaryAlias,
+				Tables:         nil,
+				ExcludeTables:  nil,
+				IncludeViews:   false,
+				TableNamesOnly: false,
+				TableSizesOnly: false,
+			})
+			return err
 		})
 		if err != nil {
 			return 0, fmt.Errorf("failed to get schema for shard %v: %v", shardName, err)
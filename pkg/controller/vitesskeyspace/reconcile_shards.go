@@ -18,6 +18,9 @@ package vitesskeyspace
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/backupgate"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
@@ -41,12 +45,18 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 
 	// Compute the set of all desired shards based on the defined partitionings.
 	shards := r.vtk.Spec.ShardTemplates()
+	// The source shards of a completed Reshard workflow are gone from
+	// topology, so they aren't desired even if a partitioning lists them.
+	completedSources := completedSourceShards(r.vtk)
 
 	// Generate keys (object names) for all desired shards.
 	// Keep a map back from generated names to the shard specs.
 	keys := make([]client.ObjectKey, 0, len(shards))
 	shardMap := make(map[client.ObjectKey]*planetscalev2.VitessKeyspaceKeyRangeShard, len(shards))
 	for _, shard := range shards {
+		if completedSources[shard.KeyRange.String()] {
+			continue
+		}
 		key := client.ObjectKey{Namespace: r.vtk.Namespace, Name: vitessshard.Name(clusterName, r.vtk.Spec.Name, shard.KeyRange)}
 		keys = append(keys, key)
 		shardMap[key] = shard
@@ -59,12 +69,26 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 	// Initialize a status entry for each desired partitioning, so it will be
 	// listed even if we end up not having anything to report about it.
 	r.vtk.Status.Partitionings = make([]planetscalev2.VitessKeyspacePartitioningStatus, len(r.vtk.Spec.Partitionings))
+	var problems []string
 	for i := range r.vtk.Spec.Partitionings {
 		p := &r.vtk.Spec.Partitionings[i]
 		r.vtk.Status.Partitionings[i] = planetscalev2.NewVitessKeyspacePartitioningStatus(p)
+		if problem := r.vtk.Status.Partitionings[i].KeyRangeProblem; problem != "" {
+			problems = append(problems, fmt.Sprintf("partitioning %v: %v", i, problem))
+		}
+	}
+	if len(problems) > 0 {
+		r.setConditionStatus(planetscalev2.VitessKeyspacePartitioningsValid, corev1.ConditionFalse, "InvalidPartitioning", strings.Join(problems, "; "))
+	} else {
+		r.setConditionStatus(planetscalev2.VitessKeyspacePartitioningsValid, corev1.ConditionTrue, "Valid", "The shards of every partitioning cover the keyspace.")
 	}
 
-	err := r.reconciler.ReconcileObjectSet(ctx, r.vtk, keys, labels, reconciler.Strategy{
+	rolloutSlots, err := r.rolloutSlots(ctx, labels)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconciler.ReconcileObjectSet(ctx, r.vtk, keys, labels, reconciler.Strategy{
 		Kind: &planetscalev2.VitessShard{},
 
 		New: func(key client.ObjectKey) runtime.Object {
@@ -74,10 +98,21 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 			newObj := obj.(*planetscalev2.VitessShard)
 			if *r.vtk.Spec.UpdateStrategy.Type == planetscalev2.ExternalVitessClusterUpdateStrategyType {
 				updateVitessShardInPlace(key, newObj, r.vtk, labels, shardMap[key])
-				return
+				if !rollout.ComponentReleased(r.vtk, planetscalev2.VttabletComponentName) {
+					return
+				}
+				// Changes to tablets have been released, so roll them out
+				// as if the update strategy were Immediate.
+				if rollout.Scheduled(newObj) {
+					// Wait until changes to the shard itself are applied
+					// before restarting any tablets.
+					rollout.Release(newObj)
+					return
+				}
+			} else {
+				updateVitessShard(key, newObj, r.vtk, labels, shardMap[key])
 			}
 
-			updateVitessShard(key, newObj, r.vtk, labels, shardMap[key])
 			if newObj.Status.LowestPodGeneration != newObj.Generation {
 				// Nothing to do here yet - need to wait until generations match before we cascade.
 				return
@@ -87,6 +122,13 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 			// our current shard generation, then we should cascade changes.
 			for _, tabletStatus := range newObj.Status.Tablets {
 				if tabletStatus.PendingChanges != "" {
+					if !rollout.Cascading(newObj) && rolloutSlots >= 0 {
+						if rolloutSlots == 0 {
+							r.vtk.Status.QueuedRollouts++
+							return
+						}
+						rolloutSlots--
+					}
 					rollout.Cascade(newObj)
 					return
 				}
@@ -100,12 +142,23 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 			}
 			updateVitessShard(key, newObj, r.vtk, labels, shardMap[key])
 		},
+		UpdatePaused: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*planetscalev2.VitessShard)
+			newShard := newVitessShard(key, r.vtk, labels, shardMap[key])
+			newObj.Spec.Paused = newShard.Spec.Paused
+			newObj.Spec.DryRun = newShard.Spec.DryRun
+		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			curObj := obj.(*planetscalev2.VitessShard)
 			keyRange := curObj.Spec.KeyRange.String()
 
 			status := r.vtk.Status.Shards[keyRange]
 			status.Cells = curObj.Status.Cells
+			status.ExternalTablets = curObj.Status.ExternalTablets
+			if status.ExternalTablets > 0 {
+				// Tablets we don't manage still use the keyspace.
+				r.vtk.Status.Idle = corev1.ConditionFalse
+			}
 			if curObj.Status.HasMaster != "" {
 				status.HasMaster = curObj.Status.HasMaster
 			}
@@ -114,6 +167,17 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 			}
 			status.Tablets = int32(len(curObj.Status.Tablets))
 			status.PendingChanges = curObj.Annotations[rollout.ScheduledAnnotation]
+			status.QueuedRestores = curObj.Status.QueuedRestores
+
+			status.LatestBackupTime = nil
+			for _, location := range curObj.Status.BackupLocations {
+				if location == nil || location.LatestCompleteBackupTime == nil {
+					continue
+				}
+				if status.LatestBackupTime == nil || status.LatestBackupTime.Before(location.LatestCompleteBackupTime) {
+					status.LatestBackupTime = location.LatestCompleteBackupTime.DeepCopy()
+				}
+			}
 
 			status.ReadyTablets = 0
 			status.UpdatedTablets = 0
@@ -137,6 +201,11 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 			curObj := obj.(*planetscalev2.VitessShard)
 			if curObj.Status.Idle == corev1.ConditionTrue {
 				// The shard is not in any serving partitioning anywhere.
+				// Its data may still be needed, so keep it until it has a
+				// recent backup if the policy asks for one.
+				if err := backupgate.Check(curObj, time.Now()); err != nil {
+					return planetscalev2.NewOrphanStatus("BackupRequired", err.Error())
+				}
 				return nil
 			}
 			// The shard is either in a serving partitioning (Idle=False),
@@ -252,6 +321,20 @@ func totalReadyShards(shardNames []string, shardStatus map[string]planetscalev2.
 func newVitessShard(key client.ObjectKey, vtk *planetscalev2.VitessKeyspace, parentLabels map[string]string, shard *planetscalev2.VitessKeyspaceKeyRangeShard) *planetscalev2.VitessShard {
 	template := shard.VitessShardTemplate.DeepCopy()
 
+	// Shards inherit the keyspace's paused state unless they override it.
+	paused := vtk.Spec.Paused
+	if template.Paused != nil {
+		paused = *template.Paused
+	}
+	template.Paused = &paused
+
+	// Shards inherit the keyspace's images unless they override them.
+	images := planetscalev2.VitessKeyspaceImages{}
+	if template.ImageOverrides != nil {
+		images = *template.ImageOverrides.DeepCopy()
+	}
+	planetscalev2.DefaultVitessShardImages(&images, &vtk.Spec.Images)
+
 	// Copy parent labels map and add shard-specific label.
 	labels := make(map[string]string, len(parentLabels)+1)
 	for k, v := range parentLabels {
@@ -270,18 +353,35 @@ func newVitessShard(key client.ObjectKey, vtk *planetscalev2.VitessKeyspace, par
 			VitessShardTemplate:    *template,
 			GlobalLockserver:       vtk.Spec.GlobalLockserver,
 			VitessOrchestrator:     vtk.Spec.VitessOrchestrator,
-			Images:                 vtk.Spec.Images,
+			Images:                 images,
 			ImagePullPolicies:      vtk.Spec.ImagePullPolicies,
 			ImagePullSecrets:       vtk.Spec.ImagePullSecrets,
 			Name:                   shard.KeyRange.String(),
 			DatabaseName:           vtk.Spec.DatabaseName,
+			DurabilityPolicy:       vtk.Spec.DurabilityPolicy,
 			KeyRange:               shard.KeyRange,
 			ZoneMap:                vtk.Spec.ZoneMap,
 			BackupLocations:        vtk.Spec.BackupLocations,
 			BackupEngine:           vtk.Spec.BackupEngine,
+			BackupCompression:      vtk.Spec.BackupCompression,
+			BackupXtrabackup:       vtk.Spec.BackupXtrabackup,
+			BackupStaleThreshold:   vtk.Spec.BackupStaleThreshold,
+			BackupDisruptionPolicy: vtk.Spec.BackupDisruptionPolicy,
+			MaxConcurrentRestores:  vtk.Spec.MaxConcurrentRestores,
+			Vtbackup:               vtk.Spec.Vtbackup,
 			ExtraVitessFlags:       vtk.Spec.ExtraVitessFlags,
+			ComponentVitessFlags:   vtk.Spec.ComponentVitessFlags,
 			TopologyReconciliation: vtk.Spec.TopologyReconciliation,
+			Networking:             vtk.Spec.Networking,
+			ResourceDefaults:       vtk.Spec.ResourceDefaults,
+			FailoverBuffer:         vtk.Spec.FailoverBuffer,
+			Evacuation:             vtk.Spec.Evacuation,
 			UpdateStrategy:         vtk.Spec.UpdateStrategy,
+			AdoptExisting:          vtk.Spec.AdoptExisting,
+			Standby:                vtk.Spec.Standby,
+			Users:                  vtk.Spec.Users,
+			InternalCredentials:    vtk.Spec.InternalCredentials,
+			DryRun:                 vtk.Spec.DryRun,
 		},
 	}
 }
@@ -306,6 +406,35 @@ func updateVitessShardInPlace(key client.ObjectKey, vts *planetscalev2.VitessSha
 	// Switching update strategies should always take effect immediately.
 	vts.Spec.UpdateStrategy = newShard.Spec.UpdateStrategy
 
+	// Pausing and unpausing should always take effect immediately.
+	vts.Spec.Paused = newShard.Spec.Paused
+	vts.Spec.DryRun = newShard.Spec.DryRun
+
+	// Evacuating cells should always take effect immediately.
+	vts.Spec.Evacuation = newShard.Spec.Evacuation
+
+	// Adoption only changes ownership, so it doesn't need to roll out.
+	vts.Spec.AdoptExisting = newShard.Spec.AdoptExisting
+
+	// The stale backup threshold only affects status.
+	vts.Spec.BackupStaleThreshold = newShard.Spec.BackupStaleThreshold
+
+	// Users are applied with SQL, so they don't need to roll out.
+	vts.Spec.Users = newShard.Spec.Users
+
+	// Tablets pick up new internal passwords in a rollout of their own.
+	vts.Spec.InternalCredentials = newShard.Spec.InternalCredentials
+
+	// The backup disruption policy should apply before anything it guards.
+	vts.Spec.BackupDisruptionPolicy = newShard.Spec.BackupDisruptionPolicy
+
+	// The restore limit only decides when new Pods are created.
+	vts.Spec.MaxConcurrentRestores = newShard.Spec.MaxConcurrentRestores
+
+	// Promoting a standby only changes tablet flags, which the shard rolls
+	// out itself.
+	vts.Spec.Standby = newShard.Spec.Standby
+
 	// For now, only disk size & annotations are safe to update in place.
 	// However, only update disk size immediately if specified to.
 	if *vts.Spec.UpdateStrategy.Type == planetscalev2.ExternalVitessClusterUpdateStrategyType {
@@ -341,3 +470,27 @@ func differentKeys(oldMap, newMap map[string]string) []string {
 
 	return differentKeys
 }
+
+// rolloutSlots returns how many more shards of the keyspace may start
+// rolling out changes to their tablets, or -1 if there's no limit.
+func (r *reconcileHandler) rolloutSlots(ctx context.Context, labels map[string]string) (int, error) {
+	maxRollouts := r.vtk.Spec.UpdateStrategy.MaxConcurrentShardRollouts
+	if maxRollouts == nil {
+		return -1, nil
+	}
+
+	shards := &planetscalev2.VitessShardList{}
+	if err := r.client.List(ctx, shards, client.InNamespace(r.vtk.Namespace), client.MatchingLabels(labels)); err != nil {
+		return 0, err
+	}
+	slots := int(*maxRollouts)
+	for i := range shards.Items {
+		if rollout.Cascading(&shards.Items[i]) {
+			slots--
+		}
+	}
+	if slots < 0 {
+		slots = 0
+	}
+	return slots, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vtctldclient"
+)
+
+// reconcileHasTables reports whether any shard of the keyspace has tables, so
+// the VitessCluster controller knows whether turning it down loses data.
+func (r *reconcileHandler) reconcileHasTables(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	// This costs an RPC to every shard primary, so only check keyspaces that
+	// can be turned down while they have tablets.
+	if r.vtk.Spec.TurndownPolicy != planetscalev2.VitessKeyspaceTurndownPolicyImmediate || r.vtk.Status.Idle == corev1.ConditionTrue {
+		return resultBuilder.Result()
+	}
+
+	err := r.tsInit(ctx)
+	if err != nil {
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	topoServer := r.ts.Server
+	keyspaceName := r.vtk.Spec.Name
+
+	ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+	defer cancel()
+
+	shardNames, err := topoServer.GetShardNames(ctx, keyspaceName)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			// No shard has ever been created, so there's nothing to lose.
+			r.vtk.Status.HasTables = corev1.ConditionFalse
+			return resultBuilder.Result()
+		}
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "TopoGetFailed", "failed to list shards: %v", err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	for _, shardName := range shardNames {
+		shard, err := topoServer.GetShard(ctx, keyspaceName, shardName)
+		if err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard %v: %v", shardName, err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		if !shard.HasPrimary() {
+			// We can't tell without a primary, so leave HasTables Unknown.
+			return resultBuilder.Result()
+		}
+		var resp *vtctldatapb.GetSchemaResponse
+		err = vtctldclient.Call(ctx, func(ctx context.Context) (err error) {
+			resp, err = r.vtctld.GetSchema(ctx, &vtctldatapb.GetSchemaRequest{
+				TabletAlias:    shard.PrimaryAlias,
+				TableNamesOnly: true,
+			})
+			return err
+		})
+		if err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "GetSchemaFailed", "failed to get schema of shard %v: %v", shardName, err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		if len(resp.GetSchema().GetTableDefinitions()) > 0 {
+			r.vtk.Status.HasTables = corev1.ConditionTrue
+			return resultBuilder.Result()
+		}
+	}
+	r.vtk.Status.HasTables = corev1.ConditionFalse
+	return resultBuilder.Result()
+}
@@ -36,10 +36,15 @@ import (
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/statusupdate"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
+	"planetscale.dev/vitess-operator/pkg/operator/webclient"
 )
 
 const (
@@ -52,9 +57,15 @@ var (
 
 	// keyspaceConditions lists all the conditions that the keyspace controller is responsible for updating.
 	keyspaceConditions = map[planetscalev2.VitessKeyspaceConditionType]bool{
-		planetscalev2.VitessKeyspaceReshardingActive: true,
-		planetscalev2.VitessKeyspaceReshardingInSync: true,
-		planetscalev2.VitessKeyspaceReady:            true,
+		planetscalev2.VitessKeyspaceReshardingActive:        true,
+		planetscalev2.VitessKeyspaceReshardingInSync:        true,
+		planetscalev2.VitessKeyspaceReady:                   true,
+		planetscalev2.VitessKeyspaceDurabilityPolicyApplied: true,
+		planetscalev2.VitessKeyspaceQueryServingApplied:     true,
+		planetscalev2.VitessKeyspaceReshardProgressing:      true,
+		planetscalev2.VitessKeyspaceMaterializeInSync:       true,
+		planetscalev2.VitessKeyspaceLookupVindexesReady:     true,
+		planetscalev2.VitessKeyspacePartitioningsValid:      true,
 	}
 )
 
@@ -78,11 +89,13 @@ func newReconciler(mgr manager.Manager) *ReconcileVitessKeyspace {
 	recorder := mgr.GetEventRecorderFor(controllerName)
 
 	return &ReconcileVitessKeyspace{
-		client:     c,
-		scheme:     scheme,
-		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
-		recorder:   recorder,
-		reconciler: reconciler.New(c, scheme, recorder),
+		client:       c,
+		scheme:       scheme,
+		resync:       resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder:     recorder,
+		reconciler:   reconciler.New(c, scheme, recorder),
+		statusWriter: statusupdate.NewWriter(c, "VitessKeyspace", environment.StatusUpdateInterval()),
+		webClient:    webclient.Default,
 	}
 }
 
@@ -128,11 +141,13 @@ var _ reconcile.Reconciler = &ReconcileVitessKeyspace{}
 type ReconcileVitessKeyspace struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client     client.Client
-	scheme     *runtime.Scheme
-	resync     *resync.Periodic
-	recorder   record.EventRecorder
-	reconciler *reconciler.Reconciler
+	client       client.Client
+	scheme       *runtime.Scheme
+	resync       *resync.Periodic
+	recorder     record.EventRecorder
+	reconciler   *reconciler.Reconciler
+	statusWriter *statusupdate.Writer
+	webClient    *webclient.Client
 }
 
 // Reconcile reads that state of the cluster for a VitessKeyspace object and makes changes based on the state read
@@ -141,15 +156,20 @@ type ReconcileVitessKeyspace struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileVitessKeyspace) Reconcile(cctx context.Context, request reconcile.Request) (finalResult reconcile.Result, finalErr error) {
-	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
 	defer cancel()
 
+	ctx, span := tracing.StartReconcile(ctx, "VitessKeyspace", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	resultBuilder := &results.Builder{}
 
 	log := log.WithFields(logrus.Fields{
-		"namespace":      request.Namespace,
-		"vitesskeyspace": request.Name,
+		"namespace":              request.Namespace,
+		"vitesskeyspace":         request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	log.Debug("Reconciling VitessKeyspace")
 
 	handler, err := r.NewReconcileHandler(ctx, request)
@@ -162,15 +182,43 @@ func (r *ReconcileVitessKeyspace) Reconcile(cctx context.Context, request reconc
 	defer handler.close()
 
 	defer func() {
-		err := handler.updateStatus(ctx)
+		delay, err := handler.updateStatus(ctx)
 		if err != nil {
 			finalResult, finalErr = resultBuilder.Error(err)
+		} else if delay > 0 {
+			// Write the latest status once the interval has passed.
+			finalResult, finalErr = resultBuilder.RequeueAfter(delay)
 		}
 	}()
 
+	// While paused, we only compute status and propagate the paused state.
+	// A dry run is the same, except that we also report what we would change.
+	paused := handler.vtk.Spec.Paused || handler.vtk.Spec.DryRun
+	if handler.vtk.Spec.Paused {
+		log.Info("Reconciliation is paused")
+		ctx = reconciler.NewPausedContext(ctx)
+	}
+	if handler.vtk.Spec.AdoptExisting {
+		ctx = reconciler.NewAdoptingContext(ctx)
+	}
+	var dryRun *reconciler.DryRun
+	if handler.vtk.Spec.DryRun {
+		log.Info("Reconciling as a dry run")
+		ctx, dryRun = reconciler.NewDryRunContext(ctx)
+	}
+
+	// Actions on the shared state of the keyspace and its shards are left
+	// to one operator instance, like the topology owner of a federation.
+	manageShards := *handler.vtk.Spec.TopologyReconciliation.ManageShards
+	if !manageShards {
+		handler.skipSharedConditions()
+	}
+
 	// Create/update keyspace record in the topo server
-	keyspaceInfoRes, err := handler.reconcileKeyspaceInformation(ctx)
-	resultBuilder.Merge(keyspaceInfoRes, err)
+	if !paused && manageShards {
+		keyspaceInfoRes, err := handler.reconcileKeyspaceInformation(ctx)
+		resultBuilder.Merge(keyspaceInfoRes, err)
+	}
 
 	// Create/update desired VitessShards.
 	if err := handler.reconcileShards(ctx); err != nil {
@@ -179,13 +227,51 @@ func (r *ReconcileVitessKeyspace) Reconcile(cctx context.Context, request reconc
 
 	// Check latest Vitess topology state and update as needed.
 	// NOTE: This must always be done after reconcileShards, so Status.Shards is populated.
-	topoResult, err := handler.reconcileTopology(ctx)
-	resultBuilder.Merge(topoResult, err)
+	if !paused {
+		topoResult, err := handler.reconcileTopology(ctx)
+		resultBuilder.Merge(topoResult, err)
+	}
 
 	// Check resharding status and report back.
 	reshardingResult, err := handler.reconcileResharding(ctx)
 	resultBuilder.Merge(reshardingResult, err)
 
+	// Drive the requested Reshard workflow forward.
+	// NOTE: This must always be done after reconcileResharding, so Status.Resharding is populated.
+	if !paused && manageShards {
+		reshardResult, err := handler.reconcileReshard(ctx)
+		resultBuilder.Merge(reshardResult, err)
+	}
+
+	// Create the requested Materialize workflow and report on it.
+	if !paused && manageShards {
+		materializeResult, err := handler.reconcileMaterialize(ctx)
+		resultBuilder.Merge(materializeResult, err)
+	}
+
+	// Create the requested lookup vindexes, one step at a time.
+	if !paused && manageShards {
+		lookupVindexesResult, err := handler.reconcileLookupVindexes(ctx)
+		resultBuilder.Merge(lookupVindexesResult, err)
+	}
+
+	// Check whether the keyspace holds data, in case it gets turned down.
+	if !paused {
+		tablesResult, err := handler.reconcileHasTables(ctx)
+		resultBuilder.Merge(tablesResult, err)
+	}
+
+	// Apply the requested tablet controls to the serving graph.
+	// NOTE: This must always be done after reconcileResharding, so Status.Resharding is populated.
+	if !paused && manageShards {
+		queryServingResult, err := handler.reconcileQueryServing(ctx)
+		resultBuilder.Merge(queryServingResult, err)
+	}
+
+	if dryRun != nil {
+		handler.vtk.Status.DryRunChanges = dryRun.Changes()
+	}
+
 	// Request a periodic resync for the keyspace so we can recheck topology
 	// even if no Kubernetes events have occurred.
 	r.resync.Enqueue(request.NamespacedName)
@@ -214,6 +300,8 @@ func (r *ReconcileVitessKeyspace) NewReconcileHandler(ctx context.Context, reque
 	oldStatus := vtk.Status.DeepCopy()
 	vtk.Status = planetscalev2.NewVitessKeyspaceStatus()
 	vtk.Status.Conditions = oldStatus.DeepCopyConditions()
+	// The cutover record can't be recomputed, so it's kept across reconciles.
+	vtk.Status.Cutover = oldStatus.Cutover.DeepCopy()
 
 	untouchedConditions := make(map[planetscalev2.VitessKeyspaceConditionType]bool, len(keyspaceConditions))
 	for condition := range keyspaceConditions {
@@ -227,6 +315,8 @@ func (r *ReconcileVitessKeyspace) NewReconcileHandler(ctx context.Context, reque
 		client:              r.client,
 		recorder:            r.recorder,
 		reconciler:          r.reconciler,
+		statusWriter:        r.statusWriter,
+		webClient:           r.webClient,
 		vtk:                 vtk,
 		oldStatus:           oldStatus,
 		untouchedConditions: untouchedConditions,
@@ -0,0 +1,288 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessmaterialize
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
+	"planetscale.dev/vitess-operator/pkg/operator/vreplication"
+)
+
+const (
+	controllerName = "vitessmaterialize-controller"
+
+	// topoTimeout is how long to wait for each call to the topology.
+	topoTimeout = 10 * time.Second
+	// requeueDelay is how soon to retry after something went wrong, or
+	// while waiting for the target keyspace to be ready.
+	requeueDelay = 5 * time.Second
+)
+
+var (
+	maxConcurrentReconciles = flag.Int("vitessmaterialize_concurrent_reconciles", 10, "the maximum number of different vitessmaterializes to reconcile concurrently")
+	resyncPeriod            = flag.Duration("vitessmaterialize_resync_period", 30*time.Second, "reconcile vitessmaterializes with this period even if no Kubernetes events occur")
+)
+
+var log = logrus.WithField("controller", "VitessMaterialize")
+
+// Add creates a new VitessMaterialize Controller and adds it to the Manager.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileVitessMaterialize {
+	return &ReconcileVitessMaterialize{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		resync:   resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder: mgr.GetEventRecorderFor(controllerName),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileVitessMaterialize) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: *maxConcurrentReconciles,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource VitessMaterialize
+	if err := c.Watch(&source.Kind{Type: &planetscalev2.VitessMaterialize{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// The workflow lives in the topology rather than in Kubernetes, so we
+	// periodically resync to report on it.
+	if err := c.Watch(r.resync.WatchSource(), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileVitessMaterialize{}
+
+// ReconcileVitessMaterialize reconciles a VitessMaterialize object
+type ReconcileVitessMaterialize struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	resync   *resync.Periodic
+	recorder record.EventRecorder
+}
+
+// Reconcile creates the Materialize workflow of a VitessMaterialize object
+// if it doesn't exist yet, and reports on the workflow in its status.
+func (r *ReconcileVitessMaterialize) Reconcile(cctx context.Context, request reconcile.Request) (finalResult reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
+	defer cancel()
+
+	ctx, span := tracing.StartReconcile(ctx, "VitessMaterialize", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
+	resultBuilder := &results.Builder{}
+
+	log := log.WithFields(logrus.Fields{
+		"namespace":              request.Namespace,
+		"vitessmaterialize":      request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
+	})
+	ctx = logging.NewContext(ctx, log)
+	log.Debug("Reconciling VitessMaterialize")
+
+	// Fetch the VitessMaterialize instance.
+	vtm := &planetscalev2.VitessMaterialize{}
+	if err := r.client.Get(ctx, request.NamespacedName, vtm); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The workflow is deliberately left running, so there's nothing
+			// to clean up.
+			return resultBuilder.Result()
+		}
+		// Error reading the object - requeue the request.
+		return resultBuilder.Error(err)
+	}
+
+	oldStatus := vtm.Status.DeepCopy()
+	defer func() {
+		vtm.Status.ObservedGeneration = vtm.Generation
+		if equality.Semantic.DeepEqual(&vtm.Status, oldStatus) {
+			return
+		}
+		if err := r.client.Status().Update(ctx, vtm); err != nil {
+			if !apierrors.IsConflict(err) {
+				r.recorder.Eventf(vtm, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
+			}
+			finalResult, finalErr = resultBuilder.Error(err)
+		}
+	}()
+
+	resultBuilder.Merge(r.reconcileWorkflow(ctx, vtm))
+
+	// Request a periodic resync so we keep reporting on the workflow even
+	// if no Kubernetes events occur.
+	r.resync.Enqueue(request.NamespacedName)
+
+	result, err := resultBuilder.Result()
+	reconcileCount.WithLabelValues(vtm.Spec.ClusterName, vtm.Name, metrics.Result(err)).Inc()
+	return result, err
+}
+
+func (r *ReconcileVitessMaterialize) reconcileWorkflow(ctx context.Context, vtm *planetscalev2.VitessMaterialize) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	spec := &vtm.Spec
+
+	if spec.SourceKeyspace == spec.TargetKeyspace {
+		vtm.Status.Message = "sourceKeyspace and targetKeyspace must be different keyspaces."
+		return resultBuilder.Result()
+	}
+
+	vt := &planetscalev2.VitessCluster{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: vtm.Namespace, Name: spec.ClusterName}, vt); err != nil {
+		if apierrors.IsNotFound(err) {
+			vtm.Status.Message = fmt.Sprintf("Waiting for VitessCluster %v to exist.", spec.ClusterName)
+			return resultBuilder.Result()
+		}
+		return resultBuilder.Error(err)
+	}
+	lockserverParams := lockserver.GlobalConnectionParams(&vt.Spec.GlobalLockserver, vt.Namespace, vt.Name)
+	if lockserverParams == nil {
+		vtm.Status.Message = fmt.Sprintf("VitessCluster %v has no global lockserver.", spec.ClusterName)
+		return resultBuilder.Result()
+	}
+
+	ts, err := toposerver.Open(ctx, r.client, vtm.Namespace, *lockserverParams)
+	if err != nil {
+		r.recorder.Eventf(vtm, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
+		// Give the lockserver some time to come up.
+		return resultBuilder.RequeueAfter(requeueDelay)
+	}
+	defer ts.Close()
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts.Server, tmc)
+
+	workflows, err := wr.ListAllWorkflows(ctx, spec.TargetKeyspace, false /* include stopped workflows */)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		r.recorder.Eventf(vtm, corev1.EventTypeWarning, "ListAllWorkflowsFailed", "failed to list all workflows: %v", err)
+		return resultBuilder.RequeueAfter(requeueDelay)
+	}
+	exists := false
+	for _, name := range workflows {
+		if name == spec.Workflow {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return r.startMaterialize(ctx, vtm, ts.Server, wr)
+	}
+
+	workflow, err := wr.ShowWorkflow(ctx, spec.Workflow, spec.TargetKeyspace)
+	if err != nil {
+		r.recorder.Eventf(vtm, corev1.EventTypeWarning, "ShowWorkflowFailed", "failed to show workflow %v: %v", spec.Workflow, err)
+		return resultBuilder.RequeueAfter(requeueDelay)
+	}
+	state, errorMsgs := vreplication.WorkflowState(workflow)
+	vtm.Status.State = state
+	vtm.Status.MaxVReplicationLagSeconds = workflow.MaxVReplicationLag
+	vtm.Status.Streams = vreplication.Streams(workflow, time.Now())
+	vreplication.ReportMetrics(spec.ClusterName, spec.TargetKeyspace, spec.Workflow, vtm.Status.Streams)
+	vtm.Status.Message = ""
+	if state == planetscalev2.WorkflowError {
+		sort.Strings(errorMsgs)
+		vtm.Status.Message = fmt.Sprintf("VReplication reported an error: %v", errorMsgs[0])
+	}
+	return resultBuilder.Result()
+}
+
+// startMaterialize creates the workflow once every shard of the target
+// keyspace has a primary.
+func (r *ReconcileVitessMaterialize) startMaterialize(ctx context.Context, vtm *planetscalev2.VitessMaterialize, ts *topo.Server, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	spec := &vtm.Spec
+
+	shardsCtx, cancel := context.WithTimeout(ctx, topoTimeout)
+	defer cancel()
+
+	shards, err := ts.GetShardNames(shardsCtx, spec.TargetKeyspace)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		r.recorder.Eventf(vtm, corev1.EventTypeWarning, "TopoGetFailed", "failed to list shards of keyspace %v: %v", spec.TargetKeyspace, err)
+		return resultBuilder.RequeueAfter(requeueDelay)
+	}
+	notReady := len(shards) == 0
+	for _, shard := range shards {
+		shardInfo, err := ts.GetShard(shardsCtx, spec.TargetKeyspace, shard)
+		if err != nil {
+			r.recorder.Eventf(vtm, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard %v/%v: %v", spec.TargetKeyspace, shard, err)
+			return resultBuilder.RequeueAfter(requeueDelay)
+		}
+		if !shardInfo.HasPrimary() {
+			notReady = true
+		}
+	}
+	if notReady {
+		vtm.Status.Message = fmt.Sprintf("Waiting for every shard of keyspace %v to have a primary.", spec.TargetKeyspace)
+		return resultBuilder.RequeueAfter(requeueDelay)
+	}
+
+	if err := wr.Materialize(ctx, vreplication.MaterializeSettings(spec.TargetKeyspace, &spec.VitessKeyspaceMaterializeSpec)); err != nil {
+		r.recorder.Eventf(vtm, corev1.EventTypeWarning, "MaterializeCreateFailed", "failed to create workflow %v: %v", spec.Workflow, err)
+		vtm.Status.Message = fmt.Sprintf("Failed to create the workflow: %v", err)
+		return resultBuilder.RequeueAfter(requeueDelay)
+	}
+	r.recorder.Eventf(vtm, corev1.EventTypeNormal, "MaterializeCreated", "Created workflow %v from keyspace %v into keyspace %v.", spec.Workflow, spec.SourceKeyspace, spec.TargetKeyspace)
+	vtm.Status.Message = "The workflow is copying data from the source keyspace."
+	return resultBuilder.Result()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// testShard returns the VitessShard "ns/shard" of keyspace "keyspace" in
+// cluster "cluster", with the given tablet pools and a fresh status.
+func testShard(pools ...planetscalev2.VitessShardTabletPool) *planetscalev2.VitessShard {
+	return &planetscalev2.VitessShard{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "shard",
+			Labels: map[string]string{
+				planetscalev2.ClusterLabel:  "cluster",
+				planetscalev2.KeyspaceLabel: "keyspace",
+			},
+		},
+		Spec: planetscalev2.VitessShardSpec{
+			VitessShardTemplate: planetscalev2.VitessShardTemplate{
+				TabletPools: pools,
+			},
+		},
+		Status: planetscalev2.NewVitessShardStatus(),
+	}
+}
+
+// testTabletAlias returns the alias of the tablet with the given UID in
+// zone1, which is where test tablets live.
+func testTabletAlias(uid uint32) string {
+	return fmt.Sprintf("zone1-%010d", uid)
+}
+
+// testTabletPod returns the Pod "tablet-<uid>" of a running tablet of the
+// shard in zone1, labeled the way the operator labels tablet Pods.
+func testTabletPod(vts *planetscalev2.VitessShard, uid uint32, poolType planetscalev2.VitessTabletPoolType) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: vts.Namespace,
+			Name:      fmt.Sprintf("tablet-%d", uid),
+			Labels: map[string]string{
+				planetscalev2.ComponentLabel:  planetscalev2.VttabletComponentName,
+				planetscalev2.ClusterLabel:    vts.Labels[planetscalev2.ClusterLabel],
+				planetscalev2.KeyspaceLabel:   vts.Labels[planetscalev2.KeyspaceLabel],
+				planetscalev2.ShardLabel:      vts.Spec.KeyRange.SafeName(),
+				planetscalev2.CellLabel:       "zone1",
+				planetscalev2.TabletUidLabel:  fmt.Sprintf("%d", uid),
+				planetscalev2.TabletTypeLabel: string(poolType),
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
@@ -17,14 +17,16 @@ limitations under the License.
 package vitessshard
 
 import (
-	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"github.com/prometheus/client_golang/prometheus"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 )
 
 const (
 	metricsSubsystemName = "shard"
+
+	tabletLabel = "tablet"
 )
 
 var (
@@ -41,11 +43,19 @@ var (
 		Name:      "reconcile_count",
 		Help:      "Reconciliation attempts for a VitessShard",
 	}, shardMetricLabels)
+
+	tabletReplicationLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "tablet_replication_lag_seconds",
+		Help:      "How far a replica tablet was behind its source the last time the operator checked",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ShardLabel, tabletLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		reconcileCount,
+		tabletReplicationLag,
 	)
 }
 
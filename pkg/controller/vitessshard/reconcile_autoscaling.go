@@ -0,0 +1,345 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/autoscaling"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+/*
+reconcileVerticalAutoscaling creates a VitessTabletPool and a
+VerticalPodAutoscaler for each tablet pool that uses vertical autoscaling,
+and reports their recommendations in status.verticalAutoscaling.
+
+In "Auto" mode, it also decides which requests the pool's containers should
+have, which reconcileTablets then sets. The requests are carried over in
+status, and only changed once they fall outside the range that the
+VerticalPodAutoscaler recommends, so tablets aren't restarted for every small
+change in usage.
+*/
+func (r *ReconcileVitessShard) reconcileVerticalAutoscaling(ctx context.Context, vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	// Replicas of the operator that aren't the leader set the requests that
+	// the leader decided on.
+	if reconciler.IsReadOnly(ctx) {
+		vts.Status.VerticalAutoscaling = copyAutoscalingStatus(oldStatus.VerticalAutoscaling)
+		return resultBuilder.Result()
+	}
+
+	installed, err := autoscaling.VerticalPodAutoscalerInstalled(r.client.RESTMapper())
+	if err != nil {
+		vts.Status.VerticalAutoscaling = copyAutoscalingStatus(oldStatus.VerticalAutoscaling)
+		return resultBuilder.Error(err)
+	}
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+
+	var keys []client.ObjectKey
+	poolSpecs := map[client.ObjectKey]*autoscaling.TabletPoolSpec{}
+	vpaSpecs := map[client.ObjectKey]*autoscaling.VerticalPodAutoscalerSpec{}
+	statusIndex := map[client.ObjectKey]int{}
+	seenPools := map[string]bool{}
+
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+
+		// Pools with the same ID deploy the same tablets, so only the first
+		// one counts, as in vttabletSpecs.
+		poolKey := pool.Cell + "/" + pool.ID()
+		if seenPools[poolKey] {
+			continue
+		}
+		seenPools[poolKey] = true
+
+		mode := pool.VerticalAutoscalingMode()
+		if mode == planetscalev2.VerticalAutoscalingOff {
+			continue
+		}
+		status := planetscalev2.VitessTabletPoolAutoscalingStatus{
+			Cell: pool.Cell,
+			Pool: pool.ID(),
+			Mode: mode,
+		}
+		if !installed {
+			status.Message = "The VerticalPodAutoscaler CRD is not installed."
+			status.Containers = autoscalingContainers(mode, nil, findAutoscalingStatus(oldStatus.VerticalAutoscaling, pool.Cell, pool.ID()))
+			vts.Status.VerticalAutoscaling = append(vts.Status.VerticalAutoscaling, status)
+			continue
+		}
+
+		objLabels := make(map[string]string, len(labels)+3)
+		for k, v := range labels {
+			objLabels[k] = v
+		}
+		objLabels[planetscalev2.CellLabel] = pool.Cell
+		objLabels[planetscalev2.TabletTypeLabel] = string(pool.Type)
+		if pool.Name != "" {
+			objLabels[planetscalev2.TabletPoolLabel] = pool.Name
+		}
+
+		key := client.ObjectKey{Namespace: vts.Namespace, Name: autoscaling.TabletPoolName(vts.Name, pool.Cell, pool.ID())}
+		keys = append(keys, key)
+		poolSpecs[key] = &autoscaling.TabletPoolSpec{
+			Labels:   objLabels,
+			Cell:     pool.Cell,
+			Pool:     pool.ID(),
+			Replicas: vts.Spec.PoolReplicas(pool),
+			Selector: tabletPoolSelector(labels, pool).String(),
+		}
+		vpaSpecs[key] = &autoscaling.VerticalPodAutoscalerSpec{
+			Labels:     objLabels,
+			TargetName: key.Name,
+			Containers: vttablet.AutoscaledContainers,
+			MinAllowed: pool.VerticalAutoscaling.MinAllowed,
+			MaxAllowed: pool.VerticalAutoscaling.MaxAllowed,
+		}
+		statusIndex[key] = len(vts.Status.VerticalAutoscaling)
+		vts.Status.VerticalAutoscaling = append(vts.Status.VerticalAutoscaling, status)
+	}
+
+	err = r.reconciler.ReconcileObjectSet(ctx, vts, keys, labels, reconciler.Strategy{
+		Kind: &planetscalev2.VitessTabletPool{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return autoscaling.NewTabletPool(key, poolSpecs[key])
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			autoscaling.UpdateTabletPool(obj.(*planetscalev2.VitessTabletPool), poolSpecs[key])
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	if !installed {
+		// If the CRD doesn't exist, there can't be any VerticalPodAutoscalers
+		// to clean up.
+		return resultBuilder.Result()
+	}
+
+	vpaStrategy := reconciler.Strategy{
+		Kind: autoscaling.NewVerticalPodAutoscalerKind(),
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return autoscaling.NewVerticalPodAutoscaler(key, vpaSpecs[key])
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			autoscaling.UpdateVerticalPodAutoscaler(obj.(*unstructured.Unstructured), vpaSpecs[key])
+		},
+	}
+	for _, key := range keys {
+		status := &vts.Status.VerticalAutoscaling[statusIndex[key]]
+		oldPoolStatus := findAutoscalingStatus(oldStatus.VerticalAutoscaling, status.Cell, status.Pool)
+
+		if err := r.reconciler.ReconcileObject(ctx, vts, key, labels, true, vpaStrategy); err != nil {
+			resultBuilder.Error(err)
+		}
+		recommendations, err := r.readRecommendations(ctx, key)
+		switch {
+		case err != nil:
+			status.Message = fmt.Sprintf("Failed to read the recommendations of the VerticalPodAutoscaler: %v", err)
+			resultBuilder.Error(err)
+		case len(recommendations) == 0:
+			status.Message = "Waiting for the VerticalPodAutoscaler to make recommendations."
+		}
+		status.Containers = autoscalingContainers(status.Mode, recommendations, oldPoolStatus)
+	}
+
+	// Delete the VerticalPodAutoscalers of pools that no longer use vertical
+	// autoscaling.
+	list := autoscaling.NewVerticalPodAutoscalerList()
+	if err := r.client.List(ctx, list, client.InNamespace(vts.Namespace), client.MatchingLabels(labels)); err != nil {
+		return resultBuilder.Error(err)
+	}
+	for i := range list.Items {
+		key := client.ObjectKeyFromObject(&list.Items[i])
+		if _, wanted := vpaSpecs[key]; wanted {
+			continue
+		}
+		if err := r.reconciler.ReconcileObject(ctx, vts, key, labels, false, vpaStrategy); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// readRecommendations returns the recommendations of a VerticalPodAutoscaler,
+// or none if it doesn't exist yet.
+func (r *ReconcileVitessShard) readRecommendations(ctx context.Context, key client.ObjectKey) ([]autoscaling.ContainerRecommendation, error) {
+	obj := autoscaling.NewVerticalPodAutoscalerKind()
+	if err := r.client.Get(ctx, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return autoscaling.Recommendations(obj)
+}
+
+// tabletPoolSelector returns the label selector of the tablet Pods of a pool.
+func tabletPoolSelector(parentLabels map[string]string, pool *planetscalev2.VitessShardTabletPool) apilabels.Selector {
+	set := make(apilabels.Set, len(parentLabels)+3)
+	for k, v := range parentLabels {
+		set[k] = v
+	}
+	set[planetscalev2.CellLabel] = pool.Cell
+	set[planetscalev2.TabletTypeLabel] = string(pool.Type)
+	if pool.Name != "" {
+		set[planetscalev2.TabletPoolLabel] = pool.Name
+		return apilabels.SelectorFromSet(set)
+	}
+	// Tablets of unnamed pools have no pool label, which tells them apart
+	// from the tablets of named pools of the same type.
+	unnamed, err := apilabels.NewRequirement(planetscalev2.TabletPoolLabel, selection.DoesNotExist, nil)
+	if err != nil {
+		// This can only happen if the label key is invalid.
+		panic(err)
+	}
+	return apilabels.SelectorFromSet(set).Add(*unnamed)
+}
+
+/*
+autoscalingContainers returns the status of each container of a pool, given
+the latest recommendations and the status of the pool from before, if any.
+
+In "Auto" mode, the requests that were applied before are kept as long as
+they're within the recommended range, and carried over for containers that
+have no recommendation right now, so tablets don't go back to the pool's own
+requests whenever the VerticalPodAutoscaler can't be read.
+*/
+func autoscalingContainers(mode planetscalev2.VerticalAutoscalingMode, recommendations []autoscaling.ContainerRecommendation, oldStatus *planetscalev2.VitessTabletPoolAutoscalingStatus) []planetscalev2.VitessContainerAutoscalingStatus {
+	applied := map[string]corev1.ResourceList{}
+	if mode == planetscalev2.VerticalAutoscalingAuto && oldStatus != nil {
+		for i := range oldStatus.Containers {
+			if requests := oldStatus.Containers[i].AppliedRequests; len(requests) > 0 {
+				applied[oldStatus.Containers[i].Name] = requests
+			}
+		}
+	}
+
+	containers := make([]planetscalev2.VitessContainerAutoscalingStatus, 0, len(recommendations))
+	for i := range recommendations {
+		rec := &recommendations[i]
+		container := planetscalev2.VitessContainerAutoscalingStatus{
+			Name:       rec.Name,
+			Target:     rec.Target,
+			LowerBound: rec.LowerBound,
+			UpperBound: rec.UpperBound,
+		}
+		if mode == planetscalev2.VerticalAutoscalingAuto {
+			container.AppliedRequests = appliedRequests(applied[rec.Name], rec)
+		}
+		delete(applied, rec.Name)
+		containers = append(containers, container)
+	}
+	for name, requests := range applied {
+		containers = append(containers, planetscalev2.VitessContainerAutoscalingStatus{
+			Name:            name,
+			AppliedRequests: requests.DeepCopy(),
+		})
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].Name < containers[j].Name
+	})
+	return containers
+}
+
+// appliedRequests returns the requests to set on a container, given the
+// requests that were set before, if any, and its latest recommendation.
+func appliedRequests(prevRequests corev1.ResourceList, rec *autoscaling.ContainerRecommendation) corev1.ResourceList {
+	requests := make(corev1.ResourceList, len(rec.Target))
+	for name, target := range rec.Target {
+		request, ok := prevRequests[name]
+		if ok && withinBound(request, rec.LowerBound, name, 1) && withinBound(request, rec.UpperBound, name, -1) {
+			requests[name] = request
+			continue
+		}
+		requests[name] = target
+	}
+	return requests
+}
+
+// withinBound returns whether a request is on the right side of a bound:
+// at or above it if sign is 1, or at or below it if sign is -1. A missing
+// bound doesn't constrain the request.
+func withinBound(request resource.Quantity, bounds corev1.ResourceList, name corev1.ResourceName, sign int) bool {
+	bound, ok := bounds[name]
+	return !ok || request.Cmp(bound)*sign >= 0
+}
+
+// setAutoscaledRequests sets the requests of a tablet's containers that
+// reconcileVerticalAutoscaling decided on, if its pool is in "Auto" mode.
+func setAutoscaledRequests(vts *planetscalev2.VitessShard, tablet *vttablet.Spec) {
+	poolID := tablet.PoolName
+	if poolID == "" {
+		poolID = string(tablet.Type)
+	}
+	status := findAutoscalingStatus(vts.Status.VerticalAutoscaling, tablet.Alias.Cell, poolID)
+	if status == nil || status.Mode != planetscalev2.VerticalAutoscalingAuto {
+		return
+	}
+	for i := range status.Containers {
+		if requests := status.Containers[i].AppliedRequests; len(requests) > 0 {
+			vttablet.SetRequests(tablet, status.Containers[i].Name, requests)
+		}
+	}
+}
+
+func findAutoscalingStatus(statuses []planetscalev2.VitessTabletPoolAutoscalingStatus, cell, pool string) *planetscalev2.VitessTabletPoolAutoscalingStatus {
+	for i := range statuses {
+		if statuses[i].Cell == cell && statuses[i].Pool == pool {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func copyAutoscalingStatus(statuses []planetscalev2.VitessTabletPoolAutoscalingStatus) []planetscalev2.VitessTabletPoolAutoscalingStatus {
+	if statuses == nil {
+		return nil
+	}
+	result := make([]planetscalev2.VitessTabletPoolAutoscalingStatus, len(statuses))
+	for i := range statuses {
+		statuses[i].DeepCopyInto(&result[i])
+	}
+	return result
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/autoscaling"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// autoscalingShard returns a shard with a replica pool in the given vertical
+// autoscaling mode.
+func autoscalingShard(mode planetscalev2.VerticalAutoscalingMode) *planetscalev2.VitessShard {
+	return testShard(planetscalev2.VitessShardTabletPool{
+		Cell:     "zone1",
+		Type:     planetscalev2.ReplicaPoolType,
+		Replicas: 3,
+		VitessShardTabletPoolTemplate: planetscalev2.VitessShardTabletPoolTemplate{
+			VerticalAutoscaling: &planetscalev2.VitessTabletPoolVerticalAutoscaling{Mode: mode},
+		},
+	})
+}
+
+// vpaRecommending returns the VerticalPodAutoscaler of the shard's pool,
+// recommending 1500m CPU for vttablet, between 500m and 2.
+func vpaRecommending(vts *planetscalev2.VitessShard) *unstructured.Unstructured {
+	key := client.ObjectKey{Namespace: vts.Namespace, Name: autoscaling.TabletPoolName(vts.Name, "zone1", "replica")}
+	obj := autoscaling.NewVerticalPodAutoscaler(key, &autoscaling.VerticalPodAutoscalerSpec{
+		TargetName: key.Name,
+		Labels: map[string]string{
+			planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+			planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+			planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+			planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+		},
+	})
+	obj.Object["status"] = map[string]interface{}{
+		"recommendation": map[string]interface{}{
+			"containerRecommendations": []interface{}{
+				map[string]interface{}{
+					"containerName": "vttablet",
+					"target":        map[string]interface{}{"cpu": "1500m"},
+					"lowerBound":    map[string]interface{}{"cpu": "500m"},
+					"upperBound":    map[string]interface{}{"cpu": "2"},
+				},
+			},
+		},
+	}
+	return obj
+}
+
+func TestReconcileVerticalAutoscaling(t *testing.T) {
+	cpu := func(value string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(value)}
+	}
+
+	table := []struct {
+		name      string
+		mode      planetscalev2.VerticalAutoscalingMode
+		installed bool
+		vpa       bool
+		// applied are the requests that were applied to vttablet before.
+		applied corev1.ResourceList
+
+		wantObjects bool
+		wantMessage string
+		wantApplied corev1.ResourceList
+	}{
+		{
+			name:        "not installed",
+			mode:        planetscalev2.VerticalAutoscalingAuto,
+			applied:     cpu("1"),
+			wantMessage: "The VerticalPodAutoscaler CRD is not installed.",
+			wantApplied: cpu("1"),
+		},
+		{
+			name:        "no recommendations yet",
+			mode:        planetscalev2.VerticalAutoscalingAuto,
+			installed:   true,
+			wantObjects: true,
+			wantMessage: "Waiting for the VerticalPodAutoscaler to make recommendations.",
+		},
+		{
+			name:        "recommend",
+			mode:        planetscalev2.VerticalAutoscalingRecommend,
+			installed:   true,
+			vpa:         true,
+			wantObjects: true,
+		},
+		{
+			name:        "auto applies the target",
+			mode:        planetscalev2.VerticalAutoscalingAuto,
+			installed:   true,
+			vpa:         true,
+			wantObjects: true,
+			wantApplied: cpu("1500m"),
+		},
+		{
+			name:        "auto keeps requests within bounds",
+			mode:        planetscalev2.VerticalAutoscalingAuto,
+			installed:   true,
+			vpa:         true,
+			applied:     cpu("1"),
+			wantObjects: true,
+			wantApplied: cpu("1"),
+		},
+		{
+			name:        "auto replaces requests out of bounds",
+			mode:        planetscalev2.VerticalAutoscalingAuto,
+			installed:   true,
+			vpa:         true,
+			applied:     cpu("4"),
+			wantObjects: true,
+			wantApplied: cpu("1500m"),
+		},
+		{
+			name:      "off deletes the objects",
+			mode:      planetscalev2.VerticalAutoscalingOff,
+			installed: true,
+			vpa:       true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			vts := autoscalingShard(test.mode)
+			oldStatus := vts.Status.DeepCopy()
+			if test.applied != nil {
+				oldStatus.VerticalAutoscaling = []planetscalev2.VitessTabletPoolAutoscalingStatus{{
+					Cell:       "zone1",
+					Pool:       "replica",
+					Mode:       planetscalev2.VerticalAutoscalingAuto,
+					Containers: []planetscalev2.VitessContainerAutoscalingStatus{{Name: "vttablet", AppliedRequests: test.applied}},
+				}}
+			}
+
+			scheme := runtime.NewScheme()
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := planetscalev2.SchemeBuilder.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			mapper := meta.NewDefaultRESTMapper(nil)
+			if test.installed {
+				// The fake client needs to know the list kind too.
+				scheme.AddKnownTypeWithName(autoscaling.VerticalPodAutoscalerGVK, &unstructured.Unstructured{})
+				scheme.AddKnownTypeWithName(autoscaling.NewVerticalPodAutoscalerList().GroupVersionKind(), &unstructured.UnstructuredList{})
+				mapper.Add(autoscaling.VerticalPodAutoscalerGVK, meta.RESTScopeNamespace)
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper)
+			if test.vpa {
+				builder = builder.WithObjects(vpaRecommending(vts))
+			}
+			c := builder.Build()
+			recorder := record.NewFakeRecorder(100)
+			r := &ReconcileVitessShard{client: c, recorder: recorder, reconciler: reconciler.New(c, scheme, recorder)}
+
+			if _, err := r.reconcileVerticalAutoscaling(ctx, vts, oldStatus); err != nil {
+				t.Fatalf("reconcileVerticalAutoscaling() error: %v", err)
+			}
+
+			key := client.ObjectKey{Namespace: vts.Namespace, Name: autoscaling.TabletPoolName(vts.Name, "zone1", "replica")}
+			pool := &planetscalev2.VitessTabletPool{}
+			err := c.Get(ctx, key, pool)
+			if exists := err == nil; exists != test.wantObjects {
+				t.Fatalf("VitessTabletPool exists = %v (%v); want %v", exists, err, test.wantObjects)
+			}
+			if test.installed {
+				vpa := autoscaling.NewVerticalPodAutoscalerKind()
+				err := c.Get(ctx, key, vpa)
+				if exists := err == nil; exists != test.wantObjects {
+					t.Fatalf("VerticalPodAutoscaler exists = %v (%v); want %v", exists, err, test.wantObjects)
+				}
+				if err == nil {
+					if mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode"); mode != "Off" {
+						t.Errorf("VerticalPodAutoscaler updateMode = %q; want Off", mode)
+					}
+				} else if !apierrors.IsNotFound(err) {
+					t.Fatalf("can't get VerticalPodAutoscaler: %v", err)
+				}
+			}
+			if test.wantObjects {
+				want := "planetscale.com/cell=zone1,planetscale.com/cluster=cluster,planetscale.com/component=vttablet,planetscale.com/keyspace=keyspace,planetscale.com/shard=x-x,!planetscale.com/tablet-pool,planetscale.com/tablet-type=replica"
+				if pool.Status.Selector != want || pool.Spec.Replicas != 3 || pool.Status.Replicas != 3 {
+					t.Errorf("VitessTabletPool = %v replicas, %v/%q; want 3 replicas, selector %q", pool.Spec.Replicas, pool.Status.Replicas, pool.Status.Selector, want)
+				}
+			}
+
+			if test.mode == planetscalev2.VerticalAutoscalingOff {
+				if len(vts.Status.VerticalAutoscaling) != 0 {
+					t.Errorf("status.verticalAutoscaling = %v; want none", vts.Status.VerticalAutoscaling)
+				}
+				return
+			}
+			if len(vts.Status.VerticalAutoscaling) != 1 {
+				t.Fatalf("status.verticalAutoscaling = %v; want 1 pool", vts.Status.VerticalAutoscaling)
+			}
+			status := &vts.Status.VerticalAutoscaling[0]
+			if status.Message != test.wantMessage {
+				t.Errorf("message = %q; want %q", status.Message, test.wantMessage)
+			}
+			var applied corev1.ResourceList
+			for _, container := range status.Containers {
+				if container.Name == "vttablet" {
+					applied = container.AppliedRequests
+				}
+			}
+			if !apiequality.Semantic.DeepEqual(applied, test.wantApplied) {
+				t.Errorf("applied requests = %v; want %v", applied, test.wantApplied)
+			}
+
+			// Tablets of the pool get the applied requests.
+			tablet := &vttablet.Spec{
+				Alias:    topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+				Type:     planetscalev2.ReplicaPoolType,
+				Vttablet: &planetscalev2.VttabletSpec{},
+			}
+			setAutoscaledRequests(vts, tablet)
+			if got := tablet.Vttablet.Resources.Requests; !apiequality.Semantic.DeepEqual(got, test.wantApplied) {
+				t.Errorf("tablet requests = %v; want %v", got, test.wantApplied)
+			}
+		})
+	}
+}
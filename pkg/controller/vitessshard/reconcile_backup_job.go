@@ -18,6 +18,7 @@ package vitessshard
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -187,6 +188,12 @@ func vtbackupInitSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, pare
 		return nil
 	}
 
+	// A standby gets its data from the source cluster's backups. An empty
+	// initial backup would shadow them, even after promotion.
+	if vts.Spec.Standby != nil {
+		return nil
+	}
+
 	if len(vts.Spec.TabletPools) == 0 {
 		// No tablet pools are defined for this shard.
 		// We don't know enough to make a vtbackup spec.
@@ -203,7 +210,7 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
 
 	// Find the backup location for this pool.
-	backupLocation := vts.Spec.BackupLocation(pool.BackupLocationName)
+	backupLocation := vts.Spec.PoolBackupLocation(pool)
 	if backupLocation == nil {
 		// No backup location is configured, so we can't do anything.
 		return nil
@@ -227,6 +234,8 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 	update.Annotations(&annotations, pool.Annotations)
 	update.Annotations(&annotations, backupLocation.Annotations)
 
+	credentialsSecretName, _ := credentialsSecret(vts)
+
 	// Fill in the parts of a vttablet spec that make sense for vtbackup.
 	tabletSpec := &vttablet.Spec{
 		GlobalLockserver:         vts.Spec.GlobalLockserver,
@@ -239,15 +248,21 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 		DataVolumePVCSpec:        pool.DataVolumeClaimTemplate,
 		KeyspaceName:             keyspaceName,
 		DatabaseName:             vts.Spec.DatabaseName,
-		DatabaseInitScriptSecret: vts.Spec.DatabaseInitScriptSecret,
+		DatabaseInitScriptSecret: initDBScriptSecret(vts),
+		CredentialsSecret:        credentialsSecretName,
 		BackupLocation:           backupLocation,
 		BackupEngine:             vts.Spec.BackupEngine,
+		Xtrabackup:               vts.Spec.BackupXtrabackup,
+		BackupCompression:        vts.Spec.BackupCompression,
 		InitContainers:           pool.InitContainers,
 		SidecarContainers:        pool.SidecarContainers,
 		ExtraEnv:                 pool.ExtraEnv,
 		Annotations:              annotations,
 		Tolerations:              pool.Tolerations,
+		NodeSelector:             pool.NodeSelector,
 		ImagePullSecrets:         vts.Spec.ImagePullSecrets,
+		ResourceDefaults:         vts.Spec.ResourceDefaults,
+		Networking:               vts.Spec.Networking,
 	}
 
 	return &vttablet.BackupSpec{
@@ -255,14 +270,18 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 		MinBackupInterval: minBackupInterval,
 		MinRetentionTime:  minRetentionTime,
 		MinRetentionCount: minRetentionCount,
+		Vtbackup:          vts.Spec.Vtbackup,
 
 		TabletSpec: tabletSpec,
 	}
 }
 
 func updateBackupStatus(vts *planetscalev2.VitessShard, allBackups []planetscalev2.VitessBackup) {
+	vts.Status.Backup = nil
+
 	// If no backup locations are configured, there's nothing to do.
 	if len(vts.Spec.BackupLocations) == 0 {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardBackupStale)
 		return
 	}
 
@@ -276,6 +295,7 @@ func updateBackupStatus(vts *planetscalev2.VitessShard, allBackups []planetscale
 	}
 
 	// Report stats on backups, grouped by location.
+	var latest *planetscalev2.VitessBackup
 	for i := range allBackups {
 		backup := &allBackups[i]
 		locationName := backup.Labels[vitessbackup.LocationLabel]
@@ -291,8 +311,43 @@ func updateBackupStatus(vts *planetscalev2.VitessShard, allBackups []planetscale
 			if location.LatestCompleteBackupTime == nil || backup.Status.StartTime.After(location.LatestCompleteBackupTime.Time) {
 				location.LatestCompleteBackupTime = &backup.Status.StartTime
 			}
+			if latest == nil || backup.Status.StartTime.After(latest.Status.StartTime.Time) {
+				latest = backup
+			}
 		} else {
 			location.IncompleteBackups++
 		}
 	}
+
+	if latest != nil {
+		vts.Status.Backup = &planetscalev2.VitessShardBackupStatus{
+			Location:     latest.Labels[vitessbackup.LocationLabel],
+			StartTime:    latest.Status.StartTime,
+			FinishedTime: latest.Status.FinishedTime,
+			Engine:       latest.Status.Engine,
+			Size:         latest.Status.Size,
+		}
+	}
+	updateBackupStaleCondition(vts, time.Now())
+}
+
+// updateBackupStaleCondition sets the BackupStale condition according to
+// the latest complete backup in status, if a threshold is set.
+func updateBackupStaleCondition(vts *planetscalev2.VitessShard, now time.Time) {
+	threshold := vts.Spec.BackupStaleThreshold
+	if threshold == nil {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardBackupStale)
+		return
+	}
+	latest := vts.Status.Backup
+	if latest == nil {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardBackupStale, corev1.ConditionTrue, "NoBackup", "The shard has no complete backup.")
+		return
+	}
+	// Keep the message stable, so status isn't updated on every pass.
+	if now.Sub(latest.StartTime.Time) > threshold.Duration {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardBackupStale, corev1.ConditionTrue, "TooOld", fmt.Sprintf("The latest complete backup started at %v, more than %v ago.", latest.StartTime.UTC().Format(time.RFC3339), threshold.Duration))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardBackupStale, corev1.ConditionFalse, "Recent", fmt.Sprintf("The latest complete backup started at %v.", latest.StartTime.UTC().Format(time.RFC3339)))
 }
@@ -53,7 +53,7 @@ func (r *ReconcileVitessShard) reconcileDisk(ctx context.Context, vts *planetsca
 			continue
 		}
 
-		poolTablets, err := tabletKeysForPool(vts, tabletPool.Cell, tabletPool.Type)
+		poolTablets, err := tabletKeysForPool(vts, tabletPool.Cell, tabletPool.ID())
 		if err != nil {
 			return resultBuilder.Error(err)
 		}
@@ -129,7 +129,7 @@ func (r *ReconcileVitessShard) claimForTabletPod(ctx context.Context, pod *v1.Po
 	return pvc, nil
 }
 
-func tabletKeysForPool(vts *planetscalev2.VitessShard, poolCell string, poolType planetscalev2.VitessTabletPoolType) ([]string, error) {
+func tabletKeysForPool(vts *planetscalev2.VitessShard, poolCell, poolID string) ([]string, error) {
 	tabletKeys := vts.Status.TabletAliases()
 
 	tabletsInCell := make([]string, 0, len(tabletKeys))
@@ -140,7 +140,7 @@ func tabletKeysForPool(vts *planetscalev2.VitessShard, poolCell string, poolType
 			return nil, err
 		}
 
-		if tablet.PoolType != string(poolType) || tabletAlias.Cell != poolCell {
+		if tablet.PoolID() != poolID || tabletAlias.Cell != poolCell {
 			continue
 		}
 
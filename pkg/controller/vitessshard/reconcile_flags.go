@@ -0,0 +1,61 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+// updateManagedFlagsCondition reports the extra vttablet and vtorc flags that
+// are left out because the operator manages them. It's a condition rather
+// than an event so it's reported once, rather than on every reconcile.
+func updateManagedFlagsCondition(vts *planetscalev2.VitessShard) {
+	allowed := vts.Spec.ComponentVitessFlags.ManagedFlagOverrides()
+
+	var problems []string
+	seenPools := map[string]bool{}
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		poolKey := pool.Cell + "/" + pool.ID()
+		if seenPools[poolKey] {
+			// validateTabletPools reports that the duplicate is ignored.
+			continue
+		}
+		seenPools[poolKey] = true
+		if _, denied := vitess.MergeExtraFlags(allowed, vts.Spec.ExtraVitessFlags, vts.Spec.ComponentVitessFlags.VttabletFlags(), pool.Vttablet.ExtraFlags); len(denied) > 0 {
+			problems = append(problems, fmt.Sprintf("vttablet in tablet pool %v: %v", poolKey, strings.Join(denied, ", ")))
+		}
+	}
+	if vts.Spec.VitessOrchestrator != nil {
+		if _, denied := vitess.MergeExtraFlags(allowed, vts.Spec.ExtraVitessFlags, vts.Spec.ComponentVitessFlags.VtorcFlags(), vts.Spec.VitessOrchestrator.ExtraFlags); len(denied) > 0 {
+			problems = append(problems, fmt.Sprintf("vtorc: %v", strings.Join(denied, ", ")))
+		}
+	}
+
+	if len(problems) == 0 {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardManagedFlagsIgnored)
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardManagedFlagsIgnored, corev1.ConditionTrue, "FlagsIgnored",
+		fmt.Sprintf("Extra flags that the operator manages are left out, unless listed in componentVitessFlags.allowManagedFlags: %v.", strings.Join(problems, "; ")))
+}
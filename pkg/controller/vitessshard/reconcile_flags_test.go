@@ -0,0 +1,103 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateManagedFlagsCondition(t *testing.T) {
+	newShard := func(poolFlags, vtorcFlags map[string]string, allowed ...string) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{
+			Spec: planetscalev2.VitessShardSpec{
+				VitessShardTemplate: planetscalev2.VitessShardTemplate{
+					TabletPools: []planetscalev2.VitessShardTabletPool{
+						{
+							Cell: "zone1",
+							Type: planetscalev2.ReplicaPoolType,
+							VitessShardTabletPoolTemplate: planetscalev2.VitessShardTabletPoolTemplate{
+								Vttablet: planetscalev2.VttabletSpec{ExtraFlags: poolFlags},
+							},
+						},
+					},
+				},
+				VitessOrchestrator:   &planetscalev2.VitessOrchestratorSpec{ExtraFlags: vtorcFlags},
+				ComponentVitessFlags: &planetscalev2.ComponentVitessFlags{AllowManagedFlags: allowed},
+			},
+			Status: planetscalev2.NewVitessShardStatus(),
+		}
+		return vts
+	}
+
+	table := []struct {
+		name        string
+		vts         *planetscalev2.VitessShard
+		wantMessage []string
+	}{
+		{
+			name: "no managed flags",
+			vts:  newShard(map[string]string{"queryserver-config-pool-size": "10"}, nil),
+		},
+		{
+			name:        "tablet pool and vtorc",
+			vts:         newShard(map[string]string{"init_shard": "-80", "tablet_hostname": "a"}, map[string]string{"--cell": "zone2"}),
+			wantMessage: []string{"vttablet in tablet pool zone1/replica: init_shard", "vtorc: cell"},
+		},
+		{
+			name: "allowed",
+			vts:  newShard(map[string]string{"grpc_port": "16000"}, nil, "grpc-port"),
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			updateManagedFlagsCondition(test.vts)
+			cond, ok := test.vts.Status.Conditions[planetscalev2.VitessShardManagedFlagsIgnored]
+			if len(test.wantMessage) == 0 {
+				if ok {
+					t.Fatalf("got condition %v; want none", cond)
+				}
+				return
+			}
+			if !ok || cond.Status != corev1.ConditionTrue {
+				t.Fatalf("got condition %v; want status True", cond)
+			}
+			for _, want := range test.wantMessage {
+				if !strings.Contains(cond.Message, want) {
+					t.Errorf("condition message %q doesn't contain %q", cond.Message, want)
+				}
+			}
+			if strings.Contains(cond.Message, "tablet_hostname") {
+				t.Errorf("condition message %q mentions tablet_hostname, which can be overridden", cond.Message)
+			}
+		})
+	}
+
+	// The condition goes away once the flags are removed.
+	vts := newShard(map[string]string{"port": "1"}, nil)
+	updateManagedFlagsCondition(vts)
+	vts.Spec.TabletPools[0].Vttablet.ExtraFlags = nil
+	updateManagedFlagsCondition(vts)
+	if cond, ok := vts.Status.Conditions[planetscalev2.VitessShardManagedFlagsIgnored]; ok {
+		t.Errorf("got condition %v after removing the flags; want none", cond)
+	}
+}
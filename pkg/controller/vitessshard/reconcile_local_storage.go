@@ -0,0 +1,253 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// volumeNodeConflict is how the scheduler reports that no Node can run a Pod
+// because of the node affinity of its volumes. For a bound local volume,
+// that happens when the Node it's on is gone, but also whenever that Node is
+// cordoned, drained, tainted or full, so it's only a hint to check the Node.
+const volumeNodeConflict = "volume node affinity conflict"
+
+// localStorageRetryInterval is how long to wait before checking again
+// whether another tablet can be recreated, while one is being recreated.
+const localStorageRetryInterval = time.Minute
+
+/*
+reconcileLocalStorage deletes the PVCs and Pods of tablets on local volumes
+whose Node is gone, so they're recreated on another Node and restore the
+latest backup there.
+
+A Node only counts as gone once its Node object has been deleted. At most one
+tablet of the shard is recreated at a time: we wait until every other tablet,
+including one that was just recreated and is restoring, is ready again.
+*/
+func (r *ReconcileVitessShard) reconcileLocalStorage(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	pools := make(map[string]*planetscalev2.VitessShardTabletPool)
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if pool.LocalStorage != nil && pool.DataVolumeClaimTemplate != nil && !pool.IsScratch() {
+			pools[pool.Cell+"/"+pool.ID()] = pool
+		}
+	}
+	if len(pools) == 0 {
+		return resultBuilder.Result()
+	}
+
+	tabletPods, err := r.tabletPodsFromShard(ctx, vts)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+
+	now := time.Now()
+	lost := map[string]bool{}
+	var candidates []string
+	for tabletKey, pod := range tabletPods {
+		pool := pools[pod.Labels[planetscalev2.CellLabel]+"/"+vttablet.PoolIDFromPod(pod)]
+		if pool == nil || pod.DeletionTimestamp != nil {
+			continue
+		}
+		since, ok := volumeNodeConflictSince(pod)
+		if !ok {
+			continue
+		}
+		gone, err := r.volumeNodeGone(ctx, pod)
+		if err != nil {
+			resultBuilder.Error(err)
+			continue
+		}
+		if !gone {
+			// The Node still exists, so it's only unavailable for now.
+			continue
+		}
+		lost[tabletKey] = true
+		if wait := pool.LocalStorage.NodeLossTimeout.Duration - now.Sub(since); wait > 0 {
+			resultBuilder.RequeueAfter(wait)
+			continue
+		}
+		candidates = append(candidates, tabletKey)
+	}
+	if len(candidates) == 0 {
+		return resultBuilder.Result()
+	}
+	sort.Strings(candidates)
+
+	// Never recreate more than one tablet of the shard at a time.
+	if busy := unreadyTablet(vts, lost); busy != "" {
+		resultBuilder.RequeueAfter(localStorageRetryInterval)
+		return resultBuilder.Result()
+	}
+
+	for _, tabletKey := range candidates {
+		pod := tabletPods[tabletKey]
+		pool := pools[pod.Labels[planetscalev2.CellLabel]+"/"+vttablet.PoolIDFromPod(pod)]
+
+		// Leave the primary for vtorc to fail over, and never throw away data
+		// that there's no backup of.
+		if tabletKey == vts.Status.MasterAlias {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "NodeLostPrimary", "Not recreating tablet %v, whose local volume's Node is gone, while it's the primary.", tabletKey)
+			continue
+		}
+		if vts.Status.HasInitialBackup != corev1.ConditionTrue {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "NodeLostNoBackup", "Not recreating tablet %v, whose local volume's Node is gone, until the shard has a backup to restore.", tabletKey)
+			continue
+		}
+
+		// The PVCs are only removed once the Pod that uses them is gone.
+		pvcNames := []string{pod.Name}
+		for i := range pool.ExtraVolumeClaimTemplates {
+			pvcNames = append(pvcNames, vttablet.ExtraPVCName(pod.Name, pool.ExtraVolumeClaimTemplates[i].Name))
+		}
+		for _, name := range pvcNames {
+			pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: name}}
+			if err := r.client.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "NodeLostFailed", "failed to delete PVC %v of tablet %v: %v", name, tabletKey, err)
+				return resultBuilder.Error(err)
+			}
+		}
+		if err := r.releaseTabletPod(ctx, pod, true); err != nil && !apierrors.IsNotFound(err) {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "NodeLostFailed", "failed to delete Pod of tablet %v: %v", tabletKey, err)
+			return resultBuilder.Error(err)
+		}
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "NodeLost", "Deleted PVCs and Pod of tablet %v, whose local volume's Node is gone, to restore it from backup on another Node.", tabletKey)
+		// The others wait until this one is ready again.
+		resultBuilder.RequeueAfter(localStorageRetryInterval)
+		break
+	}
+
+	return resultBuilder.Result()
+}
+
+// unreadyTablet returns the first tablet of the shard, other than those whose
+// Node is lost, that isn't ready, or "" if there's none.
+func unreadyTablet(vts *planetscalev2.VitessShard, lost map[string]bool) string {
+	tabletKeys := make([]string, 0, len(vts.Status.Tablets))
+	for tabletKey := range vts.Status.Tablets {
+		tabletKeys = append(tabletKeys, tabletKey)
+	}
+	sort.Strings(tabletKeys)
+	for _, tabletKey := range tabletKeys {
+		if !lost[tabletKey] && vts.Status.Tablets[tabletKey].Ready != corev1.ConditionTrue {
+			return tabletKey
+		}
+	}
+	return ""
+}
+
+// volumeNodeGone returns whether the Node that the local volume of a tablet's
+// data PVC is pinned to has been deleted. Nodes that are cordoned, drained,
+// tainted or full still exist, so their volumes aren't given up on.
+func (r *ReconcileVitessShard) volumeNodeGone(ctx context.Context, pod *corev1.Pod) (bool, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: pod.Name}, pvc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if pvc.Spec.VolumeName == "" {
+		return false, nil
+	}
+	pv := &corev1.PersistentVolume{}
+	if err := r.apiReader.Get(ctx, client.ObjectKey{Name: pvc.Spec.VolumeName}, pv); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	hostnames := localVolumeHostnames(pv)
+	if len(hostnames) == 0 {
+		// It's not a local volume we understand, so leave it alone.
+		return false, nil
+	}
+
+	for _, hostname := range hostnames {
+		node := &corev1.Node{}
+		err := r.apiReader.Get(ctx, client.ObjectKey{Name: hostname}, node)
+		if err == nil {
+			return false, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	// Node names usually match their hostname label, but they don't have to.
+	requirement, err := apilabels.NewRequirement(k8s.HostnameLabel, selection.In, hostnames)
+	if err != nil {
+		return false, err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.apiReader.List(ctx, nodes, client.MatchingLabelsSelector{Selector: apilabels.NewSelector().Add(*requirement)}); err != nil {
+		return false, err
+	}
+	return len(nodes.Items) == 0, nil
+}
+
+// localVolumeHostnames returns the hostnames of the Nodes that the node
+// affinity of a local PersistentVolume pins it to.
+func localVolumeHostnames(pv *corev1.PersistentVolume) []string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return nil
+	}
+	var hostnames []string
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == k8s.HostnameLabel && expr.Operator == corev1.NodeSelectorOpIn {
+				hostnames = append(hostnames, expr.Values...)
+			}
+		}
+	}
+	return hostnames
+}
+
+// volumeNodeConflictSince returns when a Pod became unschedulable because of
+// the node affinity of its volumes, if it is.
+func volumeNodeConflictSince(pod *corev1.Pod) (time.Time, bool) {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return time.Time{}, false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.PodScheduled {
+			continue
+		}
+		if cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable && strings.Contains(cond.Message, volumeNodeConflict) {
+			return cond.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
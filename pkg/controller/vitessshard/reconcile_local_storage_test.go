@@ -0,0 +1,255 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+)
+
+// localStorageShard returns a shard with one replica pool on local volumes,
+// and the given tablets, which are all ready.
+func localStorageShard(uids ...uint32) *planetscalev2.VitessShard {
+	vts := testShard(planetscalev2.VitessShardTabletPool{
+		Cell: "zone1",
+		Type: planetscalev2.ReplicaPoolType,
+		VitessShardTabletPoolTemplate: planetscalev2.VitessShardTabletPoolTemplate{
+			DataVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{},
+			LocalStorage: &planetscalev2.VitessTabletPoolLocalStorage{
+				NodeLossTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+		},
+	})
+	vts.Status.HasInitialBackup = corev1.ConditionTrue
+	vts.Status.MasterAlias = testTabletAlias(100)
+	for _, uid := range uids {
+		vts.Status.Tablets[testTabletAlias(uid)] = planetscalev2.VitessTabletStatus{Ready: corev1.ConditionTrue}
+	}
+	return vts
+}
+
+// localStorageTablet returns the Pod, PVC and local PV of a tablet on the
+// given Node. If stuck is set, the Pod has been unschedulable for an hour
+// because of the node affinity of its volume.
+func localStorageTablet(vts *planetscalev2.VitessShard, uid uint32, nodeName string, stuck bool) []client.Object {
+	pod := testTabletPod(vts, uid, planetscalev2.ReplicaPoolType)
+	name := pod.Name
+	if stuck {
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:               corev1.PodScheduled,
+					Status:             corev1.ConditionFalse,
+					Reason:             corev1.PodReasonUnschedulable,
+					Message:            "0/3 nodes are available: 3 node(s) had volume node affinity conflict.",
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+				},
+			},
+		}
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: vts.Namespace, Name: name},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-" + name},
+	}
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-" + name},
+		Spec: corev1.PersistentVolumeSpec{
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: k8s.HostnameLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{nodeName}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return []client.Object{pod, pvc, pv}
+}
+
+func localStorageNode(name string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{k8s.HostnameLabel: name},
+		},
+	}
+}
+
+func TestReconcileLocalStorage(t *testing.T) {
+	cordoned := localStorageNode("node-1")
+	cordoned.Spec.Unschedulable = true
+
+	drained := localStorageNode("node-1")
+	drained.Spec.Unschedulable = true
+	drained.Spec.Taints = []corev1.Taint{{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule}}
+
+	renamed := localStorageNode("node-1-abcde")
+	renamed.Labels[k8s.HostnameLabel] = "node-1"
+
+	table := []struct {
+		name string
+		// nodes are the Nodes that exist, besides node-0, where the primary
+		// (tablet 100) is.
+		nodes []client.Object
+		// stuck are the tablets that are on node-1 and unschedulable.
+		stuck []uint32
+		// notReady are other tablets that aren't ready.
+		notReady []uint32
+		noBackup bool
+		// wantDeleted are the tablets whose Pod and PVC should be deleted.
+		wantDeleted []uint32
+	}{
+		{
+			name:  "cordoned node",
+			nodes: []client.Object{cordoned},
+			stuck: []uint32{101},
+		},
+		{
+			name:  "drained node",
+			nodes: []client.Object{drained},
+			stuck: []uint32{101},
+		},
+		{
+			name:  "node name differs from hostname",
+			nodes: []client.Object{renamed},
+			stuck: []uint32{101},
+		},
+		{
+			name:        "deleted node",
+			stuck:       []uint32{101},
+			wantDeleted: []uint32{101},
+		},
+		{
+			name:        "one at a time",
+			stuck:       []uint32{101, 102},
+			wantDeleted: []uint32{101},
+		},
+		{
+			name:     "other tablet not ready",
+			stuck:    []uint32{101},
+			notReady: []uint32{103},
+		},
+		{
+			name:     "no backup",
+			stuck:    []uint32{101},
+			noBackup: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			vts := localStorageShard(100, 101, 102, 103)
+			if test.noBackup {
+				vts.Status.HasInitialBackup = corev1.ConditionFalse
+			}
+			for _, uid := range test.notReady {
+				vts.Status.Tablets[testTabletAlias(uid)] = planetscalev2.VitessTabletStatus{Ready: corev1.ConditionFalse}
+			}
+
+			objs := append([]client.Object{localStorageNode("node-0")}, test.nodes...)
+			stuck := map[uint32]bool{}
+			for _, uid := range test.stuck {
+				stuck[uid] = true
+				// Stuck tablets aren't ready, of course.
+				vts.Status.Tablets[testTabletAlias(uid)] = planetscalev2.VitessTabletStatus{Ready: corev1.ConditionFalse}
+			}
+			for _, uid := range []uint32{100, 101, 102, 103} {
+				node := "node-0"
+				if stuck[uid] {
+					node = "node-1"
+				}
+				objs = append(objs, localStorageTablet(vts, uid, node, stuck[uid])...)
+			}
+
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := &ReconcileVitessShard{
+				client:    c,
+				apiReader: c,
+				recorder:  record.NewFakeRecorder(100),
+			}
+			if _, err := r.reconcileLocalStorage(ctx, vts); err != nil {
+				t.Fatalf("reconcileLocalStorage() error: %v", err)
+			}
+
+			wantDeleted := map[uint32]bool{}
+			for _, uid := range test.wantDeleted {
+				wantDeleted[uid] = true
+			}
+			for _, uid := range []uint32{100, 101, 102, 103} {
+				name := fmt.Sprintf("tablet-%d", uid)
+				key := client.ObjectKey{Namespace: vts.Namespace, Name: name}
+				podErr := c.Get(ctx, key, &corev1.Pod{})
+				pvcErr := c.Get(ctx, key, &corev1.PersistentVolumeClaim{})
+				if got := apierrors.IsNotFound(podErr); got != wantDeleted[uid] {
+					t.Errorf("Pod %v deleted = %v; want %v", name, got, wantDeleted[uid])
+				}
+				if got := apierrors.IsNotFound(pvcErr); got != wantDeleted[uid] {
+					t.Errorf("PVC %v deleted = %v; want %v", name, got, wantDeleted[uid])
+				}
+			}
+		})
+	}
+}
+
+func TestLocalVolumeHostnames(t *testing.T) {
+	pv := &corev1.PersistentVolume{
+		Spec: corev1.PersistentVolumeSpec{
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: k8s.ZoneFailureDomainLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{"zone1"}},
+								{Key: k8s.HostnameLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}},
+							},
+						},
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: k8s.HostnameLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"node-2"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	got := localVolumeHostnames(pv)
+	if len(got) != 1 || got[0] != "node-1" {
+		t.Errorf("localVolumeHostnames() = %v; want [node-1]", got)
+	}
+	if got := localVolumeHostnames(&corev1.PersistentVolume{}); got != nil {
+		t.Errorf("localVolumeHostnames() = %v; want nil", got)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
+)
+
+// updateMaintenanceCondition reports whether a maintenance window in the
+// update strategy is open, and why not. It's a condition rather than an event
+// so it's reported once, rather than by every step that waits for a window
+// on every reconcile.
+func updateMaintenanceCondition(vts *planetscalev2.VitessShard) {
+	var windows []planetscalev2.MaintenanceWindow
+	if vts.Spec.UpdateStrategy != nil {
+		windows = vts.Spec.UpdateStrategy.MaintenanceWindows
+	}
+	if len(windows) == 0 {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardMaintenanceWindowOpen)
+		return
+	}
+
+	now := time.Now()
+	// Invalid windows never open, whether or not another one is open now.
+	open, _ := maintenance.Open(windows, now)
+	status, reason := corev1.ConditionTrue, "Open"
+	message := "A maintenance window is open, so disruptive changes are applied."
+	if !open {
+		status, reason = corev1.ConditionFalse, "Closed"
+		message = fmt.Sprintf("Not applying disruptive changes: %v.", maintenance.Describe(windows, now))
+	}
+	if err := maintenance.Validate(windows); err != nil {
+		reason = "InvalidMaintenanceWindow"
+		message = fmt.Sprintf("Ignoring an invalid maintenance window: %v. %v", err, message)
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardMaintenanceWindowOpen, status, reason, message)
+}
+
+// disruptionAllowed returns whether a disruptive change to the shard's
+// tablets, like a Pod restart or a PVC resize, may be applied now according
+// to the maintenance windows in the update strategy. The MaintenanceWindowOpen
+// condition says when the next window opens, if it's not allowed.
+func disruptionAllowed(vts *planetscalev2.VitessShard) bool {
+	cond, ok := vts.Status.Conditions[planetscalev2.VitessShardMaintenanceWindowOpen]
+	return !ok || cond.Status == corev1.ConditionTrue
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateMaintenanceCondition(t *testing.T) {
+	always := planetscalev2.MaintenanceWindow{Schedule: "* * * * *", Duration: metav1.Duration{Duration: time.Hour}}
+	never := planetscalev2.MaintenanceWindow{Schedule: "0 0 31 2 *", Duration: metav1.Duration{Duration: time.Hour}}
+	invalid := planetscalev2.MaintenanceWindow{Schedule: "* * * * *"}
+
+	table := []struct {
+		name        string
+		windows     []planetscalev2.MaintenanceWindow
+		wantStatus  corev1.ConditionStatus
+		wantReason  string
+		wantAllowed bool
+	}{
+		{
+			name:        "no windows",
+			wantAllowed: true,
+		},
+		{
+			name:        "open",
+			windows:     []planetscalev2.MaintenanceWindow{never, always},
+			wantStatus:  corev1.ConditionTrue,
+			wantReason:  "Open",
+			wantAllowed: true,
+		},
+		{
+			name:       "closed",
+			windows:    []planetscalev2.MaintenanceWindow{never},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: "Closed",
+		},
+		{
+			name:        "invalid window ignored",
+			windows:     []planetscalev2.MaintenanceWindow{invalid, always},
+			wantStatus:  corev1.ConditionTrue,
+			wantReason:  "InvalidMaintenanceWindow",
+			wantAllowed: true,
+		},
+		{
+			name:       "only invalid windows",
+			windows:    []planetscalev2.MaintenanceWindow{invalid},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: "InvalidMaintenanceWindow",
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vts := &planetscalev2.VitessShard{
+				Spec: planetscalev2.VitessShardSpec{
+					UpdateStrategy: &planetscalev2.VitessClusterUpdateStrategy{MaintenanceWindows: test.windows},
+				},
+				Status: planetscalev2.NewVitessShardStatus(),
+			}
+			// A condition left from before is updated or removed.
+			vts.Status.SetConditionStatus(planetscalev2.VitessShardMaintenanceWindowOpen, corev1.ConditionFalse, "Closed", "old")
+
+			updateMaintenanceCondition(vts)
+			cond, ok := vts.Status.Conditions[planetscalev2.VitessShardMaintenanceWindowOpen]
+			if test.wantStatus == "" {
+				if ok {
+					t.Errorf("got condition %v; want none", cond)
+				}
+			} else if !ok || cond.Status != test.wantStatus || cond.Reason != test.wantReason {
+				t.Errorf("got condition %v; want status %v, reason %v", cond, test.wantStatus, test.wantReason)
+			}
+			if got := disruptionAllowed(vts); got != test.wantAllowed {
+				t.Errorf("disruptionAllowed() = %v; want %v", got, test.wantAllowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/backupgate"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlupgrade"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+/*
+reconcileMysqlUpgrade orchestrates a MySQL major version upgrade, which we
+detect as a tablet Pod running a different mysqld flavor than the spec asks
+for. It must run before reconcileTablets, which keeps each tablet on the old
+image until it's listed in status.mysqlUpgrade.upgradedTablets.
+
+Only one tablet is changed at a time, and only once every tablet is
+available again after the previous change. The primary is upgraded last,
+after the drain that precedes its restart reparents it to an upgraded replica.
+
+Replicas of the operator that aren't the leader keep the leader's record in
+status.mysqlUpgrade as it is, since they can't make the changes that go
+with advancing it.
+*/
+func (r *ReconcileVitessShard) reconcileMysqlUpgrade(ctx context.Context, vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	desired := vts.Spec.Images.Mysqld
+
+	// Keep pinning tablets to their images even if we fail below.
+	vts.Status.MysqlUpgrade = oldStatus.MysqlUpgrade.DeepCopy()
+	if reconciler.IsReadOnly(ctx) {
+		return resultBuilder.Result()
+	}
+
+	tabletPods, err := r.tabletPodsFromShard(ctx, vts)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+	tabletKeys := oldStatus.TabletAliases()
+
+	status := vts.Status.MysqlUpgrade
+	if status == nil {
+		if vts.Spec.UpdateStrategy.MysqlUpgrade == nil || desired == nil {
+			return resultBuilder.Result()
+		}
+		from := podWithOtherFlavor(tabletKeys, tabletPods, desired.Flavor())
+		if from == nil {
+			return resultBuilder.Result()
+		}
+		status = &planetscalev2.VitessShardMysqlUpgradeStatus{
+			FromImage: *from,
+			ToImage:   *desired,
+			Phase:     planetscalev2.MysqlUpgradeReplicasPhase,
+		}
+		// Tablets that already run the new flavor don't need to be upgraded.
+		for _, tabletKey := range tabletKeys {
+			if podRunsFlavor(tabletPods[tabletKey], desired.Flavor()) {
+				status.UpgradedTablets = append(status.UpgradedTablets, tabletKey)
+			}
+		}
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeStarted", "Started upgrading mysqld from %v to %v", from.Image(), desired.Image())
+	}
+	status.Message = ""
+	vts.Status.MysqlUpgrade = status
+
+	// Changing the spec back to the old flavor aborts the upgrade, just like
+	// the annotation does. Once we're back on the old flavor, we're done.
+	reverted := desired == nil || desired.Flavor() == status.FromImage.Flavor()
+	if reverted && len(status.UpgradedTablets) == 0 {
+		vts.Status.MysqlUpgrade = nil
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeRolledBack", "All tablets are back on mysqld %v", status.FromImage.Image())
+		return resultBuilder.Result()
+	}
+	if !reverted {
+		status.ToImage = *desired
+	}
+
+	abort := reverted || mysqlupgrade.AbortRequested(vts)
+	switch status.Phase {
+	case planetscalev2.MysqlUpgradeReplicasPhase:
+		if abort {
+			status.Phase = planetscalev2.MysqlUpgradeRollingBackPhase
+			r.recorder.Event(vts, corev1.EventTypeNormal, "MysqlUpgradeAborted", "Rolling back upgraded replicas")
+		}
+	case planetscalev2.MysqlUpgradeRollingBackPhase, planetscalev2.MysqlUpgradeAbortedPhase:
+		if !abort {
+			status.Phase = planetscalev2.MysqlUpgradeReplicasPhase
+			r.recorder.Event(vts, corev1.EventTypeNormal, "MysqlUpgradeResumed", "Resuming upgrade")
+		}
+	}
+
+	if reconciler.IsPaused(ctx) {
+		status.Message = "reconciliation is paused"
+		return resultBuilder.Result()
+	}
+
+	// Wait for every tablet to be available before changing the next one.
+	// That's also how we verify that an upgraded tablet is replicating.
+	for _, tabletKey := range tabletKeys {
+		pod := tabletPods[tabletKey]
+		if pod == nil || pod.DeletionTimestamp != nil {
+			status.Message = fmt.Sprintf("waiting for tablet %v to be created", tabletKey)
+			return resultBuilder.Result()
+		}
+		if upgradeListed(status, tabletKey) && !podRunsFlavor(pod, status.ToImage.Flavor()) && status.Phase != planetscalev2.MysqlUpgradeRollingBackPhase {
+			status.Message = fmt.Sprintf("upgrading tablet %v", tabletKey)
+			return resultBuilder.Merge(r.upgradeTabletPod(ctx, vts, tabletKey, pod))
+		}
+		if rollout.Released(pod) || oldStatus.Tablets[tabletKey].Available != corev1.ConditionTrue {
+			status.Message = fmt.Sprintf("waiting for tablet %v to be available", tabletKey)
+			return resultBuilder.Result()
+		}
+	}
+
+	primaryAlias := oldStatus.MasterAlias
+	if primaryAlias == "" {
+		status.Message = "waiting for the shard to have a primary"
+		return resultBuilder.Result()
+	}
+
+	switch status.Phase {
+	case planetscalev2.MysqlUpgradeReplicasPhase:
+		for _, tabletKey := range tabletKeys {
+			if tabletKey == primaryAlias || upgradeListed(status, tabletKey) || vttablet.MysqldImageFromPod(tabletPods[tabletKey]) == nil {
+				continue
+			}
+			return resultBuilder.Merge(r.startTabletUpgrade(vts, status, tabletKey))
+		}
+		status.Phase = planetscalev2.MysqlUpgradePrimaryPhase
+		fallthrough
+	case planetscalev2.MysqlUpgradePrimaryPhase:
+		if abort {
+			status.Message = "the upgrade can't be aborted anymore, because the primary is being upgraded"
+		}
+		if !upgradeListed(status, primaryAlias) && vttablet.MysqldImageFromPod(tabletPods[primaryAlias]) != nil {
+			return resultBuilder.Merge(r.startTabletUpgrade(vts, status, primaryAlias))
+		}
+		vts.Status.MysqlUpgrade = nil
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeComplete", "All tablets were upgraded to mysqld %v", status.ToImage.Image())
+	case planetscalev2.MysqlUpgradeRollingBackPhase:
+		if len(status.UpgradedTablets) == 0 {
+			status.Phase = planetscalev2.MysqlUpgradeAbortedPhase
+			r.recorder.Event(vts, corev1.EventTypeNormal, "MysqlUpgradeAborted", "All upgraded replicas were rolled back")
+			return resultBuilder.Result()
+		}
+		return resultBuilder.Merge(r.rollBackTablet(ctx, vts, status, tabletPods, primaryAlias))
+	case planetscalev2.MysqlUpgradeAbortedPhase:
+		status.Message = fmt.Sprintf("aborted; remove the %v annotation to try again", mysqlupgrade.AbortAnnotation)
+	}
+	return resultBuilder.Result()
+}
+
+// startTabletUpgrade lists a tablet as upgraded, so reconcileTablets gives it
+// the new image. The Pod is restarted on the next pass.
+func (r *ReconcileVitessShard) startTabletUpgrade(vts *planetscalev2.VitessShard, status *planetscalev2.VitessShardMysqlUpgradeStatus, tabletKey string) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	if err := r.checkMysqlUpgradeMethod(vts); err != nil {
+		status.Message = err.Error()
+		return resultBuilder.Result()
+	}
+	if !disruptionAllowed(vts) {
+		status.Message = "waiting for a maintenance window"
+		return resultBuilder.Result()
+	}
+	if err := backupgate.Check(vts, time.Now()); err != nil {
+		status.Message = err.Error()
+		return resultBuilder.Result()
+	}
+	status.UpgradedTablets = append(status.UpgradedTablets, tabletKey)
+	status.Message = fmt.Sprintf("upgrading tablet %v", tabletKey)
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeTablet", "Upgrading tablet %v to mysqld %v", tabletKey, status.ToImage.Image())
+	return resultBuilder.Result()
+}
+
+// upgradeTabletPod restarts a tablet that was chosen for upgrade, using the
+// configured method.
+func (r *ReconcileVitessShard) upgradeTabletPod(ctx context.Context, vts *planetscalev2.VitessShard, tabletKey string, pod *corev1.Pod) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	if vts.Spec.UpdateStrategy.MysqlUpgrade != nil && vts.Spec.UpdateStrategy.MysqlUpgrade.Method == planetscalev2.RestoreFromBackupMysqlUpgradeMethod {
+		// The tablet might be the primary, so drain it first. That's a
+		// no-op for other tablets.
+		if drain.Supported(pod) && !drain.Finished(pod) {
+			if !drain.Started(pod) {
+				drain.Start(pod, "MySQL major version upgrade")
+				if err := r.client.Update(ctx, pod); err != nil {
+					return resultBuilder.Error(err)
+				}
+			}
+			return resultBuilder.Result()
+		}
+		if err := r.recreateTabletFromBackup(ctx, pod); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "MysqlUpgradeBlocked", "failed to recreate tablet %v: %v", tabletKey, err)
+			return resultBuilder.Error(err)
+		}
+		return resultBuilder.Result()
+	}
+
+	// Upgrade in place by releasing the Pod, which was scheduled for update
+	// once reconcileTablets gave it the new image. The rolling update drains
+	// the tablet before it's restarted, which reparents away from a primary.
+	if !rollout.Scheduled(pod) || rollout.Released(pod) {
+		return resultBuilder.Result()
+	}
+	if err := r.releaseTabletPod(ctx, pod, loneMaster(vts, pod)); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "MysqlUpgradeBlocked", "release of Pod %v (tablet %v) failed: %v", pod.Name, tabletKey, err)
+		return resultBuilder.Error(err)
+	}
+	return resultBuilder.Result()
+}
+
+// rollBackTablet restores the most recently upgraded tablet from a backup
+// with the old image, since MySQL can't downgrade a data directory in place.
+func (r *ReconcileVitessShard) rollBackTablet(ctx context.Context, vts *planetscalev2.VitessShard, status *planetscalev2.VitessShardMysqlUpgradeStatus, tabletPods map[string]*corev1.Pod, primaryAlias string) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	last := len(status.UpgradedTablets) - 1
+	tabletKey := status.UpgradedTablets[last]
+	pod := tabletPods[tabletKey]
+
+	if pod != nil && !podRunsFlavor(pod, status.ToImage.Flavor()) {
+		// It never got restarted with the new image, so there's nothing to undo.
+		status.UpgradedTablets = status.UpgradedTablets[:last]
+		return resultBuilder.Result()
+	}
+	if tabletKey == primaryAlias {
+		status.Message = fmt.Sprintf("can't roll back tablet %v while it's the primary; reparent to a tablet that runs mysqld %v first", tabletKey, status.FromImage.Image())
+		return resultBuilder.Result()
+	}
+	if len(vts.Spec.BackupLocations) == 0 {
+		status.Message = "can't roll back upgraded tablets without backups; configure backups, or remove the abort annotation to finish the upgrade"
+		return resultBuilder.Result()
+	}
+	if !disruptionAllowed(vts) {
+		status.Message = "waiting for a maintenance window"
+		return resultBuilder.Result()
+	}
+
+	status.UpgradedTablets = status.UpgradedTablets[:last]
+	status.Message = fmt.Sprintf("rolling back tablet %v", tabletKey)
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeRollback", "Restoring tablet %v from backup with mysqld %v", tabletKey, status.FromImage.Image())
+	if pod == nil {
+		return resultBuilder.Result()
+	}
+	if err := r.recreateTabletFromBackup(ctx, pod); err != nil {
+		return resultBuilder.Error(err)
+	}
+	return resultBuilder.Result()
+}
+
+// recreateTabletFromBackup deletes a tablet's data volume and Pod, so the
+// tablet is recreated empty and restores the latest backup.
+func (r *ReconcileVitessShard) recreateTabletFromBackup(ctx context.Context, pod *corev1.Pod) error {
+	// The data volume PVC has the same name as the Pod. It won't actually go
+	// away until the Pod is gone.
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: pod.Name}, pvc)
+	if err == nil {
+		if err := r.client.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err := r.client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileVitessShard) checkMysqlUpgradeMethod(vts *planetscalev2.VitessShard) error {
+	strategy := vts.Spec.UpdateStrategy.MysqlUpgrade
+	if strategy != nil && strategy.Method == planetscalev2.RestoreFromBackupMysqlUpgradeMethod && len(vts.Spec.BackupLocations) == 0 {
+		return fmt.Errorf("the %v upgrade method requires backups to be configured", strategy.Method)
+	}
+	return nil
+}
+
+// pinMysqldImage sets the mysqld image of a tablet according to the
+// progress of a MySQL major version upgrade.
+func pinMysqldImage(tablet *vttablet.Spec, upgrade *planetscalev2.VitessShardMysqlUpgradeStatus) {
+	if tablet.Images.Mysqld == nil {
+		return
+	}
+	if upgradeListed(upgrade, tablet.AliasStr) {
+		tablet.Images.Mysqld = upgrade.ToImage.DeepCopy()
+	} else {
+		tablet.Images.Mysqld = upgrade.FromImage.DeepCopy()
+	}
+}
+
+func upgradeListed(status *planetscalev2.VitessShardMysqlUpgradeStatus, tabletKey string) bool {
+	for _, upgraded := range status.UpgradedTablets {
+		if upgraded == tabletKey {
+			return true
+		}
+	}
+	return false
+}
+
+// podWithOtherFlavor returns the mysqld image of the first tablet Pod that
+// runs a different flavor, or nil if there are none.
+func podWithOtherFlavor(tabletKeys []string, tabletPods map[string]*corev1.Pod, flavor string) *planetscalev2.MysqldImage {
+	for _, tabletKey := range tabletKeys {
+		pod := tabletPods[tabletKey]
+		if pod == nil {
+			continue
+		}
+		if image := vttablet.MysqldImageFromPod(pod); image != nil && image.Flavor() != flavor {
+			return image
+		}
+	}
+	return nil
+}
+
+func podRunsFlavor(pod *corev1.Pod, flavor string) bool {
+	if pod == nil {
+		return false
+	}
+	image := vttablet.MysqldImageFromPod(pod)
+	return image != nil && image.Flavor() == flavor
+}
@@ -0,0 +1,580 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlupgrade"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/statusupdate"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+var (
+	oldMysqld = planetscalev2.MysqldImage{Mysql56Compatible: "mysql:5.7"}
+	newMysqld = planetscalev2.MysqldImage{Mysql80Compatible: "mysql:8.0"}
+
+	// mysqlUpgradeUIDs are the tablets of the test shard. The first one is
+	// the primary.
+	mysqlUpgradeUIDs = []uint32{100, 101, 102}
+)
+
+// mysqlUpgradeShard returns a shard whose spec asks for the new mysqld
+// image, and whose tablets are all available.
+func mysqlUpgradeShard() *planetscalev2.VitessShard {
+	vts := testShard(planetscalev2.VitessShardTabletPool{
+		Cell:     "zone1",
+		Type:     planetscalev2.ReplicaPoolType,
+		Replicas: int32(len(mysqlUpgradeUIDs)),
+	})
+	vts.Spec.Images.Mysqld = newMysqld.DeepCopy()
+	vts.Spec.BackupLocations = []planetscalev2.VitessBackupLocation{{}}
+	vts.Spec.UpdateStrategy = &planetscalev2.VitessClusterUpdateStrategy{
+		MysqlUpgrade: &planetscalev2.MysqlUpgradeStrategy{
+			Method: planetscalev2.InPlaceMysqlUpgradeMethod,
+		},
+	}
+	vts.Status.MasterAlias = testTabletAlias(mysqlUpgradeUIDs[0])
+	for _, uid := range mysqlUpgradeUIDs {
+		vts.Status.Tablets[testTabletAlias(uid)] = planetscalev2.VitessTabletStatus{Available: corev1.ConditionTrue}
+	}
+	return vts
+}
+
+// mysqlUpgradeTablet returns the Pod and data PVC of a tablet that runs the
+// given mysqld image.
+func mysqlUpgradeTablet(vts *planetscalev2.VitessShard, uid uint32, image *planetscalev2.MysqldImage) (*corev1.Pod, *corev1.PersistentVolumeClaim) {
+	pod := testTabletPod(vts, uid, planetscalev2.ReplicaPoolType)
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name:  "mysqld",
+			Image: image.Image(),
+			Env:   []corev1.EnvVar{{Name: "MYSQL_FLAVOR", Value: image.Flavor()}},
+		},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: vts.Namespace, Name: pod.Name},
+	}
+	return pod, pvc
+}
+
+func TestReconcileMysqlUpgrade(t *testing.T) {
+	table := []struct {
+		name   string
+		status *planetscalev2.VitessShardMysqlUpgradeStatus
+		// upgraded are the tablets whose Pods already run the new image.
+		upgraded []uint32
+		// missing are the tablets whose Pods don't exist.
+		missing []uint32
+		// unavailable are the tablets that aren't available.
+		unavailable []uint32
+		// scheduled are the tablets whose Pods have pending changes.
+		scheduled []uint32
+		abort     bool
+		// revert changes the spec back to the old image.
+		revert    bool
+		noBackups bool
+		method    planetscalev2.MysqlUpgradeMethod
+		// readOnly reconciles as a replica that isn't the leader.
+		readOnly bool
+
+		wantNil       bool
+		wantPhase     planetscalev2.VitessShardMysqlUpgradePhase
+		wantUpgraded  []uint32
+		wantMessage   string
+		wantReleased  []uint32
+		wantDrained   []uint32
+		wantRecreated []uint32
+	}{
+		{
+			name:         "start with the first replica",
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101},
+			wantMessage:  "upgrading tablet zone1-0000000101",
+		},
+		{
+			name:         "start skips tablets already upgraded",
+			upgraded:     []uint32{101},
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101, 102},
+		},
+		{
+			name:     "nothing to upgrade",
+			upgraded: mysqlUpgradeUIDs,
+			wantNil:  true,
+		},
+		{
+			name:         "release listed replica",
+			status:       replicasPhase(101),
+			scheduled:    []uint32{101},
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101},
+			wantMessage:  "upgrading tablet zone1-0000000101",
+			wantReleased: []uint32{101},
+		},
+		{
+			name:         "drain listed replica before restoring it",
+			status:       replicasPhase(101),
+			method:       planetscalev2.RestoreFromBackupMysqlUpgradeMethod,
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101},
+			wantDrained:  []uint32{101},
+		},
+		{
+			name:        "restore from backup needs backups",
+			method:      planetscalev2.RestoreFromBackupMysqlUpgradeMethod,
+			noBackups:   true,
+			wantPhase:   planetscalev2.MysqlUpgradeReplicasPhase,
+			wantMessage: "requires backups",
+		},
+		{
+			name:         "wait for upgraded replica to be available",
+			status:       replicasPhase(101),
+			upgraded:     []uint32{101},
+			unavailable:  []uint32{101},
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101},
+			wantMessage:  "waiting for tablet zone1-0000000101 to be available",
+		},
+		{
+			name:         "wait for recreated tablet after restart",
+			status:       replicasPhase(101),
+			missing:      []uint32{101},
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101},
+			wantMessage:  "waiting for tablet zone1-0000000101 to be created",
+		},
+		{
+			name:         "next replica",
+			status:       replicasPhase(101),
+			upgraded:     []uint32{101},
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101, 102},
+		},
+		{
+			name:         "follower keeps the leader's status",
+			status:       replicasPhase(101),
+			upgraded:     []uint32{101},
+			readOnly:     true,
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101},
+		},
+		{
+			name:     "follower doesn't start an upgrade",
+			readOnly: true,
+			wantNil:  true,
+		},
+		{
+			name:         "primary last",
+			status:       replicasPhase(101, 102),
+			upgraded:     []uint32{101, 102},
+			wantPhase:    planetscalev2.MysqlUpgradePrimaryPhase,
+			wantUpgraded: []uint32{101, 102, 100},
+		},
+		{
+			name: "complete",
+			status: &planetscalev2.VitessShardMysqlUpgradeStatus{
+				FromImage:       oldMysqld,
+				ToImage:         newMysqld,
+				Phase:           planetscalev2.MysqlUpgradePrimaryPhase,
+				UpgradedTablets: aliases(101, 102, 100),
+			},
+			upgraded: mysqlUpgradeUIDs,
+			wantNil:  true,
+		},
+		{
+			name: "primary can't be aborted",
+			status: &planetscalev2.VitessShardMysqlUpgradeStatus{
+				FromImage:       oldMysqld,
+				ToImage:         newMysqld,
+				Phase:           planetscalev2.MysqlUpgradePrimaryPhase,
+				UpgradedTablets: aliases(101, 102, 100),
+			},
+			upgraded:     []uint32{101, 102},
+			scheduled:    []uint32{100},
+			abort:        true,
+			wantPhase:    planetscalev2.MysqlUpgradePrimaryPhase,
+			wantUpgraded: []uint32{101, 102, 100},
+			wantReleased: []uint32{100},
+		},
+		{
+			name:          "abort rolls back the last upgraded replica",
+			status:        replicasPhase(101, 102),
+			upgraded:      []uint32{101, 102},
+			abort:         true,
+			wantPhase:     planetscalev2.MysqlUpgradeRollingBackPhase,
+			wantUpgraded:  []uint32{101},
+			wantMessage:   "rolling back tablet zone1-0000000102",
+			wantRecreated: []uint32{102},
+		},
+		{
+			name:         "abort without backups",
+			status:       replicasPhase(101),
+			upgraded:     []uint32{101},
+			abort:        true,
+			noBackups:    true,
+			wantPhase:    planetscalev2.MysqlUpgradeRollingBackPhase,
+			wantUpgraded: []uint32{101},
+			wantMessage:  "without backups",
+		},
+		{
+			name:      "abort before the replica was restarted",
+			status:    replicasPhase(101),
+			abort:     true,
+			wantPhase: planetscalev2.MysqlUpgradeRollingBackPhase,
+		},
+		{
+			name: "rolled back",
+			status: &planetscalev2.VitessShardMysqlUpgradeStatus{
+				FromImage: oldMysqld,
+				ToImage:   newMysqld,
+				Phase:     planetscalev2.MysqlUpgradeRollingBackPhase,
+			},
+			abort:     true,
+			wantPhase: planetscalev2.MysqlUpgradeAbortedPhase,
+		},
+		{
+			name: "stay aborted",
+			status: &planetscalev2.VitessShardMysqlUpgradeStatus{
+				FromImage: oldMysqld,
+				ToImage:   newMysqld,
+				Phase:     planetscalev2.MysqlUpgradeAbortedPhase,
+			},
+			abort:       true,
+			wantPhase:   planetscalev2.MysqlUpgradeAbortedPhase,
+			wantMessage: mysqlupgrade.AbortAnnotation,
+		},
+		{
+			name: "resume after abort",
+			status: &planetscalev2.VitessShardMysqlUpgradeStatus{
+				FromImage: oldMysqld,
+				ToImage:   newMysqld,
+				Phase:     planetscalev2.MysqlUpgradeAbortedPhase,
+			},
+			wantPhase:    planetscalev2.MysqlUpgradeReplicasPhase,
+			wantUpgraded: []uint32{101},
+		},
+		{
+			name:    "revert spec before anything was upgraded",
+			status:  replicasPhase(),
+			revert:  true,
+			wantNil: true,
+		},
+		{
+			name:          "revert spec rolls back",
+			status:        replicasPhase(101),
+			upgraded:      []uint32{101},
+			revert:        true,
+			wantPhase:     planetscalev2.MysqlUpgradeRollingBackPhase,
+			wantRecreated: []uint32{101},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			vts := mysqlUpgradeShard()
+			vts.Status.MysqlUpgrade = test.status
+			if test.abort {
+				mysqlupgrade.Abort(vts, "test")
+			}
+			if test.revert {
+				vts.Spec.Images.Mysqld = oldMysqld.DeepCopy()
+			}
+			if test.noBackups {
+				vts.Spec.BackupLocations = nil
+			}
+			if test.method != "" {
+				vts.Spec.UpdateStrategy.MysqlUpgrade.Method = test.method
+			}
+			if test.readOnly {
+				ctx = reconciler.NewReadOnlyContext(ctx)
+			}
+			for _, uid := range test.unavailable {
+				vts.Status.Tablets[testTabletAlias(uid)] = planetscalev2.VitessTabletStatus{Available: corev1.ConditionFalse}
+			}
+
+			var objs []client.Object
+			for _, uid := range mysqlUpgradeUIDs {
+				if containsUID(test.missing, uid) {
+					continue
+				}
+				image := &oldMysqld
+				if containsUID(test.upgraded, uid) {
+					image = &newMysqld
+				}
+				pod, pvc := mysqlUpgradeTablet(vts, uid, image)
+				if test.method == planetscalev2.RestoreFromBackupMysqlUpgradeMethod {
+					pod.Annotations = map[string]string{drain.SupportedAnnotation: ""}
+				}
+				if containsUID(test.scheduled, uid) {
+					rollout.Schedule(pod, "mysqld image changed")
+				}
+				objs = append(objs, pod, pvc)
+			}
+
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := &ReconcileVitessShard{
+				client:   c,
+				recorder: record.NewFakeRecorder(100),
+			}
+			oldStatus := vts.Status.DeepCopy()
+			if _, err := r.reconcileMysqlUpgrade(ctx, vts, oldStatus); err != nil {
+				t.Fatalf("reconcileMysqlUpgrade() error: %v", err)
+			}
+
+			status := vts.Status.MysqlUpgrade
+			if test.wantNil {
+				if status != nil {
+					t.Fatalf("status.mysqlUpgrade = %+v; want nil", status)
+				}
+				return
+			}
+			if status == nil {
+				t.Fatalf("status.mysqlUpgrade = nil; want phase %v", test.wantPhase)
+			}
+			if status.Phase != test.wantPhase {
+				t.Errorf("phase = %v; want %v", status.Phase, test.wantPhase)
+			}
+			if got, want := strings.Join(status.UpgradedTablets, ","), strings.Join(aliases(test.wantUpgraded...), ","); got != want {
+				t.Errorf("upgradedTablets = [%v]; want [%v]", got, want)
+			}
+			if !strings.Contains(status.Message, test.wantMessage) {
+				t.Errorf("message = %q; want it to contain %q", status.Message, test.wantMessage)
+			}
+
+			for _, uid := range mysqlUpgradeUIDs {
+				if containsUID(test.missing, uid) {
+					continue
+				}
+				name := fmt.Sprintf("tablet-%d", uid)
+				key := client.ObjectKey{Namespace: vts.Namespace, Name: name}
+				pod := &corev1.Pod{}
+				podErr := c.Get(ctx, key, pod)
+				pvcErr := c.Get(ctx, key, &corev1.PersistentVolumeClaim{})
+				recreated := containsUID(test.wantRecreated, uid)
+				if got := apierrors.IsNotFound(podErr); got != recreated {
+					t.Errorf("Pod %v deleted = %v; want %v", name, got, recreated)
+				}
+				if got := apierrors.IsNotFound(pvcErr); got != recreated {
+					t.Errorf("PVC %v deleted = %v; want %v", name, got, recreated)
+				}
+				if recreated {
+					continue
+				}
+				if got, want := rollout.Released(pod), containsUID(test.wantReleased, uid); got != want {
+					t.Errorf("Pod %v released = %v; want %v", name, got, want)
+				}
+				if got, want := drain.Started(pod), containsUID(test.wantDrained, uid); got != want {
+					t.Errorf("Pod %v drained = %v; want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestReconcileMysqlUpgradeRestart runs an upgrade to completion with a new
+// reconciler on every pass, as if the operator restarted each time, and
+// checks that tablets are upgraded once each, replicas first.
+func TestReconcileMysqlUpgradeRestart(t *testing.T) {
+	ctx := context.Background()
+	vts := mysqlUpgradeShard()
+	var objs []client.Object
+	for _, uid := range mysqlUpgradeUIDs {
+		pod, pvc := mysqlUpgradeTablet(vts, uid, &oldMysqld)
+		objs = append(objs, pod, pvc)
+	}
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+
+	var order []string
+	for pass := 0; pass < 10; pass++ {
+		r := &ReconcileVitessShard{
+			client:   c,
+			recorder: record.NewFakeRecorder(100),
+		}
+		oldStatus := vts.Status.DeepCopy()
+		if _, err := r.reconcileMysqlUpgrade(ctx, vts, oldStatus); err != nil {
+			t.Fatalf("pass %v: reconcileMysqlUpgrade() error: %v", pass, err)
+		}
+		status := vts.Status.MysqlUpgrade
+		if status == nil {
+			if pass == 0 {
+				t.Fatalf("upgrade didn't start")
+			}
+			break
+		}
+		order = status.UpgradedTablets
+
+		// Restart listed tablets with the new image, like reconcileTablets
+		// and the rolling update would.
+		for _, uid := range mysqlUpgradeUIDs {
+			if !upgradeListed(status, testTabletAlias(uid)) {
+				continue
+			}
+			pod, _ := mysqlUpgradeTablet(vts, uid, &newMysqld)
+			if err := c.Update(ctx, pod); err != nil {
+				t.Fatalf("pass %v: can't update Pod: %v", pass, err)
+			}
+		}
+	}
+	if vts.Status.MysqlUpgrade != nil {
+		t.Fatalf("upgrade didn't finish: %+v", vts.Status.MysqlUpgrade)
+	}
+	if got, want := strings.Join(order, ","), strings.Join(aliases(101, 102, 100), ","); got != want {
+		t.Errorf("upgrade order = [%v]; want [%v]", got, want)
+	}
+}
+
+// TestMysqlUpgradeRollbackStatusWrite rolls back a tablet and reconciles
+// again right away, with the shard read back from the API server like the
+// next reconcile would, while status writes are coalesced. The rollback must
+// be written right away, or the tablet would be recreated with the new image
+// and rolled back again.
+func TestMysqlUpgradeRollbackStatusWrite(t *testing.T) {
+	ctx := context.Background()
+	vts := mysqlUpgradeShard()
+	vts.Status.MysqlUpgrade = replicasPhase(101)
+	mysqlupgrade.Abort(vts, "test")
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error: %v", err)
+	}
+	if err := planetscalev2.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error: %v", err)
+	}
+	objs := []client.Object{vts.DeepCopy()}
+	for _, uid := range mysqlUpgradeUIDs {
+		image := &oldMysqld
+		if uid == 101 {
+			image = &newMysqld
+		}
+		pod, pvc := mysqlUpgradeTablet(vts, uid, image)
+		objs = append(objs, pod, pvc)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	r := &ReconcileVitessShard{
+		client:       c,
+		recorder:     record.NewFakeRecorder(100),
+		statusWriter: statusupdate.NewWriter(c, "VitessShard", time.Hour),
+	}
+
+	// reconcile reads the shard, calls update on it, and writes its status.
+	reconcile := func(update func(vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus)) *planetscalev2.VitessShard {
+		t.Helper()
+		vts := &planetscalev2.VitessShard{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "shard"}, vts); err != nil {
+			t.Fatalf("Get() error: %v", err)
+		}
+		oldStatus := vts.Status.DeepCopy()
+		update(vts, oldStatus)
+		if _, err := r.updateStatus(ctx, vts, oldStatus, false); err != nil {
+			t.Fatalf("updateStatus() error: %v", err)
+		}
+		return vts
+	}
+	upgrade := func(vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) {
+		t.Helper()
+		if _, err := r.reconcileMysqlUpgrade(ctx, vts, oldStatus); err != nil {
+			t.Fatalf("reconcileMysqlUpgrade() error: %v", err)
+		}
+	}
+
+	// A routine status change starts the interval.
+	reconcile(func(vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) {
+		vts.Status.Idle = corev1.ConditionTrue
+	})
+
+	// The abort rolls back tablet 101 by deleting its Pod and PVC.
+	reconcile(upgrade)
+	key := client.ObjectKey{Namespace: vts.Namespace, Name: "tablet-101"}
+	if err := c.Get(ctx, key, &corev1.Pod{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("Pod tablet-101 wasn't deleted: %v", err)
+	}
+
+	// The tablet is recreated with the image that the written status says.
+	written := &planetscalev2.VitessShard{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(vts), written); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	tablet := &vttablet.Spec{
+		AliasStr: testTabletAlias(101),
+		Images:   planetscalev2.VitessKeyspaceImages{Mysqld: newMysqld.DeepCopy()},
+	}
+	if upgrade := written.Status.MysqlUpgrade; upgrade != nil {
+		pinMysqldImage(tablet, upgrade)
+	}
+	if got, want := tablet.Images.Mysqld.Image(), oldMysqld.Image(); got != want {
+		t.Fatalf("recreated tablet-101 with mysqld %v; want %v", got, want)
+	}
+	pod, pvc := mysqlUpgradeTablet(vts, 101, tablet.Images.Mysqld)
+	if err := c.Create(ctx, pod); err != nil {
+		t.Fatalf("can't create Pod: %v", err)
+	}
+	if err := c.Create(ctx, pvc); err != nil {
+		t.Fatalf("can't create PVC: %v", err)
+	}
+
+	// The restored tablet isn't rolled back again.
+	reconcile(upgrade)
+	if err := c.Get(ctx, key, &corev1.Pod{}); err != nil {
+		t.Errorf("Pod tablet-101 was deleted again: %v", err)
+	}
+}
+
+func replicasPhase(uids ...uint32) *planetscalev2.VitessShardMysqlUpgradeStatus {
+	return &planetscalev2.VitessShardMysqlUpgradeStatus{
+		FromImage:       oldMysqld,
+		ToImage:         newMysqld,
+		Phase:           planetscalev2.MysqlUpgradeReplicasPhase,
+		UpgradedTablets: aliases(uids...),
+	}
+}
+
+func aliases(uids ...uint32) []string {
+	var keys []string
+	for _, uid := range uids {
+		keys = append(keys, testTabletAlias(uid))
+	}
+	return keys
+}
+
+func containsUID(uids []uint32, uid uint32) bool {
+	for _, u := range uids {
+		if u == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	// register grpc tabletmanager client
+	_ "vitess.io/vitess/go/vt/vttablet/grpctmclient"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+// replicationStatusTimeout is how long to wait for each tablet to report its
+// replication status.
+const replicationStatusTimeout = 5 * time.Second
+
+// tabletReplicationStatus is what a tablet reported about its replication.
+type tabletReplicationStatus struct {
+	name     string
+	position string
+	lag      *int32
+}
+
+// reconcileReplicationStatus records the replication position of each Ready
+// tablet, and how far each replica is behind, in status and metrics.
+func (r *ReconcileVitessShard) reconcileReplicationStatus(ctx context.Context, vts *planetscalev2.VitessShard, tablets map[string]*topo.TabletInfo) {
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	// Start over, so tablets that are gone or didn't answer aren't reported.
+	tabletReplicationLag.DeletePartialMatch(prometheus.Labels{
+		metrics.ClusterLabel:  clusterName,
+		metrics.KeyspaceLabel: keyspaceName,
+		metrics.ShardLabel:    vts.Spec.Name,
+	})
+
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+
+	statuses := make(chan tabletReplicationStatus, len(vts.Status.Tablets))
+	wg := sync.WaitGroup{}
+	for name, status := range vts.Status.Tablets {
+		tablet := tablets[name]
+		if tablet == nil || status.Ready != corev1.ConditionTrue {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, replicationStatusTimeout)
+			defer cancel()
+
+			if name == vts.Status.MasterAlias {
+				primaryStatus, err := tmc.PrimaryStatus(ctx, tablet)
+				if err != nil {
+					return
+				}
+				statuses <- tabletReplicationStatus{name: name, position: primaryStatus.Position}
+				return
+			}
+			replicationStatus, err := tmc.ReplicationStatus(ctx, tablet)
+			if err != nil {
+				// Replication may not be set up yet.
+				return
+			}
+			status := tabletReplicationStatus{name: name, position: replicationStatus.Position}
+			if !replicationStatus.ReplicationLagUnknown {
+				lag := int32(replicationStatus.ReplicationLagSeconds)
+				status.lag = &lag
+			}
+			statuses <- status
+		}(name, tablet.Tablet)
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		tabletStatus := vts.Status.Tablets[status.name]
+		tabletStatus.Position = status.position
+		tabletStatus.ReplicationLagSeconds = status.lag
+		vts.Status.Tablets[status.name] = tabletStatus
+		if status.lag != nil {
+			tabletReplicationLag.WithLabelValues(clusterName, keyspaceName, vts.Spec.Name, status.name).Set(float64(*status.lag))
+		}
+	}
+}
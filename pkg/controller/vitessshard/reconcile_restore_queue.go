@@ -0,0 +1,101 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+)
+
+// restoreQueueRequeueDelay is how often a shard with queued tablets checks
+// whether tablets in other shards have finished starting.
+const restoreQueueRequeueDelay = 30 * time.Second
+
+// queueRestores returns the Pod keys that may be reconciled, leaving out new
+// tablet Pods that must wait because too many tablets in the cluster aren't
+// Ready yet. The number left out is reported in status.
+func (r *ReconcileVitessShard) queueRestores(ctx context.Context, vts *planetscalev2.VitessShard, podKeys []client.ObjectKey) ([]client.ObjectKey, error) {
+	limit := vts.Spec.MaxConcurrentRestores
+	if limit == nil || vts.Status.Backup == nil {
+		// Without a backup, new tablets have nothing to restore.
+		return podKeys, nil
+	}
+
+	var missing []client.ObjectKey
+	for _, key := range podKeys {
+		err := r.client.Get(ctx, key, &corev1.Pod{})
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, key)
+		} else if err != nil {
+			// We don't know whether it exists, so don't create it yet.
+			return nil, err
+		}
+	}
+	if len(missing) == 0 {
+		return podKeys, nil
+	}
+
+	// Count the tablets in the whole cluster that are still starting.
+	pods := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace: vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set{
+			planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+			planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		}),
+	}
+	if err := r.client.List(ctx, pods, listOpts); err != nil {
+		return nil, err
+	}
+	starting := int32(0)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp == nil && !faults.PodReady(pod) {
+			starting++
+		}
+	}
+
+	slots := int(*limit - starting)
+	if slots < 0 {
+		slots = 0
+	}
+	if slots >= len(missing) {
+		return podKeys, nil
+	}
+
+	queued := make(map[client.ObjectKey]bool, len(missing)-slots)
+	for _, key := range missing[slots:] {
+		queued[key] = true
+	}
+	allowed := make([]client.ObjectKey, 0, len(podKeys)-len(queued))
+	for _, key := range podKeys {
+		if !queued[key] {
+			allowed = append(allowed, key)
+		}
+	}
+	vts.Status.QueuedRestores = int32(len(queued))
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "RestoreQueued", "%v tablets are waiting to start, because %v tablets in the cluster are already starting", len(queued), starting)
+	return allowed, nil
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/contenthash"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// maxShardRevisions is how many snapshots are kept in status.revisions.
+	maxShardRevisions = 10
+	// shardRevisionLength is how many hex digits of the snapshot hash are
+	// used to name a revision.
+	shardRevisionLength = 10
+)
+
+/*
+reconcileRollback records a snapshot of the images and flags of the shard's
+tablets whenever they're all updated and ready, keeping a bounded history of
+them in status.revisions. If spec.revertToRevision names one of them, it's
+recorded in status.revertedToRevision instead.
+
+If the rollout coordinator's autoRollback is enabled, it also lists the
+tablets that became unhealthy after being updated since the last snapshot,
+so the VitessCluster controller can decide whether to halt the rollout, and
+records the tablets listed in the revert-tablets annotation in
+status.revertedTablets.
+
+It must run before reconcileTablets, which pins the tablets to the snapshots.
+*/
+func (r *ReconcileVitessShard) reconcileRollback(ctx context.Context, vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) error {
+	snapshot := oldStatus.LastKnownGood.DeepCopy()
+	vts.Status.LastKnownGood = snapshot
+	for i := range oldStatus.Revisions {
+		vts.Status.Revisions = append(vts.Status.Revisions, *oldStatus.Revisions[i].DeepCopy())
+	}
+
+	if revision := vts.Spec.RevertToRevision; revision != "" {
+		// Nothing new is recorded while the tablets run an older revision.
+		if findShardRevision(vts.Status.Revisions, revision) == nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "RevisionNotFound", "Can't revert to unknown revision %v", revision)
+			return nil
+		}
+		vts.Status.RevertedToRevision = revision
+		return nil
+	}
+
+	var autoRollback *planetscalev2.RolloutAutoRollbackSpec
+	if coordinator := vts.Spec.UpdateStrategy.Coordinator; coordinator != nil {
+		autoRollback = coordinator.AutoRollback
+	}
+	if autoRollback != nil && snapshot != nil {
+		vts.Status.RevertedTablets = rollout.RevertTablets(vts)
+	}
+
+	tabletPods, err := r.tabletPodsFromShard(ctx, vts)
+	if err != nil {
+		return err
+	}
+	tabletKeys := oldStatus.TabletAliases()
+
+	current := newShardSnapshot(vts)
+	if snapshot != nil && shardSnapshotEqual(snapshot, current) {
+		// There's nothing new to roll out.
+		return nil
+	}
+
+	// Tablets without pending changes have been updated since the snapshot.
+	if autoRollback != nil && snapshot != nil {
+		now := time.Now()
+		for _, tabletKey := range tabletKeys {
+			pod := tabletPods[tabletKey]
+			if pod == nil || rollout.Scheduled(pod) || rollout.Reverted(pod) {
+				continue
+			}
+			reason := unhealthyTabletReason(pod, autoRollback, now)
+			if reason == "" {
+				continue
+			}
+			vts.Status.UnhealthyTablets = append(vts.Status.UnhealthyTablets, tabletKey)
+			if !containsString(oldStatus.UnhealthyTablets, tabletKey) {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "TabletUnhealthy", "Updated tablet %v is unhealthy: %v", tabletKey, reason)
+			}
+		}
+	}
+
+	// Take a new snapshot once every tablet is updated and ready.
+	if len(vts.Status.RevertedTablets) > 0 || len(tabletKeys) == 0 || oldStatus.LowestPodGeneration != vts.Generation {
+		return nil
+	}
+	for _, tabletKey := range tabletKeys {
+		pod := tabletPods[tabletKey]
+		if pod == nil || rollout.Scheduled(pod) || !faults.PodReady(pod) {
+			return nil
+		}
+	}
+	current.Generation = vts.Generation
+	current.Revision = shardSnapshotRevision(current)
+	vts.Status.LastKnownGood = current
+	vts.Status.Revisions = pushShardRevision(vts.Status.Revisions, current)
+	return nil
+}
+
+// unhealthyTabletReason returns why an updated tablet Pod is considered
+// unhealthy, or "" if it isn't.
+func unhealthyTabletReason(pod *corev1.Pod, autoRollback *planetscalev2.RolloutAutoRollbackSpec, now time.Time) string {
+	for i := range pod.Status.ContainerStatuses {
+		container := &pod.Status.ContainerStatuses[i]
+		if container.RestartCount >= *autoRollback.CrashLoopRestarts {
+			return fmt.Sprintf("container %v restarted %v times", container.Name, container.RestartCount)
+		}
+	}
+	if !faults.PodReady(pod) && now.Sub(pod.CreationTimestamp.Time) > autoRollback.ReadinessTimeout.Duration {
+		return fmt.Sprintf("Pod is not ready after %v", autoRollback.ReadinessTimeout.Duration)
+	}
+	return ""
+}
+
+// newShardSnapshot records the images and flags of a shard's tablets.
+func newShardSnapshot(vts *planetscalev2.VitessShard) *planetscalev2.VitessShardSnapshot {
+	snapshot := &planetscalev2.VitessShardSnapshot{
+		Images:           *vts.Spec.Images.DeepCopy(),
+		ExtraVitessFlags: copyFlags(vts.Spec.ExtraVitessFlags),
+		VttabletFlags:    copyFlags(vts.Spec.ComponentVitessFlags.VttabletFlags()),
+	}
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		snapshot.TabletPools = append(snapshot.TabletPools, planetscalev2.VitessShardTabletPoolSnapshot{
+			Cell:       pool.Cell,
+			Type:       pool.Type,
+			Name:       pool.Name,
+			ExtraFlags: copyFlags(pool.Vttablet.ExtraFlags),
+		})
+	}
+	return snapshot
+}
+
+// shardSnapshotEqual returns whether two snapshots agree on images and
+// flags, regardless of the generation they were taken from.
+func shardSnapshotEqual(a, b *planetscalev2.VitessShardSnapshot) bool {
+	x, y := *a, *b
+	x.Generation, y.Generation = 0, 0
+	x.Revision, y.Revision = "", ""
+	return apiequality.Semantic.DeepEqual(&x, &y)
+}
+
+// shardSnapshotRevision returns a hash of the images and flags in a snapshot.
+func shardSnapshotRevision(snapshot *planetscalev2.VitessShardSnapshot) string {
+	x := *snapshot
+	x.Generation, x.Revision = 0, ""
+	// Maps are marshaled with sorted keys, so the hash is stable.
+	data, err := json.Marshal(&x)
+	if err != nil {
+		// This can't happen for a struct of strings and maps of strings.
+		panic(err)
+	}
+	return contenthash.StringList([]string{string(data)})[:shardRevisionLength]
+}
+
+// pushShardRevision adds a snapshot to the front of a revision history,
+// dropping any older entry for the same revision and the entries beyond
+// maxShardRevisions.
+func pushShardRevision(revisions []planetscalev2.VitessShardSnapshot, snapshot *planetscalev2.VitessShardSnapshot) []planetscalev2.VitessShardSnapshot {
+	history := []planetscalev2.VitessShardSnapshot{*snapshot.DeepCopy()}
+	for i := range revisions {
+		if len(history) >= maxShardRevisions {
+			break
+		}
+		if revisions[i].Revision != snapshot.Revision {
+			history = append(history, revisions[i])
+		}
+	}
+	return history
+}
+
+// findShardRevision returns the snapshot with the given revision, or nil.
+func findShardRevision(revisions []planetscalev2.VitessShardSnapshot, revision string) *planetscalev2.VitessShardSnapshot {
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			return &revisions[i]
+		}
+	}
+	return nil
+}
+
+// applyShardSnapshot replaces the images and flags in a shard spec with
+// those in a snapshot.
+func applyShardSnapshot(spec *planetscalev2.VitessShardSpec, snapshot *planetscalev2.VitessShardSnapshot) {
+	spec.Images = *snapshot.Images.DeepCopy()
+	spec.ExtraVitessFlags = copyFlags(snapshot.ExtraVitessFlags)
+	if spec.ComponentVitessFlags == nil {
+		spec.ComponentVitessFlags = &planetscalev2.ComponentVitessFlags{}
+	}
+	spec.ComponentVitessFlags.Vttablet = copyFlags(snapshot.VttabletFlags)
+	for i := range spec.TabletPools {
+		pool := &spec.TabletPools[i]
+		for _, poolSnapshot := range snapshot.TabletPools {
+			if poolSnapshot.Cell == pool.Cell && poolSnapshot.Type == pool.Type && poolSnapshot.Name == pool.Name {
+				pool.Vttablet.ExtraFlags = copyFlags(poolSnapshot.ExtraFlags)
+			}
+		}
+	}
+}
+
+// revertTabletSpecs replaces the specs of all tablets with specs built from
+// the revision in status.revertedToRevision, if any, or else the specs of the
+// tablets listed in status.revertedTablets with specs built from
+// status.lastKnownGood.
+func revertTabletSpecs(vts *planetscalev2.VitessShard, tablets []*vttablet.Spec, labels map[string]string) {
+	if revision := vts.Status.RevertedToRevision; revision != "" {
+		if snapshot := findShardRevision(vts.Status.Revisions, revision); snapshot != nil {
+			replaceTabletSpecs(vts, tablets, labels, snapshot, func(string) bool { return true })
+		}
+		return
+	}
+	if len(vts.Status.RevertedTablets) == 0 || vts.Status.LastKnownGood == nil {
+		return
+	}
+	replaceTabletSpecs(vts, tablets, labels, vts.Status.LastKnownGood, func(alias string) bool {
+		return containsString(vts.Status.RevertedTablets, alias)
+	})
+}
+
+// replaceTabletSpecs replaces the specs of the selected tablets with specs
+// built from a snapshot.
+func replaceTabletSpecs(vts *planetscalev2.VitessShard, tablets []*vttablet.Spec, labels map[string]string, snapshot *planetscalev2.VitessShardSnapshot, selected func(alias string) bool) {
+	reverted := vts.DeepCopy()
+	applyShardSnapshot(&reverted.Spec, snapshot)
+	revertedTablets := map[string]*vttablet.Spec{}
+	for _, tablet := range vttabletSpecs(reverted, labels) {
+		revertedTablets[tablet.AliasStr] = tablet
+	}
+	for i, tablet := range tablets {
+		if !selected(tablet.AliasStr) {
+			continue
+		}
+		if revertedTablet := revertedTablets[tablet.AliasStr]; revertedTablet != nil {
+			tablets[i] = revertedTablet
+		}
+	}
+}
+
+func copyFlags(flags map[string]string) map[string]string {
+	if len(flags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(flags))
+	for key, value := range flags {
+		out[key] = value
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
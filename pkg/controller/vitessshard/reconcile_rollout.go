@@ -2,10 +2,13 @@ package vitessshard
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
 	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
 	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -20,16 +23,17 @@ import (
 func (r *ReconcileVitessShard) reconcileRollout(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
 	resultBuilder := &results.Builder{}
 
-	if !rollout.Cascading(vts) {
-		// If the shard is not scheduled for a cascading update, silently bail out and do nothing.
-		return resultBuilder.Result()
-	}
-
 	tabletPods, err := r.tabletPodsFromShard(ctx, vts)
 	if err != nil {
 		return resultBuilder.Error(err)
 	}
 
+	if !rollout.Cascading(vts) {
+		// If the shard is not scheduled for a cascading update, do nothing
+		// besides clearing reasons recorded by an earlier rolling restart.
+		return resultBuilder.Error(r.setRolloutBlocked(ctx, vts, tabletPods, "", nil))
+	}
+
 	tabletKeys := vts.Status.TabletAliases()
 
 	for _, tabletKey := range tabletKeys {
@@ -37,25 +41,26 @@ func (r *ReconcileVitessShard) reconcileRollout(ctx context.Context, vts *planet
 		if tablet.Available != corev1.ConditionTrue {
 			// If any tablets are unhealthy, we should bail and not perform a rolling restart.
 			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for tablet %v to be Available.", tabletKey)
-			return resultBuilder.Result()
+			return resultBuilder.Error(r.setRolloutBlocked(ctx, vts, tabletPods, fmt.Sprintf("waiting for tablet %v to be Available", tabletKey), nil))
 		}
 
 		pod, ok := tabletPods[tabletKey]
 		if !ok {
 			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for desired tablet %v to be created.", tabletKey)
-			return resultBuilder.Result()
+			return resultBuilder.Error(r.setRolloutBlocked(ctx, vts, tabletPods, fmt.Sprintf("waiting for desired tablet %v to be created", tabletKey), nil))
 		}
 
 		if rollout.Released(pod) {
 			// If any tablet has already been released, we should wait until it is finished to release another one.
 			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for tablet %v to finish release.", tabletKey)
-			return resultBuilder.Result()
+			return resultBuilder.Error(r.setRolloutBlocked(ctx, vts, tabletPods, fmt.Sprintf("waiting for tablet %v to finish release; tablets are restarted one at a time", tabletKey), nil))
 		}
 	}
 
-	primaryAlias, err := getPrimaryTabletAlias(ctx, vts)
+	primaryAlias, err := getPrimaryTabletAlias(ctx, r.client, vts)
 	if err != nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning, "RolloutBlocked", "Could not get TabletAlias for the Primary.")
+		resultBuilder.Error(r.setRolloutBlocked(ctx, vts, tabletPods, fmt.Sprintf("could not get the primary tablet: %v", err), nil))
 		return resultBuilder.Error(err)
 	}
 
@@ -69,20 +74,34 @@ func (r *ReconcileVitessShard) reconcileRollout(ctx context.Context, vts *planet
 		}
 
 		r.recorder.Eventf(vts, corev1.EventTypeNormal, "RollingRestartComplete", "Cascading rollout of tablets is complete.")
-		return resultBuilder.Result()
+		return resultBuilder.Error(r.setRolloutBlocked(ctx, vts, tabletPods, "", nil))
+	}
+
+	// Restarting a tablet is disruptive, so only do it inside a maintenance window.
+	if !disruptionAllowed(vts) {
+		windows := vts.Spec.UpdateStrategy.MaintenanceWindows
+		reason := fmt.Sprintf("outside maintenance windows: %v", maintenance.Describe(windows, time.Now()))
+		return resultBuilder.Error(r.setRolloutBlocked(ctx, vts, tabletPods, reason, nil))
+	}
+
+	// The rolling restart proceeds, so the other scheduled tablets are only
+	// waiting for their turn. The primary always goes last.
+	queued := func(key string) string {
+		switch key {
+		case tabletKey:
+			return ""
+		case primaryAlias:
+			return "the primary tablet is restarted after all other tablets"
+		default:
+			return fmt.Sprintf("waiting for tablet %v to be restarted first", tabletKey)
+		}
 	}
-
-	masterEligibleTablets := vts.Spec.MasterEligibleTabletCount()
-	deletePod := false
-	tabletType := pod.Labels[planetscalev2.TabletTypeLabel]
-	// These two conditions guarantee that the tablet is a lone master.
-	if masterEligibleTablets < 2 &&
-		(tabletType == string(planetscalev2.ReplicaPoolType) || tabletType == string(planetscalev2.ExternalMasterPoolType)) {
-		// If we have a lone master, we must delete it since reparenting is impossible.
-		deletePod = true
+	if err := r.setRolloutBlocked(ctx, vts, tabletPods, "", queued); err != nil {
+		return resultBuilder.Error(err)
 	}
 
-	if err := r.releaseTabletPod(ctx, pod, deletePod); err != nil {
+	// If we have a lone master, we must delete it since reparenting is impossible.
+	if err := r.releaseTabletPod(ctx, pod, loneMaster(vts, pod)); err != nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning, "RollingRestartBlocked", "release of Pod %v (tablet %v) failed: %v", pod.Name, tabletKey, err)
 		resultBuilder.Error(err)
 	}
@@ -90,6 +109,37 @@ func (r *ReconcileVitessShard) reconcileRollout(ctx context.Context, vts *planet
 	return resultBuilder.Result()
 }
 
+/*
+setRolloutBlocked records why the rolling restart of the shard's tablets
+isn't proceeding in the shard status, and in the blocked annotation of each
+tablet Pod whose scheduled changes haven't been released yet. An empty reason
+clears them.
+
+If queued is not nil, it's used instead of reason to explain why a Pod is
+still waiting, given its tablet alias, while the rolling restart proceeds.
+*/
+func (r *ReconcileVitessShard) setRolloutBlocked(ctx context.Context, vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, reason string, queued func(tabletKey string) string) error {
+	vts.Status.RolloutBlocked = reason
+
+	for tabletKey, pod := range tabletPods {
+		podReason := ""
+		if rollout.Scheduled(pod) && !rollout.Released(pod) {
+			podReason = reason
+			if queued != nil {
+				podReason = queued(tabletKey)
+			}
+		}
+		if rollout.BlockedReason(pod) == podReason {
+			continue
+		}
+		rollout.SetBlockedReason(pod, podReason)
+		if err := r.client.Update(ctx, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *ReconcileVitessShard) tabletPodsFromShard(ctx context.Context, vts *planetscalev2.VitessShard) (map[string]*corev1.Pod, error) {
 	tabletPods := make(map[string]*corev1.Pod)
 
@@ -119,6 +169,16 @@ func (r *ReconcileVitessShard) tabletPodsFromShard(ctx context.Context, vts *pla
 	return tabletPods, nil
 }
 
+// loneMaster returns whether a tablet Pod is the only master-eligible
+// tablet in the shard.
+func loneMaster(vts *planetscalev2.VitessShard, pod *corev1.Pod) bool {
+	masterEligibleTablets := vts.Spec.MasterEligibleTabletCount()
+	tabletType := pod.Labels[planetscalev2.TabletTypeLabel]
+	// These two conditions guarantee that the tablet is a lone master.
+	return masterEligibleTablets < 2 &&
+		(tabletType == string(planetscalev2.ReplicaPoolType) || tabletType == string(planetscalev2.ExternalMasterPoolType))
+}
+
 func (r *ReconcileVitessShard) releaseTabletPod(ctx context.Context, pod *corev1.Pod, deletePod bool) error {
 	if deletePod {
 		// TODO: Evict pods instead of deleting them directly, to respect PDBs.
@@ -168,8 +228,8 @@ func getNextScheduledTablet(tabletKeys []string, tabletPods map[string]*corev1.P
 	return "", nil
 }
 
-func getPrimaryTabletAlias(ctx context.Context, vts *planetscalev2.VitessShard) (string, error) {
-	ts, err := toposerver.Open(ctx, vts.Spec.GlobalLockserver)
+func getPrimaryTabletAlias(ctx context.Context, c client.Client, vts *planetscalev2.VitessShard) (string, error) {
+	ts, err := toposerver.Open(ctx, c, vts.Namespace, vts.Spec.GlobalLockserver)
 	if err != nil {
 		return "", err
 	}
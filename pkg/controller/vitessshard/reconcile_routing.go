@@ -0,0 +1,97 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+// gateVarsMaxAge is how long the healthcheck connections fetched from a
+// vtgate are reused, so we don't ask every vtgate on every reconcile.
+const gateVarsMaxAge = 30 * time.Second
+
+// reconcileRouting checks that the vtgates in each of the shard's serving
+// cells can route queries to its primary, and reports the result in the
+// RoutableFromGates condition.
+func (r *ReconcileVitessShard) reconcileRouting(ctx context.Context, vts *planetscalev2.VitessShard, keyspaceName string, servingCells []string) {
+	var cells []string
+	for _, cell := range servingCells {
+		// We only know the vtgates of cells in this cluster.
+		if vts.Spec.CellInCluster(cell) {
+			cells = append(cells, cell)
+		}
+	}
+	if len(cells) == 0 {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardRoutableFromGates)
+		return
+	}
+	if vts.Status.HasMaster != corev1.ConditionTrue {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardRoutableFromGates, corev1.ConditionFalse, "NoPrimary", "The shard has no primary.")
+		return
+	}
+	sort.Strings(cells)
+
+	key := fmt.Sprintf("%s.%s.primary", keyspaceName, vts.Spec.Name)
+	getVars := func(pod *corev1.Pod) (*vtgate.Vars, error) {
+		return vtgate.GetVars(ctx, r.webClient, pod, gateVarsMaxAge)
+	}
+	var problems []string
+	for _, cell := range cells {
+		pods, err := vtgate.RunningPods(ctx, r.client, vts.Labels[planetscalev2.ClusterLabel], cell)
+		if err == nil {
+			err = cellRouting(pods, key, getVars)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", cell, err))
+		}
+	}
+	if len(problems) > 0 {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardRoutableFromGates, corev1.ConditionFalse, "NotRoutable", fmt.Sprintf("No vtgate routes to the primary in some serving cells: %v.", strings.Join(problems, "; ")))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardRoutableFromGates, corev1.ConditionTrue, "Routable", fmt.Sprintf("vtgates in cells %v route to the primary.", strings.Join(cells, ",")))
+}
+
+// cellRouting returns nil if any of a cell's running vtgate Pods has a
+// serving connection for the given healthcheck key.
+func cellRouting(pods []*corev1.Pod, key string, getVars func(*corev1.Pod) (*vtgate.Vars, error)) error {
+	var lastErr error
+	for _, pod := range pods {
+		vars, err := getVars(pod)
+		if err != nil {
+			lastErr = fmt.Errorf("vtgate Pod %v: %v", pod.Name, err)
+			continue
+		}
+		if vars.HealthcheckConnections[key] > 0 {
+			return nil
+		}
+		lastErr = fmt.Errorf("vtgate Pod %v has no serving connection to the primary", pod.Name)
+	}
+	if lastErr == nil {
+		return fmt.Errorf("no running vtgate Pods")
+	}
+	return lastErr
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+func TestCellRouting(t *testing.T) {
+	const key = "commerce.-80.primary"
+	pods := []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "vtgate-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "vtgate-b"}},
+	}
+	connections := func(n int64) *vtgate.Vars {
+		return &vtgate.Vars{HealthcheckConnections: map[string]int64{key: n}}
+	}
+
+	table := []struct {
+		name    string
+		pods    []*corev1.Pod
+		vars    map[string]*vtgate.Vars
+		wantErr bool
+	}{
+		{
+			name:    "no vtgates",
+			wantErr: true,
+		},
+		{
+			name: "all route",
+			pods: pods,
+			vars: map[string]*vtgate.Vars{"vtgate-a": connections(1), "vtgate-b": connections(1)},
+		},
+		{
+			name: "one routes",
+			pods: pods,
+			vars: map[string]*vtgate.Vars{"vtgate-a": connections(0), "vtgate-b": connections(1)},
+		},
+		{
+			name: "one unreachable, one routes",
+			pods: pods,
+			vars: map[string]*vtgate.Vars{"vtgate-b": connections(1)},
+		},
+		{
+			name:    "none route",
+			pods:    pods,
+			vars:    map[string]*vtgate.Vars{"vtgate-a": connections(0), "vtgate-b": {}},
+			wantErr: true,
+		},
+		{
+			name:    "all unreachable",
+			pods:    pods,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			getVars := func(pod *corev1.Pod) (*vtgate.Vars, error) {
+				if vars, ok := test.vars[pod.Name]; ok {
+					return vars, nil
+				}
+				return nil, fmt.Errorf("connection refused")
+			}
+			err := cellRouting(test.pods, key, getVars)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("cellRouting() error = %v; want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// updateScratchPoolsCondition reports tablet pools whose scratch setting is
+// ignored or can't work. It's a condition rather than an event so it's
+// reported once, rather than on every reconcile.
+func updateScratchPoolsCondition(vts *planetscalev2.VitessShard) {
+	scratch := false
+	var problems []string
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if pool.Scratch == nil {
+			continue
+		}
+		scratch = true
+		switch {
+		case pool.Type != planetscalev2.RdonlyPoolType:
+			problems = append(problems, fmt.Sprintf("tablet pool %v/%v: scratch is only supported for rdonly pools, so it's ignored", pool.Cell, pool.ID()))
+		case vts.Spec.PoolBackupLocation(pool) == nil:
+			problems = append(problems, fmt.Sprintf("tablet pool %v/%v: scratch tablets need a backup location to restore from", pool.Cell, pool.ID()))
+		}
+	}
+
+	switch {
+	case !scratch:
+		delete(vts.Status.Conditions, planetscalev2.VitessShardScratchPoolsValid)
+	case len(problems) > 0:
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardScratchPoolsValid, corev1.ConditionFalse, "InvalidScratchPool", strings.Join(problems, "; ")+".")
+	default:
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardScratchPoolsValid, corev1.ConditionTrue, "Valid", "Every scratch pool is an rdonly pool with a backup location.")
+	}
+}
+
+// reconcileScratch recreates scratch tablets once they reach the pool's
+// restore interval, so they restore a fresh backup when they start again.
+func (r *ReconcileVitessShard) reconcileScratch(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	var pools []*planetscalev2.VitessShardTabletPool
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if pool.IsScratch() && pool.Scratch.RestoreInterval != nil && pool.Scratch.RestoreInterval.Duration > 0 {
+			pools = append(pools, pool)
+		}
+	}
+	if len(pools) == 0 {
+		return resultBuilder.Result()
+	}
+
+	tabletPods, err := r.tabletPodsFromShard(ctx, vts)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+
+	now := time.Now()
+	for _, pool := range pools {
+		interval := pool.Scratch.RestoreInterval.Duration
+
+		var pods []*corev1.Pod
+		for _, pod := range tabletPods {
+			if pod.Labels[planetscalev2.CellLabel] == pool.Cell && vttablet.PoolIDFromPod(pod) == pool.ID() {
+				pods = append(pods, pod)
+			}
+		}
+		// Only take one tablet of the pool down at a time, and never while
+		// another one is still restoring.
+		if int32(len(pods)) != vts.Spec.PoolReplicas(pool) {
+			continue
+		}
+		ready := true
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil || !faults.PodReady(pod) {
+				ready = false
+				break
+			}
+		}
+		if !ready {
+			continue
+		}
+
+		sort.Slice(pods, func(i, j int) bool {
+			return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+		})
+		oldest := pods[0]
+		age := now.Sub(oldest.CreationTimestamp.Time)
+		if age < interval {
+			resultBuilder.RequeueAfter(interval - age)
+			continue
+		}
+
+		if !disruptionAllowed(vts) {
+			// Check again once a window might have opened.
+			resultBuilder.RequeueAfter(time.Minute)
+			continue
+		}
+		if err := r.releaseTabletPod(ctx, oldest, true); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "ScratchRestoreFailed", "failed to delete scratch tablet Pod %v: %v", oldest.Name, err)
+			resultBuilder.Error(err)
+			continue
+		}
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "ScratchRestore", "Deleted scratch tablet Pod %v after %v to restore from the latest backup.", oldest.Name, age.Round(time.Second))
+	}
+
+	return resultBuilder.Result()
+}
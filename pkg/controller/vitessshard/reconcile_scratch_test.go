@@ -0,0 +1,188 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// scratchShard returns a shard with a scratch rdonly pool of the given size
+// that restores every hour.
+func scratchShard(replicas int32) *planetscalev2.VitessShard {
+	vts := testShard(planetscalev2.VitessShardTabletPool{
+		Cell:     "zone1",
+		Type:     planetscalev2.RdonlyPoolType,
+		Replicas: replicas,
+		VitessShardTabletPoolTemplate: planetscalev2.VitessShardTabletPoolTemplate{
+			Scratch: &planetscalev2.VitessTabletPoolScratch{
+				RestoreInterval: &metav1.Duration{Duration: time.Hour},
+			},
+		},
+	})
+	vts.Spec.BackupLocations = []planetscalev2.VitessBackupLocation{{}}
+	vts.Spec.UpdateStrategy = &planetscalev2.VitessClusterUpdateStrategy{}
+	return vts
+}
+
+// scratchTablet returns the Pod of a scratch tablet created age ago.
+func scratchTablet(vts *planetscalev2.VitessShard, uid uint32, age time.Duration, ready bool) *corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	pod := testTabletPod(vts, uid, planetscalev2.RdonlyPoolType)
+	pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}}
+	return pod
+}
+
+func TestReconcileScratch(t *testing.T) {
+	table := []struct {
+		name     string
+		replicas int32
+		// ages are the ages of tablets 101, 102, ...
+		ages     []time.Duration
+		notReady []uint32
+		// wantDeleted is the tablet whose Pod should be deleted, if any.
+		wantDeleted uint32
+		wantRequeue bool
+	}{
+		{
+			name:        "none due",
+			replicas:    2,
+			ages:        []time.Duration{10 * time.Minute, 30 * time.Minute},
+			wantRequeue: true,
+		},
+		{
+			name:        "oldest due",
+			replicas:    2,
+			ages:        []time.Duration{90 * time.Minute, 2 * time.Hour},
+			wantDeleted: 102,
+		},
+		{
+			name:     "another tablet not ready",
+			replicas: 2,
+			ages:     []time.Duration{2 * time.Hour, 10 * time.Minute},
+			notReady: []uint32{102},
+		},
+		{
+			name:     "pool not at full size",
+			replicas: 3,
+			ages:     []time.Duration{2 * time.Hour, 10 * time.Minute},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vts := scratchShard(test.replicas)
+			notReady := map[uint32]bool{}
+			for _, uid := range test.notReady {
+				notReady[uid] = true
+			}
+			var objs []client.Object
+			for i, age := range test.ages {
+				uid := uint32(101 + i)
+				objs = append(objs, scratchTablet(vts, uid, age, !notReady[uid]))
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := &ReconcileVitessShard{client: c, apiReader: c, recorder: record.NewFakeRecorder(10)}
+
+			result, err := r.reconcileScratch(context.Background(), vts)
+			if err != nil {
+				t.Fatalf("reconcileScratch() error: %v", err)
+			}
+			if got := result.RequeueAfter > 0; got != test.wantRequeue {
+				t.Errorf("reconcileScratch() requeue = %v; want %v", got, test.wantRequeue)
+			}
+			for i := range test.ages {
+				uid := uint32(101 + i)
+				err := c.Get(context.Background(), client.ObjectKey{Namespace: "ns", Name: fmt.Sprintf("tablet-%d", uid)}, &corev1.Pod{})
+				if deleted := apierrors.IsNotFound(err); deleted != (uid == test.wantDeleted) {
+					t.Errorf("tablet %v deleted = %v; want %v", uid, deleted, uid == test.wantDeleted)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateScratchPoolsCondition(t *testing.T) {
+	table := []struct {
+		name       string
+		mutate     func(vts *planetscalev2.VitessShard)
+		wantStatus corev1.ConditionStatus
+	}{
+		{
+			name:       "valid",
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "not rdonly",
+			mutate: func(vts *planetscalev2.VitessShard) {
+				vts.Spec.TabletPools[0].Type = planetscalev2.ReplicaPoolType
+			},
+			wantStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "no backup location",
+			mutate: func(vts *planetscalev2.VitessShard) {
+				vts.Spec.BackupLocations = nil
+			},
+			wantStatus: corev1.ConditionFalse,
+		},
+		{
+			name: "no scratch pools",
+			mutate: func(vts *planetscalev2.VitessShard) {
+				vts.Spec.TabletPools[0].Scratch = nil
+			},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vts := scratchShard(1)
+			// A condition left from before is updated or removed.
+			vts.Status.SetConditionStatus(planetscalev2.VitessShardScratchPoolsValid, corev1.ConditionFalse, "InvalidScratchPool", "old")
+			if test.mutate != nil {
+				test.mutate(vts)
+			}
+			updateScratchPoolsCondition(vts)
+
+			cond, ok := vts.Status.Conditions[planetscalev2.VitessShardScratchPoolsValid]
+			if test.wantStatus == "" {
+				if ok {
+					t.Errorf("got condition %v; want none", cond)
+				}
+				return
+			}
+			if !ok || cond.Status != test.wantStatus {
+				t.Errorf("got condition %v; want status %v", cond, test.wantStatus)
+			}
+		})
+	}
+}
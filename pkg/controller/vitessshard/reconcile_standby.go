@@ -0,0 +1,158 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileStandby reports how far a standby shard is behind the source
+// cluster, and recreates its tablets one at a time, empty, so they restore
+// the source cluster's latest backup.
+func (r *ReconcileVitessShard) reconcileStandby(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	if !vts.Spec.IsStandby() || vts.Spec.UsingExternalDatastore() || !vts.Spec.AllPoolsUsingMysqld() {
+		return resultBuilder.Result()
+	}
+
+	// The VitessBackupStorage objects of a standby list the source cluster's
+	// backups, but under our own cluster label.
+	allBackups := &planetscalev2.VitessBackupList{}
+	listOpts := &client.ListOptions{
+		Namespace: vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set{
+			planetscalev2.ClusterLabel:  vts.Labels[planetscalev2.ClusterLabel],
+			planetscalev2.KeyspaceLabel: vts.Labels[planetscalev2.KeyspaceLabel],
+			planetscalev2.ShardLabel:    vts.Spec.KeyRange.SafeName(),
+		}),
+	}
+	if err := r.client.List(ctx, allBackups, listOpts); err != nil {
+		return resultBuilder.Error(err)
+	}
+	backups := vitessbackup.CompleteBackups(allBackups.Items)
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Status.StartTime.Before(&backups[j].Status.StartTime)
+	})
+
+	tabletPods, err := r.tabletPodsFromShard(ctx, vts)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+	pods := make([]*corev1.Pod, 0, len(tabletPods))
+	for _, pod := range tabletPods {
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+
+	status := standbyStatus(pods, backups)
+	vts.Status.Standby = status
+
+	if reconciler.IsPaused(ctx) || len(pods) == 0 || len(backups) == 0 {
+		return resultBuilder.Result()
+	}
+
+	// Only take one tablet down at a time, and never while another one is
+	// still restoring.
+	var desiredTablets int32
+	for i := range vts.Spec.TabletPools {
+		desiredTablets += vts.Spec.PoolReplicas(&vts.Spec.TabletPools[i])
+	}
+	if status.RestoringTablet != "" || int32(len(pods)) != desiredTablets {
+		return resultBuilder.RequeueAfter(time.Minute)
+	}
+
+	// Recreate the tablet that was created first, once it's old enough and
+	// there's a backup that it can't have restored.
+	oldest := pods[0]
+	age := time.Since(oldest.CreationTimestamp.Time)
+	interval := vts.Spec.Standby.RestoreInterval.Duration
+	if age < interval {
+		return resultBuilder.RequeueAfter(interval - age)
+	}
+	latest := backups[len(backups)-1]
+	if !latest.Status.StartTime.After(oldest.CreationTimestamp.Time) {
+		return resultBuilder.RequeueAfter(interval)
+	}
+
+	if !disruptionAllowed(vts) {
+		// Check again once a window might have opened.
+		return resultBuilder.RequeueAfter(time.Minute)
+	}
+	if err := r.recreateTabletFromBackup(ctx, oldest); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "StandbyRestoreFailed", "failed to recreate tablet Pod %v: %v", oldest.Name, err)
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "StandbyRestore", "Recreated tablet Pod %v to restore the backup of cluster %v from %v.", oldest.Name, vts.Spec.Standby.SourceClusterName, latest.Status.StartTime.UTC().Format(time.RFC3339))
+	return resultBuilder.Result()
+}
+
+// standbyStatus summarizes the staleness of a standby shard, given its
+// tablet Pods and the source cluster's complete backups, both sorted from
+// oldest to newest.
+func standbyStatus(pods []*corev1.Pod, backups []*planetscalev2.VitessBackup) *planetscalev2.VitessShardStandbyStatus {
+	status := &planetscalev2.VitessShardStandbyStatus{}
+	if len(backups) > 0 {
+		status.LatestBackupTime = backups[len(backups)-1].Status.StartTime.DeepCopy()
+	}
+	if len(pods) == 0 {
+		return status
+	}
+	status.LastRestoreTime = pods[len(pods)-1].CreationTimestamp.DeepCopy()
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !faults.PodReady(pod) {
+			alias := vttablet.AliasFromPod(pod)
+			status.RestoringTablet = topoproto.TabletAliasString(&alias)
+			break
+		}
+	}
+
+	// The stalest tablet is the one that was created first. It restored the
+	// newest backup that had started by then.
+	created := pods[0].CreationTimestamp
+	var restored *metav1.Time
+	for _, backup := range backups {
+		if backup.Status.StartTime.After(created.Time) {
+			status.BackupsBehind++
+			continue
+		}
+		restored = &backup.Status.StartTime
+	}
+	if restored != nil {
+		status.RestoredBackupTime = restored.DeepCopy()
+	}
+	return status
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlusers"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// rendersInitDB returns whether the operator renders the init_db.sql script
+// of a shard, rather than mounting the user's Secret as it is.
+func rendersInitDB(vts *planetscalev2.VitessShard) bool {
+	userScript := &vts.Spec.DatabaseInitScriptSecret
+	return len(vts.Spec.DatabaseInitScriptFragments) > 0 || (userScript.Name == "" && userScript.VolumeName == "") || vts.Spec.ManagesInternalCredentials()
+}
+
+// initDBScriptSecret returns where tablets of a shard get their init_db.sql
+// script from.
+func initDBScriptSecret(vts *planetscalev2.VitessShard) planetscalev2.SecretSource {
+	if !rendersInitDB(vts) {
+		return vts.Spec.DatabaseInitScriptSecret
+	}
+	return planetscalev2.SecretSource{
+		Name: vttablet.InitDBSecretName(vts.Name),
+		Key:  vttablet.InitDBSecretKey,
+	}
+}
+
+// credentialsSecret returns the name of the Secret with the credentials file
+// that tablets of a shard should mount, and the hash of the passwords in it,
+// or empty strings if they shouldn't mount one yet.
+func credentialsSecret(vts *planetscalev2.VitessShard) (name, hash string) {
+	// Tablets only switch to passwords once they're set in MySQL.
+	if !vts.Spec.ManagesInternalCredentials() || vts.Status.InternalCredentials == nil {
+		return "", ""
+	}
+	return vttablet.CredentialsSecretName(vts.Name), vts.Status.InternalCredentials.Hash
+}
+
+// reconcileTabletSecrets renders the Secrets that the operator provides to
+// the tablets of a shard: the init_db.sql script, unless the user provides
+// all of it, the credentials file of the internal MySQL accounts, and the
+// password of the account that mysqld-exporter uses.
+func (r *ReconcileVitessShard) reconcileTabletSecrets(ctx context.Context, vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) (reconcile.Result, error) {
+	resultBuilder := results.Builder{}
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+	initDBKey := client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.InitDBSecretName(vts.Name)}
+	credentialsKey := client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.CredentialsSecretName(vts.Name)}
+	exporterKey := client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.MysqldExporterSecretName(vts.Name)}
+
+	var passwords map[string]string
+	passwordsHash := ""
+	if vts.Spec.ManagesInternalCredentials() {
+		var err error
+		passwords, err = r.internalPasswords(ctx, vts)
+		if err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "CredentialsReadFailed", "failed to read passwords of internal MySQL accounts: %v", err)
+			return resultBuilder.Error(err)
+		}
+		passwordsHash = mysqlusers.InternalPasswordsHash(passwords)
+		// A new shard gets the passwords from init_db.sql, so its tablets can
+		// use them from the start.
+		if vts.Status.InternalCredentials == nil && len(oldStatus.Tablets) == 0 {
+			vts.Status.InternalCredentials = &planetscalev2.VitessShardInternalCredentialsStatus{
+				Hash:     passwordsHash,
+				Accounts: mysqlusers.InternalAccounts(passwords),
+			}
+		}
+	}
+
+	var keys []client.ObjectKey
+	script := ""
+	if rendersInitDB(vts) {
+		var err error
+		script, err = r.renderInitDB(ctx, vts, passwords)
+		if err != nil {
+			// Keep the script we rendered before, if any.
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "InitDBRenderFailed", "failed to render init_db.sql: %v", err)
+			return resultBuilder.Error(err)
+		}
+		keys = append(keys, initDBKey)
+	}
+	if passwords != nil {
+		keys = append(keys, credentialsKey)
+	}
+	// The exporter's password is generated once, and the VitessShard
+	// replication controller sets it in MySQL.
+	var exporterPassword string
+	if vts.Spec.RunsMysqldExporter() {
+		var err error
+		exporterPassword, err = mysqlusers.GeneratePassword()
+		if err != nil {
+			return resultBuilder.Error(err)
+		}
+		keys = append(keys, exporterKey)
+	}
+
+	err := r.reconciler.ReconcileObjectSet(ctx, vts, keys, labels, reconciler.Strategy{
+		Kind: &corev1.Secret{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			switch key {
+			case credentialsKey:
+				return vttablet.NewCredentialsSecret(key, labels, mysqlusers.CredentialsFile(passwords))
+			case exporterKey:
+				return vttablet.NewMysqldExporterSecret(key, labels, exporterPassword)
+			}
+			return vttablet.NewInitDBSecret(key, labels, script)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			if key == exporterKey {
+				// Keep the password that was generated first.
+				return
+			}
+			if key == credentialsKey {
+				// Tablets that restart in the middle of a rotation should
+				// keep using the passwords that are set in MySQL.
+				if status := vts.Status.InternalCredentials; status != nil && status.Hash == passwordsHash {
+					vttablet.UpdateCredentialsSecretInPlace(obj.(*corev1.Secret), mysqlusers.CredentialsFile(passwords))
+				}
+				return
+			}
+			vttablet.UpdateInitDBSecretInPlace(obj.(*corev1.Secret), script)
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// renderInitDB returns the init_db.sql script for a shard, made of either the
+// user's script or the baseline one, followed by the user's fragments, and
+// finally the passwords of the internal accounts, if any.
+func (r *ReconcileVitessShard) renderInitDB(ctx context.Context, vts *planetscalev2.VitessShard, passwords map[string]string) (string, error) {
+	base := vttablet.BaselineInitDB()
+	if userScript := &vts.Spec.DatabaseInitScriptSecret; userScript.Name != "" || userScript.VolumeName != "" {
+		var err error
+		base, err = r.readSecretSource(ctx, vts.Namespace, userScript)
+		if err != nil {
+			return "", err
+		}
+	}
+	fragments := make([]vttablet.InitDBFragment, 0, len(vts.Spec.DatabaseInitScriptFragments)+1)
+	for i := range vts.Spec.DatabaseInitScriptFragments {
+		source := &vts.Spec.DatabaseInitScriptFragments[i]
+		sql, err := r.readSecretSource(ctx, vts.Namespace, source)
+		if err != nil {
+			return "", err
+		}
+		fragments = append(fragments, vttablet.InitDBFragment{
+			Source: fmt.Sprintf("Secret %v/%v", source.Name, source.Key),
+			SQL:    sql,
+		})
+	}
+	if len(passwords) > 0 {
+		sql := ""
+		for _, statement := range mysqlusers.SetInternalPasswords(passwords, nil) {
+			sql += statement.SQL + ";\n"
+		}
+		fragments = append(fragments, vttablet.InitDBFragment{
+			Source: "the passwords of the internal accounts",
+			SQL:    sql,
+		})
+	}
+	return vttablet.RenderInitDB(base, fragments), nil
+}
+
+// internalPasswords returns the passwords of the internal MySQL accounts of
+// a shard.
+func (r *ReconcileVitessShard) internalPasswords(ctx context.Context, vts *planetscalev2.VitessShard) (map[string]string, error) {
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: vts.Namespace, Name: vts.Spec.InternalCredentials.SecretName}, secret); err != nil {
+		return nil, err
+	}
+	return mysqlusers.InternalPasswords(secret.Data), nil
+}
+
+// readSecretSource returns the value of a key in a Secret.
+func (r *ReconcileVitessShard) readSecretSource(ctx context.Context, namespace string, source *planetscalev2.SecretSource) (string, error) {
+	if source.Name == "" || source.VolumeName != "" {
+		return "", fmt.Errorf("init_db.sql scripts and fragments can only be read from Secrets specified by name")
+	}
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.Name}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[source.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in Secret %v", source.Key, source.Name)
+	}
+	return string(value), nil
+}
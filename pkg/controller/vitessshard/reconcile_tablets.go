@@ -27,6 +27,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubectl/pkg/util/podutils"
@@ -34,13 +35,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/backupgate"
 	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
 )
 
@@ -57,7 +61,7 @@ const (
 	observedShardGenerationAnnotationKey = "planetscale.com/observed-shard-generation"
 )
 
-func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) (reconcile.Result, error) {
 	resultBuilder := &results.Builder{}
 	clusterName := vts.Labels[planetscalev2.ClusterLabel]
 
@@ -68,6 +72,8 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
 	}
 
+	r.validateTabletPools(vts)
+
 	// Remember which cells we deploy any tablets in.
 	deployedCells := map[string]struct{}{}
 	defer func() {
@@ -82,14 +88,33 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 	// Compute the set of all desired tablets based on the config.
 	tablets := vttabletSpecs(vts, labels)
 
+	// Tablets reverted to an older revision, or after a halted rollout, keep
+	// the images and flags they ran successfully then.
+	revertTabletSpecs(vts, tablets, labels)
+
+	// During a MySQL major version upgrade, each tablet keeps its mysqld
+	// image until reconcileMysqlUpgrade decides to upgrade it.
+	if upgrade := vts.Status.MysqlUpgrade; upgrade != nil {
+		for _, tablet := range tablets {
+			pinMysqldImage(tablet, upgrade)
+		}
+	}
+
+	// Tablets of pools in "Auto" vertical autoscaling mode get the requests
+	// that reconcileVerticalAutoscaling decided on.
+	for _, tablet := range tablets {
+		setAutoscaledRequests(vts, tablet)
+	}
+
 	// Generate podKeys (object names) for all desired tablet pods and pvcKeys for desired PVCs.
 	//
 	// Keep a map back from generated names to the tablet specs.
 	pvcKeys := make([]client.ObjectKey, 0, len(tablets))
 	podKeys := make([]client.ObjectKey, 0, len(tablets))
 	tabletMap := make(map[client.ObjectKey]*vttablet.Spec, len(tablets))
+	extraPVCMap := make(map[client.ObjectKey]*vttablet.ExtraVolumeClaim)
 	for _, tablet := range tablets {
-		podName := vttablet.PodName(clusterName, tablet.Alias)
+		podName := vttablet.PodName(clusterName, &tablet.Alias)
 		key := client.ObjectKey{Namespace: vts.Namespace, Name: podName}
 
 		if tablet.DataVolumePVCSpec != nil {
@@ -98,24 +123,43 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 
 			pvcKeys = append(pvcKeys, key)
 		}
+		for i := range tablet.ExtraVolumeClaims {
+			claim := &tablet.ExtraVolumeClaims[i]
+			claim.ClaimName = vttablet.ExtraPVCName(podName, claim.VolumeName)
+			claimKey := client.ObjectKey{Namespace: vts.Namespace, Name: claim.ClaimName}
+			pvcKeys = append(pvcKeys, claimKey)
+			tabletMap[claimKey] = tablet
+			extraPVCMap[claimKey] = claim
+		}
 
 		podKeys = append(podKeys, key)
 
 		tabletMap[key] = tablet
 
+		// The primary is only known once we've checked the topology, so
+		// go by the last time we did.
+		tablet.IsPrimary = tablet.AliasStr != "" && tablet.AliasStr == oldStatus.MasterAlias
+
 		deployedCells[tablet.Alias.Cell] = struct{}{}
 
 		// Initialize a status entry for every desired tablet, so it will be
 		// listed even if we end up not having anything to report about it.
-		vts.Status.Tablets[tablet.AliasStr] = planetscalev2.NewVitessTabletStatus(tablet.Type, tablet.Index)
+		tabletStatus := planetscalev2.NewVitessTabletStatus(tablet.Type, tablet.Index)
+		tabletStatus.Pool = tablet.PoolName
+		tabletStatus.Hostname = tablet.Hostname(vts.Namespace)
+		vts.Status.Tablets[tablet.AliasStr] = tabletStatus
 	}
 
-	// Reconcile vttablet PVCs. Note that we use the same keys as the corresponding Pods.
+	// Reconcile vttablet PVCs. Note that we use the same keys as the corresponding Pods
+	// for data volumes, which the extra PVCs are named after.
 	err := r.reconciler.ReconcileObjectSet(ctx, vts, pvcKeys, labels, reconciler.Strategy{
 		Kind: &corev1.PersistentVolumeClaim{},
 
 		New: func(key client.ObjectKey) runtime.Object {
 			tablet := tabletMap[key]
+			if claim := extraPVCMap[key]; claim != nil {
+				return vttablet.NewExtraPVC(key, tablet, claim)
+			}
 
 			// The PVC doesn't exist, so it can't be bound.
 			status := vts.Status.Tablets[tablet.AliasStr]
@@ -126,9 +170,24 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			curObj := obj.(*corev1.PersistentVolumeClaim)
-			vttablet.UpdatePVCInPlace(curObj, tabletMap[key])
+			curSize := curObj.Spec.Resources.Requests[corev1.ResourceStorage]
+			if claim := extraPVCMap[key]; claim != nil {
+				vttablet.UpdateExtraPVCInPlace(curObj, tabletMap[key], claim)
+			} else {
+				vttablet.UpdatePVCInPlace(curObj, tabletMap[key])
+			}
+
+			// Volume expansion is disruptive, so hold it back until a
+			// maintenance window opens.
+			newSize := curObj.Spec.Resources.Requests[corev1.ResourceStorage]
+			if !newSize.Equal(curSize) && !disruptionAllowed(vts) {
+				curObj.Spec.Resources.Requests[corev1.ResourceStorage] = curSize
+			}
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
+			if extraPVCMap[key] != nil {
+				return
+			}
 			tablet := tabletMap[key]
 			curObj := obj.(*corev1.PersistentVolumeClaim)
 
@@ -141,8 +200,15 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 			// corresponding Pod still exists. That way if we decide to keep a
 			// Pod around (see the other PrepareForTurndown below), we won't try
 			// to delete the PVC out from under it.
+			//
+			// Extra PVCs are named after their Pod, which we find by their labels.
+			podKey := key
+			if pvc := obj.(*corev1.PersistentVolumeClaim); pvc.Labels[planetscalev2.TabletUidLabel] != "" {
+				alias := vttablet.AliasFromLabels(pvc.Labels)
+				podKey.Name = vttablet.PodName(clusterName, &alias)
+			}
 			pod := &corev1.Pod{}
-			if getErr := r.client.Get(ctx, key, pod); getErr == nil || !apierrors.IsNotFound(getErr) {
+			if getErr := r.client.Get(ctx, podKey, pod); getErr == nil || !apierrors.IsNotFound(getErr) {
 				// If the get was successful, the Pod exists and we shouldn't delete the PVC.
 				// If the get failed for any reason other than NotFound, we don't know if it's safe.
 				return planetscalev2.NewOrphanStatus("PodExists", "not deleting tablet PVC because tablet Pod still exists")
@@ -154,6 +220,16 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 		resultBuilder.Error(err)
 	}
 
+	// Hold back new tablet Pods while too many tablets in the cluster are
+	// starting, since each may restore a backup.
+	podKeys, err = r.queueRestores(ctx, vts, podKeys)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+	if vts.Status.QueuedRestores > 0 {
+		resultBuilder.RequeueAfter(restoreQueueRequeueDelay)
+	}
+
 	// Reconcile vttablet Pods.
 	err = r.reconciler.ReconcileObjectSet(ctx, vts, podKeys, labels, reconciler.Strategy{
 		Kind: &corev1.Pod{},
@@ -168,7 +244,14 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 			tabletStatus.Available = corev1.ConditionFalse
 			vts.Status.Tablets[tablet.AliasStr] = tabletStatus
 
-			return vttablet.NewPod(key, tablet)
+			pod := vttablet.NewPod(key, tablet)
+			if containsString(vts.Status.RevertedTablets, tablet.AliasStr) {
+				if pod.Annotations == nil {
+					pod.Annotations = make(map[string]string)
+				}
+				pod.Annotations[rollout.RevertedAnnotation] = ""
+			}
+			return pod
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*corev1.Pod)
@@ -178,12 +261,31 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 				newObj.Annotations = make(map[string]string)
 			}
 			newObj.Annotations[observedShardGenerationAnnotationKey] = strconv.FormatInt(vts.Generation, 10)
+
+			// Recreate reverted tablets right away, since they're unhealthy.
+			if containsString(vts.Status.RevertedTablets, tablet.AliasStr) {
+				if !rollout.Reverted(newObj) {
+					rollout.Release(newObj)
+				}
+			} else {
+				delete(newObj.Annotations, rollout.RevertedAnnotation)
+			}
+		},
+		UpdateRollingInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			if pod := obj.(*corev1.Pod); reconciler.CanResizePod(pod) {
+				vttablet.ResizePodInPlace(pod, tabletMap[key])
+			}
 		},
 		UpdateRollingRecreate: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*corev1.Pod)
 			tablet := tabletMap[key]
+			prevObj := newObj.DeepCopy()
 			r.updatePVCFilesystemResizeAnnotation(ctx, tablet, newObj)
 			vttablet.UpdatePod(newObj, tablet)
+			if reconciler.CanResizePod(prevObj) {
+				// Don't recreate the Pod just for what couldn't be resized.
+				vttablet.KeepPodResources(newObj, prevObj)
+			}
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			pod := obj.(*corev1.Pod)
@@ -191,7 +293,7 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 
 			tabletStatus := vts.Status.Tablets[tablet.AliasStr]
 			tabletStatus.Running = k8s.ConditionStatus(pod.Status.Phase == corev1.PodRunning)
-			if podutils.IsPodReady(pod) {
+			if faults.PodReady(pod) {
 				tabletStatus.Ready = corev1.ConditionTrue
 				tabletStatus.Available = tabletAvailableStatus(resultBuilder, pod)
 			}
@@ -229,6 +331,14 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 			curObj := obj.(*corev1.Pod)
 			tabletAlias := vttablet.AliasFromPod(curObj)
 
+			// Deleting a whole tablet pool waits for a recent backup, if the
+			// policy asks for one. Scaling a pool down doesn't.
+			if vts.Spec.TabletPool(tabletAlias.Cell, vttablet.PoolIDFromPod(curObj)) == nil {
+				if err := backupgate.Check(vts, time.Now()); err != nil {
+					return planetscalev2.NewOrphanStatus("BackupRequired", err.Error())
+				}
+			}
+
 			// Drain before turn-down.
 			if !drain.Finished(curObj) {
 				drain.Start(curObj, "turning down unwanted tablet")
@@ -236,7 +346,7 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 			}
 
 			// Make sure the tablet is not the primary.
-			isPrimary, err := isTabletPrimary(ctx, vts, tabletAlias)
+			isPrimary, err := isTabletPrimary(ctx, r.client, vts, tabletAlias)
 			if err != nil {
 				return planetscalev2.NewOrphanStatus("PrimaryUnknown", "unable to determine whether this tablet is the primary")
 			}
@@ -265,39 +375,82 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 	return resultBuilder.Result()
 }
 
+// validateTabletPools reports tablet pools that are ignored because they
+// duplicate another pool, and vttablet settings that are left out of the
+// flags because they're invalid.
+func (r *ReconcileVitessShard) validateTabletPools(vts *planetscalev2.VitessShard) {
+	seenPools := map[string]bool{}
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		poolKey := pool.Cell + "/" + pool.ID()
+		if seenPools[poolKey] {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "DuplicateTabletPool", "tablet pool %v/%v: another pool in the same cell has the same name; ignoring it", pool.Cell, pool.ID())
+			continue
+		}
+		seenPools[poolKey] = true
+		for _, validate := range []func(*planetscalev2.VttabletSpec) error{vttablet.ValidateQueryServer, vttablet.ValidateThrottlers} {
+			if err := validate(&pool.Vttablet); err != nil {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidVttabletConfig", "tablet pool %v/%v: %v", pool.Cell, pool.ID(), err)
+			}
+		}
+	}
+	compression := vts.Spec.BackupCompression
+	if x := vts.Spec.BackupXtrabackup; x != nil && x.Compression != nil && vts.Spec.BackupEngine == planetscalev2.VitessBackupEngineXtraBackup {
+		compression = x.Compression
+	}
+	if compression != nil {
+		if err := vttablet.ValidateBackupCompression(compression, vts.Spec.Images.Vttablet); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidBackupCompression", "ignoring backup compression settings: %v", err)
+		}
+	}
+}
+
 // vttabletSpecs creates a list of vttablet Specs for a VitessShard.
 func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]string) []*vttablet.Spec {
 	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	credentialsSecretName, credentialsHash := credentialsSecret(vts)
 
 	var tablets []*vttablet.Spec
+	seenPools := map[string]bool{}
 
 	for poolIndex := range vts.Spec.TabletPools {
 		pool := &vts.Spec.TabletPools[poolIndex]
 
+		// Pools with the same ID would deploy the same tablets, so only the
+		// first one counts. validateTabletPools reports the rest.
+		poolKey := pool.Cell + "/" + pool.ID()
+		if seenPools[poolKey] {
+			continue
+		}
+		seenPools[poolKey] = true
+
 		// Find the backup location for this pool.
-		backupLocation := vts.Spec.BackupLocation(pool.BackupLocationName)
+		backupLocation := vts.Spec.PoolBackupLocation(pool)
 
 		// Within each pool, tablets are assigned a 1-based index.
-		for tabletIndex := int32(1); tabletIndex <= pool.Replicas; tabletIndex++ {
+		replicas := vts.Spec.PoolReplicas(pool)
+		for tabletIndex := int32(1); tabletIndex <= replicas; tabletIndex++ {
 			tabletAlias := topodatapb.TabletAlias{
 				Cell: pool.Cell,
-				Uid:  vttablet.UID(pool.Cell, keyspaceName, vts.Spec.KeyRange, pool.Type, uint32(tabletIndex)),
+				Uid:  vttablet.UID(pool.Cell, keyspaceName, vts.Spec.KeyRange, pool.ID(), uint32(tabletIndex)),
 			}
 
 			// Copy parent labels map and add tablet-specific labels.
-			labels := make(map[string]string, len(parentLabels)+4)
+			labels := make(map[string]string, len(parentLabels)+5)
 			for k, v := range parentLabels {
 				labels[k] = v
 			}
 			labels[planetscalev2.CellLabel] = tabletAlias.Cell
 			labels[planetscalev2.TabletUidLabel] = strconv.FormatUint(uint64(tabletAlias.Uid), 10)
 			labels[planetscalev2.TabletTypeLabel] = string(pool.Type)
+			if pool.Name != "" {
+				labels[planetscalev2.TabletPoolLabel] = pool.Name
+			}
 			labels[planetscalev2.TabletIndexLabel] = strconv.FormatUint(uint64(tabletIndex), 10)
 
 			// Merge ExtraVitessFlags into the tablet spec ExtraFlags field.
-			extraFlags := make(map[string]string)
-			update.StringMap(&extraFlags, vts.Spec.ExtraVitessFlags)
-			update.StringMap(&extraFlags, pool.Vttablet.ExtraFlags)
+			// updateManagedFlagsCondition reports any flags that are left out.
+			extraFlags, _ := vitess.MergeExtraFlags(vts.Spec.ComponentVitessFlags.ManagedFlagOverrides(), vts.Spec.ExtraVitessFlags, vts.Spec.ComponentVitessFlags.VttabletFlags(), pool.Vttablet.ExtraFlags)
 
 			// Make shallow copy of pool.Vttablet to avoid mutating input.
 			vttabletcpy := pool.Vttablet
@@ -310,6 +463,24 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 			if backupLocation != nil {
 				update.Annotations(&annotations, backupLocation.Annotations)
 			}
+
+			// The names of extra PVCs are filled in along with the data PVC.
+			extraVolumeClaims := make([]vttablet.ExtraVolumeClaim, 0, len(pool.ExtraVolumeClaimTemplates))
+			for i := range pool.ExtraVolumeClaimTemplates {
+				template := &pool.ExtraVolumeClaimTemplates[i]
+				extraVolumeClaims = append(extraVolumeClaims, vttablet.ExtraVolumeClaim{
+					VolumeName: template.Name,
+					Spec:       &template.Spec,
+				})
+			}
+
+			// Scratch tablets keep their data in the Pod's ephemeral storage.
+			dataVolumePVCSpec := pool.DataVolumeClaimTemplate
+			var scratchSizeLimit *resource.Quantity
+			if pool.IsScratch() {
+				dataVolumePVCSpec = nil
+				scratchSizeLimit = pool.Scratch.SizeLimit
+			}
 			tablets = append(tablets, &vttablet.Spec{
 				GlobalLockserver:          vts.Spec.GlobalLockserver,
 				Labels:                    labels,
@@ -324,14 +495,24 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 				Vttablet:                  &vttabletcpy,
 				Mysqld:                    pool.Mysqld,
 				ExternalDatastore:         pool.ExternalDatastore,
+				MysqldExporter:            pool.MysqldExporter,
+				MysqldExporterSecret:      vttablet.MysqldExporterSecretName(vts.Name),
 				Type:                      pool.Type,
-				DataVolumePVCSpec:         pool.DataVolumeClaimTemplate,
+				PoolName:                  pool.Name,
+				DataVolumePVCSpec:         dataVolumePVCSpec,
+				ExtraVolumeClaims:         extraVolumeClaims,
 				KeyspaceName:              keyspaceName,
 				DatabaseName:              vts.Spec.DatabaseName,
-				DatabaseInitScriptSecret:  vts.Spec.DatabaseInitScriptSecret,
+				DurabilityPolicy:          vts.Spec.DurabilityPolicy,
+				DatabaseInitScriptSecret:  initDBScriptSecret(vts),
+				CredentialsSecret:         credentialsSecretName,
+				CredentialsHash:           credentialsHash,
 				Annotations:               annotations,
 				BackupLocation:            backupLocation,
 				BackupEngine:              vts.Spec.BackupEngine,
+				Xtrabackup:                vts.Spec.BackupXtrabackup,
+				BackupCompression:         vts.Spec.BackupCompression,
+				BackupClusterName:         vts.Spec.BackupClusterName(vts.Labels[planetscalev2.ClusterLabel]),
 				Affinity:                  pool.Affinity,
 				ExtraEnv:                  pool.ExtraEnv,
 				ExtraVolumes:              pool.ExtraVolumes,
@@ -341,6 +522,15 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 				ExtraVolumeMounts:         pool.ExtraVolumeMounts,
 				Tolerations:               pool.Tolerations,
 				TopologySpreadConstraints: pool.TopologySpreadConstraints,
+				Networking:                vts.Spec.Networking,
+				HostNetwork:               pool.HostNetwork,
+				DNSPolicy:                 pool.DNSPolicy,
+				DNSConfig:                 pool.DNSConfig,
+				SpreadPolicy:              pool.SpreadPolicy,
+				NodeSelector:              pool.NodeSelector,
+				ProtectPrimary:            pool.ProtectPrimary,
+				ResourceDefaults:          vts.Spec.ResourceDefaults,
+				ScratchSizeLimit:          scratchSizeLimit,
 			})
 		}
 	}
@@ -348,8 +538,8 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 	return tablets
 }
 
-func isTabletPrimary(ctx context.Context, vts *planetscalev2.VitessShard, tabletAlias topodatapb.TabletAlias) (bool, error) {
-	ts, err := toposerver.Open(ctx, vts.Spec.GlobalLockserver)
+func isTabletPrimary(ctx context.Context, c client.Client, vts *planetscalev2.VitessShard, tabletAlias topodatapb.TabletAlias) (bool, error) {
+	ts, err := toposerver.Open(ctx, c, vts.Namespace, vts.Spec.GlobalLockserver)
 	if err != nil {
 		return true, err
 	}
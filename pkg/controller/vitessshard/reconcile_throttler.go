@@ -0,0 +1,105 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+	"planetscale.dev/vitess-operator/pkg/operator/webclient"
+)
+
+const (
+	// throttlerCheckMaxAge is how long the result of a throttler check is
+	// reused, so we don't check the primary on every reconcile.
+	throttlerCheckMaxAge = 30 * time.Second
+	// throttlerCheckApp is the app name we use for throttler checks, so
+	// they're not confused with checks from actual clients.
+	throttlerCheckApp = "vitess-operator"
+)
+
+// throttlerCheckResult is the JSON response of vttablet to /throttler/check.
+type throttlerCheckResult struct {
+	StatusCode int
+	Value      float64
+	Threshold  float64
+	Message    string
+}
+
+// reconcileThrottler checks the tablet throttler on the primary to report
+// its state in status.
+func (r *ReconcileVitessShard) reconcileThrottler(ctx context.Context, vts *planetscalev2.VitessShard) {
+	primaryAlias := vts.Status.MasterAlias
+	tablet, ok := vts.Status.Tablets[primaryAlias]
+	if !ok || tablet.Running != corev1.ConditionTrue {
+		return
+	}
+	alias, err := topoproto.ParseTabletAlias(primaryAlias)
+	if err != nil {
+		return
+	}
+	pool := vts.Spec.TabletPool(alias.Cell, tablet.PoolID())
+	if pool == nil || pool.Vttablet.Throttler == nil || !pool.Vttablet.Throttler.Enabled {
+		return
+	}
+
+	status := &planetscalev2.VitessShardThrottlerStatus{Throttled: corev1.ConditionUnknown}
+	vts.Status.Throttler = status
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.PodName(vts.Labels[planetscalev2.ClusterLabel], alias)}
+	if err := r.client.Get(ctx, key, pod); err != nil {
+		status.Message = fmt.Sprintf("can't get the primary tablet Pod: %v", err)
+		return
+	}
+	result, err := checkThrottler(ctx, r.webClient, pod.Status.PodIP)
+	if err != nil {
+		status.Message = err.Error()
+		return
+	}
+	if result.StatusCode == http.StatusOK {
+		status.Throttled = corev1.ConditionFalse
+	} else {
+		status.Throttled = corev1.ConditionTrue
+	}
+	status.Value = strconv.FormatFloat(result.Value, 'f', -1, 64)
+	status.Threshold = strconv.FormatFloat(result.Threshold, 'f', -1, 64)
+	status.Message = result.Message
+}
+
+// checkThrottler performs a throttler check against a tablet, the same way
+// online DDL does.
+func checkThrottler(ctx context.Context, c *webclient.Client, podIP string) (*throttlerCheckResult, error) {
+	if podIP == "" {
+		return nil, fmt.Errorf("the primary tablet Pod has no IP")
+	}
+
+	// Skip heartbeats so our checks don't keep an idle throttler busy.
+	result := &throttlerCheckResult{}
+	if _, err := c.GetJSON(ctx, webclient.URL(podIP, "/throttler/check?app="+throttlerCheckApp+"&s=true"), throttlerCheckMaxAge, result); err != nil {
+		return nil, fmt.Errorf("throttler check failed: %v", err)
+	}
+	return result, nil
+}
@@ -32,6 +32,7 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
 
@@ -56,7 +57,7 @@ func (r *ReconcileVitessShard) reconcileTopology(ctx context.Context, vts *plane
 	ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
 	defer cancel()
 
-	ts, err := toposerver.Open(ctx, vts.Spec.GlobalLockserver)
+	ts, err := toposerver.Open(ctx, r.client, vts.Namespace, vts.Spec.GlobalLockserver)
 	if err != nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
 		// Give the lockserver some time to come up.
@@ -77,7 +78,10 @@ func (r *ReconcileVitessShard) reconcileTopology(ctx context.Context, vts *plane
 		if servingCells, err := ts.GetShardServingCells(ctx, shard); err == nil {
 			vts.Status.Idle = k8s.ConditionStatus(len(servingCells) == 0)
 
-			if *vts.Spec.TopologyReconciliation.PruneShardCells {
+			// Check that vtgates can actually route queries to the shard.
+			r.reconcileRouting(ctx, vts, keyspaceName, servingCells)
+
+			if *vts.Spec.TopologyReconciliation.PruneShardCells && !reconciler.IsPaused(ctx) {
 				result, err := r.pruneShardCells(ctx, vts, keyspaceName, servingCells, wr)
 				resultBuilder.Merge(result, err)
 			}
@@ -86,12 +90,26 @@ func (r *ReconcileVitessShard) reconcileTopology(ctx context.Context, vts *plane
 			resultBuilder.RequeueAfter(topoRequeueDelay)
 		}
 	} else {
+		if topo.IsErrType(err, topo.NoNode) {
+			// A shard that isn't in topology, like the source shard of a
+			// completed Reshard workflow, can't serve anything.
+			vts.Status.Idle = corev1.ConditionTrue
+		}
 		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard info: %v", err)
 		resultBuilder.RequeueAfter(topoRequeueDelay)
 	}
 
 	// Get all the tablet records for this shard.
 	if tablets, err := ts.GetTabletMapForShard(ctx, keyspaceName, vts.Spec.Name); err == nil {
+		// Count the tablets we don't manage, so the keyspace isn't turned
+		// down while they still use it.
+		vts.Status.ExternalTablets = 0
+		for _, tabletInfo := range tablets {
+			if !vts.Spec.CellInCluster(tabletInfo.Alias.GetCell()) {
+				vts.Status.ExternalTablets++
+			}
+		}
+
 		// Update status for desired tablets.
 		for name, status := range vts.Status.Tablets {
 			tablet := tablets[name]
@@ -102,7 +120,13 @@ func (r *ReconcileVitessShard) reconcileTopology(ctx context.Context, vts *plane
 			vts.Status.Tablets[name] = status
 		}
 
-		if *vts.Spec.TopologyReconciliation.PruneTablets {
+		// Ask each tablet how far it has replicated.
+		// NOTE: This must always be done after the shard record is read, so
+		// Status.MasterAlias is populated.
+		r.reconcileReplicationStatus(ctx, vts, tablets)
+
+		// Tablets that haven't been adopted yet may still be serving.
+		if *vts.Spec.TopologyReconciliation.PruneTablets && !reconciler.IsPaused(ctx) && !vts.Spec.AdoptExisting {
 			result, err := r.pruneTablets(ctx, vts, tablets, wr)
 			resultBuilder.Merge(result, err)
 		}
@@ -168,8 +192,8 @@ func (r *ReconcileVitessShard) pruneShardCells(ctx context.Context, vts *planets
 			Keyspace:  keyspaceName,
 			ShardName: vts.Spec.Name,
 			Cell:      cellName,
-			Force:     false /* force */,
-			Recursive: false /* recursive */,
+			Force:     false, /* force */
+			Recursive: false, /* recursive */
 		}); err != nil {
 			r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoCleanupFailed", "unable to remove cell %s from shard: %v", cellName, err)
 			resultBuilder.RequeueAfter(topoRequeueDelay)
@@ -19,8 +19,6 @@ package vitessshard
 import (
 	"context"
 
-	"planetscale.dev/vitess-operator/pkg/operator/update"
-
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +29,7 @@ import (
 	"planetscale.dev/vitess-operator/pkg/operator/conditions"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 	"planetscale.dev/vitess-operator/pkg/operator/vtorc"
 )
 
@@ -107,6 +106,11 @@ func (r *ReconcileVitessShard) vtorcSpecs(vts *planetscalev2.VitessShard, parent
 	if vts.Spec.VitessOrchestrator == nil {
 		return nil
 	}
+	// A standby has no primary for vtorc to look after, and vtorc would
+	// try to repair that by electing one.
+	if vts.Spec.IsStandby() {
+		return nil
+	}
 
 	specs := make([]*vtorc.Spec, 0, len(vts.Spec.TabletPools))
 
@@ -131,9 +135,8 @@ func (r *ReconcileVitessShard) vtorcSpecs(vts *planetscalev2.VitessShard, parent
 		labels[planetscalev2.CellLabel] = tabletPool.Cell
 
 		// Merge ExtraVitessFlags and ExtraFlags into a new map.
-		extraFlags := make(map[string]string)
-		update.StringMap(&extraFlags, vts.Spec.ExtraVitessFlags)
-		update.StringMap(&extraFlags, vts.Spec.VitessOrchestrator.ExtraFlags)
+		// updateManagedFlagsCondition reports any flags that are left out.
+		extraFlags, _ := vitess.MergeExtraFlags(vts.Spec.ComponentVitessFlags.ManagedFlagOverrides(), vts.Spec.ExtraVitessFlags, vts.Spec.ComponentVitessFlags.VtorcFlags(), vts.Spec.VitessOrchestrator.ExtraFlags)
 
 		specs = append(specs, &vtorc.Spec{
 			GlobalLockserver:  vts.Spec.GlobalLockserver,
@@ -153,6 +156,7 @@ func (r *ReconcileVitessShard) vtorcSpecs(vts *planetscalev2.VitessShard, parent
 			ExtraVolumeMounts: vts.Spec.VitessOrchestrator.ExtraVolumeMounts,
 			InitContainers:    vts.Spec.VitessOrchestrator.InitContainers,
 			SidecarContainers: vts.Spec.VitessOrchestrator.SidecarContainers,
+			ResourceDefaults:  vts.Spec.ResourceDefaults,
 			Annotations:       vts.Spec.VitessOrchestrator.Annotations,
 			ExtraLabels:       vts.Spec.VitessOrchestrator.ExtraLabels,
 			Tolerations:       vts.Spec.VitessOrchestrator.Tolerations,
@@ -0,0 +1,48 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// reportTransitions emits events for significant changes between the old and
+// new status of the shard, which can trigger notifications. It returns
+// whether there were any, in which case the new status should be written
+// right away, so the same change isn't reported again on the next reconcile.
+func (r *ReconcileVitessShard) reportTransitions(vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus) bool {
+	reported := false
+
+	// The alias is empty whenever the shard record couldn't be read, so only
+	// compare known primaries.
+	if oldStatus.MasterAlias != "" && vts.Status.MasterAlias != "" && oldStatus.MasterAlias != vts.Status.MasterAlias {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryChanged", "primary changed from %v to %v", oldStatus.MasterAlias, vts.Status.MasterAlias)
+		reported = true
+	}
+	if oldStatus.HasMaster == corev1.ConditionTrue && vts.Status.HasMaster == corev1.ConditionFalse {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryLost", "shard has no primary anymore; it was %v", oldStatus.MasterAlias)
+		reported = true
+	}
+	if oldStatus.HasInitialBackup != corev1.ConditionFalse && vts.Status.HasInitialBackup == corev1.ConditionFalse {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "BackupFailed", "initial backup of the shard failed")
+		reported = true
+	}
+
+	return reported
+}
@@ -37,10 +37,16 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/follower"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
+	"planetscale.dev/vitess-operator/pkg/operator/statusupdate"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 	"planetscale.dev/vitess-operator/pkg/operator/vitessshard"
+	"planetscale.dev/vitess-operator/pkg/operator/webclient"
 )
 
 const (
@@ -58,6 +64,8 @@ var log = logrus.WithField("controller", "VitessShard")
 var watchResources = []client.Object{
 	&corev1.Pod{},
 	&corev1.PersistentVolumeClaim{},
+	&corev1.Secret{},
+	&planetscalev2.VitessTabletPool{},
 }
 
 // Add creates a new VitessShard Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -68,23 +76,26 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) *ReconcileVitessShard {
-	c := mgr.GetClient()
+	c := follower.Client(mgr.GetClient())
 	scheme := mgr.GetScheme()
-	recorder := mgr.GetEventRecorderFor(controllerName)
+	recorder := follower.Recorder(mgr.GetEventRecorderFor(controllerName))
 
 	return &ReconcileVitessShard{
-		client:     c,
-		scheme:     scheme,
-		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
-		recorder:   recorder,
-		reconciler: reconciler.New(c, scheme, recorder),
+		client:       c,
+		apiReader:    mgr.GetAPIReader(),
+		scheme:       scheme,
+		resync:       resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder:     recorder,
+		reconciler:   reconciler.New(c, scheme, recorder),
+		statusWriter: statusupdate.NewWriter(c, "VitessShard", environment.StatusUpdateInterval()),
+		webClient:    webclient.Default,
 	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r *ReconcileVitessShard) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{
+	c, err := follower.NewController(controllerName, mgr, controller.Options{
 		Reconciler:              r,
 		MaxConcurrentReconciles: *maxConcurrentReconciles,
 	})
@@ -128,11 +139,16 @@ var _ reconcile.Reconciler = &ReconcileVitessShard{}
 type ReconcileVitessShard struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client     client.Client
-	scheme     *runtime.Scheme
-	resync     *resync.Periodic
-	recorder   record.EventRecorder
-	reconciler *reconciler.Reconciler
+	client client.Client
+	// apiReader reads PersistentVolumes and Nodes directly from the
+	// apiserver, so we don't need to cache all of them.
+	apiReader    client.Reader
+	scheme       *runtime.Scheme
+	resync       *resync.Periodic
+	recorder     record.EventRecorder
+	reconciler   *reconciler.Reconciler
+	statusWriter *statusupdate.Writer
+	webClient    *webclient.Client
 }
 
 // Reconcile reads that state of the cluster for a VitessShard object and makes changes based on the state read
@@ -140,16 +156,21 @@ type ReconcileVitessShard struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile.Request) (_ reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
 	defer cancel()
 
+	ctx, span := tracing.StartReconcile(ctx, "VitessShard", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	resultBuilder := &results.Builder{}
 
 	log := log.WithFields(logrus.Fields{
-		"namespace":   request.Namespace,
-		"vitessshard": request.Name,
+		"namespace":              request.Namespace,
+		"vitessshard":            request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	// Shards have a periodic reconciliation to check replication,
 	// so we log at Debug because this is noisy.
 	log.Debug("Reconciling VitessShard")
@@ -167,6 +188,8 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 		// Error reading the object - requeue the request.
 		return resultBuilder.Error(err)
 	}
+	log = log.WithFields(logging.ObjectFields(vts))
+	ctx = logging.NewContext(ctx, log)
 	planetscalev2.DefaultVitessShard(vts)
 
 	// Reset status, since that's all out of date info that we will recompute now.
@@ -177,43 +200,130 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 	if oldStatus.Conditions != nil {
 		vts.Status.Conditions = oldStatus.DeepCopyConditions()
 	}
+	// Keep the latest backup we know of until reconcileBackupJob recomputes
+	// it, since earlier steps check it before disruptive operations.
+	vts.Status.Backup = oldStatus.Backup
+	// Keep the last count of external tablets in case the topology can't be
+	// read, so the keyspace isn't mistaken for idle.
+	vts.Status.ExternalTablets = oldStatus.ExternalTablets
+	// The replication controller records planned reparents in progress.
+	vts.Status.Reparent = oldStatus.Reparent
+	// It also records the MySQL users it applied.
+	vts.Status.Users = oldStatus.Users
+	// And it rotates the passwords of the internal MySQL accounts.
+	vts.Status.InternalCredentials = oldStatus.InternalCredentials
+
+	// While paused, we only compute status.
+	if vts.Spec.IsPaused() {
+		log.Info("Reconciliation is paused")
+		ctx = reconciler.NewPausedContext(ctx)
+	}
+	if vts.Spec.AdoptExisting {
+		ctx = reconciler.NewAdoptingContext(ctx)
+	}
+	// Replicas that aren't the leader only compute status.
+	ctx = follower.NewContext(ctx)
+	// In a dry run, we also report what we would have changed.
+	var dryRun *reconciler.DryRun
+	if vts.Spec.DryRun {
+		log.Info("Reconciling as a dry run")
+		ctx, dryRun = reconciler.NewDryRunContext(ctx)
+	}
+
+	// Report extra flags that are left out because the operator manages them.
+	updateManagedFlagsCondition(vts)
+	// Report scratch settings that can't be honored.
+	updateScratchPoolsCondition(vts)
+	// Check whether disruptive changes may be applied now.
+	// NOTE: This must always be done before anything that calls
+	// disruptionAllowed.
+	updateMaintenanceCondition(vts)
 
 	// Create/update vtorc.
 	vtorcResult, err := r.reconcileVtorc(ctx, vts)
 	resultBuilder.Merge(vtorcResult, err)
 
+	// Keep track of the images and flags that tablets ran successfully.
+	// NOTE: This must always be done before reconcileTablets.
+	if err := r.reconcileRollback(ctx, vts, &oldStatus); err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Decide which tablets should run a new MySQL major version, if needed.
+	// NOTE: This must always be done before reconcileTablets.
+	mysqlUpgradeResult, err := r.reconcileMysqlUpgrade(ctx, vts, &oldStatus)
+	resultBuilder.Merge(mysqlUpgradeResult, err)
+
+	// Render the init_db.sql script and the credentials of the internal MySQL
+	// accounts for tablets.
+	// NOTE: This must always be done before reconcileTablets.
+	tabletSecretsResult, err := r.reconcileTabletSecrets(ctx, vts, &oldStatus)
+	resultBuilder.Merge(tabletSecretsResult, err)
+
+	// Create/update the objects for vertical autoscaling, and decide on the
+	// requests of tablets in "Auto" mode.
+	// NOTE: This must always be done before reconcileTablets.
+	autoscalingResult, err := r.reconcileVerticalAutoscaling(ctx, vts, &oldStatus)
+	resultBuilder.Merge(autoscalingResult, err)
+
 	// Create/update desired tablets.
-	tabletResult, err := r.reconcileTablets(ctx, vts)
+	tabletResult, err := r.reconcileTablets(ctx, vts, &oldStatus)
 	resultBuilder.Merge(tabletResult, err)
 
-	// Mark tablet pods for disk size updates if needed.
-	// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated
-	diskUpdateResult, err := r.reconcileDisk(ctx, vts)
-	resultBuilder.Merge(diskUpdateResult, err)
+	if !vts.Spec.IsPaused() {
+		// Mark tablet pods for disk size updates if needed.
+		// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated
+		diskUpdateResult, err := r.reconcileDisk(ctx, vts)
+		resultBuilder.Merge(diskUpdateResult, err)
 
-	// Perform rolling updates on tablets if needed.
-	// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated.
-	rolloutResult, err := r.reconcileRollout(ctx, vts)
-	resultBuilder.Merge(rolloutResult, err)
+		// Perform rolling updates on tablets if needed.
+		// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated.
+		rolloutResult, err := r.reconcileRollout(ctx, vts)
+		resultBuilder.Merge(rolloutResult, err)
+
+		// Recreate scratch tablets that are due to restore a fresh backup.
+		scratchResult, err := r.reconcileScratch(ctx, vts)
+		resultBuilder.Merge(scratchResult, err)
+	}
 
 	// Check latest Vitess topology state and update as needed.
 	// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated.
 	topoResult, err := r.reconcileTopology(ctx, vts)
 	resultBuilder.Merge(topoResult, err)
 
+	// Check the tablet throttler on the primary.
+	// NOTE: This must always be done after reconcileTopology, so Status.MasterAlias is populated.
+	r.reconcileThrottler(ctx, vts)
+
 	// Take initial or periodic backups, if appropriate.
 	backupResult, err := r.reconcileBackupJob(ctx, vts)
 	resultBuilder.Merge(backupResult, err)
 
+	if !vts.Spec.IsPaused() {
+		// Recreate tablets elsewhere whose local volume was lost with its Node.
+		// NOTE: This must always be done after reconcileTopology and
+		// reconcileBackupJob, so the primary and backups are known.
+		localStorageResult, err := r.reconcileLocalStorage(ctx, vts)
+		resultBuilder.Merge(localStorageResult, err)
+	}
+
+	// Keep a standby restoring the source cluster's latest backups.
+	standbyResult, err := r.reconcileStandby(ctx, vts)
+	resultBuilder.Merge(standbyResult, err)
+
+	if dryRun != nil {
+		vts.Status.DryRunChanges = dryRun.Changes()
+	}
+
+	// Report significant changes in the state of the shard.
+	transitioned := r.reportTransitions(vts, &oldStatus)
+
 	// Update status if needed.
-	vts.Status.ObservedGeneration = vts.Generation
-	if !apiequality.Semantic.DeepEqual(&vts.Status, &oldStatus) {
-		if err := r.client.Status().Update(ctx, vts); err != nil {
-			if !apierrors.IsConflict(err) {
-				r.recorder.Eventf(vts, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
-			}
-			resultBuilder.Error(err)
-		}
+	if delay, err := r.updateStatus(ctx, vts, &oldStatus, transitioned); err != nil {
+		resultBuilder.Error(err)
+	} else if delay > 0 {
+		// Write the latest status once the interval has passed.
+		resultBuilder.RequeueAfter(delay)
 	}
 
 	// Request a periodic resync for the shard so we can recheck topology and
@@ -225,6 +335,31 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 	return result, err
 }
 
+// updateStatus writes the shard's status, if it changed. It returns how long
+// to wait before writing it, if it was written too recently.
+func (r *ReconcileVitessShard) updateStatus(ctx context.Context, vts *planetscalev2.VitessShard, oldStatus *planetscalev2.VitessShardStatus, transitioned bool) (time.Duration, error) {
+	vts.Status.ObservedGeneration = vts.Generation
+	urgent := oldStatus.ObservedGeneration != vts.Generation || transitioned || decisionsChanged(oldStatus, &vts.Status)
+	delay, err := r.statusWriter.Update(ctx, vts, &vts.Status, oldStatus, urgent)
+	if err != nil && !apierrors.IsConflict(err) {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
+	}
+	return delay, err
+}
+
+// decisionsChanged returns whether any of the status fields that record the
+// operator's decisions changed. Later reconciles act on those, so they're
+// written right away. For example, if a tablet that's being rolled back were
+// still listed as upgraded in the status that's read next, it would be
+// recreated with the new mysqld image again.
+func decisionsChanged(oldStatus, newStatus *planetscalev2.VitessShardStatus) bool {
+	return !apiequality.Semantic.DeepEqual(oldStatus.MysqlUpgrade, newStatus.MysqlUpgrade) ||
+		!apiequality.Semantic.DeepEqual(oldStatus.LastKnownGood, newStatus.LastKnownGood) ||
+		!apiequality.Semantic.DeepEqual(oldStatus.Revisions, newStatus.Revisions) ||
+		oldStatus.RevertedToRevision != newStatus.RevertedToRevision ||
+		!apiequality.Semantic.DeepEqual(oldStatus.RevertedTablets, newStatus.RevertedTablets)
+}
+
 // Map maps a VitessBackup to a list of requests for VitessShards.
 func shardBackupMapper(obj client.Object) []reconcile.Request {
 	vtb := obj.(*planetscalev2.VitessBackup)
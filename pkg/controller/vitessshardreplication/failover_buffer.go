@@ -0,0 +1,53 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"time"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// coordinatingReparents returns whether planned reparents should fit into the
+// vtgate failover buffer.
+func coordinatingReparents(buffer *planetscalev2.FailoverBufferSpec) bool {
+	return buffer != nil && buffer.CoordinateReparents && buffer.Enabled != nil && *buffer.Enabled
+}
+
+// reparentWaitTimeout returns how long a planned reparent may wait for
+// replicas to catch up with the old primary.
+func reparentWaitTimeout(buffer *planetscalev2.FailoverBufferSpec) time.Duration {
+	if !coordinatingReparents(buffer) || buffer.MaxFailoverDuration == nil {
+		return plannedReparentTimeout
+	}
+	if timeout := buffer.MaxFailoverDuration.Duration; timeout > 0 && timeout < plannedReparentTimeout {
+		return timeout
+	}
+	return plannedReparentTimeout
+}
+
+// reparentCooldown returns how much longer to wait before a planned reparent,
+// so vtgate buffers it, given when the current primary's term started.
+func reparentCooldown(buffer *planetscalev2.FailoverBufferSpec, termStart, now time.Time) time.Duration {
+	if !coordinatingReparents(buffer) || buffer.MinTimeBetweenFailovers == nil || termStart.IsZero() {
+		return 0
+	}
+	if remaining := termStart.Add(buffer.MinTimeBetweenFailovers.Duration).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
@@ -38,6 +38,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 )
 
@@ -270,7 +271,7 @@ func electInitialShardPrimary(ctx context.Context, keyspaceName, shardName strin
 			defer wg.Done()
 			err := wr.TabletManagerClient().SetReplicationSource(ctx, tablet.Tablet, candidatePrimary.tablet.Alias, 0 /* don't try to wait for a reparent journal entry */, "" /* don't wait for any position */, true /* forceStartReplication */, reparentutil.IsReplicaSemiSync(durability, candidatePrimary.tablet.Tablet, tablet.Tablet))
 			if err != nil {
-				log.Warningf("best-effort configuration of replication for tablet %v failed: %v", tablet.AliasString(), err)
+				logging.FromContext(ctx).WithField(logging.TabletField, tablet.AliasString()).Warningf("best-effort configuration of replication for tablet %v failed: %v", tablet.AliasString(), err)
 			}
 		}(replicaStatus.tablet)
 	}
@@ -344,7 +345,8 @@ func getTabletStatus(ctx context.Context, tmc tmclient.TabletManagerClient, tabl
 // values as well as the latest known value pulled from our build dependency.
 //
 // TODO: Add an officially-supported signal in the Vitess RPC to recognize this
-//       important state programmatically.
+//
+//	important state programmatically.
 func isErrNotReplica(err error) bool {
 	errString := err.Error()
 
@@ -0,0 +1,389 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/shardaction"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// backupNowTimeout is the timeout for a backup requested with the
+	// backup-now action. Backups run in the background, so this can be much
+	// longer than a reconcile pass.
+	backupNowTimeout = 6 * time.Hour
+	// backupNowConcurrency is the number of files to back up in parallel,
+	// which is the same default that vtctld uses.
+	backupNowConcurrency = 4
+	// actionReportTimeout is the timeout for recording the outcome of an
+	// action that ran in the background.
+	actionReportTimeout = 10 * time.Second
+)
+
+// backupNowAction is the action whose outcome failInterruptedBackup reports.
+var backupNowAction = shardaction.Action{Name: "BackupNow", Annotation: shardaction.BackupNowAnnotation}
+
+/*
+reconcileActions executes one-off actions requested with annotations on the
+VitessShard. See the "shardaction" package for the supported actions.
+
+Each request is removed before the action starts, so it's executed at most
+once, even if it fails. The outcome is reported in events and in the
+ActionSucceeded condition.
+*/
+func (r *ReconcileVitessShard) reconcileActions(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	r.failInterruptedBackup(ctx, vts)
+
+	actions := shardaction.Pending(vts)
+	if len(actions) == 0 {
+		return resultBuilder.Result()
+	}
+
+	// Patch a copy, since our in-memory object has defaults filled in that
+	// we don't want to lose or send to the server.
+	obj := vts.DeepCopy()
+	patch := client.MergeFrom(vts)
+	for _, action := range actions {
+		shardaction.Clear(obj, action)
+	}
+	if err := r.client.Patch(ctx, obj, patch); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to clear action annotations: %v", err)
+		return resultBuilder.Error(err)
+	}
+
+	for _, action := range actions {
+		var message string
+		var err error
+		switch action.Annotation {
+		case shardaction.ReparentToAnnotation:
+			message, err = r.reparentTo(ctx, vts, wr, action.Value)
+		case shardaction.RestartTabletAnnotation:
+			message, err = r.restartTablet(ctx, vts, wr, action.Value)
+		case shardaction.DebugTabletAnnotation:
+			message, err = r.debugTablet(ctx, vts, action.Value)
+		case shardaction.BackupNowAnnotation:
+			err = r.backupNow(ctx, vts, wr, action)
+			if err == nil {
+				// The backup reports its own outcome when it's done.
+				continue
+			}
+		}
+		r.reportAction(ctx, vts, action, message, err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// reportAction records the outcome of an action in an event and in the
+// ActionSucceeded condition.
+func (r *ReconcileVitessShard) reportAction(ctx context.Context, vts *planetscalev2.VitessShard, action shardaction.Action, message string, err error) {
+	status := corev1.ConditionTrue
+	if err != nil {
+		status = corev1.ConditionFalse
+		message = err.Error()
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, action.Name+"Failed", "%s", message)
+	} else {
+		r.recorder.Event(vts, corev1.EventTypeNormal, action.Name, message)
+	}
+	if err := r.setActionCondition(ctx, vts, status, action.Name, message); err != nil {
+		logging.FromContext(ctx).WithError(err).Warning("Failed to update ActionSucceeded condition")
+	}
+}
+
+func (r *ReconcileVitessShard) setActionCondition(ctx context.Context, vts *planetscalev2.VitessShard, status corev1.ConditionStatus, reason, message string) error {
+	// Only the condition changes, so a merge patch from a possibly stale
+	// copy won't clobber anything the main VitessShard controller wrote.
+	obj := vts.DeepCopy()
+	patch := client.MergeFrom(vts)
+	obj.Status.SetConditionStatus(planetscalev2.VitessShardActionSucceeded, status, reason, message)
+	return r.client.Status().Patch(ctx, obj, patch)
+}
+
+func (r *ReconcileVitessShard) reparentTo(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, value string) (string, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	newPrimaryAlias, err := topoproto.ParseTabletAlias(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid tablet alias %q: %v", value, err)
+	}
+
+	reparentCtx, reparentCancel := context.WithTimeout(ctx, plannedReparentTimeout)
+	defer reparentCancel()
+
+	shard, err := wr.TopoServer().GetShard(reparentCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get shard record: %v", err)
+	}
+	if topoproto.TabletAliasEqual(shard.PrimaryAlias, newPrimaryAlias) {
+		return fmt.Sprintf("tablet %v is already the primary", value), nil
+	}
+	oldPrimaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+
+	if vts.Spec.UsingExternalDatastore() {
+		err = r.handleExternalReparent(reparentCtx, vts, wr, newPrimaryAlias, shard.PrimaryAlias)
+	} else {
+		err = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimaryAlias, nil, reparentWaitTimeout(vts.Spec.FailoverBuffer))
+	}
+	plannedReparentCount.WithLabelValues(metricLabels(vts, err)...).Inc()
+	if err != nil {
+		return "", fmt.Errorf("planned reparent from current primary %v to requested primary %v failed: %v", oldPrimaryAliasStr, value, err)
+	}
+	return fmt.Sprintf("planned reparent from old primary %v to new primary %v succeeded", oldPrimaryAliasStr, value), nil
+}
+
+func (r *ReconcileVitessShard) restartTablet(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, value string) (string, error) {
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	tabletAlias, err := topoproto.ParseTabletAlias(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid tablet alias %q: %v", value, err)
+	}
+	tabletAliasStr := topoproto.TabletAliasString(tabletAlias)
+	if _, ok := vts.Status.Tablets[tabletAliasStr]; !ok {
+		return "", fmt.Errorf("tablet %v is not one of the desired tablets of this shard", tabletAliasStr)
+	}
+
+	shard, err := wr.TopoServer().GetShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get shard record: %v", err)
+	}
+	if topoproto.TabletAliasEqual(shard.PrimaryAlias, tabletAlias) {
+		return "", fmt.Errorf("tablet %v is the primary; request a reparent away from it before restarting it", tabletAliasStr)
+	}
+
+	// Restarting a tablet that's unhealthy is a reasonable thing to try,
+	// but don't take down a healthy tablet while another one is already down.
+	for name, tablet := range vts.Status.Tablets {
+		if name != tabletAliasStr && tablet.Available != corev1.ConditionTrue {
+			return "", fmt.Errorf("not restarting tablet %v because tablet %v is not Available", tabletAliasStr, name)
+		}
+	}
+
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.PodName(clusterName, tabletAlias)}
+	if err := r.client.Get(ctx, key, pod); err != nil {
+		return "", fmt.Errorf("failed to get Pod %v: %v", key.Name, err)
+	}
+	if err := r.client.Delete(ctx, pod, client.Preconditions{UID: &pod.UID}); err != nil {
+		return "", fmt.Errorf("failed to delete Pod %v: %v", pod.Name, err)
+	}
+	return fmt.Sprintf("deleted Pod %v to restart tablet %v", pod.Name, tabletAliasStr), nil
+}
+
+func (r *ReconcileVitessShard) debugTablet(ctx context.Context, vts *planetscalev2.VitessShard, value string) (string, error) {
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+
+	tabletAlias, err := topoproto.ParseTabletAlias(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid tablet alias %q: %v", value, err)
+	}
+	tabletAliasStr := topoproto.TabletAliasString(tabletAlias)
+	if _, ok := vts.Status.Tablets[tabletAliasStr]; !ok {
+		return "", fmt.Errorf("tablet %v is not one of the desired tablets of this shard", tabletAliasStr)
+	}
+
+	pod := &corev1.Pod{}
+	key := client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.PodName(clusterName, tabletAlias)}
+	if err := r.client.Get(ctx, key, pod); err != nil {
+		return "", fmt.Errorf("failed to get Pod %v: %v", key.Name, err)
+	}
+	if name := vttablet.RunningDebugContainer(pod); name != "" {
+		return fmt.Sprintf("debug container %v is already running in Pod %v; attach with: kubectl attach -it -n %v %v -c %v", name, pod.Name, pod.Namespace, pod.Name, name), nil
+	}
+
+	container := vttablet.DebugContainer(pod, vts.Spec.Images.Debug, environment.DebugContainerDuration())
+	if container == nil {
+		return "", fmt.Errorf("no tablet container to debug in Pod %v", pod.Name)
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, *container)
+	if err := r.client.SubResource("ephemeralcontainers").Update(ctx, pod); err != nil {
+		return "", fmt.Errorf("failed to add debug container to Pod %v: %v", pod.Name, err)
+	}
+	return fmt.Sprintf("added debug container %v to Pod %v for %v; attach with: kubectl attach -it -n %v %v -c %v", container.Name, pod.Name, environment.DebugContainerDuration(), pod.Namespace, pod.Name, container.Name), nil
+}
+
+// backupNow starts a backup in the background. If it returns nil, the backup
+// has been started and will report its own outcome.
+func (r *ReconcileVitessShard) backupNow(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, action shardaction.Action) error {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	if vts.Spec.UsingExternalDatastore() || !vts.Spec.AllPoolsUsingMysqld() {
+		return fmt.Errorf("backups are not supported for shards without local MySQL")
+	}
+	if len(vts.Spec.BackupLocations) == 0 {
+		return fmt.Errorf("no backup locations are configured")
+	}
+	if vts.Spec.IsStandby() {
+		return fmt.Errorf("a standby can't take backups, since they would go to the source cluster %v", vts.Spec.Standby.SourceClusterName)
+	}
+
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(ctx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		return fmt.Errorf("failed to get tablet records: %v", err)
+	}
+	var tablet *topo.TabletInfo
+	if action.Value != "" {
+		if tablet = tablets[action.Value]; tablet == nil {
+			return fmt.Errorf("tablet %v is not one of the tablets of this shard", action.Value)
+		}
+	} else {
+		if tablet = backupCandidate(vts, tablets); tablet == nil {
+			return fmt.Errorf("no Available replica or rdonly tablet to back up from")
+		}
+	}
+	tabletAliasStr := tablet.AliasString()
+
+	key := types.NamespacedName{Namespace: vts.Namespace, Name: vts.Name}
+	r.backupsMu.Lock()
+	if r.backupsRunning[key] {
+		r.backupsMu.Unlock()
+		return fmt.Errorf("a backup requested with %v is already running for this shard", shardaction.BackupNowAnnotation)
+	}
+	r.backupsRunning[key] = true
+	r.backupsMu.Unlock()
+
+	message := fmt.Sprintf("backup of tablet %v started", tabletAliasStr)
+	r.recorder.Event(vts, corev1.EventTypeNormal, action.Name+"Started", message)
+	if err := r.setActionCondition(ctx, vts, corev1.ConditionUnknown, action.Name, message); err != nil {
+		logging.FromContext(ctx).WithError(err).Warning("Failed to update ActionSucceeded condition")
+	}
+
+	go r.runBackup(key, vts.DeepCopy(), action, tablet.Tablet)
+	return nil
+}
+
+/*
+failInterruptedBackup reports a backup-now action as failed if the process
+that ran it is gone.
+
+Backups run in a goroutine of the operator process that started them. If that
+process exits or loses leadership, the stream to the tablet is closed, which
+cancels the backup, but nothing is left to report the outcome. Without this,
+the ActionSucceeded condition would stay Unknown forever.
+*/
+func (r *ReconcileVitessShard) failInterruptedBackup(ctx context.Context, vts *planetscalev2.VitessShard) {
+	cond, ok := vts.Status.Conditions[planetscalev2.VitessShardActionSucceeded]
+	if !ok || cond.Status != corev1.ConditionUnknown || cond.Reason != backupNowAction.Name {
+		return
+	}
+	// A backup we started ourselves reports its own outcome. Our cache may
+	// not have caught up with that report yet, so only fail backups started
+	// before this process.
+	if cond.LastTransitionTime == nil || !cond.LastTransitionTime.Time.Before(r.started) {
+		return
+	}
+	key := types.NamespacedName{Namespace: vts.Namespace, Name: vts.Name}
+	r.backupsMu.Lock()
+	running := r.backupsRunning[key]
+	r.backupsMu.Unlock()
+	if running {
+		return
+	}
+
+	err := fmt.Errorf("%s; it was interrupted because the operator restarted or lost leadership, so request another backup", cond.Message)
+	r.reportAction(ctx, vts, backupNowAction, "", err)
+}
+
+func (r *ReconcileVitessShard) runBackup(key types.NamespacedName, vts *planetscalev2.VitessShard, action shardaction.Action, tablet *topodatapb.Tablet) {
+	defer func() {
+		r.backupsMu.Lock()
+		delete(r.backupsRunning, key)
+		r.backupsMu.Unlock()
+	}()
+
+	tabletAliasStr := topoproto.TabletAliasString(tablet.Alias)
+	log := log.WithFields(logging.ObjectFields(vts)).WithField(logging.TabletField, tabletAliasStr)
+	ctx := logging.NewContext(context.Background(), log)
+
+	backupCtx, backupCancel := context.WithTimeout(ctx, backupNowTimeout)
+	defer backupCancel()
+
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+
+	stream, err := tmc.Backup(backupCtx, tablet, &tabletmanagerdatapb.BackupRequest{Concurrency: backupNowConcurrency})
+	if err == nil {
+		for {
+			event, recvErr := stream.Recv()
+			if recvErr != nil {
+				if recvErr != io.EOF {
+					err = recvErr
+				}
+				break
+			}
+			log.Debug(logutil.EventString(event))
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("backup of tablet %v failed: %v", tabletAliasStr, err)
+	}
+
+	// The backup may have used up the whole timeout, so give reporting its own.
+	reportCtx, reportCancel := context.WithTimeout(ctx, actionReportTimeout)
+	defer reportCancel()
+	r.reportAction(reportCtx, vts, action, fmt.Sprintf("backup of tablet %v completed", tabletAliasStr), err)
+}
+
+// backupCandidate chooses an Available, non-primary tablet to back up from,
+// preferring rdonly tablets since they don't serve replica traffic.
+func backupCandidate(vts *planetscalev2.VitessShard, tablets map[string]*topo.TabletInfo) *topo.TabletInfo {
+	aliases := make([]string, 0, len(tablets))
+	for alias := range tablets {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	for _, tabletType := range []topodatapb.TabletType{topodatapb.TabletType_RDONLY, topodatapb.TabletType_REPLICA} {
+		for _, alias := range aliases {
+			tablet := tablets[alias]
+			if tablet.Type != tabletType {
+				continue
+			}
+			if vts.Status.Tablets[alias].Available != corev1.ConditionTrue {
+				continue
+			}
+			return tablet
+		}
+	}
+	return nil
+}
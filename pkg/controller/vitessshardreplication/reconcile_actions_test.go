@@ -0,0 +1,146 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestFailInterruptedBackup(t *testing.T) {
+	started := time.Now()
+	before := metav1.NewTime(started.Add(-time.Minute))
+	after := metav1.NewTime(started.Add(time.Minute))
+
+	table := []struct {
+		name    string
+		status  corev1.ConditionStatus
+		reason  string
+		since   metav1.Time
+		running bool
+
+		wantStatus corev1.ConditionStatus
+	}{
+		{
+			name:       "started by a previous process",
+			status:     corev1.ConditionUnknown,
+			reason:     "BackupNow",
+			since:      before,
+			wantStatus: corev1.ConditionFalse,
+		},
+		{
+			name:       "started by this process",
+			status:     corev1.ConditionUnknown,
+			reason:     "BackupNow",
+			since:      after,
+			wantStatus: corev1.ConditionUnknown,
+		},
+		{
+			name:       "still running",
+			status:     corev1.ConditionUnknown,
+			reason:     "BackupNow",
+			since:      before,
+			running:    true,
+			wantStatus: corev1.ConditionUnknown,
+		},
+		{
+			name:       "finished",
+			status:     corev1.ConditionTrue,
+			reason:     "BackupNow",
+			since:      before,
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name:       "other action",
+			status:     corev1.ConditionUnknown,
+			reason:     "ReparentTo",
+			since:      before,
+			wantStatus: corev1.ConditionUnknown,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := planetscalev2.SchemeBuilder.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() error: %v", err)
+			}
+
+			vts := &planetscalev2.VitessShard{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "shard"},
+			}
+			vts.Status.Conditions = map[planetscalev2.VitessShardConditionType]planetscalev2.VitessShardCondition{
+				planetscalev2.VitessShardActionSucceeded: {
+					Status:             test.status,
+					Reason:             test.reason,
+					Message:            "backup of tablet zone1-0000000101 started",
+					LastTransitionTime: &test.since,
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vts.DeepCopy()).Build()
+			recorder := record.NewFakeRecorder(10)
+			key := types.NamespacedName{Namespace: vts.Namespace, Name: vts.Name}
+			r := &ReconcileVitessShard{
+				client:         c,
+				recorder:       recorder,
+				backupsRunning: map[types.NamespacedName]bool{key: test.running},
+				started:        started,
+			}
+
+			r.failInterruptedBackup(ctx, vts)
+
+			got := &planetscalev2.VitessShard{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(vts), got); err != nil {
+				t.Fatalf("Get() error: %v", err)
+			}
+			cond := got.Status.Conditions[planetscalev2.VitessShardActionSucceeded]
+			if cond.Status != test.wantStatus {
+				t.Errorf("ActionSucceeded = %v; want %v", cond.Status, test.wantStatus)
+			}
+			if test.wantStatus != corev1.ConditionFalse {
+				if len(recorder.Events) != 0 {
+					t.Errorf("event = %q; want none", <-recorder.Events)
+				}
+				return
+			}
+			if !strings.Contains(cond.Message, "zone1-0000000101") {
+				t.Errorf("ActionSucceeded message = %q; want it to name the tablet", cond.Message)
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.HasPrefix(event, "Warning BackupNowFailed ") {
+					t.Errorf("event = %q; want a BackupNowFailed warning", event)
+				}
+			default:
+				t.Errorf("no event recorded")
+			}
+		})
+	}
+}
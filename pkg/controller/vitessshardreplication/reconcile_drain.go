@@ -28,14 +28,17 @@ import (
 	"vitess.io/vitess/go/vt/wrangler"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
-	"k8s.io/kubectl/pkg/util/podutils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
 	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
 )
 
@@ -51,6 +54,9 @@ const (
 	plannedReparentTimeout = 30 * time.Second
 	// candidatePrimaryTimeout is the timeout for contacting candidate primarys to decide which one to choose.
 	candidatePrimaryTimeout = 2 * time.Second
+	// interruptedReparentTimeout is how long after it started we resume a
+	// planned reparent that was interrupted. After that, we start over.
+	interruptedReparentTimeout = 10 * time.Minute
 )
 
 /*
@@ -69,17 +75,17 @@ This operates in four phases:
 
 We guarantee this invariant:
 
-- Only one tablet is marked as finished, and once it is, no other tablet will be
-  marked as finished until this tablet is deleted or the drain is aborted
-  (aborting the drain is considered an emergency situation and our invariant
-  could break here).
+  - Only one tablet is marked as finished, and once it is, no other tablet will be
+    marked as finished until this tablet is deleted or the drain is aborted
+    (aborting the drain is considered an emergency situation and our invariant
+    could break here).
 
 This has implications to these situations:
 
-- If the shard becomes unhealthy, anything marked as "finished" will stay
-  "finished".
-- If the primary is reparented to a "finished" tablet, that tablet will stay
-  "finished".
+  - If the shard becomes unhealthy, anything marked as "finished" will stay
+    "finished".
+  - If the primary is reparented to a "finished" tablet, that tablet will stay
+    "finished".
 
 These are necessary because if we ever remove the "finished" annotation we could
 then later mark something else as "finished".
@@ -150,6 +156,12 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 		pods[tabletAliasStr] = pod
 	}
 
+	// Finish a planned reparent that was interrupted, for example because
+	// the operator was restarted, before doing anything else.
+	if vts.Status.Reparent != nil {
+		return r.resumePlannedReparent(ctx, vts, wr, shard, tablets)
+	}
+
 	//
 	// 1. Check shard health.  Do not take any action if shard is unhealthy.
 	//
@@ -285,32 +297,142 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 	}
 
 	// See if there's a candidate primary for a planned reparent.
-	newPrimary := candidatePrimary(ctx, wr, shard, tablets, pods, vts.Spec.UsingExternalDatastore())
+	// Don't move the primary into a cell that's being evacuated.
+	newPrimary := candidatePrimary(ctx, wr, shard, withoutEvacuatedCells(vts.Spec.Evacuation, tablets), pods, vts.Spec.UsingExternalDatastore())
 	if newPrimary == nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning, "DrainBlocked", "unable to drain primary tablet %v: no other tablet is a suitable primary candidate", primaryAliasStr)
 		return resultBuilder.RequeueAfter(replicationRequeueDelay)
 	}
 
-	// Perform a planned reparent.
+	// A planned reparent is disruptive, so only do it inside a maintenance
+	// window. The shard's MaintenanceWindowOpen condition says when the next
+	// one opens.
+	windows, now := vts.Spec.UpdateStrategy.MaintenanceWindows, time.Now()
+	if open, _ := maintenance.Open(windows, now); !open {
+		return resultBuilder.Result()
+	}
+
+	// Give vtgate time to be ready to buffer another failover.
+	if cooldown := reparentCooldown(vts.Spec.FailoverBuffer, shard.GetPrimaryTermStartTime(), now); cooldown > 0 {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "NotReparentingPrimary", "Not draining primary tablet %v for another %v, so vtgate buffers the reparent.", primaryAliasStr, cooldown.Round(time.Second))
+		return resultBuilder.RequeueAfter(cooldown)
+	}
+
+	// Don't start a reparent that might not finish before the operator stops.
+	if shutdown.Stopping() {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "NotReparentingPrimary", "Not draining primary tablet %v while the operator is shutting down.", primaryAliasStr)
+		return resultBuilder.Result()
+	}
+
+	if err := r.plannedReparent(ctx, vts, wr, shard.PrimaryAlias, newPrimary.Alias); err != nil {
+		resultBuilder.Error(err)
+	}
+	return resultBuilder.Result()
+}
+
+/*
+plannedReparent promotes newPrimaryAlias to replace oldPrimaryAlias as the
+shard's primary.
+
+The reparent is recorded in status.reparent while it runs, so that if the
+operator stops before it finishes, resumePlannedReparent can pick it up.
+*/
+func (r *ReconcileVitessShard) plannedReparent(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, oldPrimaryAlias, newPrimaryAlias *topodatapb.TabletAlias) error {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	oldPrimaryAliasStr := topoproto.TabletAliasString(oldPrimaryAlias)
+	newPrimaryAliasStr := topoproto.TabletAliasString(newPrimaryAlias)
+
+	checkpoint := &planetscalev2.VitessShardReparentStatus{
+		FromTablet: oldPrimaryAliasStr,
+		ToTablet:   newPrimaryAliasStr,
+		StartTime:  metav1.Now(),
+	}
+	if vts.Status.Reparent != nil {
+		// Keep the original start time when resuming.
+		checkpoint.StartTime = vts.Status.Reparent.StartTime
+	}
+	if err := r.setReparentStatus(ctx, vts, checkpoint); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to record planned reparent in status: %v", err)
+		return err
+	}
+
 	reparentCtx, reparentCancel := context.WithTimeout(ctx, plannedReparentTimeout)
 	defer reparentCancel()
 
 	var reparentErr error
 	if vts.Spec.UsingExternalDatastore() {
-		reparentErr = r.handleExternalReparent(ctx, vts, wr, newPrimary.Alias, shard.PrimaryAlias)
+		reparentErr = r.handleExternalReparent(ctx, vts, wr, newPrimaryAlias, oldPrimaryAlias)
 	} else {
-		reparentErr = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimary.Alias, nil, plannedReparentTimeout)
+		reparentErr = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimaryAlias, nil, reparentWaitTimeout(vts.Spec.FailoverBuffer))
 	}
 
 	if reparentErr != nil {
-		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PlannedReparentFailed", "planned reparent from current primary %v to candidate primary %v failed: %v", primaryAliasStr, newPrimary.AliasString(), reparentErr)
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PlannedReparentFailed", "planned reparent from current primary %v to candidate primary %v failed: %v", oldPrimaryAliasStr, newPrimaryAliasStr, reparentErr)
 	} else {
-		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PlannedReparent", "planned reparent from old primary %v to new primary %v succeeded", primaryAliasStr, newPrimary.AliasString())
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PlannedReparent", "planned reparent from old primary %v to new primary %v succeeded", oldPrimaryAliasStr, newPrimaryAliasStr)
 	}
 
 	plannedReparentCount.WithLabelValues(metricLabels(vts, reparentErr)...).Inc()
 
-	return resultBuilder.Result()
+	// The outcome is known, so there's nothing to resume anymore. If this
+	// fails, the next pass finds the shard record already up to date.
+	if err := r.setReparentStatus(ctx, vts, nil); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to clear planned reparent from status: %v", err)
+		return err
+	}
+	return nil
+}
+
+// resumePlannedReparent finishes the planned reparent recorded in
+// status.reparent, which was interrupted before its outcome was recorded.
+func (r *ReconcileVitessShard) resumePlannedReparent(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, shard *topo.ShardInfo, tablets map[string]*topo.TabletInfo) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	checkpoint := vts.Status.Reparent
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+	newPrimary := tablets[checkpoint.ToTablet]
+
+	var reason string
+	switch {
+	case primaryAliasStr == checkpoint.ToTablet:
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PlannedReparent", "interrupted planned reparent from old primary %v to new primary %v had succeeded", checkpoint.FromTablet, checkpoint.ToTablet)
+	case primaryAliasStr != checkpoint.FromTablet:
+		reason = fmt.Sprintf("the primary is now %v", primaryAliasStr)
+	case newPrimary == nil:
+		reason = fmt.Sprintf("tablet %v no longer exists", checkpoint.ToTablet)
+	case time.Since(checkpoint.StartTime.Time) > interruptedReparentTimeout:
+		reason = fmt.Sprintf("it started more than %v ago", interruptedReparentTimeout)
+	case shutdown.Stopping():
+		// Leave it for the next operator Pod.
+		return resultBuilder.Result()
+	default:
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "ResumingPlannedReparent", "resuming interrupted planned reparent from primary %v to %v", checkpoint.FromTablet, checkpoint.ToTablet)
+		return resultBuilder.Error(r.plannedReparent(ctx, vts, wr, shard.PrimaryAlias, newPrimary.Alias))
+	}
+	if reason != "" {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PlannedReparentAbandoned", "not resuming interrupted planned reparent from primary %v to %v: %v", checkpoint.FromTablet, checkpoint.ToTablet, reason)
+	}
+
+	if err := r.setReparentStatus(ctx, vts, nil); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to clear planned reparent from status: %v", err)
+		return resultBuilder.Error(err)
+	}
+	// Look at the drain state again now that the reparent is settled.
+	return resultBuilder.Requeue()
+}
+
+// setReparentStatus records a planned reparent in progress in status, or
+// clears it if reparent is nil.
+func (r *ReconcileVitessShard) setReparentStatus(ctx context.Context, vts *planetscalev2.VitessShard, reparent *planetscalev2.VitessShardReparentStatus) error {
+	// Only the reparent status changes, so a merge patch from a possibly
+	// stale copy won't clobber anything the main VitessShard controller wrote.
+	obj := vts.DeepCopy()
+	patch := client.MergeFrom(vts)
+	obj.Status.Reparent = reparent
+	if err := r.client.Status().Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+	vts.Status.Reparent = reparent
+	return nil
 }
 
 func (r *ReconcileVitessShard) handleExternalReparent(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, newPrimaryAlias, oldPrimaryAlias *topodatapb.TabletAlias) error {
@@ -399,7 +521,7 @@ func candidatePrimary(ctx context.Context, wr *wrangler.Wrangler, shard *topo.Sh
 			}
 		}
 
-		if !podutils.IsPodReady(pod) {
+		if !faults.PodReady(pod) {
 			continue
 		}
 		// The Pod must not have a drain request, or have already entered the
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/mysql"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// ErrantGTIDsAnnotation is set on the Pod of a quarantined tablet to the
+// errant GTIDs that were found on it.
+const ErrantGTIDsAnnotation = planetscalev2.LabelPrefix + "/" + "errant-gtids"
+
+// errantReplica is a replica with errant GTIDs.
+type errantReplica struct {
+	name   string
+	pod    *corev1.Pod
+	tablet *topo.TabletInfo
+	gtids  mysql.Mysql56GTIDSet
+}
+
+// reconcileErrantGTIDs looks for replicas that executed transactions the
+// primary never did, and quarantines or rebuilds them.
+func (r *ReconcileVitessShard) reconcileErrantGTIDs(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	errantSpec := vts.Spec.Replication.ErrantGTIDs
+	if errantSpec == nil {
+		return resultBuilder.Result()
+	}
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer cancel()
+
+	shard, err := wr.TopoServer().GetShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		return resultBuilder.Result()
+	}
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+	tablets, err := wr.TopoServer().GetTabletMapForShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	primary := tablets[primaryAliasStr]
+	if primary == nil {
+		return resultBuilder.Result()
+	}
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+
+	// Read the replicas before the primary, so that anything a replica got
+	// from the primary is in the primary's GTID set by the time we read it.
+	allReady := true
+	var replicas []*errantReplica
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || !faults.PodReady(pod) {
+			allReady = false
+			continue
+		}
+		alias := vttablet.AliasFromPod(pod)
+		name := topoproto.TabletAliasString(&alias)
+		tablet := tablets[name]
+		if name == primaryAliasStr || tablet == nil || tablet.Type == topodatapb.TabletType_DRAINED {
+			continue
+		}
+		status, err := wr.TabletManagerClient().ReplicationStatus(ctx, tablet.Tablet)
+		if err != nil {
+			// Replication may not be set up yet.
+			continue
+		}
+		if gtids, ok := mysql56GTIDSet(status.Position); ok {
+			replicas = append(replicas, &errantReplica{name: name, pod: pod, tablet: tablet, gtids: gtids})
+		}
+	}
+	if len(replicas) == 0 {
+		return resultBuilder.Result()
+	}
+	primaryStatus, err := wr.TabletManagerClient().PrimaryStatus(ctx, primary.Tablet)
+	if err != nil {
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	primaryGTIDs, ok := mysql56GTIDSet(primaryStatus.Position)
+	if !ok {
+		return resultBuilder.Result()
+	}
+
+	// Only rebuild one tablet at a time, and not while another one might
+	// still be restoring.
+	rebuilding := !allReady || vts.Status.HasInitialBackup != corev1.ConditionTrue
+	for _, replica := range replicas {
+		replica.gtids = replica.gtids.Difference(primaryGTIDs)
+		if len(replica.gtids) == 0 {
+			continue
+		}
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ErrantGTIDs", "tablet %v has errant GTIDs %v", replica.name, replica.gtids)
+
+		pool := vts.Spec.TabletPool(replica.pod.Labels[planetscalev2.CellLabel], vttablet.PoolIDFromPod(replica.pod))
+		if errantSpec.Action == planetscalev2.RebuildErrantGTIDAction && !rebuilding && pool != nil && vts.Spec.PoolBackupLocation(pool) != nil {
+			rebuilding = true
+			if err := r.rebuildTablet(ctx, replica.pod, pool); err != nil {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "ErrantGTIDsRebuildFailed", "failed to rebuild tablet %v: %v", replica.name, err)
+				resultBuilder.Error(err)
+				continue
+			}
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "ErrantGTIDsRebuild", "Deleted tablet %v to rebuild it from backup.", replica.name)
+			continue
+		}
+		if err := r.quarantineTablet(ctx, wr, replica); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "ErrantGTIDsQuarantineFailed", "failed to quarantine tablet %v: %v", replica.name, err)
+			resultBuilder.Error(err)
+			continue
+		}
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "ErrantGTIDsQuarantine", "Changed type of tablet %v to DRAINED, so it stops serving.", replica.name)
+	}
+
+	return resultBuilder.Result()
+}
+
+// quarantineTablet stops a replica from serving, and annotates its Pod with
+// its errant GTIDs.
+func (r *ReconcileVitessShard) quarantineTablet(ctx context.Context, wr *wrangler.Wrangler, replica *errantReplica) error {
+	if err := wr.TabletManagerClient().ChangeType(ctx, replica.tablet.Tablet, topodatapb.TabletType_DRAINED, false /* semiSync */); err != nil {
+		return err
+	}
+	pod := replica.pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string, 1)
+	}
+	pod.Annotations[ErrantGTIDsAnnotation] = replica.gtids.String()
+	return r.client.Patch(ctx, pod, client.MergeFrom(replica.pod))
+}
+
+// rebuildTablet deletes the Pod and data volume of a replica, so it's
+// recreated and restores the latest backup.
+func (r *ReconcileVitessShard) rebuildTablet(ctx context.Context, pod *corev1.Pod, pool *planetscalev2.VitessShardTabletPool) error {
+	if pool.DataVolumeClaimTemplate != nil && !pool.IsScratch() {
+		// The PVC is only removed once the Pod that uses it is gone.
+		pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name}}
+		if err := r.client.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if err := r.client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// mysql56GTIDSet returns the GTID set of an encoded replication position, if
+// it's of the MySQL 5.6 flavor, which is the only one we compare.
+func mysql56GTIDSet(position string) (mysql.Mysql56GTIDSet, bool) {
+	pos, err := mysql.DecodePosition(position)
+	if err != nil {
+		return nil, false
+	}
+	gtids, ok := pos.GTIDSet.(mysql.Mysql56GTIDSet)
+	return gtids, ok
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileEvacuation moves the primary out of an evacuated cell, if there's
+// a suitable replica in another cell.
+func (r *ReconcileVitessShard) reconcileEvacuation(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	evacuation := vts.Spec.Evacuation
+	if evacuation == nil || len(evacuation.Cells) == 0 {
+		return resultBuilder.Result()
+	}
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, reconcileDrainTimeout)
+	defer cancel()
+
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() || !evacuation.Evacuating(shard.PrimaryAlias.GetCell()) {
+		return resultBuilder.Result()
+	}
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	if err := r.client.List(readCtx, podList, listOpts); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	pods := make(map[string]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		tabletAlias := vttablet.AliasFromPod(pod)
+		pods[topoproto.TabletAliasString(&tabletAlias)] = pod
+	}
+
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	newPrimary := candidatePrimary(ctx, wr, shard, withoutEvacuatedCells(evacuation, tablets), pods, vts.Spec.UsingExternalDatastore())
+	if newPrimary == nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "EvacuationBlocked", "unable to move primary tablet %v out of evacuated cell %v: no tablet in another cell is a suitable primary candidate", primaryAliasStr, shard.PrimaryAlias.GetCell())
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	if cooldown := reparentCooldown(vts.Spec.FailoverBuffer, shard.GetPrimaryTermStartTime(), time.Now()); cooldown > 0 {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "EvacuationDeferred", "Waiting %v after the last reparent so vtgate buffers the next one.", cooldown.Round(time.Second))
+		return resultBuilder.RequeueAfter(cooldown)
+	}
+
+	reparentCtx, reparentCancel := context.WithTimeout(ctx, plannedReparentTimeout)
+	defer reparentCancel()
+
+	var reparentErr error
+	if vts.Spec.UsingExternalDatastore() {
+		reparentErr = r.handleExternalReparent(reparentCtx, vts, wr, newPrimary.Alias, shard.PrimaryAlias)
+	} else {
+		reparentErr = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimary.Alias, nil, reparentWaitTimeout(vts.Spec.FailoverBuffer))
+	}
+	plannedReparentCount.WithLabelValues(metricLabels(vts, reparentErr)...).Inc()
+
+	if reparentErr != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PlannedReparentFailed", "planned reparent out of evacuated cell %v from current primary %v to candidate primary %v failed: %v", shard.PrimaryAlias.GetCell(), primaryAliasStr, newPrimary.AliasString(), reparentErr)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "PlannedReparent", "planned reparent out of evacuated cell %v from old primary %v to new primary %v succeeded", shard.PrimaryAlias.GetCell(), primaryAliasStr, newPrimary.AliasString())
+	return resultBuilder.Result()
+}
+
+// withoutEvacuatedCells returns the tablets that aren't in evacuated cells.
+func withoutEvacuatedCells(evacuation *planetscalev2.EvacuationSpec, tablets map[string]*topo.TabletInfo) map[string]*topo.TabletInfo {
+	if evacuation == nil {
+		return tablets
+	}
+	remaining := make(map[string]*topo.TabletInfo, len(tablets))
+	for tabletAliasStr, tablet := range tablets {
+		if !evacuation.Evacuating(tablet.Alias.GetCell()) {
+			remaining[tabletAliasStr] = tablet
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlusers"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+/*
+reconcileInternalCredentials rotates the passwords of the internal MySQL
+accounts of a shard when they change in their Secret.
+
+A rotation sets the new passwords on the primary, from which they replicate,
+while retaining the old ones as MySQL 8 secondary passwords. The VitessShard
+controller then restarts the tablets with the new passwords, since it follows
+the hash recorded in status. Once all tablets have restarted, the old
+passwords are discarded.
+*/
+func (r *ReconcileVitessShard) reconcileInternalCredentials(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	if !vts.Spec.ManagesInternalCredentials() {
+		return resultBuilder.Result()
+	}
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: vts.Namespace, Name: vts.Spec.InternalCredentials.SecretName}, secret); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "CredentialsReadFailed", "failed to read passwords of internal MySQL accounts: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	passwords := mysqlusers.InternalPasswords(secret.Data)
+	hash := mysqlusers.InternalPasswordsHash(passwords)
+	status := vts.Status.InternalCredentials
+
+	if status != nil && status.OldPasswordsRetainedSince != nil {
+		// A rotation is in progress. Finish it before starting another one.
+		return r.finishCredentialsRotation(ctx, vts, wr)
+	}
+	if status != nil && status.Hash == hash {
+		return resultBuilder.Result()
+	}
+
+	// Only non-empty passwords can be retained, so accounts that get a
+	// password for the first time can't be rotated without downtime.
+	var retain []string
+	if status != nil {
+		retain = status.Accounts
+	}
+	primaryAlias, err := r.executeOnPrimary(ctx, vts, wr, mysqlusers.SetInternalPasswords(passwords, retain))
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "CredentialsRotationFailed", "failed to set new passwords of internal MySQL accounts: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	newStatus := &planetscalev2.VitessShardInternalCredentialsStatus{
+		Hash:     hash,
+		Accounts: mysqlusers.InternalAccounts(passwords),
+	}
+	if len(retain) > 0 {
+		newStatus.OldPasswordsRetainedSince = &metav1.Time{Time: time.Now()}
+	}
+	if err := r.setInternalCredentialsStatus(ctx, vts, newStatus); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to record internal MySQL passwords in status: %v", err)
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "CredentialsRotated", "set new passwords of internal MySQL accounts %v on primary %v; restarting tablets to pick them up", newStatus.Accounts, primaryAlias)
+
+	return resultBuilder.RequeueAfter(replicationRequeueDelay)
+}
+
+// finishCredentialsRotation discards the old passwords of the internal MySQL
+// accounts once all tablets have restarted with the new ones.
+func (r *ReconcileVitessShard) finishCredentialsRotation(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := vts.Status.InternalCredentials
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || !faults.PodReady(pod) || pod.CreationTimestamp.Before(status.OldPasswordsRetainedSince) {
+			// Not all tablets use the new passwords yet.
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+	}
+
+	if _, err := r.executeOnPrimary(ctx, vts, wr, mysqlusers.DiscardOldInternalPasswords(status.Accounts)); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "CredentialsRotationFailed", "failed to discard old passwords of internal MySQL accounts: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	newStatus := status.DeepCopy()
+	newStatus.OldPasswordsRetainedSince = nil
+	if err := r.setInternalCredentialsStatus(ctx, vts, newStatus); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to record internal MySQL passwords in status: %v", err)
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "CredentialsRotationComplete", "discarded old passwords of internal MySQL accounts %v", status.Accounts)
+
+	// Start the next rotation, if the passwords changed again.
+	return resultBuilder.Requeue()
+}
+
+// setInternalCredentialsStatus records the state of the internal MySQL
+// passwords in status.
+func (r *ReconcileVitessShard) setInternalCredentialsStatus(ctx context.Context, vts *planetscalev2.VitessShard, credentials *planetscalev2.VitessShardInternalCredentialsStatus) error {
+	obj := vts.DeepCopy()
+	patch := client.MergeFrom(vts)
+	obj.Status.InternalCredentials = credentials
+	if err := r.client.Status().Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+	vts.Status.InternalCredentials = credentials
+	return nil
+}
+
+// executeOnPrimary runs statements on the primary tablet of the shard, with
+// binlogs enabled so they replicate, and returns the alias of the primary.
+func (r *ReconcileVitessShard) executeOnPrimary(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, statements []mysqlusers.Statement) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, applyUsersTimeout)
+	defer cancel()
+
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	shard, err := wr.TopoServer().GetShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get shard record: %v", err)
+	}
+	if !shard.HasPrimary() {
+		return "", fmt.Errorf("shard has no primary")
+	}
+	primaryAlias := topoproto.TabletAliasString(shard.PrimaryAlias)
+	primary, err := wr.TopoServer().GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		return primaryAlias, fmt.Errorf("failed to get primary tablet record: %v", err)
+	}
+	for _, statement := range statements {
+		_, err := wr.TabletManagerClient().ExecuteFetchAsDba(ctx, primary.Tablet, false /* usePool */, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query: []byte(statement.SQL),
+		})
+		if err != nil {
+			return primaryAlias, fmt.Errorf("failed to %v on primary %v: %v", statement.Description, primaryAlias, err)
+		}
+	}
+	return primaryAlias, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlusers"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+/*
+reconcileMysqldExporterUser creates the MySQL account that mysqld-exporter
+connects as on the primary, with the password that the VitessShard
+controller generated for the shard. It replicates from there to the other
+tablets.
+
+Until then, the exporter can't connect, and reports that MySQL is down.
+*/
+func (r *ReconcileVitessShard) reconcileMysqldExporterUser(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	if !vts.Spec.RunsMysqldExporter() || vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+	// A standby gets its users from the source cluster until it's promoted.
+	if vts.Spec.IsStandby() {
+		return resultBuilder.Result()
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.MysqldExporterSecretName(vts.Name)}, secret); err != nil {
+		// The VitessShard controller hasn't generated the password yet.
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	password := string(secret.Data[vttablet.MysqldExporterSecretKey])
+	hash := mysqlusers.ExporterPasswordHash(password)
+	if vts.Status.MysqldExporterCredentialsHash == hash {
+		return resultBuilder.Result()
+	}
+
+	primaryAlias, err := r.executeOnPrimary(ctx, vts, wr, mysqlusers.ExporterStatements(password))
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UsersApplyFailed", "failed to create MySQL user %v for mysqld-exporter: %v", mysqlusers.ExporterUser, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	obj := vts.DeepCopy()
+	patch := client.MergeFrom(vts)
+	obj.Status.MysqldExporterCredentialsHash = hash
+	if err := r.client.Status().Patch(ctx, obj, patch); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to record MySQL user for mysqld-exporter in status: %v", err)
+		return resultBuilder.Error(err)
+	}
+	vts.Status.MysqldExporterCredentialsHash = hash
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "ExporterUserApplied", "created MySQL user %v for mysqld-exporter on primary %v", mysqlusers.ExporterUser, primaryAlias)
+
+	return resultBuilder.Result()
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// BrokenSinceAnnotation is set on the Pod of a replica to the time it was
+// first found broken, and removed once it's healthy again.
+const BrokenSinceAnnotation = planetscalev2.LabelPrefix + "/" + "replication-broken-since"
+
+// brokenCheckTimeout is how long we wait for a replica to report its
+// replication status before we consider its MySQL unreachable.
+const brokenCheckTimeout = 5 * time.Second
+
+// reconcileBrokenReplicas rebuilds replicas that have been broken for too
+// long from backup, if enabled.
+func (r *ReconcileVitessShard) reconcileBrokenReplicas(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	if vts.Spec.Replication.RebuildBrokenReplicas == nil {
+		return resultBuilder.Result()
+	}
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer cancel()
+
+	shard, err := wr.TopoServer().GetShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		// Without a primary, replicas can't be expected to replicate.
+		return resultBuilder.Result()
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	return r.rebuildBrokenReplicas(ctx, vts, wr.TabletManagerClient(), topoproto.TabletAliasString(shard.PrimaryAlias), tablets)
+}
+
+// rebuildBrokenReplicas checks the replicas of a shard with a primary, given
+// the tablet records of the shard, and rebuilds those that have been broken
+// for too long.
+func (r *ReconcileVitessShard) rebuildBrokenReplicas(ctx context.Context, vts *planetscalev2.VitessShard, tmc tmclient.TabletManagerClient, primaryAliasStr string, tablets map[string]*topo.TabletInfo) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	rebuildSpec := vts.Spec.Replication.RebuildBrokenReplicas
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+
+	// While the primary is down or unreachable, every replica fails to
+	// replicate, but they're the candidates for a failover, so they must not
+	// be rebuilt. Forget when they broke, so they get the full brokenFor
+	// once the primary is back, to reconnect to it.
+	if err := checkPrimary(ctx, tmc, tablets[primaryAliasStr]); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "RebuildBlocked", "not checking replicas for broken replication because primary %v is unhealthy: %v", primaryAliasStr, err)
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if _, ok := pod.Annotations[BrokenSinceAnnotation]; !ok {
+				continue
+			}
+			if err := r.setBrokenSince(ctx, pod, ""); err != nil {
+				resultBuilder.Error(err)
+			}
+		}
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Tablets that are unavailable without being broken are most likely
+	// starting or restoring, possibly because we rebuilt them.
+	rebuilding := 0
+	var broken []*corev1.Pod
+	now := time.Now()
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			rebuilding++
+			continue
+		}
+		alias := vttablet.AliasFromPod(pod)
+		name := topoproto.TabletAliasString(&alias)
+		tablet := tablets[name]
+		if name == primaryAliasStr || tablet == nil {
+			continue
+		}
+		switch tablet.Type {
+		case topodatapb.TabletType_PRIMARY, topodatapb.TabletType_DRAINED, topodatapb.TabletType_BACKUP:
+			// Drained tablets are left to whoever drained them, and backups
+			// stop replication on purpose.
+			continue
+		case topodatapb.TabletType_RESTORE:
+			rebuilding++
+			continue
+		}
+		_, wasBroken := pod.Annotations[BrokenSinceAnnotation]
+
+		reason := ""
+		checkCtx, checkCancel := context.WithTimeout(ctx, brokenCheckTimeout)
+		status, err := tmc.ReplicationStatus(checkCtx, tablet.Tablet)
+		checkCancel()
+		switch {
+		case err != nil:
+			reason = fmt.Sprintf("failed to get replication status: %v", err)
+		case status.LastIoError != "":
+			reason = fmt.Sprintf("replication IO thread error: %v", status.LastIoError)
+		case status.LastSqlError != "":
+			reason = fmt.Sprintf("replication SQL thread error: %v", status.LastSqlError)
+		}
+
+		if reason == "" {
+			if !faults.PodReady(pod) {
+				rebuilding++
+			}
+			if wasBroken {
+				if err := r.setBrokenSince(ctx, pod, ""); err != nil {
+					resultBuilder.Error(err)
+				}
+				r.recorder.Eventf(vts, corev1.EventTypeNormal, "ReplicaRecovered", "tablet %v is no longer broken", name)
+			}
+			continue
+		}
+		if !wasBroken {
+			if err := r.setBrokenSince(ctx, pod, now.UTC().Format(time.RFC3339)); err != nil {
+				resultBuilder.Error(err)
+			}
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "ReplicaBroken", "tablet %v is broken: %v", name, reason)
+			continue
+		}
+		since, err := time.Parse(time.RFC3339, pod.Annotations[BrokenSinceAnnotation])
+		if err != nil {
+			// Start over if the annotation was mangled.
+			if err := r.setBrokenSince(ctx, pod, now.UTC().Format(time.RFC3339)); err != nil {
+				resultBuilder.Error(err)
+			}
+			continue
+		}
+		if now.Sub(since) < rebuildSpec.BrokenFor.Duration {
+			resultBuilder.RequeueAfter(rebuildSpec.BrokenFor.Duration - now.Sub(since))
+			continue
+		}
+		broken = append(broken, pod)
+	}
+
+	if len(broken) == 0 {
+		return resultBuilder.Result()
+	}
+	if vts.Status.HasInitialBackup != corev1.ConditionTrue {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "RebuildBlocked", "not rebuilding %v broken replica(s) because the shard has no complete backup", len(broken))
+		return resultBuilder.Result()
+	}
+	for _, pod := range broken {
+		alias := vttablet.AliasFromPod(pod)
+		name := topoproto.TabletAliasString(&alias)
+		if rebuilding >= int(*rebuildSpec.MaxConcurrent) {
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RebuildDeferred", "not rebuilding broken tablet %v yet because %v tablet(s) are already starting or restoring", name, rebuilding)
+			resultBuilder.RequeueAfter(replicationRequeueDelay)
+			break
+		}
+		pool := vts.Spec.TabletPool(pod.Labels[planetscalev2.CellLabel], vttablet.PoolIDFromPod(pod))
+		if pool == nil || vts.Spec.PoolBackupLocation(pool) == nil {
+			continue
+		}
+		if err := r.rebuildTablet(ctx, pod, pool); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "RebuildFailed", "failed to rebuild broken tablet %v: %v", name, err)
+			resultBuilder.Error(err)
+			continue
+		}
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "Rebuild", "Deleted tablet %v, which was broken since %v, to rebuild it from backup.", name, pod.Annotations[BrokenSinceAnnotation])
+		rebuilding++
+	}
+
+	return resultBuilder.Result()
+}
+
+// checkPrimary returns an error if the primary's MySQL can't report its
+// replication position, or the primary has no tablet record.
+func checkPrimary(ctx context.Context, tmc tmclient.TabletManagerClient, primary *topo.TabletInfo) error {
+	if primary == nil {
+		return fmt.Errorf("it has no tablet record")
+	}
+	ctx, cancel := context.WithTimeout(ctx, brokenCheckTimeout)
+	defer cancel()
+	if _, err := tmc.PrimaryStatus(ctx, primary.Tablet); err != nil {
+		return fmt.Errorf("failed to get primary status: %v", err)
+	}
+	return nil
+}
+
+// setBrokenSince sets the BrokenSinceAnnotation on pod to value, or removes
+// it if value is empty.
+func (r *ReconcileVitessShard) setBrokenSince(ctx context.Context, pod *corev1.Pod, value string) error {
+	patched := pod.DeepCopy()
+	if value == "" {
+		delete(patched.Annotations, BrokenSinceAnnotation)
+	} else {
+		if patched.Annotations == nil {
+			patched.Annotations = make(map[string]string, 1)
+		}
+		patched.Annotations[BrokenSinceAnnotation] = value
+	}
+	return r.client.Patch(ctx, patched, client.MergeFrom(pod))
+}
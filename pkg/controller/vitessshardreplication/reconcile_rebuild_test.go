@@ -0,0 +1,229 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// fakeTabletManagerClient reports the replication state of tablets.
+type fakeTabletManagerClient struct {
+	tmclient.TabletManagerClient
+
+	// primaryErr is returned when the primary is asked for its status.
+	primaryErr error
+	// ioErrors maps tablet aliases to the replication IO error they report.
+	ioErrors map[string]string
+}
+
+func (c *fakeTabletManagerClient) PrimaryStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.PrimaryStatus, error) {
+	if c.primaryErr != nil {
+		return nil, c.primaryErr
+	}
+	return &replicationdatapb.PrimaryStatus{}, nil
+}
+
+func (c *fakeTabletManagerClient) ReplicationStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	return &replicationdatapb.Status{LastIoError: c.ioErrors[topoproto.TabletAliasString(tablet.Alias)]}, nil
+}
+
+// rebuildShard returns a shard that rebuilds replicas broken for 30m.
+func rebuildShard() *planetscalev2.VitessShard {
+	vts := &planetscalev2.VitessShard{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "shard",
+			Labels: map[string]string{
+				planetscalev2.ClusterLabel:  "cluster",
+				planetscalev2.KeyspaceLabel: "keyspace",
+			},
+		},
+		Spec: planetscalev2.VitessShardSpec{
+			VitessShardTemplate: planetscalev2.VitessShardTemplate{
+				TabletPools: []planetscalev2.VitessShardTabletPool{
+					{Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 3},
+				},
+			},
+			BackupLocations: []planetscalev2.VitessBackupLocation{{}},
+		},
+		Status: planetscalev2.VitessShardStatus{HasInitialBackup: corev1.ConditionTrue},
+	}
+	one := int32(1)
+	vts.Spec.Replication.RebuildBrokenReplicas = &planetscalev2.VitessReplicationRebuildSpec{
+		BrokenFor:     &metav1.Duration{Duration: 30 * time.Minute},
+		MaxConcurrent: &one,
+	}
+	return vts
+}
+
+// rebuildTablet returns the Pod and tablet record of a ready tablet. If
+// brokenSince is set, the Pod is annotated as broken since then.
+func rebuildTablet(vts *planetscalev2.VitessShard, uid uint32, tabletType topodatapb.TabletType, brokenSince time.Time) (*corev1.Pod, *topo.TabletInfo) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: vts.Namespace,
+			Name:      fmt.Sprintf("tablet-%d", uid),
+			Labels: map[string]string{
+				planetscalev2.ComponentLabel:  planetscalev2.VttabletComponentName,
+				planetscalev2.ClusterLabel:    vts.Labels[planetscalev2.ClusterLabel],
+				planetscalev2.KeyspaceLabel:   vts.Labels[planetscalev2.KeyspaceLabel],
+				planetscalev2.ShardLabel:      vts.Spec.KeyRange.SafeName(),
+				planetscalev2.CellLabel:       "zone1",
+				planetscalev2.TabletUidLabel:  fmt.Sprintf("%d", uid),
+				planetscalev2.TabletTypeLabel: string(planetscalev2.ReplicaPoolType),
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	if !brokenSince.IsZero() {
+		pod.Annotations = map[string]string{BrokenSinceAnnotation: brokenSince.UTC().Format(time.RFC3339)}
+	}
+	tablet := &topo.TabletInfo{Tablet: &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+		Type:  tabletType,
+	}}
+	return pod, tablet
+}
+
+func TestRebuildBrokenReplicas(t *testing.T) {
+	const primary = "zone1-0000000100"
+	longAgo := time.Now().Add(-time.Hour)
+
+	table := []struct {
+		name       string
+		primaryErr error
+		noPrimary  bool
+		// ioErrors are the replicas that report a replication IO error.
+		ioErrors []uint32
+		// brokenSince are the replicas that were already found broken an
+		// hour ago.
+		brokenSince []uint32
+
+		wantDeleted     []uint32
+		wantBrokenSince []uint32
+	}{
+		{
+			name:            "broken long enough",
+			ioErrors:        []uint32{101},
+			brokenSince:     []uint32{101},
+			wantDeleted:     []uint32{101},
+			wantBrokenSince: []uint32{101},
+		},
+		{
+			name:            "newly broken",
+			ioErrors:        []uint32{101},
+			wantBrokenSince: []uint32{101},
+		},
+		{
+			name:        "recovered",
+			brokenSince: []uint32{101},
+		},
+		{
+			name:        "primary unreachable",
+			primaryErr:  errors.New("connection refused"),
+			ioErrors:    []uint32{101, 102},
+			brokenSince: []uint32{101, 102},
+		},
+		{
+			name:        "primary has no tablet record",
+			noPrimary:   true,
+			ioErrors:    []uint32{101, 102},
+			brokenSince: []uint32{101, 102},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			vts := rebuildShard()
+			tmc := &fakeTabletManagerClient{primaryErr: test.primaryErr, ioErrors: map[string]string{}}
+			tablets := map[string]*topo.TabletInfo{}
+			var objs []client.Object
+			for _, uid := range []uint32{100, 101, 102} {
+				tabletType := topodatapb.TabletType_REPLICA
+				if uid == 100 {
+					tabletType = topodatapb.TabletType_PRIMARY
+				}
+				var since time.Time
+				if containsUID(test.brokenSince, uid) {
+					since = longAgo
+				}
+				pod, tablet := rebuildTablet(vts, uid, tabletType, since)
+				objs = append(objs, pod)
+				alias := topoproto.TabletAliasString(tablet.Alias)
+				if uid != 100 || !test.noPrimary {
+					tablets[alias] = tablet
+				}
+				if containsUID(test.ioErrors, uid) {
+					tmc.ioErrors[alias] = "error reconnecting to source"
+				}
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := &ReconcileVitessShard{client: c, recorder: record.NewFakeRecorder(100)}
+
+			if _, err := r.rebuildBrokenReplicas(ctx, vts, tmc, primary, tablets); err != nil {
+				t.Fatalf("rebuildBrokenReplicas() error: %v", err)
+			}
+
+			for _, uid := range []uint32{100, 101, 102} {
+				pod := &corev1.Pod{}
+				err := c.Get(ctx, client.ObjectKey{Namespace: vts.Namespace, Name: fmt.Sprintf("tablet-%d", uid)}, pod)
+				deleted := apierrors.IsNotFound(err)
+				if want := containsUID(test.wantDeleted, uid); deleted != want {
+					t.Errorf("tablet %v deleted = %v; want %v", uid, deleted, want)
+				}
+				if deleted {
+					continue
+				}
+				_, brokenSince := pod.Annotations[BrokenSinceAnnotation]
+				if want := containsUID(test.wantBrokenSince, uid); brokenSince != want {
+					t.Errorf("tablet %v annotated as broken = %v; want %v", uid, brokenSince, want)
+				}
+			}
+		})
+	}
+}
+
+func containsUID(uids []uint32, uid uint32) bool {
+	for _, u := range uids {
+		if u == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlusers"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// applyUsersTimeout is how long we wait for all the statements that apply
+// the declared MySQL users.
+const applyUsersTimeout = 30 * time.Second
+
+// reconcileUsers applies the declared MySQL users to the primary, whenever
+// they or their passwords change. They replicate from there to the other
+// tablets.
+func (r *ReconcileVitessShard) reconcileUsers(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	var applied []planetscalev2.VitessMySQLUser
+	if vts.Status.Users != nil {
+		applied = vts.Status.Users.Applied
+	}
+	if len(vts.Spec.Users) == 0 && len(applied) == 0 {
+		return resultBuilder.Result()
+	}
+	if err := mysqlusers.Validate(vts.Spec.Users); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidUsers", "not applying MySQL users: %v", err)
+		return resultBuilder.Result()
+	}
+
+	passwords := make(map[string]string, len(vts.Spec.Users))
+	for i := range vts.Spec.Users {
+		user := &vts.Spec.Users[i]
+		password, err := r.readPassword(ctx, vts.Namespace, &user.PasswordSecret)
+		if err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UsersApplyFailed", "failed to read password of MySQL user %v: %v", mysqlusers.Account(user), err)
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+		passwords[mysqlusers.Account(user)] = password
+	}
+	hash := mysqlusers.Hash(vts.Spec.Users, passwords)
+	if vts.Status.Users != nil && vts.Status.Users.Hash == hash {
+		return resultBuilder.Result()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, applyUsersTimeout)
+	defer cancel()
+
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	shard, err := wr.TopoServer().GetShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		// Try again once there's a primary.
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	primary, err := wr.TopoServer().GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get primary tablet record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	for _, statement := range mysqlusers.Statements(applied, vts.Spec.Users, passwords) {
+		// Leave binlogs enabled, so the change replicates.
+		_, err := wr.TabletManagerClient().ExecuteFetchAsDba(ctx, primary.Tablet, false /* usePool */, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query: []byte(statement.SQL),
+		})
+		if err == nil {
+			continue
+		}
+		if statement.Optional {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UsersApplyFailed", "failed to %v: %v", statement.Description, err)
+			continue
+		}
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UsersApplyFailed", "failed to %v on primary %v: %v", statement.Description, topoproto.TabletAliasString(shard.PrimaryAlias), err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	status := &planetscalev2.VitessShardUsersStatus{
+		Applied:         append([]planetscalev2.VitessMySQLUser(nil), vts.Spec.Users...),
+		Hash:            hash,
+		LastAppliedTime: &metav1.Time{Time: time.Now()},
+	}
+	if err := r.setUsersStatus(ctx, vts, status); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to record applied MySQL users in status: %v", err)
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "UsersApplied", "applied %v MySQL user(s) to primary %v", len(vts.Spec.Users), topoproto.TabletAliasString(shard.PrimaryAlias))
+
+	return resultBuilder.Result()
+}
+
+// readPassword returns the value of a key in a Secret.
+func (r *ReconcileVitessShard) readPassword(ctx context.Context, namespace string, source *planetscalev2.SecretSource) (string, error) {
+	if source.Name == "" {
+		return "", fmt.Errorf("passwords can only be read from Secrets specified by name")
+	}
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: source.Name}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[source.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in Secret %v", source.Key, source.Name)
+	}
+	return string(value), nil
+}
+
+// setUsersStatus records the MySQL users that were applied in status.
+func (r *ReconcileVitessShard) setUsersStatus(ctx context.Context, vts *planetscalev2.VitessShard, users *planetscalev2.VitessShardUsersStatus) error {
+	// Only the users status changes, so a merge patch from a possibly stale
+	// copy won't clobber anything the main VitessShard controller wrote.
+	obj := vts.DeepCopy()
+	patch := client.MergeFrom(vts)
+	obj.Status.Users = users
+	if err := r.client.Status().Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+	vts.Status.Users = users
+	return nil
+}
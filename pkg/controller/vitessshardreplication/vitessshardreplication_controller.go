@@ -19,6 +19,7 @@ package vitessshardreplication
 import (
 	"context"
 	"flag"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -33,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -43,10 +45,13 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/shutdown"
 	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 )
 
 const (
@@ -84,11 +89,13 @@ func newReconciler(mgr manager.Manager) *ReconcileVitessShard {
 	recorder := mgr.GetEventRecorderFor(controllerName)
 
 	return &ReconcileVitessShard{
-		client:     c,
-		scheme:     scheme,
-		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
-		recorder:   recorder,
-		reconciler: reconciler.New(c, scheme, recorder),
+		client:         c,
+		scheme:         scheme,
+		resync:         resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder:       recorder,
+		reconciler:     reconciler.New(c, scheme, recorder),
+		backupsRunning: make(map[types.NamespacedName]bool),
+		started:        time.Now(),
 	}
 }
 
@@ -138,6 +145,15 @@ type ReconcileVitessShard struct {
 	resync     *resync.Periodic
 	recorder   record.EventRecorder
 	reconciler *reconciler.Reconciler
+
+	// backupsRunning tracks shards with a backup-now action in progress,
+	// since those outlive the reconcile pass that started them.
+	backupsMu      sync.Mutex
+	backupsRunning map[types.NamespacedName]bool
+	// started is when this process started. A backup-now action that was
+	// reported as started before then was run by another process, which
+	// has since exited or lost leadership, and its backup with it.
+	started time.Time
 }
 
 // Reconcile reads that state of the cluster for a VitessShard object and makes changes based on the state read
@@ -145,16 +161,21 @@ type ReconcileVitessShard struct {
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile.Request) (_ reconcile.Result, finalErr error) {
+	ctx, cancel := context.WithTimeout(shutdown.Context(cctx), environment.ReconcileTimeout())
 	defer cancel()
 
+	ctx, span := tracing.StartReconcile(ctx, "VitessShardReplication", request)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	resultBuilder := &results.Builder{}
 
 	log := log.WithFields(logrus.Fields{
-		"namespace":   request.Namespace,
-		"vitessshard": request.Name,
+		"namespace":              request.Namespace,
+		"vitessshard":            request.Name,
+		logging.ReconcileIDField: logging.NewReconcileID(),
 	})
+	ctx = logging.NewContext(ctx, log)
 	// Shards have a periodic reconciliation to check replication,
 	// so we log at Debug because this is noisy.
 	log.Debug("Reconciling VitessShard replication")
@@ -172,6 +193,8 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 		// Error reading the object - requeue the request.
 		return resultBuilder.Error(err)
 	}
+	log = log.WithFields(logging.ObjectFields(vts))
+	ctx = logging.NewContext(ctx, log)
 
 	// Materialize defaults
 	planetscalev2.DefaultVitessShard(vts)
@@ -182,8 +205,16 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 		return resultBuilder.Result()
 	}
 
+	// Everything this controller does is a mutation, so there's nothing to do
+	// while paused. Drains and actions that were requested in the meantime
+	// will be handled once the shard is unpaused.
+	if vts.Spec.IsPaused() {
+		r.resync.Enqueue(request.NamespacedName)
+		return resultBuilder.Result()
+	}
+
 	// Get a connection to Vitess topology for this cluster.
-	ts, err := toposerver.Open(ctx, vts.Spec.GlobalLockserver)
+	ts, err := toposerver.Open(ctx, r.client, vts.Namespace, vts.Spec.GlobalLockserver)
 	if err != nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
 		// Give the lockserver some time to come up.
@@ -199,14 +230,52 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 	// multi-step Vitess cluster management workflows.
 	wr := wrangler.New(logutil.NewConsoleLogger(), ts.Server, tmc)
 
+	// Actions on the shared state of the shard are left to one operator
+	// instance, like the topology owner of a federation.
+	manageShard := *vts.Spec.TopologyReconciliation.ManageShards
+
 	// Initialize replication if it has not already been started.
-	initReplicationResult, err := r.initReplication(ctx, vts, wr)
-	resultBuilder.Merge(initReplicationResult, err)
+	if manageShard {
+		initReplicationResult, err := r.initReplication(ctx, vts, wr)
+		resultBuilder.Merge(initReplicationResult, err)
+	}
 
 	// Check if we've been asked to do a planned reparent.
 	drainResult, err := r.reconcileDrain(ctx, vts, wr)
 	resultBuilder.Merge(drainResult, err)
 
+	// Move the primary out of any cell that's being evacuated.
+	if manageShard {
+		evacuationResult, err := r.reconcileEvacuation(ctx, vts, wr)
+		resultBuilder.Merge(evacuationResult, err)
+	}
+
+	// Quarantine or rebuild replicas with errant GTIDs, if enabled.
+	errantGTIDsResult, err := r.reconcileErrantGTIDs(ctx, vts, wr)
+	resultBuilder.Merge(errantGTIDsResult, err)
+
+	// Rebuild replicas that have been broken for too long, if enabled.
+	brokenReplicasResult, err := r.reconcileBrokenReplicas(ctx, vts, wr)
+	resultBuilder.Merge(brokenReplicasResult, err)
+
+	if manageShard {
+		// Apply the declared MySQL users to the primary.
+		usersResult, err := r.reconcileUsers(ctx, vts, wr)
+		resultBuilder.Merge(usersResult, err)
+
+		// Rotate the passwords of internal MySQL accounts if they changed.
+		credentialsResult, err := r.reconcileInternalCredentials(ctx, vts, wr)
+		resultBuilder.Merge(credentialsResult, err)
+
+		// Create the MySQL user that mysqld-exporter connects as.
+		exporterResult, err := r.reconcileMysqldExporterUser(ctx, vts, wr)
+		resultBuilder.Merge(exporterResult, err)
+	}
+
+	// Execute any actions requested with annotations.
+	actionsResult, err := r.reconcileActions(ctx, vts, wr)
+	resultBuilder.Merge(actionsResult, err)
+
 	// Request a periodic resync for the shard so we can recheck replication
 	// even if no Kubernetes events have occurred.
 	r.resync.Enqueue(request.NamespacedName)
@@ -224,6 +293,11 @@ func (r *ReconcileVitessShard) initReplication(ctx context.Context, vts *planets
 		return resultBuilder.Result()
 	}
 
+	// A standby shard doesn't get a primary until it's promoted.
+	if vts.Spec.IsStandby() {
+		return resultBuilder.Result()
+	}
+
 	// Check if we need to initialize the shard.
 	// If it's already initialized, this will be a no-op.
 	// If we are using external MySQL we will bail out early.
@@ -0,0 +1,357 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package audit records the changes the operator makes in VitessAuditRecords.
+
+Every change a controller makes is already reported in an Event on the object
+it was made for. A Log wraps the event recorders of all controllers, and for
+each Event that reports a change, as opposed to progress or failures, also
+writes a VitessAuditRecord. Unlike Events, which the API server expires
+quickly, the records are kept until the Log prunes them after the configured
+retention.
+*/
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// ActionLabel is set on each VitessAuditRecord to its action, so
+	// records can be listed by action.
+	ActionLabel = planetscalev2.LabelPrefix + "/" + "audit-action"
+
+	// queueSize is how many records can wait to be written before new ones
+	// are dropped.
+	queueSize = 1000
+	// writeTimeout is how long we wait for a record to be written.
+	writeTimeout = 10 * time.Second
+	// pruneInterval is how often records past their retention are deleted.
+	pruneInterval = time.Hour
+	// maxGenerateNameLength leaves room in record names for the random
+	// suffix that the API server appends.
+	maxGenerateNameLength = 200
+)
+
+var log = logrus.WithField("component", "audit")
+
+// actions are the Event reasons that report a change the operator made.
+// Events with other reasons, like those reporting failures or waiting, aren't
+// recorded.
+var actions = map[string]bool{
+	// Objects created, updated, recreated or deleted by any controller.
+	"Created": true,
+	"Updated": true,
+	"Deleted": true,
+	"Adopted": true,
+
+	// Tablets and replication.
+	"InitShardPrimary":            true,
+	"PlannedReparent":             true,
+	"ResumingPlannedReparent":     true,
+	"QueryServiceChanged":         true,
+	"DurabilityPolicyChanged":     true,
+	"Rebuild":                     true,
+	"ErrantGTIDsQuarantine":       true,
+	"ErrantGTIDsRebuild":          true,
+	"RestoreQueued":               true,
+	"StandbyRestore":              true,
+	"ScratchRestore":              true,
+	"TabletsReverted":             true,
+	"TabletExternallyReparented":  true,
+	"NodeLost":                    true,
+	"UsersApplied":                true,
+	"ExporterUserApplied":         true,
+	"CredentialsRotated":          true,
+	"CredentialsRotationComplete": true,
+
+	// Actions requested with annotations, or kubectl vtop.
+	"ReparentTo":       true,
+	"RestartTablet":    true,
+	"BackupNowStarted": true,
+	"DebugTablet":      true,
+
+	// Rollouts and upgrades.
+	"RolloutStarted":         true,
+	"RolloutReleased":        true,
+	"RolloutAborted":         true,
+	"MysqlUpgradeStarted":    true,
+	"MysqlUpgradeTablet":     true,
+	"MysqlUpgradeResumed":    true,
+	"MysqlUpgradeRollback":   true,
+	"MysqlUpgradeRolledBack": true,
+	"MysqlUpgradeAborted":    true,
+	"MysqlUpgradeComplete":   true,
+	"UpgradeStageStarted":    true,
+	"ImageResolved":          true,
+
+	// Topology, backups and workflows.
+	"TopoUpdated":              true,
+	"TopoCleanup":              true,
+	"TopoRebuild":              true,
+	"TopoCellAlias":            true,
+	"BackupExpired":            true,
+	"ReshardCreated":           true,
+	"ReshardTrafficSwitched":   true,
+	"ReshardCompleted":         true,
+	"MaterializeCreated":       true,
+	"LookupVindexCreated":      true,
+	"LookupVindexExternalized": true,
+}
+
+// warningActions are the Warning event reasons that report a change the
+// operator made, to undo something that went wrong.
+var warningActions = map[string]bool{
+	"ReshardRolledBack": true,
+}
+
+// Log writes and prunes VitessAuditRecords.
+type Log struct {
+	client     client.Client
+	reader     client.Reader
+	scheme     *runtime.Scheme
+	namespaces []string
+	retention  time.Duration
+	now        func() time.Time
+
+	records chan *planetscalev2.VitessAuditRecord
+}
+
+/*
+Wrap returns a manager whose event recorders also write VitessAuditRecords
+for the changes that controllers report.
+
+Records past the retention are deleted from the given namespaces, where an
+empty namespace means all namespaces. A retention of 0 means records are kept
+forever. The records are written and pruned by a runnable added to mgr, which
+only runs on the leader.
+*/
+func Wrap(mgr manager.Manager, namespaces []string, retention time.Duration) (manager.Manager, error) {
+	l := &Log{
+		client:     mgr.GetClient(),
+		reader:     mgr.GetAPIReader(),
+		scheme:     mgr.GetScheme(),
+		namespaces: namespaces,
+		retention:  retention,
+		now:        time.Now,
+		records:    make(chan *planetscalev2.VitessAuditRecord, queueSize),
+	}
+	if err := mgr.Add(l); err != nil {
+		return nil, err
+	}
+	return &auditManager{Manager: mgr, log: l}, nil
+}
+
+// auditManager wraps a manager to hand out auditing event recorders.
+type auditManager struct {
+	manager.Manager
+	log *Log
+}
+
+func (m *auditManager) GetEventRecorderFor(name string) record.EventRecorder {
+	return m.log.Recorder(m.Manager.GetEventRecorderFor(name), name)
+}
+
+// Recorder returns an event recorder that passes events on to r, and also
+// records the changes they report, as made by the given controller.
+func (l *Log) Recorder(r record.EventRecorder, controller string) record.EventRecorder {
+	return &auditRecorder{EventRecorder: r, log: l, controller: controller}
+}
+
+// auditRecorder wraps an event recorder to record changes.
+type auditRecorder struct {
+	record.EventRecorder
+	log        *Log
+	controller string
+}
+
+func (r *auditRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.log.record(object, r.controller, eventtype, reason, message)
+}
+
+func (r *auditRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.log.record(object, r.controller, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *auditRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.log.record(object, r.controller, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// record queues a record of the change an event reports, if it reports one.
+// Records are dropped rather than blocking the controller if the queue is
+// full.
+func (l *Log) record(object runtime.Object, controller, eventtype, reason, message string) {
+	switch eventtype {
+	case corev1.EventTypeNormal:
+		if !actions[reason] {
+			return
+		}
+	case corev1.EventTypeWarning:
+		if !warningActions[reason] {
+			return
+		}
+	default:
+		return
+	}
+	rec, err := l.newRecord(object, controller, reason, message)
+	if err != nil {
+		log.WithError(err).Warning("not recording change in audit log")
+		auditRecordCount.WithLabelValues(failedOutcome).Inc()
+		return
+	}
+	select {
+	case l.records <- rec:
+	default:
+		log.WithField("action", reason).Warning("audit log queue is full; dropping record")
+		auditRecordCount.WithLabelValues(droppedOutcome).Inc()
+	}
+}
+
+// newRecord returns a VitessAuditRecord for a change made on behalf of
+// object.
+func (l *Log) newRecord(object runtime.Object, controller, action, message string) (*planetscalev2.VitessAuditRecord, error) {
+	objMeta, err := meta.Accessor(object)
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := apiutil.GVKForObject(object, l.scheme)
+	if err != nil {
+		return nil, err
+	}
+	if objMeta.GetNamespace() == "" {
+		return nil, fmt.Errorf("%v %v isn't namespaced", gvk.Kind, objMeta.GetName())
+	}
+
+	labels := map[string]string{ActionLabel: action}
+	for _, key := range []string{planetscalev2.ClusterLabel, planetscalev2.CellLabel, planetscalev2.KeyspaceLabel, planetscalev2.ShardLabel} {
+		if value, ok := objMeta.GetLabels()[key]; ok {
+			labels[key] = value
+		}
+	}
+	if gvk.Kind == planetscalev2.VitessClusterKind {
+		labels[planetscalev2.ClusterLabel] = objMeta.GetName()
+	}
+
+	generateName := strings.ToLower(gvk.Kind) + "-" + objMeta.GetName()
+	if len(generateName) > maxGenerateNameLength {
+		generateName = generateName[:maxGenerateNameLength]
+	}
+
+	return &planetscalev2.VitessAuditRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    objMeta.GetNamespace(),
+			GenerateName: generateName + "-",
+			Labels:       labels,
+		},
+		Spec: planetscalev2.VitessAuditRecordSpec{
+			Time:       metav1.Time{Time: l.now()},
+			Controller: controller,
+			Object: planetscalev2.VitessAuditObjectReference{
+				Kind:      gvk.Kind,
+				Namespace: objMeta.GetNamespace(),
+				Name:      objMeta.GetName(),
+				UID:       objMeta.GetUID(),
+			},
+			Action:  action,
+			Message: message,
+		},
+	}, nil
+}
+
+// Start writes queued records, and prunes old ones, until ctx is done.
+func (l *Log) Start(ctx context.Context) error {
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+	l.prune(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case rec := <-l.records:
+			l.write(ctx, rec)
+		case <-pruneTicker.C:
+			l.prune(ctx)
+		}
+	}
+}
+
+// write creates a record.
+func (l *Log) write(ctx context.Context, rec *planetscalev2.VitessAuditRecord) {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	if err := l.client.Create(ctx, rec); err != nil {
+		log.WithError(err).WithField("action", rec.Spec.Action).Warning("failed to write audit record")
+		auditRecordCount.WithLabelValues(failedOutcome).Inc()
+		return
+	}
+	auditRecordCount.WithLabelValues(writtenOutcome).Inc()
+}
+
+// prune deletes records that are older than the retention.
+func (l *Log) prune(ctx context.Context) {
+	if l.retention <= 0 {
+		return
+	}
+	cutoff := l.now().Add(-l.retention)
+
+	for _, namespace := range l.namespaces {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(planetscalev2.SchemeGroupVersion.WithKind("VitessAuditRecordList"))
+		opts := []client.ListOption{client.InNamespace(namespace), client.Limit(500)}
+		for {
+			if err := l.reader.List(ctx, list, opts...); err != nil {
+				log.WithError(err).Warning("failed to list audit records to prune")
+				break
+			}
+			for i := range list.Items {
+				item := &list.Items[i]
+				if !item.CreationTimestamp.Time.Before(cutoff) {
+					continue
+				}
+				if err := l.client.Delete(ctx, item); client.IgnoreNotFound(err) != nil {
+					log.WithError(err).Warning("failed to prune audit record")
+					continue
+				}
+				auditRecordCount.WithLabelValues(prunedOutcome).Inc()
+			}
+			if list.Continue == "" {
+				break
+			}
+			opts = []client.ListOption{client.InNamespace(namespace), client.Limit(500), client.Continue(list.Continue)}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/shardaction"
+)
+
+func TestRecorder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := planetscalev2.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := &Log{
+		scheme:  scheme,
+		now:     func() time.Time { return now },
+		records: make(chan *planetscalev2.VitessAuditRecord, 1),
+	}
+	events := record.NewFakeRecorder(10)
+	recorder := l.Recorder(events, "vitessshard-controller")
+
+	vts := &planetscalev2.VitessShard{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "example-commerce-x-x",
+		UID:       "1234",
+		Labels: map[string]string{
+			planetscalev2.ClusterLabel:  "example",
+			planetscalev2.KeyspaceLabel: "commerce",
+			planetscalev2.ShardLabel:    "x-x",
+			"unrelated":                 "label",
+		},
+	}}
+
+	// Events that don't report changes aren't recorded.
+	recorder.Eventf(vts, corev1.EventTypeWarning, "DeleteFailed", "failed to delete Pod %v", "p1")
+	recorder.Eventf(vts, corev1.EventTypeNormal, "PVCResizeWaiting", "waiting for PVC %v", "p1")
+	if len(l.records) != 0 {
+		t.Fatalf("recorded %v event(s) that don't report changes", len(l.records))
+	}
+
+	recorder.Eventf(vts, corev1.EventTypeNormal, "Deleted", "deleted Pod %v", "p1")
+	want := &planetscalev2.VitessAuditRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    "ns",
+			GenerateName: "vitessshard-example-commerce-x-x-",
+			Labels: map[string]string{
+				ActionLabel:                 "Deleted",
+				planetscalev2.ClusterLabel:  "example",
+				planetscalev2.KeyspaceLabel: "commerce",
+				planetscalev2.ShardLabel:    "x-x",
+			},
+		},
+		Spec: planetscalev2.VitessAuditRecordSpec{
+			Time:       metav1.Time{Time: now},
+			Controller: "vitessshard-controller",
+			Object: planetscalev2.VitessAuditObjectReference{
+				Kind:      "VitessShard",
+				Namespace: "ns",
+				Name:      "example-commerce-x-x",
+				UID:       "1234",
+			},
+			Action:  "Deleted",
+			Message: "deleted Pod p1",
+		},
+	}
+	if got := <-l.records; !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("record = %#v; want %#v", got, want)
+	}
+
+	// Some Warning events report changes too.
+	recorder.Eventf(vts, corev1.EventTypeWarning, "ReshardRolledBack", "switched traffic back")
+	if got := <-l.records; got.Spec.Action != "ReshardRolledBack" {
+		t.Errorf("record action = %q; want ReshardRolledBack", got.Spec.Action)
+	}
+
+	// Records are dropped, rather than blocking, when the queue is full.
+	recorder.Event(vts, corev1.EventTypeNormal, "PlannedReparent", "reparented")
+	recorder.Event(vts, corev1.EventTypeNormal, "PlannedReparent", "reparented again")
+	if len(l.records) != 1 {
+		t.Errorf("queued %v record(s); want 1", len(l.records))
+	}
+
+	// All events are still passed on.
+	if len(events.Events) != 6 {
+		t.Errorf("passed on %v event(s); want 6", len(events.Events))
+	}
+}
+
+// notActions are the Normal event reasons that report progress or
+// observations rather than changes. Each Normal event reason must be either
+// here or in actions.
+var notActions = map[string]bool{
+	"DryRun":                 true,
+	"EvacuationDeferred":     true,
+	"InitShardWaiting":       true,
+	"NotReparentingPrimary":  true,
+	"PVCResizeWaiting":       true,
+	"RebuildDeferred":        true,
+	"ReplicaRecovered":       true,
+	"RollingRestartComplete": true,
+	"RolloutPaused":          true,
+	"TopoWaiting":            true,
+	// The start of the backup is recorded.
+	"BackupNow": true,
+}
+
+// TestEventReasonsClassified checks that every Normal event that the operator
+// reports has a reason that's either an action or listed in notActions, so
+// new changes don't silently go unrecorded.
+func TestEventReasonsClassified(t *testing.T) {
+	var actionNames []string
+	for _, action := range shardaction.Pending(allShardActions()) {
+		actionNames = append(actionNames, action.Name)
+	}
+	// dynamicReasons are the reasons that aren't string literals.
+	dynamicReasons := map[string][]string{
+		"action.Name":             actionNames,
+		`action.Name + "Started"`: {"BackupNowStarted"},
+	}
+
+	root := filepath.Join("..", "..", "..")
+	fset := token.NewFileSet()
+	reasons := map[string]string{}
+	for _, dir := range []string{"cmd", "pkg"} {
+		err := filepath.Walk(filepath.Join(root, dir), func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				for i, arg := range call.Args {
+					if types.ExprString(arg) != "corev1.EventTypeNormal" || i+1 >= len(call.Args) {
+						continue
+					}
+					pos := fset.Position(call.Pos()).String()
+					reasonExpr := call.Args[i+1]
+					if lit, ok := reasonExpr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						reason, err := strconv.Unquote(lit.Value)
+						if err != nil {
+							t.Errorf("%v: %v", pos, err)
+						}
+						reasons[reason] = pos
+						continue
+					}
+					expanded, ok := dynamicReasons[types.ExprString(reasonExpr)]
+					if !ok {
+						t.Errorf("%v: can't tell the reason of a Normal event from %v; add it to dynamicReasons", pos, types.ExprString(reasonExpr))
+					}
+					for _, reason := range expanded {
+						reasons[reason] = pos
+					}
+				}
+				return true
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(reasons) == 0 {
+		t.Fatalf("found no Normal events")
+	}
+	for reason, pos := range reasons {
+		if actions[reason] == notActions[reason] {
+			t.Errorf("%v: Normal event reason %q must be in exactly one of actions and notActions", pos, reason)
+		}
+	}
+}
+
+// allShardActions returns an object that requests every shard action.
+func allShardActions() *metav1.ObjectMeta {
+	obj := &metav1.ObjectMeta{}
+	for _, annotation := range []string{
+		shardaction.ReparentToAnnotation,
+		shardaction.RestartTabletAnnotation,
+		shardaction.BackupNowAnnotation,
+		shardaction.DebugTabletAnnotation,
+	} {
+		shardaction.Request(obj, annotation, "zone1-101")
+	}
+	return obj
+}
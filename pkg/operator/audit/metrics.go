@@ -0,0 +1,51 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+const (
+	metricsSubsystemName = "audit_record"
+
+	// writtenOutcome means the record was written.
+	writtenOutcome = "written"
+	// droppedOutcome means the record was dropped because the queue was full.
+	droppedOutcome = "dropped"
+	// failedOutcome means building or writing the record failed.
+	failedOutcome = "failed"
+	// prunedOutcome means the record was deleted after its retention.
+	prunedOutcome = "pruned"
+)
+
+var (
+	auditRecordCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "count",
+		Help:      "VitessAuditRecords handled, by outcome",
+	}, []string{"outcome"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		auditRecordCount,
+	)
+}
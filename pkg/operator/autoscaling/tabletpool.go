@@ -0,0 +1,73 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaling
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// TabletPoolSpec specifies the desired state of a VitessTabletPool.
+type TabletPoolSpec struct {
+	// Labels are set on the VitessTabletPool object itself.
+	Labels map[string]string
+	// Cell and Pool identify the pool.
+	Cell string
+	Pool string
+	// Replicas is the number of tablets in the pool.
+	Replicas int32
+	// Selector is the label selector of the pool's tablet Pods.
+	Selector string
+}
+
+// TabletPoolName returns the name of the VitessTabletPool, and the
+// VerticalPodAutoscaler, for a pool of a shard.
+func TabletPoolName(shardName, cell, pool string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, shardName, cell, pool)
+}
+
+// NewTabletPool creates a new VitessTabletPool object.
+func NewTabletPool(key client.ObjectKey, spec *TabletPoolSpec) *planetscalev2.VitessTabletPool {
+	// Fill in the immutable parts.
+	obj := &planetscalev2.VitessTabletPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+	}
+	// Set everything else.
+	UpdateTabletPool(obj, spec)
+	return obj
+}
+
+// UpdateTabletPool updates the mutable parts of a VitessTabletPool.
+//
+// Its status is only read through the scale subresource, so it's updated
+// along with the rest of the object.
+func UpdateTabletPool(obj *planetscalev2.VitessTabletPool, spec *TabletPoolSpec) {
+	update.Labels(&obj.Labels, spec.Labels)
+
+	obj.Spec.Cell = spec.Cell
+	obj.Spec.Pool = spec.Pool
+	obj.Spec.Replicas = spec.Replicas
+	obj.Status.Replicas = spec.Replicas
+	obj.Status.Selector = spec.Selector
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package autoscaling generates the objects for vertical autoscaling of tablet
+pools: a VitessTabletPool for each pool, and a VerticalPodAutoscaler that
+targets it.
+
+We don't depend on the Vertical Pod Autoscaler's Go types, so its objects are
+built and read as unstructured content, and only created when the CRD is
+installed.
+*/
+package autoscaling
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// VerticalPodAutoscalerGVK is the GroupVersionKind of the
+// VerticalPodAutoscaler.
+var VerticalPodAutoscalerGVK = schema.GroupVersionKind{
+	Group:   "autoscaling.k8s.io",
+	Version: "v1",
+	Kind:    "VerticalPodAutoscaler",
+}
+
+// VerticalPodAutoscalerSpec specifies the desired state of a
+// VerticalPodAutoscaler.
+type VerticalPodAutoscalerSpec struct {
+	// Labels are set on the VerticalPodAutoscaler object itself.
+	Labels map[string]string
+	// TargetName is the name of the VitessTabletPool to target.
+	TargetName string
+	// Containers are the names of the containers to make recommendations
+	// for. Other containers are left out.
+	Containers []string
+	// MinAllowed and MaxAllowed optionally bound the recommendations for
+	// each container.
+	MinAllowed corev1.ResourceList
+	MaxAllowed corev1.ResourceList
+}
+
+// ContainerRecommendation is the recommendation of a VerticalPodAutoscaler
+// for one container.
+type ContainerRecommendation struct {
+	// Name is the name of the container.
+	Name string
+	// Target is the recommended requests.
+	Target corev1.ResourceList
+	// LowerBound and UpperBound are the range of requests that are
+	// considered good enough.
+	LowerBound corev1.ResourceList
+	UpperBound corev1.ResourceList
+}
+
+// NewVerticalPodAutoscalerKind returns an empty VerticalPodAutoscaler object
+// that can be used as the Kind in a reconciler.Strategy.
+func NewVerticalPodAutoscalerKind() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(VerticalPodAutoscalerGVK)
+	return obj
+}
+
+// NewVerticalPodAutoscalerList returns an empty list of
+// VerticalPodAutoscaler objects.
+func NewVerticalPodAutoscalerList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(VerticalPodAutoscalerGVK.GroupVersion().WithKind(VerticalPodAutoscalerGVK.Kind + "List"))
+	return list
+}
+
+// VerticalPodAutoscalerInstalled returns whether the VerticalPodAutoscaler
+// CRD is available in the Kubernetes cluster.
+func VerticalPodAutoscalerInstalled(mapper meta.RESTMapper) (bool, error) {
+	_, err := mapper.RESTMapping(VerticalPodAutoscalerGVK.GroupKind(), VerticalPodAutoscalerGVK.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// NewVerticalPodAutoscaler creates a new VerticalPodAutoscaler object.
+func NewVerticalPodAutoscaler(key client.ObjectKey, spec *VerticalPodAutoscalerSpec) *unstructured.Unstructured {
+	// Fill in the immutable parts.
+	obj := NewVerticalPodAutoscalerKind()
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
+	// Set everything else.
+	UpdateVerticalPodAutoscaler(obj, spec)
+	return obj
+}
+
+// UpdateVerticalPodAutoscaler updates the mutable parts of a
+// VerticalPodAutoscaler.
+func UpdateVerticalPodAutoscaler(obj *unstructured.Unstructured, spec *VerticalPodAutoscalerSpec) {
+	labels := obj.GetLabels()
+	update.Labels(&labels, spec.Labels)
+	obj.SetLabels(labels)
+
+	// Everything below must only use JSON-compatible types
+	// (map[string]interface{}, []interface{}, string, int64, bool),
+	// since unstructured content is deep-copied as JSON.
+	policies := make([]interface{}, 0, len(spec.Containers)+1)
+	for _, name := range spec.Containers {
+		policy := map[string]interface{}{
+			"containerName":       name,
+			"controlledResources": []interface{}{string(corev1.ResourceCPU), string(corev1.ResourceMemory)},
+		}
+		if len(spec.MinAllowed) > 0 {
+			policy["minAllowed"] = fromResourceList(spec.MinAllowed)
+		}
+		if len(spec.MaxAllowed) > 0 {
+			policy["maxAllowed"] = fromResourceList(spec.MaxAllowed)
+		}
+		policies = append(policies, policy)
+	}
+	// Leave out sidecars, like mysqld-exporter, whose resources we set.
+	policies = append(policies, map[string]interface{}{
+		"containerName": "*",
+		"mode":          "Off",
+	})
+
+	obj.Object["spec"] = map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"apiVersion": planetscalev2.SchemeGroupVersion.String(),
+			"kind":       "VitessTabletPool",
+			"name":       spec.TargetName,
+		},
+		// The VerticalPodAutoscaler must never evict or resize tablets
+		// itself. The operator applies its recommendations, if at all.
+		"updatePolicy": map[string]interface{}{
+			"updateMode": "Off",
+		},
+		"resourcePolicy": map[string]interface{}{
+			"containerPolicies": policies,
+		},
+	}
+}
+
+// Recommendations returns the recommendations of a VerticalPodAutoscaler,
+// sorted by container name, or none if it hasn't made any yet.
+func Recommendations(obj *unstructured.Unstructured) ([]ContainerRecommendation, error) {
+	items, _, err := unstructured.NestedSlice(obj.Object, "status", "recommendation", "containerRecommendations")
+	if err != nil {
+		return nil, err
+	}
+	recommendations := make([]ContainerRecommendation, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid container recommendation: %v", item)
+		}
+		name, _, err := unstructured.NestedString(fields, "containerName")
+		if err != nil {
+			return nil, err
+		}
+		rec := ContainerRecommendation{Name: name}
+		for field, dst := range map[string]*corev1.ResourceList{
+			"target":     &rec.Target,
+			"lowerBound": &rec.LowerBound,
+			"upperBound": &rec.UpperBound,
+		} {
+			values, _, err := unstructured.NestedStringMap(fields, field)
+			if err != nil {
+				return nil, err
+			}
+			if *dst, err = toResourceList(values); err != nil {
+				return nil, fmt.Errorf("invalid %v of container %v: %v", field, name, err)
+			}
+		}
+		recommendations = append(recommendations, rec)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		return recommendations[i].Name < recommendations[j].Name
+	})
+	return recommendations, nil
+}
+
+func fromResourceList(list corev1.ResourceList) map[string]interface{} {
+	values := make(map[string]interface{}, len(list))
+	for name, quantity := range list {
+		values[string(name)] = quantity.String()
+	}
+	return values
+}
+
+func toResourceList(values map[string]string) (corev1.ResourceList, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	list := make(corev1.ResourceList, len(values))
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, err
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
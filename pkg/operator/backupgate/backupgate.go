@@ -0,0 +1,65 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package backupgate decides whether a disruptive operation on a VitessShard
+may go ahead, according to the backup disruption policy in its spec.
+
+Operations that could lose data, like deleting a tablet pool or turning
+down a shard, are held back until the shard has a complete backup that's
+newer than the policy allows. Annotating the VitessShard with
+OverrideAnnotation lets them go ahead anyway.
+*/
+package backupgate
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// OverrideAnnotation is the annotation whose presence lets disruptive
+// operations on the annotated VitessShard go ahead without a recent backup.
+const OverrideAnnotation = "planetscale.com/skip-backup-gate"
+
+// OverrideRequested returns whether the object has the override annotation.
+func OverrideRequested(obj metav1.Object) bool {
+	_, present := obj.GetAnnotations()[OverrideAnnotation]
+	return present
+}
+
+// Check returns an error explaining why disruptive operations on the shard
+// must wait, or nil if they may go ahead.
+//
+// The latest backup is taken from the shard's status, which is only as
+// fresh as the last time the shard controller looked at its backups.
+func Check(vts *planetscalev2.VitessShard, now time.Time) error {
+	policy := vts.Spec.BackupDisruptionPolicy
+	if policy == nil || OverrideRequested(vts) {
+		return nil
+	}
+	latest := vts.Status.Backup
+	if latest == nil {
+		return fmt.Errorf("shard has no complete backup; take one, or annotate the VitessShard with %v", OverrideAnnotation)
+	}
+	if age := now.Sub(latest.StartTime.Time); age > policy.MaxAge.Duration {
+		return fmt.Errorf("latest backup started %v ago, more than the allowed %v; take a new one, or annotate the VitessShard with %v", age.Round(time.Minute), policy.MaxAge.Duration, OverrideAnnotation)
+	}
+	return nil
+}
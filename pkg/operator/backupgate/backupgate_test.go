@@ -0,0 +1,60 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupgate
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	policy := &planetscalev2.VitessBackupDisruptionPolicy{MaxAge: metav1.Duration{Duration: 24 * time.Hour}}
+	backup := func(age time.Duration) *planetscalev2.VitessShardBackupStatus {
+		return &planetscalev2.VitessShardBackupStatus{StartTime: metav1.NewTime(now.Add(-age))}
+	}
+
+	table := []struct {
+		name     string
+		policy   *planetscalev2.VitessBackupDisruptionPolicy
+		override bool
+		backup   *planetscalev2.VitessShardBackupStatus
+		allowed  bool
+	}{
+		{name: "no policy", allowed: true},
+		{name: "no backup", policy: policy},
+		{name: "recent backup", policy: policy, backup: backup(time.Hour), allowed: true},
+		{name: "old backup", policy: policy, backup: backup(25 * time.Hour)},
+		{name: "override", policy: policy, override: true, allowed: true},
+	}
+
+	for _, test := range table {
+		vts := &planetscalev2.VitessShard{}
+		vts.Spec.BackupDisruptionPolicy = test.policy
+		vts.Status.Backup = test.backup
+		if test.override {
+			vts.Annotations = map[string]string{OverrideAnnotation: ""}
+		}
+		if err := Check(vts, now); (err == nil) != test.allowed {
+			t.Errorf("%v: Check() = %v; want allowed: %v", test.name, err, test.allowed)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package commonmeta propagates the common labels and annotations of a
+VitessCluster to every object created for it.
+
+Each object that gets common metadata records what it got in an annotation.
+That's how objects further down, like the tablet Pods of a VitessShard, find
+the common metadata of their owner, and how labels and annotations that are no
+longer common are told apart from those that were set for other reasons.
+*/
+package commonmeta
+
+import (
+	"encoding/json"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// Annotation records the common metadata that was applied to an object.
+const Annotation = planetscalev2.LabelPrefix + "/" + "common-metadata"
+
+// Source is implemented by objects that define common metadata for all the
+// objects created for them.
+type Source interface {
+	CommonMetadata() (labels, annotations map[string]string, identity []string)
+}
+
+// Metadata is a set of common labels and annotations.
+type Metadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Identity lists the keys of Labels and Annotations that can only be
+	// changed on a Pod by recreating it.
+	Identity []string `json:"identity,omitempty"`
+}
+
+// FromOwner returns the common metadata for the objects created for owner,
+// or nil if there is none.
+func FromOwner(owner metav1.Object) *Metadata {
+	if src, ok := owner.(Source); ok {
+		labels, annotations, identity := src.CommonMetadata()
+		return &Metadata{Labels: labels, Annotations: annotations, Identity: identity}
+	}
+	return recorded(owner)
+}
+
+// recorded returns the common metadata that was applied to obj, or nil.
+func recorded(obj metav1.Object) *Metadata {
+	value, ok := obj.GetAnnotations()[Annotation]
+	if !ok {
+		return nil
+	}
+	md := &Metadata{}
+	if err := json.Unmarshal([]byte(value), md); err != nil {
+		return nil
+	}
+	return md
+}
+
+// Create applies all of md to obj, which is about to be created.
+//
+// Note that this only mutates the provided, in-memory object; the caller is
+// responsible for sending the updated object to the server.
+func Create(obj metav1.Object, md *Metadata) {
+	update(obj, md, func(string) bool { return true })
+}
+
+// UpdateInPlace applies the changes in md to obj that can be made in place.
+// For Pods, those are changes to any keys other than identity keys.
+//
+// Note that this only mutates the provided, in-memory object; the caller is
+// responsible for sending the updated object to the server.
+func UpdateInPlace(obj metav1.Object, md *Metadata) {
+	if _, ok := obj.(*corev1.Pod); !ok {
+		update(obj, md, func(string) bool { return true })
+		return
+	}
+	identity := identityKeys(obj, md)
+	update(obj, md, func(key string) bool { return !identity.Has(key) })
+}
+
+// UpdateRecreate applies the changes in md to the identity keys of obj, if
+// it's a Pod. Those changes can only be rolled out by recreating the Pod.
+//
+// Note that this only mutates the provided, in-memory object; the caller is
+// responsible for sending the updated object to the server.
+func UpdateRecreate(obj metav1.Object, md *Metadata) {
+	if _, ok := obj.(*corev1.Pod); !ok {
+		return
+	}
+	identity := identityKeys(obj, md)
+	update(obj, md, identity.Has)
+}
+
+// identityKeys returns the identity keys of both md and the metadata that
+// was applied to obj before, so that removed keys are treated the same way
+// as changed ones.
+func identityKeys(obj metav1.Object, md *Metadata) sets.Set[string] {
+	keys := sets.New[string]()
+	if md != nil {
+		keys.Insert(md.Identity...)
+	}
+	if prev := recorded(obj); prev != nil {
+		keys.Insert(prev.Identity...)
+	}
+	return keys
+}
+
+// update applies the keys of md for which covers returns true to obj, and
+// removes those that were applied before but aren't in md anymore.
+func update(obj metav1.Object, md *Metadata, covers func(key string) bool) {
+	prev := recorded(obj)
+	if md == nil && prev == nil {
+		// Leave objects alone that have never had common metadata.
+		return
+	}
+	if md == nil {
+		md = &Metadata{}
+	}
+	if prev == nil {
+		prev = &Metadata{}
+	}
+
+	// Copy the maps, since they may be shared with the caller's spec.
+	applied := &Metadata{Identity: append([]string(nil), md.Identity...)}
+	labels := copyMap(obj.GetLabels())
+	applied.Labels = updateMap(&labels, md.Labels, prev.Labels, covers)
+	obj.SetLabels(labels)
+	annotations := copyMap(obj.GetAnnotations())
+	applied.Annotations = updateMap(&annotations, md.Annotations, prev.Annotations, covers)
+	// Remember identity keys that are no longer common, but haven't been
+	// removed yet.
+	identity := sets.New(md.Identity...)
+	for _, key := range prev.Identity {
+		_, isLabel := applied.Labels[key]
+		_, isAnnotation := applied.Annotations[key]
+		if (isLabel || isAnnotation) && !identity.Has(key) {
+			identity.Insert(key)
+			applied.Identity = append(applied.Identity, key)
+		}
+	}
+	if len(applied.Labels) == 0 && len(applied.Annotations) == 0 {
+		delete(annotations, Annotation)
+	} else {
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		value, _ := json.Marshal(applied)
+		annotations[Annotation] = string(value)
+	}
+	obj.SetAnnotations(annotations)
+
+	// The Pods that a Deployment creates get common metadata too. Changes to
+	// the Pod template are rolled out by the Deployment.
+	if deployment, ok := obj.(*appsv1.Deployment); ok {
+		update(&deployment.Spec.Template.ObjectMeta, md, func(string) bool { return true })
+	}
+}
+
+// updateMap applies the covered keys of want to cur, removes the covered keys
+// of prev that aren't in want, and returns the entries of want and prev that
+// end up applied.
+func updateMap(cur *map[string]string, want, prev map[string]string, covers func(key string) bool) map[string]string {
+	applied := make(map[string]string, len(want))
+	for key, value := range prev {
+		if !covers(key) {
+			// Keep what was applied before until this key is covered.
+			applied[key] = value
+			continue
+		}
+		if _, ok := want[key]; !ok && *cur != nil {
+			delete(*cur, key)
+		}
+	}
+	for key, value := range want {
+		if !covers(key) {
+			continue
+		}
+		if _, ok := (*cur)[key]; ok {
+			if _, ours := prev[key]; !ours {
+				// The object has this key for another reason, which takes
+				// precedence.
+				continue
+			}
+		}
+		if *cur == nil {
+			*cur = make(map[string]string, len(want))
+		}
+		(*cur)[key] = value
+		applied[key] = value
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+	return applied
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commonmeta
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestPropagation(t *testing.T) {
+	vt := &planetscalev2.VitessCluster{Spec: planetscalev2.VitessClusterSpec{
+		CommonLabels:      map[string]string{"cost-center": "42", "sidecar.istio.io/inject": "true"},
+		CommonAnnotations: map[string]string{"owner": "payments"},
+		CommonLabelPolicy: &planetscalev2.CommonLabelPolicy{Identity: []string{"sidecar.istio.io/inject"}},
+	}}
+
+	// A child VitessShard gets the metadata, and passes it on to its Pods.
+	vts := &planetscalev2.VitessShard{}
+	Create(vts, FromOwner(vt))
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cost-center": "pool"}}}
+	Create(pod, FromOwner(vts))
+
+	wantLabels := map[string]string{"cost-center": "pool", "sidecar.istio.io/inject": "true"}
+	if !apiequality.Semantic.DeepEqual(pod.Labels, wantLabels) {
+		t.Errorf("Create() labels = %v; want %v", pod.Labels, wantLabels)
+	}
+	if got := pod.Annotations["owner"]; got != "payments" {
+		t.Errorf("Create() annotation owner = %q; want %q", got, "payments")
+	}
+
+	// Changes to identity keys only reach Pods when they're recreated.
+	vt.Spec.CommonLabels = map[string]string{"sidecar.istio.io/inject": "false", "team": "db"}
+	Create(vts, FromOwner(vt))
+	UpdateInPlace(pod, FromOwner(vts))
+	wantLabels = map[string]string{"cost-center": "pool", "sidecar.istio.io/inject": "true", "team": "db"}
+	if !apiequality.Semantic.DeepEqual(pod.Labels, wantLabels) {
+		t.Errorf("UpdateInPlace() labels = %v; want %v", pod.Labels, wantLabels)
+	}
+	UpdateRecreate(pod, FromOwner(vts))
+	wantLabels["sidecar.istio.io/inject"] = "false"
+	if !apiequality.Semantic.DeepEqual(pod.Labels, wantLabels) {
+		t.Errorf("UpdateRecreate() labels = %v; want %v", pod.Labels, wantLabels)
+	}
+
+	// Other objects get every change in place, including their Pod templates.
+	deployment := &appsv1.Deployment{}
+	UpdateInPlace(deployment, nil)
+	if deployment.Labels != nil || deployment.Annotations != nil {
+		t.Errorf("UpdateInPlace() changed an object without common metadata")
+	}
+	UpdateInPlace(deployment, FromOwner(vts))
+	if got := deployment.Spec.Template.Labels["sidecar.istio.io/inject"]; got != "false" {
+		t.Errorf("UpdateInPlace() Pod template label = %q; want %q", got, "false")
+	}
+
+	// Keys that are no longer common are removed, but only if they were ours.
+	vt.Spec.CommonLabels = nil
+	vt.Spec.CommonAnnotations = nil
+	Create(vts, FromOwner(vt))
+	UpdateInPlace(pod, FromOwner(vts))
+	UpdateRecreate(pod, FromOwner(vts))
+	wantLabels = map[string]string{"cost-center": "pool"}
+	if !apiequality.Semantic.DeepEqual(pod.Labels, wantLabels) {
+		t.Errorf("labels after removal = %v; want %v", pod.Labels, wantLabels)
+	}
+	if len(pod.Annotations) != 0 {
+		t.Errorf("annotations after removal = %v; want none", pod.Annotations)
+	}
+}
@@ -2,16 +2,27 @@ package controllermanager
 
 import (
 	"fmt"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/planetscale/operator-sdk-libs/pkg/k8sutil"
+
+	planetscalev3 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v3"
 	"planetscale.dev/vitess-operator/pkg/controller"
 	vbssubcontroller "planetscale.dev/vitess-operator/pkg/controller/vitessbackupstorage/subcontroller"
+	"planetscale.dev/vitess-operator/pkg/operator/audit"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/follower"
+	"planetscale.dev/vitess-operator/pkg/operator/notify"
+	"planetscale.dev/vitess-operator/pkg/operator/scope"
 )
 
 var log = logf.Log.WithName("controller-manager")
@@ -24,12 +35,60 @@ func New(forkPath string, cfg *rest.Config, opts manager.Options) (manager.Manag
 		return nil, err
 	}
 
+	// Only watch the VitessClusters selected by --cluster_label_selector, and
+	// the objects created for them.
+	if err := scope.SetSelector(environment.ClusterLabelSelector()); err != nil {
+		return nil, fmt.Errorf("invalid --cluster_label_selector: %v", err)
+	}
+	if selectors := scope.CacheSelectors(); selectors != nil {
+		newCache := opts.NewCache
+		if newCache == nil {
+			newCache = cache.New
+		}
+		opts.NewCache = func(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error) {
+			cacheOpts.SelectorsByObject = selectors
+			return newCache(config, cacheOpts)
+		}
+	}
+
+	// Only the root process serves the conversion webhook.
+	if forkPath == "" && environment.WebhookCertDir() != "" {
+		opts.CertDir = environment.WebhookCertDir()
+		opts.Port = environment.WebhookPort()
+	}
+
 	// Create a new manager to provide shared dependencies and start components
 	mgr, err := manager.New(cfg, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	// Let replicas that aren't the leader keep status fresh, if requested.
+	if opts.LeaderElection && environment.LeaderElectionConfig().FollowerStatusUpdates {
+		follower.Enable(mgr.Elected())
+	}
+
+	// Record the changes that controllers make, if requested. Only the root
+	// process makes changes worth auditing.
+	if forkPath == "" && environment.AuditLog() {
+		namespace, err := k8sutil.GetWatchNamespace()
+		if err != nil {
+			return nil, err
+		}
+		mgr, err = audit.Wrap(mgr, strings.Split(namespace, ","), environment.AuditLogRetention())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Notify a webhook of significant events, if requested.
+	if forkPath == "" && environment.NotificationWebhookURL() != "" {
+		mgr, err = notify.Wrap(mgr, environment.NotificationWebhookURL(), environment.NotificationFormat(), environment.NotificationReasons())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	log.Info("Registering Components.")
 
 	// We use the fork path primarily to decide which controllers to run in this
@@ -41,6 +100,12 @@ func New(forkPath string, cfg *rest.Config, opts manager.Options) (manager.Manag
 		if err := controller.AddToManager(mgr); err != nil {
 			return nil, err
 		}
+		// Convert VitessClusters between the v2 and v3 APIs.
+		if opts.CertDir != "" {
+			if err := builder.WebhookManagedBy(mgr).For(&planetscalev3.VitessCluster{}).Complete(); err != nil {
+				return nil, err
+			}
+		}
 	case vbssubcontroller.ForkPath:
 		// Run only the vitessbackupstorage subcontroller.
 		if err := vbssubcontroller.Add(mgr); err != nil {
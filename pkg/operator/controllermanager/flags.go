@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 )
 
 func InitFlags() {
@@ -42,6 +43,11 @@ func InitFlags() {
 		"s3_backup_storage_root":         false,
 		"s3_backup_force_path_style":     false,
 		"s3_backup_aws_endpoint":         false,
+		"topo_zk_tls_cert":               false,
+		"topo_zk_tls_key":                false,
+		"topo_zk_tls_ca":                 false,
+		"topo_zk_auth_file":              false,
+		"consul_auth_static_file":        false,
 	}
 
 	vtbackupFlags.VisitAll(func(f *pflag.Flag) {
@@ -65,6 +71,12 @@ func InitFlags() {
 
 	pflag.Parse()
 
+	// Configure the logrus logger used by our controllers.
+	if err := logging.Init(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	// Initialize flags for klog, which is necessary to configure logging from
 	// the low-level k8s client libraries. We don't use glog ourselves, but we
 	// have dependencies that use it, so we have to follow the instructions for
@@ -270,7 +270,8 @@ The workflow we are using now is:
 
 - The administrator annotates tablets that should be drained as "draining".
 - The operator annotates one and only one tablet as "finished".
-	- Only if other health checks pass and the tablet is not a master.
+  - Only if other health checks pass and the tablet is not a master.
+
 - The administrator can safely delete the tablet annotated as "finished".
 
 It is actually non trivial to ensure that one and only one tablet will be
@@ -27,10 +27,71 @@ import (
 	"github.com/spf13/pflag"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/notify"
 )
 
+const (
+	// LeaderForLifeMode is the leader election mode in which the leader
+	// holds a lock until its Pod is deleted.
+	LeaderForLifeMode = "for-life"
+	// LeaseMode is the leader election mode in which the leader holds a
+	// Lease that it must keep renewing, and can hand over on shutdown.
+	LeaseMode = "lease"
+)
+
+// LeaderElection configures how the operator elects a leader.
+type LeaderElection struct {
+	// Mode is LeaderForLifeMode or LeaseMode.
+	Mode string
+	// LeaseDuration is how long other candidates wait after the Lease was
+	// last renewed before taking it over, in LeaseMode.
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader keeps trying to renew the Lease
+	// before giving up leadership, in LeaseMode.
+	RenewDeadline time.Duration
+	// RetryPeriod is how often candidates try to acquire or renew the Lease,
+	// in LeaseMode.
+	RetryPeriod time.Duration
+	// ReleaseOnShutdown is whether the leader releases the Lease when it
+	// shuts down cleanly, so another candidate can take over right away,
+	// in LeaseMode.
+	ReleaseOnShutdown bool
+	// FollowerStatusUpdates is whether replicas that aren't the leader keep
+	// updating the status of objects, in LeaseMode.
+	FollowerStatusUpdates bool
+}
+
+// Tracing configures how the operator exports traces.
+type Tracing struct {
+	// OTLPEndpoint is the host:port of the OpenTelemetry collector to send
+	// spans to over OTLP/gRPC, or "" to disable tracing.
+	OTLPEndpoint string
+	// OTLPInsecure is whether to connect to the collector without TLS.
+	OTLPInsecure bool
+	// SampleRatio is the fraction of reconciles to trace.
+	SampleRatio float64
+}
+
 var (
-	reconcileTimeout time.Duration
+	reconcileTimeout       time.Duration
+	leaderElection         LeaderElection
+	shutdownTimeout        time.Duration
+	statusUpdateInterval   time.Duration
+	clusterLabelSelector   string
+	serverSideApply        bool
+	webhookCertDir         string
+	webhookPort            int
+	debugContainerDuration time.Duration
+	auditLog               bool
+	auditLogRetention      time.Duration
+	notificationWebhookURL string
+	notificationFormat     string
+	notificationReasons    []string
+	healthProbePort        int
+	wedgedThreshold        time.Duration
+	federationMember       string
+	inPlacePodResize       bool
+	tracing                Tracing
 )
 
 // FlagSet returns the FlagSet for the operator.
@@ -38,6 +99,15 @@ func FlagSet() *pflag.FlagSet {
 	operatorFlagSet := pflag.NewFlagSet("operator", pflag.ExitOnError)
 
 	operatorFlagSet.DurationVar(&reconcileTimeout, "reconcile_timeout", 10*time.Minute, "Maximum time that any controller will spend trying to reconcile a single object before giving up.")
+	operatorFlagSet.DurationVar(&shutdownTimeout, "shutdown_timeout", 25*time.Second, "Maximum time to let in-flight reconciles finish after the operator receives SIGTERM. It should be shorter than the operator Pod's termination grace period.")
+	operatorFlagSet.DurationVar(&statusUpdateInterval, "status_update_interval", 2*time.Second, "Minimum time between status writes to the same object, so a burst of reconciles results in one write of the latest status. Writes reporting a new observedGeneration, or new decisions that later reconciles act on, aren't delayed. A value of 0 means write every change right away.")
+
+	operatorFlagSet.StringVar(&leaderElection.Mode, "leader_election_mode", LeaderForLifeMode, "How the operator elects a leader: 'for-life' holds a lock until the leader's Pod is deleted; 'lease' holds a Lease that's handed over as soon as the leader stops. Switching modes requires stopping the old operator Pod before starting the new one.")
+	operatorFlagSet.DurationVar(&leaderElection.LeaseDuration, "leader_election_lease_duration", 15*time.Second, "How long other candidates wait after the leader last renewed its Lease before taking over, with leader_election_mode=lease.")
+	operatorFlagSet.DurationVar(&leaderElection.RenewDeadline, "leader_election_renew_deadline", 10*time.Second, "How long the leader keeps trying to renew its Lease before giving up leadership, with leader_election_mode=lease.")
+	operatorFlagSet.DurationVar(&leaderElection.RetryPeriod, "leader_election_retry_period", 2*time.Second, "How often candidates try to acquire or renew the Lease, with leader_election_mode=lease.")
+	operatorFlagSet.BoolVar(&leaderElection.ReleaseOnShutdown, "leader_election_release_on_shutdown", true, "Whether the leader releases its Lease when it shuts down cleanly, so a new operator Pod takes over right away, with leader_election_mode=lease.")
+	operatorFlagSet.BoolVar(&leaderElection.FollowerStatusUpdates, "leader_election_follower_status_updates", false, "Whether operator Pods that aren't the leader keep updating the status of VitessClusters, VitessCells, VitessShards and EtcdLockservers without changing anything else, so status stays fresh while leadership changes hands. Requires leader_election_mode=lease.")
 
 	operatorFlagSet.StringVar(&planetscalev2.DefaultVitessPriorityClass, "default_vitess_priority_class", planetscalev2.DefaultVitessPriorityClass, "Default PriorityClass to use for Pods that run Vitess components. An empty value means don't use any PriorityClass.")
 	operatorFlagSet.StringVar(&planetscalev2.DefaultVitessServiceAccount, "default_vitess_service_account", planetscalev2.DefaultVitessServiceAccount, "Default ServiceAccount to use for Pods that run Vitess components. An empty value means let Kubernetes fill in a default.")
@@ -50,6 +120,35 @@ func FlagSet() *pflag.FlagSet {
 
 	operatorFlagSet.StringVar(&planetscalev2.DefaultEtcdImage, "default_etcd_image", planetscalev2.DefaultEtcdImage, "Default etcd image to use when not specified in the CRD.")
 	operatorFlagSet.StringVar(&planetscalev2.DefaultImages.MysqldExporter, "default_mysqld_exporter_image", planetscalev2.DefaultImages.MysqldExporter, "Default mysqld-exporter image to use when not specified in the CRD.")
+	operatorFlagSet.StringVar(&planetscalev2.DefaultImages.Init, "default_init_image", planetscalev2.DefaultImages.Init, "Default image to use for the init containers of vttablet and vtbackup Pods when not specified in the CRD. An empty value means use the vttablet or vtbackup image.")
+	operatorFlagSet.StringVar(&planetscalev2.DefaultImages.Debug, "default_debug_image", planetscalev2.DefaultImages.Debug, "Default image to run in debug containers added to tablet Pods when not specified in the CRD. An empty value means use the vttablet image.")
+
+	operatorFlagSet.StringVar(&clusterLabelSelector, "cluster_label_selector", "", "Label selector for the VitessClusters this operator manages, for example 'team=payments'. Only the selected VitessClusters, and the objects created for them, are watched and reconciled. An empty value means all VitessClusters.")
+
+	operatorFlagSet.BoolVar(&serverSideApply, "server_side_apply", false, "Whether to update the objects the operator manages with server-side apply, which only sets the fields the operator owns, leaving alone fields added by users or webhooks, and reports conflicting changes in events. If false, objects are updated by replacing them.")
+
+	operatorFlagSet.StringVar(&webhookCertDir, "webhook_cert_dir", "", "Directory with the tls.crt and tls.key files the conversion webhook serves with. An empty value means don't run the conversion webhook, so only the v2 API can be used.")
+	operatorFlagSet.IntVar(&webhookPort, "webhook_port", 9443, "Port that the conversion webhook listens on, if webhook_cert_dir is set.")
+
+	operatorFlagSet.DurationVar(&debugContainerDuration, "debug_container_duration", time.Hour, "How long debug containers added to tablet Pods run before they exit.")
+
+	operatorFlagSet.BoolVar(&auditLog, "audit_log", false, "Whether to record each change the operator makes, like recreating a Pod or reparenting a shard, in a VitessAuditRecord next to the object the change was made for. Requires the VitessAuditRecord CRD.")
+	operatorFlagSet.DurationVar(&auditLogRetention, "audit_log_retention", 30*24*time.Hour, "How long to keep VitessAuditRecords before deleting them, with audit_log enabled. A value of 0 means keep them forever.")
+
+	operatorFlagSet.StringVar(&notificationWebhookURL, "notification_webhook_url", "", "URL to post notifications to for significant events, like a shard losing its primary or a backup failing. To keep the URL secret, pass it as $(ENV_VAR) with an environment variable set from a Secret. An empty value means don't send notifications.")
+	operatorFlagSet.StringVar(&notificationFormat, "notification_format", "slack", "Format of the notifications posted to notification_webhook_url: 'slack' posts Slack-compatible messages with a 'text' field; 'json' posts the details of the event as JSON.")
+	operatorFlagSet.StringSliceVar(&notificationReasons, "notification_reasons", notify.DefaultReasons, "Reasons of the events that trigger notifications.")
+
+	operatorFlagSet.IntVar(&healthProbePort, "health_probe_port", 8081, "Port that serves the /healthz and /readyz probes, which fail while any controller is wedged. A value of 0 means don't serve probes.")
+	operatorFlagSet.DurationVar(&wedgedThreshold, "wedged_threshold", 30*time.Minute, "How long a controller may go without making progress, either because a reconcile is still running or because queued objects aren't being reconciled, before the health probes fail. It should be longer than reconcile_timeout.")
+
+	operatorFlagSet.StringVar(&federationMember, "federation_member", "", "Name of the member this operator is in VitessClusterFederations, which determines the cells it deploys. An empty value means this operator doesn't deploy any VitessClusterFederations.")
+
+	operatorFlagSet.BoolVar(&inPlacePodResize, "in_place_pod_resize", true, "Whether to resize the containers of tablet Pods in place when their CPU or memory changes, instead of recreating the Pods, if the Kubernetes API server has the resize subresource of Pods, which is checked at startup. That takes Kubernetes 1.33 or later with the InPlacePodVerticalScaling feature gate, which is on by default. Pods that Kubernetes refuses to resize are recreated.")
+
+	operatorFlagSet.StringVar(&tracing.OTLPEndpoint, "otlp_traces_endpoint", "", "host:port of an OpenTelemetry collector to send traces of reconciles, and of the topology and Vitess RPCs they make, to over OTLP/gRPC. The standard OTEL_EXPORTER_OTLP_* environment variables set headers and certificates. An empty value means don't trace.")
+	operatorFlagSet.BoolVar(&tracing.OTLPInsecure, "otlp_traces_insecure", false, "Whether to connect to otlp_traces_endpoint without TLS.")
+	operatorFlagSet.Float64Var(&tracing.SampleRatio, "traces_sample_ratio", 1, "Fraction of reconciles to trace, from 0 to 1, with otlp_traces_endpoint set.")
 
 	return operatorFlagSet
 }
@@ -58,3 +157,107 @@ func FlagSet() *pflag.FlagSet {
 func ReconcileTimeout() time.Duration {
 	return reconcileTimeout
 }
+
+// ShutdownTimeout returns how long in-flight reconciles may keep running after
+// the operator is asked to stop.
+func ShutdownTimeout() time.Duration {
+	return shutdownTimeout
+}
+
+// StatusUpdateInterval returns the minimum time between status writes to the
+// same object.
+func StatusUpdateInterval() time.Duration {
+	return statusUpdateInterval
+}
+
+// ClusterLabelSelector returns the label selector for the VitessClusters this
+// operator manages, or "" for all of them.
+func ClusterLabelSelector() string {
+	return clusterLabelSelector
+}
+
+// ServerSideApply returns whether to update managed objects with server-side
+// apply.
+func ServerSideApply() bool {
+	return serverSideApply
+}
+
+// LeaderElectionConfig returns how the operator elects a leader.
+func LeaderElectionConfig() LeaderElection {
+	return leaderElection
+}
+
+// WebhookCertDir returns the directory with the conversion webhook's serving
+// certificate, or "" if the conversion webhook is disabled.
+func WebhookCertDir() string {
+	return webhookCertDir
+}
+
+// WebhookPort returns the port that the conversion webhook listens on.
+func WebhookPort() int {
+	return webhookPort
+}
+
+// DebugContainerDuration returns how long tablet debug containers run.
+func DebugContainerDuration() time.Duration {
+	return debugContainerDuration
+}
+
+// AuditLog returns whether to record the changes the operator makes in
+// VitessAuditRecords.
+func AuditLog() bool {
+	return auditLog
+}
+
+// AuditLogRetention returns how long to keep VitessAuditRecords, or 0 to keep
+// them forever.
+func AuditLogRetention() time.Duration {
+	return auditLogRetention
+}
+
+// NotificationWebhookURL returns the URL to post notifications to, or "" if
+// notifications are disabled.
+func NotificationWebhookURL() string {
+	return notificationWebhookURL
+}
+
+// NotificationFormat returns the format of notifications.
+func NotificationFormat() string {
+	return notificationFormat
+}
+
+// NotificationReasons returns the reasons of the events that trigger
+// notifications.
+func NotificationReasons() []string {
+	return notificationReasons
+}
+
+// HealthProbePort returns the port that serves the health probes, or 0 if
+// they're disabled.
+func HealthProbePort() int {
+	return healthProbePort
+}
+
+// WedgedThreshold returns how long a controller may go without making
+// progress before the health probes fail.
+func WedgedThreshold() time.Duration {
+	return wedgedThreshold
+}
+
+// FederationMember returns the name of the member this operator is in
+// VitessClusterFederations, or "" if it isn't a member.
+func FederationMember() string {
+	return federationMember
+}
+
+// InPlacePodResize returns whether to resize tablet Pods in place instead of
+// recreating them when only their resources change, if Kubernetes supports
+// it.
+func InPlacePodResize() bool {
+	return inPlacePodResize
+}
+
+// TracingConfig returns how the operator exports traces.
+func TracingConfig() Tracing {
+	return tracing
+}
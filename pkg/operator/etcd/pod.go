@@ -87,6 +87,7 @@ type Spec struct {
 	ExtraLabels       map[string]string
 	AdvertisePeerURLs []string
 	Tolerations       []corev1.Toleration
+	ResourceDefaults  *planetscalev2.ResourceDefaultsSpec
 }
 
 // NewPod creates a new etcd Pod.
@@ -308,10 +309,11 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	}
 
 	// Make a final list of desired containers and init containers before merging.
-	initContainers := spec.InitContainers
-	containers := []corev1.Container{
+	initContainers := update.ContainerResourceDefaults(spec.InitContainers, spec.ResourceDefaults)
+	sidecarContainers := update.ContainerResourceDefaults(spec.SidecarContainers, spec.ResourceDefaults)
+	containers := update.ContainerResourceDefaults([]corev1.Container{
 		*etcdContainer,
-	}
+	}, spec.ResourceDefaults)
 
 	// Record hashes of desired label and annotation keys to force the Pod
 	// to be recreated if a key disappears from the desired list.
@@ -335,11 +337,11 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	})
 
 	// Inject init containers from spec.
-	update.PodContainers(&obj.Spec.InitContainers, spec.InitContainers)
+	update.PodContainers(&obj.Spec.InitContainers, initContainers)
 
 	// Update sidecar containers we care about in the Pod template,
 	// ignoring other containers that may have been injected.
-	update.PodContainers(&obj.Spec.Containers, spec.SidecarContainers)
+	update.PodContainers(&obj.Spec.Containers, sidecarContainers)
 
 	// Update the containers we care about in the Pod template,
 	// ignoring other containers that may have been injected.
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package externaldns publishes DNS names for Services through external-dns,
+by setting the annotations that external-dns watches for.
+*/
+package externaldns
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// HostnameAnnotation is the annotation from which external-dns reads the
+	// hostnames to publish for a Service.
+	HostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	// TTLAnnotation is the annotation from which external-dns reads the TTL
+	// of the records it publishes for a Service.
+	TTLAnnotation = "external-dns.alpha.kubernetes.io/ttl"
+)
+
+// VtgateHostname returns the hostname to publish for the global vtgate
+// Service, if any.
+func VtgateHostname(config *planetscalev2.ExternalDNSConfig, clusterName, namespace string) string {
+	if config == nil {
+		return ""
+	}
+	return hostname(config.VtgateHostname, clusterName, namespace, "")
+}
+
+// VtgateCellHostname returns the hostname to publish for a cell's vtgate
+// Service, if any.
+func VtgateCellHostname(config *planetscalev2.ExternalDNSConfig, clusterName, namespace, cellName string) string {
+	if config == nil {
+		return ""
+	}
+	return hostname(config.VtgateCellHostname, clusterName, namespace, cellName)
+}
+
+// VtctldHostname returns the hostname to publish for the vtctld Service,
+// if any.
+func VtctldHostname(config *planetscalev2.ExternalDNSConfig, clusterName, namespace string) string {
+	if config == nil {
+		return ""
+	}
+	return hostname(config.VtctldHostname, clusterName, namespace, "")
+}
+
+// hostname fills in the placeholders of a hostname template.
+func hostname(template, clusterName, namespace, cellName string) string {
+	return strings.NewReplacer(
+		"{cluster}", clusterName,
+		"{namespace}", namespace,
+		"{cell}", cellName,
+	).Replace(template)
+}
+
+// UpdateService sets the external-dns annotations of a Service to publish
+// the given hostname, or removes them if the hostname is empty.
+//
+// If config is nil, the annotations are left alone, so they can still be
+// managed through Service overrides.
+func UpdateService(svc *corev1.Service, config *planetscalev2.ExternalDNSConfig, hostname string) {
+	if config == nil {
+		return
+	}
+	if hostname == "" {
+		delete(svc.Annotations, HostnameAnnotation)
+		delete(svc.Annotations, TTLAnnotation)
+		return
+	}
+	if svc.Annotations == nil {
+		svc.Annotations = map[string]string{}
+	}
+	svc.Annotations[HostnameAnnotation] = hostname
+	if config.TTL != nil {
+		svc.Annotations[TTLAnnotation] = strconv.Itoa(int(*config.TTL))
+	} else {
+		delete(svc.Annotations, TTLAnnotation)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externaldns
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestVtgateCellHostname(t *testing.T) {
+	config := &planetscalev2.ExternalDNSConfig{VtgateCellHostname: "vtgate-{cell}.{cluster}.{namespace}.example.com"}
+	if got, want := VtgateCellHostname(config, "example", "default", "zone1"), "vtgate-zone1.example.default.example.com"; got != want {
+		t.Errorf("VtgateCellHostname() = %q; want %q", got, want)
+	}
+	if got := VtgateCellHostname(nil, "example", "default", "zone1"); got != "" {
+		t.Errorf("VtgateCellHostname(nil) = %q; want empty", got)
+	}
+}
+
+func TestUpdateService(t *testing.T) {
+	svc := &corev1.Service{}
+	config := &planetscalev2.ExternalDNSConfig{TTL: pointer.Int32Ptr(60)}
+
+	UpdateService(svc, config, "vtgate.example.com")
+	if svc.Annotations[HostnameAnnotation] != "vtgate.example.com" || svc.Annotations[TTLAnnotation] != "60" {
+		t.Errorf("UpdateService() annotations = %v; want hostname and TTL", svc.Annotations)
+	}
+
+	UpdateService(svc, nil, "")
+	if _, ok := svc.Annotations[HostnameAnnotation]; !ok {
+		t.Errorf("UpdateService() with nil config removed annotations; want them left alone")
+	}
+
+	UpdateService(svc, config, "")
+	if len(svc.Annotations) != 0 {
+		t.Errorf("UpdateService() with empty hostname annotations = %v; want none", svc.Annotations)
+	}
+}
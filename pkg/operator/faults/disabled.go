@@ -0,0 +1,52 @@
+//go:build !faults
+// +build !faults
+
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Enabled is whether fault injection was compiled in.
+const Enabled = false
+
+// Register does nothing, since fault injection wasn't compiled in.
+func Register(manager.Manager) error {
+	return nil
+}
+
+// DelayTopo returns right away, since fault injection wasn't compiled in.
+func DelayTopo(context.Context) error {
+	return nil
+}
+
+// FailCreate returns nil, since fault injection wasn't compiled in.
+func FailCreate(client.Object) error {
+	return nil
+}
+
+// PodReady returns whether pod is ready.
+func PodReady(pod *corev1.Pod) bool {
+	return podutils.IsPodReady(pod)
+}
@@ -0,0 +1,176 @@
+//go:build faults
+// +build faults
+
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Enabled is whether fault injection was compiled in.
+const Enabled = true
+
+// Config selects the faults to inject.
+type Config struct {
+	// TopoDelay is how long to wait before handing out each connection to
+	// a Vitess topology server.
+	TopoDelay metav1.Duration `json:"topoDelay,omitempty"`
+	// FailCreate lists the objects whose creation fails.
+	FailCreate []ObjectMatch `json:"failCreate,omitempty"`
+	// UnhealthyPods lists regular expressions matching the names of Pods
+	// that are treated as not ready, even if they are.
+	UnhealthyPods []string `json:"unhealthyPods,omitempty"`
+}
+
+// ObjectMatch selects objects by kind and name.
+type ObjectMatch struct {
+	// Kind is the kind of the objects, like "Pod".
+	Kind string `json:"kind"`
+	// Name is a regular expression matching the names of the objects.
+	// An empty value matches all names.
+	Name string `json:"name,omitempty"`
+}
+
+var (
+	mu     sync.RWMutex
+	config Config
+)
+
+// Register serves the fault configuration at Path on the metrics server of
+// mgr. GET returns the configuration, PUT replaces it, and DELETE clears it.
+func Register(mgr manager.Manager) error {
+	return mgr.AddMetricsExtraHandler(Path, http.HandlerFunc(serveHTTP))
+}
+
+func serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		newConfig := Config{}
+		if err := json.NewDecoder(r.Body).Decode(&newConfig); err != nil {
+			http.Error(w, fmt.Sprintf("invalid fault configuration: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := set(newConfig); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.WithField("config", newConfig).Warning("injecting faults")
+	case http.MethodDelete:
+		set(Config{})
+		log.Info("cleared injected faults")
+	default:
+		http.Error(w, "unsupported method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+// set validates and applies a new configuration.
+func set(newConfig Config) error {
+	for _, match := range newConfig.FailCreate {
+		if _, err := regexp.Compile(match.Name); err != nil {
+			return fmt.Errorf("invalid name pattern %q: %v", match.Name, err)
+		}
+	}
+	for _, pattern := range newConfig.UnhealthyPods {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid Pod name pattern %q: %v", pattern, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	config = newConfig
+	return nil
+}
+
+// DelayTopo waits for the configured topo delay, or until ctx is done.
+func DelayTopo(ctx context.Context) error {
+	mu.RLock()
+	delay := config.TopoDelay.Duration
+	mu.RUnlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FailCreate returns an error if the creation of obj should fail.
+func FailCreate(obj client.Object) error {
+	kind := reflect.TypeOf(obj).Elem().Name()
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, match := range config.FailCreate {
+		if match.Kind == kind && matches(match.Name, obj.GetName()) {
+			return fmt.Errorf("injected fault: creation of %v %v fails", kind, obj.GetName())
+		}
+	}
+	return nil
+}
+
+// PodReady returns whether pod is ready, and not selected to be treated as
+// unhealthy.
+func PodReady(pod *corev1.Pod) bool {
+	if !podutils.IsPodReady(pod) {
+		return false
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, pattern := range config.UnhealthyPods {
+		if matches(pattern, pod.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns whether name matches pattern, which was validated by set.
+func matches(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, _ := regexp.MatchString(pattern, name)
+	return matched
+}
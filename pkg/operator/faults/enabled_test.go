@@ -0,0 +1,59 @@
+//go:build faults
+// +build faults
+
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFaults(t *testing.T) {
+	defer set(Config{})
+
+	if err := set(Config{UnhealthyPods: []string{"("}}); err == nil {
+		t.Errorf("set() with an invalid pattern = nil; want error")
+	}
+	if err := set(Config{
+		FailCreate:    []ObjectMatch{{Kind: "Pod", Name: "^example-vttablet-"}},
+		UnhealthyPods: []string{"-zone1-0001$"},
+	}); err != nil {
+		t.Fatalf("set() = %v", err)
+	}
+
+	tablet := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "example-vttablet-zone1-0001"}}
+	if err := FailCreate(tablet); err == nil {
+		t.Errorf("FailCreate(%v) = nil; want error", tablet.Name)
+	}
+	if err := FailCreate(&corev1.Service{ObjectMeta: tablet.ObjectMeta}); err != nil {
+		t.Errorf("FailCreate(Service) = %v; want nil", err)
+	}
+
+	tablet.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	if PodReady(tablet) {
+		t.Errorf("PodReady(%v) = true; want false", tablet.Name)
+	}
+	other := tablet.DeepCopy()
+	other.Name = "example-vttablet-zone1-0002"
+	if !PodReady(other) {
+		t.Errorf("PodReady(%v) = false; want true", other.Name)
+	}
+}
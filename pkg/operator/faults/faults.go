@@ -0,0 +1,36 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package faults injects faults into the operator for end-to-end tests, like
+slow topology servers, failing object creation and unhealthy tablets, to
+exercise failover and turndown logic that's otherwise hard to trigger.
+
+Fault injection is only compiled in with the "faults" build tag. Without it,
+every injection point is a no-op. With it, faults are configured at runtime
+through Path on the operator's metrics server.
+*/
+package faults
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// Path is the path on the metrics server that serves the fault
+// configuration, if fault injection is compiled in.
+const Path = "/debug/faults"
+
+var log = logrus.WithField("component", "faults")
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package follower
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+Client returns a client that skips writes other than status updates while
+this replica isn't the leader, as if they had succeeded. That way, reconcile
+steps that change objects only as a means to an end still report the status
+the leader would report.
+
+If follower mode is disabled, it returns c itself.
+*/
+func Client(c client.Client) client.Client {
+	if elected == nil {
+		return c
+	}
+	return &followerClient{Client: c}
+}
+
+// followerClient wraps a client to skip writes while following. Status
+// writes go through the embedded client's Status() and SubResource().
+type followerClient struct {
+	client.Client
+}
+
+func (c *followerClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if !Leading() {
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *followerClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if !Leading() {
+		return nil
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *followerClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if !Leading() {
+		return nil
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *followerClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if !Leading() {
+		return nil
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *followerClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if !Leading() {
+		return nil
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Recorder returns an event recorder that drops events while this replica
+// isn't the leader, since the leader reports the same events, and events
+// about changes that were skipped would be misleading.
+//
+// If follower mode is disabled, it returns r itself.
+func Recorder(r record.EventRecorder) record.EventRecorder {
+	if elected == nil {
+		return r
+	}
+	return &followerRecorder{EventRecorder: r}
+}
+
+// followerRecorder wraps an event recorder to drop events while following.
+type followerRecorder struct {
+	record.EventRecorder
+}
+
+func (r *followerRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if Leading() {
+		r.EventRecorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *followerRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if Leading() {
+		r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *followerRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if Leading() {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
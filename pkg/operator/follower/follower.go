@@ -0,0 +1,108 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package follower lets operator replicas that aren't the leader keep the
+status of objects up to date.
+
+Normally, only the leader runs controllers, so status goes stale while
+leadership changes hands. When follower mode is enabled, controllers created
+with NewController run on every replica. Reconcilers should then check
+Leading, and only compute status while following, by reconciling with a
+context from NewContext, writing through a client from Client, and recording
+events with a recorder from Recorder.
+*/
+package follower
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+)
+
+// elected is closed once this replica becomes the leader, or nil if follower
+// mode is disabled.
+var elected <-chan struct{}
+
+// Enable turns on follower mode. The given channel must be closed once this
+// replica becomes the leader, as with manager.Manager.Elected.
+//
+// It must be called before any controllers are created.
+func Enable(electedCh <-chan struct{}) {
+	elected = electedCh
+}
+
+// Enabled returns whether follower mode is on.
+func Enabled() bool {
+	return elected != nil
+}
+
+// Leading returns whether this replica may make changes, which is always
+// the case unless follower mode is on and another replica is the leader.
+func Leading() bool {
+	if elected == nil {
+		return true
+	}
+	select {
+	case <-elected:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+NewController creates a controller like controller.New. In follower mode,
+the controller runs on every replica instead of only on the leader.
+
+Only use it for controllers whose reconcilers check Leading.
+*/
+func NewController(name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
+	if elected == nil {
+		return controller.New(name, mgr, options)
+	}
+	c, err := controller.NewUnmanaged(name, mgr, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(&unelectedController{Controller: c}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// unelectedController is a controller that the manager starts without
+// waiting to become the leader.
+type unelectedController struct {
+	controller.Controller
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *unelectedController) NeedLeaderElection() bool {
+	return false
+}
+
+// NewContext returns a copy of ctx in which reconcilers only compute status
+// if this replica isn't the leader.
+func NewContext(ctx context.Context) context.Context {
+	if Leading() {
+		return ctx
+	}
+	return reconciler.NewReadOnlyContext(ctx)
+}
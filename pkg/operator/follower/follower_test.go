@@ -0,0 +1,70 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package follower
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+)
+
+func TestFollowerClient(t *testing.T) {
+	electedCh := make(chan struct{})
+	Enable(electedCh)
+	defer Enable(nil)
+
+	ctx := context.Background()
+	c := Client(fake.NewClientBuilder().Build())
+	key := client.ObjectKey{Namespace: "ns", Name: "pod"}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+
+	// While following, writes are skipped.
+	if Leading() {
+		t.Fatalf("Leading() = true before being elected")
+	}
+	if !reconciler.IsReadOnly(NewContext(ctx)) {
+		t.Errorf("NewContext() isn't read-only while following")
+	}
+	if err := c.Create(ctx, pod.DeepCopy()); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if err := c.Get(ctx, key, &corev1.Pod{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("Get() error = %v; want NotFound since Create() should have been skipped", err)
+	}
+
+	// Once elected, writes go through.
+	close(electedCh)
+	if !Leading() {
+		t.Fatalf("Leading() = false after being elected")
+	}
+	if reconciler.IsPaused(NewContext(ctx)) {
+		t.Errorf("NewContext() is paused while leading")
+	}
+	if err := c.Create(ctx, pod.DeepCopy()); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if err := c.Get(ctx, key, &corev1.Pod{}); err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+}
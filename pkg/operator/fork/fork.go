@@ -33,15 +33,15 @@ determine which fork it should follow, if any.
 The parent Pod must also give some environment variables to the operator's
 Container to let this package find the Pod in which it's currently running:
 
-  env:
-  - name: PS_OPERATOR_POD_NAME
-    valueFrom:
-      fieldRef:
-        fieldPath: metadata.name
-  - name: PS_OPERATOR_POD_NAMESPACE
-    valueFrom:
-      fieldRef:
-        fieldPath: metadata.namespace
+	env:
+	- name: PS_OPERATOR_POD_NAME
+	  valueFrom:
+	    fieldRef:
+	      fieldPath: metadata.name
+	- name: PS_OPERATOR_POD_NAMESPACE
+	  valueFrom:
+	    fieldRef:
+	      fieldPath: metadata.namespace
 
 The parent Pod uses its own Pod spec as a basis to build the child Pod spec.
 */
@@ -0,0 +1,303 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package health reports whether the operator's controllers are making
+progress, so an operator that's effectively wedged fails its probes and gets
+restarted.
+
+A Monitor samples the workqueue and reconcile metrics that controller-runtime
+keeps for every controller. A controller is wedged if one of its reconciles
+has been running for longer than the threshold, or if it has queued work but
+hasn't finished a reconcile for that long. Those only depend on metrics, so
+controllers don't need to report anything themselves.
+*/
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+)
+
+const (
+	// ReportPath is the path on the metrics server that serves the full
+	// health report.
+	ReportPath = "/debug/health"
+
+	// sampleInterval is how often the metrics are sampled.
+	sampleInterval = 10 * time.Second
+
+	// checkName is the name of the health and readiness checks.
+	checkName = "controllers"
+
+	// The controller-runtime metrics we sample.
+	reconcileTotalMetric = "controller_runtime_reconcile_total"
+	queueDepthMetric     = "workqueue_depth"
+	longestRunningMetric = "workqueue_longest_running_processor_seconds"
+	controllerLabel      = "controller"
+	resultLabel          = "result"
+	queueNameLabel       = "name"
+	errorResult          = "error"
+)
+
+var log = logrus.WithField("component", "health")
+
+// ControllerStatus reports the progress of one controller.
+type ControllerStatus struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+	// QueueDepth is how many objects are waiting to be reconciled.
+	QueueDepth int64 `json:"queueDepth"`
+	// LongestRunningReconcileSeconds is how long the longest reconcile in
+	// progress has been running.
+	LongestRunningReconcileSeconds float64 `json:"longestRunningReconcileSeconds"`
+	// LastReconcileTime is when a reconcile last finished, as of the
+	// sample that saw it.
+	LastReconcileTime *time.Time `json:"lastReconcileTime,omitempty"`
+	// LastSuccessfulReconcileTime is when a reconcile last finished without
+	// an error, as of the sample that saw it.
+	LastSuccessfulReconcileTime *time.Time `json:"lastSuccessfulReconcileTime,omitempty"`
+	// Wedged explains why the controller isn't making progress, if it isn't.
+	Wedged string `json:"wedged,omitempty"`
+}
+
+// Report is the full health report.
+type Report struct {
+	// Healthy is false if any controller is wedged.
+	Healthy bool `json:"healthy"`
+	// Controllers reports the progress of each controller.
+	Controllers []ControllerStatus `json:"controllers"`
+	// TopoServers reports the connections to Vitess topology servers.
+	TopoServers []toposerver.ConnStatus `json:"topoServers"`
+}
+
+// controllerState is what the Monitor remembers about a controller between
+// samples.
+type controllerState struct {
+	status ControllerStatus
+	// total and successes are the reconcile counts as of the last sample.
+	total, successes float64
+	// lastProgress is when the controller last finished a reconcile or had
+	// an empty queue.
+	lastProgress time.Time
+}
+
+// Monitor samples controller metrics to tell whether controllers are wedged.
+type Monitor struct {
+	gatherer  prometheus.Gatherer
+	threshold time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	controllers map[string]*controllerState
+}
+
+/*
+Add creates a Monitor and registers it with mgr: as a runnable that samples
+metrics, as health and readiness checks that fail while any controller is
+wedged for longer than threshold, and as a handler for the full report at
+ReportPath on the metrics server.
+*/
+func Add(mgr manager.Manager, threshold time.Duration) error {
+	m := NewMonitor(ctrlmetrics.Registry, threshold)
+	if err := mgr.Add(m); err != nil {
+		return err
+	}
+	if err := mgr.AddHealthzCheck(checkName, m.Check); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck(checkName, m.Check); err != nil {
+		return err
+	}
+	return mgr.AddMetricsExtraHandler(ReportPath, m)
+}
+
+// NewMonitor returns a Monitor that reads metrics from gatherer.
+func NewMonitor(gatherer prometheus.Gatherer, threshold time.Duration) *Monitor {
+	return &Monitor{
+		gatherer:    gatherer,
+		threshold:   threshold,
+		now:         time.Now,
+		controllers: make(map[string]*controllerState),
+	}
+}
+
+// NeedLeaderElection returns false, so every operator replica reports its
+// own health, whether it's the leader or not.
+func (m *Monitor) NeedLeaderElection() bool {
+	return false
+}
+
+// Start samples metrics until ctx is done.
+func (m *Monitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.sample(); err != nil {
+			log.WithError(err).Warning("failed to sample controller metrics")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample reads the current metrics and updates the state of each controller.
+func (m *Monitor) sample() error {
+	families, err := m.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	totals := map[string]float64{}
+	successes := map[string]float64{}
+	depths := map[string]float64{}
+	longest := map[string]float64{}
+	for _, family := range families {
+		switch family.GetName() {
+		case reconcileTotalMetric:
+			for _, metric := range family.GetMetric() {
+				name := labelValue(metric, controllerLabel)
+				value := metric.GetCounter().GetValue()
+				totals[name] += value
+				if labelValue(metric, resultLabel) != errorResult {
+					successes[name] += value
+				}
+			}
+		case queueDepthMetric:
+			for _, metric := range family.GetMetric() {
+				depths[labelValue(metric, queueNameLabel)] = metric.GetGauge().GetValue()
+			}
+		case longestRunningMetric:
+			for _, metric := range family.GetMetric() {
+				longest[labelValue(metric, queueNameLabel)] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	// The workqueue of each controller is named after it.
+	for name, depth := range depths {
+		state, ok := m.controllers[name]
+		if !ok {
+			// Give new controllers a full threshold to make progress.
+			state = &controllerState{status: ControllerStatus{Name: name}, lastProgress: now}
+			m.controllers[name] = state
+		}
+		if totals[name] != state.total {
+			state.total = totals[name]
+			state.status.LastReconcileTime = timePtr(now)
+			state.lastProgress = now
+		}
+		if successes[name] != state.successes {
+			state.successes = successes[name]
+			state.status.LastSuccessfulReconcileTime = timePtr(now)
+		}
+		if depth == 0 {
+			state.lastProgress = now
+		}
+		state.status.QueueDepth = int64(depth)
+		state.status.LongestRunningReconcileSeconds = longest[name]
+
+		state.status.Wedged = ""
+		if running := time.Duration(longest[name] * float64(time.Second)); running > m.threshold {
+			state.status.Wedged = fmt.Sprintf("a reconcile has been running for %v", running.Round(time.Second))
+		} else if stalled := now.Sub(state.lastProgress); stalled > m.threshold {
+			state.status.Wedged = fmt.Sprintf("%v object(s) are queued, but no reconcile finished in %v", int64(depth), stalled.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// Check returns an error if any controller is wedged.
+func (m *Monitor) Check(*http.Request) error {
+	var wedged []string
+	for _, status := range m.controllerStatuses() {
+		if status.Wedged != "" {
+			wedged = append(wedged, fmt.Sprintf("%v: %v", status.Name, status.Wedged))
+		}
+	}
+	if len(wedged) > 0 {
+		return fmt.Errorf("wedged controllers: %v", strings.Join(wedged, "; "))
+	}
+	return nil
+}
+
+// ServeHTTP serves the full health report as JSON.
+func (m *Monitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := &Report{
+		Healthy:     true,
+		Controllers: m.controllerStatuses(),
+		TopoServers: toposerver.Status(),
+	}
+	for _, status := range report.Controllers {
+		if status.Wedged != "" {
+			report.Healthy = false
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+}
+
+// controllerStatuses returns the status of each controller, sorted by name.
+func (m *Monitor) controllerStatuses() []ControllerStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]ControllerStatus, 0, len(m.controllers))
+	for _, state := range m.controllers {
+		statuses = append(statuses, state.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
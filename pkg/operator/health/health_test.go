@@ -0,0 +1,83 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMonitor(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	reconciles := prometheus.NewCounterVec(prometheus.CounterOpts{Name: reconcileTotalMetric}, []string{controllerLabel, resultLabel})
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: queueDepthMetric}, []string{queueNameLabel})
+	longest := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: longestRunningMetric}, []string{queueNameLabel})
+	registry.MustRegister(reconciles, depth, longest)
+
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMonitor(registry, 10*time.Minute)
+	m.now = func() time.Time { return now }
+	sample := func() {
+		if err := m.sample(); err != nil {
+			t.Fatalf("sample() = %v", err)
+		}
+	}
+
+	depth.WithLabelValues("vitessshard-controller").Set(3)
+	longest.WithLabelValues("vitessshard-controller").Set(1)
+	sample()
+	if err := m.Check(nil); err != nil {
+		t.Errorf("Check() for a new controller = %v; want nil", err)
+	}
+
+	// Finishing reconciles is progress, even if they fail.
+	now = now.Add(8 * time.Minute)
+	reconciles.WithLabelValues("vitessshard-controller", errorResult).Inc()
+	sample()
+	now = now.Add(8 * time.Minute)
+	sample()
+	if err := m.Check(nil); err != nil {
+		t.Errorf("Check() after a reconcile finished = %v; want nil", err)
+	}
+	status := m.controllerStatuses()[0]
+	if status.LastReconcileTime == nil || status.LastSuccessfulReconcileTime != nil {
+		t.Errorf("status = %+v; want a last reconcile, but no successful one", status)
+	}
+
+	// Queued work without progress wedges the controller.
+	now = now.Add(3 * time.Minute)
+	sample()
+	if err := m.Check(nil); err == nil {
+		t.Errorf("Check() with a stalled queue = nil; want error")
+	}
+
+	// An empty queue is fine, no matter how long ago the last reconcile was.
+	depth.WithLabelValues("vitessshard-controller").Set(0)
+	sample()
+	if err := m.Check(nil); err != nil {
+		t.Errorf("Check() with an empty queue = %v; want nil", err)
+	}
+
+	// A reconcile that's running for too long wedges the controller.
+	longest.WithLabelValues("vitessshard-controller").Set((11 * time.Minute).Seconds())
+	sample()
+	if err := m.Check(nil); err == nil {
+		t.Errorf("Check() with a long-running reconcile = nil; want error")
+	}
+}
@@ -19,6 +19,8 @@ package k8s
 const (
 	// ZoneFailureDomainLabel is the label on Kubernetes Nodes specifying what AZ it's in.
 	ZoneFailureDomainLabel = "failure-domain.beta.kubernetes.io/zone"
+	// TopologyZoneLabel is the GA replacement for ZoneFailureDomainLabel.
+	TopologyZoneLabel = "topology.kubernetes.io/zone"
 	// HostnameLabel is the affinity topology key used to distinguish Kuberenetes Nodes from each other.
 	HostnameLabel = "kubernetes.io/hostname"
 )
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lockserver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+const (
+	tlsCertDirName = "topo-tls-cert"
+	tlsKeyDirName  = "topo-tls-key"
+	tlsCADirName   = "topo-tls-ca"
+	authDirName    = "topo-auth"
+)
+
+// ClientMounts are the Secret mounts a Vitess component needs to connect
+// to a lockserver that requires TLS or authentication.
+//
+// A nil *ClientMounts is valid, and means no Secrets are needed.
+type ClientMounts struct {
+	implementation string
+	cert, key, ca  *secrets.VolumeMount
+	auth           *secrets.VolumeMount
+}
+
+// NewClientMounts returns the client mounts for the given lockserver params,
+// or nil if the lockserver doesn't need any.
+func NewClientMounts(params *planetscalev2.VitessLockserverParams) *ClientMounts {
+	if params == nil || (params.TLS == nil && params.AuthSecret == nil) {
+		return nil
+	}
+	m := &ClientMounts{implementation: params.Implementation}
+	if params.TLS != nil {
+		m.cert = secrets.Mount(&params.TLS.ClientCertSecret, tlsCertDirName)
+		m.key = secrets.Mount(&params.TLS.ClientKeySecret, tlsKeyDirName)
+		if params.TLS.CACertSecret != nil {
+			m.ca = secrets.Mount(params.TLS.CACertSecret, tlsCADirName)
+		}
+	}
+	// etcd2 has no auth flag, so there's nothing to mount.
+	if params.AuthSecret != nil && params.Implementation != VitessEtcdImplementationName {
+		m.auth = secrets.Mount(params.AuthSecret, authDirName)
+	}
+	return m
+}
+
+func (m *ClientMounts) mounts() []*secrets.VolumeMount {
+	if m == nil {
+		return nil
+	}
+	var mounts []*secrets.VolumeMount
+	for _, mount := range []*secrets.VolumeMount{m.cert, m.key, m.ca, m.auth} {
+		if mount != nil {
+			mounts = append(mounts, mount)
+		}
+	}
+	return mounts
+}
+
+// Flags returns the flags that tell Vitess where to find the mounted files.
+func (m *ClientMounts) Flags() vitess.Flags {
+	if m == nil {
+		return nil
+	}
+	flags := vitess.Flags{}
+	switch m.implementation {
+	case VitessEtcdImplementationName:
+		if m.cert != nil {
+			flags["topo_etcd_tls_cert"] = m.cert.FilePath()
+			flags["topo_etcd_tls_key"] = m.key.FilePath()
+		}
+		if m.ca != nil {
+			flags["topo_etcd_tls_ca"] = m.ca.FilePath()
+		}
+	case VitessZkImplementationName:
+		if m.cert != nil {
+			flags["topo_zk_tls_cert"] = m.cert.FilePath()
+			flags["topo_zk_tls_key"] = m.key.FilePath()
+		}
+		if m.ca != nil {
+			flags["topo_zk_tls_ca"] = m.ca.FilePath()
+		}
+		if m.auth != nil {
+			flags["topo_zk_auth_file"] = m.auth.FilePath()
+		}
+	case VitessConsulImplementationName:
+		if m.auth != nil {
+			flags["consul_auth_static_file"] = m.auth.FilePath()
+		}
+	}
+	return flags
+}
+
+// PodVolumes returns the Volumes to add to the Pod.
+func (m *ClientMounts) PodVolumes() []corev1.Volume {
+	var volumes []corev1.Volume
+	for _, mount := range m.mounts() {
+		volumes = append(volumes, mount.PodVolumes()...)
+	}
+	return volumes
+}
+
+// ContainerVolumeMounts returns the VolumeMounts to add to the container
+// that connects to the lockserver.
+func (m *ClientMounts) ContainerVolumeMounts() []corev1.VolumeMount {
+	var volumeMounts []corev1.VolumeMount
+	for _, mount := range m.mounts() {
+		volumeMounts = append(volumeMounts, mount.ContainerVolumeMount())
+	}
+	return volumeMounts
+}
@@ -51,21 +51,22 @@ func GlobalEtcdName(clusterName string) string {
 
 // NewEtcdLockserver generates an EtcdLockserver object for the given EtcdLockserverTemplate.
 // The EtcdLockserverTemplate must have already had defaults filled in.
-func NewEtcdLockserver(key client.ObjectKey, tpl *planetscalev2.EtcdLockserverTemplate, labels map[string]string, zone string) *planetscalev2.EtcdLockserver {
+func NewEtcdLockserver(key client.ObjectKey, tpl *planetscalev2.EtcdLockserverTemplate, labels map[string]string, zone string, resourceDefaults *planetscalev2.ResourceDefaultsSpec) *planetscalev2.EtcdLockserver {
 	ls := &planetscalev2.EtcdLockserver{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      key.Name,
 			Namespace: key.Namespace,
 		},
 	}
-	UpdateEtcdLockserver(ls, tpl, labels, zone)
+	UpdateEtcdLockserver(ls, tpl, labels, zone, resourceDefaults)
 	return ls
 }
 
 // UpdateEtcdLockserver updates parts of an existing EtcdLockserver that are allowed to change in-place.
 // The EtcdLockserverTemplate must have already had defaults filled in.
-func UpdateEtcdLockserver(obj *planetscalev2.EtcdLockserver, tpl *planetscalev2.EtcdLockserverTemplate, labels map[string]string, zone string) {
+func UpdateEtcdLockserver(obj *planetscalev2.EtcdLockserver, tpl *planetscalev2.EtcdLockserverTemplate, labels map[string]string, zone string, resourceDefaults *planetscalev2.ResourceDefaultsSpec) {
 	update.Labels(&obj.Labels, labels)
 	obj.Spec.Zone = zone
+	obj.Spec.ResourceDefaults = resourceDefaults
 	obj.Spec.EtcdLockserverTemplate = *tpl
 }
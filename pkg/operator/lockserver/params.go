@@ -29,6 +29,10 @@ const (
 	// VitessEtcdImplementationName is the topo plugin name to give Vitess to tell it to
 	// connect to an etcd cluster as the lockserver (topology store).
 	VitessEtcdImplementationName = "etcd2"
+	// VitessZkImplementationName is the topo plugin name for a ZooKeeper lockserver.
+	VitessZkImplementationName = "zk2"
+	// VitessConsulImplementationName is the topo plugin name for a Consul lockserver.
+	VitessConsulImplementationName = "consul"
 )
 
 // GlobalConnectionParams returns the Vitess connection parameters for a
@@ -83,6 +87,8 @@ func LocalConnectionParams(globalLockserverSpec, cellLockserverSpec *planetscale
 			Implementation: globalParams.Implementation,
 			Address:        globalParams.Address,
 			RootPath:       rootPath,
+			TLS:            globalParams.TLS,
+			AuthSecret:     globalParams.AuthSecret,
 		}
 	}
 }
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// LevelHandlerPath is the HTTP path at which LevelHandler is usually served.
+const LevelHandlerPath = "/debug/loglevel"
+
+var adminAddress = flag.String("log_level_admin_address", "", "host:port to serve "+LevelHandlerPath+" on with changes to the log level allowed, such as 127.0.0.1:8384 to only allow them from inside the Pod; the metrics port serves it read-only. An empty value means the level can't be changed at runtime.")
+
+// LevelHandler returns an HTTP handler to inspect the log level at runtime,
+// and to change it if writable is true.
+//
+// GET returns the current level. If writable, PUT or POST with a 'level'
+// query parameter (e.g. ?level=debug) sets a new level until the process
+// restarts.
+func LevelHandler(writable bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+		case writable && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+			level, err := logrus.ParseLevel(r.URL.Query().Get("level"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if level != logrus.GetLevel() {
+				logrus.WithField("level", level.String()).Warning("Changing log level")
+				logrus.SetLevel(level)
+			}
+		default:
+			if writable {
+				w.Header().Set("Allow", "GET, PUT, POST")
+			} else {
+				w.Header().Set("Allow", "GET")
+			}
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		fmt.Fprintln(w, logrus.GetLevel().String())
+	})
+}
+
+// AddLevelHandlers serves the log level read-only next to the metrics, which
+// anyone who can reach the Pod can read. If --log_level_admin_address is set,
+// it's also served there with changes allowed.
+func AddLevelHandlers(mgr manager.Manager) error {
+	if err := mgr.AddMetricsExtraHandler(LevelHandlerPath, LevelHandler(false)); err != nil {
+		return err
+	}
+	if *adminAddress == "" {
+		return nil
+	}
+	return mgr.Add(&adminServer{address: *adminAddress})
+}
+
+// adminServer serves the writable LevelHandler on its own listener.
+type adminServer struct {
+	address string
+}
+
+// Start implements manager.Runnable.
+func (s *adminServer) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("can't listen on --log_level_admin_address: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(LevelHandlerPath, LevelHandler(true))
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false, so the log level of every operator
+// replica can be changed.
+func (s *adminServer) NeedLeaderElection() bool {
+	return false
+}
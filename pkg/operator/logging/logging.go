@@ -0,0 +1,138 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package logging configures the operator's structured logs and carries
+per-reconcile log fields through a context.
+
+Controllers should attach a logger to the context at the top of each
+Reconcile, and anything called from there should log with FromContext(ctx)
+so that every line carries the same correlation fields.
+*/
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// ReconcileIDField is the log field that identifies a single pass
+	// through a controller's Reconcile function.
+	ReconcileIDField = "reconcileID"
+	// TabletField is the log field that carries the alias of the tablet
+	// that a log line is about.
+	TabletField = "tablet"
+
+	// TextFormat logs human-readable key=value lines.
+	TextFormat = "text"
+	// JSONFormat logs one JSON object per line.
+	JSONFormat = "json"
+)
+
+var (
+	logFormat = flag.String("log_format", TextFormat, "format of operator logs: 'text' or 'json'")
+	logLevel  = flag.String("log_level", logrus.InfoLevel.String(), "minimum level of operator logs to print (e.g. 'debug', 'info', 'warning'); can be changed at runtime via /debug/loglevel on --log_level_admin_address")
+)
+
+// objectFields maps labels set by the operator to the log fields that carry them.
+var objectFields = map[string]string{
+	planetscalev2.ClusterLabel:  "cluster",
+	planetscalev2.CellLabel:     "cell",
+	planetscalev2.KeyspaceLabel: "keyspace",
+	planetscalev2.ShardLabel:    "shard",
+}
+
+type contextKey struct{}
+
+// Init configures the global logrus logger from command-line flags.
+// It must be called after flags are parsed.
+func Init() error {
+	switch *logFormat {
+	case TextFormat:
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	case JSONFormat:
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("invalid --log_format %q; must be %q or %q", *logFormat, TextFormat, JSONFormat)
+	}
+
+	level, err := logrus.ParseLevel(*logLevel)
+	if err != nil {
+		return fmt.Errorf("invalid --log_level: %v", err)
+	}
+	logrus.SetLevel(level)
+	return nil
+}
+
+// NewReconcileID returns a random ID to correlate all the log lines
+// produced by a single reconcile.
+func NewReconcileID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// This should never happen, and an ID is just a debugging aid.
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// ObjectFields returns log fields identifying the Vitess cluster, cell,
+// keyspace, shard and tablet to which an object belongs, based on its labels.
+// Fields are only included for labels that are set.
+func ObjectFields(obj metav1.Object) logrus.Fields {
+	return LabelFields(obj.GetLabels())
+}
+
+// LabelFields returns the log fields that ObjectFields would return for an
+// object with the given labels.
+func LabelFields(labels map[string]string) logrus.Fields {
+	fields := logrus.Fields{}
+	for label, field := range objectFields {
+		if value, ok := labels[label]; ok {
+			fields[field] = value
+		}
+	}
+	// Tablet Pods and PVCs are labeled with the parts of the tablet alias.
+	if cell, ok := labels[planetscalev2.CellLabel]; ok {
+		if uid, err := strconv.ParseUint(labels[planetscalev2.TabletUidLabel], 10, 32); err == nil {
+			fields[TabletField] = fmt.Sprintf("%s-%010d", cell, uid)
+		}
+	}
+	return fields
+}
+
+// NewContext returns a copy of ctx that carries the given logger.
+func NewContext(ctx context.Context, log *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext returns the logger attached to ctx by NewContext.
+// If there is none, it returns a logger with no extra fields.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if log, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return log
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
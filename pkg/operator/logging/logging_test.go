@@ -0,0 +1,111 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestObjectFields(t *testing.T) {
+	obj := &metav1.ObjectMeta{
+		Labels: map[string]string{
+			planetscalev2.ClusterLabel:  "example",
+			planetscalev2.KeyspaceLabel: "commerce",
+			"unrelated":                 "label",
+		},
+	}
+	want := logrus.Fields{
+		"cluster":  "example",
+		"keyspace": "commerce",
+	}
+	if got := ObjectFields(obj); !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("ObjectFields() = %#v; want %#v", got, want)
+	}
+
+	// Tablet Pods also get the tablet alias.
+	pod := &metav1.ObjectMeta{
+		Labels: map[string]string{
+			planetscalev2.ClusterLabel:   "example",
+			planetscalev2.CellLabel:      "zone1",
+			planetscalev2.KeyspaceLabel:  "commerce",
+			planetscalev2.ShardLabel:     "x-x",
+			planetscalev2.TabletUidLabel: "101",
+		},
+	}
+	want = logrus.Fields{
+		"cluster":   "example",
+		"cell":      "zone1",
+		"keyspace":  "commerce",
+		"shard":     "x-x",
+		TabletField: "zone1-0000000101",
+	}
+	if got := ObjectFields(pod); !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("ObjectFields() = %#v; want %#v", got, want)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	log := logrus.WithField(ReconcileIDField, "abc")
+	ctx := NewContext(context.Background(), log)
+	if got := FromContext(ctx); got != log {
+		t.Errorf("FromContext() = %v; want %v", got, log)
+	}
+	if got := FromContext(context.Background()); got == nil {
+		t.Errorf("FromContext() = nil; want default logger")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer logrus.SetLevel(logrus.GetLevel())
+	logrus.SetLevel(logrus.InfoLevel)
+
+	table := []struct {
+		name      string
+		writable  bool
+		method    string
+		query     string
+		wantCode  int
+		wantLevel logrus.Level
+	}{
+		{"get", false, http.MethodGet, "", http.StatusOK, logrus.InfoLevel},
+		{"read-only put", false, http.MethodPut, "?level=debug", http.StatusMethodNotAllowed, logrus.InfoLevel},
+		{"read-only post", false, http.MethodPost, "?level=debug", http.StatusMethodNotAllowed, logrus.InfoLevel},
+		{"writable put", true, http.MethodPut, "?level=debug", http.StatusOK, logrus.DebugLevel},
+		{"writable bad level", true, http.MethodPut, "?level=bogus", http.StatusBadRequest, logrus.DebugLevel},
+		{"writable post", true, http.MethodPost, "?level=warning", http.StatusOK, logrus.WarnLevel},
+		{"writable delete", true, http.MethodDelete, "", http.StatusMethodNotAllowed, logrus.WarnLevel},
+	}
+	for _, test := range table {
+		w := httptest.NewRecorder()
+		LevelHandler(test.writable).ServeHTTP(w, httptest.NewRequest(test.method, LevelHandlerPath+test.query, nil))
+		if w.Code != test.wantCode {
+			t.Errorf("%s: status = %v; want %v", test.name, w.Code, test.wantCode)
+		}
+		if got := logrus.GetLevel(); got != test.wantLevel {
+			t.Errorf("%s: level = %v; want %v", test.name, got, test.wantLevel)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package maintenance evaluates the maintenance windows in a
+VitessClusterUpdateStrategy, which restrict when disruptive changes
+such as tablet restarts and planned reparents may be applied.
+*/
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	// Embed the time zone database so TimeZone works even if the operator
+	// image doesn't have one installed.
+	_ "time/tzdata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+/*
+Open returns whether disruptive changes are allowed at the given time.
+
+That's the case if no windows are defined, or if any of the windows is open.
+Windows that are invalid are never considered open; if any window is
+invalid, the first such error is also returned.
+*/
+func Open(windows []planetscalev2.MaintenanceWindow, now time.Time) (bool, error) {
+	if len(windows) == 0 {
+		return true, nil
+	}
+
+	var firstErr error
+	for i := range windows {
+		open, err := windowOpen(&windows[i], now)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if open {
+			return true, nil
+		}
+	}
+	return false, firstErr
+}
+
+// Validate returns an error describing the first invalid window, if any.
+func Validate(windows []planetscalev2.MaintenanceWindow) error {
+	for i := range windows {
+		if _, _, err := parseWindow(&windows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+NextOpen returns the next time after now that one of the windows opens.
+
+It returns the zero Time if there are no valid windows, or if none of them
+will open in the next few years.
+*/
+func NextOpen(windows []planetscalev2.MaintenanceWindow, now time.Time) time.Time {
+	var next time.Time
+	for i := range windows {
+		schedule, loc, err := parseWindow(&windows[i])
+		if err != nil {
+			continue
+		}
+		start := schedule.Next(now.In(loc))
+		if start.IsZero() {
+			continue
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+// Describe returns a human-readable explanation of why disruptive changes
+// are not allowed now, for use in event messages.
+func Describe(windows []planetscalev2.MaintenanceWindow, now time.Time) string {
+	next := NextOpen(windows, now)
+	if next.IsZero() {
+		return "waiting for a maintenance window, but none will open"
+	}
+	return fmt.Sprintf("waiting for the next maintenance window at %v", next.Format(time.RFC3339))
+}
+
+func windowOpen(window *planetscalev2.MaintenanceWindow, now time.Time) (bool, error) {
+	schedule, loc, err := parseWindow(window)
+	if err != nil {
+		return false, err
+	}
+
+	// Look for a start time that's recent enough for the window to still be
+	// open. There might be several if the window lasts longer than the time
+	// between starts.
+	duration := window.Duration.Duration
+	now = now.In(loc)
+	for start := schedule.Next(now.Add(-duration)); !start.IsZero() && !start.After(now); start = schedule.Next(start.Add(time.Minute)) {
+		if now.Before(start.Add(duration)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseWindow(window *planetscalev2.MaintenanceWindow) (*Schedule, *time.Location, error) {
+	if window.Duration.Duration <= 0 {
+		return nil, nil, fmt.Errorf("invalid maintenance window %q: duration must be positive", window.Schedule)
+	}
+	schedule, err := ParseSchedule(window.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid maintenance window: %v", err)
+	}
+	loc := time.UTC
+	if window.TimeZone != "" {
+		if loc, err = time.LoadLocation(window.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone in maintenance window %q: %v", window.Schedule, err)
+		}
+	}
+	return schedule, loc, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestScheduleNext(t *testing.T) {
+	table := []struct {
+		schedule string
+		from     string
+		want     string
+	}{
+		{"*/15 * * * *", "2020-03-04T10:07:30Z", "2020-03-04T10:15:00Z"},
+		{"0 2 * * 6", "2020-03-04T10:07:00Z", "2020-03-07T02:00:00Z"},
+		{"0 2 * * 7", "2020-03-04T10:07:00Z", "2020-03-08T02:00:00Z"},
+		{"30 1 1 * *", "2020-03-04T10:07:00Z", "2020-04-01T01:30:00Z"},
+		// Day of month and day of week are OR'd if both are restricted.
+		{"0 0 15 * 1", "2020-03-04T10:07:00Z", "2020-03-09T00:00:00Z"},
+		{"0 0 29 2 *", "2020-03-04T10:07:00Z", "2024-02-29T00:00:00Z"},
+		{"0 0 31 2 *", "2020-03-04T10:07:00Z", ""},
+	}
+
+	for _, test := range table {
+		schedule, err := ParseSchedule(test.schedule)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) error: %v", test.schedule, err)
+		}
+		from, _ := time.Parse(time.RFC3339, test.from)
+		got := schedule.Next(from)
+		gotStr := ""
+		if !got.IsZero() {
+			gotStr = got.Format(time.RFC3339)
+		}
+		if gotStr != test.want {
+			t.Errorf("%q.Next(%v) = %q; want %q", test.schedule, test.from, gotStr, test.want)
+		}
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) = nil error; want error", spec)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	windows := []planetscalev2.MaintenanceWindow{
+		{
+			// Saturdays 02:00-06:00 in Los Angeles.
+			Schedule: "0 2 * * 6",
+			Duration: metav1.Duration{Duration: 4 * time.Hour},
+			TimeZone: "America/Los_Angeles",
+		},
+	}
+
+	table := []struct {
+		now  string
+		want bool
+	}{
+		{"2020-03-07T09:59:00Z", false},
+		{"2020-03-07T10:00:00Z", true},
+		{"2020-03-07T13:59:00Z", true},
+		{"2020-03-07T14:00:00Z", false},
+	}
+
+	for _, test := range table {
+		now, _ := time.Parse(time.RFC3339, test.now)
+		got, err := Open(windows, now)
+		if err != nil {
+			t.Fatalf("Open() error: %v", err)
+		}
+		if got != test.want {
+			t.Errorf("Open(%v) = %v; want %v", test.now, got, test.want)
+		}
+	}
+
+	if got, err := Open(nil, time.Now()); !got || err != nil {
+		t.Errorf("Open(nil) = %v, %v; want true, nil", got, err)
+	}
+
+	invalid := []planetscalev2.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Nowhere/Special"}}
+	if got, err := Open(invalid, time.Now()); got || err == nil {
+		t.Errorf("Open(invalid) = %v, %v; want false, error", got, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := planetscalev2.MaintenanceWindow{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}}
+	if err := Validate([]planetscalev2.MaintenanceWindow{valid}); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+	for _, invalid := range []planetscalev2.MaintenanceWindow{
+		{Schedule: "0 2 * * 6"},
+		{Schedule: "0 2 * *", Duration: metav1.Duration{Duration: time.Hour}},
+		{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Nowhere/Special"},
+	} {
+		if err := Validate([]planetscalev2.MaintenanceWindow{valid, invalid}); err == nil {
+			t.Errorf("Validate(%v) = nil; want error", invalid)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package mesh makes Pods work with the proxy sidecars that a service mesh
+injects into them.
+*/
+package mesh
+
+import (
+	"strconv"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+/*
+Annotations returns the annotations that make a Pod work with the proxy
+sidecar of the service mesh, if any, that networking selects.
+
+Connections to and from the MySQL protocol port bypass the proxy, since the
+mesh can't detect the protocol of connections on which the server speaks
+first. The proxy runs as a native sidecar, so Kubernetes starts it before the
+init containers, and only stops it once all other containers have exited,
+which lets a deleted tablet drain, and a vtbackup Pod complete.
+*/
+func Annotations(networking *planetscalev2.NetworkingSpec) map[string]string {
+	mysqlPort := strconv.Itoa(planetscalev2.DefaultMysqlPort)
+
+	switch networking.Mesh() {
+	case planetscalev2.IstioServiceMesh:
+		return map[string]string{
+			"sidecar.istio.io/nativeSidecar":                "true",
+			"traffic.sidecar.istio.io/excludeInboundPorts":  mysqlPort,
+			"traffic.sidecar.istio.io/excludeOutboundPorts": mysqlPort,
+		}
+	case planetscalev2.LinkerdServiceMesh:
+		return map[string]string{
+			"config.alpha.linkerd.io/proxy-enable-native-sidecar": "true",
+			"config.linkerd.io/skip-inbound-ports":                mysqlPort,
+			"config.linkerd.io/skip-outbound-ports":               mysqlPort,
+		}
+	}
+	return nil
+}
@@ -34,6 +34,10 @@ const (
 	ShardLabel = "shard"
 	// BackupStorageLabel is the label whose value gives the name of a VitessBackupStorage object.
 	BackupStorageLabel = "backup_storage"
+	// MaterializeLabel is the label whose value gives the name of a VitessMaterialize object.
+	MaterializeLabel = "materialize"
+	// FederationLabel is the label whose value gives the name of a VitessClusterFederation object.
+	FederationLabel = "federation"
 
 	// ResultLabel is a common metrics label for the success/failure of an operation.
 	ResultLabel = "result"
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package monitoring generates objects for the Prometheus Operator.
+
+We don't depend on the Prometheus Operator's Go types, so the objects are
+built as unstructured content and only created when the CRDs are installed.
+*/
+package monitoring
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// PodMonitorGVK is the GroupVersionKind of the Prometheus Operator PodMonitor.
+var PodMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PodMonitor",
+}
+
+// metricsPath is the HTTP path on which all the components we scrape
+// (Vitess servers, mysqld-exporter and etcd) serve Prometheus metrics.
+const metricsPath = "/metrics"
+
+// relabelLabels maps Pod labels set by the operator to the target labels
+// that each scraped series will carry.
+var relabelLabels = []struct {
+	podLabel    string
+	targetLabel string
+}{
+	{podLabel: planetscalev2.ClusterLabel, targetLabel: "vitess_cluster"},
+	{podLabel: planetscalev2.ComponentLabel, targetLabel: "component"},
+	{podLabel: planetscalev2.CellLabel, targetLabel: "cell"},
+	{podLabel: planetscalev2.KeyspaceLabel, targetLabel: "keyspace"},
+	{podLabel: planetscalev2.ShardLabel, targetLabel: "shard"},
+	{podLabel: planetscalev2.TabletTypeLabel, targetLabel: "tablet_type"},
+}
+
+// PodMonitorSpec specifies the desired state of a PodMonitor.
+type PodMonitorSpec struct {
+	// Labels are set on the PodMonitor object itself.
+	Labels map[string]string
+	// Selector selects the Pods to scrape.
+	Selector map[string]string
+	// Ports are the names of the container ports to scrape.
+	Ports []string
+	// ScrapeInterval is optional. If empty, the Prometheus default is used.
+	ScrapeInterval string
+}
+
+// PodMonitorName returns the name of the PodMonitor for a component.
+func PodMonitorName(clusterName, componentName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, componentName)
+}
+
+// NewPodMonitorKind returns an empty PodMonitor object that can be used as
+// the Kind in a reconciler.Strategy.
+func NewPodMonitorKind() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(PodMonitorGVK)
+	return obj
+}
+
+// PodMonitorInstalled returns whether the PodMonitor CRD is available
+// in the Kubernetes cluster.
+func PodMonitorInstalled(mapper meta.RESTMapper) (bool, error) {
+	_, err := mapper.RESTMapping(PodMonitorGVK.GroupKind(), PodMonitorGVK.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// NewPodMonitor creates a new PodMonitor object.
+func NewPodMonitor(key client.ObjectKey, spec *PodMonitorSpec) *unstructured.Unstructured {
+	// Fill in the immutable parts.
+	obj := NewPodMonitorKind()
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
+	// Set everything else.
+	UpdatePodMonitor(obj, spec)
+	return obj
+}
+
+// UpdatePodMonitor updates the mutable parts of a PodMonitor.
+func UpdatePodMonitor(obj *unstructured.Unstructured, spec *PodMonitorSpec) {
+	labels := obj.GetLabels()
+	update.Labels(&labels, spec.Labels)
+	obj.SetLabels(labels)
+
+	// Everything below must only use JSON-compatible types
+	// (map[string]interface{}, []interface{}, string, int64, bool),
+	// since unstructured content is deep-copied as JSON.
+	matchLabels := make(map[string]interface{}, len(spec.Selector))
+	for key, value := range spec.Selector {
+		matchLabels[key] = value
+	}
+
+	endpoints := make([]interface{}, 0, len(spec.Ports))
+	for _, port := range spec.Ports {
+		endpoint := map[string]interface{}{
+			"port":        port,
+			"path":        metricsPath,
+			"relabelings": relabelings(),
+		}
+		if spec.ScrapeInterval != "" {
+			endpoint["interval"] = spec.ScrapeInterval
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	obj.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": matchLabels,
+		},
+		"podMetricsEndpoints": endpoints,
+	}
+}
+
+func relabelings() []interface{} {
+	result := make([]interface{}, 0, len(relabelLabels))
+	for _, l := range relabelLabels {
+		result = append(result, map[string]interface{}{
+			"sourceLabels": []interface{}{"__meta_kubernetes_pod_label_" + sanitizeLabelName(l.podLabel)},
+			"targetLabel":  l.targetLabel,
+		})
+	}
+	return result
+}
+
+// sanitizeLabelName converts a Kubernetes label key into the form used by
+// Prometheus service discovery meta labels.
+func sanitizeLabelName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNewPodMonitor(t *testing.T) {
+	obj := NewPodMonitor(client.ObjectKey{Namespace: "ns", Name: "example-vttablet"}, &PodMonitorSpec{
+		Labels:         map[string]string{"release": "prometheus"},
+		Selector:       map[string]string{"planetscale.com/component": "vttablet"},
+		Ports:          []string{"web", "metrics"},
+		ScrapeInterval: "30s",
+	})
+
+	// Unstructured content must only contain JSON-compatible types,
+	// or DeepCopy will panic when the object is reconciled.
+	cpy := obj.DeepCopy()
+	if !equality.Semantic.DeepEqual(obj, cpy) {
+		t.Errorf("DeepCopy() = %#v; want %#v", cpy, obj)
+	}
+
+	endpoints, _, err := unstructured.NestedSlice(obj.Object, "spec", "podMetricsEndpoints")
+	if err != nil {
+		t.Fatalf("NestedSlice() error: %v", err)
+	}
+	if got, want := len(endpoints), 2; got != want {
+		t.Errorf("len(podMetricsEndpoints) = %v; want %v", got, want)
+	}
+	if got, want := obj.GetLabels()["release"], "prometheus"; got != want {
+		t.Errorf("labels[release] = %q; want %q", got, want)
+	}
+}
+
+func TestSanitizeLabelName(t *testing.T) {
+	if got, want := sanitizeLabelName("planetscale.com/tablet-type"), "planetscale_com_tablet_type"; got != want {
+		t.Errorf("sanitizeLabelName() = %q; want %q", got, want)
+	}
+}
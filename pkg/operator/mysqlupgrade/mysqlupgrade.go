@@ -0,0 +1,68 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package mysqlupgrade defines the annotation that controls orchestrated
+MySQL major version upgrades of a VitessShard.
+
+The VitessShard controller starts an upgrade on its own when the mysqld
+image flavor changes. While the annotation is present, the controller
+instead rolls back any replicas that were already upgraded, and then
+leaves the shard on the old version. Removing the annotation resumes the
+upgrade from the beginning.
+*/
+package mysqlupgrade
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AbortAnnotation is the annotation whose presence requests that an
+// upgrade of the annotated VitessShard be aborted.
+const AbortAnnotation = "planetscale.com/abort-mysql-upgrade"
+
+// AbortRequested returns whether the object has the abort annotation.
+func AbortRequested(obj metav1.Object) bool {
+	_, present := obj.GetAnnotations()[AbortAnnotation]
+	return present
+}
+
+/*
+Abort annotates an object to request that its upgrade be aborted.
+Note that this only mutates the provided, in-memory object to add the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func Abort(obj metav1.Object, reason string) {
+	ann := obj.GetAnnotations()
+	if ann == nil {
+		ann = make(map[string]string, 1)
+	}
+	ann[AbortAnnotation] = reason
+	obj.SetAnnotations(ann)
+}
+
+/*
+Resume removes the abort annotation.
+Note that this only mutates the provided, in-memory object to remove the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func Resume(obj metav1.Object) {
+	ann := obj.GetAnnotations()
+	delete(ann, AbortAnnotation)
+	obj.SetAnnotations(ann)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlusers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	"planetscale.dev/vitess-operator/pkg/operator/contenthash"
+)
+
+// ExporterUser is the MySQL account that mysqld-exporter connects as.
+const ExporterUser = "vt_exporter"
+
+// exporterMaxConnections caps the connections of the exporter account, as
+// recommended by mysqld_exporter, so a slow MySQL doesn't pile up scrapes.
+const exporterMaxConnections = 3
+
+// exporterGrants are the privileges that the default mysqld_exporter
+// collectors need. Unlike vt_dba and vt_monitoring, the account can't change
+// anything, or read the tables of user databases.
+var exporterGrants = []struct {
+	privileges string
+	on         string
+}{
+	{privileges: "PROCESS, REPLICATION CLIENT", on: "*.*"},
+	{privileges: "SELECT", on: "performance_schema.*"},
+	{privileges: "SELECT", on: "sys.*"},
+	{privileges: "SELECT", on: "_vt.*"},
+}
+
+// GeneratePassword returns a new random password.
+func GeneratePassword() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ExporterPasswordHash returns a hash of the password of the exporter account.
+func ExporterPasswordHash(password string) string {
+	return contenthash.StringMap(map[string]string{ExporterUser: password})
+}
+
+// ExporterStatements returns the statements that create the exporter account
+// with the given password, or set the password if it already exists.
+func ExporterStatements(password string) []Statement {
+	account := fmt.Sprintf("%v@%v", sqltypes.EncodeStringSQL(ExporterUser), sqltypes.EncodeStringSQL("localhost"))
+	encodedPassword := sqltypes.EncodeStringSQL(password)
+
+	statements := []Statement{
+		{
+			SQL:         fmt.Sprintf("CREATE USER IF NOT EXISTS %v IDENTIFIED BY %v WITH MAX_USER_CONNECTIONS %d", account, encodedPassword, exporterMaxConnections),
+			Description: fmt.Sprintf("create user %v", ExporterUser),
+		},
+		{
+			SQL:         fmt.Sprintf("ALTER USER %v IDENTIFIED BY %v", account, encodedPassword),
+			Description: fmt.Sprintf("set password of user %v", ExporterUser),
+		},
+	}
+	for _, grant := range exporterGrants {
+		statements = append(statements, Statement{
+			SQL:         fmt.Sprintf("GRANT %v ON %v TO %v", grant.privileges, grant.on, account),
+			Description: fmt.Sprintf("grant %v on %v to %v", grant.privileges, grant.on, ExporterUser),
+		})
+	}
+	return statements
+}
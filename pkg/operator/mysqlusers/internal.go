@@ -0,0 +1,114 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlusers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	"planetscale.dev/vitess-operator/pkg/operator/contenthash"
+)
+
+// internalAccounts are the internal accounts whose passwords can be set, and
+// the hosts they're created for by init_db.sql.
+var internalAccounts = map[string]string{
+	"vt_app":      "localhost",
+	"vt_filtered": "localhost",
+	"vt_repl":     "%",
+}
+
+// InternalPasswords returns the passwords of the internal accounts from the
+// data of a Secret, ignoring any other keys.
+func InternalPasswords(data map[string][]byte) map[string]string {
+	passwords := map[string]string{}
+	for user := range internalAccounts {
+		if password, ok := data[user]; ok {
+			passwords[user] = string(password)
+		}
+	}
+	return passwords
+}
+
+// InternalPasswordsHash returns a hash of the passwords of the internal
+// accounts.
+func InternalPasswordsHash(passwords map[string]string) string {
+	return contenthash.StringMap(passwords)
+}
+
+// CredentialsFile returns the content of a file for the Vitess
+// db-credentials-file flag, with the passwords of the internal accounts.
+func CredentialsFile(passwords map[string]string) []byte {
+	credentials := make(map[string][]string, len(passwords))
+	for user, password := range passwords {
+		credentials[user] = []string{password}
+	}
+	data, _ := json.Marshal(credentials)
+	return data
+}
+
+// InternalAccounts returns the sorted internal accounts that have passwords.
+func InternalAccounts(passwords map[string]string) []string {
+	return sortedUsers(passwords)
+}
+
+// SetInternalPasswords returns the statements that set the passwords of the
+// internal accounts. The current passwords of the accounts listed in retain
+// are kept as MySQL 8 secondary passwords, which requires them not to be
+// empty.
+func SetInternalPasswords(passwords map[string]string, retain []string) []Statement {
+	var statements []Statement
+	for _, user := range sortedUsers(passwords) {
+		sql := fmt.Sprintf("ALTER USER %v IDENTIFIED BY %v", internalAccountSQL(user), sqltypes.EncodeStringSQL(passwords[user]))
+		if contains(retain, user) {
+			sql += " RETAIN CURRENT PASSWORD"
+		}
+		statements = append(statements, Statement{
+			SQL:         sql,
+			Description: fmt.Sprintf("set password of internal user %v", user),
+		})
+	}
+	return statements
+}
+
+// DiscardOldInternalPasswords returns the statements that discard the
+// secondary passwords of the given internal accounts.
+func DiscardOldInternalPasswords(accounts []string) []Statement {
+	var statements []Statement
+	for _, user := range accounts {
+		statements = append(statements, Statement{
+			SQL:         fmt.Sprintf("ALTER USER %v DISCARD OLD PASSWORD", internalAccountSQL(user)),
+			Description: fmt.Sprintf("discard old password of internal user %v", user),
+		})
+	}
+	return statements
+}
+
+func internalAccountSQL(user string) string {
+	return fmt.Sprintf("%v@%v", sqltypes.EncodeStringSQL(user), sqltypes.EncodeStringSQL(internalAccounts[user]))
+}
+
+func sortedUsers(passwords map[string]string) []string {
+	users := make([]string, 0, len(passwords))
+	for user := range passwords {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	return users
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package mysqlusers turns declared MySQL users into the SQL statements that
+create, update and drop them, and does the same for the passwords of the
+internal accounts that Vitess uses.
+*/
+package mysqlusers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/contenthash"
+)
+
+var (
+	privilegePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z _]*$`)
+	levelPattern     = regexp.MustCompile("^[A-Za-z0-9_$*`.-]+$")
+)
+
+// Statement is a SQL statement that applies part of the declared users.
+type Statement struct {
+	// SQL is the statement to execute. It may contain passwords, so it must
+	// not be logged.
+	SQL string
+	// Description describes the statement without any passwords.
+	Description string
+	// Optional statements may fail without failing the whole change. That's
+	// the case for revoking privileges, which fails if they were already
+	// revoked by other means.
+	Optional bool
+}
+
+// Account returns the account name of a user, as it's used as the key of the
+// passwords given to Statements.
+func Account(user *planetscalev2.VitessMySQLUser) string {
+	return fmt.Sprintf("%v@%v", user.Name, user.HostPattern())
+}
+
+// Hash returns a hash of the declared users and their passwords.
+func Hash(users []planetscalev2.VitessMySQLUser, passwords map[string]string) string {
+	spec, _ := json.Marshal(users)
+	return contenthash.StringList([]string{string(spec), contenthash.StringMap(passwords)})
+}
+
+// Validate checks that the declared users can be safely turned into SQL.
+func Validate(users []planetscalev2.VitessMySQLUser) error {
+	seen := map[string]bool{}
+	for i := range users {
+		user := &users[i]
+		account := Account(user)
+		if user.Name == "" {
+			return fmt.Errorf("user %d has no name", i)
+		}
+		if seen[account] {
+			return fmt.Errorf("user %v is declared more than once", account)
+		}
+		seen[account] = true
+		for _, grant := range user.Grants {
+			if !levelPattern.MatchString(grant.Level()) {
+				return fmt.Errorf("user %v: invalid grant level %q", account, grant.Level())
+			}
+			for _, privilege := range grant.Privileges {
+				if !privilegePattern.MatchString(privilege) {
+					return fmt.Errorf("user %v: invalid privilege %q", account, privilege)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Statements returns the statements that turn the users that were applied
+// before into the desired ones. Passwords are keyed by Account.
+func Statements(applied, desired []planetscalev2.VitessMySQLUser, passwords map[string]string) []Statement {
+	var statements []Statement
+
+	desiredAccounts := map[string]bool{}
+	for i := range desired {
+		desiredAccounts[Account(&desired[i])] = true
+	}
+	appliedUsers := map[string]*planetscalev2.VitessMySQLUser{}
+	for i := range applied {
+		user := &applied[i]
+		account := Account(user)
+		if !desiredAccounts[account] {
+			statements = append(statements, Statement{
+				SQL:         fmt.Sprintf("DROP USER IF EXISTS %v", accountSQL(user)),
+				Description: fmt.Sprintf("drop user %v", account),
+			})
+			continue
+		}
+		appliedUsers[account] = user
+	}
+
+	for i := range desired {
+		user := &desired[i]
+		account := Account(user)
+		password := sqltypes.EncodeStringSQL(passwords[account])
+		statements = append(statements,
+			Statement{
+				SQL:         fmt.Sprintf("CREATE USER IF NOT EXISTS %v IDENTIFIED BY %v", accountSQL(user), password),
+				Description: fmt.Sprintf("create user %v", account),
+			},
+			Statement{
+				SQL:         fmt.Sprintf("ALTER USER %v IDENTIFIED BY %v", accountSQL(user), password),
+				Description: fmt.Sprintf("set password of user %v", account),
+			},
+		)
+
+		want := privilegesByLevel(user)
+		if prev := appliedUsers[account]; prev != nil {
+			had := privilegesByLevel(prev)
+			for _, level := range sortedKeys(had) {
+				var revoke []string
+				for _, privilege := range had[level] {
+					if !contains(want[level], privilege) {
+						revoke = append(revoke, privilege)
+					}
+				}
+				if len(revoke) == 0 {
+					continue
+				}
+				statements = append(statements, Statement{
+					SQL:         fmt.Sprintf("REVOKE %v ON %v FROM %v", strings.Join(revoke, ", "), level, accountSQL(user)),
+					Description: fmt.Sprintf("revoke %v on %v from user %v", strings.Join(revoke, ", "), level, account),
+					Optional:    true,
+				})
+			}
+		}
+		for _, level := range sortedKeys(want) {
+			statements = append(statements, Statement{
+				SQL:         fmt.Sprintf("GRANT %v ON %v TO %v", strings.Join(want[level], ", "), level, accountSQL(user)),
+				Description: fmt.Sprintf("grant %v on %v to user %v", strings.Join(want[level], ", "), level, account),
+			})
+		}
+	}
+
+	return statements
+}
+
+func accountSQL(user *planetscalev2.VitessMySQLUser) string {
+	return fmt.Sprintf("%v@%v", sqltypes.EncodeStringSQL(user.Name), sqltypes.EncodeStringSQL(user.HostPattern()))
+}
+
+// privilegesByLevel returns the normalized privileges of a user by the level
+// they're granted at.
+func privilegesByLevel(user *planetscalev2.VitessMySQLUser) map[string][]string {
+	levels := map[string][]string{}
+	for _, grant := range user.Grants {
+		level := grant.Level()
+		for _, privilege := range grant.Privileges {
+			privilege = strings.ToUpper(strings.Join(strings.Fields(privilege), " "))
+			if !contains(levels[level], privilege) {
+				levels[level] = append(levels[level], privilege)
+			}
+		}
+	}
+	return levels
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
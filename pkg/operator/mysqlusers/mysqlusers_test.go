@@ -0,0 +1,138 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlusers
+
+import (
+	"reflect"
+	"testing"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestStatements(t *testing.T) {
+	applied := []planetscalev2.VitessMySQLUser{
+		{Name: "app", Grants: []planetscalev2.VitessMySQLGrant{{Privileges: []string{"SELECT", "INSERT", "DELETE"}, On: "commerce.*"}}},
+		{Name: "old", Host: "10.%"},
+	}
+	desired := []planetscalev2.VitessMySQLUser{
+		{Name: "app", Grants: []planetscalev2.VitessMySQLGrant{{Privileges: []string{"select", "insert"}, On: "commerce.*"}}},
+	}
+	passwords := map[string]string{"app@%": "it's secret"}
+
+	var got []string
+	for _, statement := range Statements(applied, desired, passwords) {
+		got = append(got, statement.SQL)
+	}
+	want := []string{
+		"DROP USER IF EXISTS 'old'@'10.%'",
+		"CREATE USER IF NOT EXISTS 'app'@'%' IDENTIFIED BY 'it\\'s secret'",
+		"ALTER USER 'app'@'%' IDENTIFIED BY 'it\\'s secret'",
+		"REVOKE DELETE ON commerce.* FROM 'app'@'%'",
+		"GRANT SELECT, INSERT ON commerce.* TO 'app'@'%'",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements() = %q; want %q", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	table := []struct {
+		name    string
+		users   []planetscalev2.VitessMySQLUser
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			users: []planetscalev2.VitessMySQLUser{{Name: "app", Grants: []planetscalev2.VitessMySQLGrant{{Privileges: []string{"ALL PRIVILEGES"}, On: "`my-db`.*"}}}},
+		},
+		{
+			name:    "duplicate",
+			users:   []planetscalev2.VitessMySQLUser{{Name: "app"}, {Name: "app", Host: "%"}},
+			wantErr: true,
+		},
+		{
+			name:    "injected privilege",
+			users:   []planetscalev2.VitessMySQLUser{{Name: "app", Grants: []planetscalev2.VitessMySQLGrant{{Privileges: []string{"SELECT ON *.* TO 'root'@'%'; --"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "injected level",
+			users:   []planetscalev2.VitessMySQLUser{{Name: "app", Grants: []planetscalev2.VitessMySQLGrant{{Privileges: []string{"SELECT"}, On: "*.* TO 'x'"}}}},
+			wantErr: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Validate(test.users); (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v; want error: %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetInternalPasswords(t *testing.T) {
+	passwords := InternalPasswords(map[string][]byte{
+		"vt_repl": []byte("repl"),
+		"vt_app":  []byte("app"),
+		"vt_dba":  []byte("ignored"),
+	})
+
+	var got []string
+	for _, statement := range SetInternalPasswords(passwords, []string{"vt_app"}) {
+		got = append(got, statement.SQL)
+	}
+	for _, statement := range DiscardOldInternalPasswords([]string{"vt_app"}) {
+		got = append(got, statement.SQL)
+	}
+	want := []string{
+		"ALTER USER 'vt_app'@'localhost' IDENTIFIED BY 'app' RETAIN CURRENT PASSWORD",
+		"ALTER USER 'vt_repl'@'%' IDENTIFIED BY 'repl'",
+		"ALTER USER 'vt_app'@'localhost' DISCARD OLD PASSWORD",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("statements = %q; want %q", got, want)
+	}
+	if got, want := string(CredentialsFile(passwords)), `{"vt_app":["app"],"vt_repl":["repl"]}`; got != want {
+		t.Errorf("CredentialsFile() = %v; want %v", got, want)
+	}
+}
+
+func TestExporterStatements(t *testing.T) {
+	var got []string
+	for _, statement := range ExporterStatements("it's secret") {
+		got = append(got, statement.SQL)
+	}
+	want := []string{
+		"CREATE USER IF NOT EXISTS 'vt_exporter'@'localhost' IDENTIFIED BY 'it\\'s secret' WITH MAX_USER_CONNECTIONS 3",
+		"ALTER USER 'vt_exporter'@'localhost' IDENTIFIED BY 'it\\'s secret'",
+		"GRANT PROCESS, REPLICATION CLIENT ON *.* TO 'vt_exporter'@'localhost'",
+		"GRANT SELECT ON performance_schema.* TO 'vt_exporter'@'localhost'",
+		"GRANT SELECT ON sys.* TO 'vt_exporter'@'localhost'",
+		"GRANT SELECT ON _vt.* TO 'vt_exporter'@'localhost'",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExporterStatements() = %q; want %q", got, want)
+	}
+
+	password, err := GeneratePassword()
+	if err != nil {
+		t.Fatalf("GeneratePassword() error: %v", err)
+	}
+	if other, _ := GeneratePassword(); len(password) != 32 || other == password {
+		t.Errorf("GeneratePassword() = %q, %q; want two different 32-character passwords", password, other)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package networkpolicy generates NetworkPolicies that only let in the traffic
+that each Vitess component needs.
+*/
+package networkpolicy
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/etcd"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// Components lists the components that get a NetworkPolicy.
+var Components = []string{
+	planetscalev2.VtgateComponentName,
+	planetscalev2.VttabletComponentName,
+	planetscalev2.VtbackupComponentName,
+	planetscalev2.VtctldComponentName,
+	planetscalev2.VtorcComponentName,
+	planetscalev2.VtadminComponentName,
+	planetscalev2.EtcdComponentName,
+}
+
+// Name returns the name of the NetworkPolicy for a component of a cluster.
+func Name(clusterName, componentName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, componentName)
+}
+
+// Spec specifies the parameters of a NetworkPolicy for one component.
+type Spec struct {
+	ClusterName   string
+	ComponentName string
+	Labels        map[string]string
+	NetworkPolicy *planetscalev2.NetworkPolicySpec
+	// PeerNamespaces are other namespaces in which some of the cluster's
+	// components run, like vtgates of cells deployed to another namespace.
+	PeerNamespaces []string
+}
+
+// NewNetworkPolicy creates a new NetworkPolicy object from a Spec.
+func NewNetworkPolicy(key client.ObjectKey, spec *Spec) *networkingv1.NetworkPolicy {
+	obj := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+	}
+	UpdateNetworkPolicy(obj, spec)
+	return obj
+}
+
+// UpdateNetworkPolicy updates the mutable parts of a NetworkPolicy.
+func UpdateNetworkPolicy(obj *networkingv1.NetworkPolicy, spec *Spec) {
+	update.Labels(&obj.Labels, spec.Labels)
+
+	obj.Spec.PodSelector = metav1.LabelSelector{MatchLabels: componentLabels(spec.ClusterName, spec.ComponentName)}
+	obj.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	obj.Spec.Ingress = ingressRules(spec)
+}
+
+// ingressRules returns the traffic that a component must let in.
+// An empty list denies all incoming traffic.
+//
+// Applications connect to vtgate's MySQL port. Unless AllowedCIDRs narrows
+// down who they are, that port is open to everyone, so enabling
+// NetworkPolicies doesn't cut applications off from the database.
+func ingressRules(spec *Spec) []networkingv1.NetworkPolicyIngressRule {
+	np := spec.NetworkPolicy
+	operator := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: np.OperatorPodLabels},
+	}
+	allowed := make([]networkingv1.NetworkPolicyPeer, 0, len(np.AllowedCIDRs))
+	for _, cidr := range np.AllowedCIDRs {
+		allowed = append(allowed, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+
+	var rules []networkingv1.NetworkPolicyIngressRule
+	switch spec.ComponentName {
+	case planetscalev2.VtgateComponentName:
+		rules = []networkingv1.NetworkPolicyIngressRule{
+			rule(peers(spec, planetscalev2.VtadminComponentName), planetscalev2.DefaultWebPortName, planetscalev2.DefaultGrpcPortName),
+			// The operator reads vtgate's /debug/vars.
+			rule([]networkingv1.NetworkPolicyPeer{operator}, planetscalev2.DefaultWebPortName),
+			rule(allowed, planetscalev2.DefaultWebPortName, planetscalev2.DefaultGrpcPortName, planetscalev2.DefaultMysqlPortName),
+		}
+	case planetscalev2.VttabletComponentName:
+		rpcClients := peers(spec,
+			planetscalev2.VtgateComponentName,
+			planetscalev2.VttabletComponentName,
+			planetscalev2.VtctldComponentName,
+			planetscalev2.VtorcComponentName,
+		)
+		rules = []networkingv1.NetworkPolicyIngressRule{
+			rule(append(rpcClients, operator), planetscalev2.DefaultWebPortName, planetscalev2.DefaultGrpcPortName),
+			// Replicas and vtbackup replicate from the primary's mysqld.
+			rule(peers(spec, planetscalev2.VttabletComponentName, planetscalev2.VtbackupComponentName), planetscalev2.DefaultMysqlPortName),
+			rule(allowed, planetscalev2.DefaultWebPortName, vttablet.MysqldExporterPortName),
+		}
+	case planetscalev2.VtctldComponentName:
+		rules = []networkingv1.NetworkPolicyIngressRule{
+			rule(append(peers(spec, planetscalev2.VtadminComponentName), operator), planetscalev2.DefaultWebPortName, planetscalev2.DefaultGrpcPortName),
+			rule(allowed, planetscalev2.DefaultWebPortName, planetscalev2.DefaultGrpcPortName),
+		}
+	case planetscalev2.VtorcComponentName:
+		rules = []networkingv1.NetworkPolicyIngressRule{
+			rule(allowed, planetscalev2.DefaultWebPortName),
+		}
+	case planetscalev2.VtadminComponentName:
+		rules = []networkingv1.NetworkPolicyIngressRule{
+			rule(allowed, planetscalev2.DefaultWebPortName, planetscalev2.DefaultAPIPortName),
+		}
+	case planetscalev2.EtcdComponentName:
+		topoClients := peers(spec,
+			planetscalev2.VtgateComponentName,
+			planetscalev2.VttabletComponentName,
+			planetscalev2.VtbackupComponentName,
+			planetscalev2.VtctldComponentName,
+			planetscalev2.VtorcComponentName,
+			planetscalev2.VtadminComponentName,
+		)
+		rules = []networkingv1.NetworkPolicyIngressRule{
+			rule(append(topoClients, operator), etcd.ClientPortName),
+			rule(peers(spec, planetscalev2.EtcdComponentName), etcd.PeerPortName),
+			rule(allowed, etcd.ClientPortName),
+		}
+	}
+
+	// A rule with no peers would let in traffic from anywhere.
+	result := make([]networkingv1.NetworkPolicyIngressRule, 0, len(rules)+1)
+	for _, r := range rules {
+		if len(r.From) > 0 {
+			result = append(result, r)
+		}
+	}
+	if spec.ComponentName == planetscalev2.VtgateComponentName && len(allowed) == 0 {
+		result = append(result, rule(nil, planetscalev2.DefaultMysqlPortName))
+	}
+	return result
+}
+
+// componentLabels returns the labels that select a component's Pods.
+func componentLabels(clusterName, componentName string) map[string]string {
+	return map[string]string{
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.ComponentLabel: componentName,
+	}
+}
+
+// peers returns peers that select the Pods of the given components in the
+// same namespace, in the cluster's peer namespaces, and in the namespaces
+// matched by the peer namespace selector.
+func peers(spec *Spec, componentNames ...string) []networkingv1.NetworkPolicyPeer {
+	var namespaceSelectors []*metav1.LabelSelector
+	if len(spec.PeerNamespaces) > 0 {
+		namespaceSelectors = append(namespaceSelectors, &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      corev1.LabelMetadataName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   spec.PeerNamespaces,
+				},
+			},
+		})
+	}
+	if spec.NetworkPolicy.PeerNamespaceSelector != nil {
+		namespaceSelectors = append(namespaceSelectors, spec.NetworkPolicy.PeerNamespaceSelector)
+	}
+
+	result := make([]networkingv1.NetworkPolicyPeer, 0, len(componentNames)*(1+len(namespaceSelectors)))
+	for _, componentName := range componentNames {
+		result = append(result, networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{MatchLabels: componentLabels(spec.ClusterName, componentName)},
+		})
+		for _, namespaceSelector := range namespaceSelectors {
+			result = append(result, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: namespaceSelector,
+				PodSelector:       &metav1.LabelSelector{MatchLabels: componentLabels(spec.ClusterName, componentName)},
+			})
+		}
+	}
+	return result
+}
+
+// rule returns a rule that lets the given peers in on the given named TCP
+// ports. Using named ports means each Pod can decide what port numbers to use.
+func rule(from []networkingv1.NetworkPolicyPeer, portNames ...string) networkingv1.NetworkPolicyIngressRule {
+	protocol := corev1.ProtocolTCP
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(portNames))
+	for _, portName := range portNames {
+		port := intstr.FromString(portName)
+		policyPorts = append(policyPorts, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+	return networkingv1.NetworkPolicyIngressRule{From: from, Ports: policyPorts}
+}
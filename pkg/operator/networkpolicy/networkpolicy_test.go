@@ -0,0 +1,152 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestIngressRules(t *testing.T) {
+	np := &planetscalev2.NetworkPolicySpec{
+		Enabled:           true,
+		OperatorPodLabels: map[string]string{"app": "vitess-operator"},
+	}
+
+	// Without allowed CIDRs, there must be no rule without peers, since
+	// that would let in traffic from anywhere. Only vtgate's MySQL port is
+	// open to everyone, which is checked below.
+	for _, component := range Components {
+		if component == planetscalev2.VtgateComponentName {
+			continue
+		}
+		for _, r := range ingressRules(&Spec{ClusterName: "example", ComponentName: component, NetworkPolicy: np}) {
+			if len(r.From) == 0 {
+				t.Errorf("ingressRules(%v) has a rule without peers: %v", component, r)
+			}
+		}
+	}
+	if rules := ingressRules(&Spec{ClusterName: "example", ComponentName: planetscalev2.VtbackupComponentName, NetworkPolicy: np}); len(rules) != 0 {
+		t.Errorf("ingressRules(vtbackup) = %v; want none", rules)
+	}
+
+	np.AllowedCIDRs = []string{"10.0.0.0/8", "fd00::/8"}
+	rules := ingressRules(&Spec{ClusterName: "example", ComponentName: planetscalev2.VtgateComponentName, NetworkPolicy: np})
+	if got, want := len(rules), 3; got != want {
+		t.Fatalf("len(ingressRules(vtgate)) = %v; want %v", got, want)
+	}
+	if got, want := len(rules[2].From), 2; got != want {
+		t.Errorf("len(ingressRules(vtgate)[2].From) = %v; want %v", got, want)
+	}
+}
+
+func TestIngressRulesVtgateWithoutCIDRs(t *testing.T) {
+	np := &planetscalev2.NetworkPolicySpec{
+		Enabled:           true,
+		OperatorPodLabels: map[string]string{"app": "vitess-operator"},
+	}
+
+	// Applications must still reach vtgate's MySQL port, and only that port.
+	var open []networkingv1.NetworkPolicyIngressRule
+	for _, r := range ingressRules(&Spec{ClusterName: "example", ComponentName: planetscalev2.VtgateComponentName, NetworkPolicy: np}) {
+		if len(r.From) == 0 {
+			open = append(open, r)
+		}
+	}
+	if len(open) != 1 {
+		t.Fatalf("ingressRules(vtgate) has %v rules open to everyone; want 1", len(open))
+	}
+	if ports := open[0].Ports; len(ports) != 1 || ports[0].Port.StrVal != planetscalev2.DefaultMysqlPortName {
+		t.Errorf("ingressRules(vtgate) open ports = %v; want only %v", ports, planetscalev2.DefaultMysqlPortName)
+	}
+}
+
+func TestIngressRulesOperator(t *testing.T) {
+	np := &planetscalev2.NetworkPolicySpec{
+		Enabled:           true,
+		OperatorPodLabels: map[string]string{"app": "vitess-operator"},
+	}
+
+	// The operator calls vtgate, vtctld, vttablet and etcd.
+	for _, component := range []string{
+		planetscalev2.VtgateComponentName,
+		planetscalev2.VtctldComponentName,
+		planetscalev2.VttabletComponentName,
+		planetscalev2.EtcdComponentName,
+	} {
+		found := false
+		for _, r := range ingressRules(&Spec{ClusterName: "example", ComponentName: component, NetworkPolicy: np}) {
+			for _, peer := range r.From {
+				if peer.PodSelector != nil && peer.PodSelector.MatchLabels["app"] == "vitess-operator" {
+					found = found || peer.NamespaceSelector != nil
+				}
+			}
+		}
+		if !found {
+			t.Errorf("ingressRules(%v) doesn't let in the operator from any namespace", component)
+		}
+	}
+}
+
+func TestIngressRulesPeerNamespaces(t *testing.T) {
+	np := &planetscalev2.NetworkPolicySpec{
+		Enabled:               true,
+		OperatorPodLabels:     map[string]string{"app": "vitess-operator"},
+		PeerNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"federation": "example"}},
+	}
+	spec := &Spec{
+		ClusterName:    "example",
+		ComponentName:  planetscalev2.VttabletComponentName,
+		NetworkPolicy:  np,
+		PeerNamespaces: []string{"gateways"},
+	}
+
+	// vtgates in the peer namespace and in namespaces matched by the
+	// selector must be let in, with the cluster's labels.
+	var gotNamespaces, gotSelector bool
+	for _, r := range ingressRules(spec) {
+		for _, peer := range r.From {
+			if peer.PodSelector == nil || peer.PodSelector.MatchLabels[planetscalev2.ComponentLabel] != planetscalev2.VtgateComponentName {
+				continue
+			}
+			if got, want := peer.PodSelector.MatchLabels[planetscalev2.ClusterLabel], "example"; got != want {
+				t.Errorf("vtgate peer cluster label = %q; want %q", got, want)
+			}
+			switch {
+			case peer.NamespaceSelector == nil:
+			case peer.NamespaceSelector == np.PeerNamespaceSelector:
+				gotSelector = true
+			case len(peer.NamespaceSelector.MatchExpressions) == 1:
+				expr := peer.NamespaceSelector.MatchExpressions[0]
+				if expr.Key == corev1.LabelMetadataName && len(expr.Values) == 1 && expr.Values[0] == "gateways" {
+					gotNamespaces = true
+				}
+			}
+		}
+	}
+	if !gotNamespaces {
+		t.Errorf("ingressRules() doesn't let in vtgates from peer namespace %v", spec.PeerNamespaces)
+	}
+	if !gotSelector {
+		t.Errorf("ingressRules() doesn't let in vtgates from namespaces matching %v", np.PeerNamespaceSelector)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+const (
+	metricsSubsystemName = "notification"
+
+	// sentOutcome means the webhook accepted the notification.
+	sentOutcome = "sent"
+	// droppedOutcome means the notification was dropped because the queue
+	// was full.
+	droppedOutcome = "dropped"
+	// failedOutcome means sending the notification failed.
+	failedOutcome = "failed"
+)
+
+var (
+	notificationCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "count",
+		Help:      "Notifications handled, by event reason and outcome",
+	}, []string{"reason", "outcome"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		notificationCount,
+	)
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package notify calls a webhook for significant events, like a shard losing its
+primary or a backup failing, so teams without full Prometheus alerting still
+hear about them.
+
+A Notifier wraps the event recorders of all controllers, and for each Event
+whose reason was selected, posts a notification in the background. The same
+notification is sent at most once per dedupeWindow, since controllers may
+report the same event on several reconciles in a row.
+*/
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// SlackFormat posts notifications as Slack-compatible messages, with a
+	// "text" field, which many chat tools accept.
+	SlackFormat = "slack"
+	// JSONFormat posts notifications as Notification objects.
+	JSONFormat = "json"
+
+	// queueSize is how many notifications can wait to be sent before new
+	// ones are dropped.
+	queueSize = 100
+	// sendTimeout is how long we wait for the webhook to respond.
+	sendTimeout = 10 * time.Second
+	// dedupeWindow is how long the same notification isn't sent again.
+	dedupeWindow = 10 * time.Minute
+)
+
+// DefaultReasons are the Event reasons that trigger notifications, unless
+// others are configured.
+var DefaultReasons = []string{
+	"PrimaryChanged",
+	"PrimaryLost",
+	"QuorumLost",
+	"BackupFailed",
+	"RollingRestartComplete",
+}
+
+var log = logrus.WithField("component", "notify")
+
+// Notification is the payload posted in JSONFormat.
+type Notification struct {
+	// Time is when the event happened.
+	Time time.Time `json:"time"`
+	// Type is the type of the event, Normal or Warning.
+	Type string `json:"type"`
+	// Reason is the reason of the event, such as "PrimaryLost".
+	Reason string `json:"reason"`
+	// Message is the human-readable message of the event.
+	Message string `json:"message"`
+	// Kind, Namespace and Name identify the object the event is about.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Cluster is the name of the VitessCluster the object belongs to.
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// Notifier posts notifications to a webhook.
+type Notifier struct {
+	url     string
+	format  string
+	reasons map[string]bool
+	scheme  *runtime.Scheme
+	client  *http.Client
+	now     func() time.Time
+
+	notifications chan *Notification
+
+	mu sync.Mutex
+	// sent records when each notification was last queued, for those
+	// queued less than dedupeWindow ago.
+	sent map[string]time.Time
+}
+
+/*
+Wrap returns a manager whose event recorders also post a notification to url
+for each event with one of the given reasons, in the given format.
+
+The notifications are sent by a runnable added to mgr, which only runs on the
+leader.
+*/
+func Wrap(mgr manager.Manager, url, format string, reasons []string) (manager.Manager, error) {
+	n, err := newNotifier(mgr.GetScheme(), url, format, reasons)
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(n); err != nil {
+		return nil, err
+	}
+	return &notifyManager{Manager: mgr, notifier: n}, nil
+}
+
+func newNotifier(scheme *runtime.Scheme, url, format string, reasons []string) (*Notifier, error) {
+	if format != SlackFormat && format != JSONFormat {
+		return nil, fmt.Errorf("invalid notification format %q; expected %q or %q", format, SlackFormat, JSONFormat)
+	}
+	n := &Notifier{
+		url:           url,
+		format:        format,
+		reasons:       make(map[string]bool, len(reasons)),
+		scheme:        scheme,
+		client:        &http.Client{Timeout: sendTimeout},
+		now:           time.Now,
+		notifications: make(chan *Notification, queueSize),
+		sent:          make(map[string]time.Time),
+	}
+	for _, reason := range reasons {
+		n.reasons[reason] = true
+	}
+	return n, nil
+}
+
+// notifyManager wraps a manager to hand out notifying event recorders.
+type notifyManager struct {
+	manager.Manager
+	notifier *Notifier
+}
+
+func (m *notifyManager) GetEventRecorderFor(name string) record.EventRecorder {
+	return m.notifier.Recorder(m.Manager.GetEventRecorderFor(name))
+}
+
+// Recorder returns an event recorder that passes events on to r, and also
+// sends notifications for them.
+func (n *Notifier) Recorder(r record.EventRecorder) record.EventRecorder {
+	return &notifyRecorder{EventRecorder: r, notifier: n}
+}
+
+// notifyRecorder wraps an event recorder to send notifications.
+type notifyRecorder struct {
+	record.EventRecorder
+	notifier *Notifier
+}
+
+func (r *notifyRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.notifier.notify(object, eventtype, reason, message)
+}
+
+func (r *notifyRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.notifier.notify(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *notifyRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.notifier.notify(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// notify queues a notification for an event, if its reason was selected and
+// the same notification wasn't queued recently. Notifications are dropped
+// rather than blocking the controller if the queue is full.
+func (n *Notifier) notify(object runtime.Object, eventtype, reason, message string) {
+	if !n.reasons[reason] {
+		return
+	}
+	objMeta, err := meta.Accessor(object)
+	if err != nil {
+		log.WithError(err).Warning("not sending notification")
+		return
+	}
+	gvk, err := apiutil.GVKForObject(object, n.scheme)
+	if err != nil {
+		log.WithError(err).Warning("not sending notification")
+		return
+	}
+	notification := &Notification{
+		Time:      n.now(),
+		Type:      eventtype,
+		Reason:    reason,
+		Message:   message,
+		Kind:      gvk.Kind,
+		Namespace: objMeta.GetNamespace(),
+		Name:      objMeta.GetName(),
+		Cluster:   objMeta.GetLabels()[planetscalev2.ClusterLabel],
+	}
+	if gvk.Kind == planetscalev2.VitessClusterKind {
+		notification.Cluster = objMeta.GetName()
+	}
+	if !n.firstInWindow(notification) {
+		return
+	}
+	select {
+	case n.notifications <- notification:
+	default:
+		log.WithField("reason", reason).Warning("notification queue is full; dropping notification")
+		notificationCount.WithLabelValues(reason, droppedOutcome).Inc()
+	}
+}
+
+// firstInWindow returns whether the same notification wasn't queued in the
+// last dedupeWindow, in which case it's recorded as queued now.
+func (n *Notifier) firstInWindow(notification *Notification) bool {
+	key := fmt.Sprintf("%v/%v/%v/%v/%v", notification.Kind, notification.Namespace, notification.Name, notification.Reason, notification.Message)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := notification.Time
+	if last, ok := n.sent[key]; ok && now.Sub(last) < dedupeWindow {
+		return false
+	}
+	n.sent[key] = now
+
+	// Forget notifications whose window has passed.
+	for k, last := range n.sent {
+		if now.Sub(last) >= dedupeWindow {
+			delete(n.sent, k)
+		}
+	}
+	return true
+}
+
+// Start sends queued notifications until ctx is done.
+func (n *Notifier) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-n.notifications:
+			if err := n.send(ctx, notification); err != nil {
+				log.WithError(err).WithField("reason", notification.Reason).Warning("failed to send notification")
+				notificationCount.WithLabelValues(notification.Reason, failedOutcome).Inc()
+				continue
+			}
+			notificationCount.WithLabelValues(notification.Reason, sentOutcome).Inc()
+		}
+	}
+}
+
+// send posts a notification to the webhook.
+func (n *Notifier) send(ctx context.Context, notification *Notification) error {
+	body, err := n.payload(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %v", resp.Status)
+	}
+	return nil
+}
+
+// payload returns the body to post for a notification.
+func (n *Notifier) payload(notification *Notification) ([]byte, error) {
+	if n.format == JSONFormat {
+		return json.Marshal(notification)
+	}
+	subject := fmt.Sprintf("%v %v/%v", notification.Kind, notification.Namespace, notification.Name)
+	if notification.Cluster != "" {
+		subject = fmt.Sprintf("%v (cluster %v)", subject, notification.Cluster)
+	}
+	text := fmt.Sprintf("*%v* on %v: %v", notification.Reason, subject, notification.Message)
+	return json.Marshal(map[string]string{"text": text})
+}
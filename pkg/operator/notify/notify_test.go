@@ -0,0 +1,103 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestNotifier(t *testing.T) {
+	received := make(chan map[string]string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		received <- payload
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	if err := planetscalev2.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	n, err := newNotifier(scheme, server.URL, SlackFormat, DefaultReasons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Start(ctx)
+
+	events := record.NewFakeRecorder(10)
+	recorder := n.Recorder(events)
+	vts := &planetscalev2.VitessShard{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "example-commerce-x-x",
+		Labels:    map[string]string{planetscalev2.ClusterLabel: "example"},
+	}}
+
+	// Only selected reasons trigger notifications, and only once per window.
+	recorder.Eventf(vts, corev1.EventTypeNormal, "Created", "created Pod %v", "p1")
+	recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryLost", "shard has no primary anymore; it was %v", "zone1-0001")
+	recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryLost", "shard has no primary anymore; it was %v", "zone1-0001")
+	now = now.Add(dedupeWindow)
+	recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryLost", "shard has no primary anymore; it was %v", "zone1-0001")
+
+	want := "*PrimaryLost* on VitessShard ns/example-commerce-x-x (cluster example): shard has no primary anymore; it was zone1-0001"
+	for i := 0; i < 2; i++ {
+		select {
+		case payload := <-received:
+			if got := payload["text"]; got != want {
+				t.Errorf("text = %q; want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %v notification(s); want 2", i)
+		}
+	}
+	select {
+	case payload := <-received:
+		t.Errorf("got unexpected notification %v", payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// All events are still passed on.
+	if len(events.Events) != 4 {
+		t.Errorf("passed on %v event(s); want 4", len(events.Events))
+	}
+}
+
+func TestInvalidFormat(t *testing.T) {
+	if _, err := newNotifier(runtime.NewScheme(), "http://example.com", "xml", DefaultReasons); err == nil {
+		t.Errorf("newNotifier() with invalid format = nil; want error")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// AdoptedAnnotation is set on objects that existed before the operator took
+// ownership of them. Its value is the time of adoption.
+const AdoptedAnnotation = planetscalev2.LabelPrefix + "/" + "adopted"
+
+type adoptingKey struct{}
+
+// NewAdoptingContext returns a copy of ctx that tells ReconcileObject and
+// ReconcileObjectSet to take ownership of existing objects that have the
+// name of a wanted object, but not our labels.
+func NewAdoptingContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adoptingKey{}, true)
+}
+
+// IsAdopting returns whether ctx was created by NewAdoptingContext.
+func IsAdopting(ctx context.Context) bool {
+	adopting, _ := ctx.Value(adoptingKey{}).(bool)
+	return adopting
+}
+
+// IsAdopted returns whether an object was adopted rather than created by us,
+// and hasn't been recreated since.
+func IsAdopted(obj metav1.Object) bool {
+	_, ok := obj.GetAnnotations()[AdoptedAnnotation]
+	return ok
+}
+
+// adopt takes ownership of an existing object by adding our labels and a
+// controller reference, without changing anything else. The remaining
+// differences from what we want are reconciled once we own it.
+func (r *Reconciler) adopt(ctx context.Context, owner runtime.Object, ownerMeta metav1.Object, labels map[string]string, curObj client.Object, curObjDesc string) error {
+	if controller := metav1.GetControllerOf(curObj); controller != nil && controller.UID != ownerMeta.GetUID() {
+		err := fmt.Errorf("%v already exists, and is controlled by %v %v", curObjDesc, controller.Kind, controller.Name)
+		r.recorder.Event(owner, corev1.EventTypeWarning, "NameCollision", err.Error())
+		return err
+	}
+
+	newObj := curObj.DeepCopyObject().(client.Object)
+	newObjMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return err
+	}
+	newLabels := newObjMeta.GetLabels()
+	if newLabels == nil {
+		newLabels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		newLabels[k] = v
+	}
+	newObjMeta.SetLabels(newLabels)
+	annotations := newObjMeta.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[AdoptedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	newObjMeta.SetAnnotations(annotations)
+	if err := r.setOwner(owner, ownerMeta, newObjMeta); err != nil {
+		r.recorder.Eventf(owner, corev1.EventTypeWarning, "AdoptFailed", "failed to adopt %v: %v", curObjDesc, err)
+		return err
+	}
+
+	if err := r.client.Update(ctx, newObj); err != nil {
+		r.recorder.Eventf(owner, corev1.EventTypeWarning, "AdoptFailed", "failed to adopt %v: %v", curObjDesc, err)
+		return err
+	}
+	r.recorder.Eventf(owner, corev1.EventTypeNormal, "Adopted", "adopted existing %v", curObjDesc)
+	return nil
+}
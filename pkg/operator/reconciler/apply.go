@@ -0,0 +1,235 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/csaupgrade"
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// FieldManager is the name the operator uses to manage the fields of the
+// objects it creates and updates.
+const FieldManager = "vitess-operator"
+
+// updateManagers are the field managers of the operator's writes from before
+// it used server-side apply. The fields they own are handed over to
+// FieldManager on the first apply, so they don't conflict with it.
+var updateManagers = sets.New(FieldManager, strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0])
+
+// typeParser has the schemas of the kinds of objects that are updated with
+// server-side apply, or is nil if server-side apply is disabled.
+var typeParser *managedfields.GvkParser
+
+/*
+EnableServerSideApply makes ReconcileObject update objects with server-side
+apply, using the schemas that the API server publishes to tell which fields
+of an object the operator sets. Kinds the API server didn't publish a schema
+for when this is called are still updated by replacing them.
+
+It must be called before any controllers are started.
+*/
+func EnableServerSideApply(cfg *rest.Config) error {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	doc, err := dc.OpenAPISchema()
+	if err != nil {
+		return fmt.Errorf("failed to get OpenAPI schema: %v", err)
+	}
+	models, err := proto.NewOpenAPIData(doc)
+	if err != nil {
+		return fmt.Errorf("failed to parse OpenAPI schema: %v", err)
+	}
+	parser, err := managedfields.NewGVKParser(models, false)
+	if err != nil {
+		return fmt.Errorf("failed to parse OpenAPI schema: %v", err)
+	}
+	typeParser = parser
+	return nil
+}
+
+// applyType returns the schema to apply objects of the given kind with, or
+// nil if they should be updated by replacing them.
+func applyType(gvk schema.GroupVersionKind) *typed.ParseableType {
+	if typeParser == nil {
+		return nil
+	}
+	objType := typeParser.Type(gvk)
+	if objType == nil || !objType.IsValid() {
+		return nil
+	}
+	return objType
+}
+
+/*
+apply writes newObj, an updated copy of curObj, with server-side apply.
+
+Only the fields the operator owns are applied: those it set before, and those
+it's changing now. Unlike an update, an apply leaves alone the fields that other
+field managers, like users or mutating webhooks, added to the object. If another
+field manager owns a field that we're changing, the conflict is reported in an
+event on the owner, and then resolved in our favor, as an update would have.
+
+The apply is conditional on the resourceVersion of curObj, so changes made
+since we read it fail the apply with a conflict, just like an update.
+*/
+func (r *Reconciler) apply(ctx context.Context, owner runtime.Object, gvk schema.GroupVersionKind, objType *typed.ParseableType, curObj, newObj client.Object, desc string) error {
+	obj, err := applyConfiguration(objType, gvk, curObj, newObj)
+	if err != nil {
+		return err
+	}
+
+	// Hand over the fields we used to own through updates. That's a write,
+	// so apply on top of the object it returns.
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(curObj, updateManagers, FieldManager)
+	if err != nil {
+		return err
+	}
+	if patch != nil {
+		upgraded := curObj.DeepCopyObject().(client.Object)
+		if err := r.client.Patch(ctx, upgraded, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+			return err
+		}
+		obj.SetResourceVersion(upgraded.GetResourceVersion())
+	}
+
+	err = r.client.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager))
+	if !isFieldManagerConflict(err) {
+		return err
+	}
+	r.recorder.Eventf(owner, corev1.EventTypeWarning, "ApplyConflict", "overriding changes by other field managers to %v: %v", desc, err)
+	return r.client.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// isFieldManagerConflict returns whether err is a conflict over fields that
+// another field manager owns, as opposed to a conflict over the
+// resourceVersion, which forcing ownership doesn't resolve.
+func isFieldManagerConflict(err error) bool {
+	if !apierrors.IsConflict(err) {
+		return false
+	}
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+applyConfiguration returns the object to send in an apply of newObj, an
+updated copy of curObj.
+
+It only has the fields of newObj that the operator owns, given the managed
+fields of curObj, and those that differ from curObj, which the operator is
+setting now. Fields the operator owned but no longer sets are left out, so
+the apply removes them. The resourceVersion of curObj is kept, so the apply
+is conditional on it.
+*/
+func applyConfiguration(objType *typed.ParseableType, gvk schema.GroupVersionKind, curObj, newObj client.Object) (*unstructured.Unstructured, error) {
+	owned, err := ownedFields(curObj)
+	if err != nil {
+		return nil, err
+	}
+	curTyped, err := toTyped(objType, curObj)
+	if err != nil {
+		return nil, err
+	}
+	newTyped, err := toTyped(objType, newObj)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := curTyped.Compare(newTyped)
+	if err != nil {
+		return nil, err
+	}
+	owned = owned.Union(changes.Added).Union(changes.Modified)
+
+	content, ok := newTyped.ExtractItems(owned.Leaves()).AsValue().Unstructured().(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("can't extract the fields of %v %v", gvk.Kind, newObj.GetName())
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(newObj.GetNamespace())
+	u.SetName(newObj.GetName())
+	u.SetResourceVersion(curObj.GetResourceVersion())
+	return u, nil
+}
+
+// ownedFields returns the fields of obj that the operator owns, either as
+// FieldManager or through its updates from before it used server-side apply.
+func ownedFields(obj client.Object) (*fieldpath.Set, error) {
+	owned := &fieldpath.Set{}
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		switch {
+		case entry.Operation == metav1.ManagedFieldsOperationApply && entry.Manager == FieldManager:
+		case entry.Operation == metav1.ManagedFieldsOperationUpdate && updateManagers.Has(entry.Manager):
+		default:
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("can't parse managed fields of %v: %v", entry.Manager, err)
+		}
+		owned = owned.Union(fields)
+	}
+	return owned, nil
+}
+
+// toTyped converts obj, without its managed fields, to a value of the given
+// schema.
+//
+// Status is kept, since some kinds, like VitessTabletPool, have no status
+// subresource, so their status is set along with the rest of the object.
+func toTyped(objType *typed.ParseableType, obj client.Object) (*typed.TypedValue, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetManagedFields(nil)
+	u.SetCreationTimestamp(metav1.Time{})
+	return objType.FromUnstructured(u.Object)
+}
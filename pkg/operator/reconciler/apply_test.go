@@ -0,0 +1,263 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// configMapSchema is enough of the ConfigMap schema for the tests.
+const configMapSchema = `types:
+- name: configmap
+  map:
+    fields:
+    - name: apiVersion
+      type:
+        scalar: string
+    - name: kind
+      type:
+        scalar: string
+    - name: metadata
+      type:
+        namedType: objectmeta
+    - name: data
+      type:
+        map:
+          elementType:
+            scalar: string
+- name: objectmeta
+  map:
+    fields:
+    - name: name
+      type:
+        scalar: string
+    - name: namespace
+      type:
+        scalar: string
+    - name: resourceVersion
+      type:
+        scalar: string
+    - name: labels
+      type:
+        map:
+          elementType:
+            scalar: string
+    - name: annotations
+      type:
+        map:
+          elementType:
+            scalar: string
+`
+
+func configMapType(t *testing.T) *typed.ParseableType {
+	t.Helper()
+	parser, err := typed.NewParser(typed.YAMLObject(configMapSchema))
+	if err != nil {
+		t.Fatalf("can't parse schema: %v", err)
+	}
+	objType := parser.Type("configmap")
+	return &objType
+}
+
+func managedFields(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  operation,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestApplyConfiguration(t *testing.T) {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	table := []struct {
+		name string
+		// managedFields of the current object.
+		managedFields []metav1.ManagedFieldsEntry
+		// curData and newData are the data of the current and updated
+		// objects. Both have the same annotations, which are never ours.
+		curData, newData map[string]string
+		annotations      map[string]string
+
+		wantData map[string]string
+	}{
+		{
+			name: "fields owned through updates are handed over",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(FieldManager, metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:a":{}}}`),
+			},
+			curData:  map[string]string{"a": "1"},
+			newData:  map[string]string{"a": "1"},
+			wantData: map[string]string{"a": "1"},
+		},
+		{
+			name: "fields owned through applies are kept",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:a":{}}}`),
+			},
+			curData:  map[string]string{"a": "1"},
+			newData:  map[string]string{"a": "1"},
+			wantData: map[string]string{"a": "1"},
+		},
+		{
+			name: "changed and added fields are applied",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:a":{}}}`),
+			},
+			curData:  map[string]string{"a": "1"},
+			newData:  map[string]string{"a": "2", "b": "1"},
+			wantData: map[string]string{"a": "2", "b": "1"},
+		},
+		{
+			name: "fields added by others are left out",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:a":{}}}`),
+				managedFields("kubectl-edit", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:user":{}}}`),
+				managedFields("webhook", metav1.ManagedFieldsOperationUpdate, `{"f:metadata":{"f:annotations":{"f:injected":{}}}}`),
+			},
+			curData:     map[string]string{"a": "1", "user": "1"},
+			newData:     map[string]string{"a": "2", "user": "1"},
+			annotations: map[string]string{"injected": "true"},
+			wantData:    map[string]string{"a": "2"},
+		},
+		{
+			name: "fields owned by others that we change are applied",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:a":{}}}`),
+				managedFields("kubectl-edit", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:a":{}}}`),
+			},
+			curData:  map[string]string{"a": "edited"},
+			newData:  map[string]string{"a": "1"},
+			wantData: map[string]string{"a": "1"},
+		},
+		{
+			name: "fields we no longer set are left out",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:a":{},"f:old":{}}}`),
+			},
+			curData:  map[string]string{"a": "1", "old": "1"},
+			newData:  map[string]string{"a": "1"},
+			wantData: map[string]string{"a": "1"},
+		},
+		{
+			name: "fields owned by subresources are left out",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:a":{}}}`),
+				func() metav1.ManagedFieldsEntry {
+					entry := managedFields(FieldManager, metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:sub":{}}}`)
+					entry.Subresource = "status"
+					return entry
+				}(),
+			},
+			curData:  map[string]string{"a": "1", "sub": "1"},
+			newData:  map[string]string{"a": "1", "sub": "1"},
+			wantData: map[string]string{"a": "1"},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			curObj := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "ns",
+					Name:            "cm",
+					ResourceVersion: "42",
+					Annotations:     test.annotations,
+					ManagedFields:   test.managedFields,
+				},
+				Data: test.curData,
+			}
+			newObj := curObj.DeepCopy()
+			newObj.Data = test.newData
+
+			got, err := applyConfiguration(configMapType(t), gvk, curObj, newObj)
+			if err != nil {
+				t.Fatalf("applyConfiguration() error: %v", err)
+			}
+
+			if got.GroupVersionKind() != gvk || got.GetNamespace() != "ns" || got.GetName() != "cm" {
+				t.Errorf("applyConfiguration() = %v %v/%v; want %v ns/cm", got.GroupVersionKind(), got.GetNamespace(), got.GetName(), gvk)
+			}
+			if got, want := got.GetResourceVersion(), "42"; got != want {
+				t.Errorf("resourceVersion = %q; want %q", got, want)
+			}
+			if got.GetManagedFields() != nil {
+				t.Errorf("managedFields = %v; want none", got.GetManagedFields())
+			}
+			data := map[string]string{}
+			for key, value := range got.Object["data"].(map[string]interface{}) {
+				data[key] = value.(string)
+			}
+			if !reflect.DeepEqual(data, test.wantData) {
+				t.Errorf("data = %v; want %v", data, test.wantData)
+			}
+			if annotations := got.GetAnnotations(); len(annotations) != 0 {
+				t.Errorf("annotations = %v; want none", annotations)
+			}
+		})
+	}
+}
+
+func TestIsFieldManagerConflict(t *testing.T) {
+	table := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "field manager conflict",
+			err: apierrors.NewApplyConflict([]metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: `conflict with "kubectl-edit"`,
+				Field:   ".data.a",
+			}}, `Apply failed with 1 conflict: conflict with "kubectl-edit": .data.a`),
+			want: true,
+		},
+		{
+			name: "stale resourceVersion",
+			err:  apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "cm", errors.New("the object has been modified")),
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "cm"),
+			want: false,
+		},
+		{
+			name: "no error",
+			want: false,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isFieldManagerConflict(test.err); got != test.want {
+				t.Errorf("isFieldManagerConflict() = %v; want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+const (
+	// OwnerAnnotation is set instead of a controller reference on objects
+	// whose owner is in another namespace, which owner references can't
+	// point to. Its value is "<kind>/<namespace>/<name>" of the owner.
+	OwnerAnnotation = planetscalev2.LabelPrefix + "/" + "owner"
+	// OwnerUIDLabel is set along with OwnerAnnotation to the UID of the
+	// owner, so all the objects it owns in other namespaces can be listed.
+	OwnerUIDLabel = planetscalev2.LabelPrefix + "/" + "owner-uid"
+
+	// CrossNamespaceFinalizer is held by owners of objects in other
+	// namespaces until those objects are deleted, since Kubernetes won't
+	// garbage collect them.
+	CrossNamespaceFinalizer = planetscalev2.LabelPrefix + "/" + "cross-namespace-objects"
+)
+
+// setOwner makes owner the controller of obj. If obj is in another
+// namespace, owner is recorded in OwnerAnnotation and OwnerUIDLabel instead
+// of a controller reference.
+//
+// Note that this only mutates the provided, in-memory object; the caller is
+// responsible for sending the updated object to the server.
+func (r *Reconciler) setOwner(owner runtime.Object, ownerMeta, obj metav1.Object) error {
+	if obj.GetNamespace() == ownerMeta.GetNamespace() {
+		return controllerutil.SetControllerReference(ownerMeta, obj, r.scheme)
+	}
+
+	gvk, err := apiutil.GVKForObject(owner, r.scheme)
+	if err != nil {
+		return err
+	}
+	value := fmt.Sprintf("%v/%v/%v", gvk.Kind, ownerMeta.GetNamespace(), ownerMeta.GetName())
+	uid := string(ownerMeta.GetUID())
+
+	annotations := obj.GetAnnotations()
+	if cur, ok := annotations[OwnerAnnotation]; ok && cur != value {
+		return fmt.Errorf("object is already owned by %v", cur)
+	}
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[OwnerAnnotation] = value
+	obj.SetAnnotations(annotations)
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[OwnerUIDLabel] = uid
+	obj.SetLabels(labels)
+	return nil
+}
+
+/*
+ReconcileCrossNamespace cleans up the objects owner has in other namespaces,
+which Kubernetes doesn't garbage collect.
+
+The 'keep' argument lists the objects owner still wants in other namespaces.
+Any other object of the given list kinds that's labeled as owned by owner in
+another namespace is deleted, as are all of them once owner is being deleted.
+
+The CrossNamespaceFinalizer is kept on owner for as long as it has objects in
+other namespaces, and removed once they're all gone, which lets the deletion of
+owner complete. Deleted objects trigger a reconcile of their owner through
+EnqueueRequestForCrossNamespaceOwner, so the finalizer is removed as soon as
+the last one is gone. The caller should return early without reconciling
+anything else if owner is being deleted.
+
+If ctx was created by NewPausedContext, nothing is deleted and the finalizer
+is left as it is.
+*/
+func (r *Reconciler) ReconcileCrossNamespace(ctx context.Context, owner client.Object, keep []client.ObjectKey, lists ...client.ObjectList) error {
+	if IsPaused(ctx) {
+		return nil
+	}
+
+	deleting := owner.GetDeletionTimestamp() != nil
+	wanted := make(map[client.ObjectKey]bool, len(keep))
+	if !deleting {
+		for _, key := range keep {
+			if key.Namespace != owner.GetNamespace() {
+				wanted[key] = true
+			}
+		}
+	}
+
+	resultBuilder := results.Builder{}
+	remaining := len(wanted) > 0
+	for _, list := range lists {
+		opts := []client.ListOption{client.MatchingLabels{OwnerUIDLabel: string(owner.GetUID())}}
+		if err := r.client.List(ctx, list, opts...); err != nil {
+			r.recorder.Eventf(owner, corev1.EventTypeWarning, "ListFailed", "failed to list objects in other namespaces: %v", err)
+			resultBuilder.Error(err)
+			remaining = true
+			continue
+		}
+		err := meta.EachListItem(list, func(obj runtime.Object) error {
+			objMeta := obj.(client.Object)
+			key := client.ObjectKeyFromObject(objMeta)
+			if key.Namespace == owner.GetNamespace() || wanted[key] {
+				return nil
+			}
+			remaining = true
+			if objMeta.GetDeletionTimestamp() != nil {
+				return nil
+			}
+			gvk, err := apiutil.GVKForObject(obj, r.scheme)
+			if err != nil {
+				return err
+			}
+			desc := fmt.Sprintf("%v %v/%v", gvk.Kind, key.Namespace, key.Name)
+			uid := objMeta.GetUID()
+			if err := r.client.Delete(ctx, objMeta, client.PropagationPolicy(metav1.DeletePropagationBackground), client.Preconditions{UID: &uid}); err != nil && !apierrors.IsNotFound(err) {
+				r.recorder.Eventf(owner, corev1.EventTypeWarning, "DeleteFailed", "failed to delete %v: %v", desc, err)
+				resultBuilder.Error(err)
+				return nil
+			}
+			r.recorder.Eventf(owner, corev1.EventTypeNormal, "Deleted", "deleted %v", desc)
+			return nil
+		})
+		if err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
+	if err := r.setFinalizer(ctx, owner, remaining); err != nil {
+		resultBuilder.Error(err)
+	}
+	_, err := resultBuilder.Result()
+	return err
+}
+
+// setFinalizer adds or removes the CrossNamespaceFinalizer on owner.
+// Only the finalizer is patched, so the in-memory changes the caller has
+// made to owner, like filling in defaults, aren't sent to the server.
+func (r *Reconciler) setFinalizer(ctx context.Context, owner client.Object, want bool) error {
+	if controllerutil.ContainsFinalizer(owner, CrossNamespaceFinalizer) == want {
+		return nil
+	}
+	base := owner.DeepCopyObject().(client.Object)
+	patched := owner.DeepCopyObject().(client.Object)
+	if want {
+		controllerutil.AddFinalizer(patched, CrossNamespaceFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(patched, CrossNamespaceFinalizer)
+	}
+	if err := r.client.Patch(ctx, patched, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})); err != nil {
+		r.recorder.Eventf(owner, corev1.EventTypeWarning, "UpdateFailed", "failed to update finalizers: %v", err)
+		return err
+	}
+	// Keep the caller's copy in sync, so a later status update doesn't conflict.
+	owner.SetFinalizers(patched.GetFinalizers())
+	owner.SetResourceVersion(patched.GetResourceVersion())
+	return nil
+}
+
+// EnqueueRequestForCrossNamespaceOwner returns an event handler that requests
+// reconciliation of the owner of objects in other namespaces, given the Kind
+// of the owner.
+func EnqueueRequestForCrossNamespaceOwner(ownerKind string) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		parts := strings.Split(obj.GetAnnotations()[OwnerAnnotation], "/")
+		if len(parts) != 3 || parts[0] != ownerKind {
+			return nil
+		}
+		return []reconcile.Request{
+			{NamespacedName: apitypes.NamespacedName{Namespace: parts[1], Name: parts[2]}},
+		}
+	})
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"planetscale.dev/vitess-operator/pkg/operator/commonmeta"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
+)
+
+// maxDryRunChanges is how many changes a DryRun keeps, so a big spec change
+// doesn't blow up the size of the status that reports them.
+const maxDryRunChanges = 100
+
+// DryRun collects the changes that ReconcileObject would have made.
+type DryRun struct {
+	mu      sync.Mutex
+	changes []string
+	dropped int
+}
+
+type dryRunKey struct{}
+
+// NewDryRunContext returns a copy of ctx that tells ReconcileObject and
+// ReconcileObjectSet to report the changes they would make, without making
+// any. Other than reporting changes, it behaves like a paused context, so
+// IsPaused also returns true for it.
+func NewDryRunContext(ctx context.Context) (context.Context, *DryRun) {
+	dryRun := &DryRun{}
+	ctx = context.WithValue(ctx, dryRunKey{}, dryRun)
+	return NewPausedContext(ctx), dryRun
+}
+
+// dryRunFromContext returns the DryRun that ctx was created with, if any.
+func dryRunFromContext(ctx context.Context) *DryRun {
+	dryRun, _ := ctx.Value(dryRunKey{}).(*DryRun)
+	return dryRun
+}
+
+// Changes returns a sorted summary of the changes that were reported.
+func (d *DryRun) Changes() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.changes) == 0 {
+		return nil
+	}
+	changes := make([]string, len(d.changes), len(d.changes)+1)
+	copy(changes, d.changes)
+	sort.Strings(changes)
+	if d.dropped > 0 {
+		changes = append(changes, fmt.Sprintf("(%v more)", d.dropped))
+	}
+	return changes
+}
+
+func (d *DryRun) add(change string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.changes) >= maxDryRunChanges {
+		d.dropped++
+		return
+	}
+	d.changes = append(d.changes, change)
+}
+
+// reconcileDryRun is the variant of ReconcileObject for dry runs. It works
+// out what ReconcileObject would do, and reports it instead of doing it.
+//
+// Like reconcilePaused, it does apply UpdatePaused, since that's how the
+// dry run itself propagates to child objects.
+func (r *Reconciler) reconcileDryRun(ctx context.Context, owner runtime.Object, key client.ObjectKey, labels map[string]string, wanted bool, s Strategy, curObj client.Object, dryRun *DryRun) error {
+	gvk, err := apiutil.GVKForObject(s.Kind, r.scheme)
+	if err != nil {
+		return err
+	}
+	objDesc := fmt.Sprintf("%v %v", gvk.Kind, key.Name)
+	report := func(change, diff string) {
+		dryRun.add(change)
+		r.recorder.Eventf(owner, corev1.EventTypeNormal, "DryRun", "%v", change)
+		if diff != "" {
+			logging.FromContext(ctx).WithFields(logrus.Fields{
+				"gvk":  gvk.String(),
+				"key":  key.String(),
+				"diff": diff,
+			}).Info(change)
+		}
+	}
+
+	if curObj == nil {
+		if wanted {
+			report(fmt.Sprintf("would create %v", objDesc), "")
+		}
+		return nil
+	}
+	curObjMeta, err := meta.Accessor(curObj)
+	if err != nil {
+		return err
+	}
+	if curObjMeta.GetDeletionTimestamp() != nil {
+		if wanted && hasMatchingLabels(curObjMeta, labels) && s.Status != nil {
+			s.Status(key, curObj)
+		}
+		return nil
+	}
+
+	if !hasMatchingLabels(curObjMeta, labels) {
+		switch {
+		case !wanted:
+			// We would leave it alone anyway.
+		case IsAdopting(ctx):
+			report(fmt.Sprintf("would adopt existing %v", objDesc), "")
+		default:
+			r.recorder.Eventf(owner, corev1.EventTypeWarning, "NameCollision", "%v already exists, but does not have matching labels", objDesc)
+		}
+		return nil
+	}
+
+	if !wanted {
+		if s.PrepareForTurndown != nil {
+			if orphanStatus := s.PrepareForTurndown(key, curObj.DeepCopyObject().(client.Object)); orphanStatus != nil {
+				if s.OrphanStatus != nil {
+					s.OrphanStatus(key, curObj, orphanStatus)
+				}
+				report(fmt.Sprintf("would keep unwanted %v: %v", objDesc, orphanStatus.Message), "")
+				return nil
+			}
+		}
+		report(fmt.Sprintf("would delete %v", objDesc), "")
+		return nil
+	}
+
+	if s.Status != nil {
+		s.Status(key, curObj)
+	}
+
+	// Propagate the dry run, and any change to the paused state, for real.
+	if s.UpdatePaused != nil {
+		newObj := curObj.DeepCopyObject().(client.Object)
+		s.UpdatePaused(key, newObj)
+		if err := r.updateInPlace(ctx, owner, key, s, curObj, newObj); err != nil {
+			return err
+		}
+		curObj = newObj
+	}
+
+	// Apply every update hook to a copy, keeping track of which kind of
+	// update would have been needed.
+	inPlace := curObj.DeepCopyObject().(client.Object)
+	if s.UpdateInPlace != nil {
+		s.UpdateInPlace(key, inPlace)
+	}
+	ownerMeta, err := meta.Accessor(owner)
+	if err != nil {
+		return err
+	}
+	commonMeta := commonmeta.FromOwner(ownerMeta)
+	commonmeta.UpdateInPlace(inPlace, commonMeta)
+	recreate := false
+	if s.UpdateRecreate != nil && !IsAdopted(curObjMeta) {
+		recreated := inPlace.DeepCopyObject()
+		s.UpdateRecreate(key, recreated)
+		recreate = !deepEqual(r.scheme, inPlace, recreated)
+	}
+	newObj := inPlace.DeepCopyObject()
+	if s.UpdateRecreate != nil && !IsAdopted(curObjMeta) {
+		s.UpdateRecreate(key, newObj)
+	}
+	if s.UpdateRollingInPlace != nil {
+		s.UpdateRollingInPlace(key, newObj)
+	}
+	if updateRollingRecreate := rollingRecreateHook(s, curObj, commonMeta); updateRollingRecreate != nil {
+		updateRollingRecreate(key, newObj)
+	}
+
+	var change string
+	switch {
+	case recreate:
+		change = fmt.Sprintf("would recreate %v", objDesc)
+	case !deepEqual(r.scheme, inPlace, newObj):
+		change = fmt.Sprintf("would roll out changes to %v", objDesc)
+	case !deepEqual(r.scheme, curObj, inPlace):
+		change = fmt.Sprintf("would update %v in place", objDesc)
+	default:
+		return nil
+	}
+	report(change, describeDiff(curObj, newObj, s.Kind))
+	return nil
+}
@@ -17,9 +17,9 @@ limitations under the License.
 package reconciler
 
 import (
-	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 )
 
 const (
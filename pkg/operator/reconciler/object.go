@@ -26,10 +26,16 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 
+	"planetscale.dev/vitess-operator/pkg/operator/commonmeta"
 	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
 	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/scope"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v2"
 
 	corev1 "k8s.io/api/core/v1"
@@ -42,7 +48,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 /*
@@ -50,6 +55,17 @@ ReconcileObject reconciles a single object by name with the given strategy.
 
 If 'wanted' is true, the object will be created or updated as needed.
 If 'wanted' is false, the object will be deleted if it exists.
+
+If ctx was created by NewPausedContext, no changes are made other than
+those from the UpdatePaused hook of the Strategy.
+
+If ctx was created by NewDryRunContext, the changes that would be made are
+reported instead, and only the UpdatePaused hook is applied.
+
+If ctx was created by NewAdoptingContext, a wanted object that already exists
+without our labels is adopted instead of being reported as a name collision.
+Adopted objects are never deleted to apply UpdateRecreate changes right away;
+those changes wait to be rolled out along with UpdateRollingRecreate.
 */
 func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object, key client.ObjectKey, labels map[string]string, wanted bool, s Strategy) (finalErr error) {
 	// Get the name of the Kind, for event log messages.
@@ -62,6 +78,13 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 		return err
 	}
 	objDesc := fmt.Sprintf("%v %v", gvk.Kind, key.Name)
+
+	ctx, span := tracing.StartSpan(ctx, "reconciler.ReconcileObject",
+		attribute.String("kind", gvk.Kind),
+		attribute.String("name", key.Name),
+	)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	ownerMeta, err := meta.Accessor(owner)
 	if err != nil {
 		return err
@@ -86,6 +109,13 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 		}
 	}
 
+	if dryRun := dryRunFromContext(ctx); dryRun != nil {
+		return r.reconcileDryRun(ctx, owner, key, labels, wanted, s, curObj, dryRun)
+	}
+	if IsPaused(ctx) {
+		return r.reconcilePaused(ctx, owner, key, labels, wanted, s, curObj)
+	}
+
 	// If it's a Pod, we need to check a special case.
 	if pod, ok := curObj.(*corev1.Pod); ok {
 		if (pod.Spec.RestartPolicy == corev1.RestartPolicyAlways || pod.Spec.RestartPolicy == corev1.RestartPolicyOnFailure) &&
@@ -170,11 +200,15 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 		}
 		newObjMeta.SetNamespace(key.Namespace)
 		newObjMeta.SetName(key.Name)
-		if err := controllerutil.SetControllerReference(ownerMeta, newObjMeta, r.scheme); err != nil {
+		scope.CopyLabels(newObjMeta, ownerMeta)
+		commonmeta.Create(newObjMeta, commonmeta.FromOwner(ownerMeta))
+		if err := r.setOwner(owner, ownerMeta, newObjMeta); err != nil {
 			r.recorder.Eventf(owner, corev1.EventTypeWarning, "CreateFailed", "failed to create %v: %v", objDesc, err)
 			return err
 		}
-		err = r.client.Create(ctx, newObj)
+		if err = faults.FailCreate(newObj); err == nil {
+			err = r.client.Create(ctx, newObj, client.FieldOwner(FieldManager))
+		}
 		createCount.With(metricLabels(gvk, ownerGVK, err)).Inc()
 		if err != nil {
 			r.recorder.Eventf(owner, corev1.EventTypeWarning, "CreateFailed", "failed to create %v: %v", objDesc, err)
@@ -192,6 +226,10 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 	}
 	curObjDesc := fmt.Sprintf("%v %v", gvk.Kind, curObjMeta.GetName())
 	if !hasMatchingLabels(curObjMeta, labels) {
+		if IsAdopting(ctx) {
+			// We'll pick up the rest of the changes once we own it.
+			return r.adopt(ctx, owner, ownerMeta, labels, curObj, curObjDesc)
+		}
 		err := fmt.Errorf("%v already exists, but does not have matching labels", curObjDesc)
 		r.recorder.Event(owner, corev1.EventTypeWarning, "NameCollision", err.Error())
 		return err
@@ -213,10 +251,16 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 	if s.UpdateInPlace != nil {
 		s.UpdateInPlace(key, updatedObjInPlace)
 	}
+	// Keep the object in the operator's scope along with its owner.
+	scope.CopyLabels(updatedObjInPlace, ownerMeta)
+	commonMeta := commonmeta.FromOwner(ownerMeta)
+	commonmeta.UpdateInPlace(updatedObjInPlace, commonMeta)
+
+	updateRollingRecreate := rollingRecreateHook(s, curObj, commonMeta)
 
 	// See if anything else needs to be updated that would trigger an immediate
 	// deletion.
-	if s.UpdateRecreate != nil {
+	if s.UpdateRecreate != nil && !IsAdopted(curObjMeta) {
 		updatedObjRecreate := updatedObjInPlace.DeepCopyObject()
 		s.UpdateRecreate(key, updatedObjRecreate)
 		if !deepEqual(r.scheme, updatedObjInPlace, updatedObjRecreate) {
@@ -231,9 +275,9 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 		if s.UpdateRollingInPlace != nil {
 			s.UpdateRollingInPlace(key, updatedObjInPlace)
 		}
-		if s.UpdateRollingRecreate != nil {
+		if updateRollingRecreate != nil {
 			updatedObjRecreate := updatedObjInPlace.DeepCopyObject().(client.Object)
-			s.UpdateRollingRecreate(key, updatedObjRecreate)
+			updateRollingRecreate(key, updatedObjRecreate)
 			if !deepEqual(r.scheme, updatedObjInPlace, updatedObjRecreate) {
 				// Even if we were to apply the immediate-in-place and rolling-in-place changes,
 				// there would still be additional changes that require deletion and recreation
@@ -257,8 +301,11 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 	if s.UpdateRollingInPlace != nil {
 		s.UpdateRollingInPlace(key, updatedObjRollout)
 	}
-	if s.UpdateRollingRecreate != nil {
-		s.UpdateRollingRecreate(key, updatedObjRollout)
+	recreateReason := ""
+	if updateRollingRecreate != nil {
+		updatedObjRollingInPlace := updatedObjRollout.DeepCopyObject()
+		updateRollingRecreate(key, updatedObjRollout)
+		recreateReason = r.describeRecreateReason(updatedObjRollingInPlace, updatedObjRollout, s.Kind)
 	}
 	// In either case, we go ahead and do the in-place update.
 	// Just set the rollout annotations accordingly.
@@ -270,6 +317,7 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 		rollout.Unschedule(updatedObjInPlaceMeta)
 	} else {
 		rollout.Schedule(updatedObjInPlaceMeta, describeDiff(updatedObjInPlace, updatedObjRollout, s.Kind))
+		rollout.SetRecreateReason(updatedObjInPlaceMeta, recreateReason)
 	}
 	return r.updateInPlace(ctx, owner, key, s, curObj, updatedObjInPlace)
 }
@@ -293,7 +341,7 @@ func (r *Reconciler) updateInPlace(ctx context.Context, owner runtime.Object, ke
 	}
 	newObjDesc := fmt.Sprintf("%v %v", gvk.Kind, newObjMeta.GetName())
 
-	if err := controllerutil.SetControllerReference(ownerMeta, newObjMeta, r.scheme); err != nil {
+	if err := r.setOwner(owner, ownerMeta, newObjMeta); err != nil {
 		r.recorder.Eventf(owner, corev1.EventTypeWarning, "UpdateFailed", "failed to update %v: %v", newObjDesc, err)
 		return err
 	}
@@ -303,13 +351,25 @@ func (r *Reconciler) updateInPlace(ctx context.Context, owner runtime.Object, ke
 		return nil
 	}
 
-	logrus.WithFields(logrus.Fields{
+	logging.FromContext(ctx).WithFields(logging.ObjectFields(newObjMeta)).WithFields(logrus.Fields{
 		"gvk":  gvk.String(),
 		"key":  key.String(),
 		"diff": describeDiff(curObj, newObj, s.Kind),
 	}).Info("Updating object in place")
 
-	err = r.client.Update(ctx, newObj)
+	if pod, ok := newObj.(*corev1.Pod); ok {
+		// Resize containers in place, if their resources changed.
+		if err := r.resizePod(ctx, owner, curObj.(*corev1.Pod), pod); err != nil {
+			r.recorder.Eventf(owner, corev1.EventTypeWarning, "ResizeFailed", "failed to resize %v: %v", newObjDesc, err)
+			return err
+		}
+	}
+
+	if objType := applyType(gvk); objType != nil {
+		err = r.apply(ctx, owner, gvk, objType, curObj, newObj, newObjDesc)
+	} else {
+		err = r.client.Update(ctx, newObj)
+	}
 	updateCount.With(metricLabels(gvk, ownerGVK, err)).Inc()
 	if err != nil {
 		r.recorder.Eventf(owner, corev1.EventTypeWarning, "UpdateFailed", "failed to update %v: %v", newObjDesc, err)
@@ -338,6 +398,7 @@ func (r *Reconciler) drainAndDelete(ctx context.Context, owner runtime.Object, k
 		// We still have changes pending from UpdateRollingRecreate
 		// since we didn't get to delete yet.
 		rollout.Schedule(newObjMeta, describeDiff(updatedObjInPlace, updatedObjRecreate, s.Kind))
+		rollout.SetRecreateReason(newObjMeta, r.describeRecreateReason(updatedObjInPlace, updatedObjRecreate, s.Kind))
 		return r.updateInPlace(ctx, owner, key, s, curObj, newObj)
 	}
 
@@ -372,6 +433,34 @@ func (r *Reconciler) delete(ctx context.Context, owner runtime.Object, key clien
 	return nil
 }
 
+// rollingRecreateHook returns the hook to use for changes that require
+// recreating the object as part of a rolling update.
+//
+// Recreating an object we adopted would cause the very downtime that adopting
+// it avoids, so for those, UpdateRecreate changes also wait to be rolled out.
+func rollingRecreateHook(s Strategy, curObj client.Object, commonMeta *commonmeta.Metadata) func(key client.ObjectKey, newObj runtime.Object) {
+	hook := s.UpdateRollingRecreate
+	if s.UpdateRecreate != nil && IsAdopted(curObj) {
+		hook = func(key client.ObjectKey, newObj runtime.Object) {
+			s.UpdateRecreate(key, newObj)
+			if s.UpdateRollingRecreate != nil {
+				s.UpdateRollingRecreate(key, newObj)
+			}
+		}
+	}
+	if _, ok := curObj.(*corev1.Pod); !ok {
+		return hook
+	}
+	// Changes to the identity keys of common metadata are rolled out by
+	// recreating Pods.
+	return func(key client.ObjectKey, newObj runtime.Object) {
+		if hook != nil {
+			hook(key, newObj)
+		}
+		commonmeta.UpdateRecreate(newObj.(client.Object), commonMeta)
+	}
+}
+
 func hasMatchingLabels(obj metav1.Object, expectedLabels map[string]string) bool {
 	labels := obj.GetLabels()
 	for k, v := range expectedLabels {
@@ -405,6 +494,19 @@ func describeDiff(curObj, newObj runtime.Object, kind runtime.Object) string {
 	return patch
 }
 
+// describeRecreateReason explains why getting from inPlace to recreated
+// requires recreating the object, or returns "" if it doesn't.
+func (r *Reconciler) describeRecreateReason(inPlace, recreated runtime.Object, kind runtime.Object) string {
+	if deepEqual(r.scheme, inPlace, recreated) {
+		return ""
+	}
+	fields := rollout.ChangedFields(describeDiff(inPlace, recreated, kind))
+	if len(fields) == 0 {
+		return "changes can't be applied in place"
+	}
+	return fmt.Sprintf("%v can't be changed in place", strings.Join(fields, ", "))
+}
+
 func describeDiffPatch(curObj, newObj runtime.Object, kind runtime.Object) (string, error) {
 	curJSON, err := json.Marshal(curObj)
 	if err != nil {
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+type pausedKey struct{}
+
+type readOnlyKey struct{}
+
+// NewPausedContext returns a copy of ctx that tells ReconcileObject and
+// ReconcileObjectSet not to make any changes, other than those allowed by
+// the UpdatePaused hook of the Strategy.
+func NewPausedContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pausedKey{}, true)
+}
+
+// IsPaused returns whether ctx was created by NewPausedContext.
+func IsPaused(ctx context.Context) bool {
+	paused, _ := ctx.Value(pausedKey{}).(bool)
+	return paused
+}
+
+// NewReadOnlyContext returns a copy of ctx that tells ReconcileObject and
+// ReconcileObjectSet to only report status, like a paused context, without
+// even applying the UpdatePaused hook of the Strategy. IsPaused also returns
+// true for it.
+func NewReadOnlyContext(ctx context.Context) context.Context {
+	return NewPausedContext(context.WithValue(ctx, readOnlyKey{}, true))
+}
+
+// IsReadOnly returns whether ctx was created by NewReadOnlyContext.
+func IsReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
+// reconcilePaused is the variant of ReconcileObject for when reconciliation
+// is paused. It only reports status for objects that exist, and applies
+// UpdatePaused so that changes to the paused state itself can propagate.
+func (r *Reconciler) reconcilePaused(ctx context.Context, owner runtime.Object, key client.ObjectKey, labels map[string]string, wanted bool, s Strategy, curObj client.Object) error {
+	if curObj == nil {
+		// We would create it, but we're paused.
+		return nil
+	}
+	curObjMeta, err := meta.Accessor(curObj)
+	if err != nil {
+		return err
+	}
+	if !hasMatchingLabels(curObjMeta, labels) {
+		// It's not ours, so there's nothing to report.
+		return nil
+	}
+	if curObjMeta.GetDeletionTimestamp() != nil {
+		if wanted && s.Status != nil {
+			s.Status(key, curObj)
+		}
+		return nil
+	}
+
+	if !wanted {
+		// We would delete it, but we're paused.
+		if s.OrphanStatus != nil && !IsReadOnly(ctx) {
+			s.OrphanStatus(key, curObj, planetscalev2.NewOrphanStatus("Paused", "reconciliation is paused"))
+		}
+		return nil
+	}
+
+	if s.Status != nil {
+		s.Status(key, curObj)
+	}
+	if s.UpdatePaused == nil || IsReadOnly(ctx) {
+		return nil
+	}
+	newObj := curObj.DeepCopyObject().(client.Object)
+	s.UpdatePaused(key, newObj)
+	return r.updateInPlace(ctx, owner, key, s, curObj, newObj)
+}
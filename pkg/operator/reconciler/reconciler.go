@@ -209,4 +209,18 @@ type Strategy struct {
 		in the Kind field (e.g. svc := obj.(*corev1.Service)).
 	*/
 	PrepareForTurndown func(key client.ObjectKey, newObj runtime.Object) *planetscalev2.OrphanStatus
+
+	/*
+		UpdatePaused is called instead of all the other update hooks when the
+		object already exists, but reconciliation is paused (see NewPausedContext).
+
+		It should only change the fields that control whether reconciliation is
+		paused, so that pausing and unpausing can propagate to child objects
+		while everything else is left alone. Like UpdateInPlace, any changes are
+		applied immediately.
+
+		It should always be safe to cast 'obj' to the same type as the object provided
+		in the Kind field (e.g. svc := obj.(*corev1.Service)).
+	*/
+	UpdatePaused func(key client.ObjectKey, newObj runtime.Object)
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// ResizeRejectedAnnotation is set on a Pod whose containers Kubernetes
+// refused to resize in place. Its value is the error. The Pod's resources
+// are then changed by recreating it, like any other change.
+const ResizeRejectedAnnotation = rollout.AnnotationPrefix + "/" + "resize-rejected"
+
+// podResizeEnabled is whether EnablePodResize found the resize subresource
+// of Pods.
+var podResizeEnabled bool
+
+/*
+EnablePodResize makes CanResizePod allow resizing the containers of Pods in
+place, if the API server has the resize subresource of Pods, which
+Kubernetes 1.33 and later have when the InPlacePodVerticalScaling feature
+gate is on. It returns whether it does.
+
+It must be called before any controllers are started.
+*/
+func EnablePodResize(cfg *rest.Config) (bool, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+	supported, err := podResizeSupported(dc)
+	if err != nil {
+		return false, err
+	}
+	podResizeEnabled = supported
+	return supported, nil
+}
+
+// podResizeSupported returns whether the API server has the resize
+// subresource of Pods.
+func podResizeSupported(dc discovery.DiscoveryInterface) (bool, error) {
+	resources, err := dc.ServerResourcesForGroupVersion("v1")
+	if err != nil {
+		return false, fmt.Errorf("failed to discover core API resources: %v", err)
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "pods/resize" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CanResizePod returns whether the containers of pod should be resized in
+// place, rather than by recreating it. That's only the case if
+// EnablePodResize found that the API server supports it, and Kubernetes
+// didn't refuse to resize this Pod before.
+func CanResizePod(pod *corev1.Pod) bool {
+	_, rejected := pod.Annotations[ResizeRejectedAnnotation]
+	return podResizeEnabled && !rejected
+}
+
+/*
+resizePod sends the changes that newPod makes to the resources of the
+containers of curPod through the resize subresource.
+
+If Kubernetes refuses to resize the Pod, for example because the subresource
+isn't there after all, the changes to resources are taken back out of newPod,
+so the rest of the update can still go through, and newPod is marked with
+ResizeRejectedAnnotation, so the resources are rolled out by recreating it
+instead.
+*/
+func (r *Reconciler) resizePod(ctx context.Context, owner runtime.Object, curPod, newPod *corev1.Pod) error {
+	resized := curPod.DeepCopy()
+	changed := false
+	for i := range resized.Spec.Containers {
+		container := &resized.Spec.Containers[i]
+		for j := range newPod.Spec.Containers {
+			newContainer := &newPod.Spec.Containers[j]
+			if newContainer.Name == container.Name && !apiequality.Semantic.DeepEqual(newContainer.Resources, container.Resources) {
+				container.Resources = *newContainer.Resources.DeepCopy()
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	err := r.client.SubResource("resize").Patch(ctx, resized, client.StrategicMergeFrom(curPod))
+	if resizeRejected(err) {
+		r.recorder.Eventf(owner, corev1.EventTypeWarning, "ResizeRejected", "Pod %v can't be resized in place, so it will be recreated instead: %v", curPod.Name, err)
+		for i := range newPod.Spec.Containers {
+			newContainer := &newPod.Spec.Containers[i]
+			for j := range curPod.Spec.Containers {
+				if curPod.Spec.Containers[j].Name == newContainer.Name {
+					newContainer.Resources = *curPod.Spec.Containers[j].Resources.DeepCopy()
+				}
+			}
+		}
+		ann := newPod.GetAnnotations()
+		if ann == nil {
+			ann = make(map[string]string, 1)
+		}
+		ann[ResizeRejectedAnnotation] = err.Error()
+		newPod.SetAnnotations(ann)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// The Pod has a new resourceVersion now, so the update that follows
+	// would conflict with the one we read.
+	newPod.SetResourceVersion(resized.GetResourceVersion())
+	return nil
+}
+
+// resizeRejected returns whether err means that Kubernetes won't resize a
+// Pod in place, as opposed to a failure that's worth retrying.
+func resizeRejected(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) || apierrors.IsInvalid(err) || apierrors.IsForbidden(err) || apierrors.IsBadRequest(err)
+}
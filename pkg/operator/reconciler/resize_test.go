@@ -0,0 +1,138 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPodResizeSupported(t *testing.T) {
+	table := []struct {
+		name      string
+		resources []string
+		want      bool
+	}{
+		{
+			name:      "before Kubernetes 1.33",
+			resources: []string{"pods", "pods/status", "pods/eviction"},
+			want:      false,
+		},
+		{
+			name:      "resize subresource",
+			resources: []string{"pods", "pods/status", "pods/eviction", "pods/resize"},
+			want:      true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			list := &metav1.APIResourceList{GroupVersion: "v1"}
+			for _, name := range test.resources {
+				list.APIResources = append(list.APIResources, metav1.APIResource{Name: name, Namespaced: true, Kind: "Pod"})
+			}
+			dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{list}}}
+			got, err := podResizeSupported(dc)
+			if err != nil {
+				t.Fatalf("podResizeSupported() error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("podResizeSupported() = %v; want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// noResizeClient is a client whose API server has no resize subresource.
+type noResizeClient struct {
+	client.Client
+}
+
+func (c noResizeClient) SubResource(subResource string) client.SubResourceClient {
+	return noResizeSubResourceClient{c.Client.SubResource(subResource)}
+}
+
+type noResizeSubResourceClient struct {
+	client.SubResourceClient
+}
+
+func (noResizeSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return apierrors.NewNotFound(schema.GroupResource{Resource: "pods/resize"}, obj.GetName())
+}
+
+func TestResizePodNotFound(t *testing.T) {
+	defer func(enabled bool) { podResizeEnabled = enabled }(podResizeEnabled)
+	podResizeEnabled = true
+
+	curPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "tablet"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "mysqld",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+			},
+		},
+	}
+	newPod := curPod.DeepCopy()
+	newPod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("2")
+	newPod.Labels = map[string]string{"changed": "in-place"}
+
+	c := noResizeClient{fake.NewClientBuilder().WithObjects(curPod.DeepCopy()).Build()}
+	recorder := record.NewFakeRecorder(10)
+	r := New(c, scheme.Scheme, recorder)
+	if err := r.resizePod(context.Background(), curPod, curPod, newPod); err != nil {
+		t.Fatalf("resizePod() error: %v", err)
+	}
+
+	// The rest of the update still goes through, without the new resources.
+	if got := newPod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; got.Cmp(resource.MustParse("1")) != 0 {
+		t.Errorf("cpu request = %v; want it to stay 1", got.String())
+	}
+	if newPod.Labels["changed"] != "in-place" {
+		t.Errorf("labels = %v; want the other changes kept", newPod.Labels)
+	}
+
+	// The Pod is recreated to change its resources instead.
+	if _, ok := newPod.Annotations[ResizeRejectedAnnotation]; !ok {
+		t.Errorf("annotations = %v; want %v", newPod.Annotations, ResizeRejectedAnnotation)
+	}
+	if CanResizePod(newPod) {
+		t.Errorf("CanResizePod() = true; want false after the resize was rejected")
+	}
+	if !CanResizePod(curPod) {
+		t.Errorf("CanResizePod() = false for a Pod that wasn't rejected; want true")
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("recorded %v events; want a ResizeRejected event", len(recorder.Events))
+	}
+}
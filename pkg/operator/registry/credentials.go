@@ -0,0 +1,106 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Credentials are registry logins, keyed by registry domain.
+type Credentials map[string]*credential
+
+type credential struct {
+	username, password string
+}
+
+func (c *credential) basic() string {
+	if c == nil {
+		return ""
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.username+":"+c.password))
+}
+
+func (creds Credentials) lookup(domain string) *credential {
+	if creds == nil {
+		return nil
+	}
+	return creds[domain]
+}
+
+// dockerConfig is the format of the config in image pull secrets.
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// AddSecret adds the registry logins from an image pull secret, of either
+// the kubernetes.io/dockerconfigjson or the kubernetes.io/dockercfg type.
+func (creds Credentials) AddSecret(secret *corev1.Secret) error {
+	var auths map[string]dockerConfigEntry
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		config := &dockerConfig{}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], config); err != nil {
+			return fmt.Errorf("invalid image pull secret %v: %v", secret.Name, err)
+		}
+		auths = config.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			return fmt.Errorf("invalid image pull secret %v: %v", secret.Name, err)
+		}
+	default:
+		return fmt.Errorf("image pull secret %v has unsupported type %q", secret.Name, secret.Type)
+	}
+
+	for server, entry := range auths {
+		cred := &credential{username: entry.Username, password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return fmt.Errorf("invalid auth for %v in image pull secret %v: %v", server, secret.Name, err)
+			}
+			cred.username, cred.password, _ = strings.Cut(string(decoded), ":")
+		}
+		creds[serverDomain(server)] = cred
+	}
+	return nil
+}
+
+// serverDomain normalizes a server as written in a Docker config, such as
+// "https://index.docker.io/v1/", to the domain of image references.
+func serverDomain(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	if i := strings.IndexByte(server, '/'); i >= 0 {
+		server = server[:i]
+	}
+	switch server {
+	case "index.docker.io", dockerHubRegistry:
+		return dockerHubDomain
+	}
+	return server
+}
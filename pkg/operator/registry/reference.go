@@ -0,0 +1,108 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package registry resolves container image tags to digests by asking the
+image registry, so Pods can be pinned to the exact image a tag pointed to.
+*/
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+// Reference is a parsed container image reference.
+type Reference struct {
+	// Name is the image name as it was given, without tag or digest.
+	Name string
+	// Domain is the registry domain, e.g. "docker.io" or "gcr.io".
+	Domain string
+	// Repository is the path of the image within the registry.
+	Repository string
+	// Tag is the image tag. It's "latest" if none was given.
+	Tag string
+	// Digest is the image digest, if the reference already had one.
+	Digest string
+}
+
+// ParseReference parses an image reference such as "vitess/lite:v16.0.0",
+// following the same rules as Docker for filling in the default registry.
+func ParseReference(image string) (*Reference, error) {
+	if image == "" {
+		return nil, fmt.Errorf("empty image reference")
+	}
+	ref := &Reference{}
+
+	name := image
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !strings.HasPrefix(ref.Digest, "sha256:") {
+			return nil, fmt.Errorf("invalid digest in image reference %q", image)
+		}
+	}
+	// A colon after the last slash separates the tag. Any other colon is
+	// part of the registry domain, as in "localhost:5000/image".
+	if i := strings.LastIndexByte(name, ':'); i > strings.LastIndexByte(name, '/') {
+		name, ref.Tag = name[:i], name[i+1:]
+		if ref.Tag == "" {
+			return nil, fmt.Errorf("empty tag in image reference %q", image)
+		}
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	ref.Name = name
+
+	// The first path component is a registry domain only if it looks like one.
+	ref.Domain, ref.Repository = dockerHubDomain, name
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Domain, ref.Repository = first, name[i+1:]
+		}
+	}
+	if ref.Domain == dockerHubDomain && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" || ref.Repository != strings.ToLower(ref.Repository) {
+		return nil, fmt.Errorf("invalid repository in image reference %q", image)
+	}
+	return ref, nil
+}
+
+// Pinned returns the image reference that refers to the given digest.
+func (ref *Reference) Pinned(digest string) string {
+	return ref.Name + "@" + digest
+}
+
+// registryHost returns the host to send registry API requests to.
+func (ref *Reference) registryHost() string {
+	if ref.Domain == dockerHubDomain {
+		return dockerHubRegistry
+	}
+	return ref.Domain
+}
+
+// IsPinned returns whether an image reference already includes a digest.
+func IsPinned(image string) bool {
+	return strings.Contains(image, "@")
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseReference(t *testing.T) {
+	table := []struct {
+		image                         string
+		domain, repository, tag, name string
+	}{
+		{"vitess/lite:v16.0.0", "docker.io", "vitess/lite", "v16.0.0", "vitess/lite"},
+		{"mysql", "docker.io", "library/mysql", "latest", "mysql"},
+		{"gcr.io/project/vitess/lite:latest", "gcr.io", "project/vitess/lite", "latest", "gcr.io/project/vitess/lite"},
+		{"localhost:5000/lite", "localhost:5000", "lite", "latest", "localhost:5000/lite"},
+		{"localhost/lite:v1", "localhost", "lite", "v1", "localhost/lite"},
+	}
+
+	for _, test := range table {
+		ref, err := ParseReference(test.image)
+		if err != nil {
+			t.Fatalf("ParseReference(%q) error: %v", test.image, err)
+		}
+		if ref.Domain != test.domain || ref.Repository != test.repository || ref.Tag != test.tag || ref.Name != test.name {
+			t.Errorf("ParseReference(%q) = %+v; want domain %q, repository %q, tag %q, name %q",
+				test.image, ref, test.domain, test.repository, test.tag, test.name)
+		}
+	}
+
+	for _, image := range []string{"", "vitess/lite:", "vitess/Lite", "vitess/lite@md5:abc"} {
+		if _, err := ParseReference(image); err == nil {
+			t.Errorf("ParseReference(%q) = nil error; want error", image)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/token":
+			if req.Header.Get("Authorization") != (&credential{username: "user", password: "secret"}).basic() {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if req.URL.Query().Get("scope") != "repository:vitess/lite:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"token": "abc"}`)
+		case "/v2/vitess/lite/manifests/v16":
+			if req.Header.Get("Authorization") != "Bearer abc" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:vitess/lite:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	domain := strings.TrimPrefix(server.URL, "https://")
+	creds := Credentials{}
+	err := creds.AddSecret(&corev1.Secret{
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths": {"https://%s/v1/": {"auth": "dXNlcjpzZWNyZXQ="}}}`, domain)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddSecret() error: %v", err)
+	}
+
+	resolver := NewResolver(server.Client())
+	got, err := resolver.Resolve(context.Background(), domain+"/vitess/lite:v16", creds)
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if want := domain + "/vitess/lite@" + digest; got != want {
+		t.Errorf("Resolve() = %q; want %q", got, want)
+	}
+
+	if _, err := resolver.Resolve(context.Background(), domain+"/vitess/lite:v16", nil); err == nil {
+		t.Errorf("Resolve() without credentials = nil error; want error")
+	}
+	if _, err := resolver.Resolve(context.Background(), domain+"/vitess/lite:missing", creds); err == nil {
+		t.Errorf("Resolve() of missing tag = nil error; want error")
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/*
+ReleasedComponentsAnnotation is the annotation that lists components whose
+scheduled changes should be released without waiting for an external tool,
+as a comma-separated list of component names ("vtgate", "vttablet", "etcd").
+
+It's meant to be set on a VitessCluster. The operator copies it to the
+objects below, and the controller of each object releases scheduled changes
+to any children that belong to a listed component. This makes it possible
+to, for example, roll out changes to all vtgates while holding back tablets.
+
+Unlike the "released" annotation, it's not removed automatically once the
+changes are rolled out. As long as a component is listed, later changes to
+it are rolled out as soon as they're scheduled.
+*/
+const ReleasedComponentsAnnotation = AnnotationPrefix + "/" + "released-components"
+
+// ReleasedComponents returns the components listed in the object's
+// ReleasedComponentsAnnotation.
+func ReleasedComponents(obj metav1.Object) []string {
+	value := obj.GetAnnotations()[ReleasedComponentsAnnotation]
+	var components []string
+	for _, component := range strings.Split(value, ",") {
+		if component = strings.TrimSpace(component); component != "" {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// ComponentReleased returns whether scheduled changes to the given component
+// should be released.
+func ComponentReleased(obj metav1.Object, component string) bool {
+	for _, released := range ReleasedComponents(obj) {
+		if released == component {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ReleaseComponent adds a component to the object's ReleasedComponentsAnnotation.
+
+If the component is already listed, this has no effect.
+
+Note that this only mutates the provided, in-memory object to add the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func ReleaseComponent(obj metav1.Object, component string) {
+	if ComponentReleased(obj, component) {
+		return
+	}
+	setReleasedComponents(obj, append(ReleasedComponents(obj), component))
+}
+
+/*
+HoldComponent removes a component from the object's ReleasedComponentsAnnotation,
+so that new changes to it wait to be released again.
+
+If the component isn't listed, this has no effect.
+
+Note that this only mutates the provided, in-memory object to remove the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func HoldComponent(obj metav1.Object, component string) {
+	var components []string
+	for _, released := range ReleasedComponents(obj) {
+		if released != component {
+			components = append(components, released)
+		}
+	}
+	setReleasedComponents(obj, components)
+}
+
+/*
+InheritReleasedComponents copies the ReleasedComponentsAnnotation from a
+parent object to its child, or removes it from the child if the parent
+doesn't have it.
+
+Note that this only mutates the provided, in-memory child object; the caller
+is responsible for sending the updated object to the server.
+*/
+func InheritReleasedComponents(child, parent metav1.Object) {
+	setReleasedComponents(child, ReleasedComponents(parent))
+}
+
+func setReleasedComponents(obj metav1.Object, components []string) {
+	ann := obj.GetAnnotations()
+	if len(components) == 0 {
+		delete(ann, ReleasedComponentsAnnotation)
+		obj.SetAnnotations(ann)
+		return
+	}
+	if ann == nil {
+		ann = make(map[string]string, 1)
+	}
+	ann[ReleasedComponentsAnnotation] = strings.Join(components, ",")
+	obj.SetAnnotations(ann)
+}
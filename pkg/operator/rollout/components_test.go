@@ -0,0 +1,49 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReleasedComponents(t *testing.T) {
+	parent := &metav1.ObjectMeta{}
+	ReleaseComponent(parent, "vtgate")
+	ReleaseComponent(parent, "etcd")
+	ReleaseComponent(parent, "vtgate")
+	if got, want := parent.Annotations[ReleasedComponentsAnnotation], "vtgate,etcd"; got != want {
+		t.Errorf("annotation = %q; want %q", got, want)
+	}
+	if !ComponentReleased(parent, "etcd") || ComponentReleased(parent, "vttablet") {
+		t.Errorf("ComponentReleased() doesn't match annotation %q", parent.Annotations[ReleasedComponentsAnnotation])
+	}
+
+	child := &metav1.ObjectMeta{}
+	InheritReleasedComponents(child, parent)
+	if !ComponentReleased(child, "vtgate") {
+		t.Errorf("child didn't inherit released components")
+	}
+
+	HoldComponent(parent, "vtgate")
+	HoldComponent(parent, "etcd")
+	InheritReleasedComponents(child, parent)
+	if _, ok := child.Annotations[ReleasedComponentsAnnotation]; ok {
+		t.Errorf("child still has annotation after all components were held")
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// maxChangedFieldDepth is how deep ChangedFields looks into nested fields.
+// Deeper paths get too specific to be useful at a glance.
+const maxChangedFieldDepth = 3
+
+// ChangedFields returns the paths of the fields that differ in the
+// description of pending changes left by Schedule, such as
+// "spec.containers" or "metadata.labels.foo".
+//
+// It returns nil if the description isn't a patch, which can happen for
+// kinds that don't support strategic merge patches.
+func ChangedFields(message string) []string {
+	patch := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal([]byte(message), &patch); err != nil {
+		return nil
+	}
+	var fields []string
+	addChangedFields(&fields, "", patch, 1)
+	sort.Strings(fields)
+	return fields
+}
+
+func addChangedFields(fields *[]string, prefix string, patch map[interface{}]interface{}, depth int) {
+	for k, v := range patch {
+		key := fmt.Sprint(k)
+		if strings.HasPrefix(key, "$") {
+			// These are patch directives, not fields.
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := v.(map[interface{}]interface{}); ok && depth < maxChangedFieldDepth {
+			addChangedFields(fields, path, nested, depth+1)
+			continue
+		}
+		*fields = append(*fields, path)
+	}
+}
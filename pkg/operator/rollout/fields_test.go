@@ -0,0 +1,52 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangedFields(t *testing.T) {
+	table := []struct {
+		message string
+		want    []string
+	}{
+		{
+			message: "spec:\n  containers:\n  - image: vitess/lite:v16\n    name: vttablet\n  nodeSelector:\n    zone: a\n",
+			want:    []string{"spec.containers", "spec.nodeSelector.zone"},
+		},
+		{
+			message: "metadata:\n  labels:\n    app: vt\n    extra:\n      deep: x\n$setElementOrder/foo: []\n",
+			want:    []string{"metadata.labels.app", "metadata.labels.extra"},
+		},
+		{
+			message: "",
+			want:    nil,
+		},
+		{
+			message: "object.Spec.Foo:\n  a: 1\n  b: 2\n: not a patch [",
+			want:    nil,
+		},
+	}
+
+	for _, test := range table {
+		if got := ChangedFields(test.message); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ChangedFields(%q) = %q; want %q", test.message, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	/*
+		RevertTabletsAnnotation is the annotation that lists tablets whose Pods
+		should run the images and flags their VitessShard last ran successfully,
+		as a comma-separated list of tablet aliases.
+
+		The VitessCluster controller sets it on a VitessShard when a rollout is
+		halted because of unhealthy tablets, and removes it once the halt is over.
+	*/
+	RevertTabletsAnnotation = AnnotationPrefix + "/" + "revert-tablets"
+
+	// RevertedAnnotation is the annotation whose presence indicates that a
+	// tablet Pod was created with the images and flags it was reverted to.
+	RevertedAnnotation = AnnotationPrefix + "/" + "reverted"
+)
+
+// RevertTablets returns the tablet aliases listed in the object's
+// RevertTabletsAnnotation.
+func RevertTablets(obj metav1.Object) []string {
+	var tablets []string
+	for _, tablet := range strings.Split(obj.GetAnnotations()[RevertTabletsAnnotation], ",") {
+		if tablet = strings.TrimSpace(tablet); tablet != "" {
+			tablets = append(tablets, tablet)
+		}
+	}
+	return tablets
+}
+
+/*
+SetRevertTablets sets the object's RevertTabletsAnnotation to the given
+tablet aliases, or removes it if there are none.
+
+Note that this only mutates the provided, in-memory object; the caller is
+responsible for sending the updated object to the server.
+*/
+func SetRevertTablets(obj metav1.Object, tablets []string) {
+	ann := obj.GetAnnotations()
+	if len(tablets) == 0 {
+		delete(ann, RevertTabletsAnnotation)
+		obj.SetAnnotations(ann)
+		return
+	}
+	if ann == nil {
+		ann = make(map[string]string, 1)
+	}
+	tablets = append([]string(nil), tablets...)
+	sort.Strings(tablets)
+	ann[RevertTabletsAnnotation] = strings.Join(tablets, ",")
+	obj.SetAnnotations(ann)
+}
+
+// Reverted returns whether a tablet Pod was created with the images and
+// flags it was reverted to.
+func Reverted(obj metav1.Object) bool {
+	_, present := obj.GetAnnotations()[RevertedAnnotation]
+	return present
+}
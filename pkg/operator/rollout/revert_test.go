@@ -0,0 +1,39 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRevertTablets(t *testing.T) {
+	obj := &metav1.ObjectMeta{}
+	SetRevertTablets(obj, []string{"zone1-0000000200", "zone1-0000000100"})
+	if got, want := obj.Annotations[RevertTabletsAnnotation], "zone1-0000000100,zone1-0000000200"; got != want {
+		t.Errorf("annotation = %q; want %q", got, want)
+	}
+	if got := RevertTablets(obj); len(got) != 2 || got[0] != "zone1-0000000100" {
+		t.Errorf("RevertTablets() = %v; want both tablets in order", got)
+	}
+
+	SetRevertTablets(obj, nil)
+	if _, ok := obj.Annotations[RevertTabletsAnnotation]; ok {
+		t.Errorf("annotation still present after clearing the list")
+	}
+}
@@ -58,6 +58,16 @@ const (
 	// object's controller should now release any scheduled changes to its children.
 	// The controller will remove the annotation when all children are updated.
 	CascadeAnnotation = AnnotationPrefix + "/" + "cascade"
+
+	// RecreateReasonAnnotation is set along with ScheduledAnnotation when the
+	// pending changes can only be applied by deleting and recreating the
+	// object. Its value explains why.
+	RecreateReasonAnnotation = AnnotationPrefix + "/" + "recreate-reason"
+
+	// BlockedAnnotation is set along with ScheduledAnnotation while the
+	// controller that releases the pending changes declines to do so.
+	// Its value explains why.
+	BlockedAnnotation = AnnotationPrefix + "/" + "blocked"
 )
 
 // Scheduled returns whether the object has pending changes.
@@ -122,11 +132,70 @@ func Unschedule(obj metav1.Object) {
 	ann := obj.GetAnnotations()
 	delete(ann, ScheduledAnnotation)
 	delete(ann, ReleasedAnnotation)
+	delete(ann, RecreateReasonAnnotation)
+	delete(ann, BlockedAnnotation)
+	obj.SetAnnotations(ann)
+}
+
+/*
+SetRecreateReason records why the pending changes scheduled on an object
+require recreating it. An empty reason means they can be applied in place.
+
+Note that this only mutates the provided, in-memory object to add the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func SetRecreateReason(obj metav1.Object, reason string) {
+	ann := obj.GetAnnotations()
+	if reason == "" {
+		delete(ann, RecreateReasonAnnotation)
+		obj.SetAnnotations(ann)
+		return
+	}
+	if ann == nil {
+		ann = make(map[string]string, 1)
+	}
+	ann[RecreateReasonAnnotation] = reason
+	obj.SetAnnotations(ann)
+}
+
+// RecreateReason returns why the pending changes of an object require
+// recreating it, or "" if they don't.
+func RecreateReason(obj metav1.Object) string {
+	return obj.GetAnnotations()[RecreateReasonAnnotation]
+}
+
+/*
+SetBlockedReason records why the pending changes scheduled on an object
+aren't being released. An empty reason means they aren't blocked.
+
+Note that this only mutates the provided, in-memory object to add the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func SetBlockedReason(obj metav1.Object, reason string) {
+	ann := obj.GetAnnotations()
+	if reason == "" {
+		delete(ann, BlockedAnnotation)
+		obj.SetAnnotations(ann)
+		return
+	}
+	if ann == nil {
+		ann = make(map[string]string, 1)
+	}
+	ann[BlockedAnnotation] = reason
 	obj.SetAnnotations(ann)
 }
 
+// BlockedReason returns why the pending changes of an object aren't being
+// released, or "" if they aren't blocked.
+func BlockedReason(obj metav1.Object) string {
+	return obj.GetAnnotations()[BlockedAnnotation]
+}
+
 /*
 Release annotates an object as being ready to have changes applied.
+It also removes any reason recorded by SetBlockedReason.
 
 If the object has already been marked as released, this has no effect.
 
@@ -136,6 +205,7 @@ the server.
 */
 func Release(obj metav1.Object) {
 	ann := obj.GetAnnotations()
+	delete(ann, BlockedAnnotation)
 	if _, present := ann[ReleasedAnnotation]; present {
 		// The object has already been released.
 		return
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBlockedReason(t *testing.T) {
+	obj := &metav1.ObjectMeta{}
+	Schedule(obj, "image")
+	SetBlockedReason(obj, "outside maintenance windows")
+	if got, want := BlockedReason(obj), "outside maintenance windows"; got != want {
+		t.Errorf("BlockedReason() = %q; want %q", got, want)
+	}
+
+	Release(obj)
+	if _, ok := obj.Annotations[BlockedAnnotation]; ok {
+		t.Errorf("annotation still present after Release()")
+	}
+
+	SetBlockedReason(obj, "waiting")
+	Unschedule(obj)
+	if _, ok := obj.Annotations[BlockedAnnotation]; ok {
+		t.Errorf("annotation still present after Unschedule()")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package scope limits an operator to the VitessClusters selected by a label
+selector, so several operators can share a Kubernetes cluster.
+
+The labels the selector refers to are copied from each object to the objects
+created for it, all the way down to tablet Pods. That way, the same selector
+also picks out the objects that belong to the selected VitessClusters, and
+the operator's cache only needs to watch those.
+*/
+package scope
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+var (
+	// selector picks out the objects in scope, or is nil if everything is.
+	selector labels.Selector
+	// keys are the label keys that selector refers to.
+	keys []string
+)
+
+// SetSelector limits the operator to the VitessClusters matching the given
+// label selector. An empty selector means all VitessClusters.
+func SetSelector(s string) error {
+	sel, err := labels.Parse(s)
+	if err != nil {
+		return err
+	}
+	requirements, _ := sel.Requirements()
+	if len(requirements) == 0 {
+		selector, keys = nil, nil
+		return nil
+	}
+	selector, keys = sel, nil
+	for _, requirement := range requirements {
+		keys = append(keys, requirement.Key())
+	}
+	return nil
+}
+
+// Selector returns the label selector that picks out the objects in scope,
+// or nil if everything is in scope.
+func Selector() labels.Selector {
+	return selector
+}
+
+// scopedKinds are the kinds of objects that are watched only if they're in
+// scope. They must only be created through the reconciler package, which
+// copies the labels that decide the scope from their owners.
+func scopedKinds() []client.Object {
+	return []client.Object{
+		&planetscalev2.VitessCluster{},
+		&planetscalev2.VitessCell{},
+		&planetscalev2.VitessKeyspace{},
+		&planetscalev2.VitessShard{},
+		&planetscalev2.VitessBackupStorage{},
+		&planetscalev2.EtcdLockserver{},
+		&corev1.Pod{},
+		&corev1.PersistentVolumeClaim{},
+		&corev1.Service{},
+		&appsv1.Deployment{},
+		&policyv1.PodDisruptionBudget{},
+	}
+}
+
+// CacheSelectors returns the selectors the cache should use to only watch
+// objects in scope, or nil if everything is in scope.
+func CacheSelectors() cache.SelectorsByObject {
+	if selector == nil {
+		return nil
+	}
+	selectors := cache.SelectorsByObject{}
+	for _, obj := range scopedKinds() {
+		selectors[obj] = cache.ObjectSelector{Label: selector}
+	}
+	return selectors
+}
+
+// CopyLabels copies the labels that decide the scope from owner to obj, so
+// obj is in scope whenever owner is.
+//
+// Note that this only mutates the provided, in-memory object; the caller is
+// responsible for sending the updated object to the server.
+func CopyLabels(obj, owner metav1.Object) {
+	if len(keys) == 0 {
+		return
+	}
+	ownerLabels := owner.GetLabels()
+	objLabels := obj.GetLabels()
+	for _, key := range keys {
+		value, ok := ownerLabels[key]
+		if !ok {
+			continue
+		}
+		if objLabels == nil {
+			objLabels = make(map[string]string, len(keys))
+		}
+		objLabels[key] = value
+	}
+	obj.SetLabels(objLabels)
+}
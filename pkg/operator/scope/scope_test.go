@@ -0,0 +1,82 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestCopyLabels(t *testing.T) {
+	defer SetSelector("")
+
+	if err := SetSelector("team=payments,tier in (gold,silver)"); err != nil {
+		t.Fatalf("SetSelector() error: %v", err)
+	}
+	if CacheSelectors() == nil {
+		t.Fatalf("CacheSelectors() = nil with a selector")
+	}
+
+	owner := &planetscalev2.VitessCluster{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		"team":  "payments",
+		"tier":  "gold",
+		"owner": "alice",
+	}}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		planetscalev2.ClusterLabel: "example",
+	}}}
+	CopyLabels(pod, owner)
+
+	want := map[string]string{
+		planetscalev2.ClusterLabel: "example",
+		"team":                     "payments",
+		"tier":                     "gold",
+	}
+	if !apiequality.Semantic.DeepEqual(pod.Labels, want) {
+		t.Errorf("CopyLabels() labels = %v; want %v", pod.Labels, want)
+	}
+	// The copied labels put the Pod in scope along with its owner.
+	if !Selector().Matches(labels.Set(pod.Labels)) {
+		t.Errorf("Selector() doesn't match Pod labels %v", pod.Labels)
+	}
+}
+
+func TestEmptySelector(t *testing.T) {
+	if err := SetSelector(""); err != nil {
+		t.Fatalf("SetSelector() error: %v", err)
+	}
+	if Selector() != nil || CacheSelectors() != nil {
+		t.Errorf("empty selector should leave everything in scope")
+	}
+
+	owner := &planetscalev2.VitessCluster{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "payments"}}}
+	pod := &corev1.Pod{}
+	CopyLabels(pod, owner)
+	if len(pod.Labels) != 0 {
+		t.Errorf("CopyLabels() labels = %v; want none", pod.Labels)
+	}
+
+	if err := SetSelector("team in (payments"); err == nil {
+		t.Errorf("SetSelector() with an invalid selector succeeded")
+	}
+}
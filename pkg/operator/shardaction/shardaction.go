@@ -0,0 +1,119 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package shardaction defines annotations that request one-off imperative
+actions on a VitessShard, such as a planned reparent to a particular tablet.
+
+Unlike drains and rollouts, which converge on a desired state, each action is
+executed at most once. The controller removes the annotation before it starts
+the action, and reports the outcome in events and in the ActionSucceeded
+condition of the VitessShard status. To run the same action again, add the
+annotation again.
+*/
+package shardaction
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationPrefix is the prefix for all shard action annotations.
+	AnnotationPrefix = "planetscale.com"
+
+	// ReparentToAnnotation requests a planned reparent of the shard to the
+	// tablet whose alias (e.g. "zone1-0123456789") is the annotation value.
+	ReparentToAnnotation = AnnotationPrefix + "/" + "reparent-to"
+	// BackupNowAnnotation requests an immediate backup from the tablet whose
+	// alias is the annotation value. If the value is empty, the controller
+	// picks a healthy non-primary tablet.
+	BackupNowAnnotation = AnnotationPrefix + "/" + "backup-now"
+	// RestartTabletAnnotation requests that the Pod of the tablet whose alias
+	// is the annotation value be deleted and recreated. The current primary
+	// can't be restarted this way; reparent away from it first.
+	RestartTabletAnnotation = AnnotationPrefix + "/" + "restart-tablet"
+	// DebugTabletAnnotation requests that an ephemeral debug container be
+	// added to the Pod of the tablet whose alias is the annotation value.
+	// The container exits on its own after a while, set by the operator's
+	// --debug_container_duration flag.
+	DebugTabletAnnotation = AnnotationPrefix + "/" + "debug-tablet"
+)
+
+// Action is a pending action request.
+type Action struct {
+	// Name is a one-word, PascalCase name for the action,
+	// suitable for event and condition reasons.
+	Name string
+	// Annotation is the annotation key that requested the action.
+	Annotation string
+	// Value is the annotation value.
+	Value string
+}
+
+// actions lists all supported actions in the order they're executed
+// if several are requested at once.
+var actions = []struct {
+	name       string
+	annotation string
+}{
+	{name: "ReparentTo", annotation: ReparentToAnnotation},
+	{name: "RestartTablet", annotation: RestartTabletAnnotation},
+	{name: "BackupNow", annotation: BackupNowAnnotation},
+	{name: "DebugTablet", annotation: DebugTabletAnnotation},
+}
+
+// Pending returns the actions requested on an object, in execution order.
+func Pending(obj metav1.Object) []Action {
+	ann := obj.GetAnnotations()
+	var pending []Action
+	for _, a := range actions {
+		if value, ok := ann[a.annotation]; ok {
+			pending = append(pending, Action{Name: a.name, Annotation: a.annotation, Value: value})
+		}
+	}
+	return pending
+}
+
+/*
+Request annotates an object to request an action.
+
+If the same action was already requested, the value is replaced.
+
+Note that this only mutates the provided, in-memory object to add the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func Request(obj metav1.Object, annotation, value string) {
+	ann := obj.GetAnnotations()
+	if ann == nil {
+		ann = make(map[string]string, 1)
+	}
+	ann[annotation] = value
+	obj.SetAnnotations(ann)
+}
+
+/*
+Clear removes the annotation that requested an action.
+
+Note that this only mutates the provided, in-memory object to remove the
+annotation; the caller is responsible for sending the updated object to
+the server.
+*/
+func Clear(obj metav1.Object, action Action) {
+	ann := obj.GetAnnotations()
+	delete(ann, action.Annotation)
+	obj.SetAnnotations(ann)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardaction
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPending(t *testing.T) {
+	obj := &metav1.ObjectMeta{}
+	if got := Pending(obj); len(got) != 0 {
+		t.Errorf("Pending() = %v; want none", got)
+	}
+
+	Request(obj, BackupNowAnnotation, "")
+	Request(obj, ReparentToAnnotation, "zone1-0000000101")
+	obj.Annotations["unrelated"] = "annotation"
+
+	want := []Action{
+		{Name: "ReparentTo", Annotation: ReparentToAnnotation, Value: "zone1-0000000101"},
+		{Name: "BackupNow", Annotation: BackupNowAnnotation, Value: ""},
+	}
+	got := Pending(obj)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Pending() = %v; want %v", got, want)
+	}
+
+	for _, action := range got {
+		Clear(obj, action)
+	}
+	if got := Pending(obj); len(got) != 0 {
+		t.Errorf("Pending() after Clear() = %v; want none", got)
+	}
+	if _, ok := obj.Annotations["unrelated"]; !ok {
+		t.Errorf("Clear() removed an unrelated annotation")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package shutdown lets in-flight reconciles finish when the operator stops.
+
+When the operator receives SIGTERM, the manager stops handing out new
+reconcile requests right away, but the reconciles that are already running
+would also see their context cancelled immediately, since it's derived from
+the manager's context. That can interrupt multi-step operations, like a
+planned reparent, at an awkward point.
+
+Reconcilers should instead derive their context with Context, which keeps
+running after SIGTERM until the shutdown timeout passes. Long-running steps
+can check Stopping to avoid starting anything new once shutdown has begun.
+*/
+package shutdown
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+
+	"planetscale.dev/vitess-operator/pkg/operator/logging"
+)
+
+var (
+	// drainCtx is cancelled once in-flight reconciles run out of time to
+	// finish after shutdown begins.
+	drainCtx = context.Background()
+	// stopping is set once shutdown begins.
+	stopping atomic.Bool
+)
+
+/*
+SetupSignalHandler returns a context for the manager that's cancelled on
+SIGTERM or SIGINT, so it stops starting new reconciles. Contexts returned by
+Context are only cancelled once timeout has passed after that.
+
+It can only be called once.
+*/
+func SetupSignalHandler(timeout time.Duration) context.Context {
+	stopCtx := signals.SetupSignalHandler()
+	drainCtx = start(stopCtx, timeout)
+	return stopCtx
+}
+
+// start returns a context that's cancelled timeout after stopCtx is done.
+func start(stopCtx context.Context, timeout time.Duration) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCtx.Done()
+		stopping.Store(true)
+		logging.FromContext(ctx).Infof("Shutting down: waiting up to %v for in-flight reconciles to finish", timeout)
+		time.AfterFunc(timeout, cancel)
+	}()
+	return ctx
+}
+
+// Context returns a context with the values of parent that's cancelled only
+// once in-flight reconciles run out of time to finish after shutdown begins,
+// rather than as soon as parent is cancelled.
+func Context(parent context.Context) context.Context {
+	return &drainingContext{Context: drainCtx, values: parent}
+}
+
+// Stopping returns whether the operator is shutting down, in which case
+// reconcilers shouldn't start new long-running operations.
+func Stopping() bool {
+	return stopping.Load()
+}
+
+// drainingContext takes its cancellation from one context, and its values
+// from another.
+type drainingContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c *drainingContext) Value(key any) any {
+	return c.values.Value(key)
+}
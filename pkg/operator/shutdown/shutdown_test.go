@@ -0,0 +1,55 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type testKey struct{}
+
+func TestContext(t *testing.T) {
+	stopCtx, stop := context.WithCancel(context.Background())
+	drainCtx = start(stopCtx, 50*time.Millisecond)
+
+	parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), testKey{}, "value"))
+	ctx := Context(parent)
+	if got := ctx.Value(testKey{}); got != "value" {
+		t.Errorf("Value() = %v; want value of parent", got)
+	}
+
+	// Cancelling the parent, as the manager does on SIGTERM, doesn't cancel
+	// the reconcile right away.
+	cancelParent()
+	stop()
+	select {
+	case <-ctx.Done():
+		t.Fatalf("context was cancelled as soon as shutdown began")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if !Stopping() {
+		t.Errorf("Stopping() = false after shutdown began")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("context wasn't cancelled after the shutdown timeout")
+	}
+}
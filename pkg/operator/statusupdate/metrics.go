@@ -0,0 +1,51 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusupdate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+const (
+	metricsSubsystemName = "status_update"
+
+	// unchangedOutcome means the status was already up to date.
+	unchangedOutcome = "unchanged"
+	// deferredOutcome means the write was delayed to coalesce it with later ones.
+	deferredOutcome = "deferred"
+	// writtenOutcome means the status was written.
+	writtenOutcome = "written"
+	// failedOutcome means writing the status failed.
+	failedOutcome = "failed"
+)
+
+var (
+	statusUpdateCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "count",
+		Help:      "Status updates computed for a Kind, by whether they were written",
+	}, []string{"kind", "outcome"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		statusUpdateCount,
+	)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package statusupdate coalesces the status writes of reconcilers.
+
+Every event on an object a controller watches, like a tablet Pod becoming
+ready, triggers a reconcile of its owner, and every reconcile may write the
+owner's status. For large shards and clusters, that means a burst of status
+writes on every resync. A Writer skips writes that wouldn't change anything,
+and writes each object's status at most once per interval, so a burst of
+reconciles results in a single write of the latest status.
+*/
+package statusupdate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Writer writes the status of objects of one kind.
+type Writer struct {
+	client   client.Client
+	kind     string
+	interval time.Duration
+	now      func() time.Time
+
+	mu sync.Mutex
+	// written records when the status of each object was last written, for
+	// objects written less than interval ago.
+	written map[client.ObjectKey]time.Time
+}
+
+// NewWriter returns a Writer for objects of the given kind that writes each
+// object's status at most once per interval. An interval of 0 means every
+// change is written right away.
+func NewWriter(c client.Client, kind string, interval time.Duration) *Writer {
+	return &Writer{
+		client:   c,
+		kind:     kind,
+		interval: interval,
+		now:      time.Now,
+		written:  make(map[client.ObjectKey]time.Time),
+	}
+}
+
+/*
+Update writes the status of obj, which the caller has already set to
+newStatus, unless it's semantically equal to oldStatus.
+
+If the status was written less than the interval ago, Update doesn't write it
+yet, and instead returns how long the caller should wait before reconciling
+again to write the latest status then. Urgent writes, like those reporting a
+new observedGeneration that clients may be waiting for, are never delayed.
+*/
+func (w *Writer) Update(ctx context.Context, obj client.Object, newStatus, oldStatus interface{}, urgent bool) (time.Duration, error) {
+	if apiequality.Semantic.DeepEqual(newStatus, oldStatus) {
+		statusUpdateCount.WithLabelValues(w.kind, unchangedOutcome).Inc()
+		return 0, nil
+	}
+
+	key := client.ObjectKeyFromObject(obj)
+	if delay := w.delay(key, urgent); delay > 0 {
+		statusUpdateCount.WithLabelValues(w.kind, deferredOutcome).Inc()
+		return delay, nil
+	}
+
+	if err := w.client.Status().Update(ctx, obj); err != nil {
+		statusUpdateCount.WithLabelValues(w.kind, failedOutcome).Inc()
+		// Let the retry write right away.
+		w.forget(key)
+		return 0, err
+	}
+	statusUpdateCount.WithLabelValues(w.kind, writtenOutcome).Inc()
+	return 0, nil
+}
+
+// delay returns how long to wait before writing the status of an object,
+// or 0 if it should be written now, in which case the write is recorded.
+func (w *Writer) delay(key client.ObjectKey, urgent bool) time.Duration {
+	if w.interval <= 0 {
+		return 0
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	if last, ok := w.written[key]; ok && !urgent {
+		if delay := last.Add(w.interval).Sub(now); delay > 0 {
+			return delay
+		}
+	}
+	w.written[key] = now
+
+	// Forget objects whose interval has passed, so the map only holds
+	// objects that were written recently.
+	for k, last := range w.written {
+		if now.Sub(last) >= w.interval {
+			delete(w.written, k)
+		}
+	}
+	return 0
+}
+
+// forget clears the record of when an object's status was last written.
+func (w *Writer) forget(key client.ObjectKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.written, key)
+}
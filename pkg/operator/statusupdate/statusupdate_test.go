@@ -0,0 +1,88 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusupdate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWriterUpdate(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"}}
+	c := fake.NewClientBuilder().WithObjects(pod.DeepCopy()).Build()
+
+	now := time.Unix(1000, 0)
+	w := NewWriter(c, "Pod", 10*time.Second)
+	w.now = func() time.Time { return now }
+
+	// update sets a new status message and returns what Update returned.
+	update := func(message string, urgent bool) time.Duration {
+		t.Helper()
+		obj := &corev1.Pod{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(pod), obj); err != nil {
+			t.Fatalf("Get() error: %v", err)
+		}
+		oldStatus := obj.Status
+		obj.Status.Message = message
+		delay, err := w.Update(ctx, obj, &obj.Status, &oldStatus, urgent)
+		if err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+		return delay
+	}
+	written := func() string {
+		t.Helper()
+		obj := &corev1.Pod{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(pod), obj); err != nil {
+			t.Fatalf("Get() error: %v", err)
+		}
+		return obj.Status.Message
+	}
+
+	if delay := update("first", false); delay != 0 || written() != "first" {
+		t.Fatalf("first write: delay = %v, status = %q; want 0, %q", delay, written(), "first")
+	}
+
+	// Unchanged status is never delayed, since there's nothing to write.
+	if delay := update("first", false); delay != 0 {
+		t.Errorf("unchanged status: delay = %v; want 0", delay)
+	}
+
+	// A change soon after is deferred until the interval passes.
+	now = now.Add(4 * time.Second)
+	if delay := update("second", false); delay != 6*time.Second || written() != "first" {
+		t.Errorf("deferred write: delay = %v, status = %q; want %v, %q", delay, written(), 6*time.Second, "first")
+	}
+
+	// Urgent changes are written right away.
+	if delay := update("urgent", true); delay != 0 || written() != "urgent" {
+		t.Errorf("urgent write: delay = %v, status = %q; want 0, %q", delay, written(), "urgent")
+	}
+
+	// Once the interval passes, the latest status is written.
+	now = now.Add(10 * time.Second)
+	if delay := update("third", false); delay != 0 || written() != "third" {
+		t.Errorf("write after interval: delay = %v, status = %q; want 0, %q", delay, written(), "third")
+	}
+}
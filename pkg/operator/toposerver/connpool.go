@@ -29,18 +29,31 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"vitess.io/vitess/go/vt/topo"
-
-	// Register the common topo plugins.
-	_ "vitess.io/vitess/go/vt/topo/consultopo"
-	_ "vitess.io/vitess/go/vt/topo/etcd2topo"
-	_ "vitess.io/vitess/go/vt/topo/zk2topo"
-	_ "vitess.io/vitess/go/vt/topo/k8stopo"
+	"vitess.io/vitess/go/vt/topo/consultopo"
+	"vitess.io/vitess/go/vt/topo/etcd2topo"
+	"vitess.io/vitess/go/vt/topo/k8stopo"
+	"vitess.io/vitess/go/vt/topo/zk2topo"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/faults"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
 )
 
+// factories are the common topo plugins, by implementation name. Connections
+// are opened with them directly, rather than with topo.OpenServer, so they can
+// be wrapped for tracing.
+var factories = map[string]topo.Factory{
+	lockserver.VitessEtcdImplementationName:   etcd2topo.Factory{},
+	lockserver.VitessZkImplementationName:     zk2topo.Factory{},
+	lockserver.VitessConsulImplementationName: consultopo.Factory{},
+	"k8s": k8stopo.Factory{},
+}
+
 const (
 	// idleTTL is how long to keep a connection around before closing it.
 	// If someone opens the connection before then, the TTL is refreshed.
@@ -68,7 +81,7 @@ const (
 )
 
 // pool is the process-wide shared pool of connections.
-var pool = &connPool{conns: make(map[planetscalev2.VitessLockserverParams]*Conn)}
+var pool = &connPool{conns: make(map[connParams]*Conn)}
 
 var log = logrus.WithField("component", "toposerver.connpool")
 
@@ -82,15 +95,52 @@ func init() {
 	}()
 }
 
+// connParams identify a pooled connection. Unlike VitessLockserverParams,
+// they only contain values, so they can be compared to find a cached
+// connection.
+type connParams struct {
+	Implementation string
+	Address        string
+	RootPath       string
+	// TLS holds the paths to TLS files, if any.
+	TLS tlsFiles
+}
+
 // Open returns a topo server connection for the given params.
+// If params configure TLS, the referenced Secrets are read from namespace.
 // If the returned error is nil, you must call Close() on the returned
 // connection when you're done using it.
-func Open(ctx context.Context, params planetscalev2.VitessLockserverParams) (*Conn, error) {
+func Open(ctx context.Context, c client.Reader, namespace string, lockserverParams planetscalev2.VitessLockserverParams) (_ *Conn, finalErr error) {
+	ctx, span := tracing.StartSpan(ctx, "toposerver.Open",
+		attribute.String("implementation", lockserverParams.Implementation),
+		attribute.String("address", lockserverParams.Address),
+	)
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
 	startTime := time.Now()
 	defer func() {
 		openLatency.Observe(time.Since(startTime).Seconds())
 	}()
 
+	// Let end-to-end tests simulate a slow topology server.
+	if err := faults.DelayTopo(ctx); err != nil {
+		return nil, err
+	}
+
+	params := connParams{
+		Implementation: lockserverParams.Implementation,
+		Address:        lockserverParams.Address,
+		RootPath:       lockserverParams.RootPath,
+	}
+	// Other plugins only read TLS settings from the operator's own flags.
+	if lockserverParams.TLS != nil && lockserverParams.Implementation == lockserver.VitessEtcdImplementationName {
+		files, err := loadTLS(ctx, c, namespace, lockserverParams.TLS)
+		if err != nil {
+			return nil, err
+		}
+		params.TLS = files
+	}
+
 	// Hold the openMu RLock for as long as we're trying to get a connection,
 	// to prevent the connection GC from closing connections.
 	// Other Open attempts can happen concurrently, however.
@@ -108,7 +158,7 @@ func Open(ctx context.Context, params planetscalev2.VitessLockserverParams) (*Co
 
 type connPool struct {
 	// conns is the set of active connections to use.
-	conns map[planetscalev2.VitessLockserverParams]*Conn
+	conns map[connParams]*Conn
 
 	// deadConns is a list of connections that have gone bad and need to be
 	// closed one no one is using them anymore. We can add conns to this list
@@ -126,7 +176,7 @@ type connPool struct {
 
 // get returns a connection attempt from the pool,
 // creating a new attempt if necessary.
-func (p *connPool) get(params planetscalev2.VitessLockserverParams) *Conn {
+func (p *connPool) get(params connParams) *Conn {
 	pool.mapMu.Lock()
 	defer pool.mapMu.Unlock()
 
@@ -155,7 +205,7 @@ func (p *connPool) get(params planetscalev2.VitessLockserverParams) *Conn {
 
 // checkConn performs a liveness check on the conection,
 // and removes it from the cache if it fails.
-func (p *connPool) checkConn(params planetscalev2.VitessLockserverParams) {
+func (p *connPool) checkConn(params connParams) {
 	// Take the usual locks as if we are opening the connection like anyone else.
 	p.openMu.RLock()
 	defer pool.openMu.RUnlock()
@@ -296,12 +346,12 @@ type Conn struct {
 	refCount    int64
 	lastOpened  time.Time
 	lastChecked time.Time
-	params      planetscalev2.VitessLockserverParams
+	params      connParams
 }
 
 // newConn starts a new connection attempt in the background.
 // It returns a Conn, which can be used to wait for the attempt.
-func newConn(params planetscalev2.VitessLockserverParams) *Conn {
+func newConn(params connParams) *Conn {
 	now := time.Now()
 	c := &Conn{
 		params:      params,
@@ -325,7 +375,15 @@ func newConn(params planetscalev2.VitessLockserverParams) *Conn {
 
 		// OpenServer has a built-in timeout that's not configurable.
 		// TODO(enisoc): Upstream a change to make the timeout configurable.
-		c.Server, c.connectErr = topo.OpenServer(params.Implementation, params.Address, params.RootPath)
+		factory, ok := factories[params.Implementation]
+		if params.TLS != (tlsFiles{}) {
+			factory, ok = etcdTLSFactory{files: params.TLS}, true
+		}
+		if ok {
+			c.Server, c.connectErr = topo.NewWithFactory(tracedFactory{Factory: factory}, params.Address, params.RootPath)
+		} else {
+			c.connectErr = topo.NewError(topo.NoImplementation, params.Implementation)
+		}
 		if c.connectErr == nil {
 			connLog.Info("successfully connected to Vitess topology server")
 			connectSuccesses.Inc()
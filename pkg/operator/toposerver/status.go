@@ -0,0 +1,94 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toposerver
+
+import (
+	"sort"
+)
+
+const (
+	// ConnConnecting means the connection attempt hasn't finished yet.
+	ConnConnecting = "connecting"
+	// ConnConnected means the connection is up, as of its last liveness check.
+	ConnConnected = "connected"
+	// ConnFailed means the connection attempt failed.
+	ConnFailed = "failed"
+	// ConnDead means the connection failed a liveness check, and is waiting
+	// to be closed.
+	ConnDead = "dead"
+)
+
+// ConnStatus describes a connection in the pool.
+type ConnStatus struct {
+	Implementation string `json:"implementation"`
+	Address        string `json:"address"`
+	RootPath       string `json:"rootPath"`
+	// State is one of ConnConnecting, ConnConnected, ConnFailed or ConnDead.
+	State string `json:"state"`
+	// Error is why the connection attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+	// Refs is how many callers are using the connection.
+	Refs int64 `json:"refs"`
+}
+
+// Status returns the state of all connections in the pool, including
+// those that failed but haven't been cleaned up yet.
+func Status() []ConnStatus {
+	pool.mapMu.Lock()
+	defer pool.mapMu.Unlock()
+
+	conns := make([]ConnStatus, 0, len(pool.conns)+len(pool.deadConns))
+	for _, conn := range pool.conns {
+		status := conn.status()
+		switch {
+		case conn.succeeded():
+			status.State = ConnConnected
+		case conn.failed():
+			status.State = ConnFailed
+			status.Error = conn.connectErr.Error()
+		default:
+			status.State = ConnConnecting
+		}
+		conns = append(conns, status)
+	}
+	for _, conn := range pool.deadConns {
+		status := conn.status()
+		status.State = ConnDead
+		conns = append(conns, status)
+	}
+
+	sort.Slice(conns, func(i, j int) bool {
+		if conns[i].Address != conns[j].Address {
+			return conns[i].Address < conns[j].Address
+		}
+		return conns[i].RootPath < conns[j].RootPath
+	})
+	return conns
+}
+
+// status returns the identity and ref count of a connection.
+func (c *Conn) status() ConnStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return ConnStatus{
+		Implementation: c.params.Implementation,
+		Address:        c.params.Address,
+		RootPath:       c.params.RootPath,
+		Refs:           c.refCount,
+	}
+}
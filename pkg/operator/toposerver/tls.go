@@ -0,0 +1,139 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toposerver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/etcd2topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/contenthash"
+)
+
+// tlsRootDir is where we write TLS files loaded from Secrets.
+// The etcd client can only load TLS settings from files.
+var tlsRootDir = filepath.Join(os.TempDir(), "vitess-operator", "topo-tls")
+
+// tlsFiles are the paths to the TLS files for a lockserver connection.
+type tlsFiles struct {
+	CertPath string
+	KeyPath  string
+	CAPath   string
+}
+
+// loadTLS reads the Secrets referenced by a lockserver TLS spec, and writes
+// them into a directory named after a hash of their contents. Rotating a
+// Secret therefore results in a new pooled connection, while the old one is
+// closed once it's idle.
+func loadTLS(ctx context.Context, c client.Reader, namespace string, spec *planetscalev2.VitessLockserverTLSSpec) (tlsFiles, error) {
+	data := map[string][]byte{}
+	var err error
+	if data["cert.pem"], err = secretData(ctx, c, namespace, &spec.ClientCertSecret); err != nil {
+		return tlsFiles{}, err
+	}
+	if data["key.pem"], err = secretData(ctx, c, namespace, &spec.ClientKeySecret); err != nil {
+		return tlsFiles{}, err
+	}
+	if spec.CACertSecret != nil {
+		if data["ca.pem"], err = secretData(ctx, c, namespace, spec.CACertSecret); err != nil {
+			return tlsFiles{}, err
+		}
+	}
+
+	dir := filepath.Join(tlsRootDir, contenthash.BytesMap(data))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := writeTLSDir(dir, data); err != nil {
+			return tlsFiles{}, fmt.Errorf("can't write lockserver TLS files: %v", err)
+		}
+	}
+
+	files := tlsFiles{
+		CertPath: filepath.Join(dir, "cert.pem"),
+		KeyPath:  filepath.Join(dir, "key.pem"),
+	}
+	if spec.CACertSecret != nil {
+		files.CAPath = filepath.Join(dir, "ca.pem")
+	}
+	return files, nil
+}
+
+// writeTLSDir writes files into a temporary directory and then renames it,
+// so concurrent callers never see a partially written directory.
+func writeTLSDir(dir string, data map[string][]byte) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for name, contents := range data {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), contents, 0600); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		// Someone else may have written the same files first.
+		if _, statErr := os.Stat(dir); statErr == nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// secretData returns the item referenced by a SecretSource.
+func secretData(ctx context.Context, c client.Reader, namespace string, src *planetscalev2.SecretSource) ([]byte, error) {
+	if src.Name == "" {
+		return nil, fmt.Errorf("lockserver TLS secrets must be referenced by name, since the operator can't read Pod volumes")
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: src.Name}, secret); err != nil {
+		return nil, fmt.Errorf("can't get lockserver TLS Secret %v: %v", src.Name, err)
+	}
+	value, ok := secret.Data[src.Key]
+	if !ok {
+		return nil, fmt.Errorf("lockserver TLS Secret %v has no key %q", src.Name, src.Key)
+	}
+	return value, nil
+}
+
+// etcdTLSFactory connects to etcd with per-connection TLS files, instead of
+// the process-wide settings that topo.OpenServer would use. The same files
+// are used to connect to cell-local lockservers, like Vitess components do.
+type etcdTLSFactory struct {
+	files tlsFiles
+}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f etcdTLSFactory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f etcdTLSFactory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	return etcd2topo.NewServerWithOpts(serverAddr, root, f.files.CertPath, f.files.KeyPath, f.files.CAPath)
+}
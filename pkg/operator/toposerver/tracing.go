@@ -0,0 +1,171 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toposerver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"vitess.io/vitess/go/vt/topo"
+
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
+)
+
+// tracedFactory wraps every connection that a topo.Factory creates, for the
+// global cell and for local cells alike, in a tracedConn.
+//
+// Topo plugins like etcd2 dial their servers with their own clients, instead
+// of through Vitess's grpcclient, so the dial options that tracing adds there
+// don't reach them.
+type tracedFactory struct {
+	topo.Factory
+}
+
+// Create is part of the topo.Factory interface.
+func (f tracedFactory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	conn, err := f.Factory.Create(cell, serverAddr, root)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, cell: cell}, nil
+}
+
+// tracedConn records a span for each call to a topo.Conn.
+type tracedConn struct {
+	topo.Conn
+	cell string
+}
+
+func (c *tracedConn) startSpan(ctx context.Context, method, path string) (context.Context, trace.Span) {
+	return tracing.StartSpan(ctx, "topo."+method,
+		attribute.String("cell", c.cell),
+		attribute.String("path", path),
+	)
+}
+
+// endSpan ends a span of a topo call. NoNode isn't recorded as an error,
+// since callers often check whether something exists that way.
+func endSpan(span trace.Span, err error) {
+	if topo.IsErrType(err, topo.NoNode) {
+		err = nil
+	}
+	tracing.EndSpan(span, err)
+}
+
+// ListDir is part of the topo.Conn interface.
+func (c *tracedConn) ListDir(ctx context.Context, dirPath string, full bool) ([]topo.DirEntry, error) {
+	ctx, span := c.startSpan(ctx, "ListDir", dirPath)
+	entries, err := c.Conn.ListDir(ctx, dirPath, full)
+	endSpan(span, err)
+	return entries, err
+}
+
+// Create is part of the topo.Conn interface.
+func (c *tracedConn) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	ctx, span := c.startSpan(ctx, "Create", filePath)
+	version, err := c.Conn.Create(ctx, filePath, contents)
+	endSpan(span, err)
+	return version, err
+}
+
+// Update is part of the topo.Conn interface.
+func (c *tracedConn) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	ctx, span := c.startSpan(ctx, "Update", filePath)
+	newVersion, err := c.Conn.Update(ctx, filePath, contents, version)
+	endSpan(span, err)
+	return newVersion, err
+}
+
+// Get is part of the topo.Conn interface.
+func (c *tracedConn) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	ctx, span := c.startSpan(ctx, "Get", filePath)
+	contents, version, err := c.Conn.Get(ctx, filePath)
+	endSpan(span, err)
+	return contents, version, err
+}
+
+// List is part of the topo.Conn interface.
+func (c *tracedConn) List(ctx context.Context, filePathPrefix string) ([]topo.KVInfo, error) {
+	ctx, span := c.startSpan(ctx, "List", filePathPrefix)
+	infos, err := c.Conn.List(ctx, filePathPrefix)
+	endSpan(span, err)
+	return infos, err
+}
+
+// Delete is part of the topo.Conn interface.
+func (c *tracedConn) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	ctx, span := c.startSpan(ctx, "Delete", filePath)
+	err := c.Conn.Delete(ctx, filePath, version)
+	endSpan(span, err)
+	return err
+}
+
+// Lock is part of the topo.Conn interface.
+func (c *tracedConn) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	ctx, span := c.startSpan(ctx, "Lock", dirPath)
+	lock, err := c.Conn.Lock(ctx, dirPath, contents)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedLock{LockDescriptor: lock, conn: c, dirPath: dirPath}, nil
+}
+
+// TryLock is part of the topo.Conn interface.
+func (c *tracedConn) TryLock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	ctx, span := c.startSpan(ctx, "TryLock", dirPath)
+	lock, err := c.Conn.TryLock(ctx, dirPath, contents)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedLock{LockDescriptor: lock, conn: c, dirPath: dirPath}, nil
+}
+
+// Watch is part of the topo.Conn interface. The span only covers starting
+// the watch.
+func (c *tracedConn) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, error) {
+	_, span := c.startSpan(ctx, "Watch", filePath)
+	current, changes, err := c.Conn.Watch(ctx, filePath)
+	endSpan(span, err)
+	return current, changes, err
+}
+
+// WatchRecursive is part of the topo.Conn interface. The span only covers
+// starting the watch.
+func (c *tracedConn) WatchRecursive(ctx context.Context, path string) ([]*topo.WatchDataRecursive, <-chan *topo.WatchDataRecursive, error) {
+	_, span := c.startSpan(ctx, "WatchRecursive", path)
+	current, changes, err := c.Conn.WatchRecursive(ctx, path)
+	endSpan(span, err)
+	return current, changes, err
+}
+
+// tracedLock records a span for releasing a lock taken through a tracedConn.
+type tracedLock struct {
+	topo.LockDescriptor
+	conn    *tracedConn
+	dirPath string
+}
+
+// Unlock is part of the topo.LockDescriptor interface.
+func (l *tracedLock) Unlock(ctx context.Context) error {
+	ctx, span := l.conn.startSpan(ctx, "Unlock", l.dirPath)
+	err := l.LockDescriptor.Unlock(ctx)
+	endSpan(span, err)
+	return err
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toposerver
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestTracedFactory(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(prev)
+
+	_, factory := memorytopo.NewServerAndFactory("zone1")
+	ts, err := topo.NewWithFactory(tracedFactory{Factory: factory}, "", "")
+	if err != nil {
+		t.Fatalf("NewWithFactory() error: %v", err)
+	}
+	defer ts.Close()
+
+	ctx := context.Background()
+	// A missing node isn't an error.
+	if _, err := ts.GetKeyspace(ctx, "commerce"); !topo.IsErrType(err, topo.NoNode) {
+		t.Fatalf("GetKeyspace() error = %v; want NoNode", err)
+	}
+	if err := ts.CreateKeyspace(ctx, "commerce", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace() error: %v", err)
+	}
+	// Creating it again fails.
+	if err := ts.CreateKeyspace(ctx, "commerce", &topodatapb.Keyspace{}); err == nil {
+		t.Fatalf("CreateKeyspace() succeeded twice")
+	}
+	// Calls to local cells are traced too.
+	if _, err := ts.GetTabletAliasesByCell(ctx, "zone1"); err != nil {
+		t.Fatalf("GetTabletAliasesByCell() error: %v", err)
+	}
+
+	type span struct {
+		name, cell string
+		status     codes.Code
+	}
+	var got []span
+	for _, s := range recorder.Ended() {
+		var cell string
+		for _, kv := range s.Attributes() {
+			if kv.Key == "cell" {
+				cell = kv.Value.AsString()
+			}
+		}
+		got = append(got, span{name: s.Name(), cell: cell, status: s.Status().Code})
+	}
+	want := []span{
+		{"topo.Get", topo.GlobalCell, codes.Unset},
+		{"topo.Create", topo.GlobalCell, codes.Unset},
+		{"topo.Create", topo.GlobalCell, codes.Error},
+		{"topo.ListDir", "zone1", codes.Unset},
+	}
+	// Ignore other calls, like reading the CellInfo of zone1.
+	i := 0
+	for _, s := range got {
+		if i < len(want) && s == want[i] {
+			i++
+		}
+	}
+	if i != len(want) {
+		t.Errorf("spans = %v; want them to include %v in order", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package tracing records OpenTelemetry spans of reconciles, and of the
+Kubernetes, topology and Vitess RPCs they make, and exports them over OTLP.
+
+Until Start is called with an endpoint, spans go to the no-op global
+TracerProvider, so instrumented code costs next to nothing.
+*/
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/grpcclient"
+
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+)
+
+const (
+	// tracerName is the instrumentation name of the operator's spans.
+	tracerName = "planetscale.dev/vitess-operator"
+	// serviceName is the service.name of the operator's spans, unless
+	// OTEL_SERVICE_NAME says otherwise.
+	serviceName = "vitess-operator"
+)
+
+/*
+Start installs a global TracerProvider that exports spans to the configured
+OTLP endpoint, and adds client spans to the gRPC connections that Vitess
+libraries open, such as to vttablets. Topology servers are dialed by their own
+clients, so package toposerver traces calls to them instead. It does nothing if
+no endpoint is set.
+
+The returned function flushes buffered spans, and must be called before the
+process exits.
+*/
+func Start(ctx context.Context, config environment.Tracing) (func(context.Context) error, error) {
+	if config.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.OTLPEndpoint)}
+	if config.OTLPInsecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName)),
+		resource.Default(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	grpcclient.RegisterGRPCDialOptions(func(opts []grpc.DialOption) ([]grpc.DialOption, error) {
+		return append(opts,
+			grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+		), nil
+	})
+
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as a child of the span in ctx, if any. The caller
+// must end the returned span.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends a span, and marks it as failed if err is not nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartReconcile starts the root span of a controller's Reconcile call for
+// the requested object. The caller must end the returned span.
+func StartReconcile(ctx context.Context, controller string, request reconcile.Request) (context.Context, trace.Span) {
+	return StartSpan(ctx, controller+".Reconcile",
+		attribute.String("k8s.namespace.name", request.Namespace),
+		attribute.String("k8s.object.name", request.Name),
+	)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+)
+
+func TestStartReconcile(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(prev)
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "example"}}
+	ctx, root := StartReconcile(context.Background(), "vitessshard", request)
+	_, child := StartSpan(ctx, "toposerver.Open", attribute.String("address", "etcd:2379"))
+	child.End()
+	root.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %v ended spans; want 2", len(spans))
+	}
+	gotChild, gotRoot := spans[0], spans[1]
+	if got, want := gotRoot.Name(), "vitessshard.Reconcile"; got != want {
+		t.Errorf("root span name = %q; want %q", got, want)
+	}
+	if got, want := gotChild.Parent().SpanID(), gotRoot.SpanContext().SpanID(); got != want {
+		t.Errorf("child span parent = %v; want %v", got, want)
+	}
+	attrs := map[attribute.Key]string{}
+	for _, kv := range gotRoot.Attributes() {
+		attrs[kv.Key] = kv.Value.AsString()
+	}
+	if got, want := attrs["k8s.namespace.name"], "ns"; got != want {
+		t.Errorf("k8s.namespace.name = %q; want %q", got, want)
+	}
+	if got, want := attrs["k8s.object.name"], "example"; got != want {
+		t.Errorf("k8s.object.name = %q; want %q", got, want)
+	}
+}
+
+func TestStartWithoutEndpoint(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	shutdown, err := Start(context.Background(), environment.Tracing{})
+	if err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error: %v", err)
+	}
+	if otel.GetTracerProvider() != prev {
+		t.Errorf("Start() without an endpoint replaced the global TracerProvider")
+	}
+}
+
+func TestEndSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(prev)
+
+	_, ok := StartSpan(context.Background(), "ok")
+	EndSpan(ok, nil)
+	_, failed := StartSpan(context.Background(), "failed")
+	EndSpan(failed, errors.New("connection refused"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %v ended spans; want 2", len(spans))
+	}
+	if got := spans[0].Status().Code; got != codes.Unset {
+		t.Errorf("status of span without error = %v; want %v", got, codes.Unset)
+	}
+	if got := spans[1].Status(); got.Code != codes.Error || got.Description != "connection refused" {
+		t.Errorf("status of span with error = %v; want %v with the error", got, codes.Error)
+	}
+	if got := len(spans[1].Events()); got != 1 {
+		t.Errorf("span with error has %v events; want the recorded error", got)
+	}
+}
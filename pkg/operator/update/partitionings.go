@@ -52,6 +52,37 @@ func PartitioningSet(dst *[]planetscalev2.VitessKeyspacePartitioning, src []plan
 	*dst = result
 }
 
+// KeyspacePaused updates the per-shard 'paused' overrides in 'dst' based on
+// values in 'src'. It does not update any other values.
+func KeyspacePaused(dst, src *planetscalev2.VitessKeyspaceTemplate) {
+	// Check that the keyspace definitions line up.
+	if !keyspacePartitioningsAreValid(dst.Partitionings, src.Partitionings) {
+		return
+	}
+
+	for i := range dst.Partitionings {
+		dstPartitioning := &dst.Partitionings[i]
+		srcPartitioning := &src.Partitionings[i]
+
+		if dstPartitioning.Equal != nil {
+			dstPartitioning.Equal.ShardTemplate.Paused = copyBool(srcPartitioning.Equal.ShardTemplate.Paused)
+		}
+		if dstPartitioning.Custom != nil {
+			for j := range dstPartitioning.Custom.Shards {
+				dstPartitioning.Custom.Shards[j].Paused = copyBool(srcPartitioning.Custom.Shards[j].Paused)
+			}
+		}
+	}
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	val := *b
+	return &val
+}
+
 // partitioningKey generates a map key to identify a partitioning by the set of
 // shards it contains. Any two partitionings that specify the same set of shard
 // ranges will be given the same key.
@@ -44,6 +44,29 @@ func TestPartitioningSet(t *testing.T) {
 	}
 }
 
+func TestKeyspacePaused(t *testing.T) {
+	paused := true
+	dst := &planetscalev2.VitessKeyspaceTemplate{
+		Partitionings: []planetscalev2.VitessKeyspacePartitioning{
+			testEqualPartitioning(2, "original value from dst"),
+		},
+	}
+	src := &planetscalev2.VitessKeyspaceTemplate{
+		Partitionings: []planetscalev2.VitessKeyspacePartitioning{
+			testEqualPartitioning(2, "try to change value in src"),
+		},
+	}
+	src.Partitionings[0].Equal.ShardTemplate.Paused = &paused
+
+	want := testEqualPartitioning(2, "original value from dst")
+	want.Equal.ShardTemplate.Paused = &paused
+
+	KeyspacePaused(dst, src)
+	if !equality.Semantic.DeepEqual(dst.Partitionings[0], want) {
+		t.Errorf("dst = %v\nwant: %v", toJSON(dst.Partitionings[0]), toJSON(want))
+	}
+}
+
 func testEqualPartitioning(parts int32, message string) planetscalev2.VitessKeyspacePartitioning {
 	return planetscalev2.VitessKeyspacePartitioning{
 		Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// Probe applies user-provided overrides to an operator-generated probe.
+// If 'overrides' is nil, 'dst' is left untouched. If a custom probe is given,
+// it replaces 'dst' entirely. Otherwise, only the timing fields that are set
+// in 'overrides' are changed, and only if there is a probe in 'dst' to tune.
+func Probe(dst **corev1.Probe, overrides *planetscalev2.ProbeOverrides) {
+	if overrides == nil {
+		return
+	}
+	if overrides.Custom != nil {
+		*dst = overrides.Custom.DeepCopy()
+		return
+	}
+	if *dst == nil {
+		return
+	}
+
+	// Make a copy so we don't modify a probe that might be shared.
+	probe := (*dst).DeepCopy()
+	if overrides.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *overrides.InitialDelaySeconds
+	}
+	if overrides.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *overrides.TimeoutSeconds
+	}
+	if overrides.PeriodSeconds != nil {
+		probe.PeriodSeconds = *overrides.PeriodSeconds
+	}
+	if overrides.SuccessThreshold != nil {
+		probe.SuccessThreshold = *overrides.SuccessThreshold
+	}
+	if overrides.FailureThreshold != nil {
+		probe.FailureThreshold = *overrides.FailureThreshold
+	}
+	*dst = probe
+}
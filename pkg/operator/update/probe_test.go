@@ -0,0 +1,64 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestProbe(t *testing.T) {
+	handler := corev1.ProbeHandler{
+		HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("web")},
+	}
+
+	// Timing overrides should only touch the fields that are set.
+	val := &corev1.Probe{ProbeHandler: handler, InitialDelaySeconds: 300, FailureThreshold: 30}
+	want := &corev1.Probe{ProbeHandler: handler, InitialDelaySeconds: 300, FailureThreshold: 5, PeriodSeconds: 20}
+	Probe(&val, &planetscalev2.ProbeOverrides{
+		FailureThreshold: pointer.Int32Ptr(5),
+		PeriodSeconds:    pointer.Int32Ptr(20),
+	})
+	if !equality.Semantic.DeepEqual(val, want) {
+		t.Errorf("val = %#v; want %#v", val, want)
+	}
+
+	// Timing overrides alone shouldn't create a probe where there was none.
+	val = nil
+	Probe(&val, &planetscalev2.ProbeOverrides{PeriodSeconds: pointer.Int32Ptr(20)})
+	if val != nil {
+		t.Errorf("val = %#v; want nil", val)
+	}
+
+	// A custom probe should replace everything.
+	custom := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{Command: []string{"true"}},
+		},
+	}
+	val = &corev1.Probe{ProbeHandler: handler}
+	Probe(&val, &planetscalev2.ProbeOverrides{Custom: custom, PeriodSeconds: pointer.Int32Ptr(20)})
+	if !equality.Semantic.DeepEqual(val, custom) {
+		t.Errorf("val = %#v; want %#v", val, custom)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// ResourceDefaults applies cluster-wide resource defaults and bounds to 'dst'.
+// It never writes into the maps found in 'dst', since those are often shared
+// with the spec they came from.
+func ResourceDefaults(dst *corev1.ResourceRequirements, defaults *planetscalev2.ResourceDefaultsSpec) {
+	if defaults == nil {
+		return
+	}
+	requests := dst.Requests.DeepCopy()
+	limits := dst.Limits.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	if limits == nil {
+		limits = corev1.ResourceList{}
+	}
+
+	for name, value := range defaults.DefaultRequests {
+		_, hasRequest := requests[name]
+		_, hasLimit := limits[name]
+		if !hasRequest && !hasLimit {
+			requests[name] = value
+		}
+	}
+	for name, value := range defaults.DefaultLimits {
+		if _, hasLimit := limits[name]; hasLimit {
+			continue
+		}
+		if request, hasRequest := requests[name]; hasRequest && request.Cmp(value) > 0 {
+			value = request
+		}
+		limits[name] = value
+	}
+	for name, value := range defaults.MinRequests {
+		if request, hasRequest := requests[name]; !hasRequest || request.Cmp(value) < 0 {
+			requests[name] = value
+		}
+		if limit, hasLimit := limits[name]; hasLimit && limit.Cmp(value) < 0 {
+			limits[name] = value
+		}
+	}
+	for name, value := range defaults.MaxLimits {
+		if limit, hasLimit := limits[name]; !hasLimit || limit.Cmp(value) > 0 {
+			limits[name] = value
+		}
+		if request, hasRequest := requests[name]; hasRequest && request.Cmp(value) > 0 {
+			requests[name] = value
+		}
+	}
+
+	// Leave empty lists unset, as they were, so Pods don't change needlessly.
+	if len(requests) == 0 {
+		requests = dst.Requests
+	}
+	if len(limits) == 0 {
+		limits = dst.Limits
+	}
+	dst.Requests = requests
+	dst.Limits = limits
+}
+
+// ContainerResourceDefaults returns copies of the given containers with
+// ResourceDefaults applied. It returns the containers as they are if there
+// are no defaults.
+func ContainerResourceDefaults(containers []corev1.Container, defaults *planetscalev2.ResourceDefaultsSpec) []corev1.Container {
+	if defaults == nil || len(containers) == 0 {
+		return containers
+	}
+	out := make([]corev1.Container, len(containers))
+	copy(out, containers)
+	for i := range out {
+		ResourceDefaults(&out[i].Resources, defaults)
+	}
+	return out
+}
+
+// PodTemplateResourceDefaults applies ResourceDefaults to every container and
+// init container in a Pod spec. Only use it on Pod specs that we build
+// entirely, since it would also touch containers that admission webhooks
+// inject into existing Pods.
+func PodTemplateResourceDefaults(podSpec *corev1.PodSpec, defaults *planetscalev2.ResourceDefaultsSpec) {
+	for i := range podSpec.InitContainers {
+		ResourceDefaults(&podSpec.InitContainers[i].Resources, defaults)
+	}
+	for i := range podSpec.Containers {
+		ResourceDefaults(&podSpec.Containers[i].Resources, defaults)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestResourceDefaults(t *testing.T) {
+	cpu := func(value string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(value)}
+	}
+	table := []struct {
+		name      string
+		defaults  planetscalev2.ResourceDefaultsSpec
+		resources corev1.ResourceRequirements
+		want      corev1.ResourceRequirements
+	}{
+		{
+			name:      "default request",
+			defaults:  planetscalev2.ResourceDefaultsSpec{DefaultRequests: cpu("100m")},
+			resources: corev1.ResourceRequirements{},
+			want:      corev1.ResourceRequirements{Requests: cpu("100m")},
+		},
+		{
+			name:      "explicit request",
+			defaults:  planetscalev2.ResourceDefaultsSpec{DefaultRequests: cpu("100m")},
+			resources: corev1.ResourceRequirements{Requests: cpu("200m")},
+			want:      corev1.ResourceRequirements{Requests: cpu("200m")},
+		},
+		{
+			name:      "no default request under a limit",
+			defaults:  planetscalev2.ResourceDefaultsSpec{DefaultRequests: cpu("100m")},
+			resources: corev1.ResourceRequirements{Limits: cpu("50m")},
+			want:      corev1.ResourceRequirements{Limits: cpu("50m")},
+		},
+		{
+			name:      "default limit below request",
+			defaults:  planetscalev2.ResourceDefaultsSpec{DefaultLimits: cpu("1")},
+			resources: corev1.ResourceRequirements{Requests: cpu("2")},
+			want:      corev1.ResourceRequirements{Requests: cpu("2"), Limits: cpu("2")},
+		},
+		{
+			name:      "min request",
+			defaults:  planetscalev2.ResourceDefaultsSpec{MinRequests: cpu("100m")},
+			resources: corev1.ResourceRequirements{Requests: cpu("10m"), Limits: cpu("50m")},
+			want:      corev1.ResourceRequirements{Requests: cpu("100m"), Limits: cpu("100m")},
+		},
+		{
+			name:      "max limit wins",
+			defaults:  planetscalev2.ResourceDefaultsSpec{MinRequests: cpu("2"), MaxLimits: cpu("1")},
+			resources: corev1.ResourceRequirements{},
+			want:      corev1.ResourceRequirements{Requests: cpu("1"), Limits: cpu("1")},
+		},
+	}
+
+	for _, test := range table {
+		defaults := test.defaults
+		resources := test.resources
+		ResourceDefaults(&resources, &defaults)
+		if !apiequality.Semantic.DeepEqual(resources, test.want) {
+			t.Errorf("%v: ResourceDefaults() = %v; want %v", test.name, resources, test.want)
+		}
+	}
+}
+
+func TestContainerResourceDefaultsCopies(t *testing.T) {
+	containers := []corev1.Container{
+		{Name: "a", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")}}},
+	}
+	defaults := &planetscalev2.ResourceDefaultsSpec{MinRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}}
+
+	out := ContainerResourceDefaults(containers, defaults)
+	if got := out[0].Resources.Requests[corev1.ResourceCPU]; got.String() != "1" {
+		t.Errorf("ContainerResourceDefaults() cpu request = %v; want 1", got.String())
+	}
+	if got := containers[0].Resources.Requests[corev1.ResourceCPU]; got.String() != "10m" {
+		t.Errorf("ContainerResourceDefaults() changed its input to %v", got.String())
+	}
+}
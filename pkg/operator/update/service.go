@@ -44,3 +44,18 @@ func InPlaceServiceOverrides(svc *corev1.Service, so *planetscalev2.ServiceOverr
 		Annotations(&svc.Annotations, so.Annotations)
 	}
 }
+
+// ServiceNetworking applies the IP family settings (if any) to the given
+// Service. These are only meant to be applied when a Service is created.
+func ServiceNetworking(svc *corev1.Service, networking *planetscalev2.NetworkingSpec) {
+	if networking == nil {
+		return
+	}
+	if networking.IPFamilyPolicy != nil {
+		policy := *networking.IPFamilyPolicy
+		svc.Spec.IPFamilyPolicy = &policy
+	}
+	if len(networking.IPFamilies) > 0 {
+		svc.Spec.IPFamilies = append([]corev1.IPFamily(nil), networking.IPFamilies...)
+	}
+}
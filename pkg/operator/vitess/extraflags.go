@@ -0,0 +1,89 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitess
+
+import (
+	"sort"
+	"strings"
+)
+
+// managedFlags are flags that the operator derives from the objects it
+// deploys, such as a component's identity, ports, and topology. Users can't
+// override them with extra flags, unless they explicitly allow it, because
+// the operator relies on the values it sets, for example to route traffic
+// or to find tablets in topology.
+//
+// tablet_hostname isn't one of them, even though the operator sets it,
+// because users have long pointed it at their own DNS names.
+//
+// Names are normalized with normalizeFlagName.
+var managedFlags = map[string]bool{
+	"port":                       true,
+	"grpc_port":                  true,
+	"mysql_server_port":          true,
+	"cell":                       true,
+	"topo_implementation":        true,
+	"topo_global_server_address": true,
+	"topo_global_root":           true,
+	"tablet_path":                true,
+	"init_keyspace":              true,
+	"init_shard":                 true,
+	"init_tablet_type":           true,
+}
+
+// normalizeFlagName returns the canonical form of a flag name. Vitess accepts
+// flags with one or two leading dashes, and with either dashes or
+// underscores between words.
+func normalizeFlagName(name string) string {
+	return strings.ReplaceAll(strings.TrimLeft(name, "-"), "-", "_")
+}
+
+// IsManagedFlag returns whether the operator always sets the given flag
+// itself, so it can't be overridden with extra flags.
+func IsManagedFlag(name string) bool {
+	return managedFlags[normalizeFlagName(name)]
+}
+
+// MergeExtraFlags merges layers of user-provided flags into a new map, with
+// later layers taking precedence. Leading dashes are removed from flag names.
+//
+// Flags that the operator manages are left out, unless they're listed in
+// allowed, and their names are returned in sorted order so the caller can
+// report them.
+func MergeExtraFlags(allowed []string, layers ...map[string]string) (flags map[string]string, denied []string) {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		allowedSet[normalizeFlagName(name)] = true
+	}
+	flags = make(map[string]string)
+	deniedSet := make(map[string]bool)
+	for _, layer := range layers {
+		for key, value := range layer {
+			key = strings.TrimLeft(key, "-")
+			if IsManagedFlag(key) && !allowedSet[normalizeFlagName(key)] {
+				deniedSet[key] = true
+				continue
+			}
+			flags[key] = value
+		}
+	}
+	for key := range deniedSet {
+		denied = append(denied, key)
+	}
+	sort.Strings(denied)
+	return flags, denied
+}
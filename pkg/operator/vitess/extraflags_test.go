@@ -0,0 +1,71 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitess
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeExtraFlags(t *testing.T) {
+	cluster := map[string]string{
+		"topo_global_root": "/other",
+		"log_dir":          "/tmp",
+		"-vmodule":         "x=1",
+	}
+	component := map[string]string{
+		"--log_dir":         "/var/log",
+		"tablet-path":       "zone1-1",
+		"--grpc-port":       "1",
+		"queryserver_x":     "y",
+		"--tablet_hostname": "tablet.example.com",
+	}
+
+	flags, denied := MergeExtraFlags(nil, cluster, component, nil)
+	wantFlags := map[string]string{
+		"log_dir":         "/var/log",
+		"vmodule":         "x=1",
+		"queryserver_x":   "y",
+		"tablet_hostname": "tablet.example.com",
+	}
+	if !reflect.DeepEqual(flags, wantFlags) {
+		t.Errorf("MergeExtraFlags() flags = %v; want %v", flags, wantFlags)
+	}
+	wantDenied := []string{"grpc-port", "tablet-path", "topo_global_root"}
+	if !reflect.DeepEqual(denied, wantDenied) {
+		t.Errorf("MergeExtraFlags() denied = %v; want %v", denied, wantDenied)
+	}
+}
+
+func TestMergeExtraFlagsAllowed(t *testing.T) {
+	component := map[string]string{
+		"--grpc-port":      "16000",
+		"topo_global_root": "/other",
+	}
+
+	flags, denied := MergeExtraFlags([]string{"grpc_port"}, component)
+	wantFlags := map[string]string{
+		"grpc-port": "16000",
+	}
+	if !reflect.DeepEqual(flags, wantFlags) {
+		t.Errorf("MergeExtraFlags() flags = %v; want %v", flags, wantFlags)
+	}
+	wantDenied := []string{"topo_global_root"}
+	if !reflect.DeepEqual(denied, wantDenied) {
+		t.Errorf("MergeExtraFlags() denied = %v; want %v", denied, wantDenied)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessbackup
+
+import (
+	"time"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// ExpiredBackups returns the indexes of the backups of one shard that the
+// retention policy allows deleting, given their start times sorted from
+// oldest to newest. The newest backups are kept until MinCount of them are
+// complete, and isComplete is only called for those.
+func ExpiredBackups(startTimes []time.Time, retention *planetscalev2.VitessBackupRetention, now time.Time, isComplete func(i int) bool) []int {
+	var expired []int
+	var keptComplete int32
+	for i := len(startTimes) - 1; i >= 0; i-- {
+		if keptComplete < retention.MinCount {
+			if isComplete(i) {
+				keptComplete++
+			}
+			continue
+		}
+		if now.Sub(startTimes[i]) > retention.MaxAge.Duration {
+			expired = append([]int{i}, expired...)
+		}
+	}
+	return expired
+}
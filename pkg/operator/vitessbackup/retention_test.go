@@ -0,0 +1,63 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessbackup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestExpiredBackups(t *testing.T) {
+	now := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	startTimes := []time.Time{
+		now.Add(-10 * day),
+		now.Add(-9 * day),
+		now.Add(-8 * day),
+		now.Add(-2 * day),
+		now.Add(-1 * day),
+	}
+	retention := &planetscalev2.VitessBackupRetention{
+		MaxAge:   metav1.Duration{Duration: 7 * day},
+		MinCount: 1,
+	}
+
+	table := []struct {
+		complete []bool
+		minCount int32
+		want     []int
+	}{
+		{[]bool{true, true, true, true, true}, 1, []int{0, 1, 2}},
+		{[]bool{true, true, true, true, true}, 3, []int{0, 1}},
+		// Incomplete backups don't count towards MinCount.
+		{[]bool{true, true, true, false, false}, 1, []int{0, 1}},
+		{[]bool{true, false, false, false, false}, 1, nil},
+	}
+
+	for _, test := range table {
+		retention.MinCount = test.minCount
+		got := ExpiredBackups(startTimes, retention, now, func(i int) bool { return test.complete[i] })
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ExpiredBackups(complete: %v, minCount: %v) = %v; want %v", test.complete, test.minCount, got, test.want)
+		}
+	}
+}
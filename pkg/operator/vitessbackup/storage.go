@@ -46,7 +46,7 @@ func StorageFlags(backupLocation *planetscalev2.VitessBackupLocation, clusterNam
 		return azblobBackupFlags(backupLocation.Azblob, clusterName)
 	case backupLocation.Ceph != nil:
 		return cephBackupFlags(backupLocation.Ceph)
-	case backupLocation.Volume != nil:
+	case backupLocation.Volume != nil, backupLocation.VolumeClaim != nil:
 		return fileBackupFlags(clusterName)
 	}
 	return nil
@@ -65,6 +65,12 @@ func StorageVolumes(backupLocation *planetscalev2.VitessBackupLocation) []corev1
 		return cephBackupVolumes(backupLocation.Ceph)
 	case backupLocation.Volume != nil:
 		return fileBackupVolumes(backupLocation.Volume)
+	case backupLocation.VolumeClaim != nil:
+		return fileBackupVolumes(&corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: backupLocation.VolumeClaim.ClaimName,
+			},
+		})
 	}
 	return nil
 }
@@ -80,12 +86,17 @@ func StorageVolumeMounts(backupLocation *planetscalev2.VitessBackupLocation) []c
 		return azblobBackupVolumeMounts(backupLocation.Azblob)
 	case backupLocation.Ceph != nil:
 		return cephBackupVolumeMounts(backupLocation.Ceph)
-	case backupLocation.Volume != nil:
+	case backupLocation.Volume != nil, backupLocation.VolumeClaim != nil:
 		return fileBackupVolumeMounts(backupLocation.VolumeSubPath)
 	}
 	return nil
 }
 
+// IsVolume returns whether the backup storage location is a mounted volume.
+func IsVolume(backupLocation *planetscalev2.VitessBackupLocation) bool {
+	return backupLocation.Volume != nil || backupLocation.VolumeClaim != nil
+}
+
 // StorageEnvVars returns the EnvVars for the configured backup storage location.
 func StorageEnvVars(backupLocation *planetscalev2.VitessBackupLocation) []corev1.EnvVar {
 	switch {
@@ -17,8 +17,12 @@ limitations under the License.
 package vitessbackup
 
 import (
-	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+	"io/fs"
+	"path/filepath"
+	"syscall"
+
 	corev1 "k8s.io/api/core/v1"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 )
 
 func fileBackupFlags(clusterName string) vitess.Flags {
@@ -46,3 +50,35 @@ func fileBackupVolumeMounts(subPath string) []corev1.VolumeMount {
 		},
 	}
 }
+
+// VolumeUsage returns the capacity and used space in bytes of the file
+// system of a volume-backed location, as mounted by StorageVolumeMounts.
+func VolumeUsage() (capacity, used int64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(fileBackupStorageMountPath, &stat); err != nil {
+		return 0, 0, err
+	}
+	blockSize := int64(stat.Bsize)
+	return int64(stat.Blocks) * blockSize, int64(stat.Blocks-stat.Bfree) * blockSize, nil
+}
+
+// VolumeBackupSize returns the total size in bytes of the files of a backup
+// in a volume-backed location, as mounted by StorageVolumeMounts.
+func VolumeBackupSize(clusterName, dir, name string) (int64, error) {
+	root := filepath.Join(rootKeyPrefix(fileBackupStorageMountPath, clusterName), dir, name)
+	var size int64
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
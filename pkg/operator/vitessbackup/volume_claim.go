@@ -0,0 +1,54 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessbackup
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// NewVolumeClaim creates the PVC for a VolumeClaim backup location.
+func NewVolumeClaim(key client.ObjectKey, labels map[string]string, claim *planetscalev2.VolumeClaimBackupLocation) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    labels,
+		},
+		Spec: *claim.Spec.DeepCopy(),
+	}
+}
+
+// UpdateVolumeClaimInPlace updates an existing backup volume PVC in-place.
+func UpdateVolumeClaimInPlace(obj *corev1.PersistentVolumeClaim, labels map[string]string, claim *planetscalev2.VolumeClaimBackupLocation) {
+	// Update labels, but ignore existing ones we don't set.
+	update.Labels(&obj.Labels, labels)
+
+	// The only in-place spec update that's possible is volume expansion.
+	curSize := obj.Spec.Resources.Requests[corev1.ResourceStorage]
+	newSize := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if newSize.Cmp(curSize) > 0 {
+		if obj.Spec.Resources.Requests == nil {
+			obj.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		obj.Spec.Resources.Requests[corev1.ResourceStorage] = newSize
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesstopo
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/stringsets"
+)
+
+type RegisterCellsAliasesParams struct {
+	// EventObj holds the object type that the recorder will use when writing events.
+	EventObj   runtime.Object
+	TopoServer *topo.Server
+	Recorder   record.EventRecorder
+	// DesiredCells is a map of cell names to their lockserver specs.
+	DesiredCells map[string]*planetscalev2.LockserverSpec
+}
+
+// RegisterCellsAliases creates or updates the cells aliases that let vtgate
+// route traffic between the desired cells.
+func RegisterCellsAliases(ctx context.Context, p RegisterCellsAliasesParams) error {
+	desiredCellsAliases := BuildCellsAliases(p.DesiredCells)
+	currentCellsAliases, err := p.TopoServer.GetCellsAliases(ctx, true)
+	if err != nil {
+		p.Recorder.Eventf(p.EventObj, corev1.EventTypeWarning, "TopoCellAlias",
+			"Failed to get current cell aliases: %v", err)
+		return err
+	}
+	for alias, desiredCellsAlias := range desiredCellsAliases {
+		// If this alias already exists and matches what we are trying to update
+		// it to, skip it.
+		if _, ok := currentCellsAliases[alias]; ok {
+			if stringsets.Equal(desiredCellsAlias.Cells, currentCellsAliases[alias].Cells) {
+				continue
+			}
+		}
+		// Create or update cells alias.
+		err = p.TopoServer.UpdateCellsAlias(ctx, alias, func(ca *topodatapb.CellsAlias) error {
+			// Even if we're creating, 'ca' will already be non-nil.
+			ca.Cells = desiredCellsAlias.Cells
+			return nil
+		})
+		if err != nil {
+			p.Recorder.Eventf(p.EventObj, corev1.EventTypeWarning, "TopoCellAlias",
+				"Failed to create or update cell alias: %s: %v", alias, err)
+			return err
+		}
+		p.Recorder.Eventf(p.EventObj, corev1.EventTypeNormal, "TopoCellAlias",
+			"Created or updated cells alias: %s -> %v", alias,
+			desiredCellsAlias.Cells)
+	}
+	return nil
+}
+
+// BuildCellsAliases returns the cells aliases to register for the desired cells.
+func BuildCellsAliases(desiredCells map[string]*planetscalev2.LockserverSpec) map[string]*topodatapb.CellsAlias {
+	cellsAlias := make(map[string]*topodatapb.CellsAlias)
+	for name := range desiredCells {
+		alias := "planetscale_operator_default"
+		if _, ok := cellsAlias[alias]; ok {
+			cellsAlias[alias].Cells = append(cellsAlias[alias].Cells, name)
+		} else {
+			cells := topodatapb.CellsAlias{
+				Cells: []string{
+					name,
+				},
+			}
+			cellsAlias[alias] = &cells
+		}
+	}
+	return cellsAlias
+}
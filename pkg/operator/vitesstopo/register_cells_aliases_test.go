@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package vitesscluster
+package vitesstopo
 
 import (
 	"testing"
@@ -58,14 +58,14 @@ func TestBuildCellAliases(t *testing.T) {
 		"gcpuscentral1f": nil,
 	}
 
-	results := buildCellsAliases(awsInput)
+	results := BuildCellsAliases(awsInput)
 	for alias, cells := range awsCellAliases {
 		assert.Contains(t, results, alias)
 		for _, cell := range cells.Cells {
 			assert.Contains(t, results[alias].Cells, cell)
 		}
 	}
-	results = buildCellsAliases(gcpInput)
+	results = BuildCellsAliases(gcpInput)
 	for alias, cells := range gcpCellAliases {
 		assert.Contains(t, results, alias)
 		for _, cell := range cells.Cells {
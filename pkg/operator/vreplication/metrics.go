@@ -0,0 +1,55 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+const (
+	metricsSubsystemName = "vreplication"
+
+	workflowLabel = "workflow"
+	streamLabel   = "stream"
+	stateLabel    = "state"
+)
+
+var (
+	streamLabels = []string{metrics.ClusterLabel, metrics.KeyspaceLabel, workflowLabel, metrics.ShardLabel, streamLabel}
+
+	streamLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "stream_lag_seconds",
+		Help:      "Time since a stream of an operator-managed workflow last processed an event from its source",
+	}, streamLabels)
+	streamState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "stream_state",
+		Help:      "State of a stream of an operator-managed workflow, which is 1 for the current state",
+	}, append(streamLabels, stateLabel))
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		streamLag,
+		streamState,
+	)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+// Streams returns the status of each stream of a workflow, ordered by
+// target shard and stream ID.
+func Streams(workflow *wrangler.ReplicationStatusResult, now time.Time) []planetscalev2.VReplicationStreamStatus {
+	var streams []planetscalev2.VReplicationStreamStatus
+	for _, status := range workflow.ShardStatuses {
+		for _, vReplRow := range status.PrimaryReplicationStatuses {
+			// This is how Vitess computes the lag of the workflow as a whole.
+			lag := int64(now.Sub(time.Unix(vReplRow.TimeUpdated, 0)).Seconds())
+			if lag < 0 {
+				lag = 0
+			}
+			stream := planetscalev2.VReplicationStreamStatus{
+				TargetShard: vReplRow.Shard,
+				ID:          vReplRow.ID,
+				State:       vReplRow.State,
+				LagSeconds:  lag,
+				RowsCopied:  vReplRow.RowsCopied,
+				Message:     vReplRow.Message,
+			}
+			if vReplRow.Bls != nil {
+				stream.SourceShard = vReplRow.Bls.Shard
+			}
+			streams = append(streams, stream)
+		}
+	}
+	sort.Slice(streams, func(i, j int) bool {
+		if streams[i].TargetShard != streams[j].TargetShard {
+			return streams[i].TargetShard < streams[j].TargetShard
+		}
+		return streams[i].ID < streams[j].ID
+	})
+	return streams
+}
+
+// ReportMetrics exports the status of a workflow's streams, replacing
+// whatever was exported for the workflow before.
+func ReportMetrics(clusterName, keyspace, workflow string, streams []planetscalev2.VReplicationStreamStatus) {
+	ForgetMetrics(clusterName, keyspace, workflow)
+	for _, stream := range streams {
+		id := strconv.Itoa(int(stream.ID))
+		streamLag.WithLabelValues(clusterName, keyspace, workflow, stream.TargetShard, id).Set(float64(stream.LagSeconds))
+		streamState.WithLabelValues(clusterName, keyspace, workflow, stream.TargetShard, id, stream.State).Set(1)
+	}
+}
+
+// ForgetMetrics stops exporting metrics for a workflow, once it's gone.
+func ForgetMetrics(clusterName, keyspace, workflow string) {
+	labels := prometheus.Labels{
+		metrics.ClusterLabel:  clusterName,
+		metrics.KeyspaceLabel: keyspace,
+		workflowLabel:         workflow,
+	}
+	streamLag.DeletePartialMatch(labels)
+	streamState.DeletePartialMatch(labels)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+	"time"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+func TestStreams(t *testing.T) {
+	now := time.Unix(1000, 0)
+	workflow := &wrangler.ReplicationStatusResult{
+		ShardStatuses: map[string]*wrangler.ShardReplicationStatus{
+			"80-/zone1-0000000200": {
+				PrimaryReplicationStatuses: []*wrangler.ReplicationStatus{
+					{Shard: "80-", ID: 1, State: "Running", TimeUpdated: 990, Bls: &binlogdatapb.BinlogSource{Shard: "0"}},
+				},
+			},
+			"-80/zone1-0000000100": {
+				PrimaryReplicationStatuses: []*wrangler.ReplicationStatus{
+					{Shard: "-80", ID: 2, State: "Error", TimeUpdated: 1001, Message: "boom"},
+					{Shard: "-80", ID: 1, State: "Copying", TimeUpdated: 940, RowsCopied: 42},
+				},
+			},
+		},
+	}
+	streams := Streams(workflow, now)
+	if len(streams) != 3 {
+		t.Fatalf("Streams() returned %v streams; want 3", len(streams))
+	}
+	want := []struct {
+		shard string
+		id    int32
+		lag   int64
+	}{
+		{"-80", 1, 60},
+		{"-80", 2, 0},
+		{"80-", 1, 10},
+	}
+	for i, w := range want {
+		got := streams[i]
+		if got.TargetShard != w.shard || got.ID != w.id || got.LagSeconds != w.lag {
+			t.Errorf("Streams()[%v] = %+v; want shard %v, id %v, lag %v", i, got, w.shard, w.id, w.lag)
+		}
+	}
+	if streams[0].RowsCopied != 42 || streams[1].Message != "boom" || streams[2].SourceShard != "0" {
+		t.Errorf("Streams() = %+v; want rows copied, message and source shard carried over", streams)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vreplication has helpers for the VReplication workflows that vtop
+// drives, such as Reshard and Materialize.
+package vreplication
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/sqlescape"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// SourceTabletTypes are the tablet types that the workflows we create
+	// may copy from, in order of preference.
+	SourceTabletTypes = "in_order:REPLICA,PRIMARY"
+	// materializeDefaultOnDDL is what a Materialize workflow does with DDL
+	// on the source keyspace, unless the spec says otherwise.
+	materializeDefaultOnDDL = "IGNORE"
+	// lookupDefaultKeyspaceIDColumn is the column of a lookup table that
+	// holds the keyspace ID, unless the spec says otherwise.
+	lookupDefaultKeyspaceIDColumn = "keyspace_id"
+)
+
+// WorkflowState aggregates the state of a workflow's streams on all shards,
+// along with the error messages of streams in the Error state.
+func WorkflowState(workflow *wrangler.ReplicationStatusResult) (planetscalev2.WorkflowState, []string) {
+	state := planetscalev2.WorkflowUnknown
+	var errorMsgs []string
+	for _, status := range workflow.ShardStatuses {
+		for _, vReplRow := range status.PrimaryReplicationStatuses {
+			if vReplRow.State == "Error" {
+				state = planetscalev2.WorkflowError
+				errorMsgs = append(errorMsgs, vReplRow.Message)
+				break
+			}
+			if vReplRow.State == "Copying" && state != planetscalev2.WorkflowError {
+				state = planetscalev2.WorkflowCopying
+			}
+			if (vReplRow.State == "Running" || vReplRow.State == "Lagging") && state == planetscalev2.WorkflowUnknown {
+				state = planetscalev2.WorkflowRunning
+			}
+		}
+	}
+	return state, errorMsgs
+}
+
+// MaterializeSettings converts a Materialize spec into the settings of a
+// workflow into the given target keyspace.
+func MaterializeSettings(targetKeyspace string, spec *planetscalev2.VitessKeyspaceMaterializeSpec) *vtctldatapb.MaterializeSettings {
+	onDDL := spec.OnDDL
+	if onDDL == "" {
+		onDDL = materializeDefaultOnDDL
+	}
+	settings := &vtctldatapb.MaterializeSettings{
+		Workflow:       spec.Workflow,
+		SourceKeyspace: spec.SourceKeyspace,
+		TargetKeyspace: targetKeyspace,
+		Cell:           strings.Join(spec.Cells, ","),
+		TabletTypes:    SourceTabletTypes,
+		OnDdl:          onDDL,
+	}
+	for _, table := range spec.Tables {
+		tableSettings := &vtctldatapb.TableMaterializeSettings{
+			TargetTable:      table.TargetTable,
+			SourceExpression: table.SourceExpression,
+			CreateDdl:        table.CreateDDL,
+		}
+		if tableSettings.SourceExpression == "" {
+			tableSettings.SourceExpression = "select * from " + sqlescape.EscapeID(table.TargetTable)
+			if tableSettings.CreateDdl == "" {
+				tableSettings.CreateDdl = "copy"
+			}
+		}
+		settings.TableSettings = append(settings.TableSettings, tableSettings)
+	}
+	return settings
+}
+
+// LookupVindexWorkflow returns the name that CreateLookupVindex gives the
+// workflow that backfills the lookup table of a vindex.
+func LookupVindexWorkflow(spec *planetscalev2.VitessLookupVindexSpec) string {
+	lookupTable := spec.LookupTable
+	if i := strings.LastIndex(lookupTable, "."); i >= 0 {
+		lookupTable = lookupTable[i+1:]
+	}
+	return lookupTable + "_vdx"
+}
+
+// LookupVindexSpecs converts a lookup vindex spec into the VSchema snippet
+// that CreateLookupVindex expects, with just the vindex and its table.
+func LookupVindexSpecs(spec *planetscalev2.VitessLookupVindexSpec) *vschemapb.Keyspace {
+	lookupColumns := spec.LookupColumns
+	if len(lookupColumns) == 0 {
+		lookupColumns = spec.Columns
+	}
+	keyspaceIDColumn := spec.KeyspaceIDColumn
+	if keyspaceIDColumn == "" {
+		keyspaceIDColumn = lookupDefaultKeyspaceIDColumn
+	}
+	vindex := &vschemapb.Vindex{
+		Type: spec.Type,
+		Params: map[string]string{
+			"table": spec.LookupTable,
+			"from":  strings.Join(lookupColumns, ","),
+			"to":    keyspaceIDColumn,
+		},
+	}
+	if spec.Owned {
+		vindex.Owner = spec.Table
+	}
+	return &vschemapb.Keyspace{
+		Vindexes: map[string]*vschemapb.Vindex{
+			spec.Name: vindex,
+		},
+		Tables: map[string]*vschemapb.Table{
+			spec.Table: {
+				ColumnVindexes: []*vschemapb.ColumnVindex{
+					{Name: spec.Name, Columns: spec.Columns},
+				},
+			},
+		},
+	}
+}
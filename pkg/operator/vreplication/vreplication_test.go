@@ -0,0 +1,79 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMaterializeSettings(t *testing.T) {
+	spec := &planetscalev2.VitessKeyspaceMaterializeSpec{
+		Workflow:       "rollups",
+		SourceKeyspace: "commerce",
+		Tables: []planetscalev2.VitessMaterializedTable{
+			{TargetTable: "customer"},
+			{TargetTable: "sales", SourceExpression: "select pid, count(*) as n from orders group by pid"},
+		},
+	}
+	settings := MaterializeSettings("reports", spec)
+	if settings.TargetKeyspace != "reports" || settings.OnDdl != "IGNORE" {
+		t.Errorf("MaterializeSettings() = %v; want target keyspace reports and onDDL IGNORE", settings)
+	}
+	if got, want := settings.TableSettings[0].SourceExpression, "select * from `customer`"; got != want {
+		t.Errorf("source expression of copied table = %q; want %q", got, want)
+	}
+	if got := settings.TableSettings[0].CreateDdl; got != "copy" {
+		t.Errorf("create DDL of copied table = %q; want copy", got)
+	}
+	if got := settings.TableSettings[1].CreateDdl; got != "" {
+		t.Errorf("create DDL of rollup table = %q; want empty", got)
+	}
+}
+
+func TestLookupVindexSpecs(t *testing.T) {
+	spec := &planetscalev2.VitessLookupVindexSpec{
+		Name:        "corder_keyspace_idx",
+		Type:        "consistent_lookup_unique",
+		Table:       "corder",
+		Columns:     []string{"corder_id"},
+		LookupTable: "customer.corder_keyspace_idx",
+		Owned:       true,
+	}
+	specs := LookupVindexSpecs(spec)
+	vindex := specs.Vindexes["corder_keyspace_idx"]
+	if vindex == nil {
+		t.Fatalf("LookupVindexSpecs() = %v; want vindex corder_keyspace_idx", specs)
+	}
+	if got, want := vindex.Params["from"], "corder_id"; got != want {
+		t.Errorf("from = %q; want %q", got, want)
+	}
+	if got, want := vindex.Params["to"], "keyspace_id"; got != want {
+		t.Errorf("to = %q; want %q", got, want)
+	}
+	if vindex.Owner != "corder" {
+		t.Errorf("owner = %q; want corder", vindex.Owner)
+	}
+	table := specs.Tables["corder"]
+	if table == nil || len(table.ColumnVindexes) != 1 || table.ColumnVindexes[0].Name != spec.Name {
+		t.Errorf("tables = %v; want one column vindex on corder", specs.Tables)
+	}
+	if got, want := LookupVindexWorkflow(spec), "corder_keyspace_idx_vdx"; got != want {
+		t.Errorf("LookupVindexWorkflow() = %q; want %q", got, want)
+	}
+}
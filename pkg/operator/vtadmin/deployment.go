@@ -104,6 +104,7 @@ type Spec struct {
 	ExtraVolumeMounts []corev1.VolumeMount
 	InitContainers    []corev1.Container
 	SidecarContainers []corev1.Container
+	ResourceDefaults  *planetscalev2.ResourceDefaultsSpec
 	Annotations       map[string]string
 	ExtraLabels       map[string]string
 	Tolerations       []corev1.Toleration
@@ -264,6 +265,7 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 	updateWebConfig(spec, vtadminWebContainer, &obj.Spec.Template.Spec)
 	update.ResourceRequirements(&vtadminWebContainer.Resources, &spec.WebResources)
 	update.PodTemplateContainers(&obj.Spec.Template.Spec.Containers, []corev1.Container{*vtadminAPIContainer, *vtadminWebContainer})
+	update.PodTemplateResourceDefaults(&obj.Spec.Template.Spec, spec.ResourceDefaults)
 
 	if spec.Affinity != nil {
 		obj.Spec.Template.Spec.Affinity = spec.Affinity
@@ -28,7 +28,9 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
 	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
 	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
@@ -41,6 +43,10 @@ const (
 	webDir     = "/vt/src/vitess.io/vitess/web/vtctld"
 	webDir2    = "/vt/src/vitess.io/vitess/web/vtctld2/app"
 	serviceMap = "grpc-vtctl,grpc-vtctld"
+
+	tlsCertDirName         = "vtctld-tls-cert"
+	tlsKeyDirName          = "vtctld-tls-key"
+	tlsClientCACertDirName = "vtctld-tls-ca-cert"
 )
 
 // DeploymentName returns the name of the vtctld Deployment for a given cell.
@@ -66,11 +72,13 @@ type Spec struct {
 	ExtraVolumeMounts []corev1.VolumeMount
 	InitContainers    []corev1.Container
 	SidecarContainers []corev1.Container
+	ResourceDefaults  *planetscalev2.ResourceDefaultsSpec
 	Annotations       map[string]string
 	ExtraLabels       map[string]string
 	Tolerations       []corev1.Toleration
 	BackupLocation    *planetscalev2.VitessBackupLocation
 	BackupEngine      planetscalev2.VitessBackupEngine
+	GRPCTLS           *planetscalev2.VtctldGRPCTLSSpec
 }
 
 // NewDeployment creates a new Deployment object for vtctld.
@@ -147,6 +155,25 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 		volumeMounts = append(volumeMounts, vitessbackup.StorageVolumeMounts(spec.BackupLocation)...)
 		env = append(env, vitessbackup.StorageEnvVars(spec.BackupLocation)...)
 	}
+	if tls := spec.GRPCTLS; tls != nil {
+		mounts := []*secrets.VolumeMount{
+			secrets.Mount(&tls.CertSecret, tlsCertDirName),
+			secrets.Mount(&tls.KeySecret, tlsKeyDirName),
+		}
+		flags["grpc_cert"] = mounts[0].FilePath()
+		flags["grpc_key"] = mounts[1].FilePath()
+		if tls.ClientCACertSecret != nil {
+			mounts = append(mounts, secrets.Mount(tls.ClientCACertSecret, tlsClientCACertDirName))
+			flags["grpc_ca"] = mounts[2].FilePath()
+		}
+		for _, mount := range mounts {
+			volumes = append(volumes, mount.PodVolumes()...)
+			volumeMounts = append(volumeMounts, mount.ContainerVolumeMount())
+		}
+	}
+	topoMounts := lockserver.NewClientMounts(spec.GlobalLockserver)
+	volumes = append(volumes, topoMounts.PodVolumes()...)
+	volumeMounts = append(volumeMounts, topoMounts.ContainerVolumeMounts()...)
 	update.Volumes(&obj.Spec.Template.Spec.Volumes, volumes)
 
 	securityContext := &corev1.SecurityContext{}
@@ -202,6 +229,7 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 			Env:          env,
 		},
 	})
+	update.PodTemplateResourceDefaults(&obj.Spec.Template.Spec, spec.ResourceDefaults)
 
 	if spec.Affinity != nil {
 		obj.Spec.Template.Spec.Affinity = spec.Affinity
@@ -243,6 +271,7 @@ func (spec *Spec) flags() vitess.Flags {
 
 		"logtostderr": true,
 	}
+	flags.Merge(lockserver.NewClientMounts(spec.GlobalLockserver).Flags())
 	if spec.BackupLocation == nil {
 		return flags
 	}
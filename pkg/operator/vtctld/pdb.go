@@ -0,0 +1,59 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// PDBName returns the name of the vtctld PDB for a cluster.
+func PDBName(clusterName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VtctldComponentName)
+}
+
+// NewPDB creates a new PDB that covers the vtctlds in all cells.
+func NewPDB(key client.ObjectKey, labels map[string]string) *policyv1.PodDisruptionBudget {
+	// Any vtctld can answer any request, so we only need one of them.
+	minAvailable := intstr.FromInt(1)
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			MinAvailable: &minAvailable,
+		},
+	}
+}
+
+// UpdatePDBInPlace updates an existing PDB in-place.
+func UpdatePDBInPlace(obj *policyv1.PodDisruptionBudget, labels map[string]string) {
+	// Update labels, but ignore existing ones we don't set.
+	update.Labels(&obj.Labels, labels)
+}
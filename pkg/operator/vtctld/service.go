@@ -32,6 +32,11 @@ func ServiceName(clusterName string) string {
 	return names.JoinWithConstraints(names.ServiceConstraints, clusterName, planetscalev2.VtctldComponentName)
 }
 
+// GRPCServiceName returns the name of the dedicated vtctld gRPC Service for a cluster.
+func GRPCServiceName(clusterName string) string {
+	return names.JoinWithConstraints(names.ServiceConstraints, clusterName, planetscalev2.VtctldComponentName, "grpc")
+}
+
 // NewService creates a new Service object for vtctld.
 func NewService(key client.ObjectKey, labels map[string]string) *corev1.Service {
 	// Fill in the immutable parts.
@@ -71,3 +76,34 @@ func UpdateService(obj *corev1.Service, labels map[string]string) {
 		},
 	}
 }
+
+// NewGRPCService creates a new Service object for the vtctld gRPC API.
+func NewGRPCService(key client.ObjectKey, labels map[string]string) *corev1.Service {
+	obj := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+	}
+	UpdateGRPCService(obj, labels)
+	return obj
+}
+
+// UpdateGRPCService updates the mutable parts of the vtctld gRPC Service.
+func UpdateGRPCService(obj *corev1.Service, labels map[string]string) {
+	update.Labels(&obj.Labels, labels)
+
+	obj.Spec.Selector = labels
+
+	// Only ready vtctlds get traffic, so clients never hit one that's still
+	// starting up or shutting down.
+	obj.Spec.PublishNotReadyAddresses = false
+	obj.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:       planetscalev2.DefaultGrpcPortName,
+			Protocol:   corev1.ProtocolTCP,
+			Port:       planetscalev2.DefaultGrpcPort,
+			TargetPort: intstr.FromString(planetscalev2.DefaultGrpcPortName),
+		},
+	}
+}
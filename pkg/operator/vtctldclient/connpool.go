@@ -0,0 +1,206 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctldclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldclient"
+	vtctld "vitess.io/vitess/go/vt/vtctl/vtctldclient"
+
+	"planetscale.dev/vitess-operator/pkg/operator/tracing"
+)
+
+const (
+	// idleTTL is how long to keep a connection around before closing it.
+	// If someone opens the connection before then, the TTL is refreshed.
+	idleTTL = 1 * time.Minute
+
+	// gcInterval is how often to check if any connections have exceeded
+	// their idleTTL.
+	gcInterval = 10 * time.Second
+
+	// connectTimeout is how long to wait synchronously for the connection to
+	// become ready. gRPC keeps trying in the background, so the connection may
+	// be ready the next time the controller reconciles the same object.
+	connectTimeout = 1 * time.Second
+)
+
+// Params identify a remote vtctld and how to connect to it.
+// Params are comparable, so they can be used as map keys.
+type Params struct {
+	// Address is the host:port of the vtctld gRPC endpoint.
+	Address string
+	// TLS holds the PEM-encoded TLS material. If CACert is empty,
+	// the connection is unencrypted.
+	TLS TLSParams
+}
+
+// TLSParams are PEM-encoded TLS settings for connecting to vtctld.
+type TLSParams struct {
+	// CACert is used to verify the vtctld server certificate.
+	CACert string
+	// Cert and Key are an optional client certificate, for when vtctld
+	// requires clients to authenticate.
+	Cert string
+	Key  string
+	// ServerName overrides the name used to verify the server certificate.
+	ServerName string
+}
+
+// pool is the process-wide shared pool of connections.
+var pool = &connPool{conns: make(map[Params]*conn)}
+
+var log = logrus.WithField("component", "vtctldclient.connpool")
+
+func init() {
+	// Start the garbage-collection goroutine.
+	go func() {
+		for {
+			time.Sleep(gcInterval)
+			pool.gc()
+		}
+	}()
+}
+
+// Open returns a Client for the remote vtctld described by params.
+// If the returned error is nil, you must call Close() on the returned
+// Client when you're done using it.
+func Open(ctx context.Context, params Params) (_ *Client, finalErr error) {
+	ctx, span := tracing.StartSpan(ctx, "vtctldclient.Open", attribute.String("address", params.Address))
+	defer func() { tracing.EndSpan(span, finalErr) }()
+
+	c, err := pool.get(params)
+	if err != nil {
+		connectErrors.Inc()
+		return nil, err
+	}
+	client := &Client{VtctldClient: c.client, release: c.release}
+
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	if waiter, ok := c.client.(interface{ WaitForReady(context.Context) error }); ok {
+		if err := waiter.WaitForReady(ctx); err != nil {
+			client.Close()
+			connectErrors.Inc()
+			return nil, fmt.Errorf("vtctld at %v is not ready: %v", params.Address, err)
+		}
+	}
+	return client, nil
+}
+
+type connPool struct {
+	mu    sync.Mutex
+	conns map[Params]*conn
+}
+
+// conn is a shared gRPC connection to one vtctld.
+type conn struct {
+	client vtctld.VtctldClient
+
+	// refCount and lastOpened are guarded by connPool.mu.
+	refCount   int64
+	lastOpened time.Time
+}
+
+// get returns a referenced connection from the pool, dialing a new one if
+// necessary. Dialing doesn't block, so this doesn't wait for the connection.
+func (p *connPool) get(params Params) (*conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c := p.conns[params]
+	if c == nil {
+		cacheMisses.Inc()
+		creds, err := transportCredentials(params.TLS)
+		if err != nil {
+			return nil, err
+		}
+		log.WithField("address", params.Address).Info("connecting to vtctld")
+		client, err := grpcvtctldclient.NewWithDialOpts(params.Address, grpcclient.FailFast(false), grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("can't dial vtctld at %v: %v", params.Address, err)
+		}
+		c = &conn{client: client}
+		p.conns[params] = c
+	} else {
+		cacheHits.Inc()
+	}
+	c.refCount++
+	c.lastOpened = time.Now()
+	return c, nil
+}
+
+// release gives back a reference returned by get.
+func (c *conn) release() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	c.refCount--
+}
+
+// gc closes any connections that have outlived their TTL.
+func (p *connPool) gc() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for params, c := range p.conns {
+		if c.refCount <= 0 && time.Since(c.lastOpened) > idleTTL {
+			log.WithField("address", params.Address).Info("closing connection to vtctld due to idle TTL")
+			if err := c.client.Close(); err != nil {
+				log.WithField("address", params.Address).WithField("err", err).Warning("failed to close connection to vtctld")
+			}
+			delete(p.conns, params)
+		}
+	}
+	connCount.Set(float64(len(p.conns)))
+}
+
+// transportCredentials returns the gRPC credentials for the given TLS params.
+func transportCredentials(params TLSParams) (credentials.TransportCredentials, error) {
+	if params.CACert == "" {
+		return insecure.NewCredentials(), nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(params.CACert)) {
+		return nil, fmt.Errorf("can't parse vtctld CA certificate")
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    roots,
+		ServerName: params.ServerName,
+	}
+	if params.Cert != "" || params.Key != "" {
+		cert, err := tls.X509KeyPair([]byte(params.Cert), []byte(params.Key))
+		if err != nil {
+			return nil, fmt.Errorf("can't load vtctld client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(config), nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctldclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+const (
+	subsystemName = "vtctldclient"
+)
+
+var (
+	connCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemName,
+		Name:      "conn_count",
+		Help:      "Number of connections in the vtctld connection cache",
+	})
+
+	cacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemName,
+		Name:      "cache_hits",
+		Help:      "Requests for a vtctld connection served from the cache",
+	})
+	cacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemName,
+		Name:      "cache_misses",
+		Help:      "Requests for a vtctld connection that missed the cache",
+	})
+	connectErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemName,
+		Name:      "connect_errors",
+		Help:      "Failed attempts to get a ready vtctld connection",
+	})
+
+	callRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemName,
+		Name:      "call_retries",
+		Help:      "vtctld RPC attempts that were retried after a transient error",
+	})
+	callErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemName,
+		Name:      "call_errors",
+		Help:      "vtctld RPCs that failed after all attempts",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		connCount,
+		cacheHits,
+		cacheMisses,
+		connectErrors,
+		callRetries,
+		callErrors,
+	)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package vtctldclient gives controllers a uniform way to call vtctld RPCs.
+
+Controllers can drive workflows like reparenting, traffic switching, and schema
+changes either in-process, against a VtctldServer built on the operator's own
+topo connection, or remotely, against the gRPC port of a cluster's vtctld.
+Either way, calls go through Call so they get the same timeouts and retries.
+*/
+package vtctldclient
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+const (
+	// callTimeout is how long to wait for each attempt of an RPC.
+	// It's long enough for the slower RPCs like GetSchema on a big keyspace,
+	// but short enough that a hung vtctld doesn't stall the workqueue forever.
+	callTimeout = 30 * time.Second
+
+	// maxAttempts is how many times to try an RPC that failed with a
+	// transient error before giving up until the next reconcile.
+	maxAttempts = 3
+
+	// retryBackoff is how long to wait after the first failed attempt.
+	// It doubles after each attempt.
+	retryBackoff = 500 * time.Millisecond
+)
+
+// Client is a vtctld client. All the VtctldClient RPC methods are available.
+type Client struct {
+	vtctlservicepb.VtctldClient
+
+	// release is called by Close() to return a pooled connection.
+	release func()
+}
+
+// NewLocal returns a Client that calls the given VtctldServer in-process,
+// such as one returned by wrangler.Wrangler.VtctldServer().
+func NewLocal(server vtctlservicepb.VtctldServer) *Client {
+	return &Client{VtctldClient: localvtctldclient.New(server)}
+}
+
+// Close should be called when you're done using the Client.
+// For pooled connections, the underlying connection is left open for reuse.
+func (c *Client) Close() {
+	if c.release != nil {
+		c.release()
+		c.release = nil
+	}
+}
+
+// Call runs fn, which should make a single RPC, with a per-attempt timeout.
+// Attempts that fail because vtctld or the tablets behind it were briefly
+// unavailable are retried with backoff, as long as ctx allows.
+func Call(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := retryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = callOnce(ctx, fn)
+		if err == nil || attempt >= maxAttempts || !isRetryable(err) {
+			break
+		}
+		callRetries.Inc()
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+	if err != nil {
+		callErrors.Inc()
+	}
+	return err
+}
+
+func callOnce(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// isRetryable returns whether an RPC error is likely to be transient.
+// Errors may come either from a remote vtctld as gRPC statuses,
+// or from an in-process VtctldServer as Vitess errors.
+func isRetryable(err error) bool {
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_RESOURCE_EXHAUSTED:
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctldclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+func TestIsRetryable(t *testing.T) {
+	table := []struct {
+		err  error
+		want bool
+	}{
+		{status.Error(codes.Unavailable, "connection refused"), true},
+		{status.Error(codes.ResourceExhausted, "too many requests"), true},
+		{status.Error(codes.NotFound, "no such keyspace"), false},
+		{vterrors.New(vtrpcpb.Code_UNAVAILABLE, "tablet is not serving"), true},
+		{vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "shard has no primary"), false},
+		{errors.New("unknown"), false},
+	}
+
+	for _, test := range table {
+		if got := isRetryable(test.err); got != test.want {
+			t.Errorf("isRetryable(%v) = %v; want %v", test.err, got, test.want)
+		}
+	}
+}
+
+func TestCallRetries(t *testing.T) {
+	attempts := 0
+	err := Call(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return status.Error(codes.Unavailable, "connection refused")
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("Call() = %v after %v attempts; want nil after 2 attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = Call(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return status.Error(codes.NotFound, "no such keyspace")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Call() = %v after %v attempts; want error after 1 attempt", err, attempts)
+	}
+}
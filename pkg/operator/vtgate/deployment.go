@@ -28,6 +28,7 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
 	"planetscale.dev/vitess-operator/pkg/operator/names"
 	"planetscale.dev/vitess-operator/pkg/operator/secrets"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
@@ -42,11 +43,6 @@ const (
 
 	tabletTypesToWait = "MASTER,REPLICA"
 
-	bufferMasterTrafficDuringFailover = true
-	bufferMinTimeBetweenFailovers     = "20s"
-	bufferMaxFailoverDuration         = "10s"
-	bufferSize                        = 1000
-
 	grpcMaxMessageSize = 64 * 1024 * 1024
 
 	staticAuthDirName      = "vtgate-static-auth"
@@ -65,6 +61,7 @@ func DeploymentName(clusterName, cellName string) string {
 type Spec struct {
 	Cell                          *planetscalev2.VitessCellSpec
 	CellsToWatch                  []string
+	AllowedTabletTypes            []planetscalev2.VitessGatewayTabletType
 	Labels                        map[string]string
 	Replicas                      int32
 	Resources                     corev1.ResourceRequirements
@@ -225,6 +222,8 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 	// Update the Pod template, container, and flags for various optional things.
 	updateAuth(spec, flags, vtgateContainer, &obj.Spec.Template.Spec)
 	updateTransport(spec, flags, vtgateContainer, &obj.Spec.Template.Spec)
+	updateBuffer(spec, flags)
+	updateTopoClient(spec, flags, vtgateContainer, &obj.Spec.Template.Spec)
 	update.Volumes(&obj.Spec.Template.Spec.Volumes, spec.ExtraVolumes)
 
 	// Apply user-provided overrides last so they take precedence.
@@ -249,6 +248,8 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 	// Update the container we care about in the Pod template,
 	// ignoring other containers that may have been injected.
 	update.PodTemplateContainers(&obj.Spec.Template.Spec.Containers, []corev1.Container{*vtgateContainer})
+
+	update.PodTemplateResourceDefaults(&obj.Spec.Template.Spec, spec.Cell.ResourceDefaults)
 }
 
 func (spec *Spec) baseFlags() vitess.Flags {
@@ -259,18 +260,16 @@ func (spec *Spec) baseFlags() vitess.Flags {
 		// itself plus whatever cells might contain MySQL masters, assuming only certain
 		// cells are "master-eligible".
 		cellsToWatch = spec.Cell.AllCells
+	} else if !contains(cellsToWatch, spec.Cell.Name) {
+		// vtgate always needs to see the tablets in its own cell.
+		cellsToWatch = append([]string{spec.Cell.Name}, cellsToWatch...)
 	}
 
-	return vitess.Flags{
+	flags := vitess.Flags{
 		"cell":                 spec.Cell.Name,
 		"cells_to_watch":       strings.Join(cellsToWatch, ","),
 		"tablet_types_to_wait": tabletTypesToWait,
 
-		"enable_buffer":                     bufferMasterTrafficDuringFailover,
-		"buffer_min_time_between_failovers": bufferMinTimeBetweenFailovers,
-		"buffer_max_failover_duration":      bufferMaxFailoverDuration,
-		"buffer_size":                       bufferSize,
-
 		"grpc_max_message_size": grpcMaxMessageSize,
 
 		"mysql_server_port": planetscalev2.DefaultMysqlPort,
@@ -284,6 +283,83 @@ func (spec *Spec) baseFlags() vitess.Flags {
 		"port":        planetscalev2.DefaultWebPort,
 		"grpc_port":   planetscalev2.DefaultGrpcPort,
 	}
+	updateTabletTypes(spec, flags)
+	return flags
+}
+
+// vitessTabletTypes maps the tablet types in the API to the names vtgate uses.
+var vitessTabletTypes = map[planetscalev2.VitessGatewayTabletType]string{
+	planetscalev2.GatewayPrimaryTabletType: "MASTER",
+	planetscalev2.GatewayReplicaTabletType: "REPLICA",
+	planetscalev2.GatewayRdonlyTabletType:  "RDONLY",
+}
+
+func updateTabletTypes(spec *Spec, flags vitess.Flags) {
+	if len(spec.AllowedTabletTypes) == 0 {
+		return
+	}
+	allowed := make([]string, 0, len(spec.AllowedTabletTypes))
+	for _, tabletType := range spec.AllowedTabletTypes {
+		if name, ok := vitessTabletTypes[tabletType]; ok && !contains(allowed, name) {
+			allowed = append(allowed, name)
+		}
+	}
+	if len(allowed) == 0 {
+		return
+	}
+	flags["allowed_tablet_types"] = strings.Join(allowed, ",")
+
+	// Don't wait at startup for tablet types that vtgate won't route to.
+	var wait []string
+	for _, name := range strings.Split(tabletTypesToWait, ",") {
+		if contains(allowed, name) {
+			wait = append(wait, name)
+		}
+	}
+	if len(wait) == 0 {
+		wait = allowed
+	}
+	flags["tablet_types_to_wait"] = strings.Join(wait, ",")
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func updateBuffer(spec *Spec, flags vitess.Flags) {
+	buffer := spec.Cell.GatewayFailoverBuffer()
+	if buffer == nil || buffer.Enabled == nil {
+		// The cell controller fills in defaults, so this shouldn't happen.
+		return
+	}
+	flags["enable_buffer"] = *buffer.Enabled
+	if !*buffer.Enabled {
+		return
+	}
+	if buffer.Size != nil {
+		flags["buffer_size"] = *buffer.Size
+	}
+	if buffer.Window != nil {
+		flags["buffer_window"] = buffer.Window.Duration.String()
+	}
+	if buffer.MaxFailoverDuration != nil {
+		flags["buffer_max_failover_duration"] = buffer.MaxFailoverDuration.Duration.String()
+	}
+	if buffer.MinTimeBetweenFailovers != nil {
+		flags["buffer_min_time_between_failovers"] = buffer.MinTimeBetweenFailovers.Duration.String()
+	}
+}
+
+func updateTopoClient(spec *Spec, flags vitess.Flags, container *corev1.Container, podSpec *corev1.PodSpec) {
+	topoMounts := lockserver.NewClientMounts(&spec.Cell.GlobalLockserver)
+	flags.Merge(topoMounts.Flags())
+	update.Volumes(&podSpec.Volumes, topoMounts.PodVolumes())
+	container.VolumeMounts = append(container.VolumeMounts, topoMounts.ContainerVolumeMounts()...)
 }
 
 func updateAuth(spec *Spec, flags vitess.Flags, container *corev1.Container, podSpec *corev1.PodSpec) {
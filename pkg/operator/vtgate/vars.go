@@ -0,0 +1,87 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/webclient"
+)
+
+// Vars is the part of vtgate's /debug/vars that the operator looks at.
+type Vars struct {
+	// HealthcheckConnections counts the serving tablets vtgate routes to,
+	// keyed by "<keyspace>.<shard>.<tablet type>".
+	HealthcheckConnections map[string]int64
+
+	// VtgateApi and VtgateApiErrorCounts hold query counts. Both are keyed
+	// by "<operation>.<keyspace>.<tablet type>", followed by
+	// ".<error code>" for errors.
+	VtgateApi struct {
+		Histograms map[string]struct {
+			Count int64
+		}
+	}
+	VtgateApiErrorCounts map[string]int64
+}
+
+// GetVars fetches the vars of the vtgate in a Pod, unless they were fetched
+// less than maxAge ago.
+func GetVars(ctx context.Context, c *webclient.Client, pod *corev1.Pod, maxAge time.Duration) (*Vars, error) {
+	vars := &Vars{}
+	statusCode, err := c.GetJSON(ctx, webclient.URL(pod.Status.PodIP, "/debug/vars"), maxAge, vars)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("/debug/vars returned HTTP status %v", statusCode)
+	}
+	return vars, nil
+}
+
+// RunningPods returns the running vtgate Pods of a cluster, in any namespace,
+// since cells can deploy vtgate to another namespace than the cluster's. If
+// cellName is set, only the Pods of that cell are returned.
+func RunningPods(ctx context.Context, c client.Reader, clusterName, cellName string) ([]*corev1.Pod, error) {
+	labels := client.MatchingLabels{
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.ComponentLabel: planetscalev2.VtgateComponentName,
+	}
+	if cellName != "" {
+		labels[planetscalev2.CellLabel] = cellName
+	}
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, labels); err != nil {
+		return nil, fmt.Errorf("can't list vtgate Pods: %v", err)
+	}
+
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestVarsParse(t *testing.T) {
+	// An excerpt of vtgate's /debug/vars, with vars we don't use.
+	body := `{
+		"BuildTimestamp": "2024-01-01",
+		"HealthcheckConnections": {"commerce.-80.primary": 1, "commerce.-80.replica": 2},
+		"VtgateApi": {
+			"TotalCount": 12,
+			"Histograms": {"Execute.commerce.primary": {"1000000": 9, "Count": 10, "Time": 1234}}
+		},
+		"VtgateApiErrorCounts": {"Execute.commerce.primary.UNAVAILABLE": 2}
+	}`
+	vars := &Vars{}
+	if err := json.Unmarshal([]byte(body), vars); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if got, want := vars.HealthcheckConnections["commerce.-80.replica"], int64(2); got != want {
+		t.Errorf("HealthcheckConnections = %v; want %v", got, want)
+	}
+	if got, want := vars.VtgateApi.Histograms["Execute.commerce.primary"].Count, int64(10); got != want {
+		t.Errorf("VtgateApi count = %v; want %v", got, want)
+	}
+	if got, want := vars.VtgateApiErrorCounts["Execute.commerce.primary.UNAVAILABLE"], int64(2); got != want {
+		t.Errorf("VtgateApiErrorCounts = %v; want %v", got, want)
+	}
+}
+
+func TestRunningPods(t *testing.T) {
+	pod := func(namespace, name, cluster, cell string, phase corev1.PodPhase) client.Object {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					planetscalev2.ClusterLabel:   cluster,
+					planetscalev2.ComponentLabel: planetscalev2.VtgateComponentName,
+					planetscalev2.CellLabel:      cell,
+				},
+			},
+			Status: corev1.PodStatus{Phase: phase, PodIP: "10.0.0.1"},
+		}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		pod("vitess", "zone1", "example", "zone1", corev1.PodRunning),
+		// Cells can deploy vtgate to another namespace.
+		pod("gateways", "zone2", "example", "zone2", corev1.PodRunning),
+		pod("vitess", "zone2-pending", "example", "zone2", corev1.PodPending),
+		pod("vitess", "other", "other", "zone1", corev1.PodRunning),
+	).Build()
+
+	pods, err := RunningPods(context.Background(), c, "example", "")
+	if err != nil {
+		t.Fatalf("RunningPods() error: %v", err)
+	}
+	if got, want := len(pods), 2; got != want {
+		t.Errorf("len(RunningPods()) = %v; want %v", got, want)
+	}
+
+	pods, err = RunningPods(context.Background(), c, "example", "zone2")
+	if err != nil {
+		t.Fatalf("RunningPods() error: %v", err)
+	}
+	if len(pods) != 1 || pods[0].Namespace != "gateways" {
+		t.Errorf("RunningPods(zone2) = %v; want the Pod in namespace gateways", pods)
+	}
+}
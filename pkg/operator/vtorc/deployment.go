@@ -28,6 +28,7 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
 	"planetscale.dev/vitess-operator/pkg/operator/names"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
 	"planetscale.dev/vitess-operator/pkg/operator/vitess"
@@ -68,6 +69,7 @@ type Spec struct {
 	ExtraVolumeMounts []corev1.VolumeMount
 	InitContainers    []corev1.Container
 	SidecarContainers []corev1.Container
+	ResourceDefaults  *planetscalev2.ResourceDefaultsSpec
 	Annotations       map[string]string
 	ExtraLabels       map[string]string
 	Tolerations       []corev1.Toleration
@@ -137,6 +139,8 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 	obj.Spec.Template.Spec.ServiceAccountName = planetscalev2.DefaultVitessServiceAccount
 	obj.Spec.Template.Spec.Tolerations = spec.Tolerations
 	update.Volumes(&obj.Spec.Template.Spec.Volumes, spec.ExtraVolumes)
+	topoMounts := lockserver.NewClientMounts(&spec.GlobalLockserver)
+	update.Volumes(&obj.Spec.Template.Spec.Volumes, topoMounts.PodVolumes())
 
 	securityContext := &corev1.SecurityContext{}
 	if planetscalev2.DefaultVitessRunAsUser >= 0 {
@@ -176,12 +180,13 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 			InitialDelaySeconds: 300,
 			FailureThreshold:    30,
 		},
-		VolumeMounts: spec.ExtraVolumeMounts,
+		VolumeMounts: append(topoMounts.ContainerVolumeMounts(), spec.ExtraVolumeMounts...),
 		Env:          spec.ExtraEnv,
 	}
 	update.ResourceRequirements(&vtorcContainer.Resources, &spec.Resources)
 	vtorcContainer.Args = flags.FormatArgs()
 	update.PodTemplateContainers(&obj.Spec.Template.Spec.Containers, []corev1.Container{*vtorcContainer})
+	update.PodTemplateResourceDefaults(&obj.Spec.Template.Spec, spec.ResourceDefaults)
 
 	if spec.Affinity != nil {
 		obj.Spec.Template.Spec.Affinity = spec.Affinity
@@ -219,5 +224,5 @@ func (spec *Spec) flags() vitess.Flags {
 		"clusters_to_watch": spec.Keyspace + "/" + spec.Shard,
 
 		"logtostderr": true,
-	}
+	}.Merge(lockserver.NewClientMounts(&spec.GlobalLockserver).Flags())
 }
@@ -18,6 +18,7 @@ package vttablet
 
 import (
 	"planetscale.dev/vitess-operator/pkg/operator/lazy"
+	"planetscale.dev/vitess-operator/pkg/operator/mesh"
 )
 
 func init() {
@@ -25,4 +26,17 @@ func init() {
 		spec := s.(*Spec)
 		return spec.Annotations
 	})
+	tabletAnnotations.Add(func(s lazy.Spec) map[string]string {
+		spec := s.(*Spec)
+		if spec.DurabilityPolicy == "" {
+			return nil
+		}
+		return map[string]string{
+			durabilityPolicyAnnotationName: spec.DurabilityPolicy,
+		}
+	})
+	tabletAnnotations.Add(func(s lazy.Spec) map[string]string {
+		spec := s.(*Spec)
+		return mesh.Annotations(spec.Networking)
+	})
 }
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// autoscalerAnnotations keep node autoscalers from removing the Node of a
+// Pod, in order to move the Pod somewhere else.
+var autoscalerAnnotations = map[string]string{
+	// Karpenter since v0.32.
+	"karpenter.sh/do-not-disrupt": "true",
+	// Karpenter before v0.32.
+	"karpenter.sh/do-not-evict": "true",
+	// The cluster autoscaler.
+	"cluster-autoscaler.kubernetes.io/safe-to-evict": "false",
+}
+
+// updateAutoscalerAnnotations sets the autoscaler annotations on the Pod of
+// a protected primary, and removes them once it's no longer the primary,
+// unless they were requested for the whole pool.
+func updateAutoscalerAnnotations(obj *corev1.Pod, spec *Spec) {
+	if !spec.ProtectPrimary {
+		// Leave alone any annotations that someone else set.
+		return
+	}
+	for key, value := range autoscalerAnnotations {
+		if spec.IsPrimary {
+			if obj.Annotations == nil {
+				obj.Annotations = make(map[string]string, len(autoscalerAnnotations))
+			}
+			obj.Annotations[key] = value
+			continue
+		}
+		if _, ok := spec.Annotations[key]; !ok {
+			delete(obj.Annotations, key)
+		}
+	}
+}
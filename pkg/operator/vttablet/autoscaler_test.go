@@ -0,0 +1,47 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestUpdateAutoscalerAnnotations(t *testing.T) {
+	pod := &corev1.Pod{}
+	spec := &Spec{ProtectPrimary: true, IsPrimary: true}
+	updateAutoscalerAnnotations(pod, spec)
+	if got := pod.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"]; got != "false" {
+		t.Errorf("primary safe-to-evict = %q; want %q", got, "false")
+	}
+
+	// After a reparent, only the annotations the pool asked for are kept.
+	spec.IsPrimary = false
+	spec.Annotations = map[string]string{"karpenter.sh/do-not-disrupt": "true"}
+	updateAutoscalerAnnotations(pod, spec)
+	if len(pod.Annotations) != 1 || pod.Annotations["karpenter.sh/do-not-disrupt"] != "true" {
+		t.Errorf("replica annotations = %v; want only karpenter.sh/do-not-disrupt", pod.Annotations)
+	}
+
+	// Pools that don't protect their primary leave the annotations alone.
+	pod.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"] = "false"
+	updateAutoscalerAnnotations(pod, &Spec{})
+	if len(pod.Annotations) != 2 {
+		t.Errorf("unprotected annotations = %v; want them unchanged", pod.Annotations)
+	}
+}
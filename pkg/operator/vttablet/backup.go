@@ -19,11 +19,11 @@ package vttablet
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/lazy"
 	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
-	corev1 "k8s.io/api/core/v1"
 )
 
 func xtrabackupFlags(spec *Spec, backupThreads, restoreThreads int) vitess.Flags {
@@ -35,6 +35,14 @@ func xtrabackupFlags(spec *Spec, backupThreads, restoreThreads int) vitess.Flags
 		"xbstream_restore_flags":  fmt.Sprintf("--parallel=%d", restoreThreads),
 		"backup_storage_compress": true,
 	}
+	if x := spec.Xtrabackup; x != nil {
+		if x.Stripes != nil {
+			flags["xtrabackup_stripes"] = *x.Stripes
+		}
+		if x.StripeBlockSize != nil {
+			flags["xtrabackup_stripe_block_size"] = *x.StripeBlockSize
+		}
+	}
 
 	return flags
 }
@@ -55,6 +63,9 @@ func init() {
 			// When vttablets take backups, we let them keep serving, so we
 			// limit to single-threaded to reduce the impact.
 			backupThreads := 1
+			if x := spec.Xtrabackup; x != nil && x.BackupThreads != nil {
+				backupThreads = int(*x.BackupThreads)
+			}
 			// When vttablets are restoring, they can't serve at the same time
 			// anyway, so let the restore use all available CPUs for this Pod.
 			// This is cheating a bit, since xtrabackup technically counts
@@ -69,8 +80,8 @@ func init() {
 			}
 			flags.Merge(xtrabackupFlags(spec, backupThreads, restoreThreads))
 		}
-		clusterName := spec.Labels[planetscalev2.ClusterLabel]
-		storageLocationFlags := vitessbackup.StorageFlags(spec.BackupLocation, clusterName)
+		flags.Merge(spec.compressionFlags())
+		storageLocationFlags := vitessbackup.StorageFlags(spec.BackupLocation, spec.backupClusterName())
 		return flags.Merge(storageLocationFlags)
 	})
 
@@ -84,19 +95,26 @@ func init() {
 			"backup_engine_implementation": string(spec.BackupEngine),
 		}
 		if spec.BackupEngine == planetscalev2.VitessBackupEngineXtraBackup {
-			// A vtbackup Pod is given the same resources as the mysqld
-			// container for a vttablet in the shard would be given.
 			// We let vtbackup use all available CPUs during both backup and
 			// restore, since it is not serving queries anyway.
-			vtbackupCPU := spec.Mysqld.Resources.Requests[corev1.ResourceCPU]
+			vtbackupCPU := backupSpec.resources().Requests[corev1.ResourceCPU]
 			threads := int(vtbackupCPU.Value())
+			if v := backupSpec.Vtbackup; v != nil && v.XtrabackupParallelism != nil {
+				threads = int(*v.XtrabackupParallelism)
+			}
 			if threads < 1 {
 				threads = 1
 			}
 			flags.Merge(xtrabackupFlags(spec, threads, threads))
+			if v := backupSpec.Vtbackup; v != nil && v.XtrabackupStripes != nil {
+				flags["xtrabackup_stripes"] = *v.XtrabackupStripes
+			}
+		}
+		if v := backupSpec.Vtbackup; v != nil && v.Timeout != nil {
+			flags["timeout"] = v.Timeout.Duration
 		}
-		clusterName := spec.Labels[planetscalev2.ClusterLabel]
-		storageLocationFlags := vitessbackup.StorageFlags(spec.BackupLocation, clusterName)
+		flags.Merge(spec.compressionFlags())
+		storageLocationFlags := vitessbackup.StorageFlags(spec.BackupLocation, spec.backupClusterName())
 		return flags.Merge(storageLocationFlags)
 	})
 
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+// compressionEnginesMinVersion is the first Vitess major version that can
+// compress backups with anything but pgzip.
+const compressionEnginesMinVersion = 15
+
+// imageVersionPattern matches image tags like v15.0.2 or v16.0.0-mysql80.
+var imageVersionPattern = regexp.MustCompile(`^v?(\d+)\.`)
+
+// backupCompression returns the compression settings for the tablet's
+// backup engine, if any.
+func (spec *Spec) backupCompression() *planetscalev2.BackupCompressionSpec {
+	if x := spec.Xtrabackup; x != nil && x.Compression != nil && spec.BackupEngine == planetscalev2.VitessBackupEngineXtraBackup {
+		return x.Compression
+	}
+	return spec.BackupCompression
+}
+
+// compressionFlags returns the flags that choose how backups are compressed.
+// Invalid settings are left out. The shard controller reports them.
+func (spec *Spec) compressionFlags() vitess.Flags {
+	c := spec.backupCompression()
+	if c == nil || ValidateBackupCompression(c, spec.Images.Vttablet) != nil {
+		return nil
+	}
+	return compressionFlags(c)
+}
+
+// ValidateBackupCompression returns an error describing what's wrong with
+// the compression settings, given the vttablet image they would be used with.
+func ValidateBackupCompression(c *planetscalev2.BackupCompressionSpec, vttabletImage string) error {
+	if isDefaultCompression(c) {
+		return nil
+	}
+	if c.Engine == planetscalev2.ExternalBackupCompression && (c.ExternalCompressor == "" || c.ExternalDecompressor == "") {
+		return fmt.Errorf("compression engine %v needs externalCompressor and externalDecompressor", c.Engine)
+	}
+	if version, ok := imageMajorVersion(vttabletImage); ok && version < compressionEnginesMinVersion {
+		return fmt.Errorf("compression settings need Vitess v%v or later, but the vttablet image is v%v", compressionEnginesMinVersion, version)
+	}
+	return nil
+}
+
+// isDefaultCompression returns whether the settings ask for nothing but what
+// every Vitess version does, which is pgzip at its default level.
+func isDefaultCompression(c *planetscalev2.BackupCompressionSpec) bool {
+	return (c.Engine == "" || c.Engine == planetscalev2.PgzipBackupCompression) && c.Level == nil
+}
+
+// compressionFlags returns the flags that choose how backups are compressed.
+func compressionFlags(c *planetscalev2.BackupCompressionSpec) vitess.Flags {
+	if isDefaultCompression(c) {
+		// Older versions don't know these flags, so only pass them if needed.
+		return nil
+	}
+	flags := vitess.Flags{}
+	if c.Engine != "" {
+		flags["compression-engine-name"] = string(c.Engine)
+	}
+	if c.Level != nil {
+		flags["compression-level"] = *c.Level
+	}
+	if c.Engine == planetscalev2.ExternalBackupCompression {
+		flags["external-compressor"] = c.ExternalCompressor
+		flags["external-decompressor"] = c.ExternalDecompressor
+		if c.ExternalExtension != "" {
+			flags["external-compressor-extension"] = c.ExternalExtension
+		}
+	}
+	return flags
+}
+
+// imageMajorVersion returns the Vitess major version in an image's tag, if
+// the tag looks like a release version. Images pinned only by digest, or
+// with tags like "latest", have no known version.
+func imageMajorVersion(image string) (int, bool) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// A colon before the last slash is part of a registry host:port.
+	i := strings.LastIndex(image, ":")
+	if i < 0 || i < strings.LastIndex(image, "/") {
+		return 0, false
+	}
+	match := imageVersionPattern.FindStringSubmatch(image[i+1:])
+	if match == nil {
+		return 0, false
+	}
+	version, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
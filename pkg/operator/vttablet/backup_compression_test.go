@@ -0,0 +1,69 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestImageMajorVersion(t *testing.T) {
+	table := []struct {
+		image   string
+		version int
+		ok      bool
+	}{
+		{"vitess/lite:v15.0.2", 15, true},
+		{"vitess/lite:v16.0.0-mysql80", 16, true},
+		{"registry.example.com:5000/vitess/lite:14.0.1", 14, true},
+		{"registry.example.com:5000/vitess/lite", 0, false},
+		{"vitess/lite:latest", 0, false},
+		{"vitess/lite@sha256:0123", 0, false},
+	}
+
+	for _, test := range table {
+		version, ok := imageMajorVersion(test.image)
+		if version != test.version || ok != test.ok {
+			t.Errorf("imageMajorVersion(%q) = %v, %v; want %v, %v", test.image, version, ok, test.version, test.ok)
+		}
+	}
+}
+
+func TestValidateBackupCompression(t *testing.T) {
+	level := int32(3)
+	table := []struct {
+		compression *planetscalev2.BackupCompressionSpec
+		image       string
+		valid       bool
+	}{
+		{&planetscalev2.BackupCompressionSpec{Engine: planetscalev2.PgzipBackupCompression}, "vitess/lite:v14.0.0", true},
+		{&planetscalev2.BackupCompressionSpec{Level: &level}, "vitess/lite:v14.0.0", false},
+		{&planetscalev2.BackupCompressionSpec{Engine: planetscalev2.ZstdBackupCompression}, "vitess/lite:v14.0.0", false},
+		{&planetscalev2.BackupCompressionSpec{Engine: planetscalev2.ZstdBackupCompression}, "vitess/lite:v15.0.0", true},
+		{&planetscalev2.BackupCompressionSpec{Engine: planetscalev2.ZstdBackupCompression}, "vitess/lite:latest", true},
+		{&planetscalev2.BackupCompressionSpec{Engine: planetscalev2.ExternalBackupCompression, ExternalCompressor: "zstd"}, "vitess/lite:v15.0.0", false},
+		{&planetscalev2.BackupCompressionSpec{Engine: planetscalev2.ExternalBackupCompression, ExternalCompressor: "zstd", ExternalDecompressor: "zstd -d"}, "vitess/lite:v15.0.0", true},
+	}
+
+	for _, test := range table {
+		err := ValidateBackupCompression(test.compression, test.image)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateBackupCompression(%+v, %q) = %v; want valid: %v", test.compression, test.image, err, test.valid)
+		}
+	}
+}
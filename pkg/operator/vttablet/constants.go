@@ -20,6 +20,10 @@ import (
 	"time"
 )
 
+// MysqldExporterPortName is the name of the container port on which
+// mysqld-exporter serves metrics.
+const MysqldExporterPortName = "metrics"
+
 const (
 	vttabletContainerName = "vttablet"
 	vttabletCommand       = "/vt/bin/vttablet"
@@ -32,9 +36,8 @@ const (
 
 	mysqldExporterContainerName      = "mysqld-exporter"
 	mysqldExporterCommand            = "/bin/mysqld_exporter"
-	mysqldExporterUser               = "vt_dba"
 	mysqldExporterPort               = 9104
-	mysqldExporterPortName           = "metrics"
+	mysqldExporterPasswordEnvVar     = "MYSQLD_EXPORTER_PASSWORD"
 	mysqldExporterCPURequestMillis   = 10
 	mysqldExporterCPULimitMillis     = 100
 	mysqldExporterMemoryRequestBytes = 32 * (1 << 20)  // 32 MiB
@@ -52,12 +55,9 @@ const (
 
 	grpcMaxMessageSize = 64 * 1024 * 1024 // 64 MiB
 
-	queryserverConfigMaxResultSize = 100000
-	queryserverConfigQueryTimeout  = 900
-
-	queryserverConfigPoolSize       = 96
-	queryserverConfigStreamPoolSize = 96
-	queryserverConfigTransactionCap = 300
+	// These are the vttablet defaults for hot row protection queues.
+	hotRowProtectionMaxQueueSize       = 20
+	hotRowProtectionMaxGlobalQueueSize = 1000
 
 	vtRootPath         = "/vt"
 	vtBinPath          = vtRootPath + "/bin"
@@ -84,12 +84,17 @@ const (
 	vreplicationTabletType = "master"
 
 	dbInitScriptDirName = "db-init-script"
+	credentialsDirName  = "internal-credentials"
 
 	externalDatastoreCredentialsDirName = "external-datastore-credentials"
 	externalDatastoreCACertDirName      = "external-datastore-ca-cert"
 
 	enableSSLBitflag = 2048
 
+	// durabilityPolicyAnnotationName records the keyspace durability policy
+	// on tablet Pods, so they're restarted to pick up a new one.
+	durabilityPolicyAnnotationName = "planetscale.com/durability-policy"
+
 	mysqldConfigOverridesAnnotationName      = "planetscale.com/mysqld-config-overrides"
 	mysqldConfigOverridesAnnotationFieldPath = "metadata.annotations['" + mysqldConfigOverridesAnnotationName + "']"
 
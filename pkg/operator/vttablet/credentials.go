@@ -0,0 +1,129 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/lazy"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+// CredentialsSecretKey is the key of the credentials file for the internal
+// MySQL accounts in the Secret the operator maintains for a shard.
+const CredentialsSecretKey = "db-credentials.json"
+
+func init() {
+	// Mount the credentials file of the internal accounts, if any.
+	tabletVolumes.Add(func(s lazy.Spec) []corev1.Volume {
+		spec := s.(*Spec)
+		if spec.CredentialsSecret == "" {
+			return nil
+		}
+		return credentialsFile(spec).PodVolumes()
+	})
+	tabletVolumeMounts.Add(func(s lazy.Spec) []corev1.VolumeMount {
+		spec := s.(*Spec)
+		if spec.CredentialsSecret == "" {
+			return nil
+		}
+		return []corev1.VolumeMount{credentialsFile(spec).ContainerVolumeMount()}
+	})
+
+	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
+		return credentialsFlags(s.(*Spec))
+	})
+	vtbackupFlags.Add(func(s lazy.Spec) vitess.Flags {
+		return credentialsFlags(s.(*BackupSpec).TabletSpec)
+	})
+}
+
+// MysqldExporterSecretKey is the key of the password of the exporter account
+// in the Secret the operator generates for a shard.
+const MysqldExporterSecretKey = "password"
+
+// MysqldExporterSecretName returns the name of the Secret with the password
+// of the MySQL account that mysqld-exporter uses, given the name of the
+// VitessShard.
+func MysqldExporterSecretName(shardName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, shardName, "mysqld-exporter")
+}
+
+// NewMysqldExporterSecret creates a new Secret with the password of the
+// exporter account.
+func NewMysqldExporterSecret(key client.ObjectKey, labels map[string]string, password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			MysqldExporterSecretKey: []byte(password),
+		},
+	}
+}
+
+// CredentialsSecretName returns the name of the Secret with the credentials
+// file of the internal MySQL accounts of a shard, given the name of the
+// VitessShard.
+func CredentialsSecretName(shardName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, shardName, "credentials")
+}
+
+// NewCredentialsSecret creates a new Secret with a credentials file.
+func NewCredentialsSecret(key client.ObjectKey, labels map[string]string, file []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			CredentialsSecretKey: file,
+		},
+	}
+}
+
+// UpdateCredentialsSecretInPlace updates an existing Secret with a
+// credentials file.
+func UpdateCredentialsSecretInPlace(obj *corev1.Secret, file []byte) {
+	if obj.Data == nil {
+		obj.Data = make(map[string][]byte, 1)
+	}
+	obj.Data[CredentialsSecretKey] = file
+}
+
+func credentialsFile(spec *Spec) *secrets.VolumeMount {
+	return secrets.Mount(&planetscalev2.SecretSource{Name: spec.CredentialsSecret, Key: CredentialsSecretKey}, credentialsDirName)
+}
+
+func credentialsFlags(spec *Spec) vitess.Flags {
+	if spec.CredentialsSecret == "" {
+		return nil
+	}
+	return vitess.Flags{
+		"db-credentials-file": credentialsFile(spec).FilePath(),
+	}
+}
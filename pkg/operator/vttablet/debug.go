@@ -0,0 +1,95 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// debugContainerPrefix is the name prefix of ephemeral debug containers.
+const debugContainerPrefix = "debug-"
+
+/*
+DebugContainer returns an ephemeral container to add to a vttablet Pod for
+debugging, or nil if the Pod has no mysqld or vttablet container to attach
+it to.
+
+The container runs the given image, or the Pod's vttablet image if it's
+empty. It shares the process namespace and the volumes of the mysqld
+container (or of vttablet, for tablets with an external datastore), so the
+MySQL socket and data files are at the usual paths. It exits after the given
+duration, since Kubernetes doesn't allow removing ephemeral containers from
+a Pod.
+*/
+func DebugContainer(pod *corev1.Pod, image string, duration time.Duration) *corev1.EphemeralContainer {
+	target := podContainer(pod, mysqldContainerName)
+	if target == nil {
+		target = podContainer(pod, vttabletContainerName)
+	}
+	if target == nil {
+		return nil
+	}
+	if vttablet := podContainer(pod, vttabletContainerName); image == "" && vttablet != nil {
+		image = vttablet.Image
+	}
+
+	var volumeMounts []corev1.VolumeMount
+	for _, mount := range target.VolumeMounts {
+		// Ephemeral containers can't use subPath mounts.
+		if mount.SubPath == "" && mount.SubPathExpr == "" {
+			volumeMounts = append(volumeMounts, mount)
+		}
+	}
+
+	return &corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:         fmt.Sprintf("%s%d", debugContainerPrefix, len(pod.Spec.EphemeralContainers)+1),
+			Image:        image,
+			Command:      []string{"sleep", strconv.Itoa(int(duration.Seconds()))},
+			Env:          target.Env,
+			VolumeMounts: volumeMounts,
+			Stdin:        true,
+			TTY:          true,
+		},
+		TargetContainerName: target.Name,
+	}
+}
+
+// RunningDebugContainer returns the name of a debug container that's still
+// running in a vttablet Pod, or "" if there's none.
+func RunningDebugContainer(pod *corev1.Pod) string {
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if strings.HasPrefix(status.Name, debugContainerPrefix) && status.State.Terminated == nil {
+			return status.Name
+		}
+	}
+	return ""
+}
+
+func podContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDebugContainer(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: vttabletContainerName, Image: "vitess/lite"},
+				{
+					Name:  mysqldContainerName,
+					Image: "vitess/lite",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "vt", MountPath: "/vt/vtdataroot"},
+						{Name: "config", MountPath: "/vt/config/my.cnf", SubPath: "my.cnf"},
+					},
+				},
+			},
+			EphemeralContainers: []corev1.EphemeralContainer{
+				{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debug-1"}},
+			},
+		},
+	}
+
+	got := DebugContainer(pod, "", time.Hour)
+	if got == nil {
+		t.Fatalf("DebugContainer() = nil")
+	}
+	if got.Name != "debug-2" || got.Image != "vitess/lite" || got.TargetContainerName != mysqldContainerName {
+		t.Errorf("DebugContainer() = %v with image %v targeting %v; want debug-2 with image vitess/lite targeting mysqld", got.Name, got.Image, got.TargetContainerName)
+	}
+	if want := []string{"sleep", "3600"}; !reflect.DeepEqual(got.Command, want) {
+		t.Errorf("DebugContainer().Command = %v; want %v", got.Command, want)
+	}
+	if want := pod.Spec.Containers[1].VolumeMounts[:1]; !reflect.DeepEqual(got.VolumeMounts, want) {
+		t.Errorf("DebugContainer().VolumeMounts = %v; want %v", got.VolumeMounts, want)
+	}
+
+	if got := DebugContainer(pod, "toolbox", time.Hour); got.Image != "toolbox" {
+		t.Errorf("DebugContainer() with an image = %v; want toolbox", got.Image)
+	}
+	if got := DebugContainer(&corev1.Pod{}, "", time.Hour); got != nil {
+		t.Errorf("DebugContainer() of a Pod without tablet containers = %v; want nil", got.Name)
+	}
+}
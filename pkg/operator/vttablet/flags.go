@@ -41,6 +41,23 @@ func init() {
 			},
 		}
 	})
+	// Tablets that advertise DNS names also need to know their namespace.
+	tabletEnvVars.Add(func(s lazy.Spec) []corev1.EnvVar {
+		spec := s.(*Spec)
+		if !spec.Networking.TabletHostnames() {
+			return nil
+		}
+		return []corev1.EnvVar{
+			{
+				Name: "POD_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.namespace",
+					},
+				},
+			},
+		}
+	})
 
 	// Base vttablet flags.
 	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
@@ -58,22 +75,16 @@ func init() {
 
 			"tablet-path": topoproto.TabletAliasString(&spec.Alias),
 
-			// We inject the POD_IP environment variable up above via the Pod Downward API.
-			// The Pod args list natively expands environment variables in this format,
-			// so we don't need to use a shell to launch vttablet.
-			"tablet_hostname": "$(POD_IP)",
+			// We inject the environment variables this refers to up above via the
+			// Pod Downward API. The Pod args list natively expands environment
+			// variables in this format, so we don't need to use a shell to launch vttablet.
+			"tablet_hostname": spec.tabletHostname(),
 
 			"init_keyspace":    spec.KeyspaceName,
 			"init_shard":       spec.KeyRange.String(),
 			"init_tablet_type": spec.Type.InitTabletType(),
 
 			"health_check_interval": healthCheckInterval,
-
-			"queryserver-config-max-result-size":  queryserverConfigMaxResultSize,
-			"queryserver-config-query-timeout":    queryserverConfigQueryTimeout,
-			"queryserver-config-pool-size":        queryserverConfigPoolSize,
-			"queryserver-config-stream-pool-size": queryserverConfigStreamPoolSize,
-			"queryserver-config-transaction-cap":  queryserverConfigTransactionCap,
 		}
 	})
 
@@ -100,7 +111,7 @@ func init() {
 		dbInitScript := secrets.Mount(&spec.DatabaseInitScriptSecret, dbInitScriptDirName)
 		return vitess.Flags{
 			// vtbackup-specific flags.
-			"concurrency":         vtbackupConcurrency,
+			"concurrency":         backupSpec.concurrency(),
 			"initial_backup":      backupSpec.InitialBackup,
 			"min_backup_interval": backupSpec.MinBackupInterval,
 			"min_retention_time":  backupSpec.MinRetentionTime,
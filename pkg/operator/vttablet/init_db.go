@@ -0,0 +1,91 @@
+/*
+Copyright 2019 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"vitess.io/vitess/config"
+
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+)
+
+// InitDBSecretKey is the key of the rendered init_db.sql script in the
+// Secret the operator maintains for a shard.
+const InitDBSecretKey = "init_db.sql"
+
+// InitDBFragment is a piece of SQL to append to an init_db.sql script.
+type InitDBFragment struct {
+	// Source describes where the fragment came from.
+	Source string
+	// SQL is the content of the fragment.
+	SQL string
+}
+
+// InitDBSecretName returns the name of the Secret with the rendered
+// init_db.sql script of a shard, given the name of the VitessShard.
+func InitDBSecretName(shardName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, shardName, "init-db")
+}
+
+// BaselineInitDB returns the init_db.sql script of the Vitess version the
+// operator is built against.
+func BaselineInitDB() string {
+	return config.DefaultInitDB
+}
+
+// RenderInitDB appends fragments, in order, to a base init_db.sql script.
+func RenderInitDB(base string, fragments []InitDBFragment) string {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, fragment := range fragments {
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "\n# Fragment from %v.\n", fragment.Source)
+		b.WriteString(fragment.SQL)
+	}
+	return b.String()
+}
+
+// NewInitDBSecret creates a new Secret with a rendered init_db.sql script.
+func NewInitDBSecret(key client.ObjectKey, labels map[string]string, script string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			InitDBSecretKey: []byte(script),
+		},
+	}
+}
+
+// UpdateInitDBSecretInPlace updates an existing Secret with a rendered
+// init_db.sql script.
+func UpdateInitDBSecretInPlace(obj *corev1.Secret, script string) {
+	if obj.Data == nil {
+		obj.Data = make(map[string][]byte, 1)
+	}
+	obj.Data[InitDBSecretKey] = []byte(script)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderInitDB(t *testing.T) {
+	fragments := []InitDBFragment{
+		{Source: "Secret users/init_db.sql", SQL: "CREATE USER 'app'@'%';"},
+		{Source: "Secret grants/init_db.sql", SQL: "GRANT SELECT ON *.* TO 'app'@'%';\n"},
+	}
+	script := RenderInitDB(BaselineInitDB(), fragments)
+
+	if !strings.HasPrefix(script, BaselineInitDB()) {
+		t.Errorf("RenderInitDB() doesn't start with the baseline script")
+	}
+	users := strings.Index(script, "CREATE USER 'app'@'%';\n")
+	grants := strings.Index(script, "# Fragment from Secret grants/init_db.sql.\nGRANT SELECT")
+	if users < 0 || grants < users {
+		t.Errorf("RenderInitDB() = %q; want fragments appended in order", script[len(BaselineInitDB()):])
+	}
+	if got := RenderInitDB("SELECT 1;", nil); got != "SELECT 1;" {
+		t.Errorf("RenderInitDB() without fragments = %q; want the base script", got)
+	}
+}
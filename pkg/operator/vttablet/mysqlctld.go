@@ -54,11 +54,15 @@ func init() {
 	// Copy Vitess files needed by mysqlctld into the mysqld container,
 	// which might be using a stock MySQL image.
 	tabletVolumes.Add(func(s lazy.Spec) []corev1.Volume {
+		spec := s.(*Spec)
 		return []corev1.Volume{
 			{
 				Name: vtRootVolumeName,
 				VolumeSource: corev1.VolumeSource{
-					EmptyDir: &corev1.EmptyDirVolumeSource{},
+					// Without a PVC, this also holds the data directory.
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						SizeLimit: spec.ScratchSizeLimit,
+					},
 				},
 			},
 		}
@@ -77,7 +81,7 @@ func init() {
 			{
 				Name:            "init-vt-root",
 				SecurityContext: securityContext,
-				Image:           spec.Images.Vttablet,
+				Image:           spec.Images.InitImage(spec.Images.Vttablet),
 				ImagePullPolicy: spec.ImagePullPolicies.Vttablet,
 				VolumeMounts: []corev1.VolumeMount{
 					{
@@ -104,7 +108,7 @@ func init() {
 			initContainers = append(initContainers, corev1.Container{
 				Name:            "init-mysql-socket",
 				SecurityContext: securityContext,
-				Image:           spec.Images.Vttablet,
+				Image:           spec.Images.InitImage(spec.Images.Vttablet),
 				ImagePullPolicy: spec.ImagePullPolicies.Vttablet,
 				VolumeMounts: []corev1.VolumeMount{
 					{
@@ -33,13 +33,16 @@ import (
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/desiredstatehash"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/mesh"
+	"planetscale.dev/vitess-operator/pkg/operator/mysqlusers"
 	"planetscale.dev/vitess-operator/pkg/operator/names"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 )
 
 // PodName returns the name of the Pod for a given vttablet.
-func PodName(clusterName string, tabletAlias topodatapb.TabletAlias) string {
-	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VttabletComponentName, topoproto.TabletAliasString(&tabletAlias))
+func PodName(clusterName string, tabletAlias *topodatapb.TabletAlias) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VttabletComponentName, topoproto.TabletAliasString(tabletAlias))
 }
 
 // NewPod creates a new vttablet Pod from a Spec.
@@ -60,6 +63,9 @@ func NewPod(key client.ObjectKey, spec *Spec) *corev1.Pod {
 func UpdatePodInPlace(obj *corev1.Pod, spec *Spec) {
 	// Update labels and annotations, but ignore existing ones we don't set.
 	update.Labels(&obj.Labels, spec.Labels)
+
+	// Follow the primary around with the annotations that protect its Node.
+	updateAutoscalerAnnotations(obj, spec)
 }
 
 // UpdatePod updates all parts of a vttablet Pod to match the desired state,
@@ -160,6 +166,9 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	}
 	// Make a copy of Resources since it contains pointers.
 	update.ResourceRequirements(&vttabletContainer.Resources, &spec.Vttablet.Resources)
+	// Apply user-provided probe tuning last so it takes precedence.
+	update.Probe(&vttabletContainer.ReadinessProbe, spec.Vttablet.ReadinessProbe)
+	update.Probe(&vttabletContainer.LivenessProbe, spec.Vttablet.LivenessProbe)
 
 	var mysqldContainer *corev1.Container
 	var mysqldExporterContainer *corev1.Container
@@ -193,6 +202,8 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 		}
 
 		update.ResourceRequirements(&mysqldContainer.Resources, &spec.Mysqld.Resources)
+		update.Probe(&mysqldContainer.ReadinessProbe, spec.Mysqld.ReadinessProbe)
+		update.Probe(&mysqldContainer.LivenessProbe, spec.Mysqld.LivenessProbe)
 
 		// TODO: Can/should we still run mysqld_exporter pointing at external mysql?
 		mysqldExporterContainer = &corev1.Container{
@@ -200,23 +211,11 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 			Image:           spec.Images.MysqldExporter,
 			ImagePullPolicy: spec.ImagePullPolicies.MysqldExporter,
 			Command:         []string{mysqldExporterCommand},
-			Args: []string{
-				"--config.my-cnf=" + spec.myCnfFilePath(),
-				// The default for `collect.info_schema.tables.databases` is
-				// `*`, which causes new time series to be created for each user
-				// table. This in turn causes scaling issues in Prometheus
-				// memory usage.
-				"--collect.info_schema.tables.databases=sys,_vt",
-			},
-			Env: []corev1.EnvVar{
-				{
-					Name:  "DATA_SOURCE_NAME",
-					Value: fmt.Sprintf("%s@unix(%s)/", mysqldExporterUser, mysqlSocketPath),
-				},
-			},
+			Args:            mysqldExporterArgs(spec),
+			Env:             mysqldExporterEnv(spec),
 			Ports: []corev1.ContainerPort{
 				{
-					Name:          mysqldExporterPortName,
+					Name:          MysqldExporterPortName,
 					ContainerPort: mysqldExporterPort,
 				},
 			},
@@ -238,6 +237,11 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 			//   This depends on the exact semantics of each of mysqld-exporter's HTTP handlers,
 			//   so we need to do more investigation. For now it's better to leave them empty.
 		}
+		if spec.MysqldExporter != nil && spec.MysqldExporter.Resources != nil {
+			// Replace the defaults entirely rather than merging, so users can
+			// remove limits by leaving them out.
+			mysqldExporterContainer.Resources = *spec.MysqldExporter.Resources.DeepCopy()
+		}
 	}
 
 	// Set the resource requirements on each of the default vttablet init
@@ -264,17 +268,33 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	if spec.Mysqld != nil {
 		containers = append(containers, *mysqldContainer)
 
-		// Only deploy mysqld-exporter if the image is set.
-		if mysqldExporterContainer.Image != "" {
+		// Only deploy mysqld-exporter if the image is set and the pool hasn't
+		// opted out.
+		if mysqldExporterContainer.Image != "" && (spec.MysqldExporter == nil || !spec.MysqldExporter.Disabled) {
 			containers = append(containers, *mysqldExporterContainer)
 		}
 	}
 
+	initContainers = update.ContainerResourceDefaults(initContainers, spec.ResourceDefaults)
+	sidecarContainers = update.ContainerResourceDefaults(sidecarContainers, spec.ResourceDefaults)
+	containers = update.ContainerResourceDefaults(containers, spec.ResourceDefaults)
+
+	// In host network mode, declare the ports we listen on as host ports, so
+	// the scheduler never puts two tablets whose ports conflict on one node.
+	if spec.HostNetwork {
+		for i := range containers {
+			for j := range containers[i].Ports {
+				containers[i].Ports[j].HostPort = containers[i].Ports[j].ContainerPort
+			}
+		}
+	}
+
 	// Record hashes of desired label and annotation keys to force the Pod
 	// to be recreated if a key disappears from the desired list.
 	desiredStateHash := desiredstatehash.NewBuilder()
 	desiredStateHash.AddStringMapKeys("labels-keys", spec.ExtraLabels)
 	desiredStateHash.AddStringMapKeys("annotations-keys", spec.Annotations)
+	desiredStateHash.AddStringMapKeys("mesh-annotations-keys", mesh.Annotations(spec.Networking))
 
 	// Record a hash of desired containers to force the Pod to be recreated if
 	// something is removed from our desired state that we otherwise might
@@ -282,10 +302,21 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	desiredStateHash.AddContainersUpdates("init-containers", initContainers)
 	desiredStateHash.AddContainersUpdates("containers", containers)
 
+	// Add the constraints of the spread policy to those requested directly.
+	topologySpreadConstraints := make([]corev1.TopologySpreadConstraint, 0, len(spec.TopologySpreadConstraints)+1)
+	topologySpreadConstraints = append(topologySpreadConstraints, spec.TopologySpreadConstraints...)
+	topologySpreadConstraints = append(topologySpreadConstraints, spec.spreadConstraints()...)
+
 	// Record a hash of desired tolerations and topologySpreadConstraints
 	// to force the Pod to be recreated if one disappears from the desired list.
 	desiredStateHash.AddTolerations("tolerations", spec.Tolerations)
-	desiredStateHash.AddTopologySpreadConstraints("topologySpreadConstraints", spec.TopologySpreadConstraints)
+	desiredStateHash.AddTopologySpreadConstraints("topologySpreadConstraints", topologySpreadConstraints)
+
+	// Restart vttablet to pick up new passwords of the internal accounts,
+	// since it only reads them when it starts.
+	if spec.CredentialsHash != "" {
+		desiredStateHash.AddStringList("internal-credentials", []string{spec.CredentialsHash})
+	}
 
 	// Add the final desired state hash annotation.
 	update.Annotations(&obj.Annotations, map[string]string{
@@ -302,9 +333,9 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	obj.Spec.ImagePullSecrets = spec.ImagePullSecrets
 	update.Annotations(&obj.Annotations, tabletAnnotations.Get(spec))
 	update.Volumes(&obj.Spec.Volumes, tabletVolumes.Get(spec))
-	update.Volumes(&obj.Spec.Volumes, spec.ExtraVolumes)
+	update.Volumes(&obj.Spec.Volumes, extraVolumes(spec))
 	update.Tolerations(&obj.Spec.Tolerations, spec.Tolerations)
-	update.TopologySpreadConstraints(&obj.Spec.TopologySpreadConstraints, spec.TopologySpreadConstraints)
+	update.TopologySpreadConstraints(&obj.Spec.TopologySpreadConstraints, topologySpreadConstraints)
 
 	if obj.Spec.SecurityContext == nil {
 		obj.Spec.SecurityContext = &corev1.PodSecurityContext{}
@@ -315,37 +346,35 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 
 	obj.Spec.TerminationGracePeriodSeconds = pointer.Int64Ptr(terminationGracePeriodSeconds)
 
+	// Give the Pod a DNS name in the vttablet Service if it advertises one.
+	if spec.Networking.TabletHostnames() {
+		obj.Spec.Hostname = spec.podHostname()
+		obj.Spec.Subdomain = ServiceName(spec.Labels[planetscalev2.ClusterLabel])
+	} else {
+		obj.Spec.Hostname = ""
+		obj.Spec.Subdomain = ""
+	}
+
+	obj.Spec.NodeSelector = spec.NodeSelector
+	obj.Spec.HostNetwork = spec.HostNetwork
+	obj.Spec.DNSConfig = spec.DNSConfig
+	obj.Spec.DNSPolicy = spec.DNSPolicy
+	if obj.Spec.DNSPolicy == "" {
+		// Without this, Pods in host network mode would use the node's
+		// resolver, and couldn't resolve cluster Services like the lockserver.
+		obj.Spec.DNSPolicy = corev1.DNSClusterFirst
+		if spec.HostNetwork {
+			obj.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		}
+	}
+
 	// In both the case of the user injecting their own affinity and the default, we
 	// simply override the pod's existing affinity configuration.
 	if spec.Affinity != nil {
 		obj.Spec.Affinity = spec.Affinity
 	} else {
 		obj.Spec.Affinity = &corev1.Affinity{
-			PodAntiAffinity: &corev1.PodAntiAffinity{
-				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
-					{
-						// A Node with no members of the same shard would be ideal.
-						Weight: 2,
-						PodAffinityTerm: corev1.PodAffinityTerm{
-							LabelSelector: &metav1.LabelSelector{
-								MatchLabels: spec.shardLabels(),
-							},
-							TopologyKey: k8s.HostnameLabel,
-						},
-					},
-					{
-						// If that's not possible, a Node that at least has no
-						// members of the exact same pool would be nice.
-						Weight: 1,
-						PodAffinityTerm: corev1.PodAffinityTerm{
-							LabelSelector: &metav1.LabelSelector{
-								MatchLabels: spec.poolLabels(),
-							},
-							TopologyKey: k8s.HostnameLabel,
-						},
-					},
-				},
-			},
+			PodAntiAffinity: spec.podAntiAffinity(),
 		}
 		if spec.Zone != "" {
 			// Limit to a specific zone.
@@ -380,9 +409,173 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 
 // AliasFromPod returns a TabletAlias corresponding to a vttablet Pod.
 func AliasFromPod(pod *corev1.Pod) topodatapb.TabletAlias {
-	uid, _ := strconv.ParseUint(pod.Labels[planetscalev2.TabletUidLabel], 10, 32)
+	return AliasFromLabels(pod.Labels)
+}
+
+// AliasFromLabels returns a TabletAlias corresponding to the labels of a
+// vttablet Pod or PVC.
+func AliasFromLabels(labels map[string]string) topodatapb.TabletAlias {
+	uid, _ := strconv.ParseUint(labels[planetscalev2.TabletUidLabel], 10, 32)
 	return topodatapb.TabletAlias{
-		Cell: pod.Labels[planetscalev2.CellLabel],
+		Cell: labels[planetscalev2.CellLabel],
 		Uid:  uint32(uid),
 	}
 }
+
+// PoolIDFromPod returns the ID (see VitessShardTabletPool.ID) of the tablet
+// pool to which a vttablet Pod belongs.
+func PoolIDFromPod(pod *corev1.Pod) string {
+	if pool := pod.Labels[planetscalev2.TabletPoolLabel]; pool != "" {
+		return pool
+	}
+	return pod.Labels[planetscalev2.TabletTypeLabel]
+}
+
+// MysqldImageFromPod returns the mysqld image and flavor that a vttablet Pod
+// was created with, or nil if the Pod doesn't run mysqld.
+func MysqldImageFromPod(pod *corev1.Pod) *planetscalev2.MysqldImage {
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if container.Name != mysqldContainerName {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == "MYSQL_FLAVOR" {
+				return planetscalev2.NewMysqldImage(env.Value, container.Image)
+			}
+		}
+	}
+	return nil
+}
+
+// mysqldExporterEnv returns the env vars for mysqld-exporter, which tell it
+// to connect to the local mysqld as the exporter account.
+func mysqldExporterEnv(spec *Spec) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: mysqldExporterPasswordEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: spec.MysqldExporterSecret},
+					Key:                  MysqldExporterSecretKey,
+				},
+			},
+		},
+		{
+			// Kubernetes expands the reference to the password above.
+			Name:  "DATA_SOURCE_NAME",
+			Value: fmt.Sprintf("%s:$(%s)@unix(%s)/", mysqlusers.ExporterUser, mysqldExporterPasswordEnvVar, mysqlSocketPath),
+		},
+	}
+}
+
+// mysqldExporterArgs returns the command-line args for mysqld-exporter,
+// including any user-provided overrides.
+func mysqldExporterArgs(spec *Spec) []string {
+	if spec.MysqldExporter == nil || len(spec.MysqldExporter.ExtraFlags) == 0 {
+		// Keep the original fixed ordering when there are no overrides, so
+		// existing Pods don't get recreated just because we sort the args.
+		return []string{
+			"--config.my-cnf=" + spec.myCnfFilePath(),
+			// The default for `collect.info_schema.tables.databases` is
+			// `*`, which causes new time series to be created for each user
+			// table. This in turn causes scaling issues in Prometheus
+			// memory usage.
+			"--collect.info_schema.tables.databases=sys,_vt",
+		}
+	}
+
+	flags := vitess.Flags{
+		"config.my-cnf":                        spec.myCnfFilePath(),
+		"collect.info_schema.tables.databases": "sys,_vt",
+	}
+	for key, value := range spec.MysqldExporter.ExtraFlags {
+		// mysqld_exporter uses double-dash flags, but we accept either form.
+		key = strings.TrimLeft(key, "-")
+		flags[key] = value
+	}
+	return flags.FormatArgs()
+}
+
+// podAntiAffinity returns the default anti-affinity for the spread policy.
+func (spec *Spec) podAntiAffinity() *corev1.PodAntiAffinity {
+	switch spec.SpreadPolicy {
+	case planetscalev2.NoSpreadPolicy:
+		return nil
+	case planetscalev2.NodeSpreadPolicy, planetscalev2.ZoneSpreadPolicy:
+		return &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: spec.shardLabels(),
+					},
+					TopologyKey: k8s.HostnameLabel,
+				},
+			},
+		}
+	default:
+		antiAffinity := &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					// A Node with no members of the same shard would be ideal.
+					Weight: 2,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: spec.shardLabels(),
+						},
+						TopologyKey: k8s.HostnameLabel,
+					},
+				},
+				{
+					// If that's not possible, a Node that at least has no
+					// members of the exact same pool would be nice.
+					Weight: 1,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: spec.poolLabels(),
+						},
+						TopologyKey: k8s.HostnameLabel,
+					},
+				},
+			},
+		}
+		if spec.SpreadPolicy == planetscalev2.ReplicaNodeSpreadPolicy && spec.Type == planetscalev2.ReplicaPoolType {
+			// Never put two master-eligible replicas of the same shard on
+			// one Node, in any cell, so a single Node failure can't take
+			// out more than one of them.
+			labels := spec.shardLabels()
+			labels[planetscalev2.TabletTypeLabel] = string(planetscalev2.ReplicaPoolType)
+			antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: labels,
+					},
+					TopologyKey: k8s.HostnameLabel,
+				},
+			}
+		}
+		return antiAffinity
+	}
+}
+
+// spreadConstraints returns the topology spread constraints for the spread
+// policy.
+func (spec *Spec) spreadConstraints() []corev1.TopologySpreadConstraint {
+	if spec.SpreadPolicy != planetscalev2.ZoneSpreadPolicy {
+		return nil
+	}
+	// Spread tablets of the same type across pools, since tablets in each
+	// cell's pool may land in any of the cell's zones.
+	labels := spec.shardLabels()
+	labels[planetscalev2.TabletTypeLabel] = spec.Labels[planetscalev2.TabletTypeLabel]
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       k8s.TopologyZoneLabel,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+		},
+	}
+}
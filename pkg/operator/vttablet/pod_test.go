@@ -0,0 +1,174 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestPodAntiAffinity(t *testing.T) {
+	tests := []struct {
+		name         string
+		poolType     planetscalev2.VitessTabletPoolType
+		spreadPolicy planetscalev2.SpreadPolicy
+		wantRequired bool
+		wantReplica  bool
+	}{
+		{name: "default replica", poolType: planetscalev2.ReplicaPoolType},
+		{name: "default rdonly", poolType: planetscalev2.RdonlyPoolType},
+		{name: "replica node replica", poolType: planetscalev2.ReplicaPoolType, spreadPolicy: planetscalev2.ReplicaNodeSpreadPolicy, wantRequired: true, wantReplica: true},
+		{name: "replica node rdonly", poolType: planetscalev2.RdonlyPoolType, spreadPolicy: planetscalev2.ReplicaNodeSpreadPolicy},
+		{name: "node rdonly", poolType: planetscalev2.RdonlyPoolType, spreadPolicy: planetscalev2.NodeSpreadPolicy, wantRequired: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &Spec{
+				Type:         test.poolType,
+				SpreadPolicy: test.spreadPolicy,
+				Labels: map[string]string{
+					planetscalev2.ClusterLabel:    "example",
+					planetscalev2.KeyspaceLabel:   "commerce",
+					planetscalev2.ShardLabel:      "x-x",
+					planetscalev2.CellLabel:       "zone1",
+					planetscalev2.TabletTypeLabel: string(test.poolType),
+				},
+			}
+			required := spec.podAntiAffinity().RequiredDuringSchedulingIgnoredDuringExecution
+			if got := len(required) > 0; got != test.wantRequired {
+				t.Fatalf("podAntiAffinity() required = %v; want required %v", required, test.wantRequired)
+			}
+			if !test.wantRequired {
+				return
+			}
+			labels := required[0].LabelSelector.MatchLabels
+			if _, got := labels[planetscalev2.TabletTypeLabel]; got != test.wantReplica {
+				t.Errorf("podAntiAffinity() required labels = %v; want tablet type %v", labels, test.wantReplica)
+			}
+			if _, ok := labels[planetscalev2.CellLabel]; ok {
+				t.Errorf("podAntiAffinity() required labels = %v; want all cells", labels)
+			}
+		})
+	}
+}
+
+func TestMysqldExporterArgs(t *testing.T) {
+	spec := &Spec{}
+	myCnf := "--config.my-cnf=" + spec.myCnfFilePath()
+
+	tests := []struct {
+		name       string
+		extraFlags map[string]string
+		want       []string
+	}{
+		{
+			name: "defaults",
+			want: []string{myCnf, "--collect.info_schema.tables.databases=sys,_vt"},
+		},
+		{
+			name: "extra flags",
+			extraFlags: map[string]string{
+				"--collect.info_schema.tables.databases": "sys",
+				"collect.perf_schema.eventswaits":        "true",
+			},
+			want: []string{
+				"--collect.info_schema.tables.databases=sys",
+				"--collect.perf_schema.eventswaits=true",
+				myCnf,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &Spec{MysqldExporter: &planetscalev2.MysqldExporterSpec{ExtraFlags: test.extraFlags}}
+			if got := mysqldExporterArgs(spec); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("mysqldExporterArgs() = %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMysqldExporterContainer(t *testing.T) {
+	custom := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}
+
+	tests := []struct {
+		name          string
+		image         string
+		exporter      *planetscalev2.MysqldExporterSpec
+		wantContainer bool
+		wantResources *corev1.ResourceRequirements
+	}{
+		{name: "default", image: "mysqld-exporter", wantContainer: true},
+		{name: "no image", wantContainer: false},
+		{name: "disabled", image: "mysqld-exporter", exporter: &planetscalev2.MysqldExporterSpec{Disabled: true}},
+		{name: "resources", image: "mysqld-exporter", exporter: &planetscalev2.MysqldExporterSpec{Resources: &custom}, wantContainer: true, wantResources: &custom},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &Spec{
+				Images: planetscalev2.VitessKeyspaceImages{
+					Mysqld:         &planetscalev2.MysqldImage{Mysql80Compatible: "mysql:8.0"},
+					MysqldExporter: test.image,
+				},
+				Vttablet:             &planetscalev2.VttabletSpec{},
+				Mysqld:               &planetscalev2.MysqldSpec{},
+				MysqldExporter:       test.exporter,
+				MysqldExporterSecret: "shard-mysqld-exporter",
+			}
+			pod := NewPod(client.ObjectKey{Namespace: "ns", Name: "tablet"}, spec)
+			container := findContainer(pod.Spec.Containers, mysqldExporterContainerName)
+			if got := container != nil; got != test.wantContainer {
+				t.Fatalf("mysqld-exporter container present = %v; want %v", got, test.wantContainer)
+			}
+			if container == nil {
+				return
+			}
+			if test.wantResources != nil && !apiequality.Semantic.DeepEqual(container.Resources, *test.wantResources) {
+				t.Errorf("mysqld-exporter resources = %v; want %v", container.Resources, *test.wantResources)
+			}
+			if test.wantResources == nil && container.Resources.Limits.Memory().Value() != mysqldExporterMemoryLimitBytes {
+				t.Errorf("mysqld-exporter resources = %v; want the defaults", container.Resources)
+			}
+
+			// The exporter connects with its own account, never as vt_dba.
+			wantEnv := []corev1.EnvVar{
+				{
+					Name: "MYSQLD_EXPORTER_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "shard-mysqld-exporter"},
+							Key:                  "password",
+						},
+					},
+				},
+				{Name: "DATA_SOURCE_NAME", Value: "vt_exporter:$(MYSQLD_EXPORTER_PASSWORD)@unix(" + mysqlSocketPath + ")/"},
+			}
+			if !apiequality.Semantic.DeepEqual(container.Env, wantEnv) {
+				t.Errorf("mysqld-exporter env = %v; want %v", container.Env, wantEnv)
+			}
+		})
+	}
+}
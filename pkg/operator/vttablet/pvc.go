@@ -19,12 +19,39 @@ package vttablet
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ExtraVolumeClaim is a PVC that the operator manages for a tablet, besides
+// the one for its data volume.
+type ExtraVolumeClaim struct {
+	// VolumeName is the name of the Pod volume for the claim.
+	VolumeName string
+	// ClaimName is the name of the PVC.
+	ClaimName string
+	// Spec is the spec of the PVC, before defaulting its size.
+	Spec *corev1.PersistentVolumeClaimSpec
+}
+
+// ExtraPVCName returns the name of the PVC for one of a tablet's extra
+// volume claims, given the name of the tablet's Pod.
+func ExtraPVCName(podName, volumeName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, podName, volumeName)
+}
+
 // NewPVC creates a new vttablet PVC from a Spec.
 func NewPVC(key client.ObjectKey, spec *Spec) *corev1.PersistentVolumeClaim {
+	return newPVC(key, spec, spec.DataVolumePVCSpec)
+}
+
+// NewExtraPVC creates a new PVC for one of a tablet's extra volume claims.
+func NewExtraPVC(key client.ObjectKey, spec *Spec, claim *ExtraVolumeClaim) *corev1.PersistentVolumeClaim {
+	return newPVC(key, spec, sizedLikeDataVolume(claim.Spec, spec.DataVolumePVCSpec))
+}
+
+func newPVC(key client.ObjectKey, spec *Spec, pvcSpec *corev1.PersistentVolumeClaimSpec) *corev1.PersistentVolumeClaim {
 	// Store labels in labels obj because we need to add extra label and avoid mutating spec.Labels value
 	labels := map[string]string{}
 	update.Labels(&labels, spec.Labels)
@@ -36,12 +63,22 @@ func NewPVC(key client.ObjectKey, spec *Spec) *corev1.PersistentVolumeClaim {
 			Name:      key.Name,
 			Labels:    labels,
 		},
-		Spec: *spec.DataVolumePVCSpec,
+		Spec: *pvcSpec,
 	}
 }
 
 // UpdatePVCInPlace updates an existing vttablet PVC in-place.
 func UpdatePVCInPlace(obj *corev1.PersistentVolumeClaim, spec *Spec) {
+	updatePVCInPlace(obj, spec, spec.DataVolumePVCSpec)
+}
+
+// UpdateExtraPVCInPlace updates an existing PVC for one of a tablet's extra
+// volume claims in-place.
+func UpdateExtraPVCInPlace(obj *corev1.PersistentVolumeClaim, spec *Spec, claim *ExtraVolumeClaim) {
+	updatePVCInPlace(obj, spec, sizedLikeDataVolume(claim.Spec, spec.DataVolumePVCSpec))
+}
+
+func updatePVCInPlace(obj *corev1.PersistentVolumeClaim, spec *Spec, pvcSpec *corev1.PersistentVolumeClaimSpec) {
 	// Update labels, but ignore existing ones we don't set.
 	update.Labels(&obj.Labels, spec.Labels)
 	// update extra labels
@@ -50,8 +87,45 @@ func UpdatePVCInPlace(obj *corev1.PersistentVolumeClaim, spec *Spec) {
 
 	// The only in-place spec update that's possible is volume expansion.
 	curSize := obj.Spec.Resources.Requests[corev1.ResourceStorage]
-	newSize := spec.DataVolumePVCSpec.Resources.Requests[corev1.ResourceStorage]
+	newSize := pvcSpec.Resources.Requests[corev1.ResourceStorage]
 	if newSize.Cmp(curSize) > 0 {
 		obj.Spec.Resources.Requests[corev1.ResourceStorage] = newSize
 	}
 }
+
+// sizedLikeDataVolume returns pvcSpec, or a copy of it that requests as much
+// storage as the data volume if it doesn't request any itself.
+func sizedLikeDataVolume(pvcSpec, dataVolume *corev1.PersistentVolumeClaimSpec) *corev1.PersistentVolumeClaimSpec {
+	if _, ok := pvcSpec.Resources.Requests[corev1.ResourceStorage]; ok || dataVolume == nil {
+		return pvcSpec
+	}
+	size, ok := dataVolume.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return pvcSpec
+	}
+	pvcSpec = pvcSpec.DeepCopy()
+	if pvcSpec.Resources.Requests == nil {
+		pvcSpec.Resources.Requests = make(corev1.ResourceList, 1)
+	}
+	pvcSpec.Resources.Requests[corev1.ResourceStorage] = size
+	return pvcSpec
+}
+
+// extraVolumes returns the Pod's extra volumes, with generic ephemeral
+// volumes that don't request any storage sized like the data volume.
+func extraVolumes(spec *Spec) []corev1.Volume {
+	volumes := make([]corev1.Volume, 0, len(spec.ExtraVolumes))
+	for i := range spec.ExtraVolumes {
+		vol := &spec.ExtraVolumes[i]
+		if vol.Ephemeral != nil && vol.Ephemeral.VolumeClaimTemplate != nil {
+			pvcSpec := sizedLikeDataVolume(&vol.Ephemeral.VolumeClaimTemplate.Spec, spec.DataVolumePVCSpec)
+			if pvcSpec != &vol.Ephemeral.VolumeClaimTemplate.Spec {
+				// Copy the volume, since it's shared with the pool.
+				vol = vol.DeepCopy()
+				vol.Ephemeral.VolumeClaimTemplate.Spec = *pvcSpec
+			}
+		}
+		volumes = append(volumes, *vol)
+	}
+	return volumes
+}
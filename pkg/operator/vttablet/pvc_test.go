@@ -0,0 +1,76 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestExtraVolumeClaimSize(t *testing.T) {
+	dataVolume := &corev1.PersistentVolumeClaimSpec{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("100Gi")},
+		},
+	}
+	tmpdir := &corev1.PersistentVolumeClaimSpec{}
+	spec := &Spec{
+		DataVolumePVCSpec: dataVolume,
+		ExtraVolumeClaims: []ExtraVolumeClaim{{VolumeName: "tmpdir", ClaimName: "tablet-tmpdir", Spec: tmpdir}},
+		ExtraVolumes: []corev1.Volume{{
+			Name: "scratch",
+			VolumeSource: corev1.VolumeSource{
+				Ephemeral: &corev1.EphemeralVolumeSource{
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{Spec: *tmpdir},
+				},
+			},
+		}},
+	}
+
+	// Claims that don't request any storage are sized like the data volume.
+	pvc := NewExtraPVC(client.ObjectKey{Name: "tablet-tmpdir"}, spec, &spec.ExtraVolumeClaims[0])
+	if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != "100Gi" {
+		t.Errorf("extra PVC size = %v; want 100Gi", got.String())
+	}
+	if got := extraVolumes(spec)[0].Ephemeral.VolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != "100Gi" {
+		t.Errorf("ephemeral volume size = %v; want 100Gi", got.String())
+	}
+	found := false
+	volumes := tabletVolumes.Get(spec)
+	for _, vol := range volumes {
+		if vol.Name == "tmpdir" {
+			found = vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName == "tablet-tmpdir"
+		}
+	}
+	if !found {
+		t.Errorf("tablet volumes = %v; want a volume for the tmpdir PVC", volumes)
+	}
+	// The pool's templates are left alone.
+	if len(tmpdir.Resources.Requests) != 0 || len(spec.ExtraVolumes[0].Ephemeral.VolumeClaimTemplate.Spec.Resources.Requests) != 0 {
+		t.Errorf("sizing a claim changed the pool's template")
+	}
+
+	// Claims that request storage keep it.
+	tmpdir.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+	pvc = NewExtraPVC(client.ObjectKey{Name: "tablet-tmpdir"}, spec, &spec.ExtraVolumeClaims[0])
+	if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != "10Gi" {
+		t.Errorf("extra PVC size = %v; want 10Gi", got.String())
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/lazy"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+func init() {
+	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
+		spec := s.(*Spec)
+		qs := &planetscalev2.VttabletQueryServerSpec{}
+		if spec.Vttablet.QueryServer != nil {
+			qs = spec.Vttablet.QueryServer.DeepCopy()
+		}
+		vttablet := &planetscalev2.VttabletSpec{QueryServer: qs}
+		planetscalev2.DefaultVttabletQueryServerSpec(vttablet)
+
+		// These flags take seconds, and used to be set as integers. Keep
+		// formatting them that way, so tablets aren't restarted needlessly.
+		flags := vitess.Flags{
+			"queryserver-config-max-result-size":  *qs.MaxResultSize,
+			"queryserver-config-query-timeout":    qs.QueryTimeout.Duration.Seconds(),
+			"queryserver-config-pool-size":        *qs.PoolSize,
+			"queryserver-config-stream-pool-size": *qs.StreamPoolSize,
+			"queryserver-config-transaction-cap":  *qs.TransactionCap,
+		}
+		if qs.TransactionTimeout != nil {
+			flags["queryserver-config-transaction-timeout"] = qs.TransactionTimeout.Duration.Seconds()
+		}
+
+		// Invalid settings are left out. The shard controller reports them.
+		hrp := qs.HotRowProtection
+		if hrp == nil || hrp.Mode == planetscalev2.DisabledHotRowProtectionMode || validateHotRowProtection(hrp) != nil {
+			return flags
+		}
+		if hrp.Mode == planetscalev2.DryRunHotRowProtectionMode {
+			flags["enable_hot_row_protection_dry_run"] = true
+		} else {
+			flags["enable_hot_row_protection"] = true
+		}
+		if hrp.MaxQueueSize != nil {
+			flags["hot_row_protection_max_queue_size"] = *hrp.MaxQueueSize
+		}
+		if hrp.MaxGlobalQueueSize != nil {
+			flags["hot_row_protection_max_global_queue_size"] = *hrp.MaxGlobalQueueSize
+		}
+		if hrp.ConcurrentTransactions != nil {
+			flags["hot_row_protection_concurrent_transactions"] = *hrp.ConcurrentTransactions
+		}
+		return flags
+	})
+}
+
+// ValidateQueryServer returns an error describing what's wrong with the
+// query server settings of a tablet pool, if anything.
+func ValidateQueryServer(spec *planetscalev2.VttabletSpec) error {
+	qs := spec.QueryServer
+	if qs == nil {
+		return nil
+	}
+	if qs.QueryTimeout != nil && qs.QueryTimeout.Duration < 0 {
+		return fmt.Errorf("invalid queryServer: queryTimeout must not be negative")
+	}
+	if qs.TransactionTimeout != nil && qs.TransactionTimeout.Duration < 0 {
+		return fmt.Errorf("invalid queryServer: transactionTimeout must not be negative")
+	}
+	if hrp := qs.HotRowProtection; hrp != nil && hrp.Mode != planetscalev2.DisabledHotRowProtectionMode {
+		if err := validateHotRowProtection(hrp); err != nil {
+			return fmt.Errorf("invalid queryServer: %v", err)
+		}
+	}
+	return nil
+}
+
+func validateHotRowProtection(hrp *planetscalev2.VttabletHotRowProtectionSpec) error {
+	// vttablet refuses to start if the global queue is smaller than the
+	// per-row queue, including when one of them is left at the default.
+	queueSize, globalQueueSize := int32(hotRowProtectionMaxQueueSize), int32(hotRowProtectionMaxGlobalQueueSize)
+	if hrp.MaxQueueSize != nil {
+		queueSize = *hrp.MaxQueueSize
+	}
+	if hrp.MaxGlobalQueueSize != nil {
+		globalQueueSize = *hrp.MaxGlobalQueueSize
+	}
+	if globalQueueSize < queueSize {
+		return fmt.Errorf("hotRowProtection.maxGlobalQueueSize (%v) must not be lower than maxQueueSize (%v)", globalQueueSize, queueSize)
+	}
+	return nil
+}
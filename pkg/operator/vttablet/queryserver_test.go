@@ -0,0 +1,67 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// TestQueryServerDefaultFlags checks that the defaults render the same
+// flags the operator used to hard-code, so upgrading the operator doesn't
+// restart tablets.
+func TestQueryServerDefaultFlags(t *testing.T) {
+	spec := &Spec{Vttablet: &planetscalev2.VttabletSpec{}}
+	args := map[string]bool{}
+	for _, arg := range vttabletFlags.Get(spec).FormatArgs() {
+		args[arg] = true
+	}
+	for _, want := range []string{
+		"--queryserver-config-max-result-size=100000",
+		"--queryserver-config-query-timeout=900",
+		"--queryserver-config-pool-size=96",
+		"--queryserver-config-stream-pool-size=96",
+		"--queryserver-config-transaction-cap=300",
+	} {
+		if !args[want] {
+			t.Errorf("vttablet flags don't include %v", want)
+		}
+	}
+}
+
+func TestValidateQueryServer(t *testing.T) {
+	table := []struct {
+		hrp   planetscalev2.VttabletHotRowProtectionSpec
+		valid bool
+	}{
+		{planetscalev2.VttabletHotRowProtectionSpec{Mode: planetscalev2.EnabledHotRowProtectionMode}, true},
+		{planetscalev2.VttabletHotRowProtectionSpec{Mode: planetscalev2.EnabledHotRowProtectionMode, MaxQueueSize: pointer.Int32Ptr(50), MaxGlobalQueueSize: pointer.Int32Ptr(10)}, false},
+		{planetscalev2.VttabletHotRowProtectionSpec{Mode: planetscalev2.DryRunHotRowProtectionMode, MaxGlobalQueueSize: pointer.Int32Ptr(10)}, false},
+		{planetscalev2.VttabletHotRowProtectionSpec{Mode: planetscalev2.DisabledHotRowProtectionMode, MaxGlobalQueueSize: pointer.Int32Ptr(10)}, true},
+	}
+
+	for _, test := range table {
+		hrp := test.hrp
+		err := ValidateQueryServer(&planetscalev2.VttabletSpec{QueryServer: &planetscalev2.VttabletQueryServerSpec{HotRowProtection: &hrp}})
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateQueryServer(%+v) = %v; want valid: %v", test.hrp, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// ResizePodInPlace updates the resources of the containers of a vttablet Pod
+// to match the desired state, so Kubernetes can resize them without
+// recreating the Pod.
+//
+// Nothing is changed if that would change the QoS class of the Pod, which
+// Kubernetes doesn't allow. The Pod is then recreated as usual.
+func ResizePodInPlace(obj *corev1.Pod, spec *Spec) {
+	desired := &corev1.Pod{}
+	UpdatePod(desired, spec)
+
+	resized := obj.DeepCopy()
+	for i := range resized.Spec.Containers {
+		container := &resized.Spec.Containers[i]
+		for j := range desired.Spec.Containers {
+			if desired.Spec.Containers[j].Name == container.Name {
+				update.ResourceRequirements(&container.Resources, &desired.Spec.Containers[j].Resources)
+			}
+		}
+	}
+	if qosClass(resized) != qosClass(obj) {
+		return
+	}
+	obj.Spec.Containers = resized.Spec.Containers
+}
+
+// KeepPodResources undoes the changes UpdatePod made to parts of obj that
+// follow from resources but can't be changed in place, given prev, a copy of
+// obj from before UpdatePod. That way, a Pod that was resized in place isn't
+// recreated just to update them.
+//
+// Those parts are the resources of init containers, which have already run,
+// and the GOMAXPROCS of vttablet, which catch up the next time the Pod is
+// recreated for another reason.
+func KeepPodResources(obj, prev *corev1.Pod) {
+	for i := range obj.Spec.InitContainers {
+		container := &obj.Spec.InitContainers[i]
+		if prevContainer := findContainer(prev.Spec.InitContainers, container.Name); prevContainer != nil {
+			container.Resources = *prevContainer.Resources.DeepCopy()
+		}
+	}
+	for i := range obj.Spec.Containers {
+		container := &obj.Spec.Containers[i]
+		prevContainer := findContainer(prev.Spec.Containers, container.Name)
+		if prevContainer == nil {
+			continue
+		}
+		if prevEnv := findEnv(prevContainer.Env, "GOMAXPROCS"); prevEnv != nil {
+			update.Env(&container.Env, []corev1.EnvVar{*prevEnv})
+			continue
+		}
+		if findEnv(container.Env, "GOMAXPROCS") == nil {
+			continue
+		}
+		env := make([]corev1.EnvVar, 0, len(container.Env)-1)
+		for _, envVar := range container.Env {
+			if envVar.Name != "GOMAXPROCS" {
+				env = append(env, envVar)
+			}
+		}
+		container.Env = env
+	}
+}
+
+func findEnv(env []corev1.EnvVar, name string) *corev1.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			return &env[i]
+		}
+	}
+	return nil
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// qosClass returns the QoS class that Kubernetes assigns to a Pod with the
+// given resources.
+func qosClass(pod *corev1.Pod) corev1.PodQOSClass {
+	containers := append(append([]corev1.Container(nil), pod.Spec.InitContainers...), pod.Spec.Containers...)
+	bestEffort := true
+	guaranteed := true
+	for i := range containers {
+		resources := &containers[i].Resources
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			request, hasRequest := resources.Requests[name]
+			limit, hasLimit := resources.Limits[name]
+			hasRequest = hasRequest && !request.IsZero()
+			hasLimit = hasLimit && !limit.IsZero()
+			if hasRequest || hasLimit {
+				bestEffort = false
+			}
+			if !hasLimit || (hasRequest && request.Cmp(limit) != 0) {
+				guaranteed = false
+			}
+		}
+	}
+	switch {
+	case bestEffort:
+		return corev1.PodQOSBestEffort
+	case guaranteed:
+		return corev1.PodQOSGuaranteed
+	default:
+		return corev1.PodQOSBurstable
+	}
+}
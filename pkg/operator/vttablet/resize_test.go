@@ -0,0 +1,76 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestResizePodInPlace(t *testing.T) {
+	resources := func(cpu string) corev1.ResourceRequirements {
+		list := corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+		return corev1.ResourceRequirements{Requests: list, Limits: list.DeepCopy()}
+	}
+	exporterResources := resources("1")
+	spec := &Spec{
+		Images:         planetscalev2.VitessKeyspaceImages{Mysqld: &planetscalev2.MysqldImage{Mysql80Compatible: "mysql:8.0"}},
+		Vttablet:       &planetscalev2.VttabletSpec{Resources: resources("1")},
+		Mysqld:         &planetscalev2.MysqldSpec{Resources: resources("2")},
+		MysqldExporter: &planetscalev2.MysqldExporterSpec{Resources: &exporterResources},
+	}
+	pod := NewPod(client.ObjectKey{Namespace: "ns", Name: "tablet"}, spec)
+	if got := qosClass(pod); got != corev1.PodQOSGuaranteed {
+		t.Fatalf("qosClass() = %v; want %v", got, corev1.PodQOSGuaranteed)
+	}
+
+	// Only the resources of containers, not those of init containers or
+	// GOMAXPROCS, change in place, and keeping the rest avoids a recreate.
+	spec.Vttablet.Resources = resources("3")
+	resized := pod.DeepCopy()
+	ResizePodInPlace(resized, spec)
+	vttablet := findContainer(resized.Spec.Containers, vttabletContainerName)
+	if cpu := vttablet.Resources.Limits[corev1.ResourceCPU]; cpu.Value() != 3 {
+		t.Errorf("ResizePodInPlace() vttablet CPU limit = %v; want 3", cpu.String())
+	}
+	recreated := resized.DeepCopy()
+	UpdatePod(recreated, spec)
+	if apiequality.Semantic.DeepEqual(recreated, resized) {
+		t.Fatalf("UpdatePod() made no changes after resize; want init container and GOMAXPROCS changes")
+	}
+	KeepPodResources(recreated, resized)
+	if !apiequality.Semantic.DeepEqual(recreated, resized) {
+		t.Errorf("KeepPodResources() left changes that would recreate the Pod")
+	}
+
+	// A resize that would change the QoS class is left to a recreate.
+	spec.Mysqld.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("1")
+	unchanged := pod.DeepCopy()
+	ResizePodInPlace(unchanged, spec)
+	if !apiequality.Semantic.DeepEqual(unchanged, pod) {
+		t.Errorf("ResizePodInPlace() changed resources in a way that changes the QoS class")
+	}
+}
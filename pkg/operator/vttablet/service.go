@@ -78,10 +78,10 @@ func UpdateService(obj *corev1.Service, labels map[string]string) {
 			TargetPort: intstr.FromString(planetscalev2.DefaultGrpcPortName),
 		},
 		{
-			Name:       mysqldExporterPortName,
+			Name:       MysqldExporterPortName,
 			Protocol:   corev1.ProtocolTCP,
 			Port:       mysqldExporterPort,
-			TargetPort: intstr.FromString(mysqldExporterPortName),
+			TargetPort: intstr.FromString(MysqldExporterPortName),
 		},
 	}
 }
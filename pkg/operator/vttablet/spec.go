@@ -22,35 +22,49 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
 )
 
 // Spec specifies all the internal parameters needed to deploy a vttablet instance.
 type Spec struct {
-	Alias                     topodatapb.TabletAlias
-	AliasStr                  string
-	Type                      planetscalev2.VitessTabletPoolType
-	Zone                      string
-	Labels                    map[string]string
-	Images                    planetscalev2.VitessKeyspaceImages
-	ImagePullPolicies         planetscalev2.VitessImagePullPolicies
-	ImagePullSecrets          []corev1.LocalObjectReference
-	Index                     int32
-	KeyRange                  planetscalev2.VitessKeyRange
-	KeyspaceName              string
-	DatabaseName              string
-	Vttablet                  *planetscalev2.VttabletSpec
-	Mysqld                    *planetscalev2.MysqldSpec
-	ExternalDatastore         *planetscalev2.ExternalDatastore
-	DataVolumePVCSpec         *corev1.PersistentVolumeClaimSpec
-	DataVolumePVCName         string
-	GlobalLockserver          planetscalev2.VitessLockserverParams
-	DatabaseInitScriptSecret  planetscalev2.SecretSource
-	Annotations               map[string]string
-	ExtraLabels               map[string]string
-	BackupLocation            *planetscalev2.VitessBackupLocation
-	BackupEngine              planetscalev2.VitessBackupEngine
+	Alias                    topodatapb.TabletAlias
+	AliasStr                 string
+	Type                     planetscalev2.VitessTabletPoolType
+	PoolName                 string
+	Zone                     string
+	Labels                   map[string]string
+	Images                   planetscalev2.VitessKeyspaceImages
+	ImagePullPolicies        planetscalev2.VitessImagePullPolicies
+	ImagePullSecrets         []corev1.LocalObjectReference
+	Index                    int32
+	KeyRange                 planetscalev2.VitessKeyRange
+	KeyspaceName             string
+	DatabaseName             string
+	DurabilityPolicy         string
+	Vttablet                 *planetscalev2.VttabletSpec
+	Mysqld                   *planetscalev2.MysqldSpec
+	ExternalDatastore        *planetscalev2.ExternalDatastore
+	MysqldExporter           *planetscalev2.MysqldExporterSpec
+	MysqldExporterSecret     string
+	DataVolumePVCSpec        *corev1.PersistentVolumeClaimSpec
+	DataVolumePVCName        string
+	ExtraVolumeClaims        []ExtraVolumeClaim
+	GlobalLockserver         planetscalev2.VitessLockserverParams
+	DatabaseInitScriptSecret planetscalev2.SecretSource
+	CredentialsSecret        string
+	CredentialsHash          string
+	Annotations              map[string]string
+	ExtraLabels              map[string]string
+	BackupLocation           *planetscalev2.VitessBackupLocation
+	BackupEngine             planetscalev2.VitessBackupEngine
+	Xtrabackup               *planetscalev2.XtrabackupSpec
+	BackupCompression        *planetscalev2.BackupCompressionSpec
+	// BackupClusterName overrides the cluster name in the backup storage
+	// path, so a standby restores the backups of its source cluster.
+	BackupClusterName         string
 	Affinity                  *corev1.Affinity
 	ExtraEnv                  []corev1.EnvVar
 	ExtraVolumes              []corev1.Volume
@@ -59,6 +73,28 @@ type Spec struct {
 	SidecarContainers         []corev1.Container
 	Tolerations               []corev1.Toleration
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint
+	Networking                *planetscalev2.NetworkingSpec
+	HostNetwork               bool
+	DNSPolicy                 corev1.DNSPolicy
+	DNSConfig                 *corev1.PodDNSConfig
+	SpreadPolicy              planetscalev2.SpreadPolicy
+	NodeSelector              map[string]string
+	ResourceDefaults          *planetscalev2.ResourceDefaultsSpec
+	// ProtectPrimary asks node autoscalers not to remove the Node of the
+	// tablet while it's the primary, which IsPrimary says it last was.
+	ProtectPrimary bool
+	IsPrimary      bool
+	// ScratchSizeLimit caps the ephemeral volume that holds the data of a
+	// scratch tablet, which has no DataVolumePVCSpec.
+	ScratchSizeLimit *resource.Quantity
+}
+
+// backupClusterName returns the cluster name under which backups are stored.
+func (spec *Spec) backupClusterName() string {
+	if spec.BackupClusterName != "" {
+		return spec.BackupClusterName
+	}
+	return spec.Labels[planetscalev2.ClusterLabel]
 }
 
 // localDatabaseName returns the MySQL database name for a tablet Spec in the case of locally managed MySQL.
@@ -69,6 +105,30 @@ func (spec *Spec) localDatabaseName() string {
 	return "vt_" + spec.KeyspaceName
 }
 
+// podHostname returns the hostname of the tablet Pod within the vttablet
+// Service, which is only set if the tablet advertises a DNS name.
+func (spec *Spec) podHostname() string {
+	return names.JoinWithConstraints(names.ServiceConstraints, spec.AliasStr)
+}
+
+// tabletHostname returns the address the tablet advertises in topology.
+// The environment variables it refers to are injected via the Downward API.
+func (spec *Spec) tabletHostname() string {
+	if !spec.Networking.TabletHostnames() {
+		return "$(POD_IP)"
+	}
+	return spec.Hostname("$(POD_NAMESPACE)")
+}
+
+// Hostname returns the stable DNS name of the tablet in the vttablet Service
+// of the given namespace, or "" if the tablet doesn't advertise one.
+func (spec *Spec) Hostname(namespace string) string {
+	if !spec.Networking.TabletHostnames() {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s.%s.svc", spec.podHostname(), ServiceName(spec.Labels[planetscalev2.ClusterLabel]), namespace)
+}
+
 // shardLabels returns only the labels needed to select Pods in the same shard.
 func (spec *Spec) shardLabels() map[string]string {
 	return map[string]string{
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/vt/throttler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/lazy"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+func init() {
+	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
+		spec := s.(*Spec)
+		flags := vitess.Flags{}
+		// Invalid settings are left out. The shard controller reports them.
+		if t := spec.Vttablet.Throttler; t != nil && t.Enabled && validateThrottler(t) == nil {
+			flags.Merge(throttlerFlags(t))
+		}
+		if t := spec.Vttablet.TransactionThrottler; t != nil && t.Enabled {
+			if config, err := txThrottlerConfig(t); err == nil {
+				flags.Merge(vitess.Flags{
+					"enable_tx_throttler": true,
+					"tx_throttler_config": config,
+				})
+				if len(t.HealthCheckCells) > 0 {
+					flags["tx_throttler_healthcheck_cells"] = strings.Join(t.HealthCheckCells, ",")
+				}
+			}
+		}
+		return flags
+	})
+}
+
+// ValidateThrottlers returns an error describing what's wrong with the
+// throttler settings of a tablet pool, if anything.
+func ValidateThrottlers(spec *planetscalev2.VttabletSpec) error {
+	if t := spec.Throttler; t != nil && t.Enabled {
+		if err := validateThrottler(t); err != nil {
+			return fmt.Errorf("invalid throttler: %v", err)
+		}
+	}
+	if t := spec.TransactionThrottler; t != nil && t.Enabled {
+		if _, err := txThrottlerConfig(t); err != nil {
+			return fmt.Errorf("invalid transactionThrottler: %v", err)
+		}
+	}
+	return nil
+}
+
+func validateThrottler(t *planetscalev2.VttabletThrottlerSpec) error {
+	if (t.CustomQuery == "") != (t.CustomQueryThreshold == "") {
+		return fmt.Errorf("customQuery and customQueryThreshold must be set together")
+	}
+	if t.CustomQuery != "" {
+		query := strings.ToLower(strings.Join(strings.Fields(t.CustomQuery), " "))
+		if !strings.HasPrefix(query, "select ") && !strings.HasPrefix(query, "show global ") {
+			return fmt.Errorf("customQuery must be a SELECT or a SHOW GLOBAL query")
+		}
+	}
+	if t.Threshold != nil && t.Threshold.Duration <= 0 {
+		return fmt.Errorf("threshold must be positive")
+	}
+	for _, tabletType := range t.TabletTypes {
+		if tabletType != planetscalev2.ReplicaPoolType && tabletType != planetscalev2.RdonlyPoolType {
+			return fmt.Errorf("tabletTypes may only contain %v and %v", planetscalev2.ReplicaPoolType, planetscalev2.RdonlyPoolType)
+		}
+	}
+	return nil
+}
+
+func throttlerFlags(t *planetscalev2.VttabletThrottlerSpec) vitess.Flags {
+	flags := vitess.Flags{
+		"enable_lag_throttler": true,
+	}
+	if t.Threshold != nil {
+		flags["throttle_threshold"] = t.Threshold.Duration.String()
+	}
+	if t.CustomQuery != "" {
+		flags["throttle_metrics_query"] = t.CustomQuery
+		flags["throttle_metrics_threshold"] = t.CustomQueryThreshold
+	}
+	if len(t.TabletTypes) > 0 {
+		types := make([]string, 0, len(t.TabletTypes))
+		for _, tabletType := range t.TabletTypes {
+			types = append(types, string(tabletType))
+		}
+		flags["throttle_tablet_types"] = strings.Join(types, ",")
+	}
+	if t.CheckAsCheckSelf {
+		flags["throttle_check_as_check_self"] = true
+	}
+	return flags
+}
+
+// txThrottlerConfig returns the value of the tx_throttler_config flag, which
+// is a text-format throttlerdata.Configuration with every field set, since
+// vttablet doesn't fill in defaults for missing fields.
+func txThrottlerConfig(t *planetscalev2.VttabletTransactionThrottlerSpec) (string, error) {
+	config := throttler.DefaultMaxReplicationLagModuleConfig()
+	// This is the default of vttablet, which differs from that of the throttler module.
+	config.MaxReplicationLagSec = 10
+	if t.TargetReplicationLag != nil {
+		config.TargetReplicationLagSec = int64(t.TargetReplicationLag.Duration / time.Second)
+	}
+	if t.MaxReplicationLag != nil {
+		config.MaxReplicationLagSec = int64(t.MaxReplicationLag.Duration / time.Second)
+	}
+	if err := config.Verify(); err != nil {
+		return "", err
+	}
+
+	// We don't use prototext, since its output deliberately varies between
+	// builds, which would restart every tablet when the operator is upgraded.
+	msg := config.Configuration.ProtoReflect()
+	descriptors := msg.Descriptor().Fields()
+	fields := make([]string, 0, descriptors.Len())
+	for i := 0; i < descriptors.Len(); i++ {
+		fd := descriptors.Get(i)
+		if msg.Has(fd) {
+			fields = append(fields, fmt.Sprintf("%s:%v", fd.Name(), msg.Get(fd).Interface()))
+		}
+	}
+	return strings.Join(fields, " "), nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	throttlerdatapb "vitess.io/vitess/go/vt/proto/throttlerdata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestTxThrottlerConfig(t *testing.T) {
+	spec := &planetscalev2.VttabletTransactionThrottlerSpec{
+		Enabled:              true,
+		TargetReplicationLag: &metav1.Duration{Duration: 5 * time.Second},
+		MaxReplicationLag:    &metav1.Duration{Duration: 30 * time.Second},
+	}
+	text, err := txThrottlerConfig(spec)
+	if err != nil {
+		t.Fatalf("txThrottlerConfig() error: %v", err)
+	}
+
+	// vttablet must be able to parse it, and get a complete config.
+	config := &throttlerdatapb.Configuration{}
+	if err := prototext.Unmarshal([]byte(text), config); err != nil {
+		t.Fatalf("can't parse txThrottlerConfig() = %q: %v", text, err)
+	}
+	if config.TargetReplicationLagSec != 5 || config.MaxReplicationLagSec != 30 {
+		t.Errorf("txThrottlerConfig() = %q; want target 5s and max 30s", text)
+	}
+	if config.InitialRate == 0 || config.EmergencyDecrease == 0 {
+		t.Errorf("txThrottlerConfig() = %q; want defaults for other fields", text)
+	}
+
+	spec.MaxReplicationLag.Duration = time.Second
+	if _, err := txThrottlerConfig(spec); err == nil {
+		t.Errorf("txThrottlerConfig() with max lag below target = nil error; want error")
+	}
+}
+
+func TestValidateThrottlers(t *testing.T) {
+	table := []struct {
+		throttler planetscalev2.VttabletThrottlerSpec
+		valid     bool
+	}{
+		{planetscalev2.VttabletThrottlerSpec{Enabled: true}, true},
+		{planetscalev2.VttabletThrottlerSpec{Enabled: true, CustomQuery: "select 1", CustomQueryThreshold: "2"}, true},
+		{planetscalev2.VttabletThrottlerSpec{Enabled: true, CustomQuery: "SHOW GLOBAL STATUS LIKE 'Threads_running'", CustomQueryThreshold: "100"}, true},
+		{planetscalev2.VttabletThrottlerSpec{Enabled: true, CustomQuery: "select 1"}, false},
+		{planetscalev2.VttabletThrottlerSpec{Enabled: true, CustomQuery: "delete from t", CustomQueryThreshold: "1"}, false},
+		{planetscalev2.VttabletThrottlerSpec{Enabled: true, TabletTypes: []planetscalev2.VitessTabletPoolType{planetscalev2.ExternalReplicaPoolType}}, false},
+		{planetscalev2.VttabletThrottlerSpec{Enabled: false, CustomQuery: "delete from t"}, true},
+	}
+
+	for _, test := range table {
+		throttler := test.throttler
+		err := ValidateThrottlers(&planetscalev2.VttabletSpec{Throttler: &throttler})
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateThrottlers(%+v) = %v; want valid: %v", test.throttler, err, test.valid)
+		}
+	}
+}
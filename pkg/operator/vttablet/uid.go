@@ -28,7 +28,8 @@ import (
 UID deterministically generates a 32-bit unsigned integer that should uniquely
 identify a given tablet within a Vitess cluster.
 
-The tablet's identity is defined as the tuple (cell,keyspace,shard,pool,index).
+The tablet's identity is defined as the tuple (cell,keyspace,shard,pool,index),
+where the pool is identified by its name, or by its type if it's unnamed.
 Any such tuple must map to only one uint32 value (the same tuple always results
 in the same integer), and there must be a negligible probability of accidental
 collisions within a given Vitess cluster.
@@ -43,11 +44,12 @@ lead time to develop a smarter way to handle tablet identity and MySQL server
 IDs in Vitess itself.
 
 WARNING: DO NOT change the behavior of this function, as that may result in
-         the deletion and recreation of all tablets.
+
+	the deletion and recreation of all tablets.
 */
-func UID(cellName, keyspaceName string, shardKeyRange planetscalev2.VitessKeyRange, tabletPoolType planetscalev2.VitessTabletPoolType, tabletIndex uint32) uint32 {
+func UID(cellName, keyspaceName string, shardKeyRange planetscalev2.VitessKeyRange, tabletPoolID string, tabletIndex uint32) uint32 {
 	h := md5.New()
-	fmt.Fprintln(h, cellName, keyspaceName, shardKeyRange.String(), string(tabletPoolType), tabletIndex)
+	fmt.Fprintln(h, cellName, keyspaceName, shardKeyRange.String(), tabletPoolID, tabletIndex)
 	sum := h.Sum(nil)
 	return binary.BigEndian.Uint32(sum[:4])
 }
@@ -27,14 +27,14 @@ func TestUIDHash(t *testing.T) {
 	cell := "cell"
 	keyspace := "keyspace"
 	keyRange := planetscalev2.VitessKeyRange{Start: "10", End: "20"}
-	tabletType := planetscalev2.ReplicaPoolType
+	tabletPool := string(planetscalev2.ReplicaPoolType)
 	tabletIndex := uint32(1)
 
 	// DO NOT CHANGE THIS VALUE!
 	// This is intentionally a change-detection test. If it breaks, you messed up.
 	want := uint32(3376898362)
 
-	if got := UID(cell, keyspace, keyRange, tabletType, tabletIndex); got != want {
+	if got := UID(cell, keyspace, keyRange, tabletPool, tabletIndex); got != want {
 		t.Fatalf("UID() = %v, want %v", got, want)
 	}
 }
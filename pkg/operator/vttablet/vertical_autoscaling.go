@@ -0,0 +1,72 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// AutoscaledContainers are the containers of tablet Pods that vertical
+// autoscaling makes recommendations for.
+var AutoscaledContainers = []string{vttabletContainerName, mysqldContainerName}
+
+// SetRequests sets the requests of one of the AutoscaledContainers of a
+// tablet. Limits are scaled by the same factor as the requests, so the ratio
+// between them is kept, or else raised to the requests if they'd be lower.
+//
+// The parts of the spec that are shared with other tablets are copied before
+// they're changed.
+func SetRequests(spec *Spec, containerName string, requests corev1.ResourceList) {
+	switch containerName {
+	case vttabletContainerName:
+		vttablet := *spec.Vttablet
+		vttablet.Resources = scaledResources(&spec.Vttablet.Resources, requests)
+		spec.Vttablet = &vttablet
+	case mysqldContainerName:
+		if spec.Mysqld == nil {
+			return
+		}
+		mysqld := *spec.Mysqld
+		mysqld.Resources = scaledResources(&spec.Mysqld.Resources, requests)
+		spec.Mysqld = &mysqld
+	}
+}
+
+func scaledResources(resources *corev1.ResourceRequirements, requests corev1.ResourceList) corev1.ResourceRequirements {
+	scaled := *resources.DeepCopy()
+	for name, request := range requests {
+		if scaled.Requests == nil {
+			scaled.Requests = make(corev1.ResourceList, len(requests))
+		}
+		prevRequest, hadRequest := scaled.Requests[name]
+		scaled.Requests[name] = request
+
+		limit, ok := scaled.Limits[name]
+		if !ok {
+			continue
+		}
+		if hadRequest && !prevRequest.IsZero() {
+			limit = *resource.NewMilliQuantity(int64(float64(limit.MilliValue())*float64(request.MilliValue())/float64(prevRequest.MilliValue())), limit.Format)
+		}
+		if limit.Cmp(request) < 0 {
+			limit = request
+		}
+		scaled.Limits[name] = limit
+	}
+	return scaled
+}
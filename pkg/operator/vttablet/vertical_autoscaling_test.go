@@ -0,0 +1,100 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestSetRequests(t *testing.T) {
+	list := func(cpu, memory string) corev1.ResourceList {
+		l := corev1.ResourceList{}
+		if cpu != "" {
+			l[corev1.ResourceCPU] = resource.MustParse(cpu)
+		}
+		if memory != "" {
+			l[corev1.ResourceMemory] = resource.MustParse(memory)
+		}
+		return l
+	}
+
+	table := []struct {
+		name      string
+		resources corev1.ResourceRequirements
+		requests  corev1.ResourceList
+		want      corev1.ResourceRequirements
+	}{
+		{
+			name:      "requests only",
+			resources: corev1.ResourceRequirements{Requests: list("1", "1Gi")},
+			requests:  list("500m", "2Gi"),
+			want:      corev1.ResourceRequirements{Requests: list("500m", "2Gi")},
+		},
+		{
+			name:     "no resources",
+			requests: list("500m", "2Gi"),
+			want:     corev1.ResourceRequirements{Requests: list("500m", "2Gi")},
+		},
+		{
+			name:      "limits keep their ratio to requests",
+			resources: corev1.ResourceRequirements{Requests: list("1", "1Gi"), Limits: list("2", "1Gi")},
+			requests:  list("2", "4Gi"),
+			want:      corev1.ResourceRequirements{Requests: list("2", "4Gi"), Limits: list("4", "4Gi")},
+		},
+		{
+			name:      "limits without requests are raised",
+			resources: corev1.ResourceRequirements{Limits: list("1", "8Gi")},
+			requests:  list("2", "4Gi"),
+			want:      corev1.ResourceRequirements{Requests: list("2", "4Gi"), Limits: list("2", "8Gi")},
+		},
+		{
+			name:      "only recommended resources change",
+			resources: corev1.ResourceRequirements{Requests: list("1", "1Gi"), Limits: list("1", "1Gi")},
+			requests:  list("", "2Gi"),
+			want:      corev1.ResourceRequirements{Requests: list("1", "2Gi"), Limits: list("1", "2Gi")},
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			vttablet := &planetscalev2.VttabletSpec{Resources: test.resources}
+			mysqld := &planetscalev2.MysqldSpec{Resources: test.resources}
+			spec := &Spec{Vttablet: vttablet, Mysqld: mysqld}
+
+			for _, containerName := range AutoscaledContainers {
+				SetRequests(spec, containerName, test.requests)
+			}
+
+			if !apiequality.Semantic.DeepEqual(spec.Vttablet.Resources, test.want) {
+				t.Errorf("vttablet resources = %v; want %v", spec.Vttablet.Resources, test.want)
+			}
+			if !apiequality.Semantic.DeepEqual(spec.Mysqld.Resources, test.want) {
+				t.Errorf("mysqld resources = %v; want %v", spec.Mysqld.Resources, test.want)
+			}
+			// The pool's specs, which other tablets share, are left alone.
+			if !apiequality.Semantic.DeepEqual(vttablet.Resources, test.resources) || !apiequality.Semantic.DeepEqual(mysqld.Resources, test.resources) {
+				t.Errorf("SetRequests() changed the shared specs")
+			}
+		})
+	}
+}
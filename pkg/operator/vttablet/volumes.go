@@ -41,6 +41,23 @@ func init() {
 			},
 		}
 	})
+	// Add the extra volume claims, which are mounted through ExtraVolumeMounts.
+	tabletVolumes.Add(func(s lazy.Spec) []corev1.Volume {
+		spec := s.(*Spec)
+		volumes := make([]corev1.Volume, 0, len(spec.ExtraVolumeClaims))
+		for i := range spec.ExtraVolumeClaims {
+			claim := &spec.ExtraVolumeClaims[i]
+			volumes = append(volumes, corev1.Volume{
+				Name: claim.VolumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: claim.ClaimName,
+					},
+				},
+			})
+		}
+		return volumes
+	})
 	// Note that we mount a subpath of the main data volume as vtdataroot.
 	// This allows us to store other persistent data in the main PVC
 	// without having to attach a second PVC.
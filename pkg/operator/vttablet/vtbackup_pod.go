@@ -72,6 +72,26 @@ type BackupSpec struct {
 	// Even if a backup is past the MinRetentionTime, it will not be deleted if
 	// doing so would take the total number of backups below MinRetentionCount.
 	MinRetentionCount int
+
+	// Vtbackup optionally overrides how the Pod is shaped and scheduled,
+	// and how vtbackup is tuned.
+	Vtbackup *planetscalev2.VtbackupSpec
+}
+
+// resources returns the compute resources of the vtbackup container.
+func (backupSpec *BackupSpec) resources() *corev1.ResourceRequirements {
+	if v := backupSpec.Vtbackup; v != nil && (len(v.Resources.Requests) > 0 || len(v.Resources.Limits) > 0) {
+		return &v.Resources
+	}
+	return &backupSpec.TabletSpec.Mysqld.Resources
+}
+
+// concurrency returns how many files vtbackup should copy at once.
+func (backupSpec *BackupSpec) concurrency() int {
+	if v := backupSpec.Vtbackup; v != nil && v.Concurrency != nil {
+		return int(*v.Concurrency)
+	}
+	return vtbackupConcurrency
 }
 
 // BackupPodName returns the name of the Pod for a periodic vtbackup job.
@@ -130,7 +150,22 @@ func NewBackupPod(key client.ObjectKey, backupSpec *BackupSpec) *corev1.Pod {
 
 	var containerResources corev1.ResourceRequirements
 	// Make a copy of Resources since it contains pointers.
-	update.ResourceRequirements(&containerResources, &tabletSpec.Mysqld.Resources)
+	update.ResourceRequirements(&containerResources, backupSpec.resources())
+
+	affinity := tabletSpec.Affinity
+	tolerations := tabletSpec.Tolerations
+	nodeSelector := tabletSpec.NodeSelector
+	if v := backupSpec.Vtbackup; v != nil {
+		if v.Affinity != nil {
+			affinity = v.Affinity
+		}
+		if v.Tolerations != nil {
+			tolerations = v.Tolerations
+		}
+		if v.NodeSelector != nil {
+			nodeSelector = v.NodeSelector
+		}
+	}
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -144,15 +179,16 @@ func NewBackupPod(key client.ObjectKey, backupSpec *BackupSpec) *corev1.Pod {
 			RestartPolicy:    corev1.RestartPolicyOnFailure,
 			Volumes:          tabletVolumes.Get(tabletSpec),
 			SecurityContext:  podSecurityContext,
-			Affinity:         tabletSpec.Affinity,
-			Tolerations:      tabletSpec.Tolerations,
+			NodeSelector:     nodeSelector,
+			Affinity:         affinity,
+			Tolerations:      tolerations,
 			InitContainers: []corev1.Container{
 				{
 					Name:            "init-vt-root",
 					SecurityContext: securityContext,
 					// We only use the vtbackup image to steal the vtbackup binary.
 					// When we actually run it, we run inside the mysqld image.
-					Image:           tabletSpec.Images.Vtbackup,
+					Image:           tabletSpec.Images.InitImage(tabletSpec.Images.Vtbackup),
 					ImagePullPolicy: tabletSpec.ImagePullPolicies.Vtbackup,
 					VolumeMounts: []corev1.VolumeMount{
 						{Name: vtRootVolumeName, MountPath: "/mnt/vt"},
@@ -187,5 +223,6 @@ func NewBackupPod(key client.ObjectKey, backupSpec *BackupSpec) *corev1.Pod {
 
 	update.PodContainers(&pod.Spec.InitContainers, backupSpec.TabletSpec.InitContainers)
 	update.PodContainers(&pod.Spec.Containers, backupSpec.TabletSpec.SidecarContainers)
+	update.PodTemplateResourceDefaults(&pod.Spec, tabletSpec.ResourceDefaults)
 	return pod
 }
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestNewBackupPodVtbackupSpec(t *testing.T) {
+	parallelism := int32(3)
+	concurrency := int32(2)
+	backupSpec := &BackupSpec{
+		TabletSpec: &Spec{
+			Images: planetscalev2.VitessKeyspaceImages{Mysqld: &planetscalev2.MysqldImage{Mysql80Compatible: "mysql:8.0"}},
+			Mysqld: &planetscalev2.MysqldSpec{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}},
+			BackupLocation: &planetscalev2.VitessBackupLocation{},
+			BackupEngine:   planetscalev2.VitessBackupEngineXtraBackup,
+			Tolerations:    []corev1.Toleration{{Key: "tablet"}},
+		},
+		Vtbackup: &planetscalev2.VtbackupSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			},
+			NodeSelector:          map[string]string{"pool": "backups"},
+			Concurrency:           &concurrency,
+			XtrabackupParallelism: &parallelism,
+			Timeout:               &metav1.Duration{Duration: 6 * time.Hour},
+		},
+	}
+
+	pod := NewBackupPod(client.ObjectKey{Namespace: "ns", Name: "backup"}, backupSpec)
+	container := pod.Spec.Containers[0]
+	if cpu := container.Resources.Requests[corev1.ResourceCPU]; cpu.Value() != 4 {
+		t.Errorf("vtbackup CPU request = %v; want 4", cpu.String())
+	}
+	if pod.Spec.NodeSelector["pool"] != "backups" {
+		t.Errorf("nodeSelector = %v; want pool=backups", pod.Spec.NodeSelector)
+	}
+	if len(pod.Spec.Tolerations) != 1 || pod.Spec.Tolerations[0].Key != "tablet" {
+		t.Errorf("tolerations = %v; want the tablet pool's", pod.Spec.Tolerations)
+	}
+
+	flags := vtbackupFlags.Get(backupSpec)
+	want := map[string]interface{}{
+		"concurrency":             2,
+		"xtrabackup_backup_flags": "--parallel=3",
+		"xtrabackup_stripes":      xtrabackupStripeCount,
+		"timeout":                 6 * time.Hour,
+	}
+	for name, value := range want {
+		if flags[name] != value {
+			t.Errorf("flag %v = %v; want %v", name, flags[name], value)
+		}
+	}
+}
+
+func TestNewBackupPodServiceMesh(t *testing.T) {
+	backupSpec := &BackupSpec{
+		TabletSpec: &Spec{
+			Images:         planetscalev2.VitessKeyspaceImages{Mysqld: &planetscalev2.MysqldImage{Mysql80Compatible: "mysql:8.0"}},
+			Mysqld:         &planetscalev2.MysqldSpec{},
+			BackupLocation: &planetscalev2.VitessBackupLocation{},
+			Networking:     &planetscalev2.NetworkingSpec{ServiceMesh: planetscalev2.IstioServiceMesh},
+		},
+	}
+
+	pod := NewBackupPod(client.ObjectKey{Namespace: "ns", Name: "backup"}, backupSpec)
+	// The proxy must run as a native sidecar, or the Pod never completes.
+	if got := pod.Annotations["sidecar.istio.io/nativeSidecar"]; got != "true" {
+		t.Errorf("nativeSidecar annotation = %q; want %q", got, "true")
+	}
+	if got := pod.Annotations["traffic.sidecar.istio.io/excludeOutboundPorts"]; got != "3306" {
+		t.Errorf("excludeOutboundPorts annotation = %q; want %q", got, "3306")
+	}
+
+	backupSpec.TabletSpec.Networking = nil
+	pod = NewBackupPod(client.ObjectKey{Namespace: "ns", Name: "backup"}, backupSpec)
+	if _, ok := pod.Annotations["sidecar.istio.io/nativeSidecar"]; ok {
+		t.Errorf("nativeSidecar annotation set without a service mesh")
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package webclient fetches JSON from the web ports of Vitess components.
+
+Requests are time-bounded, and responses, including failures, are cached for
+a while, so a reconcile doesn't block on slow or unreachable Pods, and
+doesn't hit every Pod again on every pass.
+*/
+package webclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// DefaultTimeout is how long to wait for a Vitess component to answer.
+	DefaultTimeout = 2 * time.Second
+	// maxBodySize is the most we read of a response. vtgate's /debug/vars
+	// can be large with many keyspaces, but not this large.
+	maxBodySize = 16 << 20
+)
+
+// Default is the client that controllers share, so a response fetched by one
+// can be reused by another.
+var Default = New(nil)
+
+// Client fetches JSON from Vitess components and caches the responses.
+type Client struct {
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*response
+}
+
+// response is a cached response, or the error we got instead.
+type response struct {
+	fetched    time.Time
+	statusCode int
+	body       []byte
+	err        error
+}
+
+// New returns a Client that uses the given HTTP client, or a default client
+// with DefaultTimeout if it's nil.
+func New(client *http.Client) *Client {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Client{
+		client: client,
+		now:    time.Now,
+		cache:  map[string]*response{},
+	}
+}
+
+// URL returns the URL of a path on the web port of a Vitess component's Pod.
+func URL(podIP, path string) string {
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(podIP, strconv.Itoa(planetscalev2.DefaultWebPort)), path)
+}
+
+/*
+GetJSON decodes the JSON response to a GET of the URL into v, and returns
+the HTTP status code. The body is decoded whatever the status code is, so
+callers must check it.
+
+If the URL was fetched less than maxAge ago, the result of that is returned
+instead, including any error. A maxAge of zero always fetches the URL again.
+*/
+func (c *Client) GetJSON(ctx context.Context, url string, maxAge time.Duration, v interface{}) (int, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	resp := c.cache[url]
+	c.mu.Unlock()
+
+	if resp == nil || now.Sub(resp.fetched) >= maxAge {
+		resp = c.fetch(ctx, url)
+		resp.fetched = now
+
+		c.mu.Lock()
+		// Forget responses that no one would use anymore, such as those of
+		// Pods that are gone.
+		for key, cached := range c.cache {
+			if now.Sub(cached.fetched) > time.Hour {
+				delete(c.cache, key)
+			}
+		}
+		c.cache[url] = resp
+		c.mu.Unlock()
+	}
+
+	if resp.err != nil {
+		return 0, resp.err
+	}
+	if err := json.Unmarshal(resp.body, v); err != nil {
+		return resp.statusCode, fmt.Errorf("can't parse response of %v (HTTP status %v): %v", url, resp.statusCode, err)
+	}
+	return resp.statusCode, nil
+}
+
+func (c *Client) fetch(ctx context.Context, url string) *response {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return &response{err: err}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return &response{err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return &response{err: fmt.Errorf("can't read response of %v: %v", url, err)}
+	}
+	return &response{statusCode: resp.StatusCode, body: body}
+}
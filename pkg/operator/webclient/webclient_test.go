@@ -0,0 +1,105 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetJSON(t *testing.T) {
+	ctx := context.Background()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/throttled" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+		w.Write([]byte(`{"Value": 42}`))
+	}))
+	defer server.Close()
+
+	now := time.Unix(1000, 0)
+	c := New(nil)
+	c.now = func() time.Time { return now }
+
+	get := func(path string, maxAge time.Duration) (int, int64) {
+		t.Helper()
+		var v struct{ Value int64 }
+		statusCode, err := c.GetJSON(ctx, server.URL+path, maxAge, &v)
+		if err != nil {
+			t.Fatalf("GetJSON(%v) error: %v", path, err)
+		}
+		return statusCode, v.Value
+	}
+
+	if statusCode, value := get("/vars", time.Minute); statusCode != http.StatusOK || value != 42 {
+		t.Errorf("GetJSON() = %v, %v; want %v, 42", statusCode, value, http.StatusOK)
+	}
+	// A response fetched less than maxAge ago is reused.
+	now = now.Add(30 * time.Second)
+	get("/vars", time.Minute)
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests = %v; want 1", got)
+	}
+	// An older one, or one with maxAge 0, isn't.
+	get("/vars", 10*time.Second)
+	get("/vars", 0)
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("requests = %v; want 3", got)
+	}
+	// The body is decoded whatever the status code.
+	if statusCode, value := get("/throttled", time.Minute); statusCode != http.StatusTooManyRequests || value != 42 {
+		t.Errorf("GetJSON() = %v, %v; want %v, 42", statusCode, value, http.StatusTooManyRequests)
+	}
+}
+
+func TestGetJSONError(t *testing.T) {
+	ctx := context.Background()
+	var requests int32
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	now := time.Unix(1000, 0)
+	c := New(&http.Client{Timeout: 50 * time.Millisecond})
+	c.now = func() time.Time { return now }
+
+	var v struct{}
+	if _, err := c.GetJSON(ctx, server.URL, time.Minute, &v); err == nil {
+		t.Fatalf("GetJSON() error = nil; want timeout")
+	}
+	// Failures are cached too, so a hanging Pod doesn't slow down every
+	// reconcile.
+	if _, err := c.GetJSON(ctx, server.URL, time.Minute, &v); err == nil {
+		t.Errorf("GetJSON() error = nil; want cached timeout")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests = %v; want 1", got)
+	}
+}
@@ -0,0 +1,121 @@
+#!/bin/bash
+
+source ./tools/test.env
+source ./test/endtoend/utils.sh
+
+# setFaults replaces the faults injected into the operator.
+# $1: fault configuration, as JSON
+function setFaults() {
+  out=$(curl -s -f -X PUT --data "$1" localhost:8383/debug/faults)
+  if [ $? -ne 0 ]; then
+    echo "Could not set faults to $1"
+    exit 1
+  fi
+  echo "Injecting faults: $out"
+}
+
+# clearFaults stops injecting faults into the operator.
+function clearFaults() {
+  curl -s -f -X DELETE localhost:8383/debug/faults > /dev/null
+  if [ $? -ne 0 ]; then
+    echo "Could not clear faults"
+    exit 1
+  fi
+}
+
+# readyTablets prints the number of tablets that the commerce shard reports as ready.
+function readyTablets() {
+  shard=$(kubectl get vitessshards -o name | grep commerce)
+  kubectl get "$shard" -o jsonpath='{.status.tablets.*.ready}' | tr ' ' '\n' | grep -c True
+}
+
+# waitForReadyTablets waits until the commerce shard reports the given number of ready tablets.
+# $1: number of ready tablets
+function waitForReadyTablets() {
+  for i in {1..300} ; do
+    out=$(readyTablets)
+    if [ "$out" == "$1" ]; then
+      echo "Shard reports $1 ready tablets"
+      return
+    fi
+    sleep 1
+  done
+  echo "ERROR: shard reports $out ready tablets; want $1"
+  exit 1
+}
+
+function verifyFailedCreate() {
+  echo "Failing the creation of tablet Pods"
+  setFaults '{"failCreate": [{"kind": "Pod", "name": "^example-vttablet-"}]}'
+
+  tablet=$(kubectl get pods --no-headers -o custom-columns=":metadata.name" -l "planetscale.com/component=vttablet,planetscale.com/tablet-type=replica" | head -n 1)
+  echo "Deleting tablet Pod $tablet"
+  kubectl delete pod "$tablet"
+  sleep 60
+  count=$(kubectl get pods | grep -E "example-vttablet-zone1(.*)" | wc -l)
+  if [ "$count" -ne 2 ]; then
+    echo "Tablet Pod was recreated even though its creation should fail"
+    exit 1
+  fi
+  kubectl get events | grep "CreateFailed" | grep "injected fault" > /dev/null 2>&1
+  if [ $? -ne 0 ]; then
+    echo "Could not find CreateFailed event for the injected fault"
+    exit 1
+  fi
+
+  echo "Clearing faults"
+  clearFaults
+  checkPodStatusWithTimeout "example-vttablet-zone1(.*)3/3(.*)Running(.*)" 3
+}
+
+function verifyUnhealthyTablet() {
+  waitForReadyTablets 3
+
+  tablet=$(kubectl get pods --no-headers -o custom-columns=":metadata.name" -l "planetscale.com/component=vttablet,planetscale.com/tablet-type=replica" | head -n 1)
+  echo "Treating tablet Pod $tablet as unhealthy"
+  setFaults "{\"unhealthyPods\": [\"^$tablet\$\"]}"
+  waitForReadyTablets 2
+
+  echo "Clearing faults"
+  clearFaults
+  waitForReadyTablets 3
+}
+
+function verifySlowTopo() {
+  echo "Delaying connections to the topology server"
+  setFaults '{"topoDelay": "5s"}'
+  sleep 30
+  # The operator must keep serving while the topology server is slow.
+  checkPodStatusWithTimeout "vitess-operator(.*)1/1(.*)Running(.*)"
+  waitForReadyTablets 3
+
+  echo "Clearing faults"
+  clearFaults
+}
+
+# Test setup
+echo "Building the docker image with fault injection"
+docker build -f build/Dockerfile.release --build-arg GO_BUILD_TAGS=faults -t vitess-operator-pr:latest .
+echo "Creating Kind cluster"
+kind create cluster --wait 30s --name kind-${BUILDKITE_BUILD_ID}
+echo "Loading docker image into Kind cluster"
+kind load docker-image vitess-operator-pr:latest --name kind-${BUILDKITE_BUILD_ID}
+
+cd "$PWD/test/endtoend/operator"
+killall kubectl
+setupKubectlAccessForCI
+
+get_started "operator-latest.yaml" "101_initial_cluster.yaml"
+checkSemiSyncSetup
+
+echo "Port-forwarding the operator's metrics server"
+kubectl port-forward deployment/vitess-operator 8383:8383 > /dev/null 2>&1 &
+sleep 5
+
+verifyFailedCreate
+verifyUnhealthyTablet
+verifySlowTopo
+
+# Teardown
+echo "Deleting Kind cluster. This also deletes the volume associated with it"
+kind delete cluster --name kind-${BUILDKITE_BUILD_ID}
@@ -156,6 +156,8 @@ func testMain(tests func() int) error {
 		"service_account.yaml",
 		"role.yaml",
 		"role_binding.yaml",
+		"cluster_role.yaml",
+		"cluster_role_binding.yaml",
 		"priority.yaml",
 		"crds/",
 	}
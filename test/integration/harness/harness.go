@@ -0,0 +1,225 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package harness runs individual controllers against a test kube-apiserver,
+started with controller-runtime's envtest, and in-memory Vitess topology
+servers. That lets tests exercise controller behavior that depends on the
+state of Vitess, like primary checks and topology cleanup, without a real
+cluster.
+
+Unlike the framework package, which runs the whole operator in one shared
+namespace, each Fixture runs only the controllers a test asks for, in its own
+namespace.
+*/
+package harness
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"planetscale.dev/vitess-operator/pkg/operator/controllermanager"
+)
+
+const (
+	// binaryAssetsDir is where tools/get-kube-binaries.sh installs
+	// kube-apiserver and etcd, relative to the working directory of
+	// integration test binaries. KUBEBUILDER_ASSETS takes precedence.
+	binaryAssetsDir = "../../../tools/_bin"
+	// crdDir is where the operator's CRDs are.
+	crdDir = "../../../deploy/crds"
+
+	defaultWaitTimeout  = 30 * time.Second
+	defaultWaitInterval = 250 * time.Millisecond
+)
+
+var (
+	config *rest.Config
+	scheme *runtime.Scheme
+
+	// namespaces counts the namespaces created for fixtures.
+	namespaces int32
+)
+
+// TestMain starts a kube-apiserver with the operator's CRDs installed, runs
+// the tests, and then stops the kube-apiserver. Call it from the TestMain of
+// each test package that uses a Fixture.
+func TestMain(m *testing.M) {
+	code, err := testMain(m)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+func testMain(m *testing.M) (int, error) {
+	// Resync often, so periodic checks can be tested without waiting long.
+	flag.Set("vitesscluster_resync_period", "5s")
+	flag.Set("vitesscell_resync_period", "5s")
+	flag.Set("vitesskeyspace_resync_period", "5s")
+	flag.Set("vitessshard_resync_period", "5s")
+
+	var err error
+	scheme, err = controllermanager.NewScheme()
+	if err != nil {
+		return 0, fmt.Errorf("cannot create Scheme: %v", err)
+	}
+
+	env := &envtest.Environment{
+		Scheme:                scheme,
+		CRDDirectoryPaths:     []string{crdDir},
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: binaryAssetsDir,
+	}
+	config, err = env.Start()
+	if err != nil {
+		return 0, fmt.Errorf("cannot start kube-apiserver (see tools/get-kube-binaries.sh): %v", err)
+	}
+	defer env.Stop()
+
+	return m.Run(), nil
+}
+
+// Fixture runs a set of controllers in a namespace of its own for a test.
+type Fixture struct {
+	*testing.T
+	ctx context.Context
+
+	client    client.Client
+	namespace string
+
+	topos int32
+}
+
+// NewFixture creates a namespace for the test, and runs the given
+// controllers for it until the test finishes. The controllers are added to
+// the manager with the same functions the operator uses, like
+// vitessshard.Add.
+func NewFixture(t *testing.T, controllers ...func(manager.Manager) error) *Fixture {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatalf("can't create Client: %v", err)
+	}
+
+	namespace := fmt.Sprintf("test-%d", atomic.AddInt32(&namespaces, 1))
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	if err := c.Create(ctx, ns); err != nil {
+		t.Fatalf("can't create Namespace: %v", err)
+	}
+
+	mgr, err := manager.New(config, manager.Options{
+		Scheme:             scheme,
+		Namespace:          namespace,
+		MetricsBindAddress: "0",
+	})
+	if err != nil {
+		t.Fatalf("can't create manager: %v", err)
+	}
+	for _, add := range controllers {
+		if err := add(mgr); err != nil {
+			t.Fatalf("can't add controller: %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("manager failed: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		// There's no namespace controller to finish the deletion, but
+		// marking it as terminating keeps it from being used again.
+		c.Delete(context.Background(), ns)
+	})
+
+	return &Fixture{
+		T:         t,
+		ctx:       ctx,
+		client:    c,
+		namespace: namespace,
+	}
+}
+
+// Context returns the Context for the running test.
+func (f *Fixture) Context() context.Context {
+	return f.ctx
+}
+
+// Client returns a Kubernetes client that reads from the kube-apiserver
+// directly, rather than from a cache.
+func (f *Fixture) Client() client.Client {
+	return f.client
+}
+
+// Namespace returns the namespace of the test.
+func (f *Fixture) Namespace() string {
+	return f.namespace
+}
+
+// Create creates obj in the namespace of the test.
+func (f *Fixture) Create(obj client.Object) {
+	obj.SetNamespace(f.namespace)
+	if err := f.client.Create(f.ctx, obj); err != nil {
+		f.Fatalf("can't create %T %v: %v", obj, obj.GetName(), err)
+	}
+}
+
+// Get reads the named object in the namespace of the test into obj.
+func (f *Fixture) Get(name string, obj client.Object) error {
+	return f.client.Get(f.ctx, client.ObjectKey{Namespace: f.namespace, Name: name}, obj)
+}
+
+// WaitFor polls the check function until it returns nil, with a default
+// interval and timeout. If the timeout expires first, the test is aborted.
+//
+// The check function should return nil if it is satisfied, or a non-nil error
+// indicating why it's not satisfied.
+func (f *Fixture) WaitFor(condition string, check func() error) {
+	f.Helper()
+	f.Logf("Waiting for %v...", condition)
+	start := time.Now()
+	for {
+		err := check()
+		if err == nil {
+			f.Logf("Done waiting for %v.", condition)
+			return
+		}
+		if time.Since(start) > defaultWaitTimeout {
+			f.Fatalf("Timed out waiting for %v: %v", condition, err)
+		}
+		time.Sleep(defaultWaitInterval)
+	}
+}
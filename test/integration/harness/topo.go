@@ -0,0 +1,163 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harness
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// TopoImplementation is the name under which in-memory topology servers are
+// registered with Vitess.
+const TopoImplementation = "memorytopo"
+
+// topoFactories maps the address of each in-memory topology server to its
+// factory.
+var topoFactories = struct {
+	sync.Mutex
+	m map[string]*memorytopo.Factory
+}{m: make(map[string]*memorytopo.Factory)}
+
+func init() {
+	topo.RegisterFactory(TopoImplementation, factoryByAddress{})
+}
+
+// factoryByAddress lets the operator open an in-memory topology server by
+// address, like any other, even though each one has a factory of its own.
+type factoryByAddress struct{}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (factoryByAddress) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (factoryByAddress) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	topoFactories.Lock()
+	factory := topoFactories.m[serverAddr]
+	topoFactories.Unlock()
+
+	if factory == nil {
+		return nil, topo.NewError(topo.NoNode, serverAddr)
+	}
+	return factory.Create(cell, serverAddr, root)
+}
+
+// Topo is an in-memory Vitess topology server.
+type Topo struct {
+	*topo.Server
+	// Factory can simulate failures of the topology server, with SetError,
+	// or an unresponsive one, with Lock.
+	Factory *memorytopo.Factory
+
+	f       *Fixture
+	address string
+}
+
+// NewTopo starts an in-memory topology server with the given cells, which
+// is shut down when the test finishes.
+func (f *Fixture) NewTopo(cells ...string) *Topo {
+	ts, factory := memorytopo.NewServerAndFactory(cells...)
+	address := fmt.Sprintf("%v-%d", f.namespace, atomic.AddInt32(&f.topos, 1))
+
+	// Cell connections are opened by the address in the cell info.
+	for _, cell := range cells {
+		err := ts.UpdateCellInfoFields(f.ctx, cell, func(ci *topodatapb.CellInfo) error {
+			ci.ServerAddress = address
+			return nil
+		})
+		if err != nil {
+			f.Fatalf("can't update cell info: %v", err)
+		}
+	}
+
+	topoFactories.Lock()
+	topoFactories.m[address] = factory
+	topoFactories.Unlock()
+	f.Cleanup(func() {
+		topoFactories.Lock()
+		delete(topoFactories.m, address)
+		topoFactories.Unlock()
+		ts.Close()
+	})
+
+	return &Topo{
+		Server:  ts,
+		Factory: factory,
+		f:       f,
+		address: address,
+	}
+}
+
+// Lockserver returns the parameters for the operator to connect to t, like
+// the GlobalLockserver of a VitessShard.
+func (t *Topo) Lockserver() planetscalev2.VitessLockserverParams {
+	return planetscalev2.VitessLockserverParams{
+		Implementation: TopoImplementation,
+		Address:        t.address,
+		RootPath:       "/",
+	}
+}
+
+// AddTablet adds a tablet record of the given type to a shard, creating the
+// keyspace and shard records if needed. A primary tablet also becomes the
+// primary of the shard.
+func (t *Topo) AddTablet(keyspace, shard, alias string, tabletType topodatapb.TabletType) *topodatapb.Tablet {
+	ctx := t.f.ctx
+
+	tabletAlias, err := topoproto.ParseTabletAlias(alias)
+	if err != nil {
+		t.f.Fatalf("invalid tablet alias: %v", err)
+	}
+	if err := t.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}); err != nil && !topo.IsErrType(err, topo.NodeExists) {
+		t.f.Fatalf("can't create keyspace: %v", err)
+	}
+	if err := t.CreateShard(ctx, keyspace, shard); err != nil && !topo.IsErrType(err, topo.NodeExists) {
+		t.f.Fatalf("can't create shard: %v", err)
+	}
+
+	tablet := &topodatapb.Tablet{
+		Alias:    tabletAlias,
+		Hostname: alias,
+		Keyspace: keyspace,
+		Shard:    shard,
+		Type:     tabletType,
+	}
+	if err := t.CreateTablet(ctx, tablet); err != nil {
+		t.f.Fatalf("can't create tablet: %v", err)
+	}
+
+	if tabletType == topodatapb.TabletType_PRIMARY {
+		_, err := t.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+			si.PrimaryAlias = tabletAlias
+			si.IsPrimaryServing = true
+			return nil
+		})
+		if err != nil {
+			t.f.Fatalf("can't set shard primary: %v", err)
+		}
+	}
+	return tablet
+}
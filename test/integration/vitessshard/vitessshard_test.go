@@ -0,0 +1,94 @@
+/*
+Copyright 2020 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/controller/vitessshard"
+	"planetscale.dev/vitess-operator/test/integration/harness"
+)
+
+func TestMain(m *testing.M) {
+	harness.TestMain(m)
+}
+
+func TestTopology(t *testing.T) {
+	f := harness.NewFixture(t, vitessshard.Add)
+	ts := f.NewTopo("zone1", "zone2")
+
+	// The primary is in a cell that isn't part of the cluster, so it's
+	// managed by someone else and must be left alone.
+	ts.AddTablet("commerce", "-", "zone2-0000000101", topodatapb.TabletType_PRIMARY)
+	// This replica is in a cell of the cluster, but the shard doesn't want
+	// any tablets, so it must be removed from topology.
+	ts.AddTablet("commerce", "-", "zone1-0000000102", topodatapb.TabletType_REPLICA)
+
+	f.Create(&planetscalev2.VitessShard{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "example-commerce-x-x",
+			Labels: map[string]string{
+				planetscalev2.ClusterLabel:  "example",
+				planetscalev2.KeyspaceLabel: "commerce",
+			},
+		},
+		Spec: planetscalev2.VitessShardSpec{
+			Name:             "-",
+			ZoneMap:          map[string]string{"zone1": ""},
+			GlobalLockserver: ts.Lockserver(),
+		},
+	})
+
+	f.WaitFor("shard status to report the primary", func() error {
+		vts := &planetscalev2.VitessShard{}
+		if err := f.Get("example-commerce-x-x", vts); err != nil {
+			return err
+		}
+		if vts.Status.HasMaster != corev1.ConditionTrue {
+			return fmt.Errorf("status.hasMaster = %v; want %v", vts.Status.HasMaster, corev1.ConditionTrue)
+		}
+		if got, want := vts.Status.MasterAlias, "zone2-0000000101"; got != want {
+			return fmt.Errorf("status.masterAlias = %q; want %q", got, want)
+		}
+		return nil
+	})
+
+	f.WaitFor("unwanted tablet to be removed from topology", func() error {
+		tablets, err := ts.GetTabletAliasesByCell(f.Context(), "zone1")
+		if err != nil {
+			return err
+		}
+		if len(tablets) != 0 {
+			return fmt.Errorf("found tablets %v in zone1; want none", tablets)
+		}
+		return nil
+	})
+
+	if _, err := ts.GetTablet(f.Context(), &topodatapb.TabletAlias{Cell: "zone2", Uid: 101}); err != nil {
+		t.Errorf("tablet in a cell outside the cluster was removed: %v", err)
+	}
+	if _, err := ts.GetShard(f.Context(), "commerce", "-"); topo.IsErrType(err, topo.NoNode) {
+		t.Errorf("shard record was removed")
+	}
+}